@@ -0,0 +1,29 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// v1beta1 is the storage version of the types served in several versions, and
+// the hub the other versions convert through.
+
+// Hub marks NcxInfraCluster as a conversion hub.
+func (*NcxInfraCluster) Hub() {}
+
+// Hub marks NcxInfraMachine as a conversion hub.
+func (*NcxInfraMachine) Hub() {}
+
+// Hub marks NcxInfraMachineTemplate as a conversion hub.
+func (*NcxInfraMachineTemplate) Hub() {}
@@ -17,7 +17,10 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	capierrors "sigs.k8s.io/cluster-api/errors" //nolint:staticcheck // required for CAPI contract FailureReason types
@@ -42,26 +45,139 @@ type NcxInfraClusterSpec struct {
 
 	// Subnets for control-plane and worker nodes
 	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
 	// +required
 	Subnets []SubnetSpec `json:"subnets"`
 
 	// VPCPrefixes for physical interface allocations (alternative to Subnets for FNN VPCs)
 	// +optional
+	// +listType=map
+	// +listMapKey=name
 	VPCPrefixes []VPCPrefixSpec `json:"vpcPrefixes,omitempty"`
 
 	// VPCPeerings configures VPC peering connections to other VPCs
 	// +optional
 	VPCPeerings []VPCPeeringSpec `json:"vpcPeerings,omitempty"`
 
+	// Network configures the DNS and NTP servers of the machines of the cluster,
+	// for sites that do not provide them through DHCP. The DHCP options of a
+	// subnet and the network of a NcxInfraMachine take precedence.
+	// +optional
+	Network *NetworkServices `json:"network,omitempty"`
+
+	// InstanceLabels are default labels applied to the NVIDIA Carbide instances
+	// of the cluster. The labels of a NcxInfraMachine take precedence.
+	// +optional
+	InstanceLabels map[string]string `json:"instanceLabels,omitempty"`
+
+	// WarmPool keeps the instances of the deleted machines of the cluster, and
+	// hands them to new machines of the same instance type instead of creating
+	// instances. It saves the allocation and the discovery of a bare-metal
+	// machine on scale-down and scale-up cycles.
+	// +optional
+	WarmPool *WarmPoolSpec `json:"warmPool,omitempty"`
+
+	// Provisioning paces the provisioning of the instances of the cluster, so
+	// that a rolling update of a large MachineDeployment does not provision or
+	// reimage all its bare-metal machines at once.
+	// +optional
+	Provisioning *ProvisioningSpec `json:"provisioning,omitempty"`
+
+	// Proxy configures the egress proxy of the machines of the cluster, for
+	// sites whose egress goes through a proxy. It is injected into the
+	// cloud-config bootstrap data of the machines, and takes precedence over
+	// authentication.propagateProxy.
+	// +optional
+	Proxy *ProxySpec `json:"proxy,omitempty"`
+
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane
 	// +optional
 	ControlPlaneEndpoint *clusterv1.APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
 
-	// Authentication contains credentials for accessing the NVIDIA Carbide API
+	// ControlPlaneEndpointManagement selects who sets the control plane endpoint.
+	// With Auto, the default, the address of the first ready control plane
+	// machine is reported in status.controlPlaneEndpoint and set in the spec
+	// when its host is empty, as Cluster API reads the endpoint from the spec.
+	// With Reported, the spec is left to the user, so that it does not drift
+	// from a spec applied by GitOps: the cluster only comes up once the user
+	// copies the reported endpoint to the spec. With External, the endpoint is
+	// set by the user, for instance to an external load balancer, and nothing is
+	// reported.
+	// +kubebuilder:default=Auto
+	// +optional
+	ControlPlaneEndpointManagement ControlPlaneEndpointManagement `json:"controlPlaneEndpointManagement,omitempty"`
+
+	// Organization is the NVIDIA Carbide organization of the cluster resources.
+	// It takes precedence over the orgName of the credentials secret, so that
+	// the credentials of a service account of a parent organization manage
+	// the clusters of its child organizations. Immutable.
+	// +optional
+	Organization string `json:"organization,omitempty"`
+
+	// Authentication contains credentials for accessing the NVIDIA Carbide API.
+	// Defaults to the credentials of the namespace or controller, see secretRef.
+	// +optional
+	Authentication AuthenticationSpec `json:"authentication,omitzero"`
+}
+
+// WarmPoolSpec configures the warm pool of the instances of a cluster
+type WarmPoolSpec struct {
+	// MaxSize is the number of instances the pool holds at most. The instances
+	// of the machines deleted while the pool is full are deleted, and the
+	// instances exceeding a reduced size are deleted from the pool.
+	// +kubebuilder:validation:Minimum=0
 	// +required
-	Authentication AuthenticationSpec `json:"authentication"`
+	MaxSize int32 `json:"maxSize"`
+}
+
+// ProvisioningSpec paces the provisioning of the instances of a cluster
+type ProvisioningSpec struct {
+	// MaxConcurrent is the number of instances of the cluster provisioned or
+	// reimaged at once at most, across its MachineDeployments, whose rollouts
+	// are already limited by their maxUnavailable. The other machines wait for
+	// one of them to be ready before their instance is created or reimaged.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	MaxConcurrent int32 `json:"maxConcurrent"`
+}
+
+// ProxySpec defines the proxy settings of the machines of a cluster
+type ProxySpec struct {
+	// HTTPProxy is the URL of the proxy of the HTTP requests
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy of the HTTPS requests
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy are the hosts, domains and CIDRs reached without the proxy, such
+	// as the control plane endpoint, the subnets of the cluster and the
+	// service and pod networks
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
 }
 
+// ControlPlaneEndpointManagement defines who manages the control plane endpoint.
+// +kubebuilder:validation:Enum=Reported;Auto;External
+type ControlPlaneEndpointManagement string
+
+const (
+	// ControlPlaneEndpointReported reports the address of the first ready
+	// control plane machine in the status without changing the spec, which the
+	// user sets.
+	ControlPlaneEndpointReported ControlPlaneEndpointManagement = "Reported"
+
+	// ControlPlaneEndpointAuto also sets the endpoint of the spec to the
+	// address of the first ready control plane machine when it is not set. It
+	// is the default.
+	ControlPlaneEndpointAuto ControlPlaneEndpointManagement = "Auto"
+
+	// ControlPlaneEndpointExternal leaves the endpoint to the user.
+	ControlPlaneEndpointExternal ControlPlaneEndpointManagement = "External"
+)
+
 // SiteReference references an NVIDIA Carbide Site
 type SiteReference struct {
 	// Name references a Site CRD in the same namespace
@@ -80,6 +196,13 @@ type VPCSpec struct {
 	// +required
 	Name string `json:"name"`
 
+	// ID of an existing VPC of the site to use instead of creating one, to
+	// share the VPC of another cluster. The subnets of the cluster are created
+	// in it, and it is not deleted with the cluster. The VPC settings below
+	// only apply to a VPC created by the cluster.
+	// +optional
+	ID string `json:"id,omitempty"`
+
 	// NetworkVirtualizationType specifies the network virtualization type
 	// Valid values: ETHERNET_VIRTUALIZER, FNN
 	// +kubebuilder:validation:Enum=ETHERNET_VIRTUALIZER;FNN
@@ -94,6 +217,12 @@ type VPCSpec struct {
 	// +optional
 	NetworkSecurityGroup *NSGSpec `json:"networkSecurityGroup,omitempty"`
 
+	// NetworkSecurityGroupRef references a NcxInfraNetworkSecurityGroup in the
+	// same namespace, which can be shared with other clusters. The referenced
+	// NSG is not deleted with the cluster. Mutually exclusive with NetworkSecurityGroup.
+	// +optional
+	NetworkSecurityGroupRef *corev1.LocalObjectReference `json:"networkSecurityGroupRef,omitempty"`
+
 	// NVLinkLogicalPartitionID attaches an NVLink logical partition at the VPC level
 	// +optional
 	NVLinkLogicalPartitionID string `json:"nvLinkLogicalPartitionId,omitempty"`
@@ -137,15 +266,30 @@ type NSGRule struct {
 	// +required
 	Protocol string `json:"protocol"`
 
-	// PortRange specifies the port range (e.g., "80", "1000-2000")
+	// PortRange specifies the destination port range (e.g., "80", "1000-2000")
 	// +kubebuilder:validation:Pattern=`^[0-9]{1,5}(-[0-9]{1,5})?$`
 	// +optional
 	PortRange string `json:"portRange,omitempty"`
 
+	// SourcePortRange specifies the source port range (e.g., "1024-65535")
+	// +kubebuilder:validation:Pattern=`^[0-9]{1,5}(-[0-9]{1,5})?$`
+	// +optional
+	SourcePortRange string `json:"sourcePortRange,omitempty"`
+
 	// SourceCIDR specifies the source IP range
 	// +optional
 	SourceCIDR string `json:"sourceCIDR,omitempty"`
 
+	// DestinationCIDR specifies the destination IP range, used to filter egress traffic
+	// +optional
+	DestinationCIDR string `json:"destinationCIDR,omitempty"`
+
+	// Priority orders the evaluation of the rules
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=60000
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+
 	// Action to take (allow or deny)
 	// +kubebuilder:validation:Enum=allow;deny
 	// +required
@@ -171,6 +315,79 @@ type SubnetSpec struct {
 	// Labels to apply to the subnet
 	// +optional
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// DHCPOptions configures name resolution and time synchronization on the
+	// machines attached to the subnet
+	// +optional
+	DHCPOptions *DHCPOptions `json:"dhcpOptions,omitempty"`
+
+	// Egress selects how the machines of the subnet reach networks outside the
+	// datacenter. Public allocates the subnet from a publicly routable IP block
+	// built from its CIDR, which must then be routable at the site, for ingress
+	// or other machines exposed to users. Their addresses on the subnet are
+	// reported as ExternalIP. Applies when the subnet is created.
+	// +kubebuilder:default=None
+	// +optional
+	Egress SubnetEgress `json:"egress,omitempty"`
+
+	// SiteRef places the subnet, and the machines attached to it, in another
+	// site than the one of the cluster. The subnet is created in a VPC of the
+	// cluster in that site, and the site is published as a failure domain.
+	// +optional
+	SiteRef *SiteReference `json:"siteRef,omitempty"`
+}
+
+// SubnetEgress defines how the machines of a subnet reach networks outside the datacenter.
+// NVIDIA Carbide routes IP blocks either within the datacenter only or publicly,
+// and has no NAT routing type.
+// +kubebuilder:validation:Enum=None;Public
+type SubnetEgress string
+
+const (
+	// SubnetEgressNone allocates the subnet from the DatacenterOnly IP block of the cluster.
+	SubnetEgressNone SubnetEgress = "None"
+
+	// SubnetEgressPublic allocates the subnet from a Public IP block of its own.
+	SubnetEgressPublic SubnetEgress = "Public"
+)
+
+// DHCPOptions defines the DNS and NTP configuration of the machines of a subnet.
+// NVIDIA Carbide does not serve custom DHCP options on tenant subnets, so they
+// are applied through the bootstrap data of the machines attached to the
+// subnet, which must be a cloud-config document.
+type DHCPOptions struct {
+	// DNSServers are the IP addresses of the DNS servers
+	// +kubebuilder:validation:MaxItems=3
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
+
+	// SearchDomains are the DNS search domains
+	// +kubebuilder:validation:MaxItems=6
+	// +optional
+	SearchDomains []string `json:"searchDomains,omitempty"`
+
+	// NTPServers are the hostnames or IP addresses of the NTP servers
+	// +optional
+	NTPServers []string `json:"ntpServers,omitempty"`
+}
+
+// NetworkServices defines the DNS and NTP servers of the machines. They are
+// applied through the bootstrap data of the machines, which must be a
+// cloud-config document. Each list replaces the one of lower precedence.
+type NetworkServices struct {
+	// DNSServers are the IP addresses of the DNS servers
+	// +kubebuilder:validation:MaxItems=3
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
+
+	// SearchDomains are the DNS search domains
+	// +kubebuilder:validation:MaxItems=6
+	// +optional
+	SearchDomains []string `json:"searchDomains,omitempty"`
+
+	// NTPServers are the hostnames or IP addresses of the NTP servers
+	// +optional
+	NTPServers []string `json:"ntpServers,omitempty"`
 }
 
 // VPCPrefixSpec defines a VPC Prefix configuration (physical interface alternative to subnets)
@@ -200,18 +417,105 @@ type VPCPeeringSpec struct {
 // AuthenticationSpec contains credentials for NVIDIA Carbide API
 type AuthenticationSpec struct {
 	// SecretRef references a Secret containing NVIDIA Carbide credentials
-	// The secret must contain: endpoint, orgName, token
-	// +required
-	SecretRef corev1.SecretReference `json:"secretRef"`
+	// The secret must contain: endpoint, orgName, and token or clientID, clientSecret and tokenURL
+	// It may contain httpProxy, httpsProxy and noProxy to reach the API through a proxy
+	// and caBundle, tls.crt, tls.key and insecureSkipVerify to configure TLS
+	// When omitted, the secret of the "default" NcxInfraIdentity of the namespace
+	// is used, and then the default credentials secret of the controller.
+	// A secret of another namespace must list the namespace in its
+	// ncx-infra.io/allowed-namespaces annotation.
+	// +optional
+	SecretRef corev1.SecretReference `json:"secretRef,omitzero"`
+
+	// IdentityRef references a NcxInfraClusterIdentity allowing the namespace,
+	// instead of a secret. Mutually exclusive with SecretRef.
+	// +optional
+	IdentityRef *corev1.LocalObjectReference `json:"identityRef,omitempty"`
+
+	// PropagateProxy injects the proxy settings of the credentials secret into
+	// the bootstrap data of the machines, for sites whose egress goes through
+	// the same proxy. Requires cloud-config bootstrap data.
+	// +optional
+	PropagateProxy bool `json:"propagateProxy,omitempty"`
+}
+
+// AllowedNamespacesAnnotation opts a credentials secret in to the use from
+// other namespaces. It lists the namespaces, comma-separated, whose objects
+// may reference the secret, or "*" for all of them. A secret without it can
+// only be used from its own namespace.
+const AllowedNamespacesAnnotation = "ncx-infra.io/allowed-namespaces"
+
+// SecretAllowsNamespace returns whether the objects of a namespace may use a
+// credentials secret.
+func SecretAllowsNamespace(secret metav1.Object, namespace string) bool {
+	if secret.GetNamespace() == namespace {
+		return true
+	}
+	for _, allowed := range strings.Split(secret.GetAnnotations()[AllowedNamespacesAnnotation], ",") {
+		if allowed = strings.TrimSpace(allowed); allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+	return false
 }
 
+// TeardownReportAnnotation requests a report of what deleting the cluster would
+// do to its NVIDIA Carbide resources. The controller writes the report to the
+// <name>-teardown-report ConfigMap and removes the annotation.
+const TeardownReportAnnotation = "ncx-infra.io/teardown-report"
+
+// PreflightAnnotation requests the validation of the credentials, the site, the
+// instance types, the SSH key groups and the IP space of the cluster before its
+// NVIDIA Carbide resources are reconciled. The result is reported in the
+// PreflightSucceeded condition. The controller removes the annotation once the
+// checks pass, and reconciles nothing else while they fail.
+const PreflightAnnotation = "ncx-infra.io/preflight"
+
+// DryRunAnnotation switches a NcxInfraCluster, or a single NcxInfraMachine,
+// to dry-run mode. The controllers then report the NVIDIA Carbide resources
+// they would create or delete in the DryRun condition and in events, without
+// calling the mutating NVIDIA Carbide APIs. On a NcxInfraCluster, it applies
+// to its NcxInfraMachines too. Removing the annotation applies the changes.
+const DryRunAnnotation = "ncx-infra.io/dry-run"
+
+// PreventDeletionAnnotation protects a NcxInfraCluster, or a single
+// NcxInfraMachine, from deletion. The controllers hold the deletion of the
+// NVIDIA Carbide resources, and report it in the DeletionBlocked condition,
+// until the annotation is removed. On a NcxInfraCluster, it protects its
+// NcxInfraMachines too.
+const PreventDeletionAnnotation = "ncx-infra.io/prevent-deletion"
+
+// Cluster phases
+const (
+	// ClusterPhaseProvisioning means the network resources of the cluster are
+	// being created.
+	ClusterPhaseProvisioning = "Provisioning"
+
+	// ClusterPhaseProvisioned means the cluster infrastructure is ready.
+	ClusterPhaseProvisioned = "Provisioned"
+
+	// ClusterPhaseFailed means reconciling the cluster hit a terminal problem.
+	ClusterPhaseFailed = "Failed"
+
+	// ClusterPhaseDeleting means the network resources of the cluster are
+	// being deleted.
+	ClusterPhaseDeleting = "Deleting"
+)
+
 // NcxInfraClusterStatus defines the observed state of NcxInfraCluster.
 type NcxInfraClusterStatus struct {
 	// Ready indicates if the cluster infrastructure is ready
 	// +optional
 	Ready bool `json:"ready"`
 
-	// VPCID is the NVIDIA Carbide VPC ID
+	// Phase summarizes the state of the cluster infrastructure
+	// Possible values: Provisioning, Provisioned, Failed, Deleting
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// VPCID is the NVIDIA Carbide VPC ID.
+	// Deprecated: use networkStatus.vpc.id, this field is only read to migrate
+	// the status of existing clusters.
 	// +optional
 	VPCID string `json:"vpcID,omitempty"`
 
@@ -219,6 +523,47 @@ type NcxInfraClusterStatus struct {
 	// +optional
 	NetworkStatus NetworkStatus `json:"networkStatus,omitempty"`
 
+	// OrgName is the NVIDIA Carbide org the resources of the cluster live in.
+	// The cluster is no longer reconciled if the credentials point to another org.
+	// +optional
+	OrgName string `json:"orgName,omitempty"`
+
+	// Endpoint is the NVIDIA Carbide API endpoint the resources of the cluster
+	// live behind. The cluster is no longer reconciled if the credentials point
+	// to another endpoint.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// CredentialsSecretRef is the credentials secret the cluster uses, resolved
+	// from spec.authentication, the NcxInfraIdentity of its namespace or the
+	// default of the controller. The secret carries a finalizer until no
+	// cluster uses it anymore.
+	// +optional
+	CredentialsSecretRef *corev1.SecretReference `json:"credentialsSecretRef,omitempty"`
+
+	// ControlPlaneEndpoint is the endpoint discovered from the address of the
+	// first ready control plane machine, unless controlPlaneEndpointManagement
+	// is External or the endpoint of the spec was set by the user.
+	// +optional
+	ControlPlaneEndpoint *clusterv1.APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
+
+	// BreakGlassSSH describes the SSH key giving emergency access to the
+	// instances of the cluster, when the BreakGlassSSH feature gate is enabled
+	// +optional
+	BreakGlassSSH *BreakGlassSSHStatus `json:"breakGlassSSH,omitempty"`
+
+	// WarmPoolInstances is the number of instances held in the warm pool
+	// +optional
+	WarmPoolInstances int32 `json:"warmPoolInstances,omitempty"`
+
+	// Quotas are the quota limits of the NVIDIA Carbide tenant and their
+	// current consumption, refreshed on each reconciliation. Empty when the
+	// deployment does not report quotas.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Quotas []QuotaStatus `json:"quotas,omitempty"`
+
 	// FailureReason will be set in the event that there is a terminal problem
 	// reconciling the cluster and will contain a succinct value suitable for
 	// machine interpretation.
@@ -231,30 +576,281 @@ type NcxInfraClusterStatus struct {
 	// +optional
 	FailureMessage *string `json:"failureMessage,omitempty"`
 
+	// FailureDomains are the sites of a cluster whose subnets span several
+	// sites, named after the site ID
+	// +optional
+	FailureDomains []clusterv1.FailureDomain `json:"failureDomains,omitempty"`
+
 	// Conditions represent the current state of the NcxInfraCluster
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
-// NetworkStatus contains network infrastructure status
+// QuotaStatus is the consumption of a quota of the NVIDIA Carbide tenant
+type QuotaStatus struct {
+	// Name of the quota, for instance instances or gpu-hours
+	// +required
+	Name string `json:"name"`
+
+	// Limit is the quota limit
+	// +optional
+	Limit resource.Quantity `json:"limit,omitempty"`
+
+	// Current is the consumption of the quota
+	// +optional
+	Current resource.Quantity `json:"current,omitempty"`
+
+	// Unit of the limit and consumption, when the quota reports one
+	// +optional
+	Unit string `json:"unit,omitempty"`
+}
+
+// BreakGlassSSHStatus describes the break-glass SSH key of a cluster and the
+// NVIDIA Carbide SSH key group attaching it to the instances
+type BreakGlassSSHStatus struct {
+	// SecretName is the Secret holding the private and public keys, in the
+	// namespace of the cluster. Deleting it rotates the key.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Fingerprint is the SHA256 fingerprint of the public key
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// SSHKeyID is the NVIDIA Carbide SSH key of the public key
+	// +optional
+	SSHKeyID string `json:"sshKeyID,omitempty"`
+
+	// SSHKeyGroupID is the NVIDIA Carbide SSH key group attached to the instances
+	// +optional
+	SSHKeyGroupID string `json:"sshKeyGroupID,omitempty"`
+
+	// SiteIDs are the sites the SSH key group is synced to
+	// +optional
+	SiteIDs []string `json:"siteIDs,omitempty"`
+
+	// RetiredSSHKeyID is the SSH key replaced by a rotation, deleted once the
+	// SSH key group holds the new key
+	// +optional
+	RetiredSSHKeyID string `json:"retiredSSHKeyID,omitempty"`
+
+	// LastRotationTime is when the current key was registered
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+}
+
+// NetworkStatus contains network infrastructure status. Each NVIDIA Carbide
+// resource of the cluster reports its ID, its state and the last error
+// reconciling it.
 type NetworkStatus struct {
-	// SubnetIDs maps subnet names to their IDs
+	// VPC is the status of the VPC of the cluster
+	// +optional
+	VPC *NetworkResourceStatus `json:"vpc,omitempty"`
+
+	// SiteVPCs are the VPCs of the cluster in the sites of its subnets placed
+	// in another site, named after the site ID
+	// +optional
+	SiteVPCs []NetworkResourceStatus `json:"siteVPCs,omitempty"`
+
+	// IPBlocks are the IP blocks of the cluster: a DatacenterOnly block per
+	// supernet of the CIDRs of the subnets, a Public block per subnet with
+	// Public egress, and a block per subnet placed in another site
+	// +optional
+	IPBlocks []IPBlockStatus `json:"ipBlocks,omitempty"`
+
+	// Subnets are the subnets of the cluster, by name
+	// +optional
+	Subnets []NetworkResourceStatus `json:"subnets,omitempty"`
+
+	// VPCPrefixes are the VPC prefixes of the cluster, by name
+	// +optional
+	VPCPrefixes []NetworkResourceStatus `json:"vpcPrefixes,omitempty"`
+
+	// VPCPeerings are the VPC peerings of the cluster, named after the peer VPC ID
+	// +optional
+	VPCPeerings []NetworkResourceStatus `json:"vpcPeerings,omitempty"`
+
+	// NSG is the status of the Network Security Group of the cluster
+	// +optional
+	NSG *NSGStatus `json:"nsg,omitempty"`
+
+	// Origins maps the IDs of the VPC, subnets, VPC prefixes, VPC peerings and
+	// NSG created for the cluster to their creation record
+	// +optional
+	Origins map[string]ResourceOrigin `json:"origins,omitempty"`
+
+	// SubnetIDs maps subnet names to their IDs.
+	// Deprecated: use subnets.
 	// +optional
 	SubnetIDs map[string]string `json:"subnetIDs,omitempty"`
 
-	// VPCPrefixIDs maps VPC Prefix names to their IDs
+	// VPCPrefixIDs maps VPC Prefix names to their IDs.
+	// Deprecated: use vpcPrefixes.
 	// +optional
 	VPCPrefixIDs map[string]string `json:"vpcPrefixIDs,omitempty"`
 
-	// VPCPeeringIDs maps peer VPC IDs to their peering resource IDs
+	// VPCPeeringIDs maps peer VPC IDs to their peering resource IDs.
+	// Deprecated: use vpcPeerings.
 	// +optional
 	VPCPeeringIDs map[string]string `json:"vpcPeeringIDs,omitempty"`
 
-	// NSGID is the Network Security Group ID
+	// NSGID is the Network Security Group ID.
+	// Deprecated: use nsg.id.
 	// +optional
 	NSGID string `json:"nsgID,omitempty"`
 
-	// IPBlockID is the NVIDIA Carbide IP Block ID used for subnet allocation
+	// IPBlockID is the NVIDIA Carbide IP Block ID used for subnet allocation.
+	// Deprecated: use ipBlocks.
+	// +optional
+	IPBlockID string `json:"ipBlockID,omitempty"`
+
+	// AllocationID is the NVIDIA Carbide Allocation ID.
+	// Deprecated: use ipBlocks.
+	// +optional
+	AllocationID string `json:"allocationID,omitempty"`
+
+	// ChildIPBlockID is the tenant-owned child IP block derived from the allocation.
+	// Deprecated: use ipBlocks.
+	// +optional
+	ChildIPBlockID string `json:"childIPBlockID,omitempty"`
+
+	// PublicIPBlocks maps the names of the subnets with Public egress to their IP blocks.
+	// Deprecated: use ipBlocks.
+	// +optional
+	PublicIPBlocks map[string]IPBlockStatus `json:"publicIPBlocks,omitempty"`
+}
+
+// VPCID returns the ID of the VPC, empty until it is created
+func (n *NetworkStatus) VPCID() string {
+	if n.VPC == nil {
+		return ""
+	}
+	return n.VPC.ID
+}
+
+// SiteVPCID returns the ID of the VPC of the cluster in a site other than its
+// own, empty until it is created
+func (n *NetworkStatus) SiteVPCID(siteID string) string {
+	return findNetworkResource(n.SiteVPCs, siteID).ID
+}
+
+// SubnetID returns the ID of a subnet, empty until it is created
+func (n *NetworkStatus) SubnetID(name string) string {
+	return findNetworkResource(n.Subnets, name).ID
+}
+
+// SubnetCIDR returns the prefix allocated to a subnet, empty until it is
+// created
+func (n *NetworkStatus) SubnetCIDR(name string) string {
+	return findNetworkResource(n.Subnets, name).CIDR
+}
+
+// VPCPrefixID returns the ID of a VPC prefix, empty until it is created
+func (n *NetworkStatus) VPCPrefixID(name string) string {
+	return findNetworkResource(n.VPCPrefixes, name).ID
+}
+
+func findNetworkResource(resources []NetworkResourceStatus, name string) NetworkResourceStatus {
+	for _, resource := range resources {
+		if resource.Name == name {
+			return resource
+		}
+	}
+	return NetworkResourceStatus{}
+}
+
+// NetworkResourceState is the state of a NVIDIA Carbide resource of the cluster.
+// +kubebuilder:validation:Enum=Ready;Failed;Deleting
+type NetworkResourceState string
+
+const (
+	// NetworkResourceReady means the resource exists in NVIDIA Carbide.
+	NetworkResourceReady NetworkResourceState = "Ready"
+
+	// NetworkResourceFailed means the resource could not be created or
+	// verified, lastError tells why.
+	NetworkResourceFailed NetworkResourceState = "Failed"
+
+	// NetworkResourceDeleting means the cluster is being deleted and the
+	// resource is not deleted yet, lastError tells why.
+	NetworkResourceDeleting NetworkResourceState = "Deleting"
+)
+
+// NetworkResourceStatus is the observed state of a NVIDIA Carbide resource of the cluster
+type NetworkResourceStatus struct {
+	// Name of the resource in the spec of the cluster
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ID of the resource in NVIDIA Carbide, empty until it is created
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// CIDR is the prefix NVIDIA Carbide allocated to a subnet, which can differ
+	// from the CIDR of its spec as only its prefix length is requested
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+
+	// State of the resource
+	// +optional
+	State NetworkResourceState `json:"state,omitempty"`
+
+	// LastError is the last error reconciling the resource, cleared once it succeeds
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// NSGStatus is the observed state of the Network Security Group of the cluster
+type NSGStatus struct {
+	NetworkResourceStatus `json:",inline"`
+
+	// AppliedRuleHash is the hash of the rules the Network Security Group was
+	// created with, or of the rules of the referenced NcxInfraNetworkSecurityGroup
+	// +optional
+	AppliedRuleHash string `json:"appliedRuleHash,omitempty"`
+}
+
+// ResourceCreator identifies the provider as the creator of NVIDIA Carbide resources.
+const ResourceCreator = "cluster-api-provider-nvidia-ncx-infra-controller"
+
+// ResourceOrigin records the creation of a NVIDIA Carbide resource, to tell the
+// resources created by the provider apart from the ones created by hand
+type ResourceOrigin struct {
+	// Kind of the resource (VPC, Subnet, VPCPrefix, VPCPeering, NetworkSecurityGroup or Instance)
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the resource
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Created is the creation time reported by NVIDIA Carbide
+	// +optional
+	Created *metav1.Time `json:"created,omitempty"`
+
+	// CreatedBy identifies the creator of the resource. NVIDIA Carbide does not
+	// report it, so it is set to the provider for the resources it created.
+	// +optional
+	CreatedBy string `json:"createdBy,omitempty"`
+}
+
+// IPBlockStatus records an IP block created for the cluster and its allocation to the tenant
+type IPBlockStatus struct {
+	// Subnet is the name of the subnet with Public egress, or placed in another
+	// site than the one of the cluster, the IP block is created for, empty for
+	// the IP blocks the other subnets are allocated from
+	// +optional
+	Subnet string `json:"subnet,omitempty"`
+
+	// CIDR is the supernet of the CIDRs of the subnets and VPC prefixes the IP
+	// block is created for, or the CIDR of a subnet or VPC prefix the child IP
+	// block of the allocation is allocated for from the IP block of its
+	// supernet, empty for the IP blocks of a subnet and for the single
+	// 10.0.0.0/16 IP block of the clusters created by earlier releases
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+
+	// IPBlockID is the NVIDIA Carbide IP Block ID
 	// +optional
 	IPBlockID string `json:"ipBlockID,omitempty"`
 
@@ -265,10 +861,31 @@ type NetworkStatus struct {
 	// ChildIPBlockID is the tenant-owned child IP block derived from the allocation
 	// +optional
 	ChildIPBlockID string `json:"childIPBlockID,omitempty"`
+
+	// Adopted is true when the IP block existed before the cluster, which
+	// reuses it and only deletes its allocation and child IP block on deletion
+	// +optional
+	Adopted bool `json:"adopted,omitempty"`
+
+	// State of the IP block and its allocation
+	// +optional
+	State NetworkResourceState `json:"state,omitempty"`
+
+	// LastError is the last error reconciling the IP block or its allocation,
+	// cleared once they succeed
+	// +optional
+	LastError string `json:"lastError,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this NcxInfraCluster belongs"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready",description="Cluster infrastructure is ready"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase of the cluster infrastructure"
+// +kubebuilder:printcolumn:name="VPC",type="string",JSONPath=".status.networkStatus.vpc.id",description="NVIDIA Carbide VPC ID"
+// +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".spec.controlPlaneEndpoint.host",description="Control plane endpoint",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time since creation"
+// +kubebuilder:storageversion
 
 // NcxInfraCluster is the Schema for the ncxinfraclusters API
 type NcxInfraCluster struct {
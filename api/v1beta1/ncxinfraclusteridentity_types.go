@@ -0,0 +1,107 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NcxInfraClusterIdentitySpec defines the desired state of NcxInfraClusterIdentity
+type NcxInfraClusterIdentitySpec struct {
+	// SecretRef references the Secret containing the NVIDIA Carbide org,
+	// endpoint and credentials, with the same fields as authentication.secretRef.
+	// The namespace of the secret is required. The users of the identity need
+	// no access to the secret.
+	// +required
+	SecretRef corev1.SecretReference `json:"secretRef"`
+
+	// AllowedNamespaces restricts the namespaces whose objects can use the
+	// identity. When nil, no namespace is allowed; when empty, all are.
+	// +optional
+	AllowedNamespaces *AllowedNamespaces `json:"allowedNamespaces,omitempty"`
+}
+
+// AllowedNamespaces selects namespaces by name or by label. A namespace is
+// allowed when it matches either.
+type AllowedNamespaces struct {
+	// NamesList lists the allowed namespaces
+	// +optional
+	NamesList []string `json:"list,omitempty"`
+
+	// Selector selects the allowed namespaces by label. An empty selector
+	// selects all namespaces.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// AllowsNamespace returns true when the objects of the namespace, with the
+// given labels, can use the identity.
+func (s *NcxInfraClusterIdentitySpec) AllowsNamespace(namespace string, namespaceLabels map[string]string) (bool, error) {
+	allowed := s.AllowedNamespaces
+	if allowed == nil {
+		return false, nil
+	}
+	if len(allowed.NamesList) == 0 && allowed.Selector == nil {
+		return true, nil
+	}
+	if slices.Contains(allowed.NamesList, namespace) {
+		return true, nil
+	}
+	if allowed.Selector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(allowed.Selector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(namespaceLabels)), nil
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ncxinfraclusteridentities,scope=Cluster,categories=cluster-api
+
+// NcxInfraClusterIdentity is the Schema for the ncxinfraclusteridentities API.
+// It lets the platform team share NVIDIA Carbide credentials with the
+// namespaces it allows, which reference it through authentication.identityRef.
+type NcxInfraClusterIdentity struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of NcxInfraClusterIdentity
+	// +required
+	Spec NcxInfraClusterIdentitySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// NcxInfraClusterIdentityList contains a list of NcxInfraClusterIdentity
+type NcxInfraClusterIdentityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NcxInfraClusterIdentity `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NcxInfraClusterIdentity{}, &NcxInfraClusterIdentityList{})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultIdentityName is the name of the NcxInfraIdentity providing the
+// credentials of the objects of its namespace that have no secretRef.
+const DefaultIdentityName = "default"
+
+// NcxInfraIdentitySpec defines the desired state of NcxInfraIdentity
+type NcxInfraIdentitySpec struct {
+	// SecretRef references the Secret containing the NVIDIA Carbide credentials,
+	// with the same fields as authentication.secretRef. The secret may live in
+	// another namespace, so that a platform team keeps a single copy of it,
+	// provided its ncx-infra.io/allowed-namespaces annotation lists the
+	// namespace of the identity.
+	// +required
+	SecretRef corev1.SecretReference `json:"secretRef"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ncxinfraidentities,scope=Namespaced,categories=cluster-api
+
+// NcxInfraIdentity is the Schema for the ncxinfraidentities API.
+// The NcxInfraIdentity named "default" provides the credentials of the
+// NcxInfraClusters and NcxInfraNetworkSecurityGroups of its namespace that do
+// not set authentication.secretRef.
+type NcxInfraIdentity struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of NcxInfraIdentity
+	// +required
+	Spec NcxInfraIdentitySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// NcxInfraIdentityList contains a list of NcxInfraIdentity
+type NcxInfraIdentityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NcxInfraIdentity `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NcxInfraIdentity{}, &NcxInfraIdentityList{})
+}
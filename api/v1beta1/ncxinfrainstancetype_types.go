@@ -0,0 +1,166 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// InstanceTypeSiteLabel is set on the NcxInfraInstanceTypes to the ID of
+// their site, to select the instance types of a site.
+const InstanceTypeSiteLabel = "ncx-infra.io/site-id"
+
+// NcxInfraInstanceTypeSpec identifies a discovered instance type
+type NcxInfraInstanceTypeSpec struct {
+	// SiteID is the NVIDIA Carbide Site offering the instance type
+	// +required
+	SiteID string `json:"siteID"`
+
+	// ID of the instance type, as set in spec.instanceType.id of a NcxInfraMachine
+	// +required
+	ID string `json:"id"`
+
+	// Name of the instance type in NVIDIA Carbide
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Description of the instance type
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// HardwareComponent is a component of the machines of an instance type, as
+// reported in their capabilities.
+type HardwareComponent struct {
+	// Type of the component: CPU, GPU, Memory, Storage, Network, InfiniBand or DPU
+	Type string `json:"type"`
+
+	// Name of the component, the model for processors and adapters
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Vendor of the component
+	// +optional
+	Vendor string `json:"vendor,omitempty"`
+
+	// Count of the component in a machine
+	// +optional
+	Count int32 `json:"count,omitempty"`
+
+	// Cores of a processor
+	// +optional
+	Cores int32 `json:"cores,omitempty"`
+
+	// Threads of a processor
+	// +optional
+	Threads int32 `json:"threads,omitempty"`
+
+	// Capacity of a memory module, disk or GPU
+	// +optional
+	Capacity string `json:"capacity,omitempty"`
+
+	// DeviceType of the component, when NVIDIA Carbide reports one
+	// +optional
+	DeviceType string `json:"deviceType,omitempty"`
+}
+
+// NcxInfraInstanceTypeStatus is the discovered hardware and availability of an instance type
+type NcxInfraInstanceTypeStatus struct {
+	// GPUModel is the model of the GPUs of the machines
+	// +optional
+	GPUModel string `json:"gpuModel,omitempty"`
+
+	// GPUCount is the number of GPUs of a machine
+	// +optional
+	GPUCount int32 `json:"gpuCount,omitempty"`
+
+	// Capacity is the node capacity of a machine: cpu, memory,
+	// ephemeral-storage and nvidia.com/gpu
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+
+	// Components are the hardware components of a machine, including the
+	// network, InfiniBand and DPU adapters
+	// +optional
+	Components []HardwareComponent `json:"components,omitempty"`
+
+	// AvailableMachines is the number of machines allocated to the tenant that
+	// are Ready and not used by an instance
+	// +optional
+	AvailableMachines int32 `json:"availableMachines"`
+
+	// AllocatedMachines is the number of machines allocated to the tenant
+	// +optional
+	AllocatedMachines int32 `json:"allocatedMachines"`
+
+	// UsedMachines is the number of allocated machines used by an instance
+	// +optional
+	UsedMachines int32 `json:"usedMachines"`
+
+	// State of the instance type in NVIDIA Carbide
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// LastDiscoveryTime is the time the instance type was last read from NVIDIA Carbide
+	// +optional
+	LastDiscoveryTime *metav1.Time `json:"lastDiscoveryTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=ncxinfrainstancetypes,scope=Namespaced,categories=cluster-api
+// +kubebuilder:printcolumn:name="Instance Type",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="GPU",type="string",JSONPath=".status.gpuModel"
+// +kubebuilder:printcolumn:name="GPUs",type="integer",JSONPath=".status.gpuCount"
+// +kubebuilder:printcolumn:name="Available",type="integer",JSONPath=".status.availableMachines"
+// +kubebuilder:printcolumn:name="Allocated",type="integer",JSONPath=".status.allocatedMachines"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NcxInfraInstanceType is the Schema for the ncxinfrainstancetypes API.
+// It is read-only: the provider discovers the instance types of the site of
+// each NcxInfraCluster and keeps one object per instance type in the namespace
+// of the cluster, so users and autoscalers can inspect the hardware offered
+// by a site.
+type NcxInfraInstanceType struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec identifies the instance type
+	// +required
+	Spec NcxInfraInstanceTypeSpec `json:"spec"`
+
+	// status is the discovered hardware and availability of the instance type
+	// +optional
+	Status NcxInfraInstanceTypeStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// NcxInfraInstanceTypeList contains a list of NcxInfraInstanceType
+type NcxInfraInstanceTypeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NcxInfraInstanceType `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NcxInfraInstanceType{}, &NcxInfraInstanceTypeList{})
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultLoadBalancerClass is the load balancer class of the Services an
+// NcxInfraLoadBalancer assigns addresses to, unless it sets another one.
+const DefaultLoadBalancerClass = "ncx-infra.io/load-balancer"
+
+// NcxInfraLoadBalancerSpec defines the addresses handed out to the Services
+// of type LoadBalancer of a workload cluster
+type NcxInfraLoadBalancerSpec struct {
+	// ClusterName is the name of the Cluster, in the namespace of the load
+	// balancer, whose Services get the addresses
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+
+	// Addresses is the pool of addresses assigned to the Services, as CIDRs
+	// (e.g. "10.0.8.0/28") or ranges (e.g. "10.0.8.10-10.0.8.50"). They must
+	// be routed to the nodes of the cluster, e.g. addresses of a subnet of the
+	// cluster outside of its DHCP range, and announced by a speaker running
+	// in the workload cluster, such as MetalLB or kube-vip.
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:items:MaxLength=90
+	Addresses []string `json:"addresses"`
+
+	// LoadBalancerClass is the spec.loadBalancerClass of the Services to
+	// assign addresses to. Defaults to ncx-infra.io/load-balancer.
+	// +optional
+	// +kubebuilder:default="ncx-infra.io/load-balancer"
+	// +kubebuilder:validation:MaxLength=253
+	LoadBalancerClass string `json:"loadBalancerClass,omitempty"`
+
+	// DefaultClass also assigns addresses to the Services without
+	// loadBalancerClass, which no cloud provider serves on bare metal
+	// +optional
+	DefaultClass bool `json:"defaultClass,omitempty"`
+}
+
+// LoadBalancerServiceStatus is the address assigned to a Service
+type LoadBalancerServiceStatus struct {
+	// Namespace of the Service in the workload cluster
+	// +required
+	Namespace string `json:"namespace"`
+
+	// Name of the Service
+	// +required
+	Name string `json:"name"`
+
+	// Address assigned to the Service, reported in its
+	// status.loadBalancer.ingress
+	// +required
+	Address string `json:"address"`
+}
+
+// NcxInfraLoadBalancerStatus defines the observed state of NcxInfraLoadBalancer
+type NcxInfraLoadBalancerStatus struct {
+	// Services are the Services of the workload cluster and their address
+	// +optional
+	// +listType=map
+	// +listMapKey=namespace
+	// +listMapKey=name
+	Services []LoadBalancerServiceStatus `json:"services,omitempty"`
+
+	// Available is the number of addresses of the pool left, capped to the
+	// maximum value of an int32
+	// +optional
+	Available int32 `json:"available"`
+
+	// Conditions represent the current state of the NcxInfraLoadBalancer
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=ncxinfraloadbalancers,scope=Namespaced,categories=cluster-api
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="Class",type="string",JSONPath=".spec.loadBalancerClass"
+// +kubebuilder:printcolumn:name="Available",type="integer",JSONPath=".status.available"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NcxInfraLoadBalancer is the Schema for the ncxinfraloadbalancers API.
+// NVIDIA Carbide has no load balancer service: the provider assigns the
+// addresses of the pool to the Services of type LoadBalancer of the workload
+// cluster, and a speaker in the workload cluster announces them to the nodes
+// running the Service.
+type NcxInfraLoadBalancer struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the address pool and the Services it serves
+	// +required
+	Spec NcxInfraLoadBalancerSpec `json:"spec"`
+
+	// status defines the addresses assigned to the Services
+	// +optional
+	Status NcxInfraLoadBalancerStatus `json:"status,omitzero"`
+}
+
+// GetConditions returns the conditions from the status
+func (r *NcxInfraLoadBalancer) GetConditions() []metav1.Condition {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the conditions in the status
+func (r *NcxInfraLoadBalancer) SetConditions(conditions []metav1.Condition) {
+	r.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// NcxInfraLoadBalancerList contains a list of NcxInfraLoadBalancer
+type NcxInfraLoadBalancerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NcxInfraLoadBalancer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NcxInfraLoadBalancer{}, &NcxInfraLoadBalancerList{})
+}
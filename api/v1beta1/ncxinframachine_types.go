@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	capierrors "sigs.k8s.io/cluster-api/errors" //nolint:staticcheck // required for CAPI contract FailureReason types
@@ -32,6 +33,13 @@ type NcxInfraMachineSpec struct {
 	// +optional
 	ProviderID *string `json:"providerID,omitempty"`
 
+	// TenantID overrides the NVIDIA Carbide tenant of the cluster for the
+	// instance of the machine, for clusters whose machines belong to several
+	// tenants of the organization. The instance is created in, and its provider
+	// ID names, this tenant.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+
 	// InstanceType specifies the machine instance configuration
 	// +required
 	InstanceType InstanceTypeSpec `json:"instanceType"`
@@ -77,6 +85,510 @@ type NcxInfraMachineSpec struct {
 	// +kubebuilder:default:=true
 	// +optional
 	PhoneHomeEnabled *bool `json:"phoneHomeEnabled,omitempty"`
+
+	// Deletion controls what happens to the physical machine when the instance is deleted
+	// +optional
+	Deletion *DeletionSpec `json:"deletion,omitempty"`
+
+	// ReprovisionPolicy selects what happens when the bootstrap data or the
+	// operating system of the machine change after its instance was created.
+	// Reimage reinstalls the operating system of the instance with the new
+	// bootstrap data on the same physical machine.
+	// +kubebuilder:default=None
+	// +optional
+	ReprovisionPolicy ReprovisionPolicy `json:"reprovisionPolicy,omitempty"`
+
+	// Placement constrains which physical machine the instance is placed on
+	// +optional
+	Placement *PlacementSpec `json:"placement,omitempty"`
+
+	// Inventory copies asset labels of the physical machine into Kubernetes
+	// +optional
+	Inventory *InventorySpec `json:"inventory,omitempty"`
+
+	// NodeTopologyLabels labels the workload cluster Node with the site, rack,
+	// NVLink domain, GPU and InfiniBand topology of the machine, for
+	// topology-aware scheduling. The labels use the topology.ncx-infra.io/ prefix.
+	// +optional
+	NodeTopologyLabels bool `json:"nodeTopologyLabels,omitempty"`
+
+	// Security sets the platform security requirements of the machine, for
+	// confidential workloads. The machine is not Ready until they are verified.
+	// +optional
+	Security *SecuritySpec `json:"security,omitempty"`
+
+	// Storage lays out the local disks of the machine on first boot, e.g. to
+	// stripe the NVMe disks of a GPU node into a scratch volume.
+	// Requires cloud-config bootstrap data.
+	// +optional
+	Storage *StorageSpec `json:"storage,omitempty"`
+
+	// GPUConfig partitions and configures the GPUs of the machine on first
+	// boot, so that the node joins the cluster with its GPUs ready.
+	// Requires cloud-config bootstrap data and the NVIDIA driver in the image.
+	// +optional
+	GPUConfig *GPUConfigSpec `json:"gpuConfig,omitempty"`
+
+	// KernelArgs are appended to the kernel command line, e.g.
+	// "intel_iommu=on" or "iommu=pt" for SR-IOV. The machine reboots once on
+	// first boot to apply them, before the node joins the cluster.
+	// Requires cloud-config bootstrap data.
+	// +optional
+	// +kubebuilder:validation:MaxItems=64
+	// +kubebuilder:validation:items:Pattern=`^[A-Za-z0-9_.,:=/+-]+$`
+	KernelArgs []string `json:"kernelArgs,omitempty"`
+
+	// Tuning reserves hugepages and isolates CPUs through kernel arguments,
+	// for DPDK and low-jitter workloads. It is applied with kernelArgs.
+	// +optional
+	Tuning *TuningSpec `json:"tuning,omitempty"`
+
+	// HostnameFormat is a Go template of the hostname of the node, e.g.
+	// "{{.ClusterName}}-{{.Role}}-{{.Index}}", to follow the naming
+	// conventions of the site. It can use .ClusterName, .MachineName,
+	// .Namespace, .Role (control-plane or worker), .Site and .Index, the lowest
+	// index not used by another machine of the cluster with the same role. The
+	// hostname is rendered when the instance is first created and kept when it
+	// is reprovisioned. Requires cloud-config bootstrap data.
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	HostnameFormat string `json:"hostnameFormat,omitempty"`
+
+	// Domain is the DNS domain of the node, appended to its hostname to form
+	// its fully qualified domain name. Without hostnameFormat, the hostname
+	// is the name of the machine. Requires cloud-config bootstrap data.
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	Domain string `json:"domain,omitempty"`
+}
+
+// TuningSpec defines the hugepages and CPU isolation of a machine.
+type TuningSpec struct {
+	// HugePages are the hugepages reserved at boot. The first size is the
+	// default hugepage size.
+	// +optional
+	// +listType=map
+	// +listMapKey=size
+	// +kubebuilder:validation:MaxItems=2
+	HugePages []HugePagesSpec `json:"hugePages,omitempty"`
+
+	// IsolatedCPUs is the list of CPUs removed from the scheduler, timer
+	// ticks and RCU callbacks of the kernel, e.g. "2-31,34-63".
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(-[0-9]+)?(,[0-9]+(-[0-9]+)?)*$`
+	IsolatedCPUs string `json:"isolatedCPUs,omitempty"`
+}
+
+// HugePagesSpec defines a number of hugepages of a size.
+type HugePagesSpec struct {
+	// Size of the pages.
+	// +required
+	Size HugePageSize `json:"size"`
+
+	// Count is the number of pages reserved.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count"`
+}
+
+// HugePageSize is the size of a hugepage.
+// +kubebuilder:validation:Enum="2Mi";"1Gi"
+type HugePageSize string
+
+const (
+	// HugePageSize2Mi is the 2 MiB page size.
+	HugePageSize2Mi HugePageSize = "2Mi"
+
+	// HugePageSize1Gi is the 1 GiB page size.
+	HugePageSize1Gi HugePageSize = "1Gi"
+)
+
+// GPUConfigSpec defines the configuration of the GPUs of a machine. It is
+// applied with nvidia-smi by cloud-init before the node is bootstrapped.
+type GPUConfigSpec struct {
+	// MIG partitions GPUs into Multi-Instance GPU (MIG) instances. GPUs that
+	// are not listed are left as they are.
+	// +optional
+	// +listType=map
+	// +listMapKey=gpu
+	// +kubebuilder:validation:MaxItems=16
+	MIG []MIGSpec `json:"mig,omitempty"`
+
+	// ComputeMode of all the GPUs. The driver default is kept when empty.
+	// +optional
+	ComputeMode GPUComputeMode `json:"computeMode,omitempty"`
+
+	// PersistenceMode keeps the driver loaded when no process uses the GPUs,
+	// avoiding the initialization delay of each new workload.
+	// +optional
+	PersistenceMode bool `json:"persistenceMode,omitempty"`
+
+	// FabricManager starts the NVIDIA Fabric Manager, which NVSwitch systems
+	// such as HGX need to run workloads across GPUs.
+	// +optional
+	FabricManager bool `json:"fabricManager,omitempty"`
+}
+
+// MIGSpec defines the MIG instances of a GPU.
+type MIGSpec struct {
+	// GPU is the index of the GPU, as listed by nvidia-smi.
+	// +required
+	// +kubebuilder:validation:Minimum=0
+	GPU int32 `json:"gpu"`
+
+	// Profiles are the GPU instance profiles to create, each with its compute
+	// instance, e.g. ["3g.40gb", "2g.20gb", "1g.10gb"].
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=7
+	// +kubebuilder:validation:items:Pattern=`^[1-7]g\.[0-9]+gb(\+me)?$`
+	Profiles []string `json:"profiles"`
+}
+
+// GPUComputeMode is the compute mode of a GPU.
+// +kubebuilder:validation:Enum=Default;ExclusiveProcess;Prohibited
+type GPUComputeMode string
+
+const (
+	// GPUComputeModeDefault lets several processes use the GPU.
+	GPUComputeModeDefault GPUComputeMode = "Default"
+
+	// GPUComputeModeExclusiveProcess lets a single process use the GPU.
+	GPUComputeModeExclusiveProcess GPUComputeMode = "ExclusiveProcess"
+
+	// GPUComputeModeProhibited prevents processes from using the GPU.
+	GPUComputeModeProhibited GPUComputeMode = "Prohibited"
+)
+
+// StorageSpec defines the local storage layout of a machine. NVIDIA Carbide
+// has no storage settings on instances, so the layout is applied by cloud-init
+// before the node is bootstrapped. The operating system disk is the one the
+// operating system image of NVIDIA Carbide is written to.
+type StorageSpec struct {
+	// Arrays are the software RAID arrays assembled from the data disks.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	Arrays []RAIDArraySpec `json:"arrays,omitempty"`
+}
+
+// RAIDArraySpec defines a Linux software RAID (md) array and its filesystem.
+type RAIDArraySpec struct {
+	// Name of the array, also used as the label of its filesystem.
+	// +required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9-]{0,11}$`
+	Name string `json:"name"`
+
+	// Level is the RAID level: 0 (striping), 1 (mirroring), 5, 6 or 10.
+	// +required
+	// +kubebuilder:validation:Enum=0;1;5;6;10
+	Level int32 `json:"level"`
+
+	// Devices are the disks of the array, e.g. /dev/nvme1n1 or a stable
+	// /dev/disk/by-id/ path. Their content is erased.
+	// +required
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:items:Pattern=`^/dev/[A-Za-z0-9/_.:+-]+$`
+	Devices []string `json:"devices"`
+
+	// Filesystem created on the array.
+	// +kubebuilder:validation:Enum=xfs;ext4
+	// +kubebuilder:default=xfs
+	// +optional
+	Filesystem string `json:"filesystem,omitempty"`
+
+	// MountPath is where the filesystem is mounted. The array is left
+	// unmounted when empty.
+	// +kubebuilder:validation:Pattern=`^/[A-Za-z0-9/_.-]*$`
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// SecuritySpec defines the platform security requirements of an instance.
+// NVIDIA Carbide has no security settings on instances, so the requirements
+// are passed as instance labels and verified from what the instance reports.
+type SecuritySpec struct {
+	// RequireTPM requires the machine to have a TPM, verified from the TPM
+	// endorsement key certificate of the instance
+	// +optional
+	RequireTPM bool `json:"requireTPM,omitempty"`
+
+	// SecureBoot requires the machine to boot with UEFI secure boot
+	// +optional
+	SecureBoot bool `json:"secureBoot,omitempty"`
+
+	// MeasuredBoot requires the boot measurements of the machine to be attested.
+	// Attestation is rooted in the TPM, so it implies requireTPM.
+	// +optional
+	MeasuredBoot bool `json:"measuredBoot,omitempty"`
+
+	// BootMode requires the firmware to boot in UEFI or Legacy (BIOS) mode.
+	// Secure boot and measured boot require UEFI.
+	// +optional
+	BootMode BootMode `json:"bootMode,omitempty"`
+}
+
+// BootMode is the boot mode of the firmware of a machine.
+// +kubebuilder:validation:Enum=UEFI;Legacy
+type BootMode string
+
+const (
+	// BootModeUEFI boots the machine through UEFI.
+	BootModeUEFI BootMode = "UEFI"
+
+	// BootModeLegacy boots the machine through the legacy BIOS.
+	BootModeLegacy BootMode = "Legacy"
+)
+
+// Labels passing spec.security to the NVIDIA Carbide instance, set to "required".
+const (
+	// SecurityTPMLabel requires a TPM on the machine.
+	SecurityTPMLabel = "ncx-infra.io/tpm"
+
+	// SecuritySecureBootLabel requires UEFI secure boot.
+	SecuritySecureBootLabel = "ncx-infra.io/secure-boot"
+
+	// SecurityMeasuredBootLabel requires measured boot attestation.
+	SecurityMeasuredBootLabel = "ncx-infra.io/measured-boot"
+
+	// SecurityBootModeLabel requires a boot mode, set to "uefi" or "legacy"
+	// instead of "required".
+	SecurityBootModeLabel = "ncx-infra.io/boot-mode"
+)
+
+// Labels passing the VLANs of spec.network.additionalInterfaces to the NVIDIA
+// Carbide instance, formatted with the index of the interface in the list.
+// The trunk VLANs are joined with underscores, e.g. "100_200".
+const (
+	// InterfaceAccessVLANLabelFormat is the access VLAN of an interface.
+	InterfaceAccessVLANLabelFormat = "ncx-infra.io/interface-%d-access-vlan"
+
+	// InterfaceTrunkVLANsLabelFormat is the trunk VLANs of an interface.
+	InterfaceTrunkVLANsLabelFormat = "ncx-infra.io/interface-%d-trunk-vlans"
+
+	// InterfaceNativeVLANLabelFormat is the native VLAN of a trunk.
+	InterfaceNativeVLANLabelFormat = "ncx-infra.io/interface-%d-native-vlan"
+)
+
+// MachinePowerDomainLabel is the power domain of a NVIDIA Carbide machine, set
+// by the site operators on the machines fed by the same power distribution.
+const MachinePowerDomainLabel = "topology.ncx-infra.io/power-domain"
+
+// Labels describing the firmware of the NVIDIA Carbide machines, set by the
+// site operators. The security requirements of an instance are checked
+// against the labels of the machine it is placed on. A machine without them
+// is assumed to meet the requirements.
+const (
+	// FirmwareTPMLabel is "true" when the machine has a TPM, "false" otherwise.
+	FirmwareTPMLabel = "firmware.ncx-infra.io/tpm"
+
+	// FirmwareSecureBootLabel is "true" when the firmware supports UEFI secure
+	// boot, "false" otherwise.
+	FirmwareSecureBootLabel = "firmware.ncx-infra.io/secure-boot"
+
+	// FirmwareBootModeLabel lists the boot modes the firmware supports:
+	// "uefi", "legacy" or "uefi-legacy".
+	FirmwareBootModeLabel = "firmware.ncx-infra.io/boot-mode"
+)
+
+// InventorySpec selects the NVIDIA Carbide machine labels copied into Kubernetes
+type InventorySpec struct {
+	// LabelKeys lists the machine label keys to copy (e.g. rack, datacenter, sku, warranty).
+	// The values are recorded in status.inventory.
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	LabelKeys []string `json:"labelKeys"`
+
+	// NodeLabelPrefix, when set, also labels the Node with <prefix>/<key>. When the
+	// physical machine is known before the instance is created (instanceType.machineID
+	// or placement), the labels are set at registration through cloud-config bootstrap
+	// data, otherwise they are added once the Node joins the cluster. Values that are
+	// not valid label values are skipped.
+	// +optional
+	NodeLabelPrefix string `json:"nodeLabelPrefix,omitempty"`
+}
+
+// AntiAffinityMode defines how strictly an anti-affinity rule is enforced.
+// +kubebuilder:validation:Enum=None;Preferred;Required
+type AntiAffinityMode string
+
+const (
+	// AntiAffinityNone disables the rule.
+	AntiAffinityNone AntiAffinityMode = "None"
+
+	// AntiAffinityPreferred applies the rule when a matching machine is available
+	// and falls back to any machine of the instance type otherwise.
+	AntiAffinityPreferred AntiAffinityMode = "Preferred"
+
+	// AntiAffinityRequired keeps the instance pending until a matching machine is available.
+	AntiAffinityRequired AntiAffinityMode = "Required"
+)
+
+// PlacementSpec defines physical placement constraints for the instance
+type PlacementSpec struct {
+	// ChassisAntiAffinity spreads the control plane machines of a cluster across
+	// distinct chassis. Requires targeted instance creation on the tenant.
+	// +kubebuilder:default=None
+	// +optional
+	ChassisAntiAffinity AntiAffinityMode `json:"chassisAntiAffinity,omitempty"`
+
+	// AntiAffinity spreads the machines of a MachineDeployment, or of the
+	// control plane, across distinct racks, chassis or power domains.
+	// Requires targeted instance creation on the tenant. Cannot be used with
+	// chassisAntiAffinity.
+	// +optional
+	AntiAffinity *AntiAffinitySpec `json:"antiAffinity,omitempty"`
+}
+
+// AntiAffinitySpec defines a failure unit the machines of a group avoid sharing.
+type AntiAffinitySpec struct {
+	// Level is the failure unit the machines are spread across.
+	// +required
+	Level FailureUnit `json:"level"`
+
+	// Mode defines how strictly the rule is enforced.
+	// +kubebuilder:default=Preferred
+	// +kubebuilder:validation:Enum=Preferred;Required
+	// +optional
+	Mode AntiAffinityMode `json:"mode,omitempty"`
+}
+
+// FailureUnit is a set of machines that can fail together.
+// +kubebuilder:validation:Enum=Rack;Chassis;PowerDomain
+type FailureUnit string
+
+const (
+	// FailureUnitRack is the rack hosting the compute tray of the machine.
+	FailureUnitRack FailureUnit = "Rack"
+
+	// FailureUnitChassis is the chassis of the machine.
+	FailureUnitChassis FailureUnit = "Chassis"
+
+	// FailureUnitPowerDomain is the power domain of the machine, from its
+	// topology.ncx-infra.io/power-domain label.
+	FailureUnitPowerDomain FailureUnit = "PowerDomain"
+)
+
+// Labels applied to the workload cluster Node when spec.nodeTopologyLabels is set.
+const (
+	// TopologySiteLabel is the NVIDIA Carbide site of the machine.
+	TopologySiteLabel = "topology.ncx-infra.io/site"
+
+	// TopologyRackLabel is the rack hosting the machine compute tray.
+	TopologyRackLabel = "topology.ncx-infra.io/rack"
+
+	// TopologyNVLinkDomainLabel is the NVLink domain the machine GPUs belong to.
+	TopologyNVLinkDomainLabel = "topology.ncx-infra.io/nvlink-domain"
+
+	// TopologyGPUModelLabel is the model of the machine GPUs.
+	TopologyGPUModelLabel = "topology.ncx-infra.io/gpu-model"
+
+	// TopologyGPUCountLabel is the number of GPUs in the machine.
+	TopologyGPUCountLabel = "topology.ncx-infra.io/gpu-count"
+
+	// TopologyInfiniBandPartitionLabel is the InfiniBand partition of the instance.
+	TopologyInfiniBandPartitionLabel = "topology.ncx-infra.io/infiniband-partition"
+
+	// TopologyInfiniBandRailsLabel is the number of InfiniBand rails attached to the instance.
+	TopologyInfiniBandRailsLabel = "topology.ncx-infra.io/infiniband-rails"
+)
+
+// PowerActionAnnotation requests an out-of-band power action on the machine.
+// The controller removes the annotation once the action has been submitted.
+const PowerActionAnnotation = "ncx-infra.io/power-action"
+
+// ConsoleAnnotation requests access to the out-of-band serial console of the
+// instance, reported in status.console. The controller removes the annotation
+// once the request has been processed.
+const ConsoleAnnotation = "ncx-infra.io/console"
+
+// PowerAction is a value accepted by the power action annotation.
+type PowerAction string
+
+const (
+	// PowerActionReboot reboots the instance through the instance API.
+	PowerActionReboot PowerAction = "reboot"
+
+	// PowerActionPowerOff gracefully powers off the machine tray.
+	PowerActionPowerOff PowerAction = "power-off"
+
+	// PowerActionPowerOn powers on the machine tray.
+	PowerActionPowerOn PowerAction = "power-on"
+)
+
+// ReprovisionPolicy defines how an instance follows changes to its bootstrap data or operating system.
+// +kubebuilder:validation:Enum=None;Reimage
+type ReprovisionPolicy string
+
+const (
+	// ReprovisionPolicyNone keeps the instance as created, the machine has
+	// to be replaced to apply the changes.
+	ReprovisionPolicyNone ReprovisionPolicy = "None"
+
+	// ReprovisionPolicyReimage reimages the instance in place.
+	ReprovisionPolicyReimage ReprovisionPolicy = "Reimage"
+)
+
+// DeletionPolicy defines what NVIDIA Carbide does with the machine once its instance is deleted.
+// +kubebuilder:validation:Enum=Release;Repair
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRelease returns the machine to the allocation pool.
+	DeletionPolicyRelease DeletionPolicy = "Release"
+
+	// DeletionPolicyRepair reports a machine health issue so that the machine
+	// is sent to repair instead of being handed to the next tenant.
+	DeletionPolicyRepair DeletionPolicy = "Repair"
+)
+
+// SecureEraseLevel defines how disk sanitization is enforced when the machine is released.
+// +kubebuilder:validation:Enum=Standard;Verified
+type SecureEraseLevel string
+
+const (
+	// SecureEraseStandard relies on the NVIDIA Carbide machine cleanup without waiting for it.
+	SecureEraseStandard SecureEraseLevel = "Standard"
+
+	// SecureEraseVerified keeps the NcxInfraMachine until the machine has gone
+	// through its reset cycle, which wipes the local disks.
+	SecureEraseVerified SecureEraseLevel = "Verified"
+)
+
+// DeletionSpec defines the instance deletion options
+type DeletionSpec struct {
+	// Policy selects whether the machine is released or sent to repair
+	// +kubebuilder:default=Release
+	// +optional
+	Policy DeletionPolicy `json:"policy,omitempty"`
+
+	// HealthIssue describes the machine problem reported with the Repair policy
+	// +optional
+	HealthIssue *MachineHealthIssueSpec `json:"healthIssue,omitempty"`
+
+	// SecureErase selects the disk sanitization level.
+	// Verified cannot be combined with the Repair policy.
+	// +kubebuilder:default=Standard
+	// +optional
+	SecureErase SecureEraseLevel `json:"secureErase,omitempty"`
+}
+
+// MachineHealthIssueSpec describes a machine health issue reported to NVIDIA Carbide
+type MachineHealthIssueSpec struct {
+	// Category of the issue
+	// +kubebuilder:validation:Enum=Hardware;Network;Performance;Other
+	// +kubebuilder:default=Hardware
+	// +optional
+	Category string `json:"category,omitempty"`
+
+	// Summary is a short description of the issue
+	// +optional
+	Summary string `json:"summary,omitempty"`
+
+	// Details helpful for diagnosis
+	// +optional
+	Details string `json:"details,omitempty"`
 }
 
 // InfiniBandInterfaceSpec defines an InfiniBand partition attachment
@@ -172,6 +684,10 @@ type NetworkSpec struct {
 	// AdditionalInterfaces for multi-NIC configurations
 	// +optional
 	AdditionalInterfaces []NetworkInterface `json:"additionalInterfaces,omitempty"`
+
+	// NetworkServices override the DNS and NTP servers of the cluster and of the
+	// DHCP options of the subnets
+	NetworkServices `json:",inline"`
 }
 
 // NetworkInterface defines an additional network interface
@@ -194,24 +710,218 @@ type NetworkInterface struct {
 	// IsPhysical indicates if this is a physical interface
 	// +optional
 	IsPhysical bool `json:"isPhysical,omitempty"`
+
+	// ClusterRef references another NcxInfraCluster of the namespace, owning the
+	// subnet or VPC prefix of this interface, to attach the machine to a storage
+	// or management network shared between clusters. The referenced cluster
+	// must belong to the same tenant and site as the cluster of the machine.
+	// +optional
+	ClusterRef *corev1.LocalObjectReference `json:"clusterRef,omitempty"`
+
+	// SRIOV attaches the subnet to SR-IOV virtual functions of a network
+	// device of the machine, for pods to use through the SR-IOV device plugin.
+	// Requires subnetName and a virtual interface.
+	// +optional
+	SRIOV *SRIOVSpec `json:"sriov,omitempty"`
+
+	// VLAN attaches the interface to tagged networks, either as an access
+	// port of a VLAN or as a trunk. Cannot be used with sriov, whose virtual
+	// functions have their own VLAN.
+	// +optional
+	VLAN *VLANSpec `json:"vlan,omitempty"`
+}
+
+// VLANSpec defines the VLANs of an interface. NVIDIA Carbide has no VLAN
+// settings on instance interfaces, so the VLANs are passed as instance labels
+// for the site network automation to configure the port of the interface.
+type VLANSpec struct {
+	// AccessVLAN carries the untagged traffic of the interface in a VLAN.
+	// Mutually exclusive with trunkVLANs.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4094
+	AccessVLAN int32 `json:"accessVLAN,omitempty"`
+
+	// TrunkVLANs are the VLANs the interface sends and receives tagged
+	// traffic on.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=12
+	// +kubebuilder:validation:items:Minimum=1
+	// +kubebuilder:validation:items:Maximum=4094
+	TrunkVLANs []int32 `json:"trunkVLANs,omitempty"`
+
+	// NativeVLAN carries the untagged traffic of a trunk. Requires trunkVLANs.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4094
+	NativeVLAN int32 `json:"nativeVLAN,omitempty"`
+}
+
+// SRIOVInterfaceStatus reports the virtual functions of an interface with
+// SR-IOV. NVIDIA Carbide reports the PCI address of the physical function of
+// the device, the device plugin selects its virtual functions with the
+// rootDevices selector.
+type SRIOVInterfaceStatus struct {
+	// SubnetName is the subnet of the interface.
+	SubnetName string `json:"subnetName"`
+
+	// Device is the name of the network device.
+	// +optional
+	Device string `json:"device,omitempty"`
+
+	// PCIAddress is the PCI address of the physical function of the device.
+	// +optional
+	PCIAddress string `json:"pciAddress,omitempty"`
+
+	// Trust is the trust mode of the virtual functions.
+	// +optional
+	Trust bool `json:"trust,omitempty"`
+
+	// VLAN of the virtual functions.
+	// +optional
+	VLAN int32 `json:"vlan,omitempty"`
+
+	// VirtualFunctions are the virtual functions attached to the subnet.
+	// +optional
+	VirtualFunctions []VirtualFunctionStatus `json:"virtualFunctions,omitempty"`
+}
+
+// VirtualFunctionStatus reports a virtual function attached to a subnet.
+type VirtualFunctionStatus struct {
+	// ID is the index of the virtual function on its device.
+	ID int32 `json:"id"`
+
+	// MACAddress of the virtual function.
+	// +optional
+	MACAddress string `json:"macAddress,omitempty"`
+
+	// IPAddresses allocated to the virtual function on the subnet.
+	// +optional
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+}
+
+// SRIOVSpec defines the SR-IOV virtual functions of an interface. NVIDIA
+// Carbide attaches each virtual function to the subnet. The trust mode and
+// VLAN are set on the virtual functions by the SR-IOV CNI plugin, from the
+// status of the machine.
+type SRIOVSpec struct {
+	// Device is the name of the network device whose virtual functions are
+	// used, e.g. "BlueField3". NVIDIA Carbide picks the device when empty.
+	// +optional
+	Device string `json:"device,omitempty"`
+
+	// DeviceInstance is the index of the device among the devices with the
+	// same name.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	DeviceInstance *int32 `json:"deviceInstance,omitempty"`
+
+	// NumVFs is the number of virtual functions attached to the subnet,
+	// starting from virtual function 0.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	NumVFs int32 `json:"numVFs"`
+
+	// Trust lets the pods change the MAC address of the virtual functions and
+	// receive promiscuous and multicast traffic.
+	// +optional
+	Trust bool `json:"trust,omitempty"`
+
+	// VLAN tags the traffic of the virtual functions. Untagged when zero.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4094
+	VLAN int32 `json:"vlan,omitempty"`
 }
 
+// Machine phases
+const (
+	// MachinePhasePending means no instance was created for the machine yet.
+	MachinePhasePending = "Pending"
+
+	// MachinePhaseProvisioning means the instance exists and is not ready yet.
+	MachinePhaseProvisioning = "Provisioning"
+
+	// MachinePhaseRunning means the instance is ready.
+	MachinePhaseRunning = "Running"
+
+	// MachinePhaseFailed means reconciling the machine hit a terminal problem.
+	MachinePhaseFailed = "Failed"
+
+	// MachinePhaseDeleting means the instance is being deleted.
+	MachinePhaseDeleting = "Deleting"
+)
+
+// InstancePhase is the phase of the instance of a machine, derived from the
+// state NVIDIA Carbide reports for it.
+// +kubebuilder:validation:Enum=Pending;Allocating;Imaging;Booting;Ready;Failed;Deleting
+type InstancePhase string
+
+const (
+	// InstancePhasePending means no instance was created for the machine yet,
+	// for instance while the site lacks capacity.
+	InstancePhasePending InstancePhase = "Pending"
+
+	// InstancePhaseAllocating means the instance waits for NVIDIA Carbide to
+	// allocate a machine to it (NVIDIA Carbide state Pending).
+	InstancePhaseAllocating InstancePhase = "Allocating"
+
+	// InstancePhaseImaging means the operating system image is being written
+	// to the machine of the instance (NVIDIA Carbide state Provisioning).
+	InstancePhaseImaging InstancePhase = "Imaging"
+
+	// InstancePhaseBooting means the instance boots and gets its network
+	// configured (NVIDIA Carbide states Configuring and Rebooting).
+	InstancePhaseBooting InstancePhase = "Booting"
+
+	// InstancePhaseReady means the instance runs on its machine (NVIDIA
+	// Carbide states Ready and Updating).
+	InstancePhaseReady InstancePhase = "Ready"
+
+	// InstancePhaseFailed means the instance is in error, or reconciling the
+	// machine hit a terminal problem (NVIDIA Carbide state Error).
+	InstancePhaseFailed InstancePhase = "Failed"
+
+	// InstancePhaseDeleting means the instance is being deleted (NVIDIA
+	// Carbide state Terminating), or the machine is.
+	InstancePhaseDeleting InstancePhase = "Deleting"
+)
+
 // NcxInfraMachineStatus defines the observed state of NcxInfraMachine.
 type NcxInfraMachineStatus struct {
 	// Ready indicates if the machine is ready and available
 	// +optional
 	Ready bool `json:"ready"`
 
+	// Phase summarizes the state of the machine
+	// Possible values: Pending, Provisioning, Running, Failed, Deleting
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
 	// InstanceID is the NVIDIA Carbide instance ID
 	// +optional
 	InstanceID string `json:"instanceID,omitempty"`
 
+	// InstanceOrigin records the creation of the instance
+	// +optional
+	InstanceOrigin *ResourceOrigin `json:"instanceOrigin,omitempty"`
+
 	// MachineID is the physical machine ID
 	// +optional
 	MachineID string `json:"machineID,omitempty"`
 
-	// InstanceState represents the current state of the instance
-	// Possible values: Pending, Provisioning, Ready, Error, Terminating
+	// InstancePhase is the phase of the instance, derived from InstanceState.
+	// The lastTransitionTime of the InstanceProvisioned, InstanceAllocated,
+	// InstanceImaged and InstanceBooted conditions records when each phase
+	// ended.
+	// +optional
+	InstancePhase InstancePhase `json:"instancePhase,omitempty"`
+
+	// InstanceState is the state of the instance as reported by NVIDIA Carbide
+	// Possible values: Pending, Provisioning, Configuring, Ready, Updating,
+	// Rebooting, Error, Terminating
 	// +optional
 	InstanceState string `json:"instanceState,omitempty"`
 
@@ -220,6 +930,11 @@ type NcxInfraMachineStatus struct {
 	// +optional
 	ProviderID *string `json:"providerID,omitempty"`
 
+	// NodeName is the name of the workload cluster Node whose provider ID
+	// matches the machine
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
 	// Addresses contains the IP addresses assigned to the machine
 	// +optional
 	Addresses []clusterv1.MachineAddress `json:"addresses,omitempty"`
@@ -236,11 +951,215 @@ type NcxInfraMachineStatus struct {
 	// +optional
 	FailureMessage *string `json:"failureMessage,omitempty"`
 
+	// Placement records the placement decision taken when the instance was created
+	// +optional
+	Placement *PlacementStatus `json:"placement,omitempty"`
+
+	// LastPowerAction records the last power action requested through the
+	// power action annotation
+	// +optional
+	LastPowerAction *PowerActionStatus `json:"lastPowerAction,omitempty"`
+
+	// Console describes how to reach the serial console of the instance, as
+	// requested through the console annotation
+	// +optional
+	Console *ConsoleStatus `json:"console,omitempty"`
+
+	// Provisioned records the bootstrap data and operating system the
+	// instance was last provisioned with
+	// +optional
+	Provisioned *ProvisionedStatus `json:"provisioned,omitempty"`
+
+	// Inventory holds the asset labels of the physical machine selected by spec.inventory
+	// +optional
+	Inventory map[string]string `json:"inventory,omitempty"`
+
+	// SRIOVInterfaces reports the virtual functions allocated to the
+	// interfaces with SR-IOV, to configure the SR-IOV device plugin
+	// +optional
+	SRIOVInterfaces []SRIOVInterfaceStatus `json:"sriovInterfaces,omitempty"`
+
+	// Topology holds the topology labels applied to the Node when
+	// spec.nodeTopologyLabels is set
+	// +optional
+	Topology map[string]string `json:"topology,omitempty"`
+
+	// Hardware describes the physical machine of the instance, once allocated
+	// +optional
+	Hardware *HardwareStatus `json:"hardware,omitempty"`
+
+	// Hostname is the hostname of the node, rendered from spec.hostnameFormat
+	// when the instance was first created
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
 	// Conditions represent the current state of the NcxInfraMachine
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// HardwareStatus describes the physical machine of an instance
+type HardwareStatus struct {
+	// MachineID is the NVIDIA Carbide machine the details were read from
+	// +optional
+	MachineID string `json:"machineID,omitempty"`
+
+	// Vendor is the vendor of the machine
+	// +optional
+	Vendor string `json:"vendor,omitempty"`
+
+	// ProductName is the product name of the machine
+	// +optional
+	ProductName string `json:"productName,omitempty"`
+
+	// SerialNumber is the serial number of the machine, only visible to
+	// provider admins
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	// GPUModel is the model of the GPUs of the machine
+	// +optional
+	GPUModel string `json:"gpuModel,omitempty"`
+
+	// GPUCount is the number of GPUs of the machine
+	// +optional
+	GPUCount int32 `json:"gpuCount,omitempty"`
+
+	// NetworkInterfaces lists the Ethernet adapters of the machine, only
+	// visible to provider admins
+	// +optional
+	NetworkInterfaces []HardwareInterface `json:"networkInterfaces,omitempty"`
+
+	// InfiniBandInterfaces lists the InfiniBand adapters of the machine, only
+	// visible to provider admins
+	// +optional
+	InfiniBandInterfaces []HardwareInterface `json:"infinibandInterfaces,omitempty"`
+
+	// Rack is the rack hosting the compute tray of the machine, only visible
+	// to provider admins
+	// +optional
+	Rack string `json:"rack,omitempty"`
+
+	// Slot is the slot of the compute tray of the machine in its rack, only
+	// visible to provider admins
+	// +optional
+	Slot *int32 `json:"slot,omitempty"`
+}
+
+// HardwareInterface describes a network adapter of a machine
+type HardwareInterface struct {
+	// Address is the MAC address of an Ethernet adapter, or the GUID of an
+	// InfiniBand one
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// Vendor is the vendor of the adapter
+	// +optional
+	Vendor string `json:"vendor,omitempty"`
+
+	// Device is the model of the adapter
+	// +optional
+	Device string `json:"device,omitempty"`
+
+	// Slot is the PCI slot of the adapter
+	// +optional
+	Slot string `json:"slot,omitempty"`
+
+	// NUMANode is the NUMA node the adapter is attached to
+	// +optional
+	NUMANode *int32 `json:"numaNode,omitempty"`
+}
+
+// PlacementStatus records where the instance was placed and why
+type PlacementStatus struct {
+	// ChassisSerial is the serial number of the chassis hosting the machine, if known
+	// +optional
+	ChassisSerial string `json:"chassisSerial,omitempty"`
+
+	// Rack is the rack hosting the compute tray of the machine, if known
+	// +optional
+	Rack string `json:"rack,omitempty"`
+
+	// PowerDomain is the power domain of the machine, if known
+	// +optional
+	PowerDomain string `json:"powerDomain,omitempty"`
+
+	// Decision is the outcome of the placement constraints
+	// Possible values: DistinctChassis, SharedChassis, DistinctRack, SharedRack,
+	// DistinctPowerDomain, SharedPowerDomain, Unconstrained
+	// +optional
+	Decision string `json:"decision,omitempty"`
+
+	// Message gives details about the placement decision
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ProvisionedStatus records what an instance was provisioned with
+type ProvisionedStatus struct {
+	// BootstrapDataHash is the SHA256 hash of the bootstrap data
+	// +optional
+	BootstrapDataHash string `json:"bootstrapDataHash,omitempty"`
+
+	// OperatingSystemID is the NVIDIA Carbide operating system UUID
+	// +optional
+	OperatingSystemID string `json:"operatingSystemID,omitempty"`
+
+	// Reimages counts the reimages of the instance
+	// +optional
+	Reimages int32 `json:"reimages,omitempty"`
+
+	// LastReimageTime is when the instance was last reimaged
+	// +optional
+	LastReimageTime *metav1.Time `json:"lastReimageTime,omitempty"`
+}
+
+// ConsoleStatus describes the access to the serial console of an instance
+type ConsoleStatus struct {
+	// URL is the SSH URL of the serial console of the instance
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// SSHKeySecretName is the Secret holding the break-glass SSH key accepted
+	// by the serial console, in the namespace of the cluster
+	// +optional
+	SSHKeySecretName string `json:"sshKeySecretName,omitempty"`
+
+	// Result is the outcome of the request
+	// Possible values: Ready, Failed
+	// +optional
+	Result string `json:"result,omitempty"`
+
+	// Message gives details about the outcome, such as the session limits of the site
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Time is when the request was processed
+	// +optional
+	Time metav1.Time `json:"time,omitempty"`
+}
+
+// PowerActionStatus records the outcome of a power action
+type PowerActionStatus struct {
+	// Action is the requested power action
+	// +optional
+	Action PowerAction `json:"action,omitempty"`
+
+	// Result is the outcome of the request
+	// Possible values: Submitted, Failed, or Superseded for a power-off
+	// followed by a reboot, a reimage or a power-on outside of the provider
+	// +optional
+	Result string `json:"result,omitempty"`
+
+	// Message gives details about the outcome
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Time is when the action was processed
+	// +optional
+	Time metav1.Time `json:"time,omitempty"`
+}
+
 // GetConditions returns the conditions from the status
 func (m *NcxInfraMachine) GetConditions() []metav1.Condition {
 	return m.Status.Conditions
@@ -253,6 +1172,14 @@ func (m *NcxInfraMachine) SetConditions(conditions []metav1.Condition) {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this NcxInfraMachine belongs"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready",description="Machine is ready"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase of the machine"
+// +kubebuilder:printcolumn:name="Instance",type="string",JSONPath=".status.instancePhase",description="Phase of the NVIDIA Carbide instance"
+// +kubebuilder:printcolumn:name="ProviderID",type="string",JSONPath=".spec.providerID",description="Provider ID of the instance"
+// +kubebuilder:printcolumn:name="Address",type="string",JSONPath=".status.addresses[?(@.type==\"InternalIP\")].address",description="Internal IP addresses of the instance"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time since creation"
+// +kubebuilder:storageversion
 
 // NcxInfraMachine is the Schema for the ncxinframachines API
 type NcxInfraMachine struct {
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,6 +31,16 @@ type NcxInfraMachineTemplateSpec struct {
 	Template NcxInfraMachineTemplateResource `json:"template"`
 }
 
+// NcxInfraMachineTemplateStatus defines the observed state of NcxInfraMachineTemplate
+type NcxInfraMachineTemplateStatus struct {
+	// Capacity defines the resource capacity of the machines created from this
+	// template, derived from the instance type (cpu, memory, ephemeral-storage
+	// and nvidia.com/gpu). It is read by the cluster autoscaler to scale
+	// MachineDeployments from zero.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+}
+
 // NcxInfraMachineTemplateResource describes the data needed to create a NcxInfraMachine from a template
 type NcxInfraMachineTemplateResource struct {
 	// Standard object's metadata
@@ -44,6 +55,7 @@ type NcxInfraMachineTemplateResource struct {
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=ncxinframachinetemplates,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
+// +kubebuilder:subresource:status
 
 // NcxInfraMachineTemplate is the Schema for the ncxinframachinetemplates API
 type NcxInfraMachineTemplate struct {
@@ -56,6 +68,10 @@ type NcxInfraMachineTemplate struct {
 	// spec defines the desired state of NcxInfraMachineTemplate
 	// +required
 	Spec NcxInfraMachineTemplateSpec `json:"spec"`
+
+	// status defines the observed state of NcxInfraMachineTemplate
+	// +optional
+	Status NcxInfraMachineTemplateStatus `json:"status,omitzero"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,238 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ManagedClusterNameLabel is set on the objects generated for a
+// NcxInfraManagedCluster, to the name of the NcxInfraManagedCluster.
+const ManagedClusterNameLabel = "ncx-infra.io/managed-cluster"
+
+// NcxInfraManagedClusterSpec defines the desired state of NcxInfraManagedCluster
+type NcxInfraManagedClusterSpec struct {
+	// SiteRef references the NVIDIA Carbide Site where the cluster will be provisioned
+	// +required
+	SiteRef SiteReference `json:"siteRef"`
+
+	// TenantID is the NVIDIA Carbide tenant ID for multi-tenancy
+	// +required
+	TenantID string `json:"tenantID"`
+
+	// Version is the Kubernetes version of the control plane and workers
+	// +kubebuilder:validation:Pattern=`^v\d+\.\d+\.\d+`
+	// +required
+	Version string `json:"version"`
+
+	// ControlPlane configures the kubeadm control plane machines
+	// +required
+	ControlPlane ManagedControlPlaneSpec `json:"controlPlane"`
+
+	// Workers are the pools of worker machines, each generating a
+	// MachineDeployment named after the cluster and the pool
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	Workers []ManagedWorkerPool `json:"workers,omitempty"`
+
+	// Network configures the VPC, subnets and Kubernetes networks of the cluster
+	// +kubebuilder:default={}
+	// +optional
+	Network ManagedNetworkSpec `json:"network,omitzero"`
+
+	// SSHKeyGroups are the SSH key group IDs attached to all the machines
+	// +optional
+	SSHKeyGroups []string `json:"sshKeyGroups,omitempty"`
+
+	// GPUOperator installs the NVIDIA GPU Operator in the workload cluster,
+	// through a HelmChartProxy of the Cluster API add-on provider for Helm
+	// +optional
+	GPUOperator *GPUOperatorSpec `json:"gpuOperator,omitempty"`
+
+	// Organization is the NVIDIA Carbide organization of the cluster, taking
+	// precedence over the orgName of the credentials secret.
+	// +optional
+	Organization string `json:"organization,omitempty"`
+
+	// Authentication contains credentials for accessing the NVIDIA Carbide API.
+	// Defaults to the credentials of the namespace or controller.
+	// +optional
+	Authentication AuthenticationSpec `json:"authentication,omitzero"`
+}
+
+// ManagedControlPlaneSpec configures the control plane of a managed cluster
+type ManagedControlPlaneSpec struct {
+	// Replicas is the number of control plane machines
+	// +kubebuilder:validation:Enum=1;3;5
+	// +kubebuilder:default=3
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// InstanceTypeID is the NVIDIA Carbide instance type of the control plane machines
+	// +required
+	InstanceTypeID string `json:"instanceTypeID"`
+}
+
+// ManagedWorkerPool configures a pool of worker machines of a managed cluster
+type ManagedWorkerPool struct {
+	// Name of the pool
+	// +kubebuilder:validation:MaxLength=30
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`
+	// +required
+	Name string `json:"name"`
+
+	// Replicas is the number of machines of the pool
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// InstanceTypeID is the NVIDIA Carbide instance type of the machines
+	// +required
+	InstanceTypeID string `json:"instanceTypeID"`
+
+	// GPU installs the NVIDIA driver and container toolkit on the machines
+	// before they join, makes the NVIDIA runtime the default runtime of
+	// containerd, and labels the nodes with nvidia.com/gpu.present
+	// +optional
+	GPU *ManagedGPUSpec `json:"gpu,omitempty"`
+
+	// NodeLabels are added to the nodes of the pool
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+}
+
+// ManagedGPUSpec configures the GPU software of the machines of a worker pool
+type ManagedGPUSpec struct {
+	// DriverBranch is the branch of the NVIDIA data center driver installed
+	// from the packages of the operating system, for instance 570
+	// +kubebuilder:validation:Pattern=`^[0-9]+$`
+	// +kubebuilder:default="570"
+	// +optional
+	DriverBranch string `json:"driverBranch,omitempty"`
+}
+
+// ManagedNetworkSpec configures the networks of a managed cluster
+type ManagedNetworkSpec struct {
+	// ControlPlaneSubnetCIDR is the CIDR of the subnet of the control plane machines
+	// +kubebuilder:default="10.100.1.0/24"
+	// +optional
+	ControlPlaneSubnetCIDR string `json:"controlPlaneSubnetCIDR,omitempty"`
+
+	// WorkerSubnetCIDR is the CIDR of the subnet of the worker machines
+	// +kubebuilder:default="10.100.2.0/24"
+	// +optional
+	WorkerSubnetCIDR string `json:"workerSubnetCIDR,omitempty"`
+
+	// PodCIDR is the CIDR of the pods of the workload cluster
+	// +kubebuilder:default="10.244.0.0/16"
+	// +optional
+	PodCIDR string `json:"podCIDR,omitempty"`
+
+	// ServiceCIDR is the CIDR of the services of the workload cluster
+	// +kubebuilder:default="10.96.0.0/12"
+	// +optional
+	ServiceCIDR string `json:"serviceCIDR,omitempty"`
+
+	// AllowedSourceCIDR is the CIDR allowed to reach the machines over SSH
+	// and the API server
+	// +kubebuilder:default="0.0.0.0/0"
+	// +optional
+	AllowedSourceCIDR string `json:"allowedSourceCIDR,omitempty"`
+}
+
+// GPUOperatorSpec configures the NVIDIA GPU Operator of a managed cluster.
+// The driver and container toolkit are installed on the hosts of the GPU
+// worker pools, so the operator only runs the device plugin, the GPU feature
+// discovery and the DCGM exporter.
+type GPUOperatorSpec struct {
+	// Version of the gpu-operator chart. Defaults to the latest version.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// NcxInfraManagedClusterStatus defines the observed state of NcxInfraManagedCluster
+type NcxInfraManagedClusterStatus struct {
+	// Ready mirrors the Available condition of the generated Cluster
+	// +optional
+	Ready bool `json:"ready"`
+
+	// ClusterName is the name of the generated Cluster
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the generated objects
+	// were last applied from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the current state of the NcxInfraManagedCluster
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// GetConditions returns the conditions from the status
+func (c *NcxInfraManagedCluster) GetConditions() []metav1.Condition {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the conditions in the status
+func (c *NcxInfraManagedCluster) SetConditions(conditions []metav1.Condition) {
+	c.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=ncxinframanagedclusters,scope=Namespaced,categories=cluster-api
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version",description="Kubernetes version"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready",description="Cluster is available"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time since creation"
+
+// NcxInfraManagedCluster is the Schema for the ncxinframanagedclusters API.
+// It generates, and keeps up to date, the Cluster, NcxInfraCluster,
+// KubeadmControlPlane, MachineDeployments and templates of a cluster from a
+// minimal spec, with defaults for GPU workers.
+type NcxInfraManagedCluster struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of NcxInfraManagedCluster
+	// +required
+	Spec NcxInfraManagedClusterSpec `json:"spec"`
+
+	// status defines the observed state of NcxInfraManagedCluster
+	// +optional
+	Status NcxInfraManagedClusterStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// NcxInfraManagedClusterList contains a list of NcxInfraManagedCluster
+type NcxInfraManagedClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NcxInfraManagedCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NcxInfraManagedCluster{}, &NcxInfraManagedClusterList{})
+}
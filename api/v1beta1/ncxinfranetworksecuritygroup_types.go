@@ -0,0 +1,120 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NcxInfraNetworkSecurityGroupSpec defines the desired state of NcxInfraNetworkSecurityGroup
+type NcxInfraNetworkSecurityGroupSpec struct {
+	// SiteRef references the NVIDIA Carbide Site where the NSG is created
+	// +required
+	SiteRef SiteReference `json:"siteRef"`
+
+	// Name of the Network Security Group in NVIDIA Carbide.
+	// Defaults to the name of the object.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Description of the Network Security Group
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Rules for the Network Security Group
+	// +optional
+	Rules []NSGRule `json:"rules,omitempty"`
+
+	// Authentication contains credentials for accessing the NVIDIA Carbide API.
+	// Defaults to the credentials of the namespace or controller, see secretRef.
+	// +optional
+	Authentication AuthenticationSpec `json:"authentication,omitzero"`
+}
+
+// NcxInfraNetworkSecurityGroupStatus defines the observed state of NcxInfraNetworkSecurityGroup
+type NcxInfraNetworkSecurityGroupStatus struct {
+	// Ready indicates if the Network Security Group exists in NVIDIA Carbide
+	// +optional
+	Ready bool `json:"ready"`
+
+	// NSGID is the NVIDIA Carbide Network Security Group ID
+	// +optional
+	NSGID string `json:"nsgID,omitempty"`
+
+	// Origin records the creation of the Network Security Group
+	// +optional
+	Origin *ResourceOrigin `json:"origin,omitempty"`
+
+	// Conditions represent the current state of the NcxInfraNetworkSecurityGroup
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// GetConditions returns the conditions from the status
+func (r *NcxInfraNetworkSecurityGroup) GetConditions() []metav1.Condition {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the conditions in the status
+func (r *NcxInfraNetworkSecurityGroup) SetConditions(conditions []metav1.Condition) {
+	r.Status.Conditions = conditions
+}
+
+// NSGName returns the name of the Network Security Group in NVIDIA Carbide
+func (r *NcxInfraNetworkSecurityGroup) NSGName() string {
+	if r.Spec.Name != "" {
+		return r.Spec.Name
+	}
+	return r.Name
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=ncxinfranetworksecuritygroups,scope=Namespaced,categories=cluster-api
+
+// NcxInfraNetworkSecurityGroup is the Schema for the ncxinfranetworksecuritygroups API.
+// It manages a Network Security Group independently of any cluster, so that
+// several NcxInfraClusters can share it through spec.vpc.networkSecurityGroupRef.
+type NcxInfraNetworkSecurityGroup struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of NcxInfraNetworkSecurityGroup
+	// +required
+	Spec NcxInfraNetworkSecurityGroupSpec `json:"spec"`
+
+	// status defines the observed state of NcxInfraNetworkSecurityGroup
+	// +optional
+	Status NcxInfraNetworkSecurityGroupStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// NcxInfraNetworkSecurityGroupList contains a list of NcxInfraNetworkSecurityGroup
+type NcxInfraNetworkSecurityGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NcxInfraNetworkSecurityGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NcxInfraNetworkSecurityGroup{}, &NcxInfraNetworkSecurityGroupList{})
+}
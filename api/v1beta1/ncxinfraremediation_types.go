@@ -0,0 +1,135 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemediationStrategy defines how an unhealthy machine is remediated in place.
+// +kubebuilder:validation:Enum=Reboot;Reimage
+type RemediationStrategy string
+
+const (
+	// RemediationStrategyReboot power cycles the instance.
+	RemediationStrategyReboot RemediationStrategy = "Reboot"
+
+	// RemediationStrategyReimage reboots the instance with its custom iPXE
+	// script and the machine bootstrap data, reinstalling the operating system
+	// on the same physical machine.
+	RemediationStrategyReimage RemediationStrategy = "Reimage"
+)
+
+// Remediation phases
+const (
+	// RemediationPhaseRunning means a remediation attempt was submitted and the
+	// instance has not come back Ready yet.
+	RemediationPhaseRunning = "Running"
+
+	// RemediationPhaseWaiting means the instance is Ready again and the
+	// remediation waits for the MachineHealthCheck to see the node healthy.
+	RemediationPhaseWaiting = "Waiting"
+
+	// RemediationPhaseFailed means all remediation attempts were exhausted.
+	RemediationPhaseFailed = "Failed"
+)
+
+// NcxInfraRemediationSpec defines the desired state of NcxInfraRemediation
+type NcxInfraRemediationSpec struct {
+	// Strategy selects how the machine is remediated
+	// +kubebuilder:default=Reimage
+	// +optional
+	Strategy RemediationStrategy `json:"strategy,omitempty"`
+
+	// RetryLimit is the maximum number of remediation attempts
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	RetryLimit int32 `json:"retryLimit,omitempty"`
+
+	// Timeout is how long an attempt may take before it is retried or the
+	// remediation is marked as failed
+	// +kubebuilder:default="30m"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// NcxInfraRemediationStatus defines the observed state of NcxInfraRemediation
+type NcxInfraRemediationStatus struct {
+	// Phase of the remediation
+	// Possible values: Running, Waiting, Failed
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// RetryCount is the number of remediation attempts submitted so far
+	// +optional
+	RetryCount int32 `json:"retryCount,omitempty"`
+
+	// LastRemediated is when the last remediation attempt was submitted
+	// +optional
+	LastRemediated *metav1.Time `json:"lastRemediated,omitempty"`
+
+	// Conditions represent the current state of the NcxInfraRemediation
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// GetConditions returns the conditions from the status
+func (r *NcxInfraRemediation) GetConditions() []metav1.Condition {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the conditions in the status
+func (r *NcxInfraRemediation) SetConditions(conditions []metav1.Condition) {
+	r.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=ncxinfraremediations,scope=Namespaced,categories=cluster-api
+
+// NcxInfraRemediation is the Schema for the ncxinfraremediations API.
+// It is created by a MachineHealthCheck from a NcxInfraRemediationTemplate,
+// with the name of the unhealthy Machine.
+type NcxInfraRemediation struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of NcxInfraRemediation
+	// +required
+	Spec NcxInfraRemediationSpec `json:"spec"`
+
+	// status defines the observed state of NcxInfraRemediation
+	// +optional
+	Status NcxInfraRemediationStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// NcxInfraRemediationList contains a list of NcxInfraRemediation
+type NcxInfraRemediationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NcxInfraRemediation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NcxInfraRemediation{}, &NcxInfraRemediationList{})
+}
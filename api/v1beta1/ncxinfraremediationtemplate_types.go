@@ -0,0 +1,67 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NcxInfraRemediationTemplateSpec defines the desired state of NcxInfraRemediationTemplate
+type NcxInfraRemediationTemplateSpec struct {
+	// Template contains the NcxInfraRemediation template specification
+	// +required
+	Template NcxInfraRemediationTemplateResource `json:"template"`
+}
+
+// NcxInfraRemediationTemplateResource describes the data needed to create a NcxInfraRemediation from a template
+type NcxInfraRemediationTemplateResource struct {
+	// Spec is the specification of the desired remediation
+	// +required
+	Spec NcxInfraRemediationSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ncxinfraremediationtemplates,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+
+// NcxInfraRemediationTemplate is the Schema for the ncxinfraremediationtemplates API.
+// Reference it from MachineHealthCheck.spec.remediation.templateRef to remediate
+// unhealthy machines in place instead of replacing them.
+type NcxInfraRemediationTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of NcxInfraRemediationTemplate
+	// +required
+	Spec NcxInfraRemediationTemplateSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// NcxInfraRemediationTemplateList contains a list of NcxInfraRemediationTemplate
+type NcxInfraRemediationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NcxInfraRemediationTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NcxInfraRemediationTemplate{}, &NcxInfraRemediationTemplateList{})
+}
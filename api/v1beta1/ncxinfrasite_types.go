@@ -0,0 +1,146 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NcxInfraSiteSpec identifies a discovered site
+type NcxInfraSiteSpec struct {
+	// ID of the site, as set in siteRef.id
+	// +required
+	ID string `json:"id"`
+
+	// Name of the site, as set in siteRef.name
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Description of the site
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Org is the organization whose credentials discovered the site
+	// +optional
+	Org string `json:"org,omitempty"`
+}
+
+// SiteLocation is the location of a site
+type SiteLocation struct {
+	// City of the site
+	// +optional
+	City string `json:"city,omitempty"`
+
+	// State or region of the site
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Country of the site
+	// +optional
+	Country string `json:"country,omitempty"`
+}
+
+// NcxInfraSiteStatus is the discovered state of a site
+type NcxInfraSiteStatus struct {
+	// Location of the site
+	// +optional
+	Location SiteLocation `json:"location,omitzero"`
+
+	// Capabilities are the features the site supports: NativeNetworking,
+	// NetworkSecurityGroup, NvLinkPartition, RackLevelAdministration,
+	// ImageBasedOperatingSystem and FaultManagement
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// State of the site in NVIDIA Carbide: Pending, Registered or Error
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Online reports whether NVIDIA Carbide currently reaches the site
+	// +optional
+	Online bool `json:"online"`
+
+	// Healthy is true when the site is Registered and online
+	// +optional
+	Healthy bool `json:"healthy"`
+
+	// Machines is the number of machines of the site
+	// +optional
+	Machines int32 `json:"machines,omitempty"`
+
+	// LastSyncTime is the time the site was last read from NVIDIA Carbide
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=ncxinfrasites,scope=Cluster,categories=cluster-api
+// +kubebuilder:printcolumn:name="Site",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="Org",type="string",JSONPath=".spec.org"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Healthy",type="boolean",JSONPath=".status.healthy"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NcxInfraSite is the Schema for the ncxinfrasites API.
+// It is read-only: the provider mirrors the sites visible to its credentials
+// into one object per site and organization, named after the site ID and the
+// organization. They resolve siteRef.name, let the webhook reject unknown
+// sites, and report the health of the sites of the clusters.
+type NcxInfraSite struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec identifies the site
+	// +required
+	Spec NcxInfraSiteSpec `json:"spec"`
+
+	// status is the discovered state of the site
+	// +optional
+	Status NcxInfraSiteStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// NcxInfraSiteList contains a list of NcxInfraSite
+type NcxInfraSiteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NcxInfraSite `json:"items"`
+}
+
+// Lookup returns the site of a site reference, by ID or else by name, or nil.
+// The sites of other organizations are skipped when org is set.
+func (l *NcxInfraSiteList) Lookup(ref SiteReference, org string) *NcxInfraSite {
+	for i := range l.Items {
+		site := &l.Items[i]
+		if org != "" && site.Spec.Org != "" && site.Spec.Org != org {
+			continue
+		}
+		if (ref.ID != "" && site.Spec.ID == ref.ID) || (ref.ID == "" && ref.Name != "" && site.Spec.Name == ref.Name) {
+			return site
+		}
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&NcxInfraSite{}, &NcxInfraSiteList{})
+}
@@ -19,12 +19,21 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -33,13 +42,315 @@ import (
 
 var _ webhook.CustomValidator = &NcxInfraCluster{}
 
-func (r *NcxInfraCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// SiteTenantField indexes the NcxInfraClusters by the tenant and the sites of
+// their subnets, see ClusterSiteTenants. The controllers add it to the manager
+// cache.
+const SiteTenantField = "spec.siteTenant"
+
+// ClusterSiteTenants returns the keys of the sites of the subnets of a cluster
+// in its tenant, "<tenant ID>/<site>", with the site by ID and by name as
+// referenced.
+func ClusterSiteTenants(cluster *NcxInfraCluster) []string {
+	refs := []SiteReference{cluster.Spec.SiteRef}
+	for _, subnet := range cluster.Spec.Subnets {
+		if subnet.SiteRef != nil {
+			refs = append(refs, *subnet.SiteRef)
+		}
+	}
+	return siteTenantKeys(cluster.Spec.TenantID, refs)
+}
+
+// siteTenantKeys returns the SiteTenantField keys of site references in a
+// tenant.
+func siteTenantKeys(tenantID string, refs []SiteReference) []string {
+	var keys []string
+	for _, ref := range refs {
+		for _, site := range []string{ref.ID, ref.Name} {
+			if key := tenantID + "/" + site; site != "" && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// SetupWebhookWithManager registers the webhook of the NcxInfraClusters. When
+// the cache of the manager is limited to the watched namespaces, namespaced is
+// true and the clusters of a tenant are listed from the API server to check
+// their CIDRs, so that the clusters of the other namespaces are checked too.
+func (r *NcxInfraCluster) SetupWebhookWithManager(mgr ctrl.Manager, namespaced bool) error {
+	validator := &clusterSiteValidator{reader: mgr.GetAPIReader(), clusters: mgr.GetClient()}
+	if namespaced {
+		validator.clusters = nil
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		WithValidator(r).
+		WithValidator(validator).
 		Complete()
 }
 
+// clusterSiteValidator validates the NcxInfraClusters like NcxInfraCluster
+// does, and also rejects the creation of a cluster whose site is missing from
+// the site inventory. Updates are not checked against the inventory, so a
+// cluster whose site went away can still be updated and deleted. A
+// credentials secret of another namespace is checked on creation and when it
+// changes. The subnets and VPC prefixes added to a cluster must not overlap
+// the ones of the other clusters of its tenant in the same site.
+type clusterSiteValidator struct {
+	reader client.Reader
+
+	// clusters is the manager cache, where the other clusters of a tenant
+	// are looked up by SiteTenantField. When nil, the cache does not hold the
+	// clusters of every namespace, and they are listed from reader instead.
+	clusters client.Reader
+}
+
+var _ webhook.CustomValidator = &clusterSiteValidator{}
+
+func (v *clusterSiteValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*NcxInfraCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected NcxInfraCluster, got %T", obj)
+	}
+	allErrs := cluster.validateCluster()
+	siteErrs, err := validateSiteInventory(ctx, v.reader, cluster.Spec.SiteRef, field.NewPath("spec", "siteRef"))
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, siteErrs...)
+	for i, subnet := range cluster.Spec.Subnets {
+		if subnet.SiteRef == nil {
+			continue
+		}
+		siteErrs, err := validateSiteInventory(ctx, v.reader, *subnet.SiteRef,
+			field.NewPath("spec", "subnets").Index(i).Child("siteRef"))
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		allErrs = append(allErrs, siteErrs...)
+	}
+	secretErrs, err := validateSecretNamespace(ctx, v.reader, cluster)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, secretErrs...)
+	overlapErrs, err := validateCIDROverlap(ctx, v.reader, v.clusters, cluster, nil)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, overlapErrs...)
+	return nil, allErrs.ToAggregate()
+}
+
+func (v *clusterSiteValidator) ValidateUpdate(
+	ctx context.Context, oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	warnings, err := (&NcxInfraCluster{}).ValidateUpdate(ctx, oldObj, newObj)
+	if err != nil {
+		return warnings, err
+	}
+	oldCluster, newCluster := oldObj.(*NcxInfraCluster), newObj.(*NcxInfraCluster)
+	var allErrs field.ErrorList
+	if oldCluster.Spec.Authentication.SecretRef != newCluster.Spec.Authentication.SecretRef {
+		secretErrs, err := validateSecretNamespace(ctx, v.reader, newCluster)
+		if err != nil {
+			return warnings, apierrors.NewInternalError(err)
+		}
+		allErrs = append(allErrs, secretErrs...)
+	}
+	overlapErrs, err := validateCIDROverlap(ctx, v.reader, v.clusters, newCluster, oldCluster)
+	if err != nil {
+		return warnings, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, overlapErrs...)
+	return warnings, allErrs.ToAggregate()
+}
+
+func (v *clusterSiteValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateSecretNamespace checks that a credentials secret of another
+// namespace allows the namespace of the cluster. A missing secret is accepted,
+// as it may be created after the cluster.
+func validateSecretNamespace(ctx context.Context, reader client.Reader, cluster *NcxInfraCluster) (field.ErrorList, error) {
+	ref := cluster.Spec.Authentication.SecretRef
+	if ref.Name == "" || ref.Namespace == "" || ref.Namespace == cluster.Namespace {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get credentials secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	if SecretAllowsNamespace(secret, cluster.Namespace) {
+		return nil, nil
+	}
+	return field.ErrorList{field.Forbidden(field.NewPath("spec", "authentication", "secretRef", "namespace"),
+		fmt.Sprintf("secret %s/%s does not allow namespace %s, list it in its %s annotation",
+			ref.Namespace, ref.Name, cluster.Namespace, AllowedNamespacesAnnotation))}, nil
+}
+
+// clusterRange is a subnet or VPC prefix of a cluster, in the site where it is
+// created.
+type clusterRange struct {
+	kind   string
+	name   string
+	site   SiteReference
+	prefix netip.Prefix
+	path   *field.Path
+}
+
+// clusterRanges returns the subnets and VPC prefixes of a cluster with a valid
+// CIDR. The VPC prefixes are created in the site of the cluster.
+func clusterRanges(cluster *NcxInfraCluster) []clusterRange {
+	var ranges []clusterRange
+	specPath := field.NewPath("spec")
+	for i, subnet := range cluster.Spec.Subnets {
+		prefix, err := netip.ParsePrefix(subnet.CIDR)
+		if err != nil {
+			continue
+		}
+		site := cluster.Spec.SiteRef
+		if subnet.SiteRef != nil {
+			site = *subnet.SiteRef
+		}
+		ranges = append(ranges, clusterRange{kind: "subnet", name: subnet.Name, site: site,
+			prefix: prefix.Masked(), path: specPath.Child("subnets").Index(i).Child("cidr")})
+	}
+	for i, vpcPrefix := range cluster.Spec.VPCPrefixes {
+		prefix, err := netip.ParsePrefix(vpcPrefix.CIDR)
+		if err != nil {
+			continue
+		}
+		ranges = append(ranges, clusterRange{kind: "VPC prefix", name: vpcPrefix.Name, site: cluster.Spec.SiteRef,
+			prefix: prefix.Masked(), path: specPath.Child("vpcPrefixes").Index(i).Child("cidr")})
+	}
+	return ranges
+}
+
+// sameSite reports whether two site references denote the same site, by ID,
+// by name, or through the site inventory when one references the site by ID
+// and the other by name.
+func sameSite(a, b SiteReference, sites *NcxInfraSiteList) bool {
+	if (a.ID != "" && a.ID == b.ID) || (a.Name != "" && a.Name == b.Name) {
+		return true
+	}
+	siteA, siteB := sites.Lookup(a, ""), sites.Lookup(b, "")
+	return siteA != nil && siteB != nil && siteA.Spec.ID == siteB.Spec.ID
+}
+
+// validateCIDROverlap checks that the subnets and VPC prefixes of a cluster do
+// not overlap the ones of the other clusters of its tenant, in any namespace,
+// created in the same site, looked up in clusters, or listed from reader when
+// clusters is nil: the site routes them, and the machines of both
+// clusters would silently lose the traffic meant for one another. On update,
+// only the ranges added since oldCluster are checked, so that clusters
+// overlapping before the check was introduced can still be updated. A
+// cluster being deleted still holds its ranges.
+func validateCIDROverlap(
+	ctx context.Context, reader, clusters client.Reader, cluster, oldCluster *NcxInfraCluster,
+) (field.ErrorList, error) {
+	ranges := clusterRanges(cluster)
+	if oldCluster != nil {
+		oldRanges := clusterRanges(oldCluster)
+		ranges = slices.DeleteFunc(ranges, func(r clusterRange) bool {
+			return slices.ContainsFunc(oldRanges, func(old clusterRange) bool {
+				return old.prefix == r.prefix && old.site == r.site
+			})
+		})
+	}
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+
+	sites := &NcxInfraSiteList{}
+	if err := reader.List(ctx, sites); err != nil {
+		return nil, fmt.Errorf("failed to list the NcxInfraSites: %w", err)
+	}
+	// The other clusters may reference the sites by ID or by name
+	var refs []SiteReference
+	for _, r := range ranges {
+		refs = append(refs, r.site)
+		if site := sites.Lookup(r.site, ""); site != nil {
+			refs = append(refs, SiteReference{ID: site.Spec.ID, Name: site.Spec.Name})
+		}
+	}
+	others := map[client.ObjectKey]*NcxInfraCluster{}
+	addOther := func(other *NcxInfraCluster) {
+		if other.Namespace != cluster.Namespace || other.Name != cluster.Name {
+			others[client.ObjectKeyFromObject(other)] = other
+		}
+	}
+	keys := siteTenantKeys(cluster.Spec.TenantID, refs)
+	if clusters == nil {
+		// The API server does not serve the SiteTenantField index
+		list := &NcxInfraClusterList{}
+		if err := reader.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("failed to list the NcxInfraClusters: %w", err)
+		}
+		for i := range list.Items {
+			other := &list.Items[i]
+			if slices.ContainsFunc(ClusterSiteTenants(other), func(key string) bool { return slices.Contains(keys, key) }) {
+				addOther(other)
+			}
+		}
+	} else {
+		for _, key := range keys {
+			list := &NcxInfraClusterList{}
+			if err := clusters.List(ctx, list, client.MatchingFields{SiteTenantField: key}); err != nil {
+				return nil, fmt.Errorf("failed to list the NcxInfraClusters of site %s: %w", key, err)
+			}
+			for i := range list.Items {
+				addOther(&list.Items[i])
+			}
+		}
+	}
+
+	var allErrs field.ErrorList
+	otherKeys := slices.SortedFunc(maps.Keys(others), func(a, b client.ObjectKey) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, r := range ranges {
+	others:
+		for _, key := range otherKeys {
+			for _, other := range clusterRanges(others[key]) {
+				if other.prefix.Overlaps(r.prefix) && sameSite(r.site, other.site, sites) {
+					allErrs = append(allErrs, field.Invalid(r.path, r.prefix.String(),
+						fmt.Sprintf("overlaps %s %s (%s) of NcxInfraCluster %s in the same site and tenant, "+
+							"give the clusters of a site distinct CIDRs", other.kind, other.name, other.prefix, key)))
+					break others
+				}
+			}
+		}
+	}
+	return allErrs, nil
+}
+
+// validateSiteInventory checks that a site reference matches a NcxInfraSite.
+// An empty inventory, before the first site synchronization or without any
+// credentials to discover sites, accepts all the sites.
+func validateSiteInventory(
+	ctx context.Context, reader client.Reader, ref SiteReference, siteRefPath *field.Path,
+) (field.ErrorList, error) {
+	if ref.ID == "" && ref.Name == "" {
+		return nil, nil
+	}
+	sites := &NcxInfraSiteList{}
+	if err := reader.List(ctx, sites); err != nil {
+		return nil, fmt.Errorf("failed to list the NcxInfraSites: %w", err)
+	}
+	if len(sites.Items) == 0 || sites.Lookup(ref, "") != nil {
+		return nil, nil
+	}
+	if ref.ID != "" {
+		return field.ErrorList{field.NotFound(siteRefPath.Child("id"), ref.ID)}, nil
+	}
+	return field.ErrorList{field.NotFound(siteRefPath.Child("name"), ref.Name)}, nil
+}
+
 func (r *NcxInfraCluster) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
 	cluster, ok := obj.(*NcxInfraCluster)
 	if !ok {
@@ -92,6 +403,23 @@ func (r *NcxInfraCluster) validateCluster() field.ErrorList {
 			nvt, []string{"ETHERNET_VIRTUALIZER", "FNN"}))
 	}
 
+	// Validate the NSG is either inline or referenced
+	if r.Spec.VPC.NetworkSecurityGroup != nil && r.Spec.VPC.NetworkSecurityGroupRef != nil {
+		allErrs = append(allErrs, field.Forbidden(
+			specPath.Child("vpc", "networkSecurityGroupRef"),
+			"cannot be set together with networkSecurityGroup"))
+	}
+	if ref := r.Spec.VPC.NetworkSecurityGroupRef; ref != nil && ref.Name == "" {
+		allErrs = append(allErrs, field.Required(
+			specPath.Child("vpc", "networkSecurityGroupRef", "name"),
+			"NSG reference name must not be empty"))
+	}
+
+	if nsg := r.Spec.VPC.NetworkSecurityGroup; nsg != nil {
+		allErrs = append(allErrs, validateNSGRules(nsg.Rules,
+			specPath.Child("vpc", "networkSecurityGroup", "rules"))...)
+	}
+
 	// Validate site reference: at least one of name or ID must be set
 	if r.Spec.SiteRef.Name == "" && r.Spec.SiteRef.ID == "" {
 		allErrs = append(allErrs, field.Required(
@@ -113,6 +441,7 @@ func (r *NcxInfraCluster) validateCluster() field.ErrorList {
 			"at least one subnet must be specified"))
 	}
 
+	subnetNames := map[string]bool{}
 	for i, subnet := range r.Spec.Subnets {
 		subnetPath := specPath.Child("subnets").Index(i)
 
@@ -120,7 +449,10 @@ func (r *NcxInfraCluster) validateCluster() field.ErrorList {
 			allErrs = append(allErrs, field.Required(
 				subnetPath.Child("name"),
 				"subnet name must not be empty"))
+		} else if subnetNames[subnet.Name] {
+			allErrs = append(allErrs, field.Duplicate(subnetPath.Child("name"), subnet.Name))
 		}
+		subnetNames[subnet.Name] = true
 
 		// Validate CIDR format
 		if subnet.CIDR != "" {
@@ -135,9 +467,31 @@ func (r *NcxInfraCluster) validateCluster() field.ErrorList {
 				subnetPath.Child("cidr"),
 				"CIDR must not be empty"))
 		}
+
+		if subnet.SiteRef != nil && subnet.SiteRef.Name == "" && subnet.SiteRef.ID == "" {
+			allErrs = append(allErrs, field.Required(
+				subnetPath.Child("siteRef"),
+				"at least one of name or id must be specified"))
+		}
+
+		// Validate DNS server addresses
+		if subnet.DHCPOptions != nil {
+			allErrs = append(allErrs, validateDNSServers(subnet.DHCPOptions.DNSServers,
+				subnetPath.Child("dhcpOptions", "dnsServers"))...)
+		}
+	}
+
+	if r.Spec.Network != nil {
+		allErrs = append(allErrs, validateDNSServers(r.Spec.Network.DNSServers,
+			specPath.Child("network", "dnsServers"))...)
+	}
+
+	if r.Spec.Proxy != nil {
+		allErrs = append(allErrs, validateProxy(*r.Spec.Proxy, specPath.Child("proxy"))...)
 	}
 
 	// Validate VPC Prefixes
+	prefixNames := map[string]bool{}
 	for i, prefix := range r.Spec.VPCPrefixes {
 		prefixPath := specPath.Child("vpcPrefixes").Index(i)
 
@@ -145,7 +499,10 @@ func (r *NcxInfraCluster) validateCluster() field.ErrorList {
 			allErrs = append(allErrs, field.Required(
 				prefixPath.Child("name"),
 				"VPC prefix name must not be empty"))
+		} else if prefixNames[prefix.Name] {
+			allErrs = append(allErrs, field.Duplicate(prefixPath.Child("name"), prefix.Name))
 		}
+		prefixNames[prefix.Name] = true
 
 		if prefix.CIDR != "" {
 			if _, _, err := net.ParseCIDR(prefix.CIDR); err != nil {
@@ -161,19 +518,110 @@ func (r *NcxInfraCluster) validateCluster() field.ErrorList {
 		}
 	}
 
-	// Validate authentication
-	if r.Spec.Authentication.SecretRef.Name == "" {
+	// An externally managed endpoint must be set by the user
+	if r.Spec.ControlPlaneEndpointManagement == ControlPlaneEndpointExternal &&
+		(r.Spec.ControlPlaneEndpoint == nil || r.Spec.ControlPlaneEndpoint.Host == "") {
 		allErrs = append(allErrs, field.Required(
-			specPath.Child("authentication", "secretRef", "name"),
-			"credentials secret name must not be empty"))
+			specPath.Child("controlPlaneEndpoint", "host"),
+			"must be set when controlPlaneEndpointManagement is External"))
 	}
 
+	// Validate authentication, the secret name can be omitted to use the default credentials
+	allErrs = append(allErrs, validateAuthentication(r.Spec.Authentication, specPath.Child("authentication"))...)
+
 	if len(allErrs) > 0 {
 		return allErrs
 	}
 	return nil
 }
 
+// validateAuthentication checks that the credentials come from either a
+// secret or an identity.
+func validateAuthentication(auth AuthenticationSpec, authPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if ref := auth.SecretRef; ref.Name == "" && ref.Namespace != "" {
+		allErrs = append(allErrs, field.Required(
+			authPath.Child("secretRef", "name"),
+			"credentials secret name must not be empty when its namespace is set"))
+	}
+	if auth.IdentityRef != nil {
+		if auth.SecretRef.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(
+				authPath.Child("identityRef"), "cannot be set together with secretRef"))
+		}
+		if auth.IdentityRef.Name == "" {
+			allErrs = append(allErrs, field.Required(
+				authPath.Child("identityRef", "name"), "identity name must not be empty"))
+		}
+	}
+	return allErrs
+}
+
+// validateDNSServers checks that the DNS servers are IP addresses.
+func validateDNSServers(servers []string, serversPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, server := range servers {
+		if net.ParseIP(server) == nil {
+			allErrs = append(allErrs, field.Invalid(serversPath.Index(i), server, "must be an IP address"))
+		}
+	}
+	return allErrs
+}
+
+// validateProxy checks that a proxy is set, and that the proxies are HTTP or
+// HTTPS URLs.
+func validateProxy(proxy ProxySpec, proxyPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if proxy.HTTPProxy == "" && proxy.HTTPSProxy == "" {
+		allErrs = append(allErrs, field.Required(proxyPath, "at least one of httpProxy or httpsProxy must be set"))
+	}
+	for _, p := range []struct{ name, url string }{
+		{"httpProxy", proxy.HTTPProxy}, {"httpsProxy", proxy.HTTPSProxy},
+	} {
+		if p.url == "" {
+			continue
+		}
+		if u, err := url.Parse(p.url); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(proxyPath.Child(p.name), p.url, "must be an http or https URL"))
+		}
+	}
+	return allErrs
+}
+
+// validateNSGRules checks the prefixes of the rules, and that ports are only
+// set for the protocols that have them.
+func validateNSGRules(rules []NSGRule, rulesPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, rule := range rules {
+		rulePath := rulesPath.Index(i)
+
+		if rule.SourceCIDR != "" {
+			if _, _, err := net.ParseCIDR(rule.SourceCIDR); err != nil {
+				allErrs = append(allErrs, field.Invalid(
+					rulePath.Child("sourceCIDR"), rule.SourceCIDR, fmt.Sprintf("invalid CIDR: %v", err)))
+			}
+		}
+		if rule.DestinationCIDR != "" {
+			if _, _, err := net.ParseCIDR(rule.DestinationCIDR); err != nil {
+				allErrs = append(allErrs, field.Invalid(
+					rulePath.Child("destinationCIDR"), rule.DestinationCIDR, fmt.Sprintf("invalid CIDR: %v", err)))
+			}
+		}
+
+		if rule.Protocol != "tcp" && rule.Protocol != "udp" {
+			if rule.PortRange != "" {
+				allErrs = append(allErrs, field.Forbidden(
+					rulePath.Child("portRange"), "ports can only be set for tcp and udp rules"))
+			}
+			if rule.SourcePortRange != "" {
+				allErrs = append(allErrs, field.Forbidden(
+					rulePath.Child("sourcePortRange"), "ports can only be set for tcp and udp rules"))
+			}
+		}
+	}
+	return allErrs
+}
+
 func (r *NcxInfraCluster) validateImmutableFields(old *NcxInfraCluster) field.ErrorList {
 	var allErrs field.ErrorList
 	specPath := field.NewPath("spec")
@@ -197,6 +645,13 @@ func (r *NcxInfraCluster) validateImmutableFields(old *NcxInfraCluster) field.Er
 			"field is immutable after creation"))
 	}
 
+	// The cluster resources cannot move to another organization
+	if old.Spec.Organization != r.Spec.Organization {
+		allErrs = append(allErrs, field.Forbidden(
+			specPath.Child("organization"),
+			"field is immutable after creation"))
+	}
+
 	// VPC name is immutable
 	if old.Spec.VPC.Name != r.Spec.VPC.Name {
 		allErrs = append(allErrs, field.Forbidden(
@@ -204,6 +659,36 @@ func (r *NcxInfraCluster) validateImmutableFields(old *NcxInfraCluster) field.Er
 			"field is immutable after creation"))
 	}
 
+	// The cluster cannot move its subnets to another VPC
+	if old.Spec.VPC.ID != r.Spec.VPC.ID {
+		allErrs = append(allErrs, field.Forbidden(
+			specPath.Child("vpc", "id"),
+			"field is immutable after creation"))
+	}
+
+	// The certificates and kubeconfigs of the workload cluster are issued for
+	// the control plane endpoint, it cannot change once its host is set
+	if oldEndpoint := ptr.Deref(old.Spec.ControlPlaneEndpoint, clusterv1.APIEndpoint{}); oldEndpoint.Host != "" &&
+		oldEndpoint != ptr.Deref(r.Spec.ControlPlaneEndpoint, clusterv1.APIEndpoint{}) {
+		allErrs = append(allErrs, field.Forbidden(
+			specPath.Child("controlPlaneEndpoint"),
+			"field is immutable once its host is set"))
+	}
+
+	// Nor a subnet to another site
+	oldSubnetSites := map[string]SiteReference{}
+	for _, subnet := range old.Spec.Subnets {
+		oldSubnetSites[subnet.Name] = ptr.Deref(subnet.SiteRef, SiteReference{})
+	}
+	for i, subnet := range r.Spec.Subnets {
+		oldSite, exists := oldSubnetSites[subnet.Name]
+		if exists && oldSite != ptr.Deref(subnet.SiteRef, SiteReference{}) {
+			allErrs = append(allErrs, field.Forbidden(
+				specPath.Child("subnets").Index(i).Child("siteRef"),
+				"field is immutable after creation"))
+		}
+	}
+
 	if len(allErrs) > 0 {
 		return allErrs
 	}
@@ -18,10 +18,16 @@ package v1beta1
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func validCluster() *NcxInfraCluster {
@@ -79,6 +85,201 @@ func TestClusterWebhook_InvalidCIDR(t *testing.T) {
 	}
 }
 
+func TestClusterWebhook_InvalidDNSServer(t *testing.T) {
+	c := validCluster()
+	c.Spec.Subnets[0].DHCPOptions = &DHCPOptions{
+		DNSServers: []string{"10.0.0.53", "dns.example.com"},
+		NTPServers: []string{"ntp.example.com"},
+	}
+	_, err := c.ValidateCreate(context.Background(), c)
+	if err == nil {
+		t.Error("expected error for a DNS server that is not an IP address")
+	}
+
+	c.Spec.Subnets[0].DHCPOptions.DNSServers = []string{"10.0.0.53", "fd00::53"}
+	if _, err := c.ValidateCreate(context.Background(), c); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestClusterWebhook_NetworkDNSServers(t *testing.T) {
+	c := validCluster()
+	c.Spec.Network = &NetworkServices{DNSServers: []string{"dns.example.com"}}
+	if _, err := c.ValidateCreate(context.Background(), c); err == nil {
+		t.Error("expected error for a DNS server that is not an IP address")
+	}
+
+	c.Spec.Network.DNSServers = []string{"10.0.0.53"}
+	if _, err := c.ValidateCreate(context.Background(), c); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestClusterWebhook_Proxy(t *testing.T) {
+	c := validCluster()
+	c.Spec.Proxy = &ProxySpec{NoProxy: []string{".svc"}}
+	_, err := c.ValidateCreate(context.Background(), c)
+	if err == nil || !strings.Contains(err.Error(), "spec.proxy: Required value") {
+		t.Errorf("expected error for a proxy without URL, got %v", err)
+	}
+
+	c.Spec.Proxy.HTTPProxy = "proxy.example.com:3128"
+	_, err = c.ValidateCreate(context.Background(), c)
+	if err == nil || !strings.Contains(err.Error(), "spec.proxy.httpProxy: Invalid value") {
+		t.Errorf("expected error for a proxy without scheme, got %v", err)
+	}
+
+	c.Spec.Proxy.HTTPProxy = "http://proxy.example.com:3128"
+	if _, err := c.ValidateCreate(context.Background(), c); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestClusterWebhook_NSGInlineAndRef(t *testing.T) {
+	c := validCluster()
+	c.Spec.VPC.NetworkSecurityGroupRef = &corev1.LocalObjectReference{Name: "shared-nsg"}
+	if _, err := c.ValidateCreate(context.Background(), c); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	c.Spec.VPC.NetworkSecurityGroup = &NSGSpec{Name: "inline-nsg"}
+	_, err := c.ValidateCreate(context.Background(), c)
+	if err == nil {
+		t.Error("expected error when both networkSecurityGroup and networkSecurityGroupRef are set")
+	}
+}
+
+func TestClusterWebhook_NSGRules(t *testing.T) {
+	c := validCluster()
+	c.Spec.VPC.NetworkSecurityGroup = &NSGSpec{
+		Name: "egress",
+		Rules: []NSGRule{{
+			Name:            "allow-registry",
+			Direction:       "egress",
+			Protocol:        "tcp",
+			PortRange:       "443",
+			SourcePortRange: "1024-65535",
+			DestinationCIDR: "192.168.10.0/24",
+			Priority:        ptr.To[int32](100),
+			Action:          "allow",
+		}},
+	}
+	if _, err := c.ValidateCreate(context.Background(), c); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	c.Spec.VPC.NetworkSecurityGroup.Rules[0].DestinationCIDR = "192.168.10.0"
+	if _, err := c.ValidateCreate(context.Background(), c); err == nil {
+		t.Error("expected error for an invalid destination CIDR")
+	}
+
+	c.Spec.VPC.NetworkSecurityGroup.Rules[0].DestinationCIDR = ""
+	c.Spec.VPC.NetworkSecurityGroup.Rules[0].Protocol = "icmp"
+	if _, err := c.ValidateCreate(context.Background(), c); err == nil {
+		t.Error("expected error for ports on an icmp rule")
+	}
+}
+
+func TestClusterWebhook_ExternalControlPlaneEndpoint(t *testing.T) {
+	c := validCluster()
+	c.Spec.ControlPlaneEndpointManagement = ControlPlaneEndpointExternal
+	if _, err := c.ValidateCreate(context.Background(), c); err == nil {
+		t.Error("expected error for an External control plane endpoint without host")
+	}
+
+	c.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{Host: "api.cluster.example.com", Port: 6443}
+	if _, err := c.ValidateCreate(context.Background(), c); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestClusterWebhook_ControlPlaneEndpointImmutable(t *testing.T) {
+	old := validCluster()
+	set := old.DeepCopy()
+	set.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{Host: "10.0.1.10", Port: 6443}
+	if _, err := old.ValidateUpdate(context.Background(), old, set); err != nil {
+		t.Errorf("expected no error when setting the endpoint, got %v", err)
+	}
+
+	changed := set.DeepCopy()
+	changed.Spec.ControlPlaneEndpoint.Host = "10.0.1.11"
+	if _, err := set.ValidateUpdate(context.Background(), set, changed); err == nil {
+		t.Error("expected error when changing the endpoint")
+	}
+
+	cleared := set.DeepCopy()
+	cleared.Spec.ControlPlaneEndpoint = nil
+	if _, err := set.ValidateUpdate(context.Background(), set, cleared); err == nil {
+		t.Error("expected error when clearing the endpoint")
+	}
+}
+
+func TestClusterWebhook_DefaultCredentials(t *testing.T) {
+	c := validCluster()
+	c.Spec.Authentication.SecretRef = corev1.SecretReference{}
+	if _, err := c.ValidateCreate(context.Background(), c); err != nil {
+		t.Errorf("expected no error when inheriting the credentials, got %v", err)
+	}
+
+	c.Spec.Authentication.SecretRef.Namespace = "platform"
+	if _, err := c.ValidateCreate(context.Background(), c); err == nil {
+		t.Error("expected error for a secret namespace without name")
+	}
+}
+
+func TestClusterWebhook_IdentityRef(t *testing.T) {
+	c := validCluster()
+	c.Spec.Authentication.IdentityRef = &corev1.LocalObjectReference{Name: "shared"}
+	if _, err := c.ValidateCreate(context.Background(), c); err == nil {
+		t.Error("expected error when both secretRef and identityRef are set")
+	}
+
+	c.Spec.Authentication.SecretRef = corev1.SecretReference{}
+	if _, err := c.ValidateCreate(context.Background(), c); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestClusterIdentity_AllowsNamespace(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed *AllowedNamespaces
+		want    bool
+	}{
+		{name: "nil allows no namespace", allowed: nil, want: false},
+		{name: "empty allows all namespaces", allowed: &AllowedNamespaces{}, want: true},
+		{name: "listed namespace", allowed: &AllowedNamespaces{NamesList: []string{"team-a"}}, want: true},
+		{name: "unlisted namespace", allowed: &AllowedNamespaces{NamesList: []string{"team-b"}}, want: false},
+		{
+			name: "selected namespace",
+			allowed: &AllowedNamespaces{
+				NamesList: []string{"team-b"},
+				Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "research"}},
+			},
+			want: true,
+		},
+		{
+			name: "unselected namespace",
+			allowed: &AllowedNamespaces{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "finance"}},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := NcxInfraClusterIdentitySpec{AllowedNamespaces: tt.allowed}
+			got, err := spec.AllowsNamespace("team-a", map[string]string{"tenant": "research"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestClusterWebhook_EmptySiteRef(t *testing.T) {
 	c := validCluster()
 	c.Spec.SiteRef = SiteReference{}
@@ -88,6 +289,223 @@ func TestClusterWebhook_EmptySiteRef(t *testing.T) {
 	}
 }
 
+func TestClusterWebhook_SiteInventory(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	// An empty inventory accepts all the sites
+	v := &clusterSiteValidator{reader: fake.NewClientBuilder().WithScheme(scheme).Build(), clusters: clusterCache(scheme)}
+	if _, err := v.ValidateCreate(context.Background(), validCluster()); err != nil {
+		t.Errorf("expected no error without site inventory, got %v", err)
+	}
+
+	site := &NcxInfraSite{
+		ObjectMeta: metav1.ObjectMeta{Name: "site-uuid"},
+		Spec:       NcxInfraSiteSpec{ID: "site-uuid", Name: "us-west-1"},
+	}
+	v = &clusterSiteValidator{
+		reader:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(site).Build(),
+		clusters: clusterCache(scheme),
+	}
+	if _, err := v.ValidateCreate(context.Background(), validCluster()); err != nil {
+		t.Errorf("expected no error for a known site ID, got %v", err)
+	}
+	c := validCluster()
+	c.Spec.SiteRef = SiteReference{Name: "us-west-1"}
+	if _, err := v.ValidateCreate(context.Background(), c); err != nil {
+		t.Errorf("expected no error for a known site name, got %v", err)
+	}
+
+	c.Spec.SiteRef = SiteReference{Name: "us-east-1"}
+	_, err := v.ValidateCreate(context.Background(), c)
+	if err == nil || !strings.Contains(err.Error(), "spec.siteRef.name") {
+		t.Errorf("expected spec.siteRef.name error for an unknown site, got %v", err)
+	}
+
+	// A cluster whose site went away can still be updated
+	if _, err := v.ValidateUpdate(context.Background(), c, c); err != nil {
+		t.Errorf("expected no error on update, got %v", err)
+	}
+}
+
+func TestClusterWebhook_CrossNamespaceSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:        "creds",
+		Namespace:   "platform",
+		Annotations: map[string]string{AllowedNamespacesAnnotation: "team-a, team-b"},
+	}}
+	v := &clusterSiteValidator{
+		reader:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+		clusters: clusterCache(scheme),
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		secretRef corev1.SecretReference
+		wantErr   bool
+	}{
+		{name: "same namespace", namespace: "default", secretRef: corev1.SecretReference{Name: "creds"}},
+		{name: "allowed namespace", namespace: "team-b", secretRef: corev1.SecretReference{Namespace: "platform", Name: "creds"}},
+		{name: "other namespace", namespace: "team-c",
+			secretRef: corev1.SecretReference{Namespace: "platform", Name: "creds"}, wantErr: true},
+		{name: "missing secret", namespace: "team-c", secretRef: corev1.SecretReference{Namespace: "platform", Name: "later"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validCluster()
+			c.Namespace = tt.namespace
+			c.Spec.Authentication.SecretRef = tt.secretRef
+			_, err := v.ValidateCreate(context.Background(), c)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "spec.authentication.secretRef.namespace") {
+				t.Errorf("expected spec.authentication.secretRef.namespace error, got %v", err)
+			}
+		})
+	}
+
+	// Updates are only checked when the secret changes
+	old := validCluster()
+	old.Namespace = "team-c"
+	old.Spec.Authentication.SecretRef = corev1.SecretReference{Namespace: "platform", Name: "creds"}
+	updated := old.DeepCopy()
+	updated.Labels = map[string]string{"team": "c"}
+	if _, err := v.ValidateUpdate(context.Background(), old, updated); err != nil {
+		t.Errorf("expected no error when the secret is unchanged, got %v", err)
+	}
+	old.Spec.Authentication.SecretRef = corev1.SecretReference{Name: "creds"}
+	if _, err := v.ValidateUpdate(context.Background(), old, updated); err == nil {
+		t.Error("expected an error when switching to a secret that does not allow the namespace")
+	}
+}
+
+// clusterCache returns a fake manager cache of clusters, indexed by
+// SiteTenantField.
+func clusterCache(scheme *runtime.Scheme, clusters ...client.Object) client.Reader {
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusters...).
+		WithIndex(&NcxInfraCluster{}, SiteTenantField, func(obj client.Object) []string {
+			return ClusterSiteTenants(obj.(*NcxInfraCluster))
+		}).Build()
+}
+
+func TestClusterWebhook_CIDROverlap(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	site := &NcxInfraSite{
+		ObjectMeta: metav1.ObjectMeta{Name: "site-uuid"},
+		Spec:       NcxInfraSiteSpec{ID: "site-uuid", Name: "us-west-1"},
+	}
+	otherSite := &NcxInfraSite{ObjectMeta: metav1.ObjectMeta{Name: "other-site"}, Spec: NcxInfraSiteSpec{ID: "other-site"}}
+	thirdSite := &NcxInfraSite{ObjectMeta: metav1.ObjectMeta{Name: "third-site"}, Spec: NcxInfraSiteSpec{ID: "third-site"}}
+	existing := validCluster()
+	existing.Name, existing.Namespace = "team-a", "team-a"
+	existing.Spec.SiteRef = SiteReference{Name: "us-west-1"}
+	existing.Spec.Subnets = []SubnetSpec{
+		{Name: "workers", CIDR: "10.0.0.0/22"},
+		{Name: "remote", CIDR: "10.20.1.0/24", SiteRef: &SiteReference{ID: "other-site"}},
+	}
+	existing.Spec.VPCPrefixes = []VPCPrefixSpec{{Name: "storage", CIDR: "10.50.0.0/24"}}
+	v := &clusterSiteValidator{
+		reader:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(site, otherSite, thirdSite).Build(),
+		clusters: clusterCache(scheme, existing),
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(c *NcxInfraCluster)
+		wantErr string
+	}{
+		{name: "distinct CIDRs", mutate: func(c *NcxInfraCluster) { c.Spec.Subnets[0].CIDR = "10.0.4.0/24" }},
+		{name: "subnet of the site referenced by name", mutate: func(c *NcxInfraCluster) {
+			c.Spec.SiteRef = SiteReference{Name: "us-west-1"}
+		}, wantErr: "spec.subnets[0].cidr: Invalid value: \"10.0.1.0/24\": overlaps subnet workers (10.0.0.0/22) of NcxInfraCluster team-a/team-a"},
+		{name: "subnet of the site referenced by ID", wantErr: "overlaps subnet workers"},
+		{name: "VPC prefix", mutate: func(c *NcxInfraCluster) {
+			c.Spec.Subnets[0].CIDR = "10.0.4.0/24"
+			c.Spec.VPCPrefixes = []VPCPrefixSpec{{Name: "fast", CIDR: "10.50.0.128/25"}}
+		}, wantErr: "spec.vpcPrefixes[0].cidr: Invalid value: \"10.50.0.128/25\": overlaps VPC prefix storage"},
+		{name: "subnet in the other site", mutate: func(c *NcxInfraCluster) {
+			c.Spec.Subnets[0].CIDR = "10.0.4.0/24"
+			c.Spec.Subnets = append(c.Spec.Subnets,
+				SubnetSpec{Name: "remote", CIDR: "10.20.1.0/24", SiteRef: &SiteReference{ID: "other-site"}})
+		}, wantErr: "spec.subnets[1].cidr"},
+		{name: "other tenant", mutate: func(c *NcxInfraCluster) { c.Spec.TenantID = "other-tenant" }},
+		{name: "other site", mutate: func(c *NcxInfraCluster) { c.Spec.SiteRef = SiteReference{ID: "third-site"} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validCluster()
+			if tt.mutate != nil {
+				tt.mutate(c)
+			}
+			_, err := v.ValidateCreate(context.Background(), c)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// The cluster itself does not overlap, and updates only check the added CIDRs
+	if _, err := v.ValidateUpdate(context.Background(), existing, existing.DeepCopy()); err != nil {
+		t.Errorf("expected no error when the CIDRs are unchanged, got %v", err)
+	}
+	updated := existing.DeepCopy()
+	updated.Spec.Subnets = append(updated.Spec.Subnets, SubnetSpec{Name: "more", CIDR: "10.0.2.0/24"})
+	if _, err := v.ValidateUpdate(context.Background(), existing, updated); err != nil {
+		t.Errorf("expected no error for a subnet overlapping only the cluster itself, got %v", err)
+	}
+	added := validCluster()
+	added.Spec.Subnets[0].CIDR = "10.0.4.0/24"
+	updated = added.DeepCopy()
+	updated.Spec.Subnets = append(updated.Spec.Subnets, SubnetSpec{Name: "more", CIDR: "10.0.3.0/24"})
+	if _, err := v.ValidateUpdate(context.Background(), added, updated); err == nil ||
+		!strings.Contains(err.Error(), "spec.subnets[1].cidr") {
+		t.Errorf("expected an error for the added subnet, got %v", err)
+	}
+
+	// Without a cache of every namespace, the clusters are listed from the API server
+	uncached := &clusterSiteValidator{
+		reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(site, otherSite, thirdSite, existing).Build(),
+	}
+	if _, err := uncached.ValidateCreate(context.Background(), validCluster()); err == nil ||
+		!strings.Contains(err.Error(), "overlaps subnet workers") {
+		t.Errorf("expected an error for the subnet overlapping the uncached cluster, got %v", err)
+	}
+	other := validCluster()
+	other.Spec.TenantID = "other-tenant"
+	if _, err := uncached.ValidateCreate(context.Background(), other); err != nil {
+		t.Errorf("expected no error for the cluster of another tenant, got %v", err)
+	}
+}
+
+func TestSecretAllowsNamespace(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "platform"}}
+	if !SecretAllowsNamespace(secret, "platform") || SecretAllowsNamespace(secret, "team-a") {
+		t.Error("expected a secret without annotation to only allow its own namespace")
+	}
+	secret.Annotations = map[string]string{AllowedNamespacesAnnotation: "*"}
+	if !SecretAllowsNamespace(secret, "team-a") {
+		t.Error("expected * to allow all the namespaces")
+	}
+}
+
 func TestClusterWebhook_SiteRefByName(t *testing.T) {
 	c := validCluster()
 	c.Spec.SiteRef = SiteReference{Name: "my-site"}
@@ -106,6 +524,25 @@ func TestClusterWebhook_NoSubnets(t *testing.T) {
 	}
 }
 
+func TestClusterWebhook_DuplicateNames(t *testing.T) {
+	c := validCluster()
+	c.Spec.Subnets = append(c.Spec.Subnets, SubnetSpec{Name: c.Spec.Subnets[0].Name, CIDR: "10.0.2.0/24"})
+	_, err := c.ValidateCreate(context.Background(), c)
+	if err == nil || !strings.Contains(err.Error(), "spec.subnets[1].name: Duplicate value") {
+		t.Errorf("expected a duplicate subnet name error, got %v", err)
+	}
+
+	c = validCluster()
+	c.Spec.VPCPrefixes = []VPCPrefixSpec{
+		{Name: "physical", CIDR: "10.1.0.0/24"},
+		{Name: "physical", CIDR: "10.2.0.0/24"},
+	}
+	_, err = c.ValidateCreate(context.Background(), c)
+	if err == nil || !strings.Contains(err.Error(), "spec.vpcPrefixes[1].name: Duplicate value") {
+		t.Errorf("expected a duplicate VPC prefix name error, got %v", err)
+	}
+}
+
 func TestClusterWebhook_ImmutableSiteRef(t *testing.T) {
 	old := validCluster()
 	new := validCluster()
@@ -126,6 +563,16 @@ func TestClusterWebhook_ImmutableTenantID(t *testing.T) {
 	}
 }
 
+func TestClusterWebhook_ImmutableOrganization(t *testing.T) {
+	old := validCluster()
+	new := validCluster()
+	new.Spec.Organization = "child-org"
+	_, err := old.ValidateUpdate(context.Background(), old, new)
+	if err == nil {
+		t.Error("expected error for immutable organization change")
+	}
+}
+
 func TestClusterWebhook_ImmutableVPCName(t *testing.T) {
 	old := validCluster()
 	new := validCluster()
@@ -136,6 +583,34 @@ func TestClusterWebhook_ImmutableVPCName(t *testing.T) {
 	}
 }
 
+func TestClusterWebhook_ImmutableVPCID(t *testing.T) {
+	old := validCluster()
+	new := validCluster()
+	new.Spec.VPC.ID = "shared-vpc-uuid"
+	_, err := old.ValidateUpdate(context.Background(), old, new)
+	if err == nil {
+		t.Error("expected error for immutable VPC ID change")
+	}
+}
+
+func TestClusterWebhook_SubnetSiteRef(t *testing.T) {
+	c := validCluster()
+	c.Spec.Subnets[0].SiteRef = &SiteReference{}
+	if _, err := c.ValidateCreate(context.Background(), c); err == nil {
+		t.Error("expected error for empty subnet site reference")
+	}
+
+	old := validCluster()
+	moved := validCluster()
+	moved.Spec.Subnets[0].SiteRef = &SiteReference{ID: "other-site"}
+	if _, err := moved.ValidateCreate(context.Background(), moved); err != nil {
+		t.Errorf("expected no error for subnet in another site, got %v", err)
+	}
+	if _, err := old.ValidateUpdate(context.Background(), old, moved); err == nil {
+		t.Error("expected error for subnet moved to another site")
+	}
+}
+
 func TestClusterWebhook_ValidVPCPrefix(t *testing.T) {
 	c := validCluster()
 	c.Spec.VPCPrefixes = []VPCPrefixSpec{
@@ -19,10 +19,17 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"strings"
+	"text/template"
 
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -31,13 +38,126 @@ import (
 
 var _ webhook.CustomValidator = &NcxInfraMachine{}
 
-func (r *NcxInfraMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// ProviderIDField indexes the NcxInfraMachines by the instance ID of their
+// spec.providerID, see ProviderIDInstance. The controllers add it to the
+// manager cache.
+const ProviderIDField = "spec.providerID"
+
+// ProviderIDInstance returns the instance ID of a provider ID, its last
+// segment, which identifies the instance whatever the scheme and format of the
+// provider ID. It returns an empty string for an invalid provider ID.
+func ProviderIDInstance(providerID string) string {
+	_, path, ok := strings.Cut(providerID, "://")
+	if !ok {
+		return ""
+	}
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return ""
+	}
+	return strings.ToLower(path[i+1:])
+}
+
+// SetupWebhookWithManager registers the webhook of the NcxInfraMachines. When
+// the cache of the manager is limited to the watched namespaces, namespaced is
+// true and the machines are listed from the API server to check the provider
+// IDs, so that the machines of the other namespaces are checked too.
+func (r *NcxInfraMachine) SetupWebhookWithManager(mgr ctrl.Manager, namespaced bool) error {
+	validator := &machineProviderIDValidator{reader: mgr.GetAPIReader(), machines: mgr.GetClient()}
+	if namespaced {
+		validator.machines = nil
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		WithValidator(r).
+		WithValidator(validator).
 		Complete()
 }
 
+// machineProviderIDValidator validates the NcxInfraMachines like
+// NcxInfraMachine does, and also rejects a machine whose spec.providerID
+// claims the instance of another NcxInfraMachine. Two machines owning an
+// instance, after a manual edit or a failed clusterctl move, would both manage
+// it and the first one deleted would delete it under the other.
+type machineProviderIDValidator struct {
+	reader client.Reader
+
+	// machines is the manager cache, where the machines are looked up by
+	// ProviderIDField. When nil, the cache does not hold the machines of every
+	// namespace, and they are listed from reader instead.
+	machines client.Reader
+}
+
+var _ webhook.CustomValidator = &machineProviderIDValidator{}
+
+func (v *machineProviderIDValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	machine, ok := obj.(*NcxInfraMachine)
+	if !ok {
+		return nil, fmt.Errorf("expected NcxInfraMachine, got %T", obj)
+	}
+	allErrs := machine.validateMachine()
+	claimErrs, err := validateProviderIDClaim(ctx, v.reader, v.machines, machine)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, claimErrs...)
+	return nil, allErrs.ToAggregate()
+}
+
+func (v *machineProviderIDValidator) ValidateUpdate(
+	ctx context.Context, oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	warnings, err := (&NcxInfraMachine{}).ValidateUpdate(ctx, oldObj, newObj)
+	if err != nil {
+		return warnings, err
+	}
+	oldMachine, machine := oldObj.(*NcxInfraMachine), newObj.(*NcxInfraMachine)
+	if ptr.Equal(oldMachine.Spec.ProviderID, machine.Spec.ProviderID) {
+		return warnings, nil
+	}
+	claimErrs, err := validateProviderIDClaim(ctx, v.reader, v.machines, machine)
+	if err != nil {
+		return warnings, apierrors.NewInternalError(err)
+	}
+	return warnings, claimErrs.ToAggregate()
+}
+
+func (v *machineProviderIDValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateProviderIDClaim checks that no other NcxInfraMachine, in any
+// namespace, has the instance of the provider ID of a machine, looked up in
+// machines, or listed from reader when machines is nil. A machine being
+// deleted still owns its instance.
+func validateProviderIDClaim(
+	ctx context.Context, reader, machines client.Reader, machine *NcxInfraMachine,
+) (field.ErrorList, error) {
+	instanceID := ProviderIDInstance(ptr.Deref(machine.Spec.ProviderID, ""))
+	if instanceID == "" {
+		return nil, nil
+	}
+	list := &NcxInfraMachineList{}
+	if machines == nil {
+		// The API server does not serve the ProviderIDField index
+		if err := reader.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("failed to list the NcxInfraMachines: %w", err)
+		}
+	} else if err := machines.List(ctx, list, client.MatchingFields{ProviderIDField: instanceID}); err != nil {
+		return nil, fmt.Errorf("failed to list the NcxInfraMachines of instance %s: %w", instanceID, err)
+	}
+	for _, other := range list.Items {
+		if other.Namespace == machine.Namespace && other.Name == machine.Name ||
+			ProviderIDInstance(ptr.Deref(other.Spec.ProviderID, "")) != instanceID {
+			continue
+		}
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "providerID"), *machine.Spec.ProviderID,
+			fmt.Sprintf("instance %s is already owned by NcxInfraMachine %s/%s",
+				instanceID, other.Namespace, other.Name))}, nil
+	}
+	return nil, nil
+}
+
 func (r *NcxInfraMachine) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
 	machine, ok := obj.(*NcxInfraMachine)
 	if !ok {
@@ -46,24 +166,31 @@ func (r *NcxInfraMachine) ValidateCreate(_ context.Context, obj runtime.Object)
 	return nil, machine.validateMachine().ToAggregate()
 }
 
-func (r *NcxInfraMachine) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+func (r *NcxInfraMachine) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	machine, ok := newObj.(*NcxInfraMachine)
 	if !ok {
 		return nil, fmt.Errorf("expected NcxInfraMachine, got %T", newObj)
 	}
-	return nil, machine.validateMachine().ToAggregate()
+	oldMachine, ok := oldObj.(*NcxInfraMachine)
+	if !ok {
+		return nil, fmt.Errorf("expected NcxInfraMachine, got %T", oldObj)
+	}
+	allErrs := machine.validateMachine()
+	allErrs = append(allErrs, machine.validateImmutableFields(oldMachine)...)
+	return nil, allErrs.ToAggregate()
 }
 
 func (r *NcxInfraMachine) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
 	return nil, nil
 }
 
-func (r *NcxInfraMachine) validateMachine() field.ErrorList {
+// validateMachineSpec validates the spec of a NcxInfraMachine, or of the
+// machines of a NcxInfraMachineTemplate.
+func validateMachineSpec(spec *NcxInfraMachineSpec, specPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
-	specPath := field.NewPath("spec")
 
 	// Validate mutual exclusion of instanceTypeId vs machineId
-	instanceType := r.Spec.InstanceType
+	instanceType := spec.InstanceType
 	if instanceType.ID != "" && instanceType.MachineID != "" {
 		allErrs = append(allErrs, field.Forbidden(
 			specPath.Child("instanceType"),
@@ -77,20 +204,27 @@ func (r *NcxInfraMachine) validateMachine() field.ErrorList {
 			"one of id or machineID must be specified"))
 	}
 
+	// The tenant is a segment of the provider ID of the machine
+	if strings.ContainsAny(spec.TenantID, "/ \t\n") {
+		allErrs = append(allErrs, field.Invalid(
+			specPath.Child("tenantID"), spec.TenantID,
+			"must not contain slashes or whitespace, it is a segment of the provider ID"))
+	}
+
 	// Validate primary network interface: exactly one of SubnetName or VPCPrefixName
-	if r.Spec.Network.SubnetName == "" && r.Spec.Network.VPCPrefixName == "" {
+	if spec.Network.SubnetName == "" && spec.Network.VPCPrefixName == "" {
 		allErrs = append(allErrs, field.Required(
 			specPath.Child("network"),
 			"one of subnetName or vpcPrefixName must be specified"))
 	}
-	if r.Spec.Network.SubnetName != "" && r.Spec.Network.VPCPrefixName != "" {
+	if spec.Network.SubnetName != "" && spec.Network.VPCPrefixName != "" {
 		allErrs = append(allErrs, field.Forbidden(
 			specPath.Child("network"),
 			"subnetName and vpcPrefixName are mutually exclusive"))
 	}
 
 	// Validate additional interfaces: each must have exactly one of SubnetName or VPCPrefixName
-	for i, iface := range r.Spec.Network.AdditionalInterfaces {
+	for i, iface := range spec.Network.AdditionalInterfaces {
 		ifacePath := specPath.Child("network", "additionalInterfaces").Index(i)
 		if iface.SubnetName == "" && iface.VPCPrefixName == "" {
 			allErrs = append(allErrs, field.Required(
@@ -102,10 +236,36 @@ func (r *NcxInfraMachine) validateMachine() field.ErrorList {
 				ifacePath,
 				"subnetName and vpcPrefixName are mutually exclusive"))
 		}
+		if iface.ClusterRef != nil && iface.ClusterRef.Name == "" {
+			allErrs = append(allErrs, field.Required(
+				ifacePath.Child("clusterRef", "name"),
+				"referenced NcxInfraCluster name must not be empty"))
+		}
+		if iface.SRIOV != nil && (iface.SubnetName == "" || iface.IsPhysical) {
+			allErrs = append(allErrs, field.Forbidden(
+				ifacePath.Child("sriov"),
+				"SR-IOV virtual functions are attached to a subnetName on a virtual interface"))
+		}
+		if vlan := iface.VLAN; vlan != nil {
+			vlanPath := ifacePath.Child("vlan")
+			switch {
+			case iface.SRIOV != nil:
+				allErrs = append(allErrs, field.Forbidden(vlanPath, "use sriov.vlan to tag the traffic of virtual functions"))
+			case vlan.AccessVLAN != 0 && len(vlan.TrunkVLANs) > 0:
+				allErrs = append(allErrs, field.Forbidden(vlanPath.Child("accessVLAN"),
+					"accessVLAN and trunkVLANs are mutually exclusive"))
+			case vlan.NativeVLAN != 0 && len(vlan.TrunkVLANs) == 0:
+				allErrs = append(allErrs, field.Required(vlanPath.Child("trunkVLANs"),
+					"nativeVLAN requires trunkVLANs"))
+			}
+		}
 	}
 
+	allErrs = append(allErrs, validateDNSServers(spec.Network.DNSServers,
+		specPath.Child("network", "dnsServers"))...)
+
 	// Validate DPU extension services
-	for i, dpuSpec := range r.Spec.DPUExtensionServices {
+	for i, dpuSpec := range spec.DPUExtensionServices {
 		dpuPath := specPath.Child("dpuExtensionServices").Index(i)
 		if dpuSpec.ServiceID == "" {
 			allErrs = append(allErrs, field.Required(
@@ -115,7 +275,7 @@ func (r *NcxInfraMachine) validateMachine() field.ErrorList {
 	}
 
 	// Validate InfiniBand interfaces
-	for i, ibSpec := range r.Spec.InfiniBandInterfaces {
+	for i, ibSpec := range spec.InfiniBandInterfaces {
 		ibPath := specPath.Child("infiniBandInterfaces").Index(i)
 		if ibSpec.PartitionID == "" {
 			allErrs = append(allErrs, field.Required(
@@ -125,7 +285,7 @@ func (r *NcxInfraMachine) validateMachine() field.ErrorList {
 	}
 
 	// Validate NVLink interfaces
-	for i, nvSpec := range r.Spec.NVLinkInterfaces {
+	for i, nvSpec := range spec.NVLinkInterfaces {
 		nvPath := specPath.Child("nvlinkInterfaces").Index(i)
 		if nvSpec.LogicalPartitionID == "" {
 			allErrs = append(allErrs, field.Required(
@@ -134,8 +294,250 @@ func (r *NcxInfraMachine) validateMachine() field.ErrorList {
 		}
 	}
 
+	// Chassis anti-affinity picks the machine itself, it cannot apply to an explicit machineID
+	if spec.Placement != nil && instanceType.MachineID != "" &&
+		spec.Placement.ChassisAntiAffinity != "" && spec.Placement.ChassisAntiAffinity != AntiAffinityNone {
+		allErrs = append(allErrs, field.Forbidden(
+			specPath.Child("placement", "chassisAntiAffinity"),
+			"chassis anti-affinity requires instanceType.id and cannot be used with machineID"))
+	}
+	if placement := spec.Placement; placement != nil && placement.AntiAffinity != nil {
+		antiAffinityPath := specPath.Child("placement", "antiAffinity")
+		if instanceType.MachineID != "" {
+			allErrs = append(allErrs, field.Forbidden(antiAffinityPath,
+				"anti-affinity requires instanceType.id and cannot be used with machineID"))
+		}
+		if placement.ChassisAntiAffinity != "" && placement.ChassisAntiAffinity != AntiAffinityNone {
+			allErrs = append(allErrs, field.Forbidden(antiAffinityPath,
+				"antiAffinity cannot be combined with chassisAntiAffinity, use level Chassis instead"))
+		}
+	}
+
+	// Validate deletion options
+	if deletion := spec.Deletion; deletion != nil {
+		deletionPath := specPath.Child("deletion")
+		if deletion.Policy == DeletionPolicyRepair && deletion.SecureErase == SecureEraseVerified {
+			allErrs = append(allErrs, field.Forbidden(
+				deletionPath.Child("secureErase"),
+				"Verified secure erase cannot be combined with the Repair policy"))
+		}
+		if deletion.HealthIssue != nil && deletion.Policy != DeletionPolicyRepair {
+			allErrs = append(allErrs, field.Forbidden(
+				deletionPath.Child("healthIssue"),
+				"healthIssue is only used with the Repair policy"))
+		}
+	}
+
+	// Validate inventory node labels
+	if inventory := spec.Inventory; inventory != nil && inventory.NodeLabelPrefix != "" {
+		inventoryPath := specPath.Child("inventory")
+		for _, msg := range validation.IsDNS1123Subdomain(inventory.NodeLabelPrefix) {
+			allErrs = append(allErrs, field.Invalid(
+				inventoryPath.Child("nodeLabelPrefix"), inventory.NodeLabelPrefix, msg))
+		}
+		for i, key := range inventory.LabelKeys {
+			for _, msg := range validation.IsQualifiedName(inventory.NodeLabelPrefix + "/" + key) {
+				allErrs = append(allErrs, field.Invalid(
+					inventoryPath.Child("labelKeys").Index(i), key, msg))
+			}
+		}
+	}
+
+	// Validate the firmware boot mode
+	if security := spec.Security; security != nil && security.BootMode == BootModeLegacy &&
+		(security.SecureBoot || security.MeasuredBoot) {
+		allErrs = append(allErrs, field.Forbidden(
+			specPath.Child("security", "bootMode"),
+			"secure boot and measured boot require the UEFI boot mode"))
+	}
+
+	// Validate that the kernel arguments do not override the tuning
+	if tuning := spec.Tuning; tuning != nil {
+		for i, arg := range spec.KernelArgs {
+			name, _, _ := strings.Cut(arg, "=")
+			setting, ok := tuningKernelArgs[name]
+			if !ok || (setting == "hugePages" && len(tuning.HugePages) == 0) ||
+				(setting == "isolatedCPUs" && tuning.IsolatedCPUs == "") {
+				continue
+			}
+			allErrs = append(allErrs, field.Forbidden(
+				specPath.Child("kernelArgs").Index(i),
+				fmt.Sprintf("%s is set from spec.tuning.%s", name, setting)))
+		}
+	}
+
+	// Validate the storage layout
+	if storage := spec.Storage; storage != nil {
+		allErrs = append(allErrs, validateStorage(storage, specPath.Child("storage"))...)
+	}
+
+	// Validate the hostname format against sample variables and the domain
+	if spec.HostnameFormat != "" {
+		if _, err := RenderHostname(spec.HostnameFormat, sampleHostnameVariables); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("hostnameFormat"), spec.HostnameFormat, err.Error()))
+		}
+	}
+	if spec.Domain != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(spec.Domain) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("domain"), spec.Domain, msg))
+		}
+	}
+	return allErrs
+}
+
+// HostnameVariables are the variables of spec.hostnameFormat.
+// +kubebuilder:object:generate=false
+type HostnameVariables struct {
+	ClusterName string
+	MachineName string
+	Namespace   string
+	// Role is control-plane or worker
+	Role string
+	// Site is the NVIDIA Carbide site of the instance
+	Site string
+	// Index is the lowest index not used by another machine of the cluster
+	// with the same hostname
+	Index int
+}
+
+// sampleHostnameVariables are the variables the webhook renders the hostname
+// formats with, to reject the formats that cannot render a hostname.
+var sampleHostnameVariables = HostnameVariables{
+	ClusterName: "cluster",
+	MachineName: "machine",
+	Namespace:   "default",
+	Role:        "worker",
+	Site:        "site",
+}
+
+// RenderHostname renders a hostname format with the variables of a machine.
+// It returns an error when the result is not a valid hostname, i.e. a DNS-1123
+// label.
+func RenderHostname(format string, vars HostnameVariables) (string, error) {
+	tmpl, err := template.New("hostname").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return "", err
+	}
+	var hostname strings.Builder
+	if err := tmpl.Execute(&hostname, vars); err != nil {
+		return "", err
+	}
+	if msgs := validation.IsDNS1123Label(hostname.String()); len(msgs) > 0 {
+		return "", fmt.Errorf("hostname %q is invalid: %s", hostname.String(), strings.Join(msgs, ", "))
+	}
+	return hostname.String(), nil
+}
+
+func (r *NcxInfraMachine) validateMachine() field.ErrorList {
+	allErrs := validateMachineSpec(&r.Spec, field.NewPath("spec"))
+
+	// Validate the requested power action
+	if action, ok := r.Annotations[PowerActionAnnotation]; ok {
+		switch PowerAction(action) {
+		case PowerActionReboot, PowerActionPowerOff, PowerActionPowerOn:
+		default:
+			allErrs = append(allErrs, field.NotSupported(
+				field.NewPath("metadata", "annotations").Key(PowerActionAnnotation),
+				action,
+				[]string{string(PowerActionReboot), string(PowerActionPowerOff), string(PowerActionPowerOn)}))
+		}
+	}
+
 	if len(allErrs) > 0 {
 		return allErrs
 	}
 	return nil
 }
+
+// tuningKernelArgs maps the kernel arguments set from spec.tuning to the
+// setting they come from.
+var tuningKernelArgs = map[string]string{
+	"default_hugepagesz": "hugePages",
+	"hugepagesz":         "hugePages",
+	"hugepages":          "hugePages",
+	"isolcpus":           "isolatedCPUs",
+	"nohz_full":          "isolatedCPUs",
+	"rcu_nocbs":          "isolatedCPUs",
+}
+
+// raidMinDevices is the number of disks each RAID level needs.
+var raidMinDevices = map[int32]int{0: 2, 1: 2, 5: 3, 6: 4, 10: 2}
+
+// validateStorage checks that the RAID arrays have enough disks for their
+// level and share neither disks nor mount paths.
+func validateStorage(storage *StorageSpec, storagePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	devices := map[string]bool{}
+	mountPaths := map[string]bool{}
+	for i, array := range storage.Arrays {
+		arrayPath := storagePath.Child("arrays").Index(i)
+		if minDevices := raidMinDevices[array.Level]; len(array.Devices) < minDevices {
+			allErrs = append(allErrs, field.Invalid(arrayPath.Child("devices"), array.Devices,
+				fmt.Sprintf("RAID %d needs at least %d devices", array.Level, minDevices)))
+		}
+		for j, device := range array.Devices {
+			if devices[device] {
+				allErrs = append(allErrs, field.Duplicate(arrayPath.Child("devices").Index(j), device))
+			}
+			devices[device] = true
+		}
+		if array.MountPath != "" {
+			if mountPaths[array.MountPath] {
+				allErrs = append(allErrs, field.Duplicate(arrayPath.Child("mountPath"), array.MountPath))
+			}
+			mountPaths[array.MountPath] = true
+		}
+	}
+	return allErrs
+}
+
+// machineImmutableHint tells how to change a field the instance of a machine
+// was created with.
+const machineImmutableHint = "field is immutable, the instance of a bare-metal machine cannot be changed in place: " +
+	"roll out a new NcxInfraMachineTemplate, or delete the machine, to get an instance with the new value"
+
+// validateImmutableFields rejects the changes to the fields the instance of
+// the machine is created from, which the controller cannot apply to an
+// existing instance. Until the instance is created, they can still be fixed,
+// e.g. on a machine that cannot be provisioned.
+func (r *NcxInfraMachine) validateImmutableFields(old *NcxInfraMachine) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	// The provider ID identifies the instance, it is set once by the controller
+	if oldID := ptr.Deref(old.Spec.ProviderID, ""); oldID != "" && oldID != ptr.Deref(r.Spec.ProviderID, "") {
+		allErrs = append(allErrs, field.Forbidden(
+			specPath.Child("providerID"),
+			"field is immutable once set, it identifies the instance of the machine"))
+	}
+	if ptr.Deref(old.Spec.ProviderID, "") == "" && old.Status.InstanceID == "" {
+		return allErrs
+	}
+
+	instanceTypePath := specPath.Child("instanceType")
+	if old.Spec.InstanceType.ID != r.Spec.InstanceType.ID {
+		allErrs = append(allErrs, field.Forbidden(instanceTypePath.Child("id"), machineImmutableHint))
+	}
+	if old.Spec.InstanceType.MachineID != r.Spec.InstanceType.MachineID {
+		allErrs = append(allErrs, field.Forbidden(instanceTypePath.Child("machineID"), machineImmutableHint))
+	}
+
+	if old.Spec.TenantID != r.Spec.TenantID {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("tenantID"), machineImmutableHint))
+	}
+
+	networkPath := specPath.Child("network")
+	if old.Spec.Network.SubnetName != r.Spec.Network.SubnetName {
+		allErrs = append(allErrs, field.Forbidden(networkPath.Child("subnetName"), machineImmutableHint))
+	}
+	if old.Spec.Network.VPCPrefixName != r.Spec.Network.VPCPrefixName {
+		allErrs = append(allErrs, field.Forbidden(networkPath.Child("vpcPrefixName"), machineImmutableHint))
+	}
+	if old.Spec.Network.IpAddress != r.Spec.Network.IpAddress {
+		allErrs = append(allErrs, field.Forbidden(networkPath.Child("ipAddress"), machineImmutableHint))
+	}
+	if !apiequality.Semantic.DeepEqual(old.Spec.Network.AdditionalInterfaces, r.Spec.Network.AdditionalInterfaces) {
+		allErrs = append(allErrs, field.Forbidden(networkPath.Child("additionalInterfaces"), machineImmutableHint))
+	}
+	return allErrs
+}
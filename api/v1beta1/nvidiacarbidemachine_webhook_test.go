@@ -18,9 +18,15 @@ package v1beta1
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func validMachine() *NcxInfraMachine {
@@ -105,6 +111,14 @@ func TestMachineWebhook_VPCPrefixOnly(t *testing.T) {
 	}
 }
 
+func TestMachineWebhook_NetworkDNSServers(t *testing.T) {
+	m := validMachine()
+	m.Spec.Network.DNSServers = []string{"10.0.0.53", "dns.example.com"}
+	if _, err := m.ValidateCreate(context.Background(), m); err == nil {
+		t.Error("expected error for a DNS server that is not an IP address")
+	}
+}
+
 func TestMachineWebhook_DPUExtensionEmptyServiceID(t *testing.T) {
 	m := validMachine()
 	m.Spec.DPUExtensionServices = []DPUExtensionServiceSpec{
@@ -138,6 +152,89 @@ func TestMachineWebhook_AdditionalIfaceMutualExclusion(t *testing.T) {
 	}
 }
 
+func TestMachineWebhook_AdditionalIfaceClusterRef(t *testing.T) {
+	m := validMachine()
+	m.Spec.Network.AdditionalInterfaces = []NetworkInterface{
+		{SubnetName: "storage", ClusterRef: &corev1.LocalObjectReference{Name: "storage-cluster"}},
+	}
+	if _, err := m.ValidateCreate(context.Background(), m); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	m.Spec.Network.AdditionalInterfaces[0].ClusterRef.Name = ""
+	if _, err := m.ValidateCreate(context.Background(), m); err == nil {
+		t.Error("expected error for additional interface with an empty cluster reference")
+	}
+}
+
+func TestMachineWebhook_AdditionalIfaceSRIOV(t *testing.T) {
+	m := validMachine()
+	m.Spec.Network.AdditionalInterfaces = []NetworkInterface{
+		{SubnetName: "dpdk", SRIOV: &SRIOVSpec{NumVFs: 4, Trust: true, VLAN: 100}},
+	}
+	if _, err := m.ValidateCreate(context.Background(), m); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	m.Spec.Network.AdditionalInterfaces[0].IsPhysical = true
+	if _, err := m.ValidateCreate(context.Background(), m); err == nil {
+		t.Error("expected error for SR-IOV on a physical interface")
+	}
+
+	m.Spec.Network.AdditionalInterfaces[0] = NetworkInterface{VPCPrefixName: "p1", SRIOV: &SRIOVSpec{NumVFs: 4}}
+	if _, err := m.ValidateCreate(context.Background(), m); err == nil {
+		t.Error("expected error for SR-IOV on a VPC prefix interface")
+	}
+}
+
+func TestMachineWebhook_AdditionalIfaceVLAN(t *testing.T) {
+	tests := []struct {
+		name    string
+		iface   NetworkInterface
+		wantErr string
+	}{
+		{
+			name:  "access",
+			iface: NetworkInterface{SubnetName: "oob", VLAN: &VLANSpec{AccessVLAN: 300}},
+		},
+		{
+			name:  "trunk with native VLAN",
+			iface: NetworkInterface{SubnetName: "tenant", VLAN: &VLANSpec{TrunkVLANs: []int32{100, 200}, NativeVLAN: 10}},
+		},
+		{
+			name:    "access and trunk",
+			iface:   NetworkInterface{SubnetName: "tenant", VLAN: &VLANSpec{AccessVLAN: 300, TrunkVLANs: []int32{100}}},
+			wantErr: "vlan.accessVLAN",
+		},
+		{
+			name:    "native VLAN without trunk",
+			iface:   NetworkInterface{SubnetName: "tenant", VLAN: &VLANSpec{NativeVLAN: 10}},
+			wantErr: "vlan.trunkVLANs",
+		},
+		{
+			name:    "SR-IOV",
+			iface:   NetworkInterface{SubnetName: "dpdk", SRIOV: &SRIOVSpec{NumVFs: 2}, VLAN: &VLANSpec{AccessVLAN: 300}},
+			wantErr: "sriov.vlan",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := validMachine()
+			m.Spec.Network.AdditionalInterfaces = []NetworkInterface{tt.iface}
+			_, err := m.ValidateCreate(context.Background(), m)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestMachineWebhook_EmptyIBPartitionID(t *testing.T) {
 	m := validMachine()
 	m.Spec.InfiniBandInterfaces = []InfiniBandInterfaceSpec{
@@ -180,3 +277,342 @@ func TestMachineWebhook_ValidUpdate(t *testing.T) {
 		t.Errorf("expected no error for valid update, got %v", err)
 	}
 }
+
+func TestMachineWebhook_RepairWithVerifiedErase(t *testing.T) {
+	m := validMachine()
+	m.Spec.Deletion = &DeletionSpec{Policy: DeletionPolicyRepair, SecureErase: SecureEraseVerified}
+	_, err := m.ValidateCreate(context.Background(), m)
+	if err == nil {
+		t.Error("expected error for Repair policy with Verified secure erase")
+	}
+}
+
+func TestMachineWebhook_HealthIssueWithoutRepair(t *testing.T) {
+	m := validMachine()
+	m.Spec.Deletion = &DeletionSpec{
+		Policy:      DeletionPolicyRelease,
+		HealthIssue: &MachineHealthIssueSpec{Summary: "bad DIMM"},
+	}
+	_, err := m.ValidateCreate(context.Background(), m)
+	if err == nil {
+		t.Error("expected error for healthIssue without Repair policy")
+	}
+}
+
+func TestMachineWebhook_ChassisAntiAffinityWithMachineID(t *testing.T) {
+	m := validMachine()
+	m.Spec.InstanceType.ID = ""
+	m.Spec.InstanceType.MachineID = "machine-uuid"
+	m.Spec.Placement = &PlacementSpec{ChassisAntiAffinity: AntiAffinityRequired}
+	_, err := m.ValidateCreate(context.Background(), m)
+	if err == nil {
+		t.Error("expected error for chassis anti-affinity with machineID")
+	}
+}
+
+func TestMachineWebhook_AntiAffinity(t *testing.T) {
+	m := validMachine()
+	m.Spec.Placement = &PlacementSpec{AntiAffinity: &AntiAffinitySpec{Level: FailureUnitRack}}
+	if _, err := m.ValidateCreate(context.Background(), m); err != nil {
+		t.Errorf("expected no error for rack anti-affinity, got %v", err)
+	}
+
+	m.Spec.Placement.ChassisAntiAffinity = AntiAffinityRequired
+	if _, err := m.ValidateCreate(context.Background(), m); err == nil {
+		t.Error("expected error for antiAffinity combined with chassisAntiAffinity")
+	}
+
+	m.Spec.Placement.ChassisAntiAffinity = ""
+	m.Spec.InstanceType.ID = ""
+	m.Spec.InstanceType.MachineID = "machine-uuid"
+	if _, err := m.ValidateCreate(context.Background(), m); err == nil {
+		t.Error("expected error for anti-affinity with machineID")
+	}
+}
+
+func TestMachineWebhook_PowerAction(t *testing.T) {
+	m := validMachine()
+	m.Annotations = map[string]string{PowerActionAnnotation: string(PowerActionReboot)}
+	if _, err := m.ValidateUpdate(context.Background(), m, m); err != nil {
+		t.Errorf("expected no error for reboot power action, got %v", err)
+	}
+
+	m.Annotations[PowerActionAnnotation] = "hibernate"
+	if _, err := m.ValidateUpdate(context.Background(), m, m); err == nil {
+		t.Error("expected error for unsupported power action")
+	}
+}
+
+func TestMachineWebhook_InventoryNodeLabels(t *testing.T) {
+	m := validMachine()
+	m.Spec.Inventory = &InventorySpec{
+		LabelKeys:       []string{"rack", "datacenter"},
+		NodeLabelPrefix: "inventory.ncx-infra.io",
+	}
+	if _, err := m.ValidateCreate(context.Background(), m); err != nil {
+		t.Errorf("expected no error for valid inventory, got %v", err)
+	}
+
+	m.Spec.Inventory.LabelKeys = []string{"warranty end"}
+	if _, err := m.ValidateCreate(context.Background(), m); err == nil {
+		t.Error("expected error for a label key that is not a valid node label")
+	}
+}
+
+func TestMachineWebhook_Storage(t *testing.T) {
+	tests := []struct {
+		name    string
+		arrays  []RAIDArraySpec
+		wantErr string
+	}{
+		{
+			name: "striped scratch and mirrored logs",
+			arrays: []RAIDArraySpec{
+				{Name: "scratch", Level: 0, Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}, MountPath: "/mnt/scratch"},
+				{Name: "logs", Level: 1, Devices: []string{"/dev/sdb", "/dev/sdc"}, MountPath: "/var/log"},
+			},
+		},
+		{
+			name:    "too few devices",
+			arrays:  []RAIDArraySpec{{Name: "data", Level: 6, Devices: []string{"/dev/sdb", "/dev/sdc", "/dev/sdd"}}},
+			wantErr: "RAID 6 needs at least 4 devices",
+		},
+		{
+			name: "shared device",
+			arrays: []RAIDArraySpec{
+				{Name: "a", Level: 0, Devices: []string{"/dev/sdb", "/dev/sdc"}},
+				{Name: "b", Level: 1, Devices: []string{"/dev/sdc", "/dev/sdd"}},
+			},
+			wantErr: "spec.storage.arrays[1].devices[0]",
+		},
+		{
+			name: "shared mount path",
+			arrays: []RAIDArraySpec{
+				{Name: "a", Level: 0, Devices: []string{"/dev/sdb", "/dev/sdc"}, MountPath: "/data"},
+				{Name: "b", Level: 0, Devices: []string{"/dev/sdd", "/dev/sde"}, MountPath: "/data"},
+			},
+			wantErr: "spec.storage.arrays[1].mountPath",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := validMachine()
+			m.Spec.Storage = &StorageSpec{Arrays: tt.arrays}
+			_, err := m.ValidateCreate(context.Background(), m)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMachineWebhook_BootMode(t *testing.T) {
+	m := validMachine()
+	m.Spec.Security = &SecuritySpec{RequireTPM: true, BootMode: BootModeLegacy}
+	if _, err := m.ValidateCreate(context.Background(), m); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	m.Spec.Security.SecureBoot = true
+	_, err := m.ValidateCreate(context.Background(), m)
+	if err == nil || !strings.Contains(err.Error(), "spec.security.bootMode") {
+		t.Errorf("expected an error on spec.security.bootMode, got %v", err)
+	}
+}
+
+func TestMachineWebhook_KernelArgs(t *testing.T) {
+	m := validMachine()
+	m.Spec.KernelArgs = []string{"iommu=pt", "hugepages=64"}
+	if _, err := m.ValidateCreate(context.Background(), m); err != nil {
+		t.Errorf("expected hugepages in kernel arguments to be allowed without tuning, got %v", err)
+	}
+
+	m.Spec.Tuning = &TuningSpec{IsolatedCPUs: "2-7"}
+	if _, err := m.ValidateCreate(context.Background(), m); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	m.Spec.Tuning.HugePages = []HugePagesSpec{{Size: HugePageSize1Gi, Count: 16}}
+	_, err := m.ValidateCreate(context.Background(), m)
+	if err == nil || !strings.Contains(err.Error(), "spec.kernelArgs[1]") {
+		t.Errorf("expected an error on spec.kernelArgs[1], got %v", err)
+	}
+}
+
+func TestMachineWebhook_Hostname(t *testing.T) {
+	m := validMachine()
+	m.Spec.HostnameFormat = "{{.ClusterName}}-{{.Role}}-{{.Index}}"
+	m.Spec.Domain = "dc1.example.com"
+	if _, err := m.ValidateCreate(context.Background(), m); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	for _, format := range []string{"{{.ClusterName", "{{.Rack}}", "{{.MachineName}}.{{.Site}}", "Node-{{.Index}}"} {
+		m.Spec.HostnameFormat = format
+		_, err := m.ValidateCreate(context.Background(), m)
+		if err == nil || !strings.Contains(err.Error(), "spec.hostnameFormat") {
+			t.Errorf("expected an error on spec.hostnameFormat for %q, got %v", format, err)
+		}
+	}
+
+	m.Spec.HostnameFormat = ""
+	m.Spec.Domain = "-example.com"
+	_, err := m.ValidateCreate(context.Background(), m)
+	if err == nil || !strings.Contains(err.Error(), "spec.domain") {
+		t.Errorf("expected an error on spec.domain, got %v", err)
+	}
+}
+
+func TestRenderHostname(t *testing.T) {
+	hostname, err := RenderHostname("{{.ClusterName}}-{{.Role}}-{{.Index}}",
+		HostnameVariables{ClusterName: "prod", Role: "worker", Index: 3})
+	if err != nil || hostname != "prod-worker-3" {
+		t.Errorf("expected prod-worker-3, got %q (%v)", hostname, err)
+	}
+}
+
+func TestMachineWebhook_TenantID(t *testing.T) {
+	m := validMachine()
+	m.Spec.TenantID = "tenant-b"
+	if _, err := m.ValidateCreate(context.Background(), m); err != nil {
+		t.Errorf("expected no error for a tenant override, got %v", err)
+	}
+
+	for _, tenantID := range []string{"tenant/b", "tenant b"} {
+		m.Spec.TenantID = tenantID
+		_, err := m.ValidateCreate(context.Background(), m)
+		if err == nil || !strings.Contains(err.Error(), "spec.tenantID") {
+			t.Errorf("expected an error on spec.tenantID for %q, got %v", tenantID, err)
+		}
+	}
+}
+
+func TestMachineWebhook_ImmutableFields(t *testing.T) {
+	old := validMachine()
+	set := old.DeepCopy()
+	set.Spec.ProviderID = ptr.To("nvidia-ncx-infra://org/tenant/site/instance")
+	set.Spec.SSHKeyGroups = []string{"ssh-key-group"}
+	if _, err := old.ValidateUpdate(context.Background(), old, set); err != nil {
+		t.Errorf("expected no error when setting the provider ID, got %v", err)
+	}
+
+	tests := map[string]struct {
+		mutate func(m *NcxInfraMachine)
+		field  string
+	}{
+		"provider ID changed": {func(m *NcxInfraMachine) { m.Spec.ProviderID = ptr.To("other") }, "spec.providerID"},
+		"provider ID cleared": {func(m *NcxInfraMachine) { m.Spec.ProviderID = nil }, "spec.providerID"},
+		"instance type":       {func(m *NcxInfraMachine) { m.Spec.InstanceType.ID = "other-type" }, "spec.instanceType.id"},
+		"subnet":              {func(m *NcxInfraMachine) { m.Spec.Network.SubnetName = "worker" }, "spec.network.subnetName"},
+		"IP address":          {func(m *NcxInfraMachine) { m.Spec.Network.IpAddress = "10.0.1.11" }, "spec.network.ipAddress"},
+		"tenant":              {func(m *NcxInfraMachine) { m.Spec.TenantID = "other-tenant" }, "spec.tenantID"},
+		"additional interfaces": {func(m *NcxInfraMachine) {
+			m.Spec.Network.AdditionalInterfaces = []NetworkInterface{{SubnetName: "storage"}}
+		}, "spec.network.additionalInterfaces"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			changed := set.DeepCopy()
+			tt.mutate(changed)
+			_, err := set.ValidateUpdate(context.Background(), set, changed)
+			if err == nil || !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("expected an error on %s, got %v", tt.field, err)
+			}
+		})
+	}
+
+	// The instance is created before the provider ID is set
+	created := old.DeepCopy()
+	created.Status.InstanceID = "instance"
+	changed := created.DeepCopy()
+	changed.Spec.InstanceType.ID = "other-type"
+	if _, err := created.ValidateUpdate(context.Background(), created, changed); err == nil ||
+		!strings.Contains(err.Error(), "spec.instanceType.id") {
+		t.Errorf("expected an error on spec.instanceType.id once the instance is created, got %v", err)
+	}
+
+	// A machine without instance can still be fixed
+	fixed := old.DeepCopy()
+	fixed.Spec.InstanceType.ID = "other-type"
+	fixed.Spec.Network.SubnetName = "worker"
+	fixed.Spec.Network.AdditionalInterfaces = []NetworkInterface{{SubnetName: "storage"}}
+	if _, err := old.ValidateUpdate(context.Background(), old, fixed); err != nil {
+		t.Errorf("expected no error before the instance is created, got %v", err)
+	}
+}
+
+func TestProviderIDInstance(t *testing.T) {
+	tests := map[string]string{
+		"nico://org/tenant/site/7C9E6679-7425-40DE-944B-E07FC1F90AE7": "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		"ncx-infra://org/site/7c9e6679-7425-40de-944b-e07fc1f90ae7":   "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		"7c9e6679-7425-40de-944b-e07fc1f90ae7":                        "",
+		"nico://instance":                                             "",
+	}
+	for providerID, want := range tests {
+		if got := ProviderIDInstance(providerID); got != want {
+			t.Errorf("ProviderIDInstance(%q) = %q, want %q", providerID, got, want)
+		}
+	}
+}
+
+func TestMachineWebhook_ProviderIDClaim(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	const instanceID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	owner := validMachine()
+	owner.Namespace = "team-a"
+	owner.Spec.ProviderID = ptr.To("nico://org/tenant/site/" + instanceID)
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(owner).
+		WithIndex(&NcxInfraMachine{}, ProviderIDField, func(obj client.Object) []string {
+			return []string{ProviderIDInstance(ptr.Deref(obj.(*NcxInfraMachine).Spec.ProviderID, ""))}
+		}).Build()
+	v := &machineProviderIDValidator{machines: reader}
+
+	// The legacy format of the provider ID claims the same instance
+	claimant := validMachine()
+	claimant.Spec.ProviderID = ptr.To("ncx-infra://org/site/" + instanceID)
+	_, err := v.ValidateCreate(context.Background(), claimant)
+	if err == nil || !strings.Contains(err.Error(), "already owned by NcxInfraMachine team-a/test") {
+		t.Errorf("expected the instance to be owned by team-a/test, got %v", err)
+	}
+
+	// The controller setting the provider ID of a machine without one
+	unset := validMachine()
+	if _, err := v.ValidateUpdate(context.Background(), unset, claimant); err == nil {
+		t.Error("expected an error when setting the provider ID of an owned instance")
+	}
+	claimant.Spec.ProviderID = ptr.To("nico://org/tenant/site/a3bb189e-8bf9-3888-9912-ace4e6543002")
+	if _, err := v.ValidateUpdate(context.Background(), unset, claimant); err != nil {
+		t.Errorf("expected no error for another instance, got %v", err)
+	}
+
+	// The owner itself is not a duplicate
+	updated := owner.DeepCopy()
+	updated.Spec.SSHKeyGroups = []string{"ssh-key-group"}
+	if _, err := v.ValidateUpdate(context.Background(), owner, updated); err != nil {
+		t.Errorf("expected no error when updating the owner, got %v", err)
+	}
+	if _, err := v.ValidateCreate(context.Background(), owner); err != nil {
+		t.Errorf("expected no error when the owner is validated again, got %v", err)
+	}
+
+	// Without a cache of every namespace, the machines are listed from the API server
+	uncached := &machineProviderIDValidator{reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(owner).Build()}
+	claimant.Spec.ProviderID = ptr.To("ncx-infra://org/site/" + instanceID)
+	if _, err := uncached.ValidateCreate(context.Background(), claimant); err == nil ||
+		!strings.Contains(err.Error(), "already owned by NcxInfraMachine team-a/test") {
+		t.Errorf("expected the instance to be owned by team-a/test, got %v", err)
+	}
+	claimant.Spec.ProviderID = ptr.To("nico://org/tenant/site/a3bb189e-8bf9-3888-9912-ace4e6543002")
+	if _, err := uncached.ValidateCreate(context.Background(), claimant); err != nil {
+		t.Errorf("expected no error for another instance, got %v", err)
+	}
+}
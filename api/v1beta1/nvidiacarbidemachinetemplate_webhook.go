@@ -0,0 +1,237 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-ncxinframachinetemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=ncxinframachinetemplates,verbs=create;update,versions=v1beta1,name=vncxinframachinetemplate.kb.io,admissionReviewVersions=v1
+
+// MachineTemplateResolver resolves the NVIDIA Carbide resources referenced by
+// the machines of a NcxInfraMachineTemplate, with the credentials of the
+// NcxInfraCluster the template is used with.
+// +kubebuilder:object:generate=false
+type MachineTemplateResolver interface {
+	// ResolveMachineSpec returns the errors of the instance type and SSH key
+	// groups of spec unknown to NVIDIA Carbide. It returns an error when
+	// NVIDIA Carbide cannot tell.
+	ResolveMachineSpec(
+		ctx context.Context, cluster *NcxInfraCluster, spec *NcxInfraMachineSpec, specPath *field.Path,
+	) (field.ErrorList, error)
+}
+
+// SetupWebhookWithManager registers the conversion and validation webhooks of
+// NcxInfraMachineTemplate. The resolver, when not nil, checks the instance
+// type and SSH key groups of the templates in NVIDIA Carbide.
+func (r *NcxInfraMachineTemplate) SetupWebhookWithManager(mgr ctrl.Manager, resolver MachineTemplateResolver) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&machineTemplateValidator{reader: mgr.GetAPIReader(), resolver: resolver}).
+		Complete()
+}
+
+// machineTemplateValidator validates the machines of the NcxInfraMachineTemplates
+// like NcxInfraMachine does, and resolves their references against the
+// NcxInfraCluster the template is used with, so that a broken template is
+// rejected before its MachineDeployment creates failing machines.
+//
+// The references are resolved on creation, and on the updates changing the
+// spec: the updates of the metadata, such as the owner references added by the
+// MachineDeployments and MachineSets, are never rejected. A template whose
+// Cluster or NcxInfraCluster does not exist yet is only validated on its own.
+type machineTemplateValidator struct {
+	reader   client.Reader
+	resolver MachineTemplateResolver
+}
+
+var _ webhook.CustomValidator = &machineTemplateValidator{}
+
+func (v *machineTemplateValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	template, ok := obj.(*NcxInfraMachineTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected NcxInfraMachineTemplate, got %T", obj)
+	}
+	return v.validateTemplate(ctx, template)
+}
+
+func (v *machineTemplateValidator) ValidateUpdate(
+	ctx context.Context, oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	oldTemplate, ok := oldObj.(*NcxInfraMachineTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected NcxInfraMachineTemplate, got %T", oldObj)
+	}
+	template, ok := newObj.(*NcxInfraMachineTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected NcxInfraMachineTemplate, got %T", newObj)
+	}
+	if equality.Semantic.DeepEqual(oldTemplate.Spec, template.Spec) {
+		return nil, nil
+	}
+	return v.validateTemplate(ctx, template)
+}
+
+func (v *machineTemplateValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *machineTemplateValidator) validateTemplate(
+	ctx context.Context, template *NcxInfraMachineTemplate,
+) (admission.Warnings, error) {
+	specPath := field.NewPath("spec", "template", "spec")
+	spec := &template.Spec.Template.Spec
+	allErrs := validateMachineSpec(spec, specPath)
+	if len(allErrs) > 0 {
+		return nil, allErrs.ToAggregate()
+	}
+
+	cluster, err := templateCluster(ctx, v.reader, template)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	if cluster == nil {
+		return nil, nil
+	}
+	networkErrs, err := validateMachineNetwork(ctx, v.reader, cluster, spec, specPath)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, networkErrs...)
+
+	var warnings admission.Warnings
+	if v.resolver != nil {
+		resolveErrs, err := v.resolver.ResolveMachineSpec(ctx, cluster, spec, specPath)
+		if err != nil {
+			// An unavailable API does not block the templates, the machines
+			// report the references they cannot resolve
+			warnings = append(warnings, fmt.Sprintf(
+				"the instance type and SSH key groups were not checked in NVIDIA Carbide: %v", err))
+		}
+		allErrs = append(allErrs, resolveErrs...)
+	}
+	return warnings, allErrs.ToAggregate()
+}
+
+// templateCluster returns the NcxInfraCluster of the Cluster a
+// NcxInfraMachineTemplate is used with: its owner Cluster, added by the
+// MachineDeployments, or the Cluster of its cluster name label, set by the
+// ClusterClasses and the cluster templates. It returns nil when the template
+// is not used by a Cluster yet, or when the Cluster or its NcxInfraCluster
+// does not exist yet.
+func templateCluster(
+	ctx context.Context, reader client.Reader, template *NcxInfraMachineTemplate,
+) (*NcxInfraCluster, error) {
+	clusterName := template.Labels[clusterv1.ClusterNameLabel]
+	for _, ref := range template.OwnerReferences {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err == nil && ref.Kind == "Cluster" && gv.Group == clusterv1.GroupVersion.Group {
+			clusterName = ref.Name
+			break
+		}
+	}
+	if clusterName == "" {
+		return nil, nil
+	}
+
+	cluster := &clusterv1.Cluster{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: template.Namespace, Name: clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get Cluster %s: %w", clusterName, err)
+	}
+	if cluster.Spec.InfrastructureRef.Kind != "NcxInfraCluster" {
+		return nil, nil
+	}
+	ncxInfraCluster := &NcxInfraCluster{}
+	key := client.ObjectKey{Namespace: template.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := reader.Get(ctx, key, ncxInfraCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get NcxInfraCluster %s: %w", key.Name, err)
+	}
+	return ncxInfraCluster, nil
+}
+
+// validateMachineNetwork checks that the subnets and VPC prefixes of the
+// interfaces of a machine are declared by their NcxInfraCluster. The
+// interfaces referencing another cluster that does not exist are left to the
+// machines, which report them.
+func validateMachineNetwork(
+	ctx context.Context, reader client.Reader, cluster *NcxInfraCluster, spec *NcxInfraMachineSpec,
+	specPath *field.Path,
+) (field.ErrorList, error) {
+	networkPath := specPath.Child("network")
+	allErrs := validateClusterNetworkRef(cluster, spec.Network.SubnetName, spec.Network.VPCPrefixName, networkPath)
+
+	for i, iface := range spec.Network.AdditionalInterfaces {
+		ifaceCluster := cluster
+		if iface.ClusterRef != nil {
+			ifaceCluster = &NcxInfraCluster{}
+			key := client.ObjectKey{Namespace: cluster.Namespace, Name: iface.ClusterRef.Name}
+			if err := reader.Get(ctx, key, ifaceCluster); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get NcxInfraCluster %s: %w", key.Name, err)
+			}
+		}
+		allErrs = append(allErrs, validateClusterNetworkRef(ifaceCluster, iface.SubnetName, iface.VPCPrefixName,
+			networkPath.Child("additionalInterfaces").Index(i))...)
+	}
+	return allErrs, nil
+}
+
+// validateClusterNetworkRef checks that a subnet or VPC prefix name is
+// declared by a NcxInfraCluster.
+func validateClusterNetworkRef(
+	cluster *NcxInfraCluster, subnetName, vpcPrefixName string, ifacePath *field.Path,
+) field.ErrorList {
+	switch {
+	case subnetName != "":
+		for _, subnet := range cluster.Spec.Subnets {
+			if subnet.Name == subnetName {
+				return nil
+			}
+		}
+		return field.ErrorList{field.Invalid(ifacePath.Child("subnetName"), subnetName,
+			fmt.Sprintf("not a subnet of NcxInfraCluster %s", cluster.Name))}
+	case vpcPrefixName != "":
+		for _, prefix := range cluster.Spec.VPCPrefixes {
+			if prefix.Name == vpcPrefixName {
+				return nil
+			}
+		}
+		return field.ErrorList{field.Invalid(ifacePath.Child("vpcPrefixName"), vpcPrefixName,
+			fmt.Sprintf("not a VPC prefix of NcxInfraCluster %s", cluster.Name))}
+	}
+	return nil
+}
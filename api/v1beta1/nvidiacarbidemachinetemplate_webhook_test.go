@@ -0,0 +1,230 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeTemplateResolver reports the instance types of unknown as not found
+type fakeTemplateResolver struct {
+	unknown map[string]bool
+	err     error
+}
+
+func (r *fakeTemplateResolver) ResolveMachineSpec(
+	_ context.Context, _ *NcxInfraCluster, spec *NcxInfraMachineSpec, specPath *field.Path,
+) (field.ErrorList, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.unknown[spec.InstanceType.ID] {
+		return field.ErrorList{field.NotFound(specPath.Child("instanceType", "id"), spec.InstanceType.ID)}, nil
+	}
+	return nil, nil
+}
+
+func newTemplateValidator(t *testing.T, resolver MachineTemplateResolver) *machineTemplateValidator {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	if err := clusterv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	objects := []client.Object{
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+				APIGroup: GroupVersion.Group, Kind: "NcxInfraCluster", Name: "test-infra",
+			}},
+		},
+		&NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-infra", Namespace: "default"},
+			Spec: NcxInfraClusterSpec{
+				Subnets:     []SubnetSpec{{Name: "control-plane"}, {Name: "worker"}},
+				VPCPrefixes: []VPCPrefixSpec{{Name: "physical"}},
+			},
+		},
+		&NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "storage", Namespace: "default"},
+			Spec:       NcxInfraClusterSpec{Subnets: []SubnetSpec{{Name: "storage"}}},
+		},
+	}
+	return &machineTemplateValidator{
+		reader:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		resolver: resolver,
+	}
+}
+
+func newTestTemplate(network NetworkSpec) *NcxInfraMachineTemplate {
+	return &NcxInfraMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-workers",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "test"},
+		},
+		Spec: NcxInfraMachineTemplateSpec{Template: NcxInfraMachineTemplateResource{
+			Spec: NcxInfraMachineSpec{
+				InstanceType: InstanceTypeSpec{ID: "instance-type-uuid"},
+				Network:      network,
+			},
+		}},
+	}
+}
+
+func TestMachineTemplateWebhook_ClusterNetwork(t *testing.T) {
+	v := newTemplateValidator(t, nil)
+	tests := []struct {
+		name      string
+		network   NetworkSpec
+		wantField string
+	}{
+		{name: "subnet of the cluster", network: NetworkSpec{SubnetName: "worker"}},
+		{name: "VPC prefix of the cluster", network: NetworkSpec{VPCPrefixName: "physical"}},
+		{
+			name:      "unknown subnet",
+			network:   NetworkSpec{SubnetName: "workers"},
+			wantField: "spec.template.spec.network.subnetName",
+		},
+		{
+			name:      "unknown VPC prefix",
+			network:   NetworkSpec{VPCPrefixName: "fnn"},
+			wantField: "spec.template.spec.network.vpcPrefixName",
+		},
+		{
+			name: "subnet of another cluster",
+			network: NetworkSpec{SubnetName: "worker", AdditionalInterfaces: []NetworkInterface{{
+				SubnetName: "storage", ClusterRef: &corev1.LocalObjectReference{Name: "storage"},
+			}}},
+		},
+		{
+			name: "subnet missing from another cluster",
+			network: NetworkSpec{SubnetName: "worker", AdditionalInterfaces: []NetworkInterface{{
+				SubnetName: "worker", ClusterRef: &corev1.LocalObjectReference{Name: "storage"},
+			}}},
+			wantField: "spec.template.spec.network.additionalInterfaces[0].subnetName",
+		},
+		{
+			name: "missing cluster left to the machines",
+			network: NetworkSpec{SubnetName: "worker", AdditionalInterfaces: []NetworkInterface{{
+				SubnetName: "storage", ClusterRef: &corev1.LocalObjectReference{Name: "missing"},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.ValidateCreate(context.Background(), newTestTemplate(tt.network))
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("ValidateCreate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantField) {
+				t.Errorf("ValidateCreate() error = %v, want an error on %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestMachineTemplateWebhook_ClusterReference(t *testing.T) {
+	v := newTemplateValidator(t, nil)
+	network := NetworkSpec{SubnetName: "workers"}
+
+	// The machine spec is validated without cluster
+	invalid := newTestTemplate(network)
+	invalid.Labels = nil
+	invalid.Spec.Template.Spec.InstanceType.ID = ""
+	if _, err := v.ValidateCreate(context.Background(), invalid); err == nil {
+		t.Error("ValidateCreate() accepted a template without instance type")
+	}
+
+	unused := newTestTemplate(network)
+	unused.Labels = nil
+	if _, err := v.ValidateCreate(context.Background(), unused); err != nil {
+		t.Errorf("ValidateCreate() error = %v for a template not used by a Cluster", err)
+	}
+
+	missing := newTestTemplate(network)
+	missing.Labels[clusterv1.ClusterNameLabel] = "missing"
+	if _, err := v.ValidateCreate(context.Background(), missing); err != nil {
+		t.Errorf("ValidateCreate() error = %v for the template of a Cluster not created yet", err)
+	}
+
+	owned := newTestTemplate(network)
+	owned.Labels = nil
+	owned.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "test", UID: "cluster-uid",
+	}}
+	if _, err := v.ValidateCreate(context.Background(), owned); err == nil {
+		t.Error("ValidateCreate() accepted an unknown subnet of the owner Cluster")
+	}
+}
+
+func TestMachineTemplateWebhook_Resolver(t *testing.T) {
+	template := newTestTemplate(NetworkSpec{SubnetName: "worker"})
+
+	v := newTemplateValidator(t, &fakeTemplateResolver{unknown: map[string]bool{"instance-type-uuid": true}})
+	_, err := v.ValidateCreate(context.Background(), template)
+	if err == nil || !strings.Contains(err.Error(), "spec.template.spec.instanceType.id") {
+		t.Errorf("ValidateCreate() error = %v, want an unknown instance type", err)
+	}
+
+	// An unavailable API warns without blocking the template
+	v = newTemplateValidator(t, &fakeTemplateResolver{err: errors.New("connection refused")})
+	warnings, err := v.ValidateCreate(context.Background(), template)
+	if err != nil {
+		t.Errorf("ValidateCreate() error = %v, want nil", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "connection refused") {
+		t.Errorf("ValidateCreate() warnings = %v, want the API error", warnings)
+	}
+}
+
+func TestMachineTemplateWebhook_MetadataUpdate(t *testing.T) {
+	v := newTemplateValidator(t, &fakeTemplateResolver{unknown: map[string]bool{"instance-type-uuid": true}})
+	oldTemplate := newTestTemplate(NetworkSpec{SubnetName: "workers"})
+	oldTemplate.Labels = nil
+
+	// The owner reference added by a MachineDeployment is not rejected
+	newTemplate := oldTemplate.DeepCopy()
+	newTemplate.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "test", UID: "cluster-uid",
+	}}
+	if _, err := v.ValidateUpdate(context.Background(), oldTemplate, newTemplate); err != nil {
+		t.Errorf("ValidateUpdate() error = %v for a metadata update", err)
+	}
+
+	changed := newTemplate.DeepCopy()
+	changed.Spec.Template.Spec.Network.SubnetName = "worker"
+	if _, err := v.ValidateUpdate(context.Background(), newTemplate, changed); err == nil {
+		t.Error("ValidateUpdate() accepted a spec update with an unknown instance type")
+	}
+}
@@ -21,16 +21,62 @@ limitations under the License.
 package v1beta1
 
 import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedNamespaces) DeepCopyInto(out *AllowedNamespaces) {
+	*out = *in
+	if in.NamesList != nil {
+		in, out := &in.NamesList, &out.NamesList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedNamespaces.
+func (in *AllowedNamespaces) DeepCopy() *AllowedNamespaces {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedNamespaces)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AntiAffinitySpec) DeepCopyInto(out *AntiAffinitySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntiAffinitySpec.
+func (in *AntiAffinitySpec) DeepCopy() *AntiAffinitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AntiAffinitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthenticationSpec) DeepCopyInto(out *AuthenticationSpec) {
 	*out = *in
 	out.SecretRef = in.SecretRef
+	if in.IdentityRef != nil {
+		in, out := &in.IdentityRef, &out.IdentityRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthenticationSpec.
@@ -43,6 +89,76 @@ func (in *AuthenticationSpec) DeepCopy() *AuthenticationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BreakGlassSSHStatus) DeepCopyInto(out *BreakGlassSSHStatus) {
+	*out = *in
+	if in.SiteIDs != nil {
+		in, out := &in.SiteIDs, &out.SiteIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BreakGlassSSHStatus.
+func (in *BreakGlassSSHStatus) DeepCopy() *BreakGlassSSHStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BreakGlassSSHStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleStatus) DeepCopyInto(out *ConsoleStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleStatus.
+func (in *ConsoleStatus) DeepCopy() *ConsoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPOptions) DeepCopyInto(out *DHCPOptions) {
+	*out = *in
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchDomains != nil {
+		in, out := &in.SearchDomains, &out.SearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NTPServers != nil {
+		in, out := &in.NTPServers, &out.NTPServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPOptions.
+func (in *DHCPOptions) DeepCopy() *DHCPOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DPUExtensionServiceSpec) DeepCopyInto(out *DPUExtensionServiceSpec) {
 	*out = *in
@@ -58,6 +174,162 @@ func (in *DPUExtensionServiceSpec) DeepCopy() *DPUExtensionServiceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionSpec) DeepCopyInto(out *DeletionSpec) {
+	*out = *in
+	if in.HealthIssue != nil {
+		in, out := &in.HealthIssue, &out.HealthIssue
+		*out = new(MachineHealthIssueSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionSpec.
+func (in *DeletionSpec) DeepCopy() *DeletionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUConfigSpec) DeepCopyInto(out *GPUConfigSpec) {
+	*out = *in
+	if in.MIG != nil {
+		in, out := &in.MIG, &out.MIG
+		*out = make([]MIGSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUConfigSpec.
+func (in *GPUConfigSpec) DeepCopy() *GPUConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUOperatorSpec) DeepCopyInto(out *GPUOperatorSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUOperatorSpec.
+func (in *GPUOperatorSpec) DeepCopy() *GPUOperatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUOperatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareComponent) DeepCopyInto(out *HardwareComponent) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareComponent.
+func (in *HardwareComponent) DeepCopy() *HardwareComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareInterface) DeepCopyInto(out *HardwareInterface) {
+	*out = *in
+	if in.NUMANode != nil {
+		in, out := &in.NUMANode, &out.NUMANode
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareInterface.
+func (in *HardwareInterface) DeepCopy() *HardwareInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareStatus) DeepCopyInto(out *HardwareStatus) {
+	*out = *in
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make([]HardwareInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InfiniBandInterfaces != nil {
+		in, out := &in.InfiniBandInterfaces, &out.InfiniBandInterfaces
+		*out = make([]HardwareInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Slot != nil {
+		in, out := &in.Slot, &out.Slot
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareStatus.
+func (in *HardwareStatus) DeepCopy() *HardwareStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HugePagesSpec) DeepCopyInto(out *HugePagesSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugePagesSpec.
+func (in *HugePagesSpec) DeepCopy() *HugePagesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HugePagesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPBlockStatus) DeepCopyInto(out *IPBlockStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPBlockStatus.
+func (in *IPBlockStatus) DeepCopy() *IPBlockStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IPBlockStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InfiniBandInterfaceSpec) DeepCopyInto(out *InfiniBandInterfaceSpec) {
 	*out = *in
@@ -94,62 +366,1471 @@ func (in *InstanceTypeSpec) DeepCopy() *InstanceTypeSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSGRule) DeepCopyInto(out *NSGRule) {
+func (in *InventorySpec) DeepCopyInto(out *InventorySpec) {
 	*out = *in
+	if in.LabelKeys != nil {
+		in, out := &in.LabelKeys, &out.LabelKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSGRule.
-func (in *NSGRule) DeepCopy() *NSGRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventorySpec.
+func (in *InventorySpec) DeepCopy() *InventorySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NSGRule)
+	out := new(InventorySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NSGSpec) DeepCopyInto(out *NSGSpec) {
+func (in *LoadBalancerServiceStatus) DeepCopyInto(out *LoadBalancerServiceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerServiceStatus.
+func (in *LoadBalancerServiceStatus) DeepCopy() *LoadBalancerServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MIGSpec) DeepCopyInto(out *MIGSpec) {
+	*out = *in
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MIGSpec.
+func (in *MIGSpec) DeepCopy() *MIGSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MIGSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineHealthIssueSpec) DeepCopyInto(out *MachineHealthIssueSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineHealthIssueSpec.
+func (in *MachineHealthIssueSpec) DeepCopy() *MachineHealthIssueSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineHealthIssueSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedControlPlaneSpec) DeepCopyInto(out *ManagedControlPlaneSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedControlPlaneSpec.
+func (in *ManagedControlPlaneSpec) DeepCopy() *ManagedControlPlaneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedControlPlaneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedGPUSpec) DeepCopyInto(out *ManagedGPUSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedGPUSpec.
+func (in *ManagedGPUSpec) DeepCopy() *ManagedGPUSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedGPUSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedNetworkSpec) DeepCopyInto(out *ManagedNetworkSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedNetworkSpec.
+func (in *ManagedNetworkSpec) DeepCopy() *ManagedNetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedNetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedWorkerPool) DeepCopyInto(out *ManagedWorkerPool) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.GPU != nil {
+		in, out := &in.GPU, &out.GPU
+		*out = new(ManagedGPUSpec)
+		**out = **in
+	}
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedWorkerPool.
+func (in *ManagedWorkerPool) DeepCopy() *ManagedWorkerPool {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedWorkerPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSGRule) DeepCopyInto(out *NSGRule) {
+	*out = *in
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSGRule.
+func (in *NSGRule) DeepCopy() *NSGRule {
+	if in == nil {
+		return nil
+	}
+	out := new(NSGRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSGSpec) DeepCopyInto(out *NSGSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]NSGRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSGSpec.
+func (in *NSGSpec) DeepCopy() *NSGSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NSGSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSGStatus) DeepCopyInto(out *NSGStatus) {
+	*out = *in
+	out.NetworkResourceStatus = in.NetworkResourceStatus
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSGStatus.
+func (in *NSGStatus) DeepCopy() *NSGStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NSGStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NVLinkInterfaceSpec) DeepCopyInto(out *NVLinkInterfaceSpec) {
+	*out = *in
+	if in.DeviceInstance != nil {
+		in, out := &in.DeviceInstance, &out.DeviceInstance
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NVLinkInterfaceSpec.
+func (in *NVLinkInterfaceSpec) DeepCopy() *NVLinkInterfaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NVLinkInterfaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraCluster) DeepCopyInto(out *NcxInfraCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraCluster.
+func (in *NcxInfraCluster) DeepCopy() *NcxInfraCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterIdentity) DeepCopyInto(out *NcxInfraClusterIdentity) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterIdentity.
+func (in *NcxInfraClusterIdentity) DeepCopy() *NcxInfraClusterIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraClusterIdentity) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterIdentityList) DeepCopyInto(out *NcxInfraClusterIdentityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraClusterIdentity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterIdentityList.
+func (in *NcxInfraClusterIdentityList) DeepCopy() *NcxInfraClusterIdentityList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterIdentityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraClusterIdentityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterIdentitySpec) DeepCopyInto(out *NcxInfraClusterIdentitySpec) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = new(AllowedNamespaces)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterIdentitySpec.
+func (in *NcxInfraClusterIdentitySpec) DeepCopy() *NcxInfraClusterIdentitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterIdentitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterList) DeepCopyInto(out *NcxInfraClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterList.
+func (in *NcxInfraClusterList) DeepCopy() *NcxInfraClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterSpec) DeepCopyInto(out *NcxInfraClusterSpec) {
+	*out = *in
+	out.SiteRef = in.SiteRef
+	in.VPC.DeepCopyInto(&out.VPC)
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]SubnetSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VPCPrefixes != nil {
+		in, out := &in.VPCPrefixes, &out.VPCPrefixes
+		*out = make([]VPCPrefixSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.VPCPeerings != nil {
+		in, out := &in.VPCPeerings, &out.VPCPeerings
+		*out = make([]VPCPeeringSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(NetworkServices)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceLabels != nil {
+		in, out := &in.InstanceLabels, &out.InstanceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.WarmPool != nil {
+		in, out := &in.WarmPool, &out.WarmPool
+		*out = new(WarmPoolSpec)
+		**out = **in
+	}
+	if in.Provisioning != nil {
+		in, out := &in.Provisioning, &out.Provisioning
+		*out = new(ProvisioningSpec)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControlPlaneEndpoint != nil {
+		in, out := &in.ControlPlaneEndpoint, &out.ControlPlaneEndpoint
+		*out = new(v1beta2.APIEndpoint)
+		**out = **in
+	}
+	in.Authentication.DeepCopyInto(&out.Authentication)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterSpec.
+func (in *NcxInfraClusterSpec) DeepCopy() *NcxInfraClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterStatus) DeepCopyInto(out *NcxInfraClusterStatus) {
+	*out = *in
+	in.NetworkStatus.DeepCopyInto(&out.NetworkStatus)
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.ControlPlaneEndpoint != nil {
+		in, out := &in.ControlPlaneEndpoint, &out.ControlPlaneEndpoint
+		*out = new(v1beta2.APIEndpoint)
+		**out = **in
+	}
+	if in.BreakGlassSSH != nil {
+		in, out := &in.BreakGlassSSH, &out.BreakGlassSSH
+		*out = new(BreakGlassSSHStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]QuotaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.ClusterStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]v1beta2.FailureDomain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterStatus.
+func (in *NcxInfraClusterStatus) DeepCopy() *NcxInfraClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterTemplate) DeepCopyInto(out *NcxInfraClusterTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterTemplate.
+func (in *NcxInfraClusterTemplate) DeepCopy() *NcxInfraClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraClusterTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterTemplateList) DeepCopyInto(out *NcxInfraClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterTemplateList.
+func (in *NcxInfraClusterTemplateList) DeepCopy() *NcxInfraClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraClusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterTemplateResource) DeepCopyInto(out *NcxInfraClusterTemplateResource) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterTemplateResource.
+func (in *NcxInfraClusterTemplateResource) DeepCopy() *NcxInfraClusterTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterTemplateSpec) DeepCopyInto(out *NcxInfraClusterTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterTemplateSpec.
+func (in *NcxInfraClusterTemplateSpec) DeepCopy() *NcxInfraClusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraIdentity) DeepCopyInto(out *NcxInfraIdentity) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraIdentity.
+func (in *NcxInfraIdentity) DeepCopy() *NcxInfraIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraIdentity) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraIdentityList) DeepCopyInto(out *NcxInfraIdentityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraIdentity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraIdentityList.
+func (in *NcxInfraIdentityList) DeepCopy() *NcxInfraIdentityList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraIdentityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraIdentityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraIdentitySpec) DeepCopyInto(out *NcxInfraIdentitySpec) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraIdentitySpec.
+func (in *NcxInfraIdentitySpec) DeepCopy() *NcxInfraIdentitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraIdentitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraInstanceType) DeepCopyInto(out *NcxInfraInstanceType) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraInstanceType.
+func (in *NcxInfraInstanceType) DeepCopy() *NcxInfraInstanceType {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraInstanceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraInstanceType) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraInstanceTypeList) DeepCopyInto(out *NcxInfraInstanceTypeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraInstanceType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraInstanceTypeList.
+func (in *NcxInfraInstanceTypeList) DeepCopy() *NcxInfraInstanceTypeList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraInstanceTypeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraInstanceTypeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraInstanceTypeSpec) DeepCopyInto(out *NcxInfraInstanceTypeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraInstanceTypeSpec.
+func (in *NcxInfraInstanceTypeSpec) DeepCopy() *NcxInfraInstanceTypeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraInstanceTypeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraInstanceTypeStatus) DeepCopyInto(out *NcxInfraInstanceTypeStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]HardwareComponent, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastDiscoveryTime != nil {
+		in, out := &in.LastDiscoveryTime, &out.LastDiscoveryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraInstanceTypeStatus.
+func (in *NcxInfraInstanceTypeStatus) DeepCopy() *NcxInfraInstanceTypeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraInstanceTypeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraLoadBalancer) DeepCopyInto(out *NcxInfraLoadBalancer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraLoadBalancer.
+func (in *NcxInfraLoadBalancer) DeepCopy() *NcxInfraLoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraLoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraLoadBalancer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraLoadBalancerList) DeepCopyInto(out *NcxInfraLoadBalancerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraLoadBalancer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraLoadBalancerList.
+func (in *NcxInfraLoadBalancerList) DeepCopy() *NcxInfraLoadBalancerList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraLoadBalancerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraLoadBalancerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraLoadBalancerSpec) DeepCopyInto(out *NcxInfraLoadBalancerSpec) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraLoadBalancerSpec.
+func (in *NcxInfraLoadBalancerSpec) DeepCopy() *NcxInfraLoadBalancerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraLoadBalancerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraLoadBalancerStatus) DeepCopyInto(out *NcxInfraLoadBalancerStatus) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]LoadBalancerServiceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraLoadBalancerStatus.
+func (in *NcxInfraLoadBalancerStatus) DeepCopy() *NcxInfraLoadBalancerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraLoadBalancerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachine) DeepCopyInto(out *NcxInfraMachine) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachine.
+func (in *NcxInfraMachine) DeepCopy() *NcxInfraMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraMachine) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineList) DeepCopyInto(out *NcxInfraMachineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineList.
+func (in *NcxInfraMachineList) DeepCopy() *NcxInfraMachineList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraMachineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineSpec) DeepCopyInto(out *NcxInfraMachineSpec) {
+	*out = *in
+	if in.ProviderID != nil {
+		in, out := &in.ProviderID, &out.ProviderID
+		*out = new(string)
+		**out = **in
+	}
+	out.InstanceType = in.InstanceType
+	if in.OperatingSystem != nil {
+		in, out := &in.OperatingSystem, &out.OperatingSystem
+		*out = new(OSSpec)
+		**out = **in
+	}
+	in.Network.DeepCopyInto(&out.Network)
+	if in.SSHKeyGroups != nil {
+		in, out := &in.SSHKeyGroups, &out.SSHKeyGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InfiniBandInterfaces != nil {
+		in, out := &in.InfiniBandInterfaces, &out.InfiniBandInterfaces
+		*out = make([]InfiniBandInterfaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NVLinkInterfaces != nil {
+		in, out := &in.NVLinkInterfaces, &out.NVLinkInterfaces
+		*out = make([]NVLinkInterfaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DPUExtensionServices != nil {
+		in, out := &in.DPUExtensionServices, &out.DPUExtensionServices
+		*out = make([]DPUExtensionServiceSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PhoneHomeEnabled != nil {
+		in, out := &in.PhoneHomeEnabled, &out.PhoneHomeEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(DeletionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = new(InventorySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(SecuritySpec)
+		**out = **in
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GPUConfig != nil {
+		in, out := &in.GPUConfig, &out.GPUConfig
+		*out = new(GPUConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KernelArgs != nil {
+		in, out := &in.KernelArgs, &out.KernelArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(TuningSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineSpec.
+func (in *NcxInfraMachineSpec) DeepCopy() *NcxInfraMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineStatus) DeepCopyInto(out *NcxInfraMachineStatus) {
+	*out = *in
+	if in.InstanceOrigin != nil {
+		in, out := &in.InstanceOrigin, &out.InstanceOrigin
+		*out = new(ResourceOrigin)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderID != nil {
+		in, out := &in.ProviderID, &out.ProviderID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]v1beta2.MachineAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PlacementStatus)
+		**out = **in
+	}
+	if in.LastPowerAction != nil {
+		in, out := &in.LastPowerAction, &out.LastPowerAction
+		*out = new(PowerActionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Console != nil {
+		in, out := &in.Console, &out.Console
+		*out = new(ConsoleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Provisioned != nil {
+		in, out := &in.Provisioned, &out.Provisioned
+		*out = new(ProvisionedStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SRIOVInterfaces != nil {
+		in, out := &in.SRIOVInterfaces, &out.SRIOVInterfaces
+		*out = make([]SRIOVInterfaceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hardware != nil {
+		in, out := &in.Hardware, &out.Hardware
+		*out = new(HardwareStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineStatus.
+func (in *NcxInfraMachineStatus) DeepCopy() *NcxInfraMachineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineTemplate) DeepCopyInto(out *NcxInfraMachineTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplate.
+func (in *NcxInfraMachineTemplate) DeepCopy() *NcxInfraMachineTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraMachineTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineTemplateList) DeepCopyInto(out *NcxInfraMachineTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraMachineTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateList.
+func (in *NcxInfraMachineTemplateList) DeepCopy() *NcxInfraMachineTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraMachineTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineTemplateResource) DeepCopyInto(out *NcxInfraMachineTemplateResource) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateResource.
+func (in *NcxInfraMachineTemplateResource) DeepCopy() *NcxInfraMachineTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineTemplateSpec) DeepCopyInto(out *NcxInfraMachineTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateSpec.
+func (in *NcxInfraMachineTemplateSpec) DeepCopy() *NcxInfraMachineTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineTemplateStatus) DeepCopyInto(out *NcxInfraMachineTemplateStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateStatus.
+func (in *NcxInfraMachineTemplateStatus) DeepCopy() *NcxInfraMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraManagedCluster) DeepCopyInto(out *NcxInfraManagedCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraManagedCluster.
+func (in *NcxInfraManagedCluster) DeepCopy() *NcxInfraManagedCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraManagedCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraManagedCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraManagedClusterList) DeepCopyInto(out *NcxInfraManagedClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraManagedCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraManagedClusterList.
+func (in *NcxInfraManagedClusterList) DeepCopy() *NcxInfraManagedClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraManagedClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraManagedClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraManagedClusterSpec) DeepCopyInto(out *NcxInfraManagedClusterSpec) {
+	*out = *in
+	out.SiteRef = in.SiteRef
+	out.ControlPlane = in.ControlPlane
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = make([]ManagedWorkerPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Network = in.Network
+	if in.SSHKeyGroups != nil {
+		in, out := &in.SSHKeyGroups, &out.SSHKeyGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GPUOperator != nil {
+		in, out := &in.GPUOperator, &out.GPUOperator
+		*out = new(GPUOperatorSpec)
+		**out = **in
+	}
+	in.Authentication.DeepCopyInto(&out.Authentication)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraManagedClusterSpec.
+func (in *NcxInfraManagedClusterSpec) DeepCopy() *NcxInfraManagedClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraManagedClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraManagedClusterStatus) DeepCopyInto(out *NcxInfraManagedClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraManagedClusterStatus.
+func (in *NcxInfraManagedClusterStatus) DeepCopy() *NcxInfraManagedClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraManagedClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraNetworkSecurityGroup) DeepCopyInto(out *NcxInfraNetworkSecurityGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraNetworkSecurityGroup.
+func (in *NcxInfraNetworkSecurityGroup) DeepCopy() *NcxInfraNetworkSecurityGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraNetworkSecurityGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraNetworkSecurityGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraNetworkSecurityGroupList) DeepCopyInto(out *NcxInfraNetworkSecurityGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraNetworkSecurityGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraNetworkSecurityGroupList.
+func (in *NcxInfraNetworkSecurityGroupList) DeepCopy() *NcxInfraNetworkSecurityGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraNetworkSecurityGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraNetworkSecurityGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraNetworkSecurityGroupSpec) DeepCopyInto(out *NcxInfraNetworkSecurityGroupSpec) {
 	*out = *in
+	out.SiteRef = in.SiteRef
 	if in.Rules != nil {
 		in, out := &in.Rules, &out.Rules
 		*out = make([]NSGRule, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
+	in.Authentication.DeepCopyInto(&out.Authentication)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSGSpec.
-func (in *NSGSpec) DeepCopy() *NSGSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraNetworkSecurityGroupSpec.
+func (in *NcxInfraNetworkSecurityGroupSpec) DeepCopy() *NcxInfraNetworkSecurityGroupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NSGSpec)
+	out := new(NcxInfraNetworkSecurityGroupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NVLinkInterfaceSpec) DeepCopyInto(out *NVLinkInterfaceSpec) {
+func (in *NcxInfraNetworkSecurityGroupStatus) DeepCopyInto(out *NcxInfraNetworkSecurityGroupStatus) {
 	*out = *in
-	if in.DeviceInstance != nil {
-		in, out := &in.DeviceInstance, &out.DeviceInstance
-		*out = new(int32)
-		**out = **in
+	if in.Origin != nil {
+		in, out := &in.Origin, &out.Origin
+		*out = new(ResourceOrigin)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NVLinkInterfaceSpec.
-func (in *NVLinkInterfaceSpec) DeepCopy() *NVLinkInterfaceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraNetworkSecurityGroupStatus.
+func (in *NcxInfraNetworkSecurityGroupStatus) DeepCopy() *NcxInfraNetworkSecurityGroupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NVLinkInterfaceSpec)
+	out := new(NcxInfraNetworkSecurityGroupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraCluster) DeepCopyInto(out *NcxInfraCluster) {
+func (in *NcxInfraRemediation) DeepCopyInto(out *NcxInfraRemediation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -157,18 +1838,18 @@ func (in *NcxInfraCluster) DeepCopyInto(out *NcxInfraCluster) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraCluster.
-func (in *NcxInfraCluster) DeepCopy() *NcxInfraCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraRemediation.
+func (in *NcxInfraRemediation) DeepCopy() *NcxInfraRemediation {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraCluster)
+	out := new(NcxInfraRemediation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NcxInfraCluster) DeepCopyObject() runtime.Object {
+func (in *NcxInfraRemediation) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -176,31 +1857,31 @@ func (in *NcxInfraCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraClusterList) DeepCopyInto(out *NcxInfraClusterList) {
+func (in *NcxInfraRemediationList) DeepCopyInto(out *NcxInfraRemediationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NcxInfraCluster, len(*in))
+		*out = make([]NcxInfraRemediation, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterList.
-func (in *NcxInfraClusterList) DeepCopy() *NcxInfraClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraRemediationList.
+func (in *NcxInfraRemediationList) DeepCopy() *NcxInfraRemediationList {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraClusterList)
+	out := new(NcxInfraRemediationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NcxInfraClusterList) DeepCopyObject() runtime.Object {
+func (in *NcxInfraRemediationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -208,98 +1889,71 @@ func (in *NcxInfraClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraClusterSpec) DeepCopyInto(out *NcxInfraClusterSpec) {
+func (in *NcxInfraRemediationSpec) DeepCopyInto(out *NcxInfraRemediationSpec) {
 	*out = *in
-	out.SiteRef = in.SiteRef
-	in.VPC.DeepCopyInto(&out.VPC)
-	if in.Subnets != nil {
-		in, out := &in.Subnets, &out.Subnets
-		*out = make([]SubnetSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.VPCPrefixes != nil {
-		in, out := &in.VPCPrefixes, &out.VPCPrefixes
-		*out = make([]VPCPrefixSpec, len(*in))
-		copy(*out, *in)
-	}
-	if in.VPCPeerings != nil {
-		in, out := &in.VPCPeerings, &out.VPCPeerings
-		*out = make([]VPCPeeringSpec, len(*in))
-		copy(*out, *in)
-	}
-	if in.ControlPlaneEndpoint != nil {
-		in, out := &in.ControlPlaneEndpoint, &out.ControlPlaneEndpoint
-		*out = new(v1beta2.APIEndpoint)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	out.Authentication = in.Authentication
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterSpec.
-func (in *NcxInfraClusterSpec) DeepCopy() *NcxInfraClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraRemediationSpec.
+func (in *NcxInfraRemediationSpec) DeepCopy() *NcxInfraRemediationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraClusterSpec)
+	out := new(NcxInfraRemediationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraClusterStatus) DeepCopyInto(out *NcxInfraClusterStatus) {
+func (in *NcxInfraRemediationStatus) DeepCopyInto(out *NcxInfraRemediationStatus) {
 	*out = *in
-	in.NetworkStatus.DeepCopyInto(&out.NetworkStatus)
-	if in.FailureReason != nil {
-		in, out := &in.FailureReason, &out.FailureReason
-		*out = new(errors.ClusterStatusError)
-		**out = **in
-	}
-	if in.FailureMessage != nil {
-		in, out := &in.FailureMessage, &out.FailureMessage
-		*out = new(string)
-		**out = **in
+	if in.LastRemediated != nil {
+		in, out := &in.LastRemediated, &out.LastRemediated
+		*out = (*in).DeepCopy()
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterStatus.
-func (in *NcxInfraClusterStatus) DeepCopy() *NcxInfraClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraRemediationStatus.
+func (in *NcxInfraRemediationStatus) DeepCopy() *NcxInfraRemediationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraClusterStatus)
+	out := new(NcxInfraRemediationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraClusterTemplate) DeepCopyInto(out *NcxInfraClusterTemplate) {
+func (in *NcxInfraRemediationTemplate) DeepCopyInto(out *NcxInfraRemediationTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterTemplate.
-func (in *NcxInfraClusterTemplate) DeepCopy() *NcxInfraClusterTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraRemediationTemplate.
+func (in *NcxInfraRemediationTemplate) DeepCopy() *NcxInfraRemediationTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraClusterTemplate)
+	out := new(NcxInfraRemediationTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NcxInfraClusterTemplate) DeepCopyObject() runtime.Object {
+func (in *NcxInfraRemediationTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -307,31 +1961,31 @@ func (in *NcxInfraClusterTemplate) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraClusterTemplateList) DeepCopyInto(out *NcxInfraClusterTemplateList) {
+func (in *NcxInfraRemediationTemplateList) DeepCopyInto(out *NcxInfraRemediationTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NcxInfraClusterTemplate, len(*in))
+		*out = make([]NcxInfraRemediationTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterTemplateList.
-func (in *NcxInfraClusterTemplateList) DeepCopy() *NcxInfraClusterTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraRemediationTemplateList.
+func (in *NcxInfraRemediationTemplateList) DeepCopy() *NcxInfraRemediationTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraClusterTemplateList)
+	out := new(NcxInfraRemediationTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NcxInfraClusterTemplateList) DeepCopyObject() runtime.Object {
+func (in *NcxInfraRemediationTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -339,59 +1993,58 @@ func (in *NcxInfraClusterTemplateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraClusterTemplateResource) DeepCopyInto(out *NcxInfraClusterTemplateResource) {
+func (in *NcxInfraRemediationTemplateResource) DeepCopyInto(out *NcxInfraRemediationTemplateResource) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterTemplateResource.
-func (in *NcxInfraClusterTemplateResource) DeepCopy() *NcxInfraClusterTemplateResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraRemediationTemplateResource.
+func (in *NcxInfraRemediationTemplateResource) DeepCopy() *NcxInfraRemediationTemplateResource {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraClusterTemplateResource)
+	out := new(NcxInfraRemediationTemplateResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraClusterTemplateSpec) DeepCopyInto(out *NcxInfraClusterTemplateSpec) {
+func (in *NcxInfraRemediationTemplateSpec) DeepCopyInto(out *NcxInfraRemediationTemplateSpec) {
 	*out = *in
 	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterTemplateSpec.
-func (in *NcxInfraClusterTemplateSpec) DeepCopy() *NcxInfraClusterTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraRemediationTemplateSpec.
+func (in *NcxInfraRemediationTemplateSpec) DeepCopy() *NcxInfraRemediationTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraClusterTemplateSpec)
+	out := new(NcxInfraRemediationTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraMachine) DeepCopyInto(out *NcxInfraMachine) {
+func (in *NcxInfraSite) DeepCopyInto(out *NcxInfraSite) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachine.
-func (in *NcxInfraMachine) DeepCopy() *NcxInfraMachine {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraSite.
+func (in *NcxInfraSite) DeepCopy() *NcxInfraSite {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraMachine)
+	out := new(NcxInfraSite)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NcxInfraMachine) DeepCopyObject() runtime.Object {
+func (in *NcxInfraSite) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -399,31 +2052,31 @@ func (in *NcxInfraMachine) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraMachineList) DeepCopyInto(out *NcxInfraMachineList) {
+func (in *NcxInfraSiteList) DeepCopyInto(out *NcxInfraSiteList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NcxInfraMachine, len(*in))
+		*out = make([]NcxInfraSite, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineList.
-func (in *NcxInfraMachineList) DeepCopy() *NcxInfraMachineList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraSiteList.
+func (in *NcxInfraSiteList) DeepCopy() *NcxInfraSiteList {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraMachineList)
+	out := new(NcxInfraSiteList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NcxInfraMachineList) DeepCopyObject() runtime.Object {
+func (in *NcxInfraSiteList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -431,283 +2084,472 @@ func (in *NcxInfraMachineList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraMachineSpec) DeepCopyInto(out *NcxInfraMachineSpec) {
+func (in *NcxInfraSiteSpec) DeepCopyInto(out *NcxInfraSiteSpec) {
 	*out = *in
-	if in.ProviderID != nil {
-		in, out := &in.ProviderID, &out.ProviderID
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraSiteSpec.
+func (in *NcxInfraSiteSpec) DeepCopy() *NcxInfraSiteSpec {
+	if in == nil {
+		return nil
 	}
-	out.InstanceType = in.InstanceType
-	if in.OperatingSystem != nil {
-		in, out := &in.OperatingSystem, &out.OperatingSystem
-		*out = new(OSSpec)
+	out := new(NcxInfraSiteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraSiteStatus) DeepCopyInto(out *NcxInfraSiteStatus) {
+	*out = *in
+	out.Location = in.Location
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraSiteStatus.
+func (in *NcxInfraSiteStatus) DeepCopy() *NcxInfraSiteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraSiteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
+	*out = *in
+	if in.ClusterRef != nil {
+		in, out := &in.ClusterRef, &out.ClusterRef
+		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
-	in.Network.DeepCopyInto(&out.Network)
-	if in.SSHKeyGroups != nil {
-		in, out := &in.SSHKeyGroups, &out.SSHKeyGroups
+	if in.SRIOV != nil {
+		in, out := &in.SRIOV, &out.SRIOV
+		*out = new(SRIOVSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VLAN != nil {
+		in, out := &in.VLAN, &out.VLAN
+		*out = new(VLANSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
+func (in *NetworkInterface) DeepCopy() *NetworkInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkResourceStatus) DeepCopyInto(out *NetworkResourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkResourceStatus.
+func (in *NetworkResourceStatus) DeepCopy() *NetworkResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkServices) DeepCopyInto(out *NetworkServices) {
+	*out = *in
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.InfiniBandInterfaces != nil {
-		in, out := &in.InfiniBandInterfaces, &out.InfiniBandInterfaces
-		*out = make([]InfiniBandInterfaceSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.SearchDomains != nil {
+		in, out := &in.SearchDomains, &out.SearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.NVLinkInterfaces != nil {
-		in, out := &in.NVLinkInterfaces, &out.NVLinkInterfaces
-		*out = make([]NVLinkInterfaceSpec, len(*in))
+	if in.NTPServers != nil {
+		in, out := &in.NTPServers, &out.NTPServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkServices.
+func (in *NetworkServices) DeepCopy() *NetworkServices {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkServices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+	*out = *in
+	if in.AdditionalInterfaces != nil {
+		in, out := &in.AdditionalInterfaces, &out.AdditionalInterfaces
+		*out = make([]NetworkInterface, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.DPUExtensionServices != nil {
-		in, out := &in.DPUExtensionServices, &out.DPUExtensionServices
-		*out = make([]DPUExtensionServiceSpec, len(*in))
+	in.NetworkServices.DeepCopyInto(&out.NetworkServices)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
+func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkStatus) DeepCopyInto(out *NetworkStatus) {
+	*out = *in
+	if in.VPC != nil {
+		in, out := &in.VPC, &out.VPC
+		*out = new(NetworkResourceStatus)
+		**out = **in
+	}
+	if in.SiteVPCs != nil {
+		in, out := &in.SiteVPCs, &out.SiteVPCs
+		*out = make([]NetworkResourceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPBlocks != nil {
+		in, out := &in.IPBlocks, &out.IPBlocks
+		*out = make([]IPBlockStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]NetworkResourceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.VPCPrefixes != nil {
+		in, out := &in.VPCPrefixes, &out.VPCPrefixes
+		*out = make([]NetworkResourceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.VPCPeerings != nil {
+		in, out := &in.VPCPeerings, &out.VPCPeerings
+		*out = make([]NetworkResourceStatus, len(*in))
 		copy(*out, *in)
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
+	if in.NSG != nil {
+		in, out := &in.NSG, &out.NSG
+		*out = new(NSGStatus)
+		**out = **in
+	}
+	if in.Origins != nil {
+		in, out := &in.Origins, &out.Origins
+		*out = make(map[string]ResourceOrigin, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.SubnetIDs != nil {
+		in, out := &in.SubnetIDs, &out.SubnetIDs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.VPCPrefixIDs != nil {
+		in, out := &in.VPCPrefixIDs, &out.VPCPrefixIDs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.VPCPeeringIDs != nil {
+		in, out := &in.VPCPeeringIDs, &out.VPCPeeringIDs
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.PhoneHomeEnabled != nil {
-		in, out := &in.PhoneHomeEnabled, &out.PhoneHomeEnabled
-		*out = new(bool)
-		**out = **in
+	if in.PublicIPBlocks != nil {
+		in, out := &in.PublicIPBlocks, &out.PublicIPBlocks
+		*out = make(map[string]IPBlockStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineSpec.
-func (in *NcxInfraMachineSpec) DeepCopy() *NcxInfraMachineSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkStatus.
+func (in *NetworkStatus) DeepCopy() *NetworkStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraMachineSpec)
+	out := new(NetworkStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraMachineStatus) DeepCopyInto(out *NcxInfraMachineStatus) {
+func (in *OSSpec) DeepCopyInto(out *OSSpec) {
 	*out = *in
-	if in.ProviderID != nil {
-		in, out := &in.ProviderID, &out.ProviderID
-		*out = new(string)
-		**out = **in
-	}
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]v1beta2.MachineAddress, len(*in))
-		copy(*out, *in)
-	}
-	if in.FailureReason != nil {
-		in, out := &in.FailureReason, &out.FailureReason
-		*out = new(errors.MachineStatusError)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSSpec.
+func (in *OSSpec) DeepCopy() *OSSpec {
+	if in == nil {
+		return nil
 	}
-	if in.FailureMessage != nil {
-		in, out := &in.FailureMessage, &out.FailureMessage
-		*out = new(string)
+	out := new(OSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
+	*out = *in
+	if in.AntiAffinity != nil {
+		in, out := &in.AntiAffinity, &out.AntiAffinity
+		*out = new(AntiAffinitySpec)
 		**out = **in
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineStatus.
-func (in *NcxInfraMachineStatus) DeepCopy() *NcxInfraMachineStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSpec.
+func (in *PlacementSpec) DeepCopy() *PlacementSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraMachineStatus)
+	out := new(PlacementSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraMachineTemplate) DeepCopyInto(out *NcxInfraMachineTemplate) {
+func (in *PlacementStatus) DeepCopyInto(out *PlacementStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplate.
-func (in *NcxInfraMachineTemplate) DeepCopy() *NcxInfraMachineTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementStatus.
+func (in *PlacementStatus) DeepCopy() *PlacementStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraMachineTemplate)
+	out := new(PlacementStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NcxInfraMachineTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerActionStatus) DeepCopyInto(out *PowerActionStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerActionStatus.
+func (in *PowerActionStatus) DeepCopy() *PowerActionStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(PowerActionStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraMachineTemplateList) DeepCopyInto(out *NcxInfraMachineTemplateList) {
+func (in *ProvisionedStatus) DeepCopyInto(out *ProvisionedStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]NcxInfraMachineTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LastReimageTime != nil {
+		in, out := &in.LastReimageTime, &out.LastReimageTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateList.
-func (in *NcxInfraMachineTemplateList) DeepCopy() *NcxInfraMachineTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisionedStatus.
+func (in *ProvisionedStatus) DeepCopy() *ProvisionedStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraMachineTemplateList)
+	out := new(ProvisionedStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NcxInfraMachineTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningSpec) DeepCopyInto(out *ProvisioningSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningSpec.
+func (in *ProvisioningSpec) DeepCopy() *ProvisioningSpec {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ProvisioningSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraMachineTemplateResource) DeepCopyInto(out *NcxInfraMachineTemplateResource) {
+func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateResource.
-func (in *NcxInfraMachineTemplateResource) DeepCopy() *NcxInfraMachineTemplateResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySpec.
+func (in *ProxySpec) DeepCopy() *ProxySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraMachineTemplateResource)
+	out := new(ProxySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NcxInfraMachineTemplateSpec) DeepCopyInto(out *NcxInfraMachineTemplateSpec) {
+func (in *QuotaStatus) DeepCopyInto(out *QuotaStatus) {
 	*out = *in
-	in.Template.DeepCopyInto(&out.Template)
+	out.Limit = in.Limit.DeepCopy()
+	out.Current = in.Current.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateSpec.
-func (in *NcxInfraMachineTemplateSpec) DeepCopy() *NcxInfraMachineTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaStatus.
+func (in *QuotaStatus) DeepCopy() *QuotaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NcxInfraMachineTemplateSpec)
+	out := new(QuotaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
+func (in *RAIDArraySpec) DeepCopyInto(out *RAIDArraySpec) {
 	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
-func (in *NetworkInterface) DeepCopy() *NetworkInterface {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAIDArraySpec.
+func (in *RAIDArraySpec) DeepCopy() *RAIDArraySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkInterface)
+	out := new(RAIDArraySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+func (in *ResourceOrigin) DeepCopyInto(out *ResourceOrigin) {
 	*out = *in
-	if in.AdditionalInterfaces != nil {
-		in, out := &in.AdditionalInterfaces, &out.AdditionalInterfaces
-		*out = make([]NetworkInterface, len(*in))
-		copy(*out, *in)
+	if in.Created != nil {
+		in, out := &in.Created, &out.Created
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
-func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceOrigin.
+func (in *ResourceOrigin) DeepCopy() *ResourceOrigin {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkSpec)
+	out := new(ResourceOrigin)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkStatus) DeepCopyInto(out *NetworkStatus) {
+func (in *SRIOVInterfaceStatus) DeepCopyInto(out *SRIOVInterfaceStatus) {
 	*out = *in
-	if in.SubnetIDs != nil {
-		in, out := &in.SubnetIDs, &out.SubnetIDs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.VirtualFunctions != nil {
+		in, out := &in.VirtualFunctions, &out.VirtualFunctions
+		*out = make([]VirtualFunctionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.VPCPrefixIDs != nil {
-		in, out := &in.VPCPrefixIDs, &out.VPCPrefixIDs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SRIOVInterfaceStatus.
+func (in *SRIOVInterfaceStatus) DeepCopy() *SRIOVInterfaceStatus {
+	if in == nil {
+		return nil
 	}
-	if in.VPCPeeringIDs != nil {
-		in, out := &in.VPCPeeringIDs, &out.VPCPeeringIDs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	out := new(SRIOVInterfaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SRIOVSpec) DeepCopyInto(out *SRIOVSpec) {
+	*out = *in
+	if in.DeviceInstance != nil {
+		in, out := &in.DeviceInstance, &out.DeviceInstance
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkStatus.
-func (in *NetworkStatus) DeepCopy() *NetworkStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SRIOVSpec.
+func (in *SRIOVSpec) DeepCopy() *SRIOVSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkStatus)
+	out := new(SRIOVSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OSSpec) DeepCopyInto(out *OSSpec) {
+func (in *SecuritySpec) DeepCopyInto(out *SecuritySpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSSpec.
-func (in *OSSpec) DeepCopy() *OSSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecuritySpec.
+func (in *SecuritySpec) DeepCopy() *SecuritySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OSSpec)
+	out := new(SecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteLocation) DeepCopyInto(out *SiteLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteLocation.
+func (in *SiteLocation) DeepCopy() *SiteLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteLocation)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -727,6 +2569,28 @@ func (in *SiteReference) DeepCopy() *SiteReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	if in.Arrays != nil {
+		in, out := &in.Arrays, &out.Arrays
+		*out = make([]RAIDArraySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 	*out = *in
@@ -737,6 +2601,16 @@ func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.DHCPOptions != nil {
+		in, out := &in.DHCPOptions, &out.DHCPOptions
+		*out = new(DHCPOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SiteRef != nil {
+		in, out := &in.SiteRef, &out.SiteRef
+		*out = new(SiteReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetSpec.
@@ -749,6 +2623,46 @@ func (in *SubnetSpec) DeepCopy() *SubnetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningSpec) DeepCopyInto(out *TuningSpec) {
+	*out = *in
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = make([]HugePagesSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningSpec.
+func (in *TuningSpec) DeepCopy() *TuningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VLANSpec) DeepCopyInto(out *VLANSpec) {
+	*out = *in
+	if in.TrunkVLANs != nil {
+		in, out := &in.TrunkVLANs, &out.TrunkVLANs
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VLANSpec.
+func (in *VLANSpec) DeepCopy() *VLANSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VLANSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VPCPeeringSpec) DeepCopyInto(out *VPCPeeringSpec) {
 	*out = *in
@@ -794,6 +2708,11 @@ func (in *VPCSpec) DeepCopyInto(out *VPCSpec) {
 		*out = new(NSGSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NetworkSecurityGroupRef != nil {
+		in, out := &in.NetworkSecurityGroupRef, &out.NetworkSecurityGroupRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	if in.Vni != nil {
 		in, out := &in.Vni, &out.Vni
 		*out = new(int32)
@@ -810,3 +2729,38 @@ func (in *VPCSpec) DeepCopy() *VPCSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualFunctionStatus) DeepCopyInto(out *VirtualFunctionStatus) {
+	*out = *in
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualFunctionStatus.
+func (in *VirtualFunctionStatus) DeepCopy() *VirtualFunctionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualFunctionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WarmPoolSpec) DeepCopyInto(out *WarmPoolSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WarmPoolSpec.
+func (in *WarmPoolSpec) DeepCopy() *WarmPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	infrav1beta1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// ConvertTo converts this NcxInfraCluster to the hub version (v1beta1).
+func (src *NcxInfraCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*infrav1beta1.NcxInfraCluster)
+
+	restored := &infrav1beta1.NcxInfraCluster{}
+	ok, err := utilconversion.UnmarshalData(src, restored)
+	if err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_NcxInfraCluster_To_v1beta1_NcxInfraCluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	if !ok {
+		return nil
+	}
+
+	// An empty endpoint is not distinguishable from an unset one in v1beta2
+	if restored.Spec.ControlPlaneEndpoint != nil && dst.Spec.ControlPlaneEndpoint == nil {
+		dst.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{}
+	}
+
+	// The deprecated network status fields of v1beta1 are gone in v1beta2
+	dst.Status.VPCID = restored.Status.VPCID
+	network, legacy := &dst.Status.NetworkStatus, restored.Status.NetworkStatus
+	network.SubnetIDs = legacy.SubnetIDs
+	network.VPCPrefixIDs = legacy.VPCPrefixIDs
+	network.VPCPeeringIDs = legacy.VPCPeeringIDs
+	network.NSGID = legacy.NSGID
+	network.IPBlockID = legacy.IPBlockID
+	network.AllocationID = legacy.AllocationID
+	network.ChildIPBlockID = legacy.ChildIPBlockID
+	network.PublicIPBlocks = legacy.PublicIPBlocks
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1beta1) to this version.
+func (dst *NcxInfraCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*infrav1beta1.NcxInfraCluster)
+
+	if err := Convert_v1beta1_NcxInfraCluster_To_v1beta2_NcxInfraCluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve the hub data lost in the conversion, to restore it on the way back
+	return utilconversion.MarshalData(src, dst)
+}
+
+// ConvertTo converts this NcxInfraMachine to the hub version (v1beta1).
+func (src *NcxInfraMachine) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*infrav1beta1.NcxInfraMachine)
+
+	if _, err := utilconversion.UnmarshalData(src, &infrav1beta1.NcxInfraMachine{}); err != nil {
+		return err
+	}
+	return Convert_v1beta2_NcxInfraMachine_To_v1beta1_NcxInfraMachine(src, dst, nil)
+}
+
+// ConvertFrom converts from the hub version (v1beta1) to this version.
+func (dst *NcxInfraMachine) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*infrav1beta1.NcxInfraMachine)
+
+	if err := Convert_v1beta1_NcxInfraMachine_To_v1beta2_NcxInfraMachine(src, dst, nil); err != nil {
+		return err
+	}
+	return utilconversion.MarshalData(src, dst)
+}
+
+// ConvertTo converts this NcxInfraMachineTemplate to the hub version (v1beta1).
+func (src *NcxInfraMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*infrav1beta1.NcxInfraMachineTemplate)
+
+	if _, err := utilconversion.UnmarshalData(src, &infrav1beta1.NcxInfraMachineTemplate{}); err != nil {
+		return err
+	}
+	return Convert_v1beta2_NcxInfraMachineTemplate_To_v1beta1_NcxInfraMachineTemplate(src, dst, nil)
+}
+
+// ConvertFrom converts from the hub version (v1beta1) to this version.
+func (dst *NcxInfraMachineTemplate) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*infrav1beta1.NcxInfraMachineTemplate)
+
+	if err := Convert_v1beta1_NcxInfraMachineTemplate_To_v1beta2_NcxInfraMachineTemplate(src, dst, nil); err != nil {
+		return err
+	}
+	return utilconversion.MarshalData(src, dst)
+}
+
+// Convert_v1beta2_NcxInfraClusterSpec_To_v1beta1_NcxInfraClusterSpec converts
+// the control plane endpoint to a pointer, unset when empty.
+func Convert_v1beta2_NcxInfraClusterSpec_To_v1beta1_NcxInfraClusterSpec(in *NcxInfraClusterSpec, out *infrav1beta1.NcxInfraClusterSpec, s apiconversion.Scope) error {
+	if err := autoConvert_v1beta2_NcxInfraClusterSpec_To_v1beta1_NcxInfraClusterSpec(in, out, s); err != nil {
+		return err
+	}
+	out.ControlPlaneEndpoint = nil
+	if !in.ControlPlaneEndpoint.IsZero() {
+		endpoint := in.ControlPlaneEndpoint
+		out.ControlPlaneEndpoint = &endpoint
+	}
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraClusterSpec_To_v1beta2_NcxInfraClusterSpec converts
+// the control plane endpoint to a value.
+func Convert_v1beta1_NcxInfraClusterSpec_To_v1beta2_NcxInfraClusterSpec(in *infrav1beta1.NcxInfraClusterSpec, out *NcxInfraClusterSpec, s apiconversion.Scope) error {
+	if err := autoConvert_v1beta1_NcxInfraClusterSpec_To_v1beta2_NcxInfraClusterSpec(in, out, s); err != nil {
+		return err
+	}
+	out.ControlPlaneEndpoint = clusterv1.APIEndpoint{}
+	if in.ControlPlaneEndpoint != nil {
+		out.ControlPlaneEndpoint = *in.ControlPlaneEndpoint
+	}
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraClusterStatus_To_v1beta2_NcxInfraClusterStatus drops
+// the deprecated VPC ID, replaced by the VPC network status.
+func Convert_v1beta1_NcxInfraClusterStatus_To_v1beta2_NcxInfraClusterStatus(in *infrav1beta1.NcxInfraClusterStatus, out *NcxInfraClusterStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraClusterStatus_To_v1beta2_NcxInfraClusterStatus(in, out, s)
+}
+
+// Convert_v1beta1_NetworkStatus_To_v1beta2_NetworkStatus drops the deprecated
+// resource IDs, replaced by the network resource statuses.
+func Convert_v1beta1_NetworkStatus_To_v1beta2_NetworkStatus(in *infrav1beta1.NetworkStatus, out *NetworkStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_NetworkStatus_To_v1beta2_NetworkStatus(in, out, s)
+}
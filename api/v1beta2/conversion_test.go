@@ -0,0 +1,96 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+
+	infrav1beta1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+func TestFuzzyConversion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := infrav1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("for NcxInfraCluster", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme: scheme,
+		Hub:    &infrav1beta1.NcxInfraCluster{},
+		Spoke:  &NcxInfraCluster{},
+	}))
+	t.Run("for NcxInfraMachine", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme: scheme,
+		Hub:    &infrav1beta1.NcxInfraMachine{},
+		Spoke:  &NcxInfraMachine{},
+	}))
+	t.Run("for NcxInfraMachineTemplate", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme: scheme,
+		Hub:    &infrav1beta1.NcxInfraMachineTemplate{},
+		Spoke:  &NcxInfraMachineTemplate{},
+	}))
+}
+
+func TestConvertControlPlaneEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint *clusterv1.APIEndpoint
+	}{
+		{name: "unset"},
+		{name: "empty", endpoint: &clusterv1.APIEndpoint{}},
+		{name: "port only", endpoint: &clusterv1.APIEndpoint{Port: 6443}},
+		{name: "set", endpoint: &clusterv1.APIEndpoint{Host: "10.0.1.10", Port: 6443}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &infrav1beta1.NcxInfraCluster{}
+			hub.Spec.ControlPlaneEndpoint = tt.endpoint
+
+			spoke := &NcxInfraCluster{}
+			if err := spoke.ConvertFrom(hub); err != nil {
+				t.Fatal(err)
+			}
+			want := clusterv1.APIEndpoint{}
+			if tt.endpoint != nil {
+				want = *tt.endpoint
+			}
+			if spoke.Spec.ControlPlaneEndpoint != want {
+				t.Errorf("v1beta2 endpoint = %+v, want %+v", spoke.Spec.ControlPlaneEndpoint, want)
+			}
+
+			restored := &infrav1beta1.NcxInfraCluster{}
+			if err := spoke.ConvertTo(restored); err != nil {
+				t.Fatal(err)
+			}
+			if (restored.Spec.ControlPlaneEndpoint == nil) != (tt.endpoint == nil) ||
+				(tt.endpoint != nil && *restored.Spec.ControlPlaneEndpoint != *tt.endpoint) {
+				t.Errorf("v1beta1 endpoint = %+v, want %+v", restored.Spec.ControlPlaneEndpoint, tt.endpoint)
+			}
+			if _, ok := restored.Annotations[utilconversion.DataAnnotation]; ok {
+				t.Errorf("conversion data annotation left on the hub")
+			}
+		})
+	}
+}
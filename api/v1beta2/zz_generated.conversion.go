@@ -0,0 +1,2429 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v1beta2
+
+import (
+	unsafe "unsafe"
+
+	v1beta1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1beta2 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	errors "sigs.k8s.io/cluster-api/errors"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*AntiAffinitySpec)(nil), (*v1beta1.AntiAffinitySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AntiAffinitySpec_To_v1beta1_AntiAffinitySpec(a.(*AntiAffinitySpec), b.(*v1beta1.AntiAffinitySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AntiAffinitySpec)(nil), (*AntiAffinitySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AntiAffinitySpec_To_v1beta2_AntiAffinitySpec(a.(*v1beta1.AntiAffinitySpec), b.(*AntiAffinitySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AuthenticationSpec)(nil), (*v1beta1.AuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AuthenticationSpec_To_v1beta1_AuthenticationSpec(a.(*AuthenticationSpec), b.(*v1beta1.AuthenticationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AuthenticationSpec)(nil), (*AuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AuthenticationSpec_To_v1beta2_AuthenticationSpec(a.(*v1beta1.AuthenticationSpec), b.(*AuthenticationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*BreakGlassSSHStatus)(nil), (*v1beta1.BreakGlassSSHStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_BreakGlassSSHStatus_To_v1beta1_BreakGlassSSHStatus(a.(*BreakGlassSSHStatus), b.(*v1beta1.BreakGlassSSHStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.BreakGlassSSHStatus)(nil), (*BreakGlassSSHStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_BreakGlassSSHStatus_To_v1beta2_BreakGlassSSHStatus(a.(*v1beta1.BreakGlassSSHStatus), b.(*BreakGlassSSHStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ConsoleStatus)(nil), (*v1beta1.ConsoleStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ConsoleStatus_To_v1beta1_ConsoleStatus(a.(*ConsoleStatus), b.(*v1beta1.ConsoleStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ConsoleStatus)(nil), (*ConsoleStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ConsoleStatus_To_v1beta2_ConsoleStatus(a.(*v1beta1.ConsoleStatus), b.(*ConsoleStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DHCPOptions)(nil), (*v1beta1.DHCPOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_DHCPOptions_To_v1beta1_DHCPOptions(a.(*DHCPOptions), b.(*v1beta1.DHCPOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.DHCPOptions)(nil), (*DHCPOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_DHCPOptions_To_v1beta2_DHCPOptions(a.(*v1beta1.DHCPOptions), b.(*DHCPOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DPUExtensionServiceSpec)(nil), (*v1beta1.DPUExtensionServiceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_DPUExtensionServiceSpec_To_v1beta1_DPUExtensionServiceSpec(a.(*DPUExtensionServiceSpec), b.(*v1beta1.DPUExtensionServiceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.DPUExtensionServiceSpec)(nil), (*DPUExtensionServiceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_DPUExtensionServiceSpec_To_v1beta2_DPUExtensionServiceSpec(a.(*v1beta1.DPUExtensionServiceSpec), b.(*DPUExtensionServiceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DeletionSpec)(nil), (*v1beta1.DeletionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_DeletionSpec_To_v1beta1_DeletionSpec(a.(*DeletionSpec), b.(*v1beta1.DeletionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.DeletionSpec)(nil), (*DeletionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_DeletionSpec_To_v1beta2_DeletionSpec(a.(*v1beta1.DeletionSpec), b.(*DeletionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GPUConfigSpec)(nil), (*v1beta1.GPUConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_GPUConfigSpec_To_v1beta1_GPUConfigSpec(a.(*GPUConfigSpec), b.(*v1beta1.GPUConfigSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.GPUConfigSpec)(nil), (*GPUConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_GPUConfigSpec_To_v1beta2_GPUConfigSpec(a.(*v1beta1.GPUConfigSpec), b.(*GPUConfigSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HardwareInterface)(nil), (*v1beta1.HardwareInterface)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_HardwareInterface_To_v1beta1_HardwareInterface(a.(*HardwareInterface), b.(*v1beta1.HardwareInterface), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.HardwareInterface)(nil), (*HardwareInterface)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HardwareInterface_To_v1beta2_HardwareInterface(a.(*v1beta1.HardwareInterface), b.(*HardwareInterface), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HardwareStatus)(nil), (*v1beta1.HardwareStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_HardwareStatus_To_v1beta1_HardwareStatus(a.(*HardwareStatus), b.(*v1beta1.HardwareStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.HardwareStatus)(nil), (*HardwareStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HardwareStatus_To_v1beta2_HardwareStatus(a.(*v1beta1.HardwareStatus), b.(*HardwareStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HugePagesSpec)(nil), (*v1beta1.HugePagesSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_HugePagesSpec_To_v1beta1_HugePagesSpec(a.(*HugePagesSpec), b.(*v1beta1.HugePagesSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.HugePagesSpec)(nil), (*HugePagesSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HugePagesSpec_To_v1beta2_HugePagesSpec(a.(*v1beta1.HugePagesSpec), b.(*HugePagesSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*IPBlockStatus)(nil), (*v1beta1.IPBlockStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_IPBlockStatus_To_v1beta1_IPBlockStatus(a.(*IPBlockStatus), b.(*v1beta1.IPBlockStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.IPBlockStatus)(nil), (*IPBlockStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_IPBlockStatus_To_v1beta2_IPBlockStatus(a.(*v1beta1.IPBlockStatus), b.(*IPBlockStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*InfiniBandInterfaceSpec)(nil), (*v1beta1.InfiniBandInterfaceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_InfiniBandInterfaceSpec_To_v1beta1_InfiniBandInterfaceSpec(a.(*InfiniBandInterfaceSpec), b.(*v1beta1.InfiniBandInterfaceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.InfiniBandInterfaceSpec)(nil), (*InfiniBandInterfaceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_InfiniBandInterfaceSpec_To_v1beta2_InfiniBandInterfaceSpec(a.(*v1beta1.InfiniBandInterfaceSpec), b.(*InfiniBandInterfaceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*InstanceTypeSpec)(nil), (*v1beta1.InstanceTypeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_InstanceTypeSpec_To_v1beta1_InstanceTypeSpec(a.(*InstanceTypeSpec), b.(*v1beta1.InstanceTypeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.InstanceTypeSpec)(nil), (*InstanceTypeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_InstanceTypeSpec_To_v1beta2_InstanceTypeSpec(a.(*v1beta1.InstanceTypeSpec), b.(*InstanceTypeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*InventorySpec)(nil), (*v1beta1.InventorySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_InventorySpec_To_v1beta1_InventorySpec(a.(*InventorySpec), b.(*v1beta1.InventorySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.InventorySpec)(nil), (*InventorySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_InventorySpec_To_v1beta2_InventorySpec(a.(*v1beta1.InventorySpec), b.(*InventorySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MIGSpec)(nil), (*v1beta1.MIGSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_MIGSpec_To_v1beta1_MIGSpec(a.(*MIGSpec), b.(*v1beta1.MIGSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.MIGSpec)(nil), (*MIGSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MIGSpec_To_v1beta2_MIGSpec(a.(*v1beta1.MIGSpec), b.(*MIGSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MachineHealthIssueSpec)(nil), (*v1beta1.MachineHealthIssueSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_MachineHealthIssueSpec_To_v1beta1_MachineHealthIssueSpec(a.(*MachineHealthIssueSpec), b.(*v1beta1.MachineHealthIssueSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.MachineHealthIssueSpec)(nil), (*MachineHealthIssueSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MachineHealthIssueSpec_To_v1beta2_MachineHealthIssueSpec(a.(*v1beta1.MachineHealthIssueSpec), b.(*MachineHealthIssueSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NSGRule)(nil), (*v1beta1.NSGRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NSGRule_To_v1beta1_NSGRule(a.(*NSGRule), b.(*v1beta1.NSGRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NSGRule)(nil), (*NSGRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NSGRule_To_v1beta2_NSGRule(a.(*v1beta1.NSGRule), b.(*NSGRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NSGSpec)(nil), (*v1beta1.NSGSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NSGSpec_To_v1beta1_NSGSpec(a.(*NSGSpec), b.(*v1beta1.NSGSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NSGSpec)(nil), (*NSGSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NSGSpec_To_v1beta2_NSGSpec(a.(*v1beta1.NSGSpec), b.(*NSGSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NSGStatus)(nil), (*v1beta1.NSGStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NSGStatus_To_v1beta1_NSGStatus(a.(*NSGStatus), b.(*v1beta1.NSGStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NSGStatus)(nil), (*NSGStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NSGStatus_To_v1beta2_NSGStatus(a.(*v1beta1.NSGStatus), b.(*NSGStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NVLinkInterfaceSpec)(nil), (*v1beta1.NVLinkInterfaceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NVLinkInterfaceSpec_To_v1beta1_NVLinkInterfaceSpec(a.(*NVLinkInterfaceSpec), b.(*v1beta1.NVLinkInterfaceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NVLinkInterfaceSpec)(nil), (*NVLinkInterfaceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NVLinkInterfaceSpec_To_v1beta2_NVLinkInterfaceSpec(a.(*v1beta1.NVLinkInterfaceSpec), b.(*NVLinkInterfaceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraCluster)(nil), (*v1beta1.NcxInfraCluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraCluster_To_v1beta1_NcxInfraCluster(a.(*NcxInfraCluster), b.(*v1beta1.NcxInfraCluster), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraCluster)(nil), (*NcxInfraCluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraCluster_To_v1beta2_NcxInfraCluster(a.(*v1beta1.NcxInfraCluster), b.(*NcxInfraCluster), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraClusterList)(nil), (*v1beta1.NcxInfraClusterList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraClusterList_To_v1beta1_NcxInfraClusterList(a.(*NcxInfraClusterList), b.(*v1beta1.NcxInfraClusterList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraClusterList)(nil), (*NcxInfraClusterList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraClusterList_To_v1beta2_NcxInfraClusterList(a.(*v1beta1.NcxInfraClusterList), b.(*NcxInfraClusterList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraClusterStatus)(nil), (*v1beta1.NcxInfraClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraClusterStatus_To_v1beta1_NcxInfraClusterStatus(a.(*NcxInfraClusterStatus), b.(*v1beta1.NcxInfraClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraMachine)(nil), (*v1beta1.NcxInfraMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraMachine_To_v1beta1_NcxInfraMachine(a.(*NcxInfraMachine), b.(*v1beta1.NcxInfraMachine), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraMachine)(nil), (*NcxInfraMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraMachine_To_v1beta2_NcxInfraMachine(a.(*v1beta1.NcxInfraMachine), b.(*NcxInfraMachine), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraMachineList)(nil), (*v1beta1.NcxInfraMachineList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraMachineList_To_v1beta1_NcxInfraMachineList(a.(*NcxInfraMachineList), b.(*v1beta1.NcxInfraMachineList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraMachineList)(nil), (*NcxInfraMachineList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraMachineList_To_v1beta2_NcxInfraMachineList(a.(*v1beta1.NcxInfraMachineList), b.(*NcxInfraMachineList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraMachineSpec)(nil), (*v1beta1.NcxInfraMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraMachineSpec_To_v1beta1_NcxInfraMachineSpec(a.(*NcxInfraMachineSpec), b.(*v1beta1.NcxInfraMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraMachineSpec)(nil), (*NcxInfraMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraMachineSpec_To_v1beta2_NcxInfraMachineSpec(a.(*v1beta1.NcxInfraMachineSpec), b.(*NcxInfraMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraMachineStatus)(nil), (*v1beta1.NcxInfraMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraMachineStatus_To_v1beta1_NcxInfraMachineStatus(a.(*NcxInfraMachineStatus), b.(*v1beta1.NcxInfraMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraMachineStatus)(nil), (*NcxInfraMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraMachineStatus_To_v1beta2_NcxInfraMachineStatus(a.(*v1beta1.NcxInfraMachineStatus), b.(*NcxInfraMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraMachineTemplate)(nil), (*v1beta1.NcxInfraMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraMachineTemplate_To_v1beta1_NcxInfraMachineTemplate(a.(*NcxInfraMachineTemplate), b.(*v1beta1.NcxInfraMachineTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraMachineTemplate)(nil), (*NcxInfraMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraMachineTemplate_To_v1beta2_NcxInfraMachineTemplate(a.(*v1beta1.NcxInfraMachineTemplate), b.(*NcxInfraMachineTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraMachineTemplateList)(nil), (*v1beta1.NcxInfraMachineTemplateList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraMachineTemplateList_To_v1beta1_NcxInfraMachineTemplateList(a.(*NcxInfraMachineTemplateList), b.(*v1beta1.NcxInfraMachineTemplateList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraMachineTemplateList)(nil), (*NcxInfraMachineTemplateList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraMachineTemplateList_To_v1beta2_NcxInfraMachineTemplateList(a.(*v1beta1.NcxInfraMachineTemplateList), b.(*NcxInfraMachineTemplateList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraMachineTemplateResource)(nil), (*v1beta1.NcxInfraMachineTemplateResource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraMachineTemplateResource_To_v1beta1_NcxInfraMachineTemplateResource(a.(*NcxInfraMachineTemplateResource), b.(*v1beta1.NcxInfraMachineTemplateResource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraMachineTemplateResource)(nil), (*NcxInfraMachineTemplateResource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraMachineTemplateResource_To_v1beta2_NcxInfraMachineTemplateResource(a.(*v1beta1.NcxInfraMachineTemplateResource), b.(*NcxInfraMachineTemplateResource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraMachineTemplateSpec)(nil), (*v1beta1.NcxInfraMachineTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraMachineTemplateSpec_To_v1beta1_NcxInfraMachineTemplateSpec(a.(*NcxInfraMachineTemplateSpec), b.(*v1beta1.NcxInfraMachineTemplateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraMachineTemplateSpec)(nil), (*NcxInfraMachineTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraMachineTemplateSpec_To_v1beta2_NcxInfraMachineTemplateSpec(a.(*v1beta1.NcxInfraMachineTemplateSpec), b.(*NcxInfraMachineTemplateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NcxInfraMachineTemplateStatus)(nil), (*v1beta1.NcxInfraMachineTemplateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraMachineTemplateStatus_To_v1beta1_NcxInfraMachineTemplateStatus(a.(*NcxInfraMachineTemplateStatus), b.(*v1beta1.NcxInfraMachineTemplateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NcxInfraMachineTemplateStatus)(nil), (*NcxInfraMachineTemplateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraMachineTemplateStatus_To_v1beta2_NcxInfraMachineTemplateStatus(a.(*v1beta1.NcxInfraMachineTemplateStatus), b.(*NcxInfraMachineTemplateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NetworkInterface)(nil), (*v1beta1.NetworkInterface)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NetworkInterface_To_v1beta1_NetworkInterface(a.(*NetworkInterface), b.(*v1beta1.NetworkInterface), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NetworkInterface)(nil), (*NetworkInterface)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkInterface_To_v1beta2_NetworkInterface(a.(*v1beta1.NetworkInterface), b.(*NetworkInterface), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NetworkResourceStatus)(nil), (*v1beta1.NetworkResourceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NetworkResourceStatus_To_v1beta1_NetworkResourceStatus(a.(*NetworkResourceStatus), b.(*v1beta1.NetworkResourceStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NetworkResourceStatus)(nil), (*NetworkResourceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkResourceStatus_To_v1beta2_NetworkResourceStatus(a.(*v1beta1.NetworkResourceStatus), b.(*NetworkResourceStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NetworkServices)(nil), (*v1beta1.NetworkServices)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NetworkServices_To_v1beta1_NetworkServices(a.(*NetworkServices), b.(*v1beta1.NetworkServices), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NetworkServices)(nil), (*NetworkServices)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkServices_To_v1beta2_NetworkServices(a.(*v1beta1.NetworkServices), b.(*NetworkServices), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NetworkSpec)(nil), (*v1beta1.NetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NetworkSpec_To_v1beta1_NetworkSpec(a.(*NetworkSpec), b.(*v1beta1.NetworkSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.NetworkSpec)(nil), (*NetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec(a.(*v1beta1.NetworkSpec), b.(*NetworkSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NetworkStatus)(nil), (*v1beta1.NetworkStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NetworkStatus_To_v1beta1_NetworkStatus(a.(*NetworkStatus), b.(*v1beta1.NetworkStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*OSSpec)(nil), (*v1beta1.OSSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_OSSpec_To_v1beta1_OSSpec(a.(*OSSpec), b.(*v1beta1.OSSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.OSSpec)(nil), (*OSSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_OSSpec_To_v1beta2_OSSpec(a.(*v1beta1.OSSpec), b.(*OSSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PlacementSpec)(nil), (*v1beta1.PlacementSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_PlacementSpec_To_v1beta1_PlacementSpec(a.(*PlacementSpec), b.(*v1beta1.PlacementSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.PlacementSpec)(nil), (*PlacementSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PlacementSpec_To_v1beta2_PlacementSpec(a.(*v1beta1.PlacementSpec), b.(*PlacementSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PlacementStatus)(nil), (*v1beta1.PlacementStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_PlacementStatus_To_v1beta1_PlacementStatus(a.(*PlacementStatus), b.(*v1beta1.PlacementStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.PlacementStatus)(nil), (*PlacementStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PlacementStatus_To_v1beta2_PlacementStatus(a.(*v1beta1.PlacementStatus), b.(*PlacementStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PowerActionStatus)(nil), (*v1beta1.PowerActionStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_PowerActionStatus_To_v1beta1_PowerActionStatus(a.(*PowerActionStatus), b.(*v1beta1.PowerActionStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.PowerActionStatus)(nil), (*PowerActionStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PowerActionStatus_To_v1beta2_PowerActionStatus(a.(*v1beta1.PowerActionStatus), b.(*PowerActionStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ProvisionedStatus)(nil), (*v1beta1.ProvisionedStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ProvisionedStatus_To_v1beta1_ProvisionedStatus(a.(*ProvisionedStatus), b.(*v1beta1.ProvisionedStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ProvisionedStatus)(nil), (*ProvisionedStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ProvisionedStatus_To_v1beta2_ProvisionedStatus(a.(*v1beta1.ProvisionedStatus), b.(*ProvisionedStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ProvisioningSpec)(nil), (*v1beta1.ProvisioningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ProvisioningSpec_To_v1beta1_ProvisioningSpec(a.(*ProvisioningSpec), b.(*v1beta1.ProvisioningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ProvisioningSpec)(nil), (*ProvisioningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ProvisioningSpec_To_v1beta2_ProvisioningSpec(a.(*v1beta1.ProvisioningSpec), b.(*ProvisioningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ProxySpec)(nil), (*v1beta1.ProxySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ProxySpec_To_v1beta1_ProxySpec(a.(*ProxySpec), b.(*v1beta1.ProxySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ProxySpec)(nil), (*ProxySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ProxySpec_To_v1beta2_ProxySpec(a.(*v1beta1.ProxySpec), b.(*ProxySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*QuotaStatus)(nil), (*v1beta1.QuotaStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_QuotaStatus_To_v1beta1_QuotaStatus(a.(*QuotaStatus), b.(*v1beta1.QuotaStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.QuotaStatus)(nil), (*QuotaStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_QuotaStatus_To_v1beta2_QuotaStatus(a.(*v1beta1.QuotaStatus), b.(*QuotaStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RAIDArraySpec)(nil), (*v1beta1.RAIDArraySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_RAIDArraySpec_To_v1beta1_RAIDArraySpec(a.(*RAIDArraySpec), b.(*v1beta1.RAIDArraySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.RAIDArraySpec)(nil), (*RAIDArraySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RAIDArraySpec_To_v1beta2_RAIDArraySpec(a.(*v1beta1.RAIDArraySpec), b.(*RAIDArraySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ResourceOrigin)(nil), (*v1beta1.ResourceOrigin)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ResourceOrigin_To_v1beta1_ResourceOrigin(a.(*ResourceOrigin), b.(*v1beta1.ResourceOrigin), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ResourceOrigin)(nil), (*ResourceOrigin)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ResourceOrigin_To_v1beta2_ResourceOrigin(a.(*v1beta1.ResourceOrigin), b.(*ResourceOrigin), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SRIOVInterfaceStatus)(nil), (*v1beta1.SRIOVInterfaceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SRIOVInterfaceStatus_To_v1beta1_SRIOVInterfaceStatus(a.(*SRIOVInterfaceStatus), b.(*v1beta1.SRIOVInterfaceStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.SRIOVInterfaceStatus)(nil), (*SRIOVInterfaceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SRIOVInterfaceStatus_To_v1beta2_SRIOVInterfaceStatus(a.(*v1beta1.SRIOVInterfaceStatus), b.(*SRIOVInterfaceStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SRIOVSpec)(nil), (*v1beta1.SRIOVSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SRIOVSpec_To_v1beta1_SRIOVSpec(a.(*SRIOVSpec), b.(*v1beta1.SRIOVSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.SRIOVSpec)(nil), (*SRIOVSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SRIOVSpec_To_v1beta2_SRIOVSpec(a.(*v1beta1.SRIOVSpec), b.(*SRIOVSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SecuritySpec)(nil), (*v1beta1.SecuritySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SecuritySpec_To_v1beta1_SecuritySpec(a.(*SecuritySpec), b.(*v1beta1.SecuritySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.SecuritySpec)(nil), (*SecuritySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SecuritySpec_To_v1beta2_SecuritySpec(a.(*v1beta1.SecuritySpec), b.(*SecuritySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SiteReference)(nil), (*v1beta1.SiteReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SiteReference_To_v1beta1_SiteReference(a.(*SiteReference), b.(*v1beta1.SiteReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.SiteReference)(nil), (*SiteReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SiteReference_To_v1beta2_SiteReference(a.(*v1beta1.SiteReference), b.(*SiteReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StorageSpec)(nil), (*v1beta1.StorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_StorageSpec_To_v1beta1_StorageSpec(a.(*StorageSpec), b.(*v1beta1.StorageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.StorageSpec)(nil), (*StorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_StorageSpec_To_v1beta2_StorageSpec(a.(*v1beta1.StorageSpec), b.(*StorageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SubnetSpec)(nil), (*v1beta1.SubnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(a.(*SubnetSpec), b.(*v1beta1.SubnetSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.SubnetSpec)(nil), (*SubnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SubnetSpec_To_v1beta2_SubnetSpec(a.(*v1beta1.SubnetSpec), b.(*SubnetSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TuningSpec)(nil), (*v1beta1.TuningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_TuningSpec_To_v1beta1_TuningSpec(a.(*TuningSpec), b.(*v1beta1.TuningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.TuningSpec)(nil), (*TuningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_TuningSpec_To_v1beta2_TuningSpec(a.(*v1beta1.TuningSpec), b.(*TuningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VLANSpec)(nil), (*v1beta1.VLANSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_VLANSpec_To_v1beta1_VLANSpec(a.(*VLANSpec), b.(*v1beta1.VLANSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.VLANSpec)(nil), (*VLANSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VLANSpec_To_v1beta2_VLANSpec(a.(*v1beta1.VLANSpec), b.(*VLANSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VPCPeeringSpec)(nil), (*v1beta1.VPCPeeringSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_VPCPeeringSpec_To_v1beta1_VPCPeeringSpec(a.(*VPCPeeringSpec), b.(*v1beta1.VPCPeeringSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.VPCPeeringSpec)(nil), (*VPCPeeringSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VPCPeeringSpec_To_v1beta2_VPCPeeringSpec(a.(*v1beta1.VPCPeeringSpec), b.(*VPCPeeringSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VPCPrefixSpec)(nil), (*v1beta1.VPCPrefixSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_VPCPrefixSpec_To_v1beta1_VPCPrefixSpec(a.(*VPCPrefixSpec), b.(*v1beta1.VPCPrefixSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.VPCPrefixSpec)(nil), (*VPCPrefixSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VPCPrefixSpec_To_v1beta2_VPCPrefixSpec(a.(*v1beta1.VPCPrefixSpec), b.(*VPCPrefixSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VPCSpec)(nil), (*v1beta1.VPCSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_VPCSpec_To_v1beta1_VPCSpec(a.(*VPCSpec), b.(*v1beta1.VPCSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.VPCSpec)(nil), (*VPCSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VPCSpec_To_v1beta2_VPCSpec(a.(*v1beta1.VPCSpec), b.(*VPCSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VirtualFunctionStatus)(nil), (*v1beta1.VirtualFunctionStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_VirtualFunctionStatus_To_v1beta1_VirtualFunctionStatus(a.(*VirtualFunctionStatus), b.(*v1beta1.VirtualFunctionStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.VirtualFunctionStatus)(nil), (*VirtualFunctionStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VirtualFunctionStatus_To_v1beta2_VirtualFunctionStatus(a.(*v1beta1.VirtualFunctionStatus), b.(*VirtualFunctionStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*WarmPoolSpec)(nil), (*v1beta1.WarmPoolSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_WarmPoolSpec_To_v1beta1_WarmPoolSpec(a.(*WarmPoolSpec), b.(*v1beta1.WarmPoolSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.WarmPoolSpec)(nil), (*WarmPoolSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_WarmPoolSpec_To_v1beta2_WarmPoolSpec(a.(*v1beta1.WarmPoolSpec), b.(*WarmPoolSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.NcxInfraClusterSpec)(nil), (*NcxInfraClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraClusterSpec_To_v1beta2_NcxInfraClusterSpec(a.(*v1beta1.NcxInfraClusterSpec), b.(*NcxInfraClusterSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.NcxInfraClusterStatus)(nil), (*NcxInfraClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NcxInfraClusterStatus_To_v1beta2_NcxInfraClusterStatus(a.(*v1beta1.NcxInfraClusterStatus), b.(*NcxInfraClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.NetworkStatus)(nil), (*NetworkStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkStatus_To_v1beta2_NetworkStatus(a.(*v1beta1.NetworkStatus), b.(*NetworkStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*NcxInfraClusterSpec)(nil), (*v1beta1.NcxInfraClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NcxInfraClusterSpec_To_v1beta1_NcxInfraClusterSpec(a.(*NcxInfraClusterSpec), b.(*v1beta1.NcxInfraClusterSpec), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1beta2_AntiAffinitySpec_To_v1beta1_AntiAffinitySpec(in *AntiAffinitySpec, out *v1beta1.AntiAffinitySpec, s conversion.Scope) error {
+	out.Level = v1beta1.FailureUnit(in.Level)
+	out.Mode = v1beta1.AntiAffinityMode(in.Mode)
+	return nil
+}
+
+// Convert_v1beta2_AntiAffinitySpec_To_v1beta1_AntiAffinitySpec is an autogenerated conversion function.
+func Convert_v1beta2_AntiAffinitySpec_To_v1beta1_AntiAffinitySpec(in *AntiAffinitySpec, out *v1beta1.AntiAffinitySpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_AntiAffinitySpec_To_v1beta1_AntiAffinitySpec(in, out, s)
+}
+
+func autoConvert_v1beta1_AntiAffinitySpec_To_v1beta2_AntiAffinitySpec(in *v1beta1.AntiAffinitySpec, out *AntiAffinitySpec, s conversion.Scope) error {
+	out.Level = FailureUnit(in.Level)
+	out.Mode = AntiAffinityMode(in.Mode)
+	return nil
+}
+
+// Convert_v1beta1_AntiAffinitySpec_To_v1beta2_AntiAffinitySpec is an autogenerated conversion function.
+func Convert_v1beta1_AntiAffinitySpec_To_v1beta2_AntiAffinitySpec(in *v1beta1.AntiAffinitySpec, out *AntiAffinitySpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_AntiAffinitySpec_To_v1beta2_AntiAffinitySpec(in, out, s)
+}
+
+func autoConvert_v1beta2_AuthenticationSpec_To_v1beta1_AuthenticationSpec(in *AuthenticationSpec, out *v1beta1.AuthenticationSpec, s conversion.Scope) error {
+	out.SecretRef = in.SecretRef
+	out.IdentityRef = (*v1.LocalObjectReference)(unsafe.Pointer(in.IdentityRef))
+	out.PropagateProxy = in.PropagateProxy
+	return nil
+}
+
+// Convert_v1beta2_AuthenticationSpec_To_v1beta1_AuthenticationSpec is an autogenerated conversion function.
+func Convert_v1beta2_AuthenticationSpec_To_v1beta1_AuthenticationSpec(in *AuthenticationSpec, out *v1beta1.AuthenticationSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_AuthenticationSpec_To_v1beta1_AuthenticationSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_AuthenticationSpec_To_v1beta2_AuthenticationSpec(in *v1beta1.AuthenticationSpec, out *AuthenticationSpec, s conversion.Scope) error {
+	out.SecretRef = in.SecretRef
+	out.IdentityRef = (*v1.LocalObjectReference)(unsafe.Pointer(in.IdentityRef))
+	out.PropagateProxy = in.PropagateProxy
+	return nil
+}
+
+// Convert_v1beta1_AuthenticationSpec_To_v1beta2_AuthenticationSpec is an autogenerated conversion function.
+func Convert_v1beta1_AuthenticationSpec_To_v1beta2_AuthenticationSpec(in *v1beta1.AuthenticationSpec, out *AuthenticationSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_AuthenticationSpec_To_v1beta2_AuthenticationSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_BreakGlassSSHStatus_To_v1beta1_BreakGlassSSHStatus(in *BreakGlassSSHStatus, out *v1beta1.BreakGlassSSHStatus, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Fingerprint = in.Fingerprint
+	out.SSHKeyID = in.SSHKeyID
+	out.SSHKeyGroupID = in.SSHKeyGroupID
+	out.SiteIDs = *(*[]string)(unsafe.Pointer(&in.SiteIDs))
+	out.RetiredSSHKeyID = in.RetiredSSHKeyID
+	out.LastRotationTime = (*metav1.Time)(unsafe.Pointer(in.LastRotationTime))
+	return nil
+}
+
+// Convert_v1beta2_BreakGlassSSHStatus_To_v1beta1_BreakGlassSSHStatus is an autogenerated conversion function.
+func Convert_v1beta2_BreakGlassSSHStatus_To_v1beta1_BreakGlassSSHStatus(in *BreakGlassSSHStatus, out *v1beta1.BreakGlassSSHStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_BreakGlassSSHStatus_To_v1beta1_BreakGlassSSHStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_BreakGlassSSHStatus_To_v1beta2_BreakGlassSSHStatus(in *v1beta1.BreakGlassSSHStatus, out *BreakGlassSSHStatus, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Fingerprint = in.Fingerprint
+	out.SSHKeyID = in.SSHKeyID
+	out.SSHKeyGroupID = in.SSHKeyGroupID
+	out.SiteIDs = *(*[]string)(unsafe.Pointer(&in.SiteIDs))
+	out.RetiredSSHKeyID = in.RetiredSSHKeyID
+	out.LastRotationTime = (*metav1.Time)(unsafe.Pointer(in.LastRotationTime))
+	return nil
+}
+
+// Convert_v1beta1_BreakGlassSSHStatus_To_v1beta2_BreakGlassSSHStatus is an autogenerated conversion function.
+func Convert_v1beta1_BreakGlassSSHStatus_To_v1beta2_BreakGlassSSHStatus(in *v1beta1.BreakGlassSSHStatus, out *BreakGlassSSHStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_BreakGlassSSHStatus_To_v1beta2_BreakGlassSSHStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_ConsoleStatus_To_v1beta1_ConsoleStatus(in *ConsoleStatus, out *v1beta1.ConsoleStatus, s conversion.Scope) error {
+	out.URL = in.URL
+	out.SSHKeySecretName = in.SSHKeySecretName
+	out.Result = in.Result
+	out.Message = in.Message
+	out.Time = in.Time
+	return nil
+}
+
+// Convert_v1beta2_ConsoleStatus_To_v1beta1_ConsoleStatus is an autogenerated conversion function.
+func Convert_v1beta2_ConsoleStatus_To_v1beta1_ConsoleStatus(in *ConsoleStatus, out *v1beta1.ConsoleStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_ConsoleStatus_To_v1beta1_ConsoleStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_ConsoleStatus_To_v1beta2_ConsoleStatus(in *v1beta1.ConsoleStatus, out *ConsoleStatus, s conversion.Scope) error {
+	out.URL = in.URL
+	out.SSHKeySecretName = in.SSHKeySecretName
+	out.Result = in.Result
+	out.Message = in.Message
+	out.Time = in.Time
+	return nil
+}
+
+// Convert_v1beta1_ConsoleStatus_To_v1beta2_ConsoleStatus is an autogenerated conversion function.
+func Convert_v1beta1_ConsoleStatus_To_v1beta2_ConsoleStatus(in *v1beta1.ConsoleStatus, out *ConsoleStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_ConsoleStatus_To_v1beta2_ConsoleStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_DHCPOptions_To_v1beta1_DHCPOptions(in *DHCPOptions, out *v1beta1.DHCPOptions, s conversion.Scope) error {
+	out.DNSServers = *(*[]string)(unsafe.Pointer(&in.DNSServers))
+	out.SearchDomains = *(*[]string)(unsafe.Pointer(&in.SearchDomains))
+	out.NTPServers = *(*[]string)(unsafe.Pointer(&in.NTPServers))
+	return nil
+}
+
+// Convert_v1beta2_DHCPOptions_To_v1beta1_DHCPOptions is an autogenerated conversion function.
+func Convert_v1beta2_DHCPOptions_To_v1beta1_DHCPOptions(in *DHCPOptions, out *v1beta1.DHCPOptions, s conversion.Scope) error {
+	return autoConvert_v1beta2_DHCPOptions_To_v1beta1_DHCPOptions(in, out, s)
+}
+
+func autoConvert_v1beta1_DHCPOptions_To_v1beta2_DHCPOptions(in *v1beta1.DHCPOptions, out *DHCPOptions, s conversion.Scope) error {
+	out.DNSServers = *(*[]string)(unsafe.Pointer(&in.DNSServers))
+	out.SearchDomains = *(*[]string)(unsafe.Pointer(&in.SearchDomains))
+	out.NTPServers = *(*[]string)(unsafe.Pointer(&in.NTPServers))
+	return nil
+}
+
+// Convert_v1beta1_DHCPOptions_To_v1beta2_DHCPOptions is an autogenerated conversion function.
+func Convert_v1beta1_DHCPOptions_To_v1beta2_DHCPOptions(in *v1beta1.DHCPOptions, out *DHCPOptions, s conversion.Scope) error {
+	return autoConvert_v1beta1_DHCPOptions_To_v1beta2_DHCPOptions(in, out, s)
+}
+
+func autoConvert_v1beta2_DPUExtensionServiceSpec_To_v1beta1_DPUExtensionServiceSpec(in *DPUExtensionServiceSpec, out *v1beta1.DPUExtensionServiceSpec, s conversion.Scope) error {
+	out.ServiceID = in.ServiceID
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1beta2_DPUExtensionServiceSpec_To_v1beta1_DPUExtensionServiceSpec is an autogenerated conversion function.
+func Convert_v1beta2_DPUExtensionServiceSpec_To_v1beta1_DPUExtensionServiceSpec(in *DPUExtensionServiceSpec, out *v1beta1.DPUExtensionServiceSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_DPUExtensionServiceSpec_To_v1beta1_DPUExtensionServiceSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_DPUExtensionServiceSpec_To_v1beta2_DPUExtensionServiceSpec(in *v1beta1.DPUExtensionServiceSpec, out *DPUExtensionServiceSpec, s conversion.Scope) error {
+	out.ServiceID = in.ServiceID
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1beta1_DPUExtensionServiceSpec_To_v1beta2_DPUExtensionServiceSpec is an autogenerated conversion function.
+func Convert_v1beta1_DPUExtensionServiceSpec_To_v1beta2_DPUExtensionServiceSpec(in *v1beta1.DPUExtensionServiceSpec, out *DPUExtensionServiceSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_DPUExtensionServiceSpec_To_v1beta2_DPUExtensionServiceSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_DeletionSpec_To_v1beta1_DeletionSpec(in *DeletionSpec, out *v1beta1.DeletionSpec, s conversion.Scope) error {
+	out.Policy = v1beta1.DeletionPolicy(in.Policy)
+	out.HealthIssue = (*v1beta1.MachineHealthIssueSpec)(unsafe.Pointer(in.HealthIssue))
+	out.SecureErase = v1beta1.SecureEraseLevel(in.SecureErase)
+	return nil
+}
+
+// Convert_v1beta2_DeletionSpec_To_v1beta1_DeletionSpec is an autogenerated conversion function.
+func Convert_v1beta2_DeletionSpec_To_v1beta1_DeletionSpec(in *DeletionSpec, out *v1beta1.DeletionSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_DeletionSpec_To_v1beta1_DeletionSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_DeletionSpec_To_v1beta2_DeletionSpec(in *v1beta1.DeletionSpec, out *DeletionSpec, s conversion.Scope) error {
+	out.Policy = DeletionPolicy(in.Policy)
+	out.HealthIssue = (*MachineHealthIssueSpec)(unsafe.Pointer(in.HealthIssue))
+	out.SecureErase = SecureEraseLevel(in.SecureErase)
+	return nil
+}
+
+// Convert_v1beta1_DeletionSpec_To_v1beta2_DeletionSpec is an autogenerated conversion function.
+func Convert_v1beta1_DeletionSpec_To_v1beta2_DeletionSpec(in *v1beta1.DeletionSpec, out *DeletionSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_DeletionSpec_To_v1beta2_DeletionSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_GPUConfigSpec_To_v1beta1_GPUConfigSpec(in *GPUConfigSpec, out *v1beta1.GPUConfigSpec, s conversion.Scope) error {
+	out.MIG = *(*[]v1beta1.MIGSpec)(unsafe.Pointer(&in.MIG))
+	out.ComputeMode = v1beta1.GPUComputeMode(in.ComputeMode)
+	out.PersistenceMode = in.PersistenceMode
+	out.FabricManager = in.FabricManager
+	return nil
+}
+
+// Convert_v1beta2_GPUConfigSpec_To_v1beta1_GPUConfigSpec is an autogenerated conversion function.
+func Convert_v1beta2_GPUConfigSpec_To_v1beta1_GPUConfigSpec(in *GPUConfigSpec, out *v1beta1.GPUConfigSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_GPUConfigSpec_To_v1beta1_GPUConfigSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_GPUConfigSpec_To_v1beta2_GPUConfigSpec(in *v1beta1.GPUConfigSpec, out *GPUConfigSpec, s conversion.Scope) error {
+	out.MIG = *(*[]MIGSpec)(unsafe.Pointer(&in.MIG))
+	out.ComputeMode = GPUComputeMode(in.ComputeMode)
+	out.PersistenceMode = in.PersistenceMode
+	out.FabricManager = in.FabricManager
+	return nil
+}
+
+// Convert_v1beta1_GPUConfigSpec_To_v1beta2_GPUConfigSpec is an autogenerated conversion function.
+func Convert_v1beta1_GPUConfigSpec_To_v1beta2_GPUConfigSpec(in *v1beta1.GPUConfigSpec, out *GPUConfigSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_GPUConfigSpec_To_v1beta2_GPUConfigSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_HardwareInterface_To_v1beta1_HardwareInterface(in *HardwareInterface, out *v1beta1.HardwareInterface, s conversion.Scope) error {
+	out.Address = in.Address
+	out.Vendor = in.Vendor
+	out.Device = in.Device
+	out.Slot = in.Slot
+	out.NUMANode = (*int32)(unsafe.Pointer(in.NUMANode))
+	return nil
+}
+
+// Convert_v1beta2_HardwareInterface_To_v1beta1_HardwareInterface is an autogenerated conversion function.
+func Convert_v1beta2_HardwareInterface_To_v1beta1_HardwareInterface(in *HardwareInterface, out *v1beta1.HardwareInterface, s conversion.Scope) error {
+	return autoConvert_v1beta2_HardwareInterface_To_v1beta1_HardwareInterface(in, out, s)
+}
+
+func autoConvert_v1beta1_HardwareInterface_To_v1beta2_HardwareInterface(in *v1beta1.HardwareInterface, out *HardwareInterface, s conversion.Scope) error {
+	out.Address = in.Address
+	out.Vendor = in.Vendor
+	out.Device = in.Device
+	out.Slot = in.Slot
+	out.NUMANode = (*int32)(unsafe.Pointer(in.NUMANode))
+	return nil
+}
+
+// Convert_v1beta1_HardwareInterface_To_v1beta2_HardwareInterface is an autogenerated conversion function.
+func Convert_v1beta1_HardwareInterface_To_v1beta2_HardwareInterface(in *v1beta1.HardwareInterface, out *HardwareInterface, s conversion.Scope) error {
+	return autoConvert_v1beta1_HardwareInterface_To_v1beta2_HardwareInterface(in, out, s)
+}
+
+func autoConvert_v1beta2_HardwareStatus_To_v1beta1_HardwareStatus(in *HardwareStatus, out *v1beta1.HardwareStatus, s conversion.Scope) error {
+	out.MachineID = in.MachineID
+	out.Vendor = in.Vendor
+	out.ProductName = in.ProductName
+	out.SerialNumber = in.SerialNumber
+	out.GPUModel = in.GPUModel
+	out.GPUCount = in.GPUCount
+	out.NetworkInterfaces = *(*[]v1beta1.HardwareInterface)(unsafe.Pointer(&in.NetworkInterfaces))
+	out.InfiniBandInterfaces = *(*[]v1beta1.HardwareInterface)(unsafe.Pointer(&in.InfiniBandInterfaces))
+	out.Rack = in.Rack
+	out.Slot = (*int32)(unsafe.Pointer(in.Slot))
+	return nil
+}
+
+// Convert_v1beta2_HardwareStatus_To_v1beta1_HardwareStatus is an autogenerated conversion function.
+func Convert_v1beta2_HardwareStatus_To_v1beta1_HardwareStatus(in *HardwareStatus, out *v1beta1.HardwareStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_HardwareStatus_To_v1beta1_HardwareStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_HardwareStatus_To_v1beta2_HardwareStatus(in *v1beta1.HardwareStatus, out *HardwareStatus, s conversion.Scope) error {
+	out.MachineID = in.MachineID
+	out.Vendor = in.Vendor
+	out.ProductName = in.ProductName
+	out.SerialNumber = in.SerialNumber
+	out.GPUModel = in.GPUModel
+	out.GPUCount = in.GPUCount
+	out.NetworkInterfaces = *(*[]HardwareInterface)(unsafe.Pointer(&in.NetworkInterfaces))
+	out.InfiniBandInterfaces = *(*[]HardwareInterface)(unsafe.Pointer(&in.InfiniBandInterfaces))
+	out.Rack = in.Rack
+	out.Slot = (*int32)(unsafe.Pointer(in.Slot))
+	return nil
+}
+
+// Convert_v1beta1_HardwareStatus_To_v1beta2_HardwareStatus is an autogenerated conversion function.
+func Convert_v1beta1_HardwareStatus_To_v1beta2_HardwareStatus(in *v1beta1.HardwareStatus, out *HardwareStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_HardwareStatus_To_v1beta2_HardwareStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_HugePagesSpec_To_v1beta1_HugePagesSpec(in *HugePagesSpec, out *v1beta1.HugePagesSpec, s conversion.Scope) error {
+	out.Size = v1beta1.HugePageSize(in.Size)
+	out.Count = in.Count
+	return nil
+}
+
+// Convert_v1beta2_HugePagesSpec_To_v1beta1_HugePagesSpec is an autogenerated conversion function.
+func Convert_v1beta2_HugePagesSpec_To_v1beta1_HugePagesSpec(in *HugePagesSpec, out *v1beta1.HugePagesSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_HugePagesSpec_To_v1beta1_HugePagesSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_HugePagesSpec_To_v1beta2_HugePagesSpec(in *v1beta1.HugePagesSpec, out *HugePagesSpec, s conversion.Scope) error {
+	out.Size = HugePageSize(in.Size)
+	out.Count = in.Count
+	return nil
+}
+
+// Convert_v1beta1_HugePagesSpec_To_v1beta2_HugePagesSpec is an autogenerated conversion function.
+func Convert_v1beta1_HugePagesSpec_To_v1beta2_HugePagesSpec(in *v1beta1.HugePagesSpec, out *HugePagesSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_HugePagesSpec_To_v1beta2_HugePagesSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_IPBlockStatus_To_v1beta1_IPBlockStatus(in *IPBlockStatus, out *v1beta1.IPBlockStatus, s conversion.Scope) error {
+	out.Subnet = in.Subnet
+	out.CIDR = in.CIDR
+	out.IPBlockID = in.IPBlockID
+	out.AllocationID = in.AllocationID
+	out.ChildIPBlockID = in.ChildIPBlockID
+	out.Adopted = in.Adopted
+	out.State = v1beta1.NetworkResourceState(in.State)
+	out.LastError = in.LastError
+	return nil
+}
+
+// Convert_v1beta2_IPBlockStatus_To_v1beta1_IPBlockStatus is an autogenerated conversion function.
+func Convert_v1beta2_IPBlockStatus_To_v1beta1_IPBlockStatus(in *IPBlockStatus, out *v1beta1.IPBlockStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_IPBlockStatus_To_v1beta1_IPBlockStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_IPBlockStatus_To_v1beta2_IPBlockStatus(in *v1beta1.IPBlockStatus, out *IPBlockStatus, s conversion.Scope) error {
+	out.Subnet = in.Subnet
+	out.CIDR = in.CIDR
+	out.IPBlockID = in.IPBlockID
+	out.AllocationID = in.AllocationID
+	out.ChildIPBlockID = in.ChildIPBlockID
+	out.Adopted = in.Adopted
+	out.State = NetworkResourceState(in.State)
+	out.LastError = in.LastError
+	return nil
+}
+
+// Convert_v1beta1_IPBlockStatus_To_v1beta2_IPBlockStatus is an autogenerated conversion function.
+func Convert_v1beta1_IPBlockStatus_To_v1beta2_IPBlockStatus(in *v1beta1.IPBlockStatus, out *IPBlockStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_IPBlockStatus_To_v1beta2_IPBlockStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_InfiniBandInterfaceSpec_To_v1beta1_InfiniBandInterfaceSpec(in *InfiniBandInterfaceSpec, out *v1beta1.InfiniBandInterfaceSpec, s conversion.Scope) error {
+	out.PartitionID = in.PartitionID
+	out.Device = in.Device
+	out.DeviceInstance = (*int32)(unsafe.Pointer(in.DeviceInstance))
+	out.IsPhysical = in.IsPhysical
+	return nil
+}
+
+// Convert_v1beta2_InfiniBandInterfaceSpec_To_v1beta1_InfiniBandInterfaceSpec is an autogenerated conversion function.
+func Convert_v1beta2_InfiniBandInterfaceSpec_To_v1beta1_InfiniBandInterfaceSpec(in *InfiniBandInterfaceSpec, out *v1beta1.InfiniBandInterfaceSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_InfiniBandInterfaceSpec_To_v1beta1_InfiniBandInterfaceSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_InfiniBandInterfaceSpec_To_v1beta2_InfiniBandInterfaceSpec(in *v1beta1.InfiniBandInterfaceSpec, out *InfiniBandInterfaceSpec, s conversion.Scope) error {
+	out.PartitionID = in.PartitionID
+	out.Device = in.Device
+	out.DeviceInstance = (*int32)(unsafe.Pointer(in.DeviceInstance))
+	out.IsPhysical = in.IsPhysical
+	return nil
+}
+
+// Convert_v1beta1_InfiniBandInterfaceSpec_To_v1beta2_InfiniBandInterfaceSpec is an autogenerated conversion function.
+func Convert_v1beta1_InfiniBandInterfaceSpec_To_v1beta2_InfiniBandInterfaceSpec(in *v1beta1.InfiniBandInterfaceSpec, out *InfiniBandInterfaceSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_InfiniBandInterfaceSpec_To_v1beta2_InfiniBandInterfaceSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_InstanceTypeSpec_To_v1beta1_InstanceTypeSpec(in *InstanceTypeSpec, out *v1beta1.InstanceTypeSpec, s conversion.Scope) error {
+	out.ID = in.ID
+	out.MachineID = in.MachineID
+	out.AllowUnhealthyMachine = in.AllowUnhealthyMachine
+	return nil
+}
+
+// Convert_v1beta2_InstanceTypeSpec_To_v1beta1_InstanceTypeSpec is an autogenerated conversion function.
+func Convert_v1beta2_InstanceTypeSpec_To_v1beta1_InstanceTypeSpec(in *InstanceTypeSpec, out *v1beta1.InstanceTypeSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_InstanceTypeSpec_To_v1beta1_InstanceTypeSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_InstanceTypeSpec_To_v1beta2_InstanceTypeSpec(in *v1beta1.InstanceTypeSpec, out *InstanceTypeSpec, s conversion.Scope) error {
+	out.ID = in.ID
+	out.MachineID = in.MachineID
+	out.AllowUnhealthyMachine = in.AllowUnhealthyMachine
+	return nil
+}
+
+// Convert_v1beta1_InstanceTypeSpec_To_v1beta2_InstanceTypeSpec is an autogenerated conversion function.
+func Convert_v1beta1_InstanceTypeSpec_To_v1beta2_InstanceTypeSpec(in *v1beta1.InstanceTypeSpec, out *InstanceTypeSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_InstanceTypeSpec_To_v1beta2_InstanceTypeSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_InventorySpec_To_v1beta1_InventorySpec(in *InventorySpec, out *v1beta1.InventorySpec, s conversion.Scope) error {
+	out.LabelKeys = *(*[]string)(unsafe.Pointer(&in.LabelKeys))
+	out.NodeLabelPrefix = in.NodeLabelPrefix
+	return nil
+}
+
+// Convert_v1beta2_InventorySpec_To_v1beta1_InventorySpec is an autogenerated conversion function.
+func Convert_v1beta2_InventorySpec_To_v1beta1_InventorySpec(in *InventorySpec, out *v1beta1.InventorySpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_InventorySpec_To_v1beta1_InventorySpec(in, out, s)
+}
+
+func autoConvert_v1beta1_InventorySpec_To_v1beta2_InventorySpec(in *v1beta1.InventorySpec, out *InventorySpec, s conversion.Scope) error {
+	out.LabelKeys = *(*[]string)(unsafe.Pointer(&in.LabelKeys))
+	out.NodeLabelPrefix = in.NodeLabelPrefix
+	return nil
+}
+
+// Convert_v1beta1_InventorySpec_To_v1beta2_InventorySpec is an autogenerated conversion function.
+func Convert_v1beta1_InventorySpec_To_v1beta2_InventorySpec(in *v1beta1.InventorySpec, out *InventorySpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_InventorySpec_To_v1beta2_InventorySpec(in, out, s)
+}
+
+func autoConvert_v1beta2_MIGSpec_To_v1beta1_MIGSpec(in *MIGSpec, out *v1beta1.MIGSpec, s conversion.Scope) error {
+	out.GPU = in.GPU
+	out.Profiles = *(*[]string)(unsafe.Pointer(&in.Profiles))
+	return nil
+}
+
+// Convert_v1beta2_MIGSpec_To_v1beta1_MIGSpec is an autogenerated conversion function.
+func Convert_v1beta2_MIGSpec_To_v1beta1_MIGSpec(in *MIGSpec, out *v1beta1.MIGSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_MIGSpec_To_v1beta1_MIGSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_MIGSpec_To_v1beta2_MIGSpec(in *v1beta1.MIGSpec, out *MIGSpec, s conversion.Scope) error {
+	out.GPU = in.GPU
+	out.Profiles = *(*[]string)(unsafe.Pointer(&in.Profiles))
+	return nil
+}
+
+// Convert_v1beta1_MIGSpec_To_v1beta2_MIGSpec is an autogenerated conversion function.
+func Convert_v1beta1_MIGSpec_To_v1beta2_MIGSpec(in *v1beta1.MIGSpec, out *MIGSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_MIGSpec_To_v1beta2_MIGSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_MachineHealthIssueSpec_To_v1beta1_MachineHealthIssueSpec(in *MachineHealthIssueSpec, out *v1beta1.MachineHealthIssueSpec, s conversion.Scope) error {
+	out.Category = string(in.Category)
+	out.Summary = in.Summary
+	out.Details = in.Details
+	return nil
+}
+
+// Convert_v1beta2_MachineHealthIssueSpec_To_v1beta1_MachineHealthIssueSpec is an autogenerated conversion function.
+func Convert_v1beta2_MachineHealthIssueSpec_To_v1beta1_MachineHealthIssueSpec(in *MachineHealthIssueSpec, out *v1beta1.MachineHealthIssueSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_MachineHealthIssueSpec_To_v1beta1_MachineHealthIssueSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_MachineHealthIssueSpec_To_v1beta2_MachineHealthIssueSpec(in *v1beta1.MachineHealthIssueSpec, out *MachineHealthIssueSpec, s conversion.Scope) error {
+	out.Category = HealthIssueCategory(in.Category)
+	out.Summary = in.Summary
+	out.Details = in.Details
+	return nil
+}
+
+// Convert_v1beta1_MachineHealthIssueSpec_To_v1beta2_MachineHealthIssueSpec is an autogenerated conversion function.
+func Convert_v1beta1_MachineHealthIssueSpec_To_v1beta2_MachineHealthIssueSpec(in *v1beta1.MachineHealthIssueSpec, out *MachineHealthIssueSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_MachineHealthIssueSpec_To_v1beta2_MachineHealthIssueSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_NSGRule_To_v1beta1_NSGRule(in *NSGRule, out *v1beta1.NSGRule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Direction = string(in.Direction)
+	out.Protocol = string(in.Protocol)
+	out.PortRange = in.PortRange
+	out.SourcePortRange = in.SourcePortRange
+	out.SourceCIDR = in.SourceCIDR
+	out.DestinationCIDR = in.DestinationCIDR
+	out.Priority = (*int32)(unsafe.Pointer(in.Priority))
+	out.Action = string(in.Action)
+	return nil
+}
+
+// Convert_v1beta2_NSGRule_To_v1beta1_NSGRule is an autogenerated conversion function.
+func Convert_v1beta2_NSGRule_To_v1beta1_NSGRule(in *NSGRule, out *v1beta1.NSGRule, s conversion.Scope) error {
+	return autoConvert_v1beta2_NSGRule_To_v1beta1_NSGRule(in, out, s)
+}
+
+func autoConvert_v1beta1_NSGRule_To_v1beta2_NSGRule(in *v1beta1.NSGRule, out *NSGRule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Direction = NSGRuleDirection(in.Direction)
+	out.Protocol = NSGRuleProtocol(in.Protocol)
+	out.PortRange = in.PortRange
+	out.SourcePortRange = in.SourcePortRange
+	out.SourceCIDR = in.SourceCIDR
+	out.DestinationCIDR = in.DestinationCIDR
+	out.Priority = (*int32)(unsafe.Pointer(in.Priority))
+	out.Action = NSGRuleAction(in.Action)
+	return nil
+}
+
+// Convert_v1beta1_NSGRule_To_v1beta2_NSGRule is an autogenerated conversion function.
+func Convert_v1beta1_NSGRule_To_v1beta2_NSGRule(in *v1beta1.NSGRule, out *NSGRule, s conversion.Scope) error {
+	return autoConvert_v1beta1_NSGRule_To_v1beta2_NSGRule(in, out, s)
+}
+
+func autoConvert_v1beta2_NSGSpec_To_v1beta1_NSGSpec(in *NSGSpec, out *v1beta1.NSGSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Rules = *(*[]v1beta1.NSGRule)(unsafe.Pointer(&in.Rules))
+	return nil
+}
+
+// Convert_v1beta2_NSGSpec_To_v1beta1_NSGSpec is an autogenerated conversion function.
+func Convert_v1beta2_NSGSpec_To_v1beta1_NSGSpec(in *NSGSpec, out *v1beta1.NSGSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_NSGSpec_To_v1beta1_NSGSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_NSGSpec_To_v1beta2_NSGSpec(in *v1beta1.NSGSpec, out *NSGSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Rules = *(*[]NSGRule)(unsafe.Pointer(&in.Rules))
+	return nil
+}
+
+// Convert_v1beta1_NSGSpec_To_v1beta2_NSGSpec is an autogenerated conversion function.
+func Convert_v1beta1_NSGSpec_To_v1beta2_NSGSpec(in *v1beta1.NSGSpec, out *NSGSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_NSGSpec_To_v1beta2_NSGSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_NSGStatus_To_v1beta1_NSGStatus(in *NSGStatus, out *v1beta1.NSGStatus, s conversion.Scope) error {
+	if err := Convert_v1beta2_NetworkResourceStatus_To_v1beta1_NetworkResourceStatus(&in.NetworkResourceStatus, &out.NetworkResourceStatus, s); err != nil {
+		return err
+	}
+	out.AppliedRuleHash = in.AppliedRuleHash
+	return nil
+}
+
+// Convert_v1beta2_NSGStatus_To_v1beta1_NSGStatus is an autogenerated conversion function.
+func Convert_v1beta2_NSGStatus_To_v1beta1_NSGStatus(in *NSGStatus, out *v1beta1.NSGStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_NSGStatus_To_v1beta1_NSGStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_NSGStatus_To_v1beta2_NSGStatus(in *v1beta1.NSGStatus, out *NSGStatus, s conversion.Scope) error {
+	if err := Convert_v1beta1_NetworkResourceStatus_To_v1beta2_NetworkResourceStatus(&in.NetworkResourceStatus, &out.NetworkResourceStatus, s); err != nil {
+		return err
+	}
+	out.AppliedRuleHash = in.AppliedRuleHash
+	return nil
+}
+
+// Convert_v1beta1_NSGStatus_To_v1beta2_NSGStatus is an autogenerated conversion function.
+func Convert_v1beta1_NSGStatus_To_v1beta2_NSGStatus(in *v1beta1.NSGStatus, out *NSGStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_NSGStatus_To_v1beta2_NSGStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_NVLinkInterfaceSpec_To_v1beta1_NVLinkInterfaceSpec(in *NVLinkInterfaceSpec, out *v1beta1.NVLinkInterfaceSpec, s conversion.Scope) error {
+	out.LogicalPartitionID = in.LogicalPartitionID
+	out.DeviceInstance = (*int32)(unsafe.Pointer(in.DeviceInstance))
+	return nil
+}
+
+// Convert_v1beta2_NVLinkInterfaceSpec_To_v1beta1_NVLinkInterfaceSpec is an autogenerated conversion function.
+func Convert_v1beta2_NVLinkInterfaceSpec_To_v1beta1_NVLinkInterfaceSpec(in *NVLinkInterfaceSpec, out *v1beta1.NVLinkInterfaceSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_NVLinkInterfaceSpec_To_v1beta1_NVLinkInterfaceSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_NVLinkInterfaceSpec_To_v1beta2_NVLinkInterfaceSpec(in *v1beta1.NVLinkInterfaceSpec, out *NVLinkInterfaceSpec, s conversion.Scope) error {
+	out.LogicalPartitionID = in.LogicalPartitionID
+	out.DeviceInstance = (*int32)(unsafe.Pointer(in.DeviceInstance))
+	return nil
+}
+
+// Convert_v1beta1_NVLinkInterfaceSpec_To_v1beta2_NVLinkInterfaceSpec is an autogenerated conversion function.
+func Convert_v1beta1_NVLinkInterfaceSpec_To_v1beta2_NVLinkInterfaceSpec(in *v1beta1.NVLinkInterfaceSpec, out *NVLinkInterfaceSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_NVLinkInterfaceSpec_To_v1beta2_NVLinkInterfaceSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraCluster_To_v1beta1_NcxInfraCluster(in *NcxInfraCluster, out *v1beta1.NcxInfraCluster, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta2_NcxInfraClusterSpec_To_v1beta1_NcxInfraClusterSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_NcxInfraClusterStatus_To_v1beta1_NcxInfraClusterStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraCluster_To_v1beta1_NcxInfraCluster is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraCluster_To_v1beta1_NcxInfraCluster(in *NcxInfraCluster, out *v1beta1.NcxInfraCluster, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraCluster_To_v1beta1_NcxInfraCluster(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraCluster_To_v1beta2_NcxInfraCluster(in *v1beta1.NcxInfraCluster, out *NcxInfraCluster, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_NcxInfraClusterSpec_To_v1beta2_NcxInfraClusterSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_NcxInfraClusterStatus_To_v1beta2_NcxInfraClusterStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraCluster_To_v1beta2_NcxInfraCluster is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraCluster_To_v1beta2_NcxInfraCluster(in *v1beta1.NcxInfraCluster, out *NcxInfraCluster, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraCluster_To_v1beta2_NcxInfraCluster(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraClusterList_To_v1beta1_NcxInfraClusterList(in *NcxInfraClusterList, out *v1beta1.NcxInfraClusterList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta1.NcxInfraCluster, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta2_NcxInfraCluster_To_v1beta1_NcxInfraCluster(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraClusterList_To_v1beta1_NcxInfraClusterList is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraClusterList_To_v1beta1_NcxInfraClusterList(in *NcxInfraClusterList, out *v1beta1.NcxInfraClusterList, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraClusterList_To_v1beta1_NcxInfraClusterList(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraClusterList_To_v1beta2_NcxInfraClusterList(in *v1beta1.NcxInfraClusterList, out *NcxInfraClusterList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraCluster, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_NcxInfraCluster_To_v1beta2_NcxInfraCluster(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraClusterList_To_v1beta2_NcxInfraClusterList is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraClusterList_To_v1beta2_NcxInfraClusterList(in *v1beta1.NcxInfraClusterList, out *NcxInfraClusterList, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraClusterList_To_v1beta2_NcxInfraClusterList(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraClusterSpec_To_v1beta1_NcxInfraClusterSpec(in *NcxInfraClusterSpec, out *v1beta1.NcxInfraClusterSpec, s conversion.Scope) error {
+	if err := Convert_v1beta2_SiteReference_To_v1beta1_SiteReference(&in.SiteRef, &out.SiteRef, s); err != nil {
+		return err
+	}
+	out.TenantID = in.TenantID
+	if err := Convert_v1beta2_VPCSpec_To_v1beta1_VPCSpec(&in.VPC, &out.VPC, s); err != nil {
+		return err
+	}
+	out.Subnets = *(*[]v1beta1.SubnetSpec)(unsafe.Pointer(&in.Subnets))
+	out.VPCPrefixes = *(*[]v1beta1.VPCPrefixSpec)(unsafe.Pointer(&in.VPCPrefixes))
+	out.VPCPeerings = *(*[]v1beta1.VPCPeeringSpec)(unsafe.Pointer(&in.VPCPeerings))
+	out.Network = (*v1beta1.NetworkServices)(unsafe.Pointer(in.Network))
+	out.InstanceLabels = *(*map[string]string)(unsafe.Pointer(&in.InstanceLabels))
+	out.WarmPool = (*v1beta1.WarmPoolSpec)(unsafe.Pointer(in.WarmPool))
+	out.Provisioning = (*v1beta1.ProvisioningSpec)(unsafe.Pointer(in.Provisioning))
+	out.Proxy = (*v1beta1.ProxySpec)(unsafe.Pointer(in.Proxy))
+	// WARNING: in.ControlPlaneEndpoint requires manual conversion: inconvertible types (sigs.k8s.io/cluster-api/api/core/v1beta2.APIEndpoint vs *sigs.k8s.io/cluster-api/api/core/v1beta2.APIEndpoint)
+	out.ControlPlaneEndpointManagement = v1beta1.ControlPlaneEndpointManagement(in.ControlPlaneEndpointManagement)
+	out.Organization = in.Organization
+	if err := Convert_v1beta2_AuthenticationSpec_To_v1beta1_AuthenticationSpec(&in.Authentication, &out.Authentication, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1beta1_NcxInfraClusterSpec_To_v1beta2_NcxInfraClusterSpec(in *v1beta1.NcxInfraClusterSpec, out *NcxInfraClusterSpec, s conversion.Scope) error {
+	if err := Convert_v1beta1_SiteReference_To_v1beta2_SiteReference(&in.SiteRef, &out.SiteRef, s); err != nil {
+		return err
+	}
+	out.TenantID = in.TenantID
+	if err := Convert_v1beta1_VPCSpec_To_v1beta2_VPCSpec(&in.VPC, &out.VPC, s); err != nil {
+		return err
+	}
+	out.Subnets = *(*[]SubnetSpec)(unsafe.Pointer(&in.Subnets))
+	out.VPCPrefixes = *(*[]VPCPrefixSpec)(unsafe.Pointer(&in.VPCPrefixes))
+	out.VPCPeerings = *(*[]VPCPeeringSpec)(unsafe.Pointer(&in.VPCPeerings))
+	out.Network = (*NetworkServices)(unsafe.Pointer(in.Network))
+	out.InstanceLabels = *(*map[string]string)(unsafe.Pointer(&in.InstanceLabels))
+	out.WarmPool = (*WarmPoolSpec)(unsafe.Pointer(in.WarmPool))
+	out.Provisioning = (*ProvisioningSpec)(unsafe.Pointer(in.Provisioning))
+	out.Proxy = (*ProxySpec)(unsafe.Pointer(in.Proxy))
+	// WARNING: in.ControlPlaneEndpoint requires manual conversion: inconvertible types (*sigs.k8s.io/cluster-api/api/core/v1beta2.APIEndpoint vs sigs.k8s.io/cluster-api/api/core/v1beta2.APIEndpoint)
+	out.ControlPlaneEndpointManagement = ControlPlaneEndpointManagement(in.ControlPlaneEndpointManagement)
+	out.Organization = in.Organization
+	if err := Convert_v1beta1_AuthenticationSpec_To_v1beta2_AuthenticationSpec(&in.Authentication, &out.Authentication, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1beta2_NcxInfraClusterStatus_To_v1beta1_NcxInfraClusterStatus(in *NcxInfraClusterStatus, out *v1beta1.NcxInfraClusterStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.Phase = in.Phase
+	if err := Convert_v1beta2_NetworkStatus_To_v1beta1_NetworkStatus(&in.NetworkStatus, &out.NetworkStatus, s); err != nil {
+		return err
+	}
+	out.OrgName = in.OrgName
+	out.Endpoint = in.Endpoint
+	out.CredentialsSecretRef = (*v1.SecretReference)(unsafe.Pointer(in.CredentialsSecretRef))
+	out.ControlPlaneEndpoint = (*corev1beta2.APIEndpoint)(unsafe.Pointer(in.ControlPlaneEndpoint))
+	out.BreakGlassSSH = (*v1beta1.BreakGlassSSHStatus)(unsafe.Pointer(in.BreakGlassSSH))
+	out.WarmPoolInstances = in.WarmPoolInstances
+	out.Quotas = *(*[]v1beta1.QuotaStatus)(unsafe.Pointer(&in.Quotas))
+	out.FailureReason = (*errors.ClusterStatusError)(unsafe.Pointer(in.FailureReason))
+	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	out.FailureDomains = *(*[]corev1beta2.FailureDomain)(unsafe.Pointer(&in.FailureDomains))
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraClusterStatus_To_v1beta1_NcxInfraClusterStatus is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraClusterStatus_To_v1beta1_NcxInfraClusterStatus(in *NcxInfraClusterStatus, out *v1beta1.NcxInfraClusterStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraClusterStatus_To_v1beta1_NcxInfraClusterStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraClusterStatus_To_v1beta2_NcxInfraClusterStatus(in *v1beta1.NcxInfraClusterStatus, out *NcxInfraClusterStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.Phase = in.Phase
+	// WARNING: in.VPCID requires manual conversion: does not exist in peer-type
+	if err := Convert_v1beta1_NetworkStatus_To_v1beta2_NetworkStatus(&in.NetworkStatus, &out.NetworkStatus, s); err != nil {
+		return err
+	}
+	out.OrgName = in.OrgName
+	out.Endpoint = in.Endpoint
+	out.CredentialsSecretRef = (*v1.SecretReference)(unsafe.Pointer(in.CredentialsSecretRef))
+	out.ControlPlaneEndpoint = (*corev1beta2.APIEndpoint)(unsafe.Pointer(in.ControlPlaneEndpoint))
+	out.BreakGlassSSH = (*BreakGlassSSHStatus)(unsafe.Pointer(in.BreakGlassSSH))
+	out.WarmPoolInstances = in.WarmPoolInstances
+	out.Quotas = *(*[]QuotaStatus)(unsafe.Pointer(&in.Quotas))
+	out.FailureReason = (*errors.ClusterStatusError)(unsafe.Pointer(in.FailureReason))
+	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	out.FailureDomains = *(*[]corev1beta2.FailureDomain)(unsafe.Pointer(&in.FailureDomains))
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+func autoConvert_v1beta2_NcxInfraMachine_To_v1beta1_NcxInfraMachine(in *NcxInfraMachine, out *v1beta1.NcxInfraMachine, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta2_NcxInfraMachineSpec_To_v1beta1_NcxInfraMachineSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_NcxInfraMachineStatus_To_v1beta1_NcxInfraMachineStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraMachine_To_v1beta1_NcxInfraMachine is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraMachine_To_v1beta1_NcxInfraMachine(in *NcxInfraMachine, out *v1beta1.NcxInfraMachine, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraMachine_To_v1beta1_NcxInfraMachine(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraMachine_To_v1beta2_NcxInfraMachine(in *v1beta1.NcxInfraMachine, out *NcxInfraMachine, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_NcxInfraMachineSpec_To_v1beta2_NcxInfraMachineSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_NcxInfraMachineStatus_To_v1beta2_NcxInfraMachineStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraMachine_To_v1beta2_NcxInfraMachine is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraMachine_To_v1beta2_NcxInfraMachine(in *v1beta1.NcxInfraMachine, out *NcxInfraMachine, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraMachine_To_v1beta2_NcxInfraMachine(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraMachineList_To_v1beta1_NcxInfraMachineList(in *NcxInfraMachineList, out *v1beta1.NcxInfraMachineList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]v1beta1.NcxInfraMachine)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraMachineList_To_v1beta1_NcxInfraMachineList is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraMachineList_To_v1beta1_NcxInfraMachineList(in *NcxInfraMachineList, out *v1beta1.NcxInfraMachineList, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraMachineList_To_v1beta1_NcxInfraMachineList(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraMachineList_To_v1beta2_NcxInfraMachineList(in *v1beta1.NcxInfraMachineList, out *NcxInfraMachineList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]NcxInfraMachine)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraMachineList_To_v1beta2_NcxInfraMachineList is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraMachineList_To_v1beta2_NcxInfraMachineList(in *v1beta1.NcxInfraMachineList, out *NcxInfraMachineList, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraMachineList_To_v1beta2_NcxInfraMachineList(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraMachineSpec_To_v1beta1_NcxInfraMachineSpec(in *NcxInfraMachineSpec, out *v1beta1.NcxInfraMachineSpec, s conversion.Scope) error {
+	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
+	out.TenantID = in.TenantID
+	if err := Convert_v1beta2_InstanceTypeSpec_To_v1beta1_InstanceTypeSpec(&in.InstanceType, &out.InstanceType, s); err != nil {
+		return err
+	}
+	out.OperatingSystem = (*v1beta1.OSSpec)(unsafe.Pointer(in.OperatingSystem))
+	if err := Convert_v1beta2_NetworkSpec_To_v1beta1_NetworkSpec(&in.Network, &out.Network, s); err != nil {
+		return err
+	}
+	out.SSHKeyGroups = *(*[]string)(unsafe.Pointer(&in.SSHKeyGroups))
+	out.InfiniBandInterfaces = *(*[]v1beta1.InfiniBandInterfaceSpec)(unsafe.Pointer(&in.InfiniBandInterfaces))
+	out.NVLinkInterfaces = *(*[]v1beta1.NVLinkInterfaceSpec)(unsafe.Pointer(&in.NVLinkInterfaces))
+	out.DPUExtensionServices = *(*[]v1beta1.DPUExtensionServiceSpec)(unsafe.Pointer(&in.DPUExtensionServices))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Description = in.Description
+	out.AlwaysBootWithCustomIpxe = in.AlwaysBootWithCustomIpxe
+	out.PhoneHomeEnabled = (*bool)(unsafe.Pointer(in.PhoneHomeEnabled))
+	out.Deletion = (*v1beta1.DeletionSpec)(unsafe.Pointer(in.Deletion))
+	out.ReprovisionPolicy = v1beta1.ReprovisionPolicy(in.ReprovisionPolicy)
+	out.Placement = (*v1beta1.PlacementSpec)(unsafe.Pointer(in.Placement))
+	out.Inventory = (*v1beta1.InventorySpec)(unsafe.Pointer(in.Inventory))
+	out.NodeTopologyLabels = in.NodeTopologyLabels
+	out.Security = (*v1beta1.SecuritySpec)(unsafe.Pointer(in.Security))
+	out.Storage = (*v1beta1.StorageSpec)(unsafe.Pointer(in.Storage))
+	out.GPUConfig = (*v1beta1.GPUConfigSpec)(unsafe.Pointer(in.GPUConfig))
+	out.KernelArgs = *(*[]string)(unsafe.Pointer(&in.KernelArgs))
+	out.Tuning = (*v1beta1.TuningSpec)(unsafe.Pointer(in.Tuning))
+	out.HostnameFormat = in.HostnameFormat
+	out.Domain = in.Domain
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraMachineSpec_To_v1beta1_NcxInfraMachineSpec is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraMachineSpec_To_v1beta1_NcxInfraMachineSpec(in *NcxInfraMachineSpec, out *v1beta1.NcxInfraMachineSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraMachineSpec_To_v1beta1_NcxInfraMachineSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraMachineSpec_To_v1beta2_NcxInfraMachineSpec(in *v1beta1.NcxInfraMachineSpec, out *NcxInfraMachineSpec, s conversion.Scope) error {
+	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
+	out.TenantID = in.TenantID
+	if err := Convert_v1beta1_InstanceTypeSpec_To_v1beta2_InstanceTypeSpec(&in.InstanceType, &out.InstanceType, s); err != nil {
+		return err
+	}
+	out.OperatingSystem = (*OSSpec)(unsafe.Pointer(in.OperatingSystem))
+	if err := Convert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec(&in.Network, &out.Network, s); err != nil {
+		return err
+	}
+	out.SSHKeyGroups = *(*[]string)(unsafe.Pointer(&in.SSHKeyGroups))
+	out.InfiniBandInterfaces = *(*[]InfiniBandInterfaceSpec)(unsafe.Pointer(&in.InfiniBandInterfaces))
+	out.NVLinkInterfaces = *(*[]NVLinkInterfaceSpec)(unsafe.Pointer(&in.NVLinkInterfaces))
+	out.DPUExtensionServices = *(*[]DPUExtensionServiceSpec)(unsafe.Pointer(&in.DPUExtensionServices))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Description = in.Description
+	out.AlwaysBootWithCustomIpxe = in.AlwaysBootWithCustomIpxe
+	out.PhoneHomeEnabled = (*bool)(unsafe.Pointer(in.PhoneHomeEnabled))
+	out.Deletion = (*DeletionSpec)(unsafe.Pointer(in.Deletion))
+	out.ReprovisionPolicy = ReprovisionPolicy(in.ReprovisionPolicy)
+	out.Placement = (*PlacementSpec)(unsafe.Pointer(in.Placement))
+	out.Inventory = (*InventorySpec)(unsafe.Pointer(in.Inventory))
+	out.NodeTopologyLabels = in.NodeTopologyLabels
+	out.Security = (*SecuritySpec)(unsafe.Pointer(in.Security))
+	out.Storage = (*StorageSpec)(unsafe.Pointer(in.Storage))
+	out.GPUConfig = (*GPUConfigSpec)(unsafe.Pointer(in.GPUConfig))
+	out.KernelArgs = *(*[]string)(unsafe.Pointer(&in.KernelArgs))
+	out.Tuning = (*TuningSpec)(unsafe.Pointer(in.Tuning))
+	out.HostnameFormat = in.HostnameFormat
+	out.Domain = in.Domain
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraMachineSpec_To_v1beta2_NcxInfraMachineSpec is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraMachineSpec_To_v1beta2_NcxInfraMachineSpec(in *v1beta1.NcxInfraMachineSpec, out *NcxInfraMachineSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraMachineSpec_To_v1beta2_NcxInfraMachineSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraMachineStatus_To_v1beta1_NcxInfraMachineStatus(in *NcxInfraMachineStatus, out *v1beta1.NcxInfraMachineStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.Phase = in.Phase
+	out.InstanceID = in.InstanceID
+	out.InstanceOrigin = (*v1beta1.ResourceOrigin)(unsafe.Pointer(in.InstanceOrigin))
+	out.MachineID = in.MachineID
+	out.InstancePhase = v1beta1.InstancePhase(in.InstancePhase)
+	out.InstanceState = string(in.InstanceState)
+	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
+	out.NodeName = in.NodeName
+	out.Addresses = *(*[]corev1beta2.MachineAddress)(unsafe.Pointer(&in.Addresses))
+	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
+	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	out.Placement = (*v1beta1.PlacementStatus)(unsafe.Pointer(in.Placement))
+	out.LastPowerAction = (*v1beta1.PowerActionStatus)(unsafe.Pointer(in.LastPowerAction))
+	out.Console = (*v1beta1.ConsoleStatus)(unsafe.Pointer(in.Console))
+	out.Provisioned = (*v1beta1.ProvisionedStatus)(unsafe.Pointer(in.Provisioned))
+	out.Inventory = *(*map[string]string)(unsafe.Pointer(&in.Inventory))
+	out.SRIOVInterfaces = *(*[]v1beta1.SRIOVInterfaceStatus)(unsafe.Pointer(&in.SRIOVInterfaces))
+	out.Topology = *(*map[string]string)(unsafe.Pointer(&in.Topology))
+	out.Hardware = (*v1beta1.HardwareStatus)(unsafe.Pointer(in.Hardware))
+	out.Hostname = in.Hostname
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraMachineStatus_To_v1beta1_NcxInfraMachineStatus is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraMachineStatus_To_v1beta1_NcxInfraMachineStatus(in *NcxInfraMachineStatus, out *v1beta1.NcxInfraMachineStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraMachineStatus_To_v1beta1_NcxInfraMachineStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraMachineStatus_To_v1beta2_NcxInfraMachineStatus(in *v1beta1.NcxInfraMachineStatus, out *NcxInfraMachineStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.Phase = in.Phase
+	out.InstanceID = in.InstanceID
+	out.InstanceOrigin = (*ResourceOrigin)(unsafe.Pointer(in.InstanceOrigin))
+	out.MachineID = in.MachineID
+	out.InstancePhase = InstancePhase(in.InstancePhase)
+	out.InstanceState = InstanceState(in.InstanceState)
+	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
+	out.NodeName = in.NodeName
+	out.Addresses = *(*[]corev1beta2.MachineAddress)(unsafe.Pointer(&in.Addresses))
+	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
+	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	out.Placement = (*PlacementStatus)(unsafe.Pointer(in.Placement))
+	out.LastPowerAction = (*PowerActionStatus)(unsafe.Pointer(in.LastPowerAction))
+	out.Console = (*ConsoleStatus)(unsafe.Pointer(in.Console))
+	out.Provisioned = (*ProvisionedStatus)(unsafe.Pointer(in.Provisioned))
+	out.Inventory = *(*map[string]string)(unsafe.Pointer(&in.Inventory))
+	out.SRIOVInterfaces = *(*[]SRIOVInterfaceStatus)(unsafe.Pointer(&in.SRIOVInterfaces))
+	out.Topology = *(*map[string]string)(unsafe.Pointer(&in.Topology))
+	out.Hardware = (*HardwareStatus)(unsafe.Pointer(in.Hardware))
+	out.Hostname = in.Hostname
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraMachineStatus_To_v1beta2_NcxInfraMachineStatus is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraMachineStatus_To_v1beta2_NcxInfraMachineStatus(in *v1beta1.NcxInfraMachineStatus, out *NcxInfraMachineStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraMachineStatus_To_v1beta2_NcxInfraMachineStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraMachineTemplate_To_v1beta1_NcxInfraMachineTemplate(in *NcxInfraMachineTemplate, out *v1beta1.NcxInfraMachineTemplate, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta2_NcxInfraMachineTemplateSpec_To_v1beta1_NcxInfraMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_NcxInfraMachineTemplateStatus_To_v1beta1_NcxInfraMachineTemplateStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraMachineTemplate_To_v1beta1_NcxInfraMachineTemplate is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraMachineTemplate_To_v1beta1_NcxInfraMachineTemplate(in *NcxInfraMachineTemplate, out *v1beta1.NcxInfraMachineTemplate, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraMachineTemplate_To_v1beta1_NcxInfraMachineTemplate(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraMachineTemplate_To_v1beta2_NcxInfraMachineTemplate(in *v1beta1.NcxInfraMachineTemplate, out *NcxInfraMachineTemplate, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_NcxInfraMachineTemplateSpec_To_v1beta2_NcxInfraMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_NcxInfraMachineTemplateStatus_To_v1beta2_NcxInfraMachineTemplateStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraMachineTemplate_To_v1beta2_NcxInfraMachineTemplate is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraMachineTemplate_To_v1beta2_NcxInfraMachineTemplate(in *v1beta1.NcxInfraMachineTemplate, out *NcxInfraMachineTemplate, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraMachineTemplate_To_v1beta2_NcxInfraMachineTemplate(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraMachineTemplateList_To_v1beta1_NcxInfraMachineTemplateList(in *NcxInfraMachineTemplateList, out *v1beta1.NcxInfraMachineTemplateList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]v1beta1.NcxInfraMachineTemplate)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraMachineTemplateList_To_v1beta1_NcxInfraMachineTemplateList is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraMachineTemplateList_To_v1beta1_NcxInfraMachineTemplateList(in *NcxInfraMachineTemplateList, out *v1beta1.NcxInfraMachineTemplateList, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraMachineTemplateList_To_v1beta1_NcxInfraMachineTemplateList(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraMachineTemplateList_To_v1beta2_NcxInfraMachineTemplateList(in *v1beta1.NcxInfraMachineTemplateList, out *NcxInfraMachineTemplateList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]NcxInfraMachineTemplate)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraMachineTemplateList_To_v1beta2_NcxInfraMachineTemplateList is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraMachineTemplateList_To_v1beta2_NcxInfraMachineTemplateList(in *v1beta1.NcxInfraMachineTemplateList, out *NcxInfraMachineTemplateList, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraMachineTemplateList_To_v1beta2_NcxInfraMachineTemplateList(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraMachineTemplateResource_To_v1beta1_NcxInfraMachineTemplateResource(in *NcxInfraMachineTemplateResource, out *v1beta1.NcxInfraMachineTemplateResource, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta2_NcxInfraMachineSpec_To_v1beta1_NcxInfraMachineSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraMachineTemplateResource_To_v1beta1_NcxInfraMachineTemplateResource is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraMachineTemplateResource_To_v1beta1_NcxInfraMachineTemplateResource(in *NcxInfraMachineTemplateResource, out *v1beta1.NcxInfraMachineTemplateResource, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraMachineTemplateResource_To_v1beta1_NcxInfraMachineTemplateResource(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraMachineTemplateResource_To_v1beta2_NcxInfraMachineTemplateResource(in *v1beta1.NcxInfraMachineTemplateResource, out *NcxInfraMachineTemplateResource, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_NcxInfraMachineSpec_To_v1beta2_NcxInfraMachineSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraMachineTemplateResource_To_v1beta2_NcxInfraMachineTemplateResource is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraMachineTemplateResource_To_v1beta2_NcxInfraMachineTemplateResource(in *v1beta1.NcxInfraMachineTemplateResource, out *NcxInfraMachineTemplateResource, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraMachineTemplateResource_To_v1beta2_NcxInfraMachineTemplateResource(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraMachineTemplateSpec_To_v1beta1_NcxInfraMachineTemplateSpec(in *NcxInfraMachineTemplateSpec, out *v1beta1.NcxInfraMachineTemplateSpec, s conversion.Scope) error {
+	if err := Convert_v1beta2_NcxInfraMachineTemplateResource_To_v1beta1_NcxInfraMachineTemplateResource(&in.Template, &out.Template, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraMachineTemplateSpec_To_v1beta1_NcxInfraMachineTemplateSpec is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraMachineTemplateSpec_To_v1beta1_NcxInfraMachineTemplateSpec(in *NcxInfraMachineTemplateSpec, out *v1beta1.NcxInfraMachineTemplateSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraMachineTemplateSpec_To_v1beta1_NcxInfraMachineTemplateSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraMachineTemplateSpec_To_v1beta2_NcxInfraMachineTemplateSpec(in *v1beta1.NcxInfraMachineTemplateSpec, out *NcxInfraMachineTemplateSpec, s conversion.Scope) error {
+	if err := Convert_v1beta1_NcxInfraMachineTemplateResource_To_v1beta2_NcxInfraMachineTemplateResource(&in.Template, &out.Template, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraMachineTemplateSpec_To_v1beta2_NcxInfraMachineTemplateSpec is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraMachineTemplateSpec_To_v1beta2_NcxInfraMachineTemplateSpec(in *v1beta1.NcxInfraMachineTemplateSpec, out *NcxInfraMachineTemplateSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraMachineTemplateSpec_To_v1beta2_NcxInfraMachineTemplateSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_NcxInfraMachineTemplateStatus_To_v1beta1_NcxInfraMachineTemplateStatus(in *NcxInfraMachineTemplateStatus, out *v1beta1.NcxInfraMachineTemplateStatus, s conversion.Scope) error {
+	out.Capacity = *(*v1.ResourceList)(unsafe.Pointer(&in.Capacity))
+	return nil
+}
+
+// Convert_v1beta2_NcxInfraMachineTemplateStatus_To_v1beta1_NcxInfraMachineTemplateStatus is an autogenerated conversion function.
+func Convert_v1beta2_NcxInfraMachineTemplateStatus_To_v1beta1_NcxInfraMachineTemplateStatus(in *NcxInfraMachineTemplateStatus, out *v1beta1.NcxInfraMachineTemplateStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_NcxInfraMachineTemplateStatus_To_v1beta1_NcxInfraMachineTemplateStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_NcxInfraMachineTemplateStatus_To_v1beta2_NcxInfraMachineTemplateStatus(in *v1beta1.NcxInfraMachineTemplateStatus, out *NcxInfraMachineTemplateStatus, s conversion.Scope) error {
+	out.Capacity = *(*v1.ResourceList)(unsafe.Pointer(&in.Capacity))
+	return nil
+}
+
+// Convert_v1beta1_NcxInfraMachineTemplateStatus_To_v1beta2_NcxInfraMachineTemplateStatus is an autogenerated conversion function.
+func Convert_v1beta1_NcxInfraMachineTemplateStatus_To_v1beta2_NcxInfraMachineTemplateStatus(in *v1beta1.NcxInfraMachineTemplateStatus, out *NcxInfraMachineTemplateStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_NcxInfraMachineTemplateStatus_To_v1beta2_NcxInfraMachineTemplateStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_NetworkInterface_To_v1beta1_NetworkInterface(in *NetworkInterface, out *v1beta1.NetworkInterface, s conversion.Scope) error {
+	out.SubnetName = in.SubnetName
+	out.VPCPrefixName = in.VPCPrefixName
+	out.IpAddress = in.IpAddress
+	out.IsPhysical = in.IsPhysical
+	out.ClusterRef = (*v1.LocalObjectReference)(unsafe.Pointer(in.ClusterRef))
+	out.SRIOV = (*v1beta1.SRIOVSpec)(unsafe.Pointer(in.SRIOV))
+	out.VLAN = (*v1beta1.VLANSpec)(unsafe.Pointer(in.VLAN))
+	return nil
+}
+
+// Convert_v1beta2_NetworkInterface_To_v1beta1_NetworkInterface is an autogenerated conversion function.
+func Convert_v1beta2_NetworkInterface_To_v1beta1_NetworkInterface(in *NetworkInterface, out *v1beta1.NetworkInterface, s conversion.Scope) error {
+	return autoConvert_v1beta2_NetworkInterface_To_v1beta1_NetworkInterface(in, out, s)
+}
+
+func autoConvert_v1beta1_NetworkInterface_To_v1beta2_NetworkInterface(in *v1beta1.NetworkInterface, out *NetworkInterface, s conversion.Scope) error {
+	out.SubnetName = in.SubnetName
+	out.VPCPrefixName = in.VPCPrefixName
+	out.IpAddress = in.IpAddress
+	out.IsPhysical = in.IsPhysical
+	out.ClusterRef = (*v1.LocalObjectReference)(unsafe.Pointer(in.ClusterRef))
+	out.SRIOV = (*SRIOVSpec)(unsafe.Pointer(in.SRIOV))
+	out.VLAN = (*VLANSpec)(unsafe.Pointer(in.VLAN))
+	return nil
+}
+
+// Convert_v1beta1_NetworkInterface_To_v1beta2_NetworkInterface is an autogenerated conversion function.
+func Convert_v1beta1_NetworkInterface_To_v1beta2_NetworkInterface(in *v1beta1.NetworkInterface, out *NetworkInterface, s conversion.Scope) error {
+	return autoConvert_v1beta1_NetworkInterface_To_v1beta2_NetworkInterface(in, out, s)
+}
+
+func autoConvert_v1beta2_NetworkResourceStatus_To_v1beta1_NetworkResourceStatus(in *NetworkResourceStatus, out *v1beta1.NetworkResourceStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ID = in.ID
+	out.CIDR = in.CIDR
+	out.State = v1beta1.NetworkResourceState(in.State)
+	out.LastError = in.LastError
+	return nil
+}
+
+// Convert_v1beta2_NetworkResourceStatus_To_v1beta1_NetworkResourceStatus is an autogenerated conversion function.
+func Convert_v1beta2_NetworkResourceStatus_To_v1beta1_NetworkResourceStatus(in *NetworkResourceStatus, out *v1beta1.NetworkResourceStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_NetworkResourceStatus_To_v1beta1_NetworkResourceStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_NetworkResourceStatus_To_v1beta2_NetworkResourceStatus(in *v1beta1.NetworkResourceStatus, out *NetworkResourceStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ID = in.ID
+	out.CIDR = in.CIDR
+	out.State = NetworkResourceState(in.State)
+	out.LastError = in.LastError
+	return nil
+}
+
+// Convert_v1beta1_NetworkResourceStatus_To_v1beta2_NetworkResourceStatus is an autogenerated conversion function.
+func Convert_v1beta1_NetworkResourceStatus_To_v1beta2_NetworkResourceStatus(in *v1beta1.NetworkResourceStatus, out *NetworkResourceStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_NetworkResourceStatus_To_v1beta2_NetworkResourceStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_NetworkServices_To_v1beta1_NetworkServices(in *NetworkServices, out *v1beta1.NetworkServices, s conversion.Scope) error {
+	out.DNSServers = *(*[]string)(unsafe.Pointer(&in.DNSServers))
+	out.SearchDomains = *(*[]string)(unsafe.Pointer(&in.SearchDomains))
+	out.NTPServers = *(*[]string)(unsafe.Pointer(&in.NTPServers))
+	return nil
+}
+
+// Convert_v1beta2_NetworkServices_To_v1beta1_NetworkServices is an autogenerated conversion function.
+func Convert_v1beta2_NetworkServices_To_v1beta1_NetworkServices(in *NetworkServices, out *v1beta1.NetworkServices, s conversion.Scope) error {
+	return autoConvert_v1beta2_NetworkServices_To_v1beta1_NetworkServices(in, out, s)
+}
+
+func autoConvert_v1beta1_NetworkServices_To_v1beta2_NetworkServices(in *v1beta1.NetworkServices, out *NetworkServices, s conversion.Scope) error {
+	out.DNSServers = *(*[]string)(unsafe.Pointer(&in.DNSServers))
+	out.SearchDomains = *(*[]string)(unsafe.Pointer(&in.SearchDomains))
+	out.NTPServers = *(*[]string)(unsafe.Pointer(&in.NTPServers))
+	return nil
+}
+
+// Convert_v1beta1_NetworkServices_To_v1beta2_NetworkServices is an autogenerated conversion function.
+func Convert_v1beta1_NetworkServices_To_v1beta2_NetworkServices(in *v1beta1.NetworkServices, out *NetworkServices, s conversion.Scope) error {
+	return autoConvert_v1beta1_NetworkServices_To_v1beta2_NetworkServices(in, out, s)
+}
+
+func autoConvert_v1beta2_NetworkSpec_To_v1beta1_NetworkSpec(in *NetworkSpec, out *v1beta1.NetworkSpec, s conversion.Scope) error {
+	out.SubnetName = in.SubnetName
+	out.VPCPrefixName = in.VPCPrefixName
+	out.IpAddress = in.IpAddress
+	out.AdditionalInterfaces = *(*[]v1beta1.NetworkInterface)(unsafe.Pointer(&in.AdditionalInterfaces))
+	if err := Convert_v1beta2_NetworkServices_To_v1beta1_NetworkServices(&in.NetworkServices, &out.NetworkServices, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_NetworkSpec_To_v1beta1_NetworkSpec is an autogenerated conversion function.
+func Convert_v1beta2_NetworkSpec_To_v1beta1_NetworkSpec(in *NetworkSpec, out *v1beta1.NetworkSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_NetworkSpec_To_v1beta1_NetworkSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec(in *v1beta1.NetworkSpec, out *NetworkSpec, s conversion.Scope) error {
+	out.SubnetName = in.SubnetName
+	out.VPCPrefixName = in.VPCPrefixName
+	out.IpAddress = in.IpAddress
+	out.AdditionalInterfaces = *(*[]NetworkInterface)(unsafe.Pointer(&in.AdditionalInterfaces))
+	if err := Convert_v1beta1_NetworkServices_To_v1beta2_NetworkServices(&in.NetworkServices, &out.NetworkServices, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec is an autogenerated conversion function.
+func Convert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec(in *v1beta1.NetworkSpec, out *NetworkSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_NetworkStatus_To_v1beta1_NetworkStatus(in *NetworkStatus, out *v1beta1.NetworkStatus, s conversion.Scope) error {
+	out.VPC = (*v1beta1.NetworkResourceStatus)(unsafe.Pointer(in.VPC))
+	out.SiteVPCs = *(*[]v1beta1.NetworkResourceStatus)(unsafe.Pointer(&in.SiteVPCs))
+	out.IPBlocks = *(*[]v1beta1.IPBlockStatus)(unsafe.Pointer(&in.IPBlocks))
+	out.Subnets = *(*[]v1beta1.NetworkResourceStatus)(unsafe.Pointer(&in.Subnets))
+	out.VPCPrefixes = *(*[]v1beta1.NetworkResourceStatus)(unsafe.Pointer(&in.VPCPrefixes))
+	out.VPCPeerings = *(*[]v1beta1.NetworkResourceStatus)(unsafe.Pointer(&in.VPCPeerings))
+	out.NSG = (*v1beta1.NSGStatus)(unsafe.Pointer(in.NSG))
+	out.Origins = *(*map[string]v1beta1.ResourceOrigin)(unsafe.Pointer(&in.Origins))
+	return nil
+}
+
+// Convert_v1beta2_NetworkStatus_To_v1beta1_NetworkStatus is an autogenerated conversion function.
+func Convert_v1beta2_NetworkStatus_To_v1beta1_NetworkStatus(in *NetworkStatus, out *v1beta1.NetworkStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_NetworkStatus_To_v1beta1_NetworkStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_NetworkStatus_To_v1beta2_NetworkStatus(in *v1beta1.NetworkStatus, out *NetworkStatus, s conversion.Scope) error {
+	out.VPC = (*NetworkResourceStatus)(unsafe.Pointer(in.VPC))
+	out.SiteVPCs = *(*[]NetworkResourceStatus)(unsafe.Pointer(&in.SiteVPCs))
+	out.IPBlocks = *(*[]IPBlockStatus)(unsafe.Pointer(&in.IPBlocks))
+	out.Subnets = *(*[]NetworkResourceStatus)(unsafe.Pointer(&in.Subnets))
+	out.VPCPrefixes = *(*[]NetworkResourceStatus)(unsafe.Pointer(&in.VPCPrefixes))
+	out.VPCPeerings = *(*[]NetworkResourceStatus)(unsafe.Pointer(&in.VPCPeerings))
+	out.NSG = (*NSGStatus)(unsafe.Pointer(in.NSG))
+	out.Origins = *(*map[string]ResourceOrigin)(unsafe.Pointer(&in.Origins))
+	// WARNING: in.SubnetIDs requires manual conversion: does not exist in peer-type
+	// WARNING: in.VPCPrefixIDs requires manual conversion: does not exist in peer-type
+	// WARNING: in.VPCPeeringIDs requires manual conversion: does not exist in peer-type
+	// WARNING: in.NSGID requires manual conversion: does not exist in peer-type
+	// WARNING: in.IPBlockID requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllocationID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ChildIPBlockID requires manual conversion: does not exist in peer-type
+	// WARNING: in.PublicIPBlocks requires manual conversion: does not exist in peer-type
+	return nil
+}
+
+func autoConvert_v1beta2_OSSpec_To_v1beta1_OSSpec(in *OSSpec, out *v1beta1.OSSpec, s conversion.Scope) error {
+	out.ID = in.ID
+	out.Type = in.Type
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1beta2_OSSpec_To_v1beta1_OSSpec is an autogenerated conversion function.
+func Convert_v1beta2_OSSpec_To_v1beta1_OSSpec(in *OSSpec, out *v1beta1.OSSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_OSSpec_To_v1beta1_OSSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_OSSpec_To_v1beta2_OSSpec(in *v1beta1.OSSpec, out *OSSpec, s conversion.Scope) error {
+	out.ID = in.ID
+	out.Type = in.Type
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1beta1_OSSpec_To_v1beta2_OSSpec is an autogenerated conversion function.
+func Convert_v1beta1_OSSpec_To_v1beta2_OSSpec(in *v1beta1.OSSpec, out *OSSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_OSSpec_To_v1beta2_OSSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_PlacementSpec_To_v1beta1_PlacementSpec(in *PlacementSpec, out *v1beta1.PlacementSpec, s conversion.Scope) error {
+	out.ChassisAntiAffinity = v1beta1.AntiAffinityMode(in.ChassisAntiAffinity)
+	out.AntiAffinity = (*v1beta1.AntiAffinitySpec)(unsafe.Pointer(in.AntiAffinity))
+	return nil
+}
+
+// Convert_v1beta2_PlacementSpec_To_v1beta1_PlacementSpec is an autogenerated conversion function.
+func Convert_v1beta2_PlacementSpec_To_v1beta1_PlacementSpec(in *PlacementSpec, out *v1beta1.PlacementSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_PlacementSpec_To_v1beta1_PlacementSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_PlacementSpec_To_v1beta2_PlacementSpec(in *v1beta1.PlacementSpec, out *PlacementSpec, s conversion.Scope) error {
+	out.ChassisAntiAffinity = AntiAffinityMode(in.ChassisAntiAffinity)
+	out.AntiAffinity = (*AntiAffinitySpec)(unsafe.Pointer(in.AntiAffinity))
+	return nil
+}
+
+// Convert_v1beta1_PlacementSpec_To_v1beta2_PlacementSpec is an autogenerated conversion function.
+func Convert_v1beta1_PlacementSpec_To_v1beta2_PlacementSpec(in *v1beta1.PlacementSpec, out *PlacementSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_PlacementSpec_To_v1beta2_PlacementSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_PlacementStatus_To_v1beta1_PlacementStatus(in *PlacementStatus, out *v1beta1.PlacementStatus, s conversion.Scope) error {
+	out.ChassisSerial = in.ChassisSerial
+	out.Rack = in.Rack
+	out.PowerDomain = in.PowerDomain
+	out.Decision = string(in.Decision)
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1beta2_PlacementStatus_To_v1beta1_PlacementStatus is an autogenerated conversion function.
+func Convert_v1beta2_PlacementStatus_To_v1beta1_PlacementStatus(in *PlacementStatus, out *v1beta1.PlacementStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_PlacementStatus_To_v1beta1_PlacementStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_PlacementStatus_To_v1beta2_PlacementStatus(in *v1beta1.PlacementStatus, out *PlacementStatus, s conversion.Scope) error {
+	out.ChassisSerial = in.ChassisSerial
+	out.Rack = in.Rack
+	out.PowerDomain = in.PowerDomain
+	out.Decision = PlacementDecision(in.Decision)
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1beta1_PlacementStatus_To_v1beta2_PlacementStatus is an autogenerated conversion function.
+func Convert_v1beta1_PlacementStatus_To_v1beta2_PlacementStatus(in *v1beta1.PlacementStatus, out *PlacementStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_PlacementStatus_To_v1beta2_PlacementStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_PowerActionStatus_To_v1beta1_PowerActionStatus(in *PowerActionStatus, out *v1beta1.PowerActionStatus, s conversion.Scope) error {
+	out.Action = v1beta1.PowerAction(in.Action)
+	out.Result = string(in.Result)
+	out.Message = in.Message
+	out.Time = in.Time
+	return nil
+}
+
+// Convert_v1beta2_PowerActionStatus_To_v1beta1_PowerActionStatus is an autogenerated conversion function.
+func Convert_v1beta2_PowerActionStatus_To_v1beta1_PowerActionStatus(in *PowerActionStatus, out *v1beta1.PowerActionStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_PowerActionStatus_To_v1beta1_PowerActionStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_PowerActionStatus_To_v1beta2_PowerActionStatus(in *v1beta1.PowerActionStatus, out *PowerActionStatus, s conversion.Scope) error {
+	out.Action = PowerAction(in.Action)
+	out.Result = PowerActionResult(in.Result)
+	out.Message = in.Message
+	out.Time = in.Time
+	return nil
+}
+
+// Convert_v1beta1_PowerActionStatus_To_v1beta2_PowerActionStatus is an autogenerated conversion function.
+func Convert_v1beta1_PowerActionStatus_To_v1beta2_PowerActionStatus(in *v1beta1.PowerActionStatus, out *PowerActionStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_PowerActionStatus_To_v1beta2_PowerActionStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_ProvisionedStatus_To_v1beta1_ProvisionedStatus(in *ProvisionedStatus, out *v1beta1.ProvisionedStatus, s conversion.Scope) error {
+	out.BootstrapDataHash = in.BootstrapDataHash
+	out.OperatingSystemID = in.OperatingSystemID
+	out.Reimages = in.Reimages
+	out.LastReimageTime = (*metav1.Time)(unsafe.Pointer(in.LastReimageTime))
+	return nil
+}
+
+// Convert_v1beta2_ProvisionedStatus_To_v1beta1_ProvisionedStatus is an autogenerated conversion function.
+func Convert_v1beta2_ProvisionedStatus_To_v1beta1_ProvisionedStatus(in *ProvisionedStatus, out *v1beta1.ProvisionedStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_ProvisionedStatus_To_v1beta1_ProvisionedStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_ProvisionedStatus_To_v1beta2_ProvisionedStatus(in *v1beta1.ProvisionedStatus, out *ProvisionedStatus, s conversion.Scope) error {
+	out.BootstrapDataHash = in.BootstrapDataHash
+	out.OperatingSystemID = in.OperatingSystemID
+	out.Reimages = in.Reimages
+	out.LastReimageTime = (*metav1.Time)(unsafe.Pointer(in.LastReimageTime))
+	return nil
+}
+
+// Convert_v1beta1_ProvisionedStatus_To_v1beta2_ProvisionedStatus is an autogenerated conversion function.
+func Convert_v1beta1_ProvisionedStatus_To_v1beta2_ProvisionedStatus(in *v1beta1.ProvisionedStatus, out *ProvisionedStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_ProvisionedStatus_To_v1beta2_ProvisionedStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_ProvisioningSpec_To_v1beta1_ProvisioningSpec(in *ProvisioningSpec, out *v1beta1.ProvisioningSpec, s conversion.Scope) error {
+	out.MaxConcurrent = in.MaxConcurrent
+	return nil
+}
+
+// Convert_v1beta2_ProvisioningSpec_To_v1beta1_ProvisioningSpec is an autogenerated conversion function.
+func Convert_v1beta2_ProvisioningSpec_To_v1beta1_ProvisioningSpec(in *ProvisioningSpec, out *v1beta1.ProvisioningSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_ProvisioningSpec_To_v1beta1_ProvisioningSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_ProvisioningSpec_To_v1beta2_ProvisioningSpec(in *v1beta1.ProvisioningSpec, out *ProvisioningSpec, s conversion.Scope) error {
+	out.MaxConcurrent = in.MaxConcurrent
+	return nil
+}
+
+// Convert_v1beta1_ProvisioningSpec_To_v1beta2_ProvisioningSpec is an autogenerated conversion function.
+func Convert_v1beta1_ProvisioningSpec_To_v1beta2_ProvisioningSpec(in *v1beta1.ProvisioningSpec, out *ProvisioningSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_ProvisioningSpec_To_v1beta2_ProvisioningSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_ProxySpec_To_v1beta1_ProxySpec(in *ProxySpec, out *v1beta1.ProxySpec, s conversion.Scope) error {
+	out.HTTPProxy = in.HTTPProxy
+	out.HTTPSProxy = in.HTTPSProxy
+	out.NoProxy = *(*[]string)(unsafe.Pointer(&in.NoProxy))
+	return nil
+}
+
+// Convert_v1beta2_ProxySpec_To_v1beta1_ProxySpec is an autogenerated conversion function.
+func Convert_v1beta2_ProxySpec_To_v1beta1_ProxySpec(in *ProxySpec, out *v1beta1.ProxySpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_ProxySpec_To_v1beta1_ProxySpec(in, out, s)
+}
+
+func autoConvert_v1beta1_ProxySpec_To_v1beta2_ProxySpec(in *v1beta1.ProxySpec, out *ProxySpec, s conversion.Scope) error {
+	out.HTTPProxy = in.HTTPProxy
+	out.HTTPSProxy = in.HTTPSProxy
+	out.NoProxy = *(*[]string)(unsafe.Pointer(&in.NoProxy))
+	return nil
+}
+
+// Convert_v1beta1_ProxySpec_To_v1beta2_ProxySpec is an autogenerated conversion function.
+func Convert_v1beta1_ProxySpec_To_v1beta2_ProxySpec(in *v1beta1.ProxySpec, out *ProxySpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_ProxySpec_To_v1beta2_ProxySpec(in, out, s)
+}
+
+func autoConvert_v1beta2_QuotaStatus_To_v1beta1_QuotaStatus(in *QuotaStatus, out *v1beta1.QuotaStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Limit = in.Limit
+	out.Current = in.Current
+	out.Unit = in.Unit
+	return nil
+}
+
+// Convert_v1beta2_QuotaStatus_To_v1beta1_QuotaStatus is an autogenerated conversion function.
+func Convert_v1beta2_QuotaStatus_To_v1beta1_QuotaStatus(in *QuotaStatus, out *v1beta1.QuotaStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_QuotaStatus_To_v1beta1_QuotaStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_QuotaStatus_To_v1beta2_QuotaStatus(in *v1beta1.QuotaStatus, out *QuotaStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Limit = in.Limit
+	out.Current = in.Current
+	out.Unit = in.Unit
+	return nil
+}
+
+// Convert_v1beta1_QuotaStatus_To_v1beta2_QuotaStatus is an autogenerated conversion function.
+func Convert_v1beta1_QuotaStatus_To_v1beta2_QuotaStatus(in *v1beta1.QuotaStatus, out *QuotaStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_QuotaStatus_To_v1beta2_QuotaStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_RAIDArraySpec_To_v1beta1_RAIDArraySpec(in *RAIDArraySpec, out *v1beta1.RAIDArraySpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Level = in.Level
+	out.Devices = *(*[]string)(unsafe.Pointer(&in.Devices))
+	out.Filesystem = in.Filesystem
+	out.MountPath = in.MountPath
+	return nil
+}
+
+// Convert_v1beta2_RAIDArraySpec_To_v1beta1_RAIDArraySpec is an autogenerated conversion function.
+func Convert_v1beta2_RAIDArraySpec_To_v1beta1_RAIDArraySpec(in *RAIDArraySpec, out *v1beta1.RAIDArraySpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_RAIDArraySpec_To_v1beta1_RAIDArraySpec(in, out, s)
+}
+
+func autoConvert_v1beta1_RAIDArraySpec_To_v1beta2_RAIDArraySpec(in *v1beta1.RAIDArraySpec, out *RAIDArraySpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Level = in.Level
+	out.Devices = *(*[]string)(unsafe.Pointer(&in.Devices))
+	out.Filesystem = in.Filesystem
+	out.MountPath = in.MountPath
+	return nil
+}
+
+// Convert_v1beta1_RAIDArraySpec_To_v1beta2_RAIDArraySpec is an autogenerated conversion function.
+func Convert_v1beta1_RAIDArraySpec_To_v1beta2_RAIDArraySpec(in *v1beta1.RAIDArraySpec, out *RAIDArraySpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_RAIDArraySpec_To_v1beta2_RAIDArraySpec(in, out, s)
+}
+
+func autoConvert_v1beta2_ResourceOrigin_To_v1beta1_ResourceOrigin(in *ResourceOrigin, out *v1beta1.ResourceOrigin, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Name = in.Name
+	out.Created = (*metav1.Time)(unsafe.Pointer(in.Created))
+	out.CreatedBy = in.CreatedBy
+	return nil
+}
+
+// Convert_v1beta2_ResourceOrigin_To_v1beta1_ResourceOrigin is an autogenerated conversion function.
+func Convert_v1beta2_ResourceOrigin_To_v1beta1_ResourceOrigin(in *ResourceOrigin, out *v1beta1.ResourceOrigin, s conversion.Scope) error {
+	return autoConvert_v1beta2_ResourceOrigin_To_v1beta1_ResourceOrigin(in, out, s)
+}
+
+func autoConvert_v1beta1_ResourceOrigin_To_v1beta2_ResourceOrigin(in *v1beta1.ResourceOrigin, out *ResourceOrigin, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Name = in.Name
+	out.Created = (*metav1.Time)(unsafe.Pointer(in.Created))
+	out.CreatedBy = in.CreatedBy
+	return nil
+}
+
+// Convert_v1beta1_ResourceOrigin_To_v1beta2_ResourceOrigin is an autogenerated conversion function.
+func Convert_v1beta1_ResourceOrigin_To_v1beta2_ResourceOrigin(in *v1beta1.ResourceOrigin, out *ResourceOrigin, s conversion.Scope) error {
+	return autoConvert_v1beta1_ResourceOrigin_To_v1beta2_ResourceOrigin(in, out, s)
+}
+
+func autoConvert_v1beta2_SRIOVInterfaceStatus_To_v1beta1_SRIOVInterfaceStatus(in *SRIOVInterfaceStatus, out *v1beta1.SRIOVInterfaceStatus, s conversion.Scope) error {
+	out.SubnetName = in.SubnetName
+	out.Device = in.Device
+	out.PCIAddress = in.PCIAddress
+	out.Trust = in.Trust
+	out.VLAN = in.VLAN
+	out.VirtualFunctions = *(*[]v1beta1.VirtualFunctionStatus)(unsafe.Pointer(&in.VirtualFunctions))
+	return nil
+}
+
+// Convert_v1beta2_SRIOVInterfaceStatus_To_v1beta1_SRIOVInterfaceStatus is an autogenerated conversion function.
+func Convert_v1beta2_SRIOVInterfaceStatus_To_v1beta1_SRIOVInterfaceStatus(in *SRIOVInterfaceStatus, out *v1beta1.SRIOVInterfaceStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_SRIOVInterfaceStatus_To_v1beta1_SRIOVInterfaceStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_SRIOVInterfaceStatus_To_v1beta2_SRIOVInterfaceStatus(in *v1beta1.SRIOVInterfaceStatus, out *SRIOVInterfaceStatus, s conversion.Scope) error {
+	out.SubnetName = in.SubnetName
+	out.Device = in.Device
+	out.PCIAddress = in.PCIAddress
+	out.Trust = in.Trust
+	out.VLAN = in.VLAN
+	out.VirtualFunctions = *(*[]VirtualFunctionStatus)(unsafe.Pointer(&in.VirtualFunctions))
+	return nil
+}
+
+// Convert_v1beta1_SRIOVInterfaceStatus_To_v1beta2_SRIOVInterfaceStatus is an autogenerated conversion function.
+func Convert_v1beta1_SRIOVInterfaceStatus_To_v1beta2_SRIOVInterfaceStatus(in *v1beta1.SRIOVInterfaceStatus, out *SRIOVInterfaceStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_SRIOVInterfaceStatus_To_v1beta2_SRIOVInterfaceStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_SRIOVSpec_To_v1beta1_SRIOVSpec(in *SRIOVSpec, out *v1beta1.SRIOVSpec, s conversion.Scope) error {
+	out.Device = in.Device
+	out.DeviceInstance = (*int32)(unsafe.Pointer(in.DeviceInstance))
+	out.NumVFs = in.NumVFs
+	out.Trust = in.Trust
+	out.VLAN = in.VLAN
+	return nil
+}
+
+// Convert_v1beta2_SRIOVSpec_To_v1beta1_SRIOVSpec is an autogenerated conversion function.
+func Convert_v1beta2_SRIOVSpec_To_v1beta1_SRIOVSpec(in *SRIOVSpec, out *v1beta1.SRIOVSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_SRIOVSpec_To_v1beta1_SRIOVSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_SRIOVSpec_To_v1beta2_SRIOVSpec(in *v1beta1.SRIOVSpec, out *SRIOVSpec, s conversion.Scope) error {
+	out.Device = in.Device
+	out.DeviceInstance = (*int32)(unsafe.Pointer(in.DeviceInstance))
+	out.NumVFs = in.NumVFs
+	out.Trust = in.Trust
+	out.VLAN = in.VLAN
+	return nil
+}
+
+// Convert_v1beta1_SRIOVSpec_To_v1beta2_SRIOVSpec is an autogenerated conversion function.
+func Convert_v1beta1_SRIOVSpec_To_v1beta2_SRIOVSpec(in *v1beta1.SRIOVSpec, out *SRIOVSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_SRIOVSpec_To_v1beta2_SRIOVSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_SecuritySpec_To_v1beta1_SecuritySpec(in *SecuritySpec, out *v1beta1.SecuritySpec, s conversion.Scope) error {
+	out.RequireTPM = in.RequireTPM
+	out.SecureBoot = in.SecureBoot
+	out.MeasuredBoot = in.MeasuredBoot
+	out.BootMode = v1beta1.BootMode(in.BootMode)
+	return nil
+}
+
+// Convert_v1beta2_SecuritySpec_To_v1beta1_SecuritySpec is an autogenerated conversion function.
+func Convert_v1beta2_SecuritySpec_To_v1beta1_SecuritySpec(in *SecuritySpec, out *v1beta1.SecuritySpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_SecuritySpec_To_v1beta1_SecuritySpec(in, out, s)
+}
+
+func autoConvert_v1beta1_SecuritySpec_To_v1beta2_SecuritySpec(in *v1beta1.SecuritySpec, out *SecuritySpec, s conversion.Scope) error {
+	out.RequireTPM = in.RequireTPM
+	out.SecureBoot = in.SecureBoot
+	out.MeasuredBoot = in.MeasuredBoot
+	out.BootMode = BootMode(in.BootMode)
+	return nil
+}
+
+// Convert_v1beta1_SecuritySpec_To_v1beta2_SecuritySpec is an autogenerated conversion function.
+func Convert_v1beta1_SecuritySpec_To_v1beta2_SecuritySpec(in *v1beta1.SecuritySpec, out *SecuritySpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_SecuritySpec_To_v1beta2_SecuritySpec(in, out, s)
+}
+
+func autoConvert_v1beta2_SiteReference_To_v1beta1_SiteReference(in *SiteReference, out *v1beta1.SiteReference, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ID = in.ID
+	return nil
+}
+
+// Convert_v1beta2_SiteReference_To_v1beta1_SiteReference is an autogenerated conversion function.
+func Convert_v1beta2_SiteReference_To_v1beta1_SiteReference(in *SiteReference, out *v1beta1.SiteReference, s conversion.Scope) error {
+	return autoConvert_v1beta2_SiteReference_To_v1beta1_SiteReference(in, out, s)
+}
+
+func autoConvert_v1beta1_SiteReference_To_v1beta2_SiteReference(in *v1beta1.SiteReference, out *SiteReference, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ID = in.ID
+	return nil
+}
+
+// Convert_v1beta1_SiteReference_To_v1beta2_SiteReference is an autogenerated conversion function.
+func Convert_v1beta1_SiteReference_To_v1beta2_SiteReference(in *v1beta1.SiteReference, out *SiteReference, s conversion.Scope) error {
+	return autoConvert_v1beta1_SiteReference_To_v1beta2_SiteReference(in, out, s)
+}
+
+func autoConvert_v1beta2_StorageSpec_To_v1beta1_StorageSpec(in *StorageSpec, out *v1beta1.StorageSpec, s conversion.Scope) error {
+	out.Arrays = *(*[]v1beta1.RAIDArraySpec)(unsafe.Pointer(&in.Arrays))
+	return nil
+}
+
+// Convert_v1beta2_StorageSpec_To_v1beta1_StorageSpec is an autogenerated conversion function.
+func Convert_v1beta2_StorageSpec_To_v1beta1_StorageSpec(in *StorageSpec, out *v1beta1.StorageSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_StorageSpec_To_v1beta1_StorageSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_StorageSpec_To_v1beta2_StorageSpec(in *v1beta1.StorageSpec, out *StorageSpec, s conversion.Scope) error {
+	out.Arrays = *(*[]RAIDArraySpec)(unsafe.Pointer(&in.Arrays))
+	return nil
+}
+
+// Convert_v1beta1_StorageSpec_To_v1beta2_StorageSpec is an autogenerated conversion function.
+func Convert_v1beta1_StorageSpec_To_v1beta2_StorageSpec(in *v1beta1.StorageSpec, out *StorageSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_StorageSpec_To_v1beta2_StorageSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(in *SubnetSpec, out *v1beta1.SubnetSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.CIDR = in.CIDR
+	out.Role = string(in.Role)
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.DHCPOptions = (*v1beta1.DHCPOptions)(unsafe.Pointer(in.DHCPOptions))
+	out.Egress = v1beta1.SubnetEgress(in.Egress)
+	out.SiteRef = (*v1beta1.SiteReference)(unsafe.Pointer(in.SiteRef))
+	return nil
+}
+
+// Convert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec is an autogenerated conversion function.
+func Convert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(in *SubnetSpec, out *v1beta1.SubnetSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_SubnetSpec_To_v1beta2_SubnetSpec(in *v1beta1.SubnetSpec, out *SubnetSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.CIDR = in.CIDR
+	out.Role = NetworkRole(in.Role)
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.DHCPOptions = (*DHCPOptions)(unsafe.Pointer(in.DHCPOptions))
+	out.Egress = SubnetEgress(in.Egress)
+	out.SiteRef = (*SiteReference)(unsafe.Pointer(in.SiteRef))
+	return nil
+}
+
+// Convert_v1beta1_SubnetSpec_To_v1beta2_SubnetSpec is an autogenerated conversion function.
+func Convert_v1beta1_SubnetSpec_To_v1beta2_SubnetSpec(in *v1beta1.SubnetSpec, out *SubnetSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_SubnetSpec_To_v1beta2_SubnetSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_TuningSpec_To_v1beta1_TuningSpec(in *TuningSpec, out *v1beta1.TuningSpec, s conversion.Scope) error {
+	out.HugePages = *(*[]v1beta1.HugePagesSpec)(unsafe.Pointer(&in.HugePages))
+	out.IsolatedCPUs = in.IsolatedCPUs
+	return nil
+}
+
+// Convert_v1beta2_TuningSpec_To_v1beta1_TuningSpec is an autogenerated conversion function.
+func Convert_v1beta2_TuningSpec_To_v1beta1_TuningSpec(in *TuningSpec, out *v1beta1.TuningSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_TuningSpec_To_v1beta1_TuningSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_TuningSpec_To_v1beta2_TuningSpec(in *v1beta1.TuningSpec, out *TuningSpec, s conversion.Scope) error {
+	out.HugePages = *(*[]HugePagesSpec)(unsafe.Pointer(&in.HugePages))
+	out.IsolatedCPUs = in.IsolatedCPUs
+	return nil
+}
+
+// Convert_v1beta1_TuningSpec_To_v1beta2_TuningSpec is an autogenerated conversion function.
+func Convert_v1beta1_TuningSpec_To_v1beta2_TuningSpec(in *v1beta1.TuningSpec, out *TuningSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_TuningSpec_To_v1beta2_TuningSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_VLANSpec_To_v1beta1_VLANSpec(in *VLANSpec, out *v1beta1.VLANSpec, s conversion.Scope) error {
+	out.AccessVLAN = in.AccessVLAN
+	out.TrunkVLANs = *(*[]int32)(unsafe.Pointer(&in.TrunkVLANs))
+	out.NativeVLAN = in.NativeVLAN
+	return nil
+}
+
+// Convert_v1beta2_VLANSpec_To_v1beta1_VLANSpec is an autogenerated conversion function.
+func Convert_v1beta2_VLANSpec_To_v1beta1_VLANSpec(in *VLANSpec, out *v1beta1.VLANSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_VLANSpec_To_v1beta1_VLANSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_VLANSpec_To_v1beta2_VLANSpec(in *v1beta1.VLANSpec, out *VLANSpec, s conversion.Scope) error {
+	out.AccessVLAN = in.AccessVLAN
+	out.TrunkVLANs = *(*[]int32)(unsafe.Pointer(&in.TrunkVLANs))
+	out.NativeVLAN = in.NativeVLAN
+	return nil
+}
+
+// Convert_v1beta1_VLANSpec_To_v1beta2_VLANSpec is an autogenerated conversion function.
+func Convert_v1beta1_VLANSpec_To_v1beta2_VLANSpec(in *v1beta1.VLANSpec, out *VLANSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_VLANSpec_To_v1beta2_VLANSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_VPCPeeringSpec_To_v1beta1_VPCPeeringSpec(in *VPCPeeringSpec, out *v1beta1.VPCPeeringSpec, s conversion.Scope) error {
+	out.PeerVPCID = in.PeerVPCID
+	return nil
+}
+
+// Convert_v1beta2_VPCPeeringSpec_To_v1beta1_VPCPeeringSpec is an autogenerated conversion function.
+func Convert_v1beta2_VPCPeeringSpec_To_v1beta1_VPCPeeringSpec(in *VPCPeeringSpec, out *v1beta1.VPCPeeringSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_VPCPeeringSpec_To_v1beta1_VPCPeeringSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_VPCPeeringSpec_To_v1beta2_VPCPeeringSpec(in *v1beta1.VPCPeeringSpec, out *VPCPeeringSpec, s conversion.Scope) error {
+	out.PeerVPCID = in.PeerVPCID
+	return nil
+}
+
+// Convert_v1beta1_VPCPeeringSpec_To_v1beta2_VPCPeeringSpec is an autogenerated conversion function.
+func Convert_v1beta1_VPCPeeringSpec_To_v1beta2_VPCPeeringSpec(in *v1beta1.VPCPeeringSpec, out *VPCPeeringSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_VPCPeeringSpec_To_v1beta2_VPCPeeringSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_VPCPrefixSpec_To_v1beta1_VPCPrefixSpec(in *VPCPrefixSpec, out *v1beta1.VPCPrefixSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.CIDR = in.CIDR
+	out.Role = string(in.Role)
+	return nil
+}
+
+// Convert_v1beta2_VPCPrefixSpec_To_v1beta1_VPCPrefixSpec is an autogenerated conversion function.
+func Convert_v1beta2_VPCPrefixSpec_To_v1beta1_VPCPrefixSpec(in *VPCPrefixSpec, out *v1beta1.VPCPrefixSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_VPCPrefixSpec_To_v1beta1_VPCPrefixSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_VPCPrefixSpec_To_v1beta2_VPCPrefixSpec(in *v1beta1.VPCPrefixSpec, out *VPCPrefixSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.CIDR = in.CIDR
+	out.Role = NetworkRole(in.Role)
+	return nil
+}
+
+// Convert_v1beta1_VPCPrefixSpec_To_v1beta2_VPCPrefixSpec is an autogenerated conversion function.
+func Convert_v1beta1_VPCPrefixSpec_To_v1beta2_VPCPrefixSpec(in *v1beta1.VPCPrefixSpec, out *VPCPrefixSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_VPCPrefixSpec_To_v1beta2_VPCPrefixSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_VPCSpec_To_v1beta1_VPCSpec(in *VPCSpec, out *v1beta1.VPCSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ID = in.ID
+	out.NetworkVirtualizationType = string(in.NetworkVirtualizationType)
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.NetworkSecurityGroup = (*v1beta1.NSGSpec)(unsafe.Pointer(in.NetworkSecurityGroup))
+	out.NetworkSecurityGroupRef = (*v1.LocalObjectReference)(unsafe.Pointer(in.NetworkSecurityGroupRef))
+	out.NVLinkLogicalPartitionID = in.NVLinkLogicalPartitionID
+	out.Vni = (*int32)(unsafe.Pointer(in.Vni))
+	out.Description = in.Description
+	return nil
+}
+
+// Convert_v1beta2_VPCSpec_To_v1beta1_VPCSpec is an autogenerated conversion function.
+func Convert_v1beta2_VPCSpec_To_v1beta1_VPCSpec(in *VPCSpec, out *v1beta1.VPCSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_VPCSpec_To_v1beta1_VPCSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_VPCSpec_To_v1beta2_VPCSpec(in *v1beta1.VPCSpec, out *VPCSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.ID = in.ID
+	out.NetworkVirtualizationType = NetworkVirtualizationType(in.NetworkVirtualizationType)
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.NetworkSecurityGroup = (*NSGSpec)(unsafe.Pointer(in.NetworkSecurityGroup))
+	out.NetworkSecurityGroupRef = (*v1.LocalObjectReference)(unsafe.Pointer(in.NetworkSecurityGroupRef))
+	out.NVLinkLogicalPartitionID = in.NVLinkLogicalPartitionID
+	out.Vni = (*int32)(unsafe.Pointer(in.Vni))
+	out.Description = in.Description
+	return nil
+}
+
+// Convert_v1beta1_VPCSpec_To_v1beta2_VPCSpec is an autogenerated conversion function.
+func Convert_v1beta1_VPCSpec_To_v1beta2_VPCSpec(in *v1beta1.VPCSpec, out *VPCSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_VPCSpec_To_v1beta2_VPCSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_VirtualFunctionStatus_To_v1beta1_VirtualFunctionStatus(in *VirtualFunctionStatus, out *v1beta1.VirtualFunctionStatus, s conversion.Scope) error {
+	out.ID = in.ID
+	out.MACAddress = in.MACAddress
+	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
+	return nil
+}
+
+// Convert_v1beta2_VirtualFunctionStatus_To_v1beta1_VirtualFunctionStatus is an autogenerated conversion function.
+func Convert_v1beta2_VirtualFunctionStatus_To_v1beta1_VirtualFunctionStatus(in *VirtualFunctionStatus, out *v1beta1.VirtualFunctionStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_VirtualFunctionStatus_To_v1beta1_VirtualFunctionStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_VirtualFunctionStatus_To_v1beta2_VirtualFunctionStatus(in *v1beta1.VirtualFunctionStatus, out *VirtualFunctionStatus, s conversion.Scope) error {
+	out.ID = in.ID
+	out.MACAddress = in.MACAddress
+	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
+	return nil
+}
+
+// Convert_v1beta1_VirtualFunctionStatus_To_v1beta2_VirtualFunctionStatus is an autogenerated conversion function.
+func Convert_v1beta1_VirtualFunctionStatus_To_v1beta2_VirtualFunctionStatus(in *v1beta1.VirtualFunctionStatus, out *VirtualFunctionStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_VirtualFunctionStatus_To_v1beta2_VirtualFunctionStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_WarmPoolSpec_To_v1beta1_WarmPoolSpec(in *WarmPoolSpec, out *v1beta1.WarmPoolSpec, s conversion.Scope) error {
+	out.MaxSize = in.MaxSize
+	return nil
+}
+
+// Convert_v1beta2_WarmPoolSpec_To_v1beta1_WarmPoolSpec is an autogenerated conversion function.
+func Convert_v1beta2_WarmPoolSpec_To_v1beta1_WarmPoolSpec(in *WarmPoolSpec, out *v1beta1.WarmPoolSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_WarmPoolSpec_To_v1beta1_WarmPoolSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_WarmPoolSpec_To_v1beta2_WarmPoolSpec(in *v1beta1.WarmPoolSpec, out *WarmPoolSpec, s conversion.Scope) error {
+	out.MaxSize = in.MaxSize
+	return nil
+}
+
+// Convert_v1beta1_WarmPoolSpec_To_v1beta2_WarmPoolSpec is an autogenerated conversion function.
+func Convert_v1beta1_WarmPoolSpec_To_v1beta2_WarmPoolSpec(in *v1beta1.WarmPoolSpec, out *WarmPoolSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_WarmPoolSpec_To_v1beta2_WarmPoolSpec(in, out, s)
+}
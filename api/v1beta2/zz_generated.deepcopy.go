@@ -0,0 +1,1596 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta2
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1beta2 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/errors"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AntiAffinitySpec) DeepCopyInto(out *AntiAffinitySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntiAffinitySpec.
+func (in *AntiAffinitySpec) DeepCopy() *AntiAffinitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AntiAffinitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthenticationSpec) DeepCopyInto(out *AuthenticationSpec) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	if in.IdentityRef != nil {
+		in, out := &in.IdentityRef, &out.IdentityRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthenticationSpec.
+func (in *AuthenticationSpec) DeepCopy() *AuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BreakGlassSSHStatus) DeepCopyInto(out *BreakGlassSSHStatus) {
+	*out = *in
+	if in.SiteIDs != nil {
+		in, out := &in.SiteIDs, &out.SiteIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BreakGlassSSHStatus.
+func (in *BreakGlassSSHStatus) DeepCopy() *BreakGlassSSHStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BreakGlassSSHStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleStatus) DeepCopyInto(out *ConsoleStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleStatus.
+func (in *ConsoleStatus) DeepCopy() *ConsoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPOptions) DeepCopyInto(out *DHCPOptions) {
+	*out = *in
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchDomains != nil {
+		in, out := &in.SearchDomains, &out.SearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NTPServers != nil {
+		in, out := &in.NTPServers, &out.NTPServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPOptions.
+func (in *DHCPOptions) DeepCopy() *DHCPOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DPUExtensionServiceSpec) DeepCopyInto(out *DPUExtensionServiceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DPUExtensionServiceSpec.
+func (in *DPUExtensionServiceSpec) DeepCopy() *DPUExtensionServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DPUExtensionServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionSpec) DeepCopyInto(out *DeletionSpec) {
+	*out = *in
+	if in.HealthIssue != nil {
+		in, out := &in.HealthIssue, &out.HealthIssue
+		*out = new(MachineHealthIssueSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionSpec.
+func (in *DeletionSpec) DeepCopy() *DeletionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUConfigSpec) DeepCopyInto(out *GPUConfigSpec) {
+	*out = *in
+	if in.MIG != nil {
+		in, out := &in.MIG, &out.MIG
+		*out = make([]MIGSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUConfigSpec.
+func (in *GPUConfigSpec) DeepCopy() *GPUConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareInterface) DeepCopyInto(out *HardwareInterface) {
+	*out = *in
+	if in.NUMANode != nil {
+		in, out := &in.NUMANode, &out.NUMANode
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareInterface.
+func (in *HardwareInterface) DeepCopy() *HardwareInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareStatus) DeepCopyInto(out *HardwareStatus) {
+	*out = *in
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make([]HardwareInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InfiniBandInterfaces != nil {
+		in, out := &in.InfiniBandInterfaces, &out.InfiniBandInterfaces
+		*out = make([]HardwareInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Slot != nil {
+		in, out := &in.Slot, &out.Slot
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareStatus.
+func (in *HardwareStatus) DeepCopy() *HardwareStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HugePagesSpec) DeepCopyInto(out *HugePagesSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugePagesSpec.
+func (in *HugePagesSpec) DeepCopy() *HugePagesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HugePagesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPBlockStatus) DeepCopyInto(out *IPBlockStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPBlockStatus.
+func (in *IPBlockStatus) DeepCopy() *IPBlockStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IPBlockStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfiniBandInterfaceSpec) DeepCopyInto(out *InfiniBandInterfaceSpec) {
+	*out = *in
+	if in.DeviceInstance != nil {
+		in, out := &in.DeviceInstance, &out.DeviceInstance
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfiniBandInterfaceSpec.
+func (in *InfiniBandInterfaceSpec) DeepCopy() *InfiniBandInterfaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InfiniBandInterfaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceTypeSpec) DeepCopyInto(out *InstanceTypeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceTypeSpec.
+func (in *InstanceTypeSpec) DeepCopy() *InstanceTypeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceTypeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventorySpec) DeepCopyInto(out *InventorySpec) {
+	*out = *in
+	if in.LabelKeys != nil {
+		in, out := &in.LabelKeys, &out.LabelKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventorySpec.
+func (in *InventorySpec) DeepCopy() *InventorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InventorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MIGSpec) DeepCopyInto(out *MIGSpec) {
+	*out = *in
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MIGSpec.
+func (in *MIGSpec) DeepCopy() *MIGSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MIGSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineHealthIssueSpec) DeepCopyInto(out *MachineHealthIssueSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineHealthIssueSpec.
+func (in *MachineHealthIssueSpec) DeepCopy() *MachineHealthIssueSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineHealthIssueSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSGRule) DeepCopyInto(out *NSGRule) {
+	*out = *in
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSGRule.
+func (in *NSGRule) DeepCopy() *NSGRule {
+	if in == nil {
+		return nil
+	}
+	out := new(NSGRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSGSpec) DeepCopyInto(out *NSGSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]NSGRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSGSpec.
+func (in *NSGSpec) DeepCopy() *NSGSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NSGSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NSGStatus) DeepCopyInto(out *NSGStatus) {
+	*out = *in
+	out.NetworkResourceStatus = in.NetworkResourceStatus
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NSGStatus.
+func (in *NSGStatus) DeepCopy() *NSGStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NSGStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NVLinkInterfaceSpec) DeepCopyInto(out *NVLinkInterfaceSpec) {
+	*out = *in
+	if in.DeviceInstance != nil {
+		in, out := &in.DeviceInstance, &out.DeviceInstance
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NVLinkInterfaceSpec.
+func (in *NVLinkInterfaceSpec) DeepCopy() *NVLinkInterfaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NVLinkInterfaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraCluster) DeepCopyInto(out *NcxInfraCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraCluster.
+func (in *NcxInfraCluster) DeepCopy() *NcxInfraCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterList) DeepCopyInto(out *NcxInfraClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterList.
+func (in *NcxInfraClusterList) DeepCopy() *NcxInfraClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterSpec) DeepCopyInto(out *NcxInfraClusterSpec) {
+	*out = *in
+	out.SiteRef = in.SiteRef
+	in.VPC.DeepCopyInto(&out.VPC)
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]SubnetSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VPCPrefixes != nil {
+		in, out := &in.VPCPrefixes, &out.VPCPrefixes
+		*out = make([]VPCPrefixSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.VPCPeerings != nil {
+		in, out := &in.VPCPeerings, &out.VPCPeerings
+		*out = make([]VPCPeeringSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(NetworkServices)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceLabels != nil {
+		in, out := &in.InstanceLabels, &out.InstanceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.WarmPool != nil {
+		in, out := &in.WarmPool, &out.WarmPool
+		*out = new(WarmPoolSpec)
+		**out = **in
+	}
+	if in.Provisioning != nil {
+		in, out := &in.Provisioning, &out.Provisioning
+		*out = new(ProvisioningSpec)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	in.Authentication.DeepCopyInto(&out.Authentication)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterSpec.
+func (in *NcxInfraClusterSpec) DeepCopy() *NcxInfraClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraClusterStatus) DeepCopyInto(out *NcxInfraClusterStatus) {
+	*out = *in
+	in.NetworkStatus.DeepCopyInto(&out.NetworkStatus)
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.ControlPlaneEndpoint != nil {
+		in, out := &in.ControlPlaneEndpoint, &out.ControlPlaneEndpoint
+		*out = new(corev1beta2.APIEndpoint)
+		**out = **in
+	}
+	if in.BreakGlassSSH != nil {
+		in, out := &in.BreakGlassSSH, &out.BreakGlassSSH
+		*out = new(BreakGlassSSHStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]QuotaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.ClusterStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]corev1beta2.FailureDomain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraClusterStatus.
+func (in *NcxInfraClusterStatus) DeepCopy() *NcxInfraClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachine) DeepCopyInto(out *NcxInfraMachine) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachine.
+func (in *NcxInfraMachine) DeepCopy() *NcxInfraMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraMachine) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineList) DeepCopyInto(out *NcxInfraMachineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineList.
+func (in *NcxInfraMachineList) DeepCopy() *NcxInfraMachineList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraMachineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineSpec) DeepCopyInto(out *NcxInfraMachineSpec) {
+	*out = *in
+	if in.ProviderID != nil {
+		in, out := &in.ProviderID, &out.ProviderID
+		*out = new(string)
+		**out = **in
+	}
+	out.InstanceType = in.InstanceType
+	if in.OperatingSystem != nil {
+		in, out := &in.OperatingSystem, &out.OperatingSystem
+		*out = new(OSSpec)
+		**out = **in
+	}
+	in.Network.DeepCopyInto(&out.Network)
+	if in.SSHKeyGroups != nil {
+		in, out := &in.SSHKeyGroups, &out.SSHKeyGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InfiniBandInterfaces != nil {
+		in, out := &in.InfiniBandInterfaces, &out.InfiniBandInterfaces
+		*out = make([]InfiniBandInterfaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NVLinkInterfaces != nil {
+		in, out := &in.NVLinkInterfaces, &out.NVLinkInterfaces
+		*out = make([]NVLinkInterfaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DPUExtensionServices != nil {
+		in, out := &in.DPUExtensionServices, &out.DPUExtensionServices
+		*out = make([]DPUExtensionServiceSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PhoneHomeEnabled != nil {
+		in, out := &in.PhoneHomeEnabled, &out.PhoneHomeEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(DeletionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = new(InventorySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(SecuritySpec)
+		**out = **in
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GPUConfig != nil {
+		in, out := &in.GPUConfig, &out.GPUConfig
+		*out = new(GPUConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KernelArgs != nil {
+		in, out := &in.KernelArgs, &out.KernelArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(TuningSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineSpec.
+func (in *NcxInfraMachineSpec) DeepCopy() *NcxInfraMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineStatus) DeepCopyInto(out *NcxInfraMachineStatus) {
+	*out = *in
+	if in.InstanceOrigin != nil {
+		in, out := &in.InstanceOrigin, &out.InstanceOrigin
+		*out = new(ResourceOrigin)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderID != nil {
+		in, out := &in.ProviderID, &out.ProviderID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]corev1beta2.MachineAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PlacementStatus)
+		**out = **in
+	}
+	if in.LastPowerAction != nil {
+		in, out := &in.LastPowerAction, &out.LastPowerAction
+		*out = new(PowerActionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Console != nil {
+		in, out := &in.Console, &out.Console
+		*out = new(ConsoleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Provisioned != nil {
+		in, out := &in.Provisioned, &out.Provisioned
+		*out = new(ProvisionedStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SRIOVInterfaces != nil {
+		in, out := &in.SRIOVInterfaces, &out.SRIOVInterfaces
+		*out = make([]SRIOVInterfaceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hardware != nil {
+		in, out := &in.Hardware, &out.Hardware
+		*out = new(HardwareStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineStatus.
+func (in *NcxInfraMachineStatus) DeepCopy() *NcxInfraMachineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineTemplate) DeepCopyInto(out *NcxInfraMachineTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplate.
+func (in *NcxInfraMachineTemplate) DeepCopy() *NcxInfraMachineTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraMachineTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineTemplateList) DeepCopyInto(out *NcxInfraMachineTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NcxInfraMachineTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateList.
+func (in *NcxInfraMachineTemplateList) DeepCopy() *NcxInfraMachineTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NcxInfraMachineTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineTemplateResource) DeepCopyInto(out *NcxInfraMachineTemplateResource) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateResource.
+func (in *NcxInfraMachineTemplateResource) DeepCopy() *NcxInfraMachineTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineTemplateSpec) DeepCopyInto(out *NcxInfraMachineTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateSpec.
+func (in *NcxInfraMachineTemplateSpec) DeepCopy() *NcxInfraMachineTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NcxInfraMachineTemplateStatus) DeepCopyInto(out *NcxInfraMachineTemplateStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NcxInfraMachineTemplateStatus.
+func (in *NcxInfraMachineTemplateStatus) DeepCopy() *NcxInfraMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NcxInfraMachineTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
+	*out = *in
+	if in.ClusterRef != nil {
+		in, out := &in.ClusterRef, &out.ClusterRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SRIOV != nil {
+		in, out := &in.SRIOV, &out.SRIOV
+		*out = new(SRIOVSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VLAN != nil {
+		in, out := &in.VLAN, &out.VLAN
+		*out = new(VLANSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
+func (in *NetworkInterface) DeepCopy() *NetworkInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkResourceStatus) DeepCopyInto(out *NetworkResourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkResourceStatus.
+func (in *NetworkResourceStatus) DeepCopy() *NetworkResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkServices) DeepCopyInto(out *NetworkServices) {
+	*out = *in
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchDomains != nil {
+		in, out := &in.SearchDomains, &out.SearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NTPServers != nil {
+		in, out := &in.NTPServers, &out.NTPServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkServices.
+func (in *NetworkServices) DeepCopy() *NetworkServices {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkServices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+	*out = *in
+	if in.AdditionalInterfaces != nil {
+		in, out := &in.AdditionalInterfaces, &out.AdditionalInterfaces
+		*out = make([]NetworkInterface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.NetworkServices.DeepCopyInto(&out.NetworkServices)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
+func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkStatus) DeepCopyInto(out *NetworkStatus) {
+	*out = *in
+	if in.VPC != nil {
+		in, out := &in.VPC, &out.VPC
+		*out = new(NetworkResourceStatus)
+		**out = **in
+	}
+	if in.SiteVPCs != nil {
+		in, out := &in.SiteVPCs, &out.SiteVPCs
+		*out = make([]NetworkResourceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPBlocks != nil {
+		in, out := &in.IPBlocks, &out.IPBlocks
+		*out = make([]IPBlockStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]NetworkResourceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.VPCPrefixes != nil {
+		in, out := &in.VPCPrefixes, &out.VPCPrefixes
+		*out = make([]NetworkResourceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.VPCPeerings != nil {
+		in, out := &in.VPCPeerings, &out.VPCPeerings
+		*out = make([]NetworkResourceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.NSG != nil {
+		in, out := &in.NSG, &out.NSG
+		*out = new(NSGStatus)
+		**out = **in
+	}
+	if in.Origins != nil {
+		in, out := &in.Origins, &out.Origins
+		*out = make(map[string]ResourceOrigin, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkStatus.
+func (in *NetworkStatus) DeepCopy() *NetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSSpec) DeepCopyInto(out *OSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSSpec.
+func (in *OSSpec) DeepCopy() *OSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
+	*out = *in
+	if in.AntiAffinity != nil {
+		in, out := &in.AntiAffinity, &out.AntiAffinity
+		*out = new(AntiAffinitySpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSpec.
+func (in *PlacementSpec) DeepCopy() *PlacementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementStatus) DeepCopyInto(out *PlacementStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementStatus.
+func (in *PlacementStatus) DeepCopy() *PlacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerActionStatus) DeepCopyInto(out *PowerActionStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerActionStatus.
+func (in *PowerActionStatus) DeepCopy() *PowerActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisionedStatus) DeepCopyInto(out *ProvisionedStatus) {
+	*out = *in
+	if in.LastReimageTime != nil {
+		in, out := &in.LastReimageTime, &out.LastReimageTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisionedStatus.
+func (in *ProvisionedStatus) DeepCopy() *ProvisionedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisionedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningSpec) DeepCopyInto(out *ProvisioningSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningSpec.
+func (in *ProvisioningSpec) DeepCopy() *ProvisioningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
+	*out = *in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySpec.
+func (in *ProxySpec) DeepCopy() *ProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaStatus) DeepCopyInto(out *QuotaStatus) {
+	*out = *in
+	out.Limit = in.Limit.DeepCopy()
+	out.Current = in.Current.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaStatus.
+func (in *QuotaStatus) DeepCopy() *QuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RAIDArraySpec) DeepCopyInto(out *RAIDArraySpec) {
+	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RAIDArraySpec.
+func (in *RAIDArraySpec) DeepCopy() *RAIDArraySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RAIDArraySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceOrigin) DeepCopyInto(out *ResourceOrigin) {
+	*out = *in
+	if in.Created != nil {
+		in, out := &in.Created, &out.Created
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceOrigin.
+func (in *ResourceOrigin) DeepCopy() *ResourceOrigin {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceOrigin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SRIOVInterfaceStatus) DeepCopyInto(out *SRIOVInterfaceStatus) {
+	*out = *in
+	if in.VirtualFunctions != nil {
+		in, out := &in.VirtualFunctions, &out.VirtualFunctions
+		*out = make([]VirtualFunctionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SRIOVInterfaceStatus.
+func (in *SRIOVInterfaceStatus) DeepCopy() *SRIOVInterfaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SRIOVInterfaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SRIOVSpec) DeepCopyInto(out *SRIOVSpec) {
+	*out = *in
+	if in.DeviceInstance != nil {
+		in, out := &in.DeviceInstance, &out.DeviceInstance
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SRIOVSpec.
+func (in *SRIOVSpec) DeepCopy() *SRIOVSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SRIOVSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecuritySpec) DeepCopyInto(out *SecuritySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecuritySpec.
+func (in *SecuritySpec) DeepCopy() *SecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteReference) DeepCopyInto(out *SiteReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteReference.
+func (in *SiteReference) DeepCopy() *SiteReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	if in.Arrays != nil {
+		in, out := &in.Arrays, &out.Arrays
+		*out = make([]RAIDArraySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DHCPOptions != nil {
+		in, out := &in.DHCPOptions, &out.DHCPOptions
+		*out = new(DHCPOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SiteRef != nil {
+		in, out := &in.SiteRef, &out.SiteRef
+		*out = new(SiteReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetSpec.
+func (in *SubnetSpec) DeepCopy() *SubnetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningSpec) DeepCopyInto(out *TuningSpec) {
+	*out = *in
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = make([]HugePagesSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningSpec.
+func (in *TuningSpec) DeepCopy() *TuningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VLANSpec) DeepCopyInto(out *VLANSpec) {
+	*out = *in
+	if in.TrunkVLANs != nil {
+		in, out := &in.TrunkVLANs, &out.TrunkVLANs
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VLANSpec.
+func (in *VLANSpec) DeepCopy() *VLANSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VLANSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCPeeringSpec) DeepCopyInto(out *VPCPeeringSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCPeeringSpec.
+func (in *VPCPeeringSpec) DeepCopy() *VPCPeeringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCPeeringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCPrefixSpec) DeepCopyInto(out *VPCPrefixSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCPrefixSpec.
+func (in *VPCPrefixSpec) DeepCopy() *VPCPrefixSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCPrefixSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCSpec) DeepCopyInto(out *VPCSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NetworkSecurityGroup != nil {
+		in, out := &in.NetworkSecurityGroup, &out.NetworkSecurityGroup
+		*out = new(NSGSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkSecurityGroupRef != nil {
+		in, out := &in.NetworkSecurityGroupRef, &out.NetworkSecurityGroupRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Vni != nil {
+		in, out := &in.Vni, &out.Vni
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCSpec.
+func (in *VPCSpec) DeepCopy() *VPCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualFunctionStatus) DeepCopyInto(out *VirtualFunctionStatus) {
+	*out = *in
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualFunctionStatus.
+func (in *VirtualFunctionStatus) DeepCopy() *VirtualFunctionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualFunctionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WarmPoolSpec) DeepCopyInto(out *WarmPoolSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WarmPoolSpec.
+func (in *WarmPoolSpec) DeepCopy() *WarmPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -20,25 +20,42 @@ import (
 	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	bootstrapv1 "sigs.k8s.io/cluster-api/api/bootstrap/kubeadm/v1beta2"
+	controlplanev1 "sigs.k8s.io/cluster-api/api/controlplane/kubeadm/v1beta2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/controllers/clustercache"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	infrastructurev1beta1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	infrastructurev1beta2 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta2"
 	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/feature"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/simulator"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -50,19 +67,42 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(clusterv1.AddToScheme(scheme))
+	utilruntime.Must(bootstrapv1.AddToScheme(scheme))
+	utilruntime.Must(controlplanev1.AddToScheme(scheme))
 	utilruntime.Must(infrastructurev1beta1.AddToScheme(scheme))
+	utilruntime.Must(infrastructurev1beta2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 // nolint:gocyclo
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "preflight" {
+		os.Exit(runPreflight(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
+	var leaderElectionLeaseDuration time.Duration
+	var leaderElectionRenewDeadline time.Duration
+	var leaderElectionRetryPeriod time.Duration
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var watchNamespace string
+	var watchNamespaces []string
+	var watchFilterValue string
+	var syncPeriod time.Duration
+	var capacityRetryInterval time.Duration
+	var bootstrapTokenTTL time.Duration
+	var webhookPort int
+	var verbosity int
+	var simulationMode bool
+	var logAPIPayloads bool
+	var auditSinkTarget string
+	throttle := scope.DefaultThrottleConfig
+	var defaultCredentials corev1.SecretReference
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -70,6 +110,13 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaderElectionLeaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"How long the other replicas wait before taking over the lease of a leader that stopped renewing it.")
+	flag.DurationVar(&leaderElectionRenewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"How long the leader retries renewing its lease before stopping. Must be less than "+
+			"--leader-elect-lease-duration, so that it stops reconciling before another replica takes over.")
+	flag.DurationVar(&leaderElectionRetryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"How long the replicas wait between attempts to acquire or renew the lease.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
@@ -81,13 +128,93 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&watchNamespace, "namespace", "",
+		"Namespace that the controller watches to reconcile objects, in addition to --watch-namespaces.")
+	flag.Func("watch-namespaces",
+		"Comma-separated namespaces that the controller watches to reconcile objects, for a provider deployment "+
+			"per tenant team. If unspecified, the controller watches all namespaces.",
+		func(value string) error {
+			for _, namespace := range strings.Split(value, ",") {
+				if namespace = strings.TrimSpace(namespace); namespace != "" {
+					watchNamespaces = append(watchNamespaces, namespace)
+				}
+			}
+			return nil
+		})
+	flag.StringVar(&watchFilterValue, "watch-filter", "",
+		fmt.Sprintf("Label value that the controller watches to reconcile objects. Label key is always %s. "+
+			"If unspecified, the controller watches all objects.", clusterv1.WatchLabel))
+	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
+		"The minimum interval at which watched resources are reconciled.")
+	flag.DurationVar(&capacityRetryInterval, "capacity-retry-interval", time.Minute,
+		"The interval at which the creation of an instance is retried while the site has no available machine "+
+			"of its instance type, or the instance quota of the tenant is exhausted.")
+	flag.DurationVar(&bootstrapTokenTTL, "bootstrap-token-ttl", 15*time.Minute,
+		"The lifetime of the bootstrap tokens embedded in the bootstrap data. Instances created from older "+
+			"bootstrap data are reported in the BootstrapDataFresh condition. Zero disables the check.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443,
+		"The port the webhook server listens on. 0 disables the webhooks, for a provider deployment per tenant team "+
+			"next to one serving them.")
+	flag.BoolVar(&simulationMode, "simulation-mode", false,
+		"Replace the NVIDIA Carbide API with an in-memory simulation, for demos and development without hardware.")
+	flag.BoolVar(&logAPIPayloads, "log-api-payloads", false,
+		"Log the request and response bodies of the NVIDIA Carbide API calls, with their secrets redacted. "+
+			"For debugging only.")
+	flag.StringVar(&auditSinkTarget, "audit-sink", "",
+		"Record every mutating NVIDIA Carbide API call: stdout for JSON lines on the standard output, or the "+
+			"http(s) URL of a webhook receiving each record in a POST request. Empty disables the audit.")
+	flag.Float64Var(&throttle.QPS, "api-qps", throttle.QPS,
+		"The sustained rate of the NVIDIA Carbide API calls of each organization, overridden by the qps field "+
+			"of its credentials secret. Zero disables rate limiting.")
+	flag.IntVar(&throttle.Burst, "api-burst", throttle.Burst,
+		"The number of NVIDIA Carbide API calls of each organization allowed above --api-qps, overridden by the "+
+			"burst field of its credentials secret.")
+	flag.IntVar(&throttle.FailureThreshold, "api-failure-threshold", throttle.FailureThreshold,
+		"The number of consecutive NVIDIA Carbide API server errors of an organization after which its status "+
+			"polling pauses for --api-failure-pause. Zero disables the circuit breaker.")
+	flag.DurationVar(&throttle.OpenDuration, "api-failure-pause", throttle.OpenDuration,
+		"How long the status polling of an organization pauses after --api-failure-threshold consecutive "+
+			"server errors, before probing the NVIDIA Carbide API again.")
+	flag.Func("default-credentials-secret",
+		"The namespace/name of the credentials secret of the objects without authentication.secretRef, "+
+			"when their namespace has no default NcxInfraIdentity.",
+		func(value string) error {
+			namespace, name, ok := strings.Cut(value, "/")
+			if !ok || namespace == "" || name == "" {
+				return fmt.Errorf("expected namespace/name, got %q", value)
+			}
+			defaultCredentials = corev1.SecretReference{Namespace: namespace, Name: name}
+			return nil
+		})
+	flag.Func("feature-gates", "A set of key=value pairs that describe feature gates for alpha/experimental features.",
+		feature.MutableGates.Set)
+
+	// Aliases for the flag names set by the Cluster API Operator when it
+	// customizes the manager Deployment from an InfrastructureProvider CR.
+	flag.StringVar(&metricsAddr, "metrics-bind-addr", "0", "Alias of --metrics-bind-address.")
+	flag.StringVar(&probeAddr, "health-addr", ":8081", "Alias of --health-probe-bind-address.")
+	flag.StringVar(&webhookCertPath, "webhook-cert-dir", "", "Alias of --webhook-cert-path.")
+	flag.IntVar(&verbosity, "v", 0, "Log verbosity. Overrides --zap-log-level when greater than zero.")
+
 	opts := zap.Options{
 		Development: true,
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	if verbosity > 0 {
+		opts.Level = zapcore.Level(-verbosity)
+	}
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	if leaderElectionRenewDeadline >= leaderElectionLeaseDuration ||
+		leaderElectionRetryPeriod >= leaderElectionRenewDeadline {
+		setupLog.Error(nil, "invalid leader election timings, expected retry period < renew deadline < lease duration",
+			"retryPeriod", leaderElectionRetryPeriod, "renewDeadline", leaderElectionRenewDeadline,
+			"leaseDuration", leaderElectionLeaseDuration)
+		os.Exit(1)
+	}
+	scope.SetLogAPIPayloads(logAPIPayloads)
+	scope.SetThrottleConfig(throttle)
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -107,6 +234,7 @@ func main() {
 	// Initial webhook TLS options
 	webhookTLSOpts := tlsOpts
 	webhookServerOptions := webhook.Options{
+		Port:    webhookPort,
 		TLSOpts: webhookTLSOpts,
 	}
 
@@ -156,56 +284,231 @@ func main() {
 		metricsServerOptions.KeyName = metricsCertKey
 	}
 
+	cacheOptions := cache.Options{
+		SyncPeriod: &syncPeriod,
+	}
+	if watchNamespace != "" && !slices.Contains(watchNamespaces, watchNamespace) {
+		watchNamespaces = append(watchNamespaces, watchNamespace)
+	}
+	if len(watchNamespaces) > 0 {
+		// The default credentials secret is read through the cache too
+		if namespace := defaultCredentials.Namespace; namespace != "" && !slices.Contains(watchNamespaces, namespace) {
+			watchNamespaces = append(watchNamespaces, namespace)
+		}
+		setupLog.Info("Watching cluster-api objects only in namespaces for reconciliation", "namespaces", watchNamespaces)
+		cacheOptions.DefaultNamespaces = make(map[string]cache.Config, len(watchNamespaces))
+		for _, namespace := range watchNamespaces {
+			cacheOptions.DefaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  cacheOptions,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "7eb3518c.cluster.x-k8s.io",
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:          &leaderElectionLeaseDuration,
+		RenewDeadline:          &leaderElectionRenewDeadline,
+		RetryPeriod:            &leaderElectionRetryPeriod,
+		// The leader steps down when the manager stops, e.g. during a rolling
+		// update, instead of leaving the others wait for its lease to expire.
+		// This is safe because the program ends right after the manager stops.
+		LeaderElectionReleaseOnCancel: true,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// Record the mutating NVIDIA Carbide API calls for the compliance teams
+	if auditSinkTarget != "" {
+		auditSink, err := scope.NewAuditSink(auditSinkTarget)
+		if err != nil {
+			setupLog.Error(err, "invalid --audit-sink")
+			os.Exit(1)
+		}
+		if runnable, ok := auditSink.(manager.Runnable); ok {
+			if err := mgr.Add(runnable); err != nil {
+				setupLog.Error(err, "unable to add the audit sink")
+				os.Exit(1)
+			}
+		}
+		scope.SetAuditSink(auditSink)
+		setupLog.Info("Auditing the mutating NVIDIA Carbide API calls")
+	}
+
 	ctx := context.Background()
 
+	if err := controller.SetupIndexes(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to set up field indexes")
+		os.Exit(1)
+	}
+
+	// The cluster cache gives access to the workload clusters, e.g. to find the
+	// Node matching a machine provider ID or the Services of type LoadBalancer.
+	clusterCache, err := clustercache.SetupWithManager(ctx, mgr, clustercache.Options{
+		SecretClient:     mgr.GetClient(),
+		WatchFilterValue: watchFilterValue,
+		Cache: clustercache.CacheOptions{
+			Indexes: []clustercache.CacheOptionsIndex{clustercache.NodeProviderIDIndex},
+		},
+		Client: clustercache.ClientOptions{
+			UserAgent: "cluster-api-provider-nvidia-ncx-infra-controller",
+		},
+	}, ctrlcontroller.Options{MaxConcurrentReconciles: 10})
+	if err != nil {
+		setupLog.Error(err, "unable to create cluster cache")
+		os.Exit(1)
+	}
+
+	// In simulation mode all controllers share one in-memory NVIDIA Carbide API
+	// instead of building a client from each cluster credentials secret.
+	var ncxInfraClient scope.NcxInfraClientInterface
+	var orgName string
+	if simulationMode {
+		setupLog.Info("Simulation mode enabled, no NVIDIA Carbide API calls will be made")
+		if enableLeaderElection {
+			// Unlike the state of the controllers, kept in the objects and the API,
+			// the simulated API lives in the memory of the leader
+			setupLog.Info("The simulated NVIDIA Carbide API is lost when another replica takes over the leader election")
+		}
+		ncxInfraClient = simulator.New(simulator.DefaultOptions())
+		orgName = "simulation"
+	}
+
 	if err := (&controller.NcxInfraClusterReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("ncxinfracluster-controller"),
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		Recorder:           mgr.GetEventRecorderFor("ncxinfracluster-controller"),
+		NcxInfraClient:     ncxInfraClient,
+		OrgName:            orgName,
+		DefaultCredentials: defaultCredentials,
+		WatchFilterValue:   watchFilterValue,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NcxInfraCluster")
 		os.Exit(1)
 	}
-	if err := (&controller.NcxInfraMachineReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("ncxinframachine-controller"),
-	}).SetupWithManager(mgr); err != nil {
+	machineReconciler := &controller.NcxInfraMachineReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		Recorder:              mgr.GetEventRecorderFor("ncxinframachine-controller"),
+		ClusterCache:          clusterCache,
+		NcxInfraClient:        ncxInfraClient,
+		OrgName:               orgName,
+		DefaultCredentials:    defaultCredentials,
+		CapacityRetryInterval: capacityRetryInterval,
+		BootstrapTokenTTL:     bootstrapTokenTTL,
+		WatchFilterValue:      watchFilterValue,
+	}
+	if err := machineReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NcxInfraMachine")
 		os.Exit(1)
 	}
-	if err := (&infrastructurev1beta1.NcxInfraCluster{}).SetupWebhookWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create webhook", "webhook", "NcxInfraCluster")
+	if err := (&controller.NcxInfraRemediationReconciler{
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		Recorder:           mgr.GetEventRecorderFor("ncxinfraremediation-controller"),
+		NcxInfraClient:     ncxInfraClient,
+		OrgName:            orgName,
+		DefaultCredentials: defaultCredentials,
+		WatchFilterValue:   watchFilterValue,
+		Machines:           machineReconciler,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NcxInfraRemediation")
 		os.Exit(1)
 	}
-	if err := (&infrastructurev1beta1.NcxInfraMachine{}).SetupWebhookWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create webhook", "webhook", "NcxInfraMachine")
+	if err := (&controller.NcxInfraMachineTemplateReconciler{
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		NcxInfraClient:     ncxInfraClient,
+		OrgName:            orgName,
+		DefaultCredentials: defaultCredentials,
+		WatchFilterValue:   watchFilterValue,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NcxInfraMachineTemplate")
+		os.Exit(1)
+	}
+	if err := (&controller.NcxInfraNetworkSecurityGroupReconciler{
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		Recorder:           mgr.GetEventRecorderFor("ncxinfranetworksecuritygroup-controller"),
+		NcxInfraClient:     ncxInfraClient,
+		OrgName:            orgName,
+		DefaultCredentials: defaultCredentials,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NcxInfraNetworkSecurityGroup")
 		os.Exit(1)
 	}
+	if err := (&controller.NcxInfraInstanceTypeReconciler{
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		NcxInfraClient:     ncxInfraClient,
+		OrgName:            orgName,
+		DefaultCredentials: defaultCredentials,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NcxInfraInstanceType")
+		os.Exit(1)
+	}
+	if err := (&controller.NcxInfraSiteReconciler{
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		NcxInfraClient:     ncxInfraClient,
+		OrgName:            orgName,
+		DefaultCredentials: defaultCredentials,
+		WatchFilterValue:   watchFilterValue,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NcxInfraSite")
+		os.Exit(1)
+	}
+	if feature.Gates.Enabled(feature.LoadBalancer) {
+		if err := (&controller.NcxInfraLoadBalancerReconciler{
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			Recorder:         mgr.GetEventRecorderFor("ncxinfraloadbalancer-controller"),
+			ClusterCache:     clusterCache,
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NcxInfraLoadBalancer")
+			os.Exit(1)
+		}
+	}
+	if feature.Gates.Enabled(feature.ManagedCluster) {
+		if err := (&controller.NcxInfraManagedClusterReconciler{
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			Recorder:         mgr.GetEventRecorderFor("ncxinframanagedcluster-controller"),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NcxInfraManagedCluster")
+			os.Exit(1)
+		}
+	}
+	// The webhook server is only started once a webhook is registered
+	if webhookPort == 0 {
+		setupLog.Info("Webhooks disabled, served by another deployment of the provider")
+	} else {
+		if err := (&infrastructurev1beta1.NcxInfraCluster{}).SetupWebhookWithManager(mgr, len(watchNamespaces) > 0); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NcxInfraCluster")
+			os.Exit(1)
+		}
+		if err := (&infrastructurev1beta1.NcxInfraMachine{}).SetupWebhookWithManager(mgr, len(watchNamespaces) > 0); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NcxInfraMachine")
+			os.Exit(1)
+		}
+		if err := (&infrastructurev1beta1.NcxInfraMachineTemplate{}).SetupWebhookWithManager(mgr,
+			&controller.MachineTemplateResolver{
+				Client:             mgr.GetClient(),
+				NcxInfraClient:     ncxInfraClient,
+				OrgName:            orgName,
+				DefaultCredentials: defaultCredentials,
+			}); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NcxInfraMachineTemplate")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	infrastructurev1beta1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// preflightTimeout bounds the NVIDIA Carbide API calls of the preflight command.
+const preflightTimeout = 2 * time.Minute
+
+// preflightManifest holds the objects of a cluster manifest the preflight
+// checks read, converted to the storage version.
+type preflightManifest struct {
+	cluster         *clusterv1.Cluster
+	ncxInfraCluster *infrastructurev1beta1.NcxInfraCluster
+	machines        []infrastructurev1beta1.NcxInfraMachine
+	templates       []infrastructurev1beta1.NcxInfraMachineTemplate
+}
+
+// runPreflight runs the preflight checks of the NcxInfraCluster of a manifest
+// against NVIDIA Carbide, with the credentials it references in the management
+// cluster, before the manifest is applied. It returns the exit code: 0 when the
+// checks pass, 1 when they fail and 2 on usage errors.
+func runPreflight(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("preflight", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var filename, namespace string
+	var defaultCredentials corev1.SecretReference
+	fs.StringVar(&filename, "f", "", "The cluster manifest to check, - for the standard input.")
+	fs.StringVar(&namespace, "namespace", metav1.NamespaceDefault,
+		"The namespace of the objects of the manifest without one.")
+	fs.Func("default-credentials-secret",
+		"The namespace/name of the credentials secret of the cluster without authentication.secretRef, "+
+			"when its namespace has no default NcxInfraIdentity.",
+		func(value string) error {
+			ns, name, ok := strings.Cut(value, "/")
+			if !ok || ns == "" || name == "" {
+				return fmt.Errorf("expected namespace/name, got %q", value)
+			}
+			defaultCredentials = corev1.SecretReference{Namespace: ns, Name: name}
+			return nil
+		})
+	config.RegisterFlags(fs)
+	opts := zap.Options{}
+	opts.BindFlags(fs)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: %s preflight -f <cluster manifest> [flags]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if filename == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	in := stdin
+	if filename != "-" {
+		file, err := os.Open(filename)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 2
+		}
+		defer func() { _ = file.Close() }()
+		in = file
+	}
+	manifest, err := decodePreflightManifest(in, namespace)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: failed to read the manifest: %v\n", err)
+		return 2
+	}
+
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: failed to get the management cluster configuration: %v\n", err)
+		return 2
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: failed to create the management cluster client: %v\n", err)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(ctrl.SetupSignalHandler(), preflightTimeout)
+	defer cancel()
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:             c,
+		Cluster:            manifest.cluster,
+		NcxInfraCluster:    manifest.ncxInfraCluster,
+		DefaultCredentials: defaultCredentials,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(stdout, "Preflight checks of NcxInfraCluster %s failed:\n- %v\n",
+			client.ObjectKeyFromObject(manifest.ncxInfraCluster), err)
+		return 1
+	}
+
+	summary, failures := controller.Preflight(ctx, clusterScope, manifest.machines, manifest.templates)
+	if len(failures) > 0 {
+		_, _ = fmt.Fprintf(stdout, "Preflight checks of NcxInfraCluster %s failed:\n- %s\n",
+			client.ObjectKeyFromObject(manifest.ncxInfraCluster), strings.Join(failures, "\n- "))
+		return 1
+	}
+	_, _ = fmt.Fprintf(stdout, "Preflight checks of NcxInfraCluster %s passed: %s\n",
+		client.ObjectKeyFromObject(manifest.ncxInfraCluster), summary)
+	return 0
+}
+
+// decodePreflightManifest reads the Cluster, NcxInfraCluster,
+// NcxInfraMachines and NcxInfraMachineTemplates of a multi-document YAML or
+// JSON manifest, and skips its other objects. The manifest must hold a single
+// NcxInfraCluster, whose namespace holds the machines and templates of the
+// cluster. A manifest without Cluster gets one named after the NcxInfraCluster.
+func decodePreflightManifest(in io.Reader, namespace string) (*preflightManifest, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	var clusters []*clusterv1.Cluster
+	var ncxInfraClusters []*infrastructurev1beta1.NcxInfraCluster
+	var machines []infrastructurev1beta1.NcxInfraMachine
+	var templates []infrastructurev1beta1.NcxInfraMachineTemplate
+	documents := utilyaml.NewYAMLOrJSONDecoder(in, 4096)
+	for {
+		var raw runtime.RawExtension
+		if err := documents.Decode(&raw); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(strings.TrimSpace(string(raw.Raw))) == 0 || string(raw.Raw) == "null" {
+			continue
+		}
+		obj, gvk, err := decoder.Decode(raw.Raw, nil, nil)
+		if runtime.IsNotRegisteredError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		obj, err = toHub(obj, gvk.Kind)
+		if err != nil {
+			return nil, err
+		}
+		if object, ok := obj.(client.Object); ok && object.GetNamespace() == "" {
+			object.SetNamespace(namespace)
+		}
+		switch o := obj.(type) {
+		case *clusterv1.Cluster:
+			clusters = append(clusters, o)
+		case *infrastructurev1beta1.NcxInfraCluster:
+			ncxInfraClusters = append(ncxInfraClusters, o)
+		case *infrastructurev1beta1.NcxInfraMachine:
+			machines = append(machines, *o)
+		case *infrastructurev1beta1.NcxInfraMachineTemplate:
+			templates = append(templates, *o)
+		}
+	}
+
+	if len(ncxInfraClusters) != 1 {
+		return nil, fmt.Errorf("expected one NcxInfraCluster, found %d", len(ncxInfraClusters))
+	}
+	manifest := &preflightManifest{ncxInfraCluster: ncxInfraClusters[0]}
+	for _, cluster := range clusters {
+		ref := cluster.Spec.InfrastructureRef
+		if cluster.Namespace == manifest.ncxInfraCluster.Namespace &&
+			(ref.Name == manifest.ncxInfraCluster.Name || cluster.Name == manifest.ncxInfraCluster.Name) {
+			manifest.cluster = cluster
+		}
+	}
+	if manifest.cluster == nil {
+		manifest.cluster = &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{
+			Name:      manifest.ncxInfraCluster.Name,
+			Namespace: manifest.ncxInfraCluster.Namespace,
+		}}
+	}
+	for _, machine := range machines {
+		if machine.Namespace == manifest.ncxInfraCluster.Namespace {
+			manifest.machines = append(manifest.machines, machine)
+		}
+	}
+	for _, template := range templates {
+		if template.Namespace == manifest.ncxInfraCluster.Namespace {
+			manifest.templates = append(manifest.templates, template)
+		}
+	}
+	return manifest, nil
+}
+
+// toHub converts an object of another API version of the provider to the
+// storage version.
+func toHub(obj runtime.Object, kind string) (runtime.Object, error) {
+	convertible, ok := obj.(conversion.Convertible)
+	if !ok {
+		return obj, nil
+	}
+	hubObj, err := scheme.New(infrastructurev1beta1.GroupVersion.WithKind(kind))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %w", kind, err)
+	}
+	hub, ok := hubObj.(conversion.Hub)
+	if !ok {
+		return nil, fmt.Errorf("%s has no storage version", kind)
+	}
+	if err := convertible.ConvertTo(hub); err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %w", kind, err)
+	}
+	return hub, nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const preflightTestCluster = `
+apiVersion: cluster.x-k8s.io/v1beta2
+kind: Cluster
+metadata:
+  name: my-cluster
+spec:
+  infrastructureRef:
+    apiGroup: infrastructure.cluster.x-k8s.io
+    kind: NcxInfraCluster
+    name: my-infra
+---
+apiVersion: infrastructure.cluster.x-k8s.io/v1beta2
+kind: NcxInfraCluster
+metadata:
+  name: my-infra
+spec:
+  siteRef:
+    id: site-uuid
+  tenantID: tenant-uuid
+---
+apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: NcxInfraMachineTemplate
+metadata:
+  name: workers
+spec:
+  template:
+    spec:
+      instanceType:
+        id: h100
+---
+apiVersion: infrastructure.cluster.x-k8s.io/v1beta2
+kind: NcxInfraMachine
+metadata:
+  name: control-plane-0
+spec:
+  instanceType:
+    id: gb200
+---
+apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: NcxInfraMachine
+metadata:
+  name: other
+  namespace: other
+spec:
+  instanceType:
+    id: gb200
+---
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: skipped
+`
+
+func TestDecodePreflightManifest(t *testing.T) {
+	manifest, err := decodePreflightManifest(strings.NewReader(preflightTestCluster), "my-namespace")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := manifest.cluster.Name; got != "my-cluster" {
+		t.Errorf("cluster = %q, want my-cluster", got)
+	}
+	if got := manifest.ncxInfraCluster; got.Namespace != "my-namespace" || got.Spec.TenantID != "tenant-uuid" ||
+		got.Spec.SiteRef.ID != "site-uuid" {
+		t.Errorf("NcxInfraCluster = %s/%s with tenant %q and site %q", got.Namespace, got.Name,
+			got.Spec.TenantID, got.Spec.SiteRef.ID)
+	}
+	if len(manifest.machines) != 1 || manifest.machines[0].Spec.InstanceType.ID != "gb200" {
+		t.Errorf("machines = %+v, want control-plane-0 only", manifest.machines)
+	}
+	if len(manifest.templates) != 1 || manifest.templates[0].Spec.Template.Spec.InstanceType.ID != "h100" {
+		t.Errorf("templates = %+v, want workers", manifest.templates)
+	}
+}
+
+func TestDecodePreflightManifestWithoutCluster(t *testing.T) {
+	manifest, err := decodePreflightManifest(strings.NewReader(`{
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+		"kind": "NcxInfraCluster",
+		"metadata": {"name": "my-infra", "namespace": "ns"},
+		"spec": {"siteRef": {"id": "site-uuid"}, "tenantID": "tenant-uuid"}
+	}`), "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := manifest.cluster; got.Namespace != "ns" || got.Name != "my-infra" {
+		t.Errorf("cluster = %s/%s, want ns/my-infra", got.Namespace, got.Name)
+	}
+}
+
+func TestDecodePreflightManifestErrors(t *testing.T) {
+	const ncxInfraCluster = `
+apiVersion: infrastructure.cluster.x-k8s.io/v1beta1
+kind: NcxInfraCluster
+metadata:
+  name: my-infra
+`
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{
+			name:     "no NcxInfraCluster",
+			manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n",
+			want:     "expected one NcxInfraCluster, found 0",
+		},
+		{
+			name:     "two NcxInfraClusters",
+			manifest: ncxInfraCluster + "---" + ncxInfraCluster,
+			want:     "expected one NcxInfraCluster, found 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodePreflightManifest(strings.NewReader(tt.manifest), "default")
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPreflightUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no manifest", args: nil},
+		{name: "extra argument", args: []string{"-f", "-", "extra"}},
+		{name: "invalid default credentials", args: []string{"-f", "-", "--default-credentials-secret", "name"}},
+		{name: "invalid manifest", args: []string{"-f", "-"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := runPreflight(tt.args, strings.NewReader(""), &stdout, &stderr); code != 2 {
+				t.Errorf("exit code = %d, want 2", code)
+			}
+			if stdout.Len() > 0 {
+				t.Errorf("stdout = %q, want nothing", stdout.String())
+			}
+		})
+	}
+}
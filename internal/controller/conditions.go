@@ -0,0 +1,111 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// readySummary lists the conditions rolled into the Ready condition of a kind.
+// The conditions of the required types are Unknown until they are reported,
+// the optional ones only count once set, and the negative polarity ones report
+// an issue when True.
+type readySummary struct {
+	required         []clusterv1.ConditionType
+	optional         []clusterv1.ConditionType
+	negativePolarity []clusterv1.ConditionType
+}
+
+// clusterReadySummary rolls the network resources of a NcxInfraCluster into
+// its Ready condition.
+var clusterReadySummary = readySummary{
+	required: []clusterv1.ConditionType{
+		VPCReadyCondition,
+		SubnetsReadyCondition,
+	},
+	optional: []clusterv1.ConditionType{
+		AllocationReadyCondition,
+		NSGReadyCondition,
+		VPCPeeringReadyCondition,
+		BreakGlassSSHReadyCondition,
+		AuthenticationValidCondition,
+	},
+	negativePolarity: []clusterv1.ConditionType{
+		CredentialsMissingCondition,
+		InsufficientPermissionsCondition,
+	},
+}
+
+// machineReadySummary rolls the instance of a NcxInfraMachine into its Ready
+// condition.
+var machineReadySummary = readySummary{
+	required: []clusterv1.ConditionType{
+		InstanceProvisionedCondition,
+		NetworkConfiguredCondition,
+	},
+	optional: []clusterv1.ConditionType{
+		AttestationVerifiedCondition,
+		FirmwareCompatibleCondition,
+		AuthenticationValidCondition,
+	},
+	negativePolarity: []clusterv1.ConditionType{
+		InstanceProvisioningCondition,
+		InsufficientPermissionsCondition,
+		QuotaExceededCondition,
+	},
+}
+
+// setSummary sets the Ready condition of obj to the worst of the conditions
+// of the summary: False with reason IssuesReported when any of them reports
+// an issue, Unknown with reason UnknownReported when any is Unknown or not
+// yet reported, True with reason InfoReported otherwise. Its message lists
+// the conditions behind the status.
+func (s readySummary) setSummary(obj conditions.Setter) error {
+	var forTypes, ignoreIfMissing, negative []string
+	for _, conditionType := range s.required {
+		forTypes = append(forTypes, string(conditionType))
+	}
+	for _, conditionType := range s.optional {
+		forTypes = append(forTypes, string(conditionType))
+		ignoreIfMissing = append(ignoreIfMissing, string(conditionType))
+	}
+	for _, conditionType := range s.negativePolarity {
+		forTypes = append(forTypes, string(conditionType))
+		ignoreIfMissing = append(ignoreIfMissing, string(conditionType))
+		negative = append(negative, string(conditionType))
+	}
+	return conditions.SetSummaryCondition(obj, obj, string(clusterv1.ReadyCondition),
+		conditions.ForConditionTypes(forTypes),
+		conditions.IgnoreTypesIfMissing(ignoreIfMissing),
+		conditions.NegativePolarityConditionTypes(negative),
+	)
+}
+
+// setClusterReadyCondition summarizes the conditions of a NcxInfraCluster in
+// its Ready condition.
+func setClusterReadyCondition(nvidiaCarbideCluster *infrastructurev1.NcxInfraCluster) error {
+	return clusterReadySummary.setSummary(nvidiaCarbideCluster)
+}
+
+// setMachineReadyCondition summarizes the conditions of a NcxInfraMachine in
+// its Ready condition.
+func setMachineReadyCondition(nvidiaCarbideMachine *infrastructurev1.NcxInfraMachine) error {
+	return machineReadySummary.setSummary(nvidiaCarbideMachine)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+var _ = Describe("Ready condition summary", func() {
+	set := func(obj conditions.Setter, conditionType clusterv1.ConditionType, status metav1.ConditionStatus, reason string) {
+		conditions.Set(obj, metav1.Condition{Type: string(conditionType), Status: status, Reason: reason})
+	}
+
+	Context("of a NcxInfraCluster", func() {
+		var nvidiaCarbideCluster *infrastructurev1.NcxInfraCluster
+
+		BeforeEach(func() {
+			nvidiaCarbideCluster = &infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			}
+		})
+
+		It("should be Unknown until the network resources are reported", func() {
+			set(nvidiaCarbideCluster, VPCReadyCondition, metav1.ConditionTrue, "VPCReady")
+
+			Expect(setClusterReadyCondition(nvidiaCarbideCluster)).To(Succeed())
+			ready := conditions.Get(nvidiaCarbideCluster, string(clusterv1.ReadyCondition))
+			Expect(ready.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(ready.Reason).To(Equal("UnknownReported"))
+			Expect(ready.Message).To(ContainSubstring("SubnetsReady"))
+		})
+
+		It("should be True once the network resources are ready", func() {
+			set(nvidiaCarbideCluster, VPCReadyCondition, metav1.ConditionTrue, "VPCReady")
+			set(nvidiaCarbideCluster, SubnetsReadyCondition, metav1.ConditionTrue, "SubnetsReady")
+			set(nvidiaCarbideCluster, NSGReadyCondition, metav1.ConditionTrue, "NSGReady")
+			set(nvidiaCarbideCluster, InsufficientPermissionsCondition, metav1.ConditionFalse, "PermissionsGranted")
+
+			Expect(setClusterReadyCondition(nvidiaCarbideCluster)).To(Succeed())
+			Expect(conditions.IsTrue(nvidiaCarbideCluster, string(clusterv1.ReadyCondition))).To(BeTrue())
+		})
+
+		It("should report the worst condition", func() {
+			set(nvidiaCarbideCluster, VPCReadyCondition, metav1.ConditionTrue, "VPCReady")
+			set(nvidiaCarbideCluster, SubnetsReadyCondition, metav1.ConditionUnknown, "Pending")
+			conditions.Set(nvidiaCarbideCluster, metav1.Condition{
+				Type:    string(NSGReadyCondition),
+				Status:  metav1.ConditionFalse,
+				Reason:  "NSGCreateFailed",
+				Message: "failed to create network security group",
+			})
+
+			Expect(setClusterReadyCondition(nvidiaCarbideCluster)).To(Succeed())
+			ready := conditions.Get(nvidiaCarbideCluster, string(clusterv1.ReadyCondition))
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("IssuesReported"))
+			Expect(ready.Message).To(ContainSubstring("failed to create network security group"))
+		})
+
+		It("should report a negative polarity condition when True", func() {
+			set(nvidiaCarbideCluster, VPCReadyCondition, metav1.ConditionTrue, "VPCReady")
+			set(nvidiaCarbideCluster, SubnetsReadyCondition, metav1.ConditionTrue, "SubnetsReady")
+			conditions.Set(nvidiaCarbideCluster, metav1.Condition{
+				Type:    string(InsufficientPermissionsCondition),
+				Status:  metav1.ConditionTrue,
+				Reason:  "Forbidden",
+				Message: "CreateVpc was denied",
+			})
+
+			Expect(setClusterReadyCondition(nvidiaCarbideCluster)).To(Succeed())
+			ready := conditions.Get(nvidiaCarbideCluster, string(clusterv1.ReadyCondition))
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Message).To(ContainSubstring("CreateVpc was denied"))
+		})
+	})
+
+	Context("of a NcxInfraMachine", func() {
+		var nvidiaCarbideMachine *infrastructurev1.NcxInfraMachine
+
+		BeforeEach(func() {
+			nvidiaCarbideMachine = &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default"},
+			}
+			set(nvidiaCarbideMachine, InstanceProvisionedCondition, metav1.ConditionTrue, "InstanceCreated")
+			set(nvidiaCarbideMachine, NetworkConfiguredCondition, metav1.ConditionTrue, "NetworkReady")
+		})
+
+		It("should be False while the instance is provisioning", func() {
+			set(nvidiaCarbideMachine, InstanceProvisioningCondition, metav1.ConditionTrue, "Provisioning")
+
+			Expect(setMachineReadyCondition(nvidiaCarbideMachine)).To(Succeed())
+			Expect(conditions.IsFalse(nvidiaCarbideMachine, string(clusterv1.ReadyCondition))).To(BeTrue())
+		})
+
+		It("should be True once the instance is provisioned", func() {
+			set(nvidiaCarbideMachine, InstanceProvisioningCondition, metav1.ConditionFalse, "ProvisioningComplete")
+			set(nvidiaCarbideMachine, QuotaExceededCondition, metav1.ConditionFalse, "WithinQuota")
+
+			Expect(setMachineReadyCondition(nvidiaCarbideMachine)).To(Succeed())
+			ready := conditions.Get(nvidiaCarbideMachine, string(clusterv1.ReadyCondition))
+			Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+			Expect(ready.Reason).To(Equal("InfoReported"))
+		})
+
+		It("should report an exhausted quota", func() {
+			set(nvidiaCarbideMachine, InstanceProvisionedCondition, metav1.ConditionFalse, "QuotaExceeded")
+			set(nvidiaCarbideMachine, QuotaExceededCondition, metav1.ConditionTrue, "InstanceQuotaExhausted")
+
+			Expect(setMachineReadyCondition(nvidiaCarbideMachine)).To(Succeed())
+			ready := conditions.Get(nvidiaCarbideMachine, string(clusterv1.ReadyCondition))
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Message).To(ContainSubstring("QuotaExceeded"))
+		})
+	})
+})
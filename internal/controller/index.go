@@ -0,0 +1,139 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// Field indexes of the manager cache, which let the controllers look objects
+// up without listing and filtering a whole namespace.
+const (
+	// ClusterNameField indexes the NcxInfraMachines by the name of their Cluster.
+	ClusterNameField = "metadata.labels.clusterName"
+
+	// NetworkSecurityGroupRefField indexes the NcxInfraClusters by the name of
+	// the NcxInfraNetworkSecurityGroup they reference.
+	NetworkSecurityGroupRefField = "spec.vpc.networkSecurityGroupRef.name"
+
+	// CredentialsSecretField indexes the NcxInfraClusters by the namespace/name
+	// of the credentials secrets they reference or use.
+	CredentialsSecretField = "spec.authentication.secretRef"
+
+	// SharedVPCField indexes the NcxInfraClusters by the ID of the existing VPC
+	// they use.
+	SharedVPCField = "spec.vpc.id"
+)
+
+// fieldIndex is a field index of the manager cache.
+type fieldIndex struct {
+	obj     client.Object
+	field   string
+	extract client.IndexerFunc
+}
+
+// fieldIndexes are the field indexes used by the controllers.
+var fieldIndexes = []fieldIndex{
+	{obj: &infrastructurev1.NcxInfraMachine{}, field: ClusterNameField, extract: machineClusterName},
+	{obj: &infrastructurev1.NcxInfraMachine{}, field: infrastructurev1.ProviderIDField, extract: machineProviderIDInstance},
+	{obj: &infrastructurev1.NcxInfraCluster{}, field: NetworkSecurityGroupRefField, extract: clusterNetworkSecurityGroupRef},
+	{obj: &infrastructurev1.NcxInfraCluster{}, field: CredentialsSecretField, extract: clusterCredentialsSecret},
+	{obj: &infrastructurev1.NcxInfraCluster{}, field: SharedVPCField, extract: clusterSharedVPC},
+	{obj: &infrastructurev1.NcxInfraCluster{}, field: infrastructurev1.SiteTenantField, extract: clusterSiteTenants},
+}
+
+// SetupIndexes adds the field indexes used by the controllers to the manager cache.
+func SetupIndexes(ctx context.Context, mgr ctrl.Manager) error {
+	for _, index := range fieldIndexes {
+		if err := mgr.GetFieldIndexer().IndexField(ctx, index.obj, index.field, index.extract); err != nil {
+			return fmt.Errorf("failed to index %T by %s: %w", index.obj, index.field, err)
+		}
+	}
+	return nil
+}
+
+func machineClusterName(obj client.Object) []string {
+	if name, ok := obj.GetLabels()[clusterv1.ClusterNameLabel]; ok && name != "" {
+		return []string{name}
+	}
+	return nil
+}
+
+func machineProviderIDInstance(obj client.Object) []string {
+	m, ok := obj.(*infrastructurev1.NcxInfraMachine)
+	if !ok || m.Spec.ProviderID == nil {
+		return nil
+	}
+	if instanceID := infrastructurev1.ProviderIDInstance(*m.Spec.ProviderID); instanceID != "" {
+		return []string{instanceID}
+	}
+	return nil
+}
+
+func clusterNetworkSecurityGroupRef(obj client.Object) []string {
+	c, ok := obj.(*infrastructurev1.NcxInfraCluster)
+	if !ok || c.Spec.VPC.NetworkSecurityGroupRef == nil || c.Spec.VPC.NetworkSecurityGroupRef.Name == "" {
+		return nil
+	}
+	return []string{c.Spec.VPC.NetworkSecurityGroupRef.Name}
+}
+
+func clusterSharedVPC(obj client.Object) []string {
+	c, ok := obj.(*infrastructurev1.NcxInfraCluster)
+	if !ok || c.Spec.VPC.ID == "" {
+		return nil
+	}
+	return []string{c.Spec.VPC.ID}
+}
+
+func clusterSiteTenants(obj client.Object) []string {
+	c, ok := obj.(*infrastructurev1.NcxInfraCluster)
+	if !ok {
+		return nil
+	}
+	return infrastructurev1.ClusterSiteTenants(c)
+}
+
+func clusterCredentialsSecret(obj client.Object) []string {
+	c, ok := obj.(*infrastructurev1.NcxInfraCluster)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	if c.Spec.Authentication.SecretRef.Name != "" {
+		key := client.ObjectKey{Namespace: c.Spec.Authentication.SecretRef.Namespace, Name: c.Spec.Authentication.SecretRef.Name}
+		if key.Namespace == "" {
+			key.Namespace = c.Namespace
+		}
+		keys = append(keys, key.String())
+	}
+	// The secret in use may come from an identity or the controller default
+	if ref := c.Status.CredentialsSecretRef; ref != nil {
+		if key := (client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}).String(); !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
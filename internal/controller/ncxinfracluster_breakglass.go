@@ -0,0 +1,326 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/feature"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// BreakGlassSSHReadyCondition reports whether the break-glass SSH key of the
+// cluster is registered and attached to its instances.
+const BreakGlassSSHReadyCondition clusterv1.ConditionType = "BreakGlassSSHReady"
+
+// breakGlassPublicKey is the key of the public key in the break-glass Secret,
+// next to the private key under corev1.SSHAuthPrivateKey.
+const breakGlassPublicKey = "ssh-publickey"
+
+// breakGlassSecretName returns the name of the Secret holding the break-glass
+// SSH keypair of a cluster.
+func breakGlassSecretName(clusterName string) string {
+	return clusterName + "-break-glass-ssh"
+}
+
+// breakGlassSSHKeyGroupID returns the SSH key group attached to the instances
+// of the cluster for emergency access, or an empty string when there is none.
+func breakGlassSSHKeyGroupID(ncxInfraCluster *infrastructurev1.NcxInfraCluster) string {
+	if !feature.Gates.Enabled(feature.BreakGlassSSH) || ncxInfraCluster == nil ||
+		ncxInfraCluster.Status.BreakGlassSSH == nil {
+		return ""
+	}
+	return ncxInfraCluster.Status.BreakGlassSSH.SSHKeyGroupID
+}
+
+// reconcileBreakGlassSSH generates the break-glass SSH keypair of the cluster
+// and registers its public key in an SSH key group synced to the sites of the
+// cluster. Deleting the Secret rotates the key: the new key replaces the old
+// one in the group, then the old key is deleted.
+func (r *NcxInfraClusterReconciler) reconcileBreakGlassSSH(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteIDs []string,
+) error {
+	logger := log.FromContext(ctx)
+	ncxInfraCluster := clusterScope.NcxInfraCluster
+
+	publicKey, err := r.ensureBreakGlassSecret(ctx, ncxInfraCluster)
+	if err != nil {
+		return err
+	}
+	fingerprint := ssh.FingerprintSHA256(publicKey)
+
+	if ncxInfraCluster.Status.BreakGlassSSH == nil {
+		ncxInfraCluster.Status.BreakGlassSSH = &infrastructurev1.BreakGlassSSHStatus{}
+	}
+	status := ncxInfraCluster.Status.BreakGlassSSH
+	status.SecretName = breakGlassSecretName(ncxInfraCluster.Name)
+
+	// Register the key, it is rotated when the Secret was replaced
+	if status.SSHKeyID == "" || status.Fingerprint != fingerprint {
+		key, err := r.createBreakGlassSSHKey(ctx, clusterScope, publicKey)
+		if err != nil {
+			return err
+		}
+		previous := status.SSHKeyID
+		if previous != "" {
+			// A key that never replaced the retired one in the group is not
+			// worth keeping until the group is updated
+			if status.RetiredSSHKeyID != "" {
+				if err := r.deleteBreakGlassSSHKey(ctx, clusterScope, previous); err != nil {
+					return err
+				}
+			} else {
+				status.RetiredSSHKeyID = previous
+			}
+		}
+		now := metav1.Now()
+		status.SSHKeyID, status.Fingerprint, status.LastRotationTime = key.GetId(), fingerprint, &now
+		logger.Info("Registered break-glass SSH key", "sshKeyID", status.SSHKeyID, "fingerprint", fingerprint)
+		if previous != "" {
+			r.recordEvent(ncxInfraCluster, "BreakGlassSSHKeyRotated",
+				"Replaced break-glass SSH key %s with %s, fingerprint %s", previous, status.SSHKeyID, fingerprint)
+		} else {
+			r.recordEvent(ncxInfraCluster, "BreakGlassSSHKeyCreated",
+				"Registered break-glass SSH key %s with fingerprint %s", status.SSHKeyID, fingerprint)
+		}
+	}
+
+	// Attach the key to the group synced to the sites of the cluster
+	if status.SSHKeyGroupID == "" {
+		createStart := time.Now()
+		group, httpResp, err := clusterScope.NcxInfraClient.CreateSshKeyGroup(ctx, clusterScope.OrgName,
+			nico.SshKeyGroupCreateRequest{
+				Name:        breakGlassSSHName(ncxInfraCluster),
+				Description: nico.PtrString("Break-glass SSH access to the instances of the cluster"),
+				SiteIds:     siteIDs,
+				SshKeyIds:   []string{status.SSHKeyID},
+			})
+		createErr := scope.ClassifyAPIError(httpResp, err, "CreateSshKeyGroup")
+		recordAPIMetrics("CreateSshKeyGroup", createStart, createErr)
+		if createErr != nil {
+			return fmt.Errorf("failed to create the break-glass SSH key group: %w", createErr)
+		}
+		if group == nil || group.Id == nil {
+			return fmt.Errorf("SSH key group ID missing in response")
+		}
+		status.SSHKeyGroupID, status.SiteIDs = *group.Id, siteIDs
+		logger.Info("Created break-glass SSH key group", "sshKeyGroupID", status.SSHKeyGroupID)
+		r.recordEvent(ncxInfraCluster, "BreakGlassSSHKeyGroupCreated",
+			"Created break-glass SSH key group %s", status.SSHKeyGroupID)
+	} else if status.RetiredSSHKeyID != "" || !slices.Equal(status.SiteIDs, siteIDs) {
+		if err := r.updateBreakGlassSSHKeyGroup(ctx, clusterScope, siteIDs); err != nil {
+			return err
+		}
+		status.SiteIDs = siteIDs
+	}
+
+	// The group no longer holds the retired key
+	if status.RetiredSSHKeyID != "" {
+		if err := r.deleteBreakGlassSSHKey(ctx, clusterScope, status.RetiredSSHKeyID); err != nil {
+			return err
+		}
+		status.RetiredSSHKeyID = ""
+	}
+	return nil
+}
+
+// breakGlassSSHName returns the name of the SSH key group of a cluster, unique
+// in the organization as the NVIDIA Carbide names are.
+func breakGlassSSHName(ncxInfraCluster *infrastructurev1.NcxInfraCluster) string {
+	return ncxInfraCluster.Namespace + "-" + ncxInfraCluster.Name + "-break-glass"
+}
+
+// ensureBreakGlassSecret returns the public key of the break-glass Secret of
+// the cluster, generating an Ed25519 keypair when the Secret does not exist.
+// The Secret is owned by the NcxInfraCluster and deleted with it.
+func (r *NcxInfraClusterReconciler) ensureBreakGlassSecret(
+	ctx context.Context, ncxInfraCluster *infrastructurev1.NcxInfraCluster,
+) (ssh.PublicKey, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: ncxInfraCluster.Namespace, Name: breakGlassSecretName(ncxInfraCluster.Name)}
+	err := r.Get(ctx, key, secret)
+	if err == nil {
+		publicKey, _, _, _, err := ssh.ParseAuthorizedKey(secret.Data[breakGlassPublicKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key in Secret %s: %w", key, err)
+		}
+		return publicKey, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get Secret %s: %w", key, err)
+	}
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the break-glass SSH key: %w", err)
+	}
+	publicKey, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := ssh.MarshalPrivateKey(private, breakGlassSSHName(ncxInfraCluster))
+	if err != nil {
+		return nil, err
+	}
+
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels:    map[string]string{},
+		},
+		Type: corev1.SecretTypeSSHAuth,
+		Data: map[string][]byte{
+			corev1.SSHAuthPrivateKey: pem.EncodeToMemory(privateKey),
+			breakGlassPublicKey:      ssh.MarshalAuthorizedKey(publicKey),
+		},
+	}
+	if clusterName, ok := ncxInfraCluster.Labels[clusterv1.ClusterNameLabel]; ok {
+		secret.Labels[clusterv1.ClusterNameLabel] = clusterName
+	}
+	if err := controllerutil.SetOwnerReference(ncxInfraCluster, secret, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, secret); err != nil {
+		return nil, fmt.Errorf("failed to create Secret %s: %w", key, err)
+	}
+	log.FromContext(ctx).Info("Generated break-glass SSH keypair", "secret", key.Name)
+	return publicKey, nil
+}
+
+// createBreakGlassSSHKey registers a break-glass public key, named after its
+// hash as a replaced key is only deleted once its successor is registered.
+func (r *NcxInfraClusterReconciler) createBreakGlassSSHKey(
+	ctx context.Context, clusterScope *scope.ClusterScope, publicKey ssh.PublicKey,
+) (*nico.SshKey, error) {
+	hash := sha256.Sum256(publicKey.Marshal())
+	createStart := time.Now()
+	key, httpResp, err := clusterScope.NcxInfraClient.CreateSshKey(ctx, clusterScope.OrgName, nico.SshKeyCreateRequest{
+		Name:      breakGlassSSHName(clusterScope.NcxInfraCluster) + "-" + hex.EncodeToString(hash[:4]),
+		PublicKey: strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))),
+	})
+	createErr := scope.ClassifyAPIError(httpResp, err, "CreateSshKey")
+	recordAPIMetrics("CreateSshKey", createStart, createErr)
+	if createErr != nil {
+		return nil, fmt.Errorf("failed to register the break-glass SSH key: %w", createErr)
+	}
+	if key == nil || key.Id == nil {
+		return nil, fmt.Errorf("SSH key ID missing in response")
+	}
+	return key, nil
+}
+
+// updateBreakGlassSSHKeyGroup replaces the sites and key of the break-glass
+// SSH key group with the current ones.
+func (r *NcxInfraClusterReconciler) updateBreakGlassSSHKeyGroup(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteIDs []string,
+) error {
+	status := clusterScope.NcxInfraCluster.Status.BreakGlassSSH
+
+	getStart := time.Now()
+	group, httpResp, err := clusterScope.NcxInfraClient.GetSshKeyGroup(ctx, clusterScope.OrgName, status.SSHKeyGroupID)
+	getErr := scope.ClassifyAPIError(httpResp, err, "GetSshKeyGroup")
+	recordAPIMetrics("GetSshKeyGroup", getStart, getErr)
+	if getErr != nil {
+		return fmt.Errorf("failed to get the break-glass SSH key group %s: %w", status.SSHKeyGroupID, getErr)
+	}
+
+	updateStart := time.Now()
+	_, httpResp, err = clusterScope.NcxInfraClient.UpdateSshKeyGroup(ctx, clusterScope.OrgName, status.SSHKeyGroupID,
+		nico.SshKeyGroupUpdateRequest{
+			SiteIds:   siteIDs,
+			SshKeyIds: []string{status.SSHKeyID},
+			Version:   group.GetVersion(),
+		})
+	updateErr := scope.ClassifyAPIError(httpResp, err, "UpdateSshKeyGroup")
+	recordAPIMetrics("UpdateSshKeyGroup", updateStart, updateErr)
+	if updateErr != nil {
+		return fmt.Errorf("failed to update the break-glass SSH key group %s: %w", status.SSHKeyGroupID, updateErr)
+	}
+	log.FromContext(ctx).Info("Updated break-glass SSH key group",
+		"sshKeyGroupID", status.SSHKeyGroupID, "sshKeyID", status.SSHKeyID, "siteIDs", siteIDs)
+	return nil
+}
+
+// deleteBreakGlassSSHKey deletes a break-glass SSH key, a key already gone is
+// not an error.
+func (r *NcxInfraClusterReconciler) deleteBreakGlassSSHKey(
+	ctx context.Context, clusterScope *scope.ClusterScope, sshKeyID string,
+) error {
+	deleteStart := time.Now()
+	httpResp, err := clusterScope.NcxInfraClient.DeleteSshKey(ctx, clusterScope.OrgName, sshKeyID)
+	if httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+		err = nil
+	}
+	deleteErr := scope.ClassifyAPIError(httpResp, err, "DeleteSshKey")
+	recordAPIMetrics("DeleteSshKey", deleteStart, deleteErr)
+	if deleteErr != nil {
+		return fmt.Errorf("failed to delete the break-glass SSH key %s: %w", sshKeyID, deleteErr)
+	}
+	r.recordEvent(clusterScope.NcxInfraCluster, "BreakGlassSSHKeyDeleted",
+		"Deleted break-glass SSH key %s", sshKeyID)
+	return nil
+}
+
+// deleteBreakGlassSSH deletes the break-glass SSH key group of the cluster,
+// then its keys. The Secret is garbage collected with the NcxInfraCluster.
+func (r *NcxInfraClusterReconciler) deleteBreakGlassSSH(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	status := clusterScope.NcxInfraCluster.Status.BreakGlassSSH
+	if status == nil {
+		return nil
+	}
+
+	if status.SSHKeyGroupID != "" {
+		log.FromContext(ctx).Info("Deleting break-glass SSH key group", "sshKeyGroupID", status.SSHKeyGroupID)
+		if err := r.deleteResource(ctx, clusterScope, "SSH key group", status.SSHKeyGroupID,
+			clusterScope.NcxInfraClient.DeleteSshKeyGroup, "DeleteSshKeyGroup"); err != nil {
+			return err
+		}
+		status.SSHKeyGroupID, status.SiteIDs = "", nil
+	}
+	for _, keyID := range []*string{&status.RetiredSSHKeyID, &status.SSHKeyID} {
+		if *keyID == "" {
+			continue
+		}
+		if err := r.deleteBreakGlassSSHKey(ctx, clusterScope, *keyID); err != nil {
+			return err
+		}
+		*keyID = ""
+	}
+	return nil
+}
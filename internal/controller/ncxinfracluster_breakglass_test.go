@@ -0,0 +1,201 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/feature"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Break-glass SSH", func() {
+	var (
+		ctx             context.Context
+		ncxInfraCluster *infrastructurev1.NcxInfraCluster
+		clusterScope    *scope.ClusterScope
+		reconciler      *NcxInfraClusterReconciler
+		recorder        *record.FakeRecorder
+		keys            []nico.SshKeyCreateRequest
+		groups          []nico.SshKeyGroupCreateRequest
+		groupUpdates    []nico.SshKeyGroupUpdateRequest
+		calls           []string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		keys, groups, groupUpdates, calls = nil, nil, nil, nil
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			TypeMeta:   metav1.TypeMeta{APIVersion: infrastructurev1.GroupVersion.String(), Kind: "NcxInfraCluster"},
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default", UID: "cluster-uid"},
+		}
+		mockClient := &testutil.MockNcxInfraClient{
+			CreateSshKeyFunc: func(
+				ctx context.Context, org string, req nico.SshKeyCreateRequest,
+			) (*nico.SshKey, *http.Response, error) {
+				keys = append(keys, req)
+				id := fmt.Sprintf("key-%d", len(keys))
+				return &nico.SshKey{Id: testutil.Ptr(id)}, testutil.MockHTTPResponse(http.StatusCreated), nil
+			},
+			DeleteSshKeyFunc: func(ctx context.Context, org, sshKeyId string) (*http.Response, error) {
+				calls = append(calls, "DeleteSshKey "+sshKeyId)
+				return testutil.MockHTTPResponse(http.StatusNoContent), nil
+			},
+			CreateSshKeyGroupFunc: func(
+				ctx context.Context, org string, req nico.SshKeyGroupCreateRequest,
+			) (*nico.SshKeyGroup, *http.Response, error) {
+				groups = append(groups, req)
+				return &nico.SshKeyGroup{Id: testutil.Ptr("group-uuid")}, testutil.MockHTTPResponse(http.StatusCreated), nil
+			},
+			GetSshKeyGroupFunc: func(ctx context.Context, org, id string) (*nico.SshKeyGroup, *http.Response, error) {
+				return &nico.SshKeyGroup{Id: testutil.Ptr(id), Version: testutil.Ptr("v3")},
+					testutil.MockHTTPResponse(http.StatusOK), nil
+			},
+			UpdateSshKeyGroupFunc: func(
+				ctx context.Context, org, id string, req nico.SshKeyGroupUpdateRequest,
+			) (*nico.SshKeyGroup, *http.Response, error) {
+				groupUpdates = append(groupUpdates, req)
+				calls = append(calls, "UpdateSshKeyGroup "+id)
+				return &nico.SshKeyGroup{Id: testutil.Ptr(id)}, testutil.MockHTTPResponse(http.StatusOK), nil
+			},
+			DeleteSshKeyGroupFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+				calls = append(calls, "DeleteSshKeyGroup "+id)
+				return testutil.MockHTTPResponse(http.StatusNoContent), nil
+			},
+		}
+		clusterScope = &scope.ClusterScope{NcxInfraCluster: ncxInfraCluster, NcxInfraClient: mockClient, OrgName: "test-org"}
+		scheme := newTestScheme()
+		recorder = record.NewFakeRecorder(20)
+		reconciler = &NcxInfraClusterReconciler{
+			Client:   newFakeClientBuilder(scheme).Build(),
+			Scheme:   scheme,
+			Recorder: recorder,
+		}
+	})
+
+	It("should generate a keypair and register it in a key group synced to the sites", func() {
+		Expect(reconciler.reconcileBreakGlassSSH(ctx, clusterScope, []string{"site-a", "site-b"})).To(Succeed())
+
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test-cluster-break-glass-ssh"}, secret)).
+			To(Succeed())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeSSHAuth))
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		Expect(secret.OwnerReferences[0].Name).To(Equal("test-cluster"))
+		signer, err := ssh.ParsePrivateKey(secret.Data[corev1.SSHAuthPrivateKey])
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(keys).To(HaveLen(1))
+		Expect(keys[0].PublicKey + "\n").To(Equal(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))))
+		Expect(keys[0].PublicKey).To(HavePrefix("ssh-ed25519 "))
+		Expect(groups).To(Equal([]nico.SshKeyGroupCreateRequest{{
+			Name:        "default-test-cluster-break-glass",
+			Description: groups[0].Description,
+			SiteIds:     []string{"site-a", "site-b"},
+			SshKeyIds:   []string{"key-1"},
+		}}))
+
+		status := ncxInfraCluster.Status.BreakGlassSSH
+		Expect(status.SecretName).To(Equal("test-cluster-break-glass-ssh"))
+		Expect(status.Fingerprint).To(Equal(ssh.FingerprintSHA256(signer.PublicKey())))
+		Expect(status.SSHKeyID).To(Equal("key-1"))
+		Expect(status.SSHKeyGroupID).To(Equal("group-uuid"))
+		Expect(status.LastRotationTime).NotTo(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("BreakGlassSSHKeyCreated")))
+
+		// Nothing changes on the next reconciliation
+		Expect(reconciler.reconcileBreakGlassSSH(ctx, clusterScope, []string{"site-a", "site-b"})).To(Succeed())
+		Expect(keys).To(HaveLen(1))
+		Expect(groups).To(HaveLen(1))
+		Expect(groupUpdates).To(BeEmpty())
+	})
+
+	It("should rotate the key when the Secret is deleted", func() {
+		Expect(reconciler.reconcileBreakGlassSSH(ctx, clusterScope, []string{"site-a"})).To(Succeed())
+		fingerprint := ncxInfraCluster.Status.BreakGlassSSH.Fingerprint
+		Expect(reconciler.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default", Name: "test-cluster-break-glass-ssh",
+		}})).To(Succeed())
+
+		Expect(reconciler.reconcileBreakGlassSSH(ctx, clusterScope, []string{"site-a"})).To(Succeed())
+		status := ncxInfraCluster.Status.BreakGlassSSH
+		Expect(status.Fingerprint).NotTo(Equal(fingerprint))
+		Expect(status.SSHKeyID).To(Equal("key-2"))
+		Expect(status.RetiredSSHKeyID).To(BeEmpty())
+		Expect(keys[1].Name).NotTo(Equal(keys[0].Name))
+
+		// The new key replaces the old one in the group before it is deleted
+		Expect(groupUpdates).To(Equal([]nico.SshKeyGroupUpdateRequest{{
+			SiteIds: []string{"site-a"}, SshKeyIds: []string{"key-2"}, Version: "v3",
+		}}))
+		Expect(calls).To(Equal([]string{"UpdateSshKeyGroup group-uuid", "DeleteSshKey key-1"}))
+	})
+
+	It("should sync the key group to the sites added to the cluster", func() {
+		Expect(reconciler.reconcileBreakGlassSSH(ctx, clusterScope, []string{"site-a"})).To(Succeed())
+		Expect(reconciler.reconcileBreakGlassSSH(ctx, clusterScope, []string{"site-a", "site-b"})).To(Succeed())
+
+		Expect(groupUpdates).To(Equal([]nico.SshKeyGroupUpdateRequest{{
+			SiteIds: []string{"site-a", "site-b"}, SshKeyIds: []string{"key-1"}, Version: "v3",
+		}}))
+		Expect(ncxInfraCluster.Status.BreakGlassSSH.SiteIDs).To(Equal([]string{"site-a", "site-b"}))
+	})
+
+	It("should delete the key group, then its keys", func() {
+		ncxInfraCluster.Status.BreakGlassSSH = &infrastructurev1.BreakGlassSSHStatus{
+			SSHKeyGroupID:   "group-uuid",
+			SSHKeyID:        "key-2",
+			RetiredSSHKeyID: "key-1",
+		}
+		Expect(reconciler.deleteBreakGlassSSH(ctx, clusterScope)).To(Succeed())
+		Expect(calls).To(Equal([]string{
+			"DeleteSshKeyGroup group-uuid", "DeleteSshKey key-1", "DeleteSshKey key-2",
+		}))
+		Expect(*ncxInfraCluster.Status.BreakGlassSSH).To(Equal(infrastructurev1.BreakGlassSSHStatus{}))
+	})
+
+	It("should attach the key group to the instances only when the feature gate is enabled", func() {
+		ncxInfraCluster.Status.BreakGlassSSH = &infrastructurev1.BreakGlassSSHStatus{SSHKeyGroupID: "group-uuid"}
+		machineScope := &scope.MachineScope{
+			NcxInfraCluster: ncxInfraCluster,
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				Spec: infrastructurev1.NcxInfraMachineSpec{SSHKeyGroups: []string{"admins"}},
+			},
+		}
+		Expect(instanceSSHKeyGroups(machineScope)).To(Equal([]string{"admins"}))
+
+		Expect(feature.MutableGates.SetFromMap(map[string]bool{string(feature.BreakGlassSSH): true})).To(Succeed())
+		DeferCleanup(func() {
+			Expect(feature.MutableGates.SetFromMap(map[string]bool{string(feature.BreakGlassSSH): false})).To(Succeed())
+		})
+		Expect(instanceSSHKeyGroups(machineScope)).To(Equal([]string{"admins", "group-uuid"}))
+		Expect(machineScope.NcxInfraMachine.Spec.SSHKeyGroups).To(Equal([]string{"admins"}))
+	})
+})
@@ -0,0 +1,193 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// SubnetCIDRMismatchCondition reports that NVIDIA Carbide allocated subnets
+// of the cluster a prefix other than the CIDR of their spec. The subnets are
+// created with the prefix length of their CIDR only, so the IP block picks
+// the prefix, which fails the subnets unless they are allocated from the
+// single IP block of the clusters created by earlier releases.
+const SubnetCIDRMismatchCondition clusterv1.ConditionType = "SubnetCIDRMismatch"
+
+// supernetPrefixLength is the prefix length of the supernets of the CIDRs of
+// the subnets and VPC prefixes, each created as an IP block of the cluster.
+const supernetPrefixLength = 16
+
+// defaultSupernet is the IP block of the clusters without subnets nor VPC
+// prefixes to derive one from, which the tenant needs to create the VPC.
+var defaultSupernet = netip.MustParsePrefix("10.0.0.0/16")
+
+// supernetCIDRs returns the CIDRs of the subnets without Public egress in the
+// site of the cluster and of the VPC prefixes, sorted and without duplicates:
+// the CIDRs allocated from the IP blocks of the supernets.
+func supernetCIDRs(spec infrastructurev1.NcxInfraClusterSpec) []netip.Prefix {
+	var cidrs []string
+	for _, subnet := range spec.Subnets {
+		inOtherSite := subnet.SiteRef != nil && *subnet.SiteRef != spec.SiteRef
+		if subnet.Egress != infrastructurev1.SubnetEgressPublic && !inOtherSite {
+			cidrs = append(cidrs, subnet.CIDR)
+		}
+	}
+	for _, prefix := range spec.VPCPrefixes {
+		cidrs = append(cidrs, prefix.CIDR)
+	}
+
+	var prefixes []netip.Prefix
+	for _, cidr := range cidrs {
+		if prefix, err := netip.ParsePrefix(cidr); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		}
+	}
+	slices.SortFunc(prefixes, comparePrefixes)
+	return slices.Compact(prefixes)
+}
+
+// comparePrefixes orders prefixes by address, then the larger ones first.
+func comparePrefixes(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
+
+// clusterSupernets returns the supernets of the CIDRs of the subnets without
+// Public egress in the site of the cluster and of the VPC prefixes, sorted:
+// the /16 of each CIDR, or the CIDR itself when it is larger, leaving out the
+// supernets covered by a larger one.
+func clusterSupernets(spec infrastructurev1.NcxInfraClusterSpec) []netip.Prefix {
+	var candidates []netip.Prefix
+	for _, prefix := range supernetCIDRs(spec) {
+		candidates = append(candidates, netip.PrefixFrom(prefix.Addr(), min(prefix.Bits(), supernetPrefixLength)).Masked())
+	}
+	if len(candidates) == 0 {
+		return []netip.Prefix{defaultSupernet}
+	}
+
+	// The larger supernets first, so that the ones they cover are left out
+	slices.SortFunc(candidates, func(a, b netip.Prefix) int {
+		if a.Bits() != b.Bits() {
+			return a.Bits() - b.Bits()
+		}
+		return a.Addr().Compare(b.Addr())
+	})
+	var supernets []netip.Prefix
+	for _, candidate := range candidates {
+		if !slices.ContainsFunc(supernets, candidate.Overlaps) {
+			supernets = append(supernets, candidate)
+		}
+	}
+	slices.SortFunc(supernets, func(a, b netip.Prefix) int { return a.Addr().Compare(b.Addr()) })
+	return supernets
+}
+
+// supernetName returns the suffix of the names of the IP block of a supernet,
+// or of the allocation of a CIDR, such as 10-0-0-0-16.
+func supernetName(supernet netip.Prefix) string {
+	return strings.NewReplacer(".", "-", ":", "-", "/", "-").Replace(supernet.String())
+}
+
+// supernetOf returns the supernet covering a prefix, false when none does.
+func supernetOf(supernets []netip.Prefix, prefix netip.Prefix) (netip.Prefix, bool) {
+	for _, supernet := range supernets {
+		if supernet.Contains(prefix.Addr()) && supernet.Bits() <= prefix.Bits() {
+			return supernet, true
+		}
+	}
+	return netip.Prefix{}, false
+}
+
+// subnetPrefix returns the prefix NVIDIA Carbide allocated to a subnet, empty
+// when the response does not tell.
+func subnetPrefix(subnet *nico.Subnet) string {
+	if subnet == nil {
+		return ""
+	}
+	prefix := subnet.GetIpv4Prefix()
+	if prefix == "" {
+		return ""
+	}
+	if !strings.Contains(prefix, "/") && subnet.PrefixLength != nil {
+		prefix = fmt.Sprintf("%s/%d", prefix, *subnet.PrefixLength)
+	}
+	if parsed, err := netip.ParsePrefix(prefix); err == nil {
+		return parsed.Masked().String()
+	}
+	return prefix
+}
+
+// sameCIDR returns whether two CIDRs denote the same prefix.
+func sameCIDR(a, b string) bool {
+	prefixA, errA := netip.ParsePrefix(a)
+	prefixB, errB := netip.ParsePrefix(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return prefixA.Masked() == prefixB.Masked()
+}
+
+// reconcileSubnetCIDRs compares the prefixes allocated to the subnets with the
+// CIDRs of their spec, and reports the subnets whose prefix differs in the
+// SubnetCIDRMismatch condition. The subnets whose prefix is not known are
+// skipped.
+func (r *NcxInfraClusterReconciler) reconcileSubnetCIDRs(clusterScope *scope.ClusterScope) {
+	ncxInfraCluster := clusterScope.NcxInfraCluster
+	var mismatches []string
+	for _, subnetSpec := range ncxInfraCluster.Spec.Subnets {
+		allocated := ncxInfraCluster.Status.NetworkStatus.SubnetCIDR(subnetSpec.Name)
+		if allocated == "" || sameCIDR(allocated, subnetSpec.CIDR) {
+			continue
+		}
+		mismatches = append(mismatches,
+			fmt.Sprintf("%s (requested %s, allocated %s)", subnetSpec.Name, subnetSpec.CIDR, allocated))
+	}
+
+	if len(mismatches) == 0 {
+		conditions.Set(ncxInfraCluster, metav1.Condition{
+			Type:   string(SubnetCIDRMismatchCondition),
+			Status: metav1.ConditionFalse,
+			Reason: "SubnetCIDRsMatch",
+		})
+		return
+	}
+
+	msg := "subnets allocated another prefix than their CIDR: " + strings.Join(mismatches, ", ")
+	if !conditions.IsTrue(ncxInfraCluster, string(SubnetCIDRMismatchCondition)) && r.Recorder != nil {
+		r.Recorder.Event(ncxInfraCluster, corev1.EventTypeWarning, "SubnetCIDRMismatch", msg)
+	}
+	conditions.Set(ncxInfraCluster, metav1.Condition{
+		Type:    string(SubnetCIDRMismatchCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  "AllocatedCIDRDiffers",
+		Message: msg,
+	})
+}
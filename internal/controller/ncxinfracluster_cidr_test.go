@@ -0,0 +1,220 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	ncxinfraerrors "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/errors"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("clusterSupernets", func() {
+	It("should derive one supernet per /16 of the CIDRs", func() {
+		Expect(clusterSupernets(infrastructurev1.NcxInfraClusterSpec{
+			SiteRef: infrastructurev1.SiteReference{ID: "site-uuid"},
+			Subnets: []infrastructurev1.SubnetSpec{
+				{Name: "workers", CIDR: "172.16.4.0/22"},
+				{Name: "control-plane", CIDR: "10.0.1.0/24"},
+				{Name: "storage", CIDR: "10.0.2.0/24"},
+				{Name: "ingress", CIDR: "192.0.2.0/28", Egress: infrastructurev1.SubnetEgressPublic},
+				{Name: "remote", CIDR: "10.20.0.0/24", SiteRef: &infrastructurev1.SiteReference{ID: "other-site"}},
+			},
+			VPCPrefixes: []infrastructurev1.VPCPrefixSpec{{Name: "physical", CIDR: "10.1.0.0/24"}},
+		})).To(Equal([]netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/16"),
+			netip.MustParsePrefix("10.1.0.0/16"),
+			netip.MustParsePrefix("172.16.0.0/16"),
+		}))
+	})
+
+	It("should keep a CIDR larger than a /16 and leave out the supernets it covers", func() {
+		Expect(clusterSupernets(infrastructurev1.NcxInfraClusterSpec{
+			Subnets: []infrastructurev1.SubnetSpec{
+				{Name: "control-plane", CIDR: "10.0.1.0/24"},
+				{Name: "workers", CIDR: "10.0.0.0/12"},
+			},
+		})).To(Equal([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/12")}))
+	})
+
+	It("should fall back to 10.0.0.0/16 without CIDRs", func() {
+		Expect(clusterSupernets(infrastructurev1.NcxInfraClusterSpec{})).To(Equal([]netip.Prefix{defaultSupernet}))
+	})
+})
+
+var _ = Describe("Supernet IP blocks", func() {
+	const orgName = "test-org"
+
+	var (
+		ctx             context.Context
+		ncxInfraCluster *infrastructurev1.NcxInfraCluster
+		createdBlocks   []nico.IpBlockCreateRequest
+		allocations     []nico.AllocationCreateRequest
+		subnetPrefixes  map[string]string
+		clusterScope    *scope.ClusterScope
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		createdBlocks = nil
+		allocations = nil
+		subnetPrefixes = map[string]string{}
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				SiteRef:  infrastructurev1.SiteReference{ID: "site-uuid"},
+				TenantID: "tenant-uuid",
+				Subnets: []infrastructurev1.SubnetSpec{
+					{Name: "control-plane", CIDR: "10.0.1.0/24"},
+					{Name: "workers", CIDR: "172.16.0.0/22"},
+				},
+			},
+			Status: infrastructurev1.NcxInfraClusterStatus{NetworkStatus: infrastructurev1.NetworkStatus{
+				VPC: &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"},
+			}},
+		}
+		resourceType := resourceTypeIPBlock
+		clusterScope = &scope.ClusterScope{
+			Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+			NcxInfraCluster: ncxInfraCluster,
+			OrgName:         orgName,
+			NcxInfraClient: &testutil.MockNcxInfraClient{
+				CreateIpblockFunc: func(
+					ctx context.Context, org string, req nico.IpBlockCreateRequest,
+				) (*nico.IpBlock, *http.Response, error) {
+					createdBlocks = append(createdBlocks, req)
+					return &nico.IpBlock{Id: testutil.Ptr(req.Prefix)}, testutil.MockHTTPResponse(201), nil
+				},
+				CreateAllocationFunc: func(
+					ctx context.Context, org string, req nico.AllocationCreateRequest,
+				) (*nico.Allocation, *http.Response, error) {
+					allocations = append(allocations, req)
+					constraint := req.AllocationConstraints[0]
+					child := fmt.Sprintf("child-%s/%d", constraint.ResourceTypeId, constraint.ConstraintValue)
+					return &nico.Allocation{
+						Id: testutil.Ptr(req.Name),
+						AllocationConstraints: []nico.AllocationConstraint{{
+							ResourceType:      &resourceType,
+							DerivedResourceId: *nico.NewNullableString(&child),
+						}},
+					}, testutil.MockHTTPResponse(201), nil
+				},
+				CreateSubnetFunc: func(
+					ctx context.Context, org string, req nico.SubnetCreateRequest,
+				) (*nico.Subnet, *http.Response, error) {
+					subnet := &nico.Subnet{Id: testutil.Ptr(req.Name + "=" + *req.Ipv4BlockId)}
+					if prefix, ok := subnetPrefixes[req.Name]; ok {
+						subnet.SetIpv4Prefix(prefix)
+					}
+					return subnet, testutil.MockHTTPResponse(201), nil
+				},
+			},
+		}
+	})
+
+	It("should carve each subnet from a block of its size of the IP block of its supernet", func() {
+		reconciler := &NcxInfraClusterReconciler{}
+		Expect(reconciler.reconcileSubnets(ctx, clusterScope, "site-uuid")).To(Succeed())
+
+		Expect(createdBlocks).To(HaveLen(2))
+		Expect(createdBlocks[0].Name).To(Equal("test-cluster-10-0-0-0-16-ipblock"))
+		Expect(createdBlocks[0].Prefix).To(Equal("10.0.0.0"))
+		Expect(createdBlocks[1].Name).To(Equal("test-cluster-172-16-0-0-16-ipblock"))
+		Expect(createdBlocks[1].PrefixLength).To(Equal(int32(16)))
+		Expect(allocations).To(HaveLen(2))
+		Expect(allocations[0].Name).To(Equal("test-cluster-10-0-1-0-24-allocation"))
+		Expect(allocations[0].AllocationConstraints[0].ConstraintValue).To(Equal(int32(24)))
+		Expect(allocations[1].Name).To(Equal("test-cluster-172-16-0-0-22-allocation"))
+		Expect(allocations[1].AllocationConstraints[0].ConstraintValue).To(Equal(int32(22)))
+		Expect(clusterScope.SubnetIDs()).To(Equal(map[string]string{
+			"control-plane": "control-plane=child-10.0.0.0/24",
+			"workers":       "workers=child-172.16.0.0/22",
+		}))
+		Expect(clusterScope.SupernetIPBlock("172.16.0.0/16").IPBlockID).To(Equal("172.16.0.0"))
+		Expect(clusterScope.SupernetIPBlock("172.16.0.0/22").ChildIPBlockID).To(Equal("child-172.16.0.0/22"))
+	})
+
+	It("should allocate a block for the VPC from a supernet without CIDR", func() {
+		ncxInfraCluster.Spec.Subnets = nil
+
+		reconciler := &NcxInfraClusterReconciler{}
+		Expect(reconciler.reconcileSubnets(ctx, clusterScope, "site-uuid")).To(Succeed())
+		Expect(createdBlocks).To(HaveLen(1))
+		Expect(createdBlocks[0].Name).To(Equal("test-cluster-10-0-0-0-16-ipblock"))
+		Expect(allocations).To(HaveLen(1))
+		Expect(allocations[0].AllocationConstraints[0].ConstraintValue).To(Equal(int32(defaultAllocationPrefixLength)))
+		Expect(clusterScope.SupernetIPBlock("10.0.0.0/16").ChildIPBlockID).To(Equal("child-10.0.0.0/24"))
+	})
+
+	It("should fail a subnet allocated another prefix than its CIDR", func() {
+		subnetPrefixes["workers"] = "172.16.4.0/22"
+
+		reconciler := &NcxInfraClusterReconciler{}
+		err := reconciler.reconcileSubnets(ctx, clusterScope, "site-uuid")
+		Expect(err).To(MatchError(ContainSubstring("subnet workers was allocated 172.16.4.0/22 instead of its CIDR 172.16.0.0/22")))
+		Expect(ncxinfraerrors.IsTerminal(err)).To(BeTrue())
+		Expect(ncxinfraerrors.ReasonOf(err, "")).To(Equal(ncxinfraerrors.SubnetCIDRMismatch))
+		// The subnet is recorded, to be deleted with the cluster
+		Expect(clusterScope.SubnetIDs()).To(HaveKeyWithValue("workers", "workers=child-172.16.0.0/22"))
+		Expect(ncxInfraCluster.Status.NetworkStatus.SubnetCIDR("workers")).To(Equal("172.16.4.0/22"))
+	})
+
+	It("should keep carving the subnets of earlier clusters from their IP block", func() {
+		ncxInfraCluster.Status.NetworkStatus.IPBlocks = []infrastructurev1.IPBlockStatus{{
+			IPBlockID: "ipblock", AllocationID: "allocation", ChildIPBlockID: "child",
+		}}
+		clusterScope.NcxInfraClient.(*testutil.MockNcxInfraClient).GetIpblockFunc = func(
+			ctx context.Context, org, id string,
+		) (*nico.IpBlock, *http.Response, error) {
+			return &nico.IpBlock{Id: &id}, testutil.MockHTTPResponse(200), nil
+		}
+
+		reconciler := &NcxInfraClusterReconciler{}
+		Expect(reconciler.reconcileSubnets(ctx, clusterScope, "site-uuid")).To(Succeed())
+		Expect(createdBlocks).To(BeEmpty())
+		Expect(clusterScope.SubnetIDs()).To(Equal(map[string]string{
+			"control-plane": "control-plane=child",
+			"workers":       "workers=child",
+		}))
+	})
+})
+
+var _ = Describe("ipBlocksInDeletionOrder", func() {
+	It("should delete the child IP blocks before the IP blocks of their supernets", func() {
+		ipBlocks := []infrastructurev1.IPBlockStatus{
+			{CIDR: "10.0.0.0/16", IPBlockID: "supernet"},
+			{CIDR: "10.0.1.0/24", AllocationID: "allocation", ChildIPBlockID: "child"},
+			{CIDR: "10.1.0.0/16", IPBlockID: "whole", AllocationID: "whole-allocation", ChildIPBlockID: "whole-child"},
+			{Subnet: "ingress", IPBlockID: "public", ChildIPBlockID: "public-child"},
+		}
+
+		ordered := ipBlocksInDeletionOrder(ipBlocks)
+		Expect(ordered).To(Equal([]infrastructurev1.IPBlockStatus{ipBlocks[1], ipBlocks[3], ipBlocks[0], ipBlocks[2]}))
+		Expect(ipBlocks[0].IPBlockID).To(Equal("supernet"))
+	})
+})
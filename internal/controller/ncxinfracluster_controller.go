@@ -18,16 +18,23 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -35,14 +42,20 @@ import (
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
 	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	ncxinfraerrors "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/errors"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/feature"
 	ncxinframetrics "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/metrics"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/convert"
 	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
 )
 
@@ -58,8 +71,45 @@ const (
 	NSGReadyCondition        clusterv1.ConditionType = "NSGReady"
 	AllocationReadyCondition clusterv1.ConditionType = "AllocationReady"
 	VPCPeeringReadyCondition clusterv1.ConditionType = "VPCPeeringReady"
+
+	// InstancesProvisionedCondition summarizes the provisioning failures of the cluster machines.
+	InstancesProvisionedCondition clusterv1.ConditionType = "InstancesProvisioned"
+
+	// InsufficientPermissionsCondition is set on NcxInfraCluster and NcxInfraMachine
+	// when the credentials lack the role required by an NVIDIA Carbide API call.
+	InsufficientPermissionsCondition clusterv1.ConditionType = "InsufficientPermissions"
+
+	// AuthenticationValidCondition is set to False on NcxInfraCluster and NcxInfraMachine
+	// when NVIDIA Carbide or the OAuth2 token URL rejects the credentials.
+	AuthenticationValidCondition clusterv1.ConditionType = "AuthenticationValid"
+
+	// SiteHealthyCondition reports the health of the site of the cluster, as
+	// mirrored in its NcxInfraSite.
+	SiteHealthyCondition clusterv1.ConditionType = "SiteHealthy"
+
+	// CredentialsTargetCondition reports whether the credentials still point to
+	// the org and endpoint the cluster resources were created in.
+	CredentialsTargetCondition clusterv1.ConditionType = "CredentialsTargetUnchanged"
 )
 
+// permissionsRetryInterval paces the retries of a reconciliation blocked by
+// missing permissions, which only succeed once an org admin grants the role.
+const permissionsRetryInterval = 5 * time.Minute
+
+// mirroredConditionPrefix prefixes the provider conditions mirrored into the owner
+// Cluster, so they show up in clusterctl describe cluster next to the CAPI ones.
+const mirroredConditionPrefix = "NcxInfra"
+
+// mirroredConditions are the provider conditions mirrored into the owner Cluster.
+var mirroredConditions = []clusterv1.ConditionType{
+	VPCReadyCondition,
+	SubnetsReadyCondition,
+	InstancesProvisionedCondition,
+}
+
+// maxFailedMachinesInMessage bounds the machines listed in the InstancesProvisioned message.
+const maxFailedMachinesInMessage = 3
+
 // resourceTypeIPBlock is the Carbide allocation resource type for IP blocks.
 const resourceTypeIPBlock = "IPBlock"
 
@@ -69,21 +119,36 @@ type NcxInfraClusterReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 
-	// NcxInfraClient can be set for testing to inject a mock client
+	// NcxInfraClient can be set for testing or simulation mode to inject a client
 	NcxInfraClient scope.NcxInfraClientInterface
-	// OrgName can be set for testing
+	// OrgName can be set for testing or simulation mode
 	OrgName string
+
+	// DefaultCredentials is the credentials secret of the objects that do not
+	// reference one, when their namespace has no default NcxInfraIdentity
+	DefaultCredentials corev1.SecretReference
+
+	// WatchFilterValue is the value of the cluster.x-k8s.io/watch-filter label
+	// of the objects to reconcile, all of them when empty
+	WatchFilterValue string
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraclusters/finalizers,verbs=update
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfranetworksecuritygroups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraidentities,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfrasites,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraclusteridentities,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile handles NcxInfraCluster reconciliation
-func (r *NcxInfraClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *NcxInfraClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, reterr error) {
 	logger := log.FromContext(ctx)
 
 	// Fetch the NcxInfraCluster instance
@@ -104,6 +169,9 @@ func (r *NcxInfraClusterReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		logger.Info("Waiting for Cluster Controller to set OwnerRef on NcxInfraCluster")
 		return ctrl.Result{}, nil
 	}
+	logger = logger.WithValues("cluster", cluster.Name)
+	ctx = log.IntoContext(ctx, logger)
+	ctx = scope.WithAuditObject(ctx, "NcxInfraCluster", req.NamespacedName)
 
 	// Check if cluster is paused
 	if annotations.IsPaused(cluster, nvidiaCarbideCluster) {
@@ -117,32 +185,104 @@ func (r *NcxInfraClusterReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
-	// Always attempt to patch the object and status after each reconciliation
+	// Always attempt to patch the object and status after each reconciliation,
+	// with the Ready condition summarizing the others, then surface the key
+	// provider conditions on the owner Cluster
 	defer func() {
+		deleting := !nvidiaCarbideCluster.DeletionTimestamp.IsZero()
+		if !deleting {
+			r.updateInstancesProvisioned(ctx, cluster, nvidiaCarbideCluster)
+			if err := setClusterReadyCondition(nvidiaCarbideCluster); err != nil {
+				logger.Error(err, "failed to summarize the Ready condition")
+			}
+		}
+		nvidiaCarbideCluster.Status.Phase = clusterPhase(nvidiaCarbideCluster)
+		sortConditions(nvidiaCarbideCluster)
 		if err := patchHelper.Patch(ctx, nvidiaCarbideCluster); err != nil {
-			logger.Error(err, "failed to patch NcxInfraCluster")
+			res, reterr = patchResult(res, reterr, fmt.Errorf("failed to patch NcxInfraCluster: %w", err))
+		}
+		if !deleting {
+			if err := r.mirrorConditions(ctx, cluster, nvidiaCarbideCluster); err != nil {
+				logger.Error(err, "failed to mirror conditions into Cluster")
+			}
 		}
 	}()
 
+	// Hold the deletion of a protected cluster until the annotation is removed
+	if !nvidiaCarbideCluster.DeletionTimestamp.IsZero() && isDeletionProtected(nvidiaCarbideCluster) {
+		if setDeletionBlocked(nvidiaCarbideCluster) {
+			logger.Info("Deletion prevented by annotation", "annotation", infrastructurev1.PreventDeletionAnnotation)
+			r.recordEvent(nvidiaCarbideCluster, "DeletionBlocked",
+				"Deletion held until the %s annotation is removed", infrastructurev1.PreventDeletionAnnotation)
+		}
+		return ctrl.Result{}, nil
+	}
+	conditions.Delete(nvidiaCarbideCluster, string(DeletionBlockedCondition))
+
 	// Create cluster scope
 	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
-		Client:          r.Client,
-		Cluster:         cluster,
-		NcxInfraCluster: nvidiaCarbideCluster,
-		NcxInfraClient:  r.NcxInfraClient, // Will be nil in production, set for tests
-		OrgName:         r.OrgName,        // Will be empty in production (fetched from secret), set for tests
+		Client:             r.Client,
+		Cluster:            cluster,
+		NcxInfraCluster:    nvidiaCarbideCluster,
+		NcxInfraClient:     r.NcxInfraClient, // Will be nil in production, set for tests
+		OrgName:            r.OrgName,        // Will be empty in production (fetched from secret), set for tests
+		DefaultCredentials: r.DefaultCredentials,
 	})
 	if err != nil {
+		if errors.Is(err, scope.ErrCredentialsMissing) {
+			setCredentialsMissing(nvidiaCarbideCluster, err)
+		}
 		return ctrl.Result{}, fmt.Errorf("failed to create cluster scope: %w", err)
 	}
+	conditions.Delete(nvidiaCarbideCluster, string(CredentialsMissingCondition))
+
+	// The status IDs are meaningless in another org, leave them alone until
+	// the credentials are restored
+	if change := clusterScope.TargetChange(); change != "" {
+		return r.blockTargetChange(ctx, clusterScope, change), nil
+	}
+	if conditions.Has(nvidiaCarbideCluster, string(CredentialsTargetCondition)) {
+		conditions.Set(nvidiaCarbideCluster, metav1.Condition{
+			Type:   string(CredentialsTargetCondition),
+			Status: metav1.ConditionTrue,
+			Reason: "CredentialsTargetUnchanged",
+		})
+	}
+
+	// Report the planned changes instead of making them, unless a deletion
+	// has nothing to delete
+	if isDryRun(nvidiaCarbideCluster) {
+		if nvidiaCarbideCluster.DeletionTimestamp.IsZero() || len(planClusterChanges(nvidiaCarbideCluster)) > 0 {
+			return r.reconcileDryRun(ctx, nvidiaCarbideCluster), nil
+		}
+	} else {
+		conditions.Delete(nvidiaCarbideCluster, string(DryRunCondition))
+	}
 
 	// Handle deletion
 	if !nvidiaCarbideCluster.DeletionTimestamp.IsZero() {
-		return r.reconcileDelete(ctx, clusterScope)
+		result, err := r.reconcileDelete(ctx, clusterScope)
+		return handlePermissionError(ctx, nvidiaCarbideCluster, clusterScope.OrgName, result, err)
 	}
 
 	// Handle normal reconciliation
-	return r.reconcileNormal(ctx, clusterScope)
+	result, err := r.reconcileNormal(ctx, clusterScope)
+	result, err = r.handleSiteUnreachable(ctx, nvidiaCarbideCluster, result, err)
+	return handlePermissionError(ctx, nvidiaCarbideCluster, clusterScope.OrgName, result, err)
+}
+
+// clusterPhase summarizes the state of the cluster infrastructure for status.phase.
+func clusterPhase(nvidiaCarbideCluster *infrastructurev1.NcxInfraCluster) string {
+	switch {
+	case !nvidiaCarbideCluster.DeletionTimestamp.IsZero():
+		return infrastructurev1.ClusterPhaseDeleting
+	case nvidiaCarbideCluster.Status.FailureReason != nil:
+		return infrastructurev1.ClusterPhaseFailed
+	case nvidiaCarbideCluster.Status.Ready:
+		return infrastructurev1.ClusterPhaseProvisioned
+	default:
+		return infrastructurev1.ClusterPhaseProvisioning
+	}
 }
 
 func (r *NcxInfraClusterReconciler) reconcileNormal(
@@ -157,17 +297,66 @@ func (r *NcxInfraClusterReconciler) reconcileNormal(
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Remember where the resources are created
+	clusterScope.RecordTarget()
+
+	// Keep the credentials around until the cluster is deleted
+	if err := r.reconcileCredentialsFinalizer(ctx, clusterScope); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Find the resources of a cluster recreated after the management cluster was rebuilt
+	if feature.Gates.Enabled(feature.ResourceExport) {
+		if _, err := r.restoreFromResourceExport(ctx, clusterScope); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Report what deleting the cluster would do, when requested
+	if _, ok := clusterScope.NcxInfraCluster.Annotations[infrastructurev1.TeardownReportAnnotation]; ok {
+		if err := r.reconcileTeardownReport(ctx, clusterScope); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Validate the environment before reconciling anything, when requested
+	if _, ok := clusterScope.NcxInfraCluster.Annotations[infrastructurev1.PreflightAnnotation]; ok {
+		passed, err := r.reconcilePreflight(ctx, clusterScope)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !passed {
+			return ctrl.Result{RequeueAfter: preflightRetryInterval}, nil
+		}
+	}
+
+	// Subnets or VPC prefixes sharing a name would overwrite each other in
+	// the status, create nothing until the spec is fixed
+	if duplicates := duplicateNetworkNames(clusterScope.NcxInfraCluster.Spec); len(duplicates) > 0 {
+		msg := fmt.Sprintf("duplicate names in spec: %s", strings.Join(duplicates, ", "))
+		logger.Info("Not reconciling the cluster networks", "reason", msg)
+		conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
+			Type:    string(SubnetsReadyCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "DuplicateSubnetName",
+			Message: msg,
+		})
+		return ctrl.Result{}, nil
+	}
+
 	// Get Site ID
 	siteID, err := clusterScope.SiteID(ctx)
 	if err != nil {
 		conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
 			Type:    string(VPCReadyCondition),
 			Status:  metav1.ConditionFalse,
-			Reason:  "SiteNotFound",
+			Reason:  string(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.SiteNotFound)),
 			Message: err.Error(),
 		})
 		return ctrl.Result{}, err
 	}
+	r.reconcileSiteHealth(ctx, clusterScope, siteID)
+	r.reconcileQuotas(ctx, clusterScope)
 
 	// Ensure IP block and allocation exist before VPC creation
 	// (the tenant must have an allocation with the site to create VPCs)
@@ -175,7 +364,7 @@ func (r *NcxInfraClusterReconciler) reconcileNormal(
 		conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
 			Type:    string(AllocationReadyCondition),
 			Status:  metav1.ConditionFalse,
-			Reason:  "AllocationFailed",
+			Reason:  string(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.AllocationFailed)),
 			Message: err.Error(),
 		})
 		return ctrl.Result{}, err
@@ -188,10 +377,11 @@ func (r *NcxInfraClusterReconciler) reconcileNormal(
 
 	// Reconcile VPC
 	if err := r.reconcileVPC(ctx, clusterScope, siteID); err != nil {
+		clusterScope.SetVPCError(err)
 		conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
 			Type:    string(VPCReadyCondition),
 			Status:  metav1.ConditionFalse,
-			Reason:  "VPCReconcileFailed",
+			Reason:  string(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.VPCReconcileFailed)),
 			Message: err.Error(),
 		})
 		return ctrl.Result{}, err
@@ -203,11 +393,13 @@ func (r *NcxInfraClusterReconciler) reconcileNormal(
 	})
 
 	// Reconcile Subnets
-	if err := r.reconcileSubnets(ctx, clusterScope, siteID); err != nil {
+	err = r.reconcileSubnets(ctx, clusterScope, siteID)
+	r.reconcileSubnetCIDRs(clusterScope)
+	if err != nil {
 		conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
 			Type:    string(SubnetsReadyCondition),
 			Status:  metav1.ConditionFalse,
-			Reason:  "SubnetReconcileFailed",
+			Reason:  string(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.SubnetReconcileFailed)),
 			Message: err.Error(),
 		})
 		return ctrl.Result{}, err
@@ -224,7 +416,7 @@ func (r *NcxInfraClusterReconciler) reconcileNormal(
 			conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
 				Type:    string(SubnetsReadyCondition),
 				Status:  metav1.ConditionFalse,
-				Reason:  "VPCPrefixReconcileFailed",
+				Reason:  string(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.VPCPrefixReconcileFailed)),
 				Message: err.Error(),
 			})
 			return ctrl.Result{}, err
@@ -232,12 +424,14 @@ func (r *NcxInfraClusterReconciler) reconcileNormal(
 	}
 
 	// Reconcile Network Security Group (if specified)
-	if clusterScope.NcxInfraCluster.Spec.VPC.NetworkSecurityGroup != nil {
+	vpcSpec := clusterScope.NcxInfraCluster.Spec.VPC
+	if vpcSpec.NetworkSecurityGroup != nil || vpcSpec.NetworkSecurityGroupRef != nil {
 		if err := r.reconcileNSG(ctx, clusterScope, siteID); err != nil {
+			clusterScope.SetNSGError(err)
 			conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
 				Type:    string(NSGReadyCondition),
 				Status:  metav1.ConditionFalse,
-				Reason:  "NSGReconcileFailed",
+				Reason:  string(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.NSGReconcileFailed)),
 				Message: err.Error(),
 			})
 			return ctrl.Result{}, err
@@ -255,7 +449,7 @@ func (r *NcxInfraClusterReconciler) reconcileNormal(
 			conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
 				Type:    string(VPCPeeringReadyCondition),
 				Status:  metav1.ConditionFalse,
-				Reason:  "VPCPeeringReconcileFailed",
+				Reason:  string(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.VPCPeeringReconcileFailed)),
 				Message: err.Error(),
 			})
 			return ctrl.Result{}, err
@@ -267,13 +461,42 @@ func (r *NcxInfraClusterReconciler) reconcileNormal(
 		})
 	}
 
-	// Mark cluster as ready
+	// Give the operators emergency access to the instances, when enabled
+	if feature.Gates.Enabled(feature.BreakGlassSSH) {
+		siteIDs := append([]string{siteID}, sortedKeys(clusterScope.SiteVPCIDs())...)
+		if err := r.reconcileBreakGlassSSH(ctx, clusterScope, siteIDs); err != nil {
+			conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
+				Type:    string(BreakGlassSSHReadyCondition),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.BreakGlassSSHReconcileFailed)),
+				Message: err.Error(),
+			})
+			return ctrl.Result{}, err
+		}
+		conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
+			Type:   string(BreakGlassSSHReadyCondition),
+			Status: metav1.ConditionTrue,
+			Reason: "BreakGlassSSHReady",
+		})
+	}
+
+	// Delete the warm pool instances exceeding the size of the pool
+	if _, err := r.reconcileWarmPool(ctx, clusterScope); err != nil {
+		logger.Error(err, "failed to reconcile the warm pool")
+	}
+
+	// Forget the creation records of the resources that were replaced
+	clusterScope.PruneResourceOrigins()
+
+	// Keep the resource IDs where a backup of the management cluster finds them
+	if feature.Gates.Enabled(feature.ResourceExport) {
+		if err := r.reconcileResourceExport(ctx, clusterScope); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Mark cluster as ready, the Ready condition summarizes the others
 	clusterScope.SetReady(true)
-	conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
-		Type:   string(clusterv1.ReadyCondition),
-		Status: metav1.ConditionTrue,
-		Reason: "NcxInfraClusterReady",
-	})
 
 	r.recordEvent(clusterScope.NcxInfraCluster, "ClusterInfrastructureReady",
 		"Cluster infrastructure is ready")
@@ -286,20 +509,27 @@ func (r *NcxInfraClusterReconciler) reconcileVPC(
 ) error {
 	logger := log.FromContext(ctx)
 
+	if vpcID := clusterScope.NcxInfraCluster.Spec.VPC.ID; vpcID != "" {
+		return r.reconcileSharedVPC(ctx, clusterScope, siteID, vpcID)
+	}
+
 	// Check if VPC already exists
 	if clusterScope.VPCID() != "" {
 		// Verify VPC still exists in NVIDIA Carbide
-		vpc, _, err := clusterScope.NcxInfraClient.GetVpc(ctx, clusterScope.OrgName, clusterScope.VPCID())
+		vpc, httpResp, err := clusterScope.NcxInfraClient.GetVpc(ctx, clusterScope.OrgName, clusterScope.VPCID())
+		exists, err := lookupExisting(httpResp, err, vpc != nil, "GetVpc", "VPC", clusterScope.VPCID())
 		if err != nil {
-			logger.Error(err, "VPC not found in NVIDIA Carbide, will recreate", "vpcID", clusterScope.VPCID())
-			clusterScope.SetVPCID("")
-		} else if vpc != nil {
+			return err
+		}
+		if exists {
 			logger.V(1).Info("VPC already exists", "vpcID", clusterScope.VPCID())
+			// Clear the last error
+			clusterScope.SetVPCID(clusterScope.VPCID())
+			r.syncVPCLabels(ctx, clusterScope, vpc)
 			return nil
-		} else {
-			logger.Info("VPC not found, will recreate", "vpcID", clusterScope.VPCID())
-			clusterScope.SetVPCID("")
 		}
+		logger.Info("VPC not found in NVIDIA Carbide, will recreate", "vpcID", clusterScope.VPCID())
+		clusterScope.SetVPCID("")
 	}
 
 	// Create VPC
@@ -329,7 +559,7 @@ func (r *NcxInfraClusterReconciler) reconcileVPC(
 	logger.Info("Creating VPC", "name", vpcSpec.Name, "siteID", siteID)
 	vpc, httpResp, err := clusterScope.NcxInfraClient.CreateVpc(ctx, clusterScope.OrgName, vpcReq)
 	if err != nil {
-		return fmt.Errorf("failed to create VPC: %w", err)
+		return fmt.Errorf("failed to create VPC: %w", scope.WithPermissionError(httpResp, err, "CreateVpc"))
 	}
 
 	if httpResp.StatusCode != http.StatusCreated {
@@ -341,6 +571,7 @@ func (r *NcxInfraClusterReconciler) reconcileVPC(
 	}
 
 	clusterScope.SetVPCID(*vpc.Id)
+	clusterScope.SetResourceOrigin(*vpc.Id, "VPC", vpcSpec.Name, vpc.Created)
 	logger.Info("Successfully created VPC", "vpcID", *vpc.Id)
 	r.recordEvent(clusterScope.NcxInfraCluster, "VPCCreated",
 		"Successfully created VPC %s", *vpc.Id)
@@ -349,6 +580,121 @@ func (r *NcxInfraClusterReconciler) reconcileVPC(
 	return nil
 }
 
+// reconcileSiteHealth reports the health of the site of the cluster in the
+// SiteHealthy condition, without holding the cluster back. The condition is
+// left out while the site inventory has no NcxInfraSite for the site.
+func (r *NcxInfraClusterReconciler) reconcileSiteHealth(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID string,
+) {
+	site := &infrastructurev1.NcxInfraSite{}
+	if err := r.Get(ctx, client.ObjectKey{Name: siteID}, site); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "failed to get the NcxInfraSite of the cluster", "siteID", siteID)
+		}
+		conditions.Delete(clusterScope.NcxInfraCluster, string(SiteHealthyCondition))
+		return
+	}
+
+	if site.Status.Healthy {
+		conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
+			Type:   string(SiteHealthyCondition),
+			Status: metav1.ConditionTrue,
+			Reason: "SiteHealthy",
+		})
+		return
+	}
+
+	reason, msg := "SiteNotRegistered", fmt.Sprintf("site %s is in state %s", siteID, site.Status.State)
+	if !site.Status.Online {
+		reason, msg = "SiteOffline", fmt.Sprintf("site %s is not reachable from NVIDIA Carbide", siteID)
+	}
+	if !conditions.IsFalse(clusterScope.NcxInfraCluster, string(SiteHealthyCondition)) && r.Recorder != nil {
+		r.Recorder.Event(clusterScope.NcxInfraCluster, corev1.EventTypeWarning, "SiteUnhealthy", msg)
+	}
+	conditions.Set(clusterScope.NcxInfraCluster, metav1.Condition{
+		Type:    string(SiteHealthyCondition),
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: msg,
+	})
+}
+
+// reconcileSharedVPC uses the existing VPC set in the spec, which must be in
+// the site of the cluster. The cluster neither updates nor deletes it.
+func (r *NcxInfraClusterReconciler) reconcileSharedVPC(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID, vpcID string,
+) error {
+	getStart := time.Now()
+	vpc, httpResp, err := clusterScope.NcxInfraClient.GetVpc(ctx, clusterScope.OrgName, vpcID)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetVpc")
+	recordAPIMetrics("GetVpc", getStart, apiErr)
+	if apiErr != nil {
+		return fmt.Errorf("failed to get shared VPC %s: %w", vpcID, apiErr)
+	}
+	if vpc == nil {
+		return fmt.Errorf("shared VPC %s not found", vpcID)
+	}
+	if vpc.GetSiteId() != "" && vpc.GetSiteId() != siteID {
+		return fmt.Errorf("shared VPC %s is in site %s, not in the site of the cluster %s",
+			vpcID, vpc.GetSiteId(), siteID)
+	}
+
+	if clusterScope.VPCID() != vpcID {
+		log.FromContext(ctx).Info("Using shared VPC", "vpcID", vpcID)
+		r.recordEvent(clusterScope.NcxInfraCluster, "SharedVPCUsed", "Using shared VPC %s", vpcID)
+	}
+	clusterScope.SetVPCID(vpcID)
+	return nil
+}
+
+// syncVPCLabels applies the labels of the spec to an existing VPC when they
+// drifted, after an edit of the spec or in NVIDIA Carbide. A VPC whose spec has
+// no labels is left alone. Failures are reported and retried at the next
+// reconcile, they do not hold the cluster back.
+func (r *NcxInfraClusterReconciler) syncVPCLabels(
+	ctx context.Context, clusterScope *scope.ClusterScope, vpc *nico.VPC,
+) {
+	labels := clusterScope.NcxInfraCluster.Spec.VPC.Labels
+	if len(labels) == 0 || mapsEqual(vpc.Labels, labels) {
+		return
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Info("Updating VPC labels", "vpcID", clusterScope.VPCID())
+	updateStart := time.Now()
+	_, httpResp, err := clusterScope.NcxInfraClient.UpdateVpc(ctx, clusterScope.OrgName, clusterScope.VPCID(),
+		nico.VpcUpdateRequest{Labels: labels})
+	updateErr := scope.ClassifyAPIError(httpResp, err, "UpdateVpc")
+	recordAPIMetrics("UpdateVpc", updateStart, updateErr)
+	if updateErr != nil {
+		logger.Error(updateErr, "failed to update VPC labels", "vpcID", clusterScope.VPCID())
+		r.recordEvent(clusterScope.NcxInfraCluster, "VPCUpdateFailed",
+			"Failed to update the labels of VPC %s: %v", clusterScope.VPCID(), updateErr)
+		return
+	}
+	r.recordEvent(clusterScope.NcxInfraCluster, "VPCUpdated",
+		"Updated the labels of VPC %s", clusterScope.VPCID())
+}
+
+// duplicateNetworkNames returns the subnets and VPC prefixes of the spec whose
+// name is used more than once.
+func duplicateNetworkNames(spec infrastructurev1.NcxInfraClusterSpec) []string {
+	var duplicates []string
+	subnets := map[string]int{}
+	for _, subnet := range spec.Subnets {
+		if subnets[subnet.Name]++; subnets[subnet.Name] == 2 {
+			duplicates = append(duplicates, fmt.Sprintf("subnet %s", subnet.Name))
+		}
+	}
+	prefixes := map[string]int{}
+	for _, prefix := range spec.VPCPrefixes {
+		if prefixes[prefix.Name]++; prefixes[prefix.Name] == 2 {
+			duplicates = append(duplicates, fmt.Sprintf("VPC prefix %s", prefix.Name))
+		}
+	}
+	return duplicates
+}
+
 // parseCIDR parses a CIDR string and returns the prefix length.
 func parseCIDR(cidr string) (prefixLength int, err error) {
 	_, ipNet, err := net.ParseCIDR(cidr)
@@ -359,59 +705,246 @@ func parseCIDR(cidr string) (prefixLength int, err error) {
 	return ones, nil
 }
 
-// ensureIPBlockAndAllocation ensures an IP block and allocation exist for subnet allocation.
-// The allocation creates a child IP block owned by the tenant, which must be used for subnets.
-// Returns the child IP block ID.
+// IP block routing types
+const (
+	routingTypeDatacenterOnly = "DatacenterOnly"
+	routingTypePublic         = "Public"
+)
+
+// The IP block of the clusters created by earlier releases and the child IP
+// block allocated to the tenant, which all their subnets without Public egress
+// and VPC prefixes are carved from
+const (
+	legacyIPBlockPrefix          = "10.0.0.0"
+	legacyIPBlockPrefixLength    = 16
+	legacyAllocationPrefixLength = 24
+)
+
+// defaultAllocationPrefixLength is the size of the child IP block allocated to
+// the tenant from the IP block of a supernet without subnets nor VPC prefixes,
+// which the tenant needs to create the VPC.
+const defaultAllocationPrefixLength = 24
+
+// ipBlockRequest describes an IP block created for the cluster and allocated to its tenant.
+type ipBlockRequest struct {
+	name         string
+	prefix       string
+	prefixLength int
+	routingType  string
+	// allocationPrefixLength is the size of the child IP block allocated to the tenant
+	allocationPrefixLength int
+}
+
+// clusterIPBlocks are the child IP blocks allocated to the tenant that the
+// subnets without a dedicated IP block and the VPC prefixes are created from.
+type clusterIPBlocks struct {
+	// legacy is the child IP block of the clusters created by earlier releases,
+	// which serves all the CIDRs
+	legacy string
+	// cidrs maps the CIDRs of the subnets and VPC prefixes to the child IP
+	// block of their size allocated for them
+	cidrs map[netip.Prefix]string
+}
+
+// childIPBlockID returns the ID of the child IP block a subnet or VPC prefix
+// of cidr is created from, false when no IP block of the cluster covers it.
+func (b clusterIPBlocks) childIPBlockID(cidr string) (string, bool) {
+	if b.legacy != "" {
+		return b.legacy, true
+	}
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return "", false
+	}
+	childIPBlockID, ok := b.cidrs[prefix.Masked()]
+	return childIPBlockID, ok
+}
+
+// ensureIPBlockAndAllocation ensures an IP block exists for each supernet of
+// the CIDRs of the subnets and VPC prefixes, and that a child IP block of the
+// size of each of these CIDRs is allocated from it to the tenant. The subnet
+// or VPC prefix is created from the child IP block of its CIDR, so that it
+// takes no more than its own range. A supernet without CIDR gets a child IP
+// block of defaultAllocationPrefixLength, which the tenant needs to create
+// the VPC. The clusters created by earlier releases keep their single
+// 10.0.0.0/16 IP block.
 func (r *NcxInfraClusterReconciler) ensureIPBlockAndAllocation(
 	ctx context.Context, clusterScope *scope.ClusterScope, siteID string,
-) (string, error) {
-	logger := log.FromContext(ctx)
+) (clusterIPBlocks, error) {
+	clusterName := clusterScope.NcxInfraCluster.Name
+	if slices.ContainsFunc(clusterScope.IPBlocks(), func(ipBlock infrastructurev1.IPBlockStatus) bool {
+		return ipBlock.Subnet == "" && ipBlock.CIDR == ""
+	}) {
+		ipBlock := clusterScope.IPBlock("")
+		childIPBlockID, err := r.ensureIPBlock(ctx, clusterScope, siteID, ipBlockRequest{
+			name:                   clusterName,
+			prefix:                 legacyIPBlockPrefix,
+			prefixLength:           legacyIPBlockPrefixLength,
+			routingType:            routingTypeDatacenterOnly,
+			allocationPrefixLength: legacyAllocationPrefixLength,
+		}, &ipBlock)
+		clusterScope.SetIPBlock(ipBlock, err)
+		return clusterIPBlocks{legacy: childIPBlockID}, err
+	}
+
+	ipBlocks := clusterIPBlocks{cidrs: map[netip.Prefix]string{}}
+	supernets := clusterSupernets(clusterScope.NcxInfraCluster.Spec)
+	cidrs := map[netip.Prefix][]netip.Prefix{}
+	for _, cidr := range supernetCIDRs(clusterScope.NcxInfraCluster.Spec) {
+		if supernet, ok := supernetOf(supernets, cidr); ok {
+			cidrs[supernet] = append(cidrs[supernet], cidr)
+		}
+	}
+	for _, supernet := range supernets {
+		ipBlock := clusterScope.SupernetIPBlock(supernet.String())
+		req := ipBlockRequest{
+			name:                   fmt.Sprintf("%s-%s", clusterName, supernetName(supernet)),
+			prefix:                 supernet.Addr().String(),
+			prefixLength:           supernet.Bits(),
+			routingType:            routingTypeDatacenterOnly,
+			allocationPrefixLength: defaultAllocationPrefixLength,
+		}
+		if len(cidrs[supernet]) == 0 {
+			_, err := r.ensureIPBlock(ctx, clusterScope, siteID, req, &ipBlock)
+			clusterScope.SetIPBlock(ipBlock, err)
+			if err != nil {
+				return ipBlocks, err
+			}
+			continue
+		}
 
-	// If we already have a child IP block ID, verify it exists
-	if clusterScope.ChildIPBlockID() != "" {
-		ipBlock, _, err := clusterScope.NcxInfraClient.GetIpblock(ctx, clusterScope.OrgName, clusterScope.ChildIPBlockID())
-		if err == nil && ipBlock != nil {
-			logger.V(1).Info("Child IP block already exists", "childIPBlockID", clusterScope.ChildIPBlockID())
-			return clusterScope.ChildIPBlockID(), nil
+		err := r.ensureParentIPBlock(ctx, clusterScope, siteID, req, &ipBlock)
+		clusterScope.SetIPBlock(ipBlock, err)
+		if err != nil {
+			return ipBlocks, err
+		}
+		// The allocation of a CIDR is recorded along with the IP block of the
+		// supernet when they are the same prefix
+		for _, cidr := range cidrs[supernet] {
+			allocation := clusterScope.SupernetIPBlock(cidr.String())
+			childIPBlockID, err := r.ensureAllocation(ctx, clusterScope, siteID,
+				fmt.Sprintf("%s-%s", clusterName, supernetName(cidr)), ipBlock.IPBlockID, cidr.Bits(), &allocation)
+			clusterScope.SetIPBlock(allocation, err)
+			if err != nil {
+				return ipBlocks, err
+			}
+			ipBlocks.cidrs[cidr] = childIPBlockID
 		}
-		logger.Info("Existing child IP block not found, will recreate", "oldChildIPBlockID", clusterScope.ChildIPBlockID())
-		clusterScope.SetChildIPBlockID("")
-		clusterScope.SetAllocationID("")
 	}
+	return ipBlocks, nil
+}
+
+// ensureSubnetIPBlock ensures an IP block covering the CIDR of a subnet exists
+// and is allocated to the tenant: a Public one for a subnet with Public egress,
+// a DatacenterOnly one for a subnet placed in another site than the cluster or
+// outside its supernets.
+// Returns the child IP block ID.
+func (r *NcxInfraClusterReconciler) ensureSubnetIPBlock(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID string, subnetSpec infrastructurev1.SubnetSpec,
+) (string, error) {
+	_, ipNet, err := net.ParseCIDR(subnetSpec.CIDR)
+	if err != nil {
+		return "", fmt.Errorf("invalid CIDR %s: %w", subnetSpec.CIDR, err)
+	}
+	prefixLength, _ := ipNet.Mask.Size()
+	routingType := routingTypeDatacenterOnly
+	if subnetSpec.Egress == infrastructurev1.SubnetEgressPublic {
+		routingType = routingTypePublic
+	}
+
+	ipBlock := clusterScope.IPBlock(subnetSpec.Name)
+	childIPBlockID, err := r.ensureIPBlock(ctx, clusterScope, siteID, ipBlockRequest{
+		name:                   fmt.Sprintf("%s-%s", clusterScope.NcxInfraCluster.Name, subnetSpec.Name),
+		prefix:                 ipNet.IP.String(),
+		prefixLength:           prefixLength,
+		routingType:            routingType,
+		allocationPrefixLength: prefixLength,
+	}, &ipBlock)
+	clusterScope.SetIPBlock(ipBlock, err)
+	return childIPBlockID, err
+}
+
+// ensureIPBlock ensures the IP block described by req and its allocation to the
+// tenant exist, recording their IDs in ipBlock. Returns the child IP block ID.
+func (r *NcxInfraClusterReconciler) ensureIPBlock(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID string,
+	req ipBlockRequest, ipBlock *infrastructurev1.IPBlockStatus,
+) (string, error) {
+	// The parent IP block is only needed to allocate the child IP block again
+	if exists, err := r.childIPBlockExists(ctx, clusterScope, ipBlock); err != nil || exists {
+		return ipBlock.ChildIPBlockID, err
+	}
+	if err := r.ensureParentIPBlock(ctx, clusterScope, siteID, req, ipBlock); err != nil {
+		return "", err
+	}
+	return r.ensureAllocation(ctx, clusterScope, siteID, req.name, ipBlock.IPBlockID, req.allocationPrefixLength, ipBlock)
+}
+
+// ensureParentIPBlock ensures the IP block described by req exists, recording
+// its ID in ipBlock.
+func (r *NcxInfraClusterReconciler) ensureParentIPBlock(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID string,
+	req ipBlockRequest, ipBlock *infrastructurev1.IPBlockStatus,
+) error {
+	logger := log.FromContext(ctx)
 
-	// Step 1: Create parent IP block if needed
-	parentIPBlockID := clusterScope.IPBlockID()
-	if parentIPBlockID == "" {
-		ipBlockName := fmt.Sprintf("%s-ipblock", clusterScope.NcxInfraCluster.Name)
+	if ipBlock.IPBlockID == "" {
+		ipBlockName := fmt.Sprintf("%s-ipblock", req.name)
 		ipBlockReq := nico.IpBlockCreateRequest{
 			Name:            ipBlockName,
-			Prefix:          "10.0.0.0",
-			PrefixLength:    16,
+			Description:     nico.PtrString(ipBlockDescription(clusterScope)),
+			Prefix:          req.prefix,
+			PrefixLength:    int32(req.prefixLength),
 			ProtocolVersion: "IPv4",
-			RoutingType:     "DatacenterOnly",
+			RoutingType:     req.routingType,
 			SiteId:          siteID,
 		}
 
-		logger.Info("Creating IP block", "name", ipBlockName, "prefix", "10.0.0.0/16", "siteID", siteID)
-		ipBlock, httpResp, err := clusterScope.NcxInfraClient.CreateIpblock(ctx, clusterScope.OrgName, ipBlockReq)
-		if err != nil {
-			return "", fmt.Errorf("failed to create IP block: %w", err)
-		}
-		if httpResp.StatusCode != http.StatusCreated {
-			return "", fmt.Errorf("failed to create IP block, status %d", httpResp.StatusCode)
-		}
-		if ipBlock == nil || ipBlock.Id == nil {
-			return "", fmt.Errorf("IP block ID missing in response")
+		logger.Info("Creating IP block", "name", ipBlockName,
+			"prefix", fmt.Sprintf("%s/%d", req.prefix, req.prefixLength),
+			"routingType", req.routingType, "siteID", siteID)
+		created, httpResp, err := clusterScope.NcxInfraClient.CreateIpblock(ctx, clusterScope.OrgName, ipBlockReq)
+		if httpResp != nil && httpResp.StatusCode == http.StatusConflict {
+			// 409 Conflict — an IP block of that name already exists, created
+			// by a previous attempt whose status was lost or by hand.
+			if err := r.reuseExistingIPBlock(ctx, clusterScope, siteID, ipBlockReq, ipBlock); err != nil {
+				return err
+			}
+		} else {
+			if err != nil {
+				return fmt.Errorf("failed to create IP block: %w",
+					scope.WithPermissionError(httpResp, err, "CreateIpblock"))
+			}
+			if httpResp.StatusCode != http.StatusCreated {
+				return fmt.Errorf("failed to create IP block, status %d", httpResp.StatusCode)
+			}
+			if created == nil || created.Id == nil {
+				return fmt.Errorf("IP block ID missing in response")
+			}
+
+			ipBlock.IPBlockID = *created.Id
+			logger.Info("Successfully created IP block", "ipBlockID", ipBlock.IPBlockID)
 		}
+	}
+	return nil
+}
+
+// ensureAllocation ensures a child IP block of prefixLength is allocated to
+// the tenant from the IP block parentIPBlockID, recording the allocation and
+// child IP block IDs in ipBlock. Returns the child IP block ID.
+func (r *NcxInfraClusterReconciler) ensureAllocation(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID, name, parentIPBlockID string,
+	prefixLength int, ipBlock *infrastructurev1.IPBlockStatus,
+) (string, error) {
+	logger := log.FromContext(ctx)
 
-		parentIPBlockID = *ipBlock.Id
-		clusterScope.SetIPBlockID(parentIPBlockID)
-		logger.Info("Successfully created IP block", "ipBlockID", parentIPBlockID)
+	if exists, err := r.childIPBlockExists(ctx, clusterScope, ipBlock); err != nil || exists {
+		return ipBlock.ChildIPBlockID, err
 	}
 
-	// Step 2: Create allocation to link tenant to IP block (creates child IP block)
-	if clusterScope.AllocationID() == "" {
-		allocName := fmt.Sprintf("%s-allocation", clusterScope.NcxInfraCluster.Name)
+	// Create allocation to link tenant to IP block (creates child IP block)
+	if ipBlock.AllocationID == "" {
+		allocName := fmt.Sprintf("%s-allocation", name)
 		resourceType := resourceTypeIPBlock
 		allocReq := nico.AllocationCreateRequest{
 			Name:     allocName,
@@ -422,7 +955,7 @@ func (r *NcxInfraClusterReconciler) ensureIPBlockAndAllocation(
 					ResourceType:    &resourceType,
 					ResourceTypeId:  parentIPBlockID,
 					ConstraintType:  "OnDemand",
-					ConstraintValue: 24,
+					ConstraintValue: int32(prefixLength),
 				},
 			},
 		}
@@ -435,9 +968,9 @@ func (r *NcxInfraClusterReconciler) ensureIPBlockAndAllocation(
 		if httpResp != nil && httpResp.StatusCode == http.StatusCreated {
 			// Allocation created — extract IDs if available
 			if alloc != nil && alloc.Id != nil {
-				clusterScope.SetAllocationID(*alloc.Id)
+				ipBlock.AllocationID = *alloc.Id
 				logger.Info("Successfully created allocation", "allocationID", *alloc.Id)
-				r.extractChildIPBlockID(clusterScope, alloc)
+				extractChildIPBlockID(ipBlock, alloc)
 			} else if err != nil {
 				// SDK deserialization failed but allocation was created.
 				// We cannot recover without knowing the allocation ID.
@@ -451,44 +984,111 @@ func (r *NcxInfraClusterReconciler) ensureIPBlockAndAllocation(
 			if foundAlloc, err := r.findExistingAllocation(ctx, clusterScope, allocName); err != nil {
 				return "", fmt.Errorf("failed to find existing allocation: %w", err)
 			} else if foundAlloc != nil && foundAlloc.Id != nil {
-				clusterScope.SetAllocationID(*foundAlloc.Id)
-				r.extractChildIPBlockID(clusterScope, foundAlloc)
+				ipBlock.AllocationID = *foundAlloc.Id
+				extractChildIPBlockID(ipBlock, foundAlloc)
 				logger.Info("Found existing allocation", "allocationID", *foundAlloc.Id)
 			} else {
 				return "", fmt.Errorf("allocation conflict but could not find existing allocation")
 			}
 		} else if err != nil {
-			return "", fmt.Errorf("failed to create allocation: %w", err)
+			return "", fmt.Errorf("failed to create allocation: %w",
+				scope.WithPermissionError(httpResp, err, "CreateAllocation"))
 		}
 	}
 
 	// If we have an allocation ID but no child IP block ID, query the allocation
-	if clusterScope.AllocationID() != "" && clusterScope.ChildIPBlockID() == "" {
-		alloc, _, err := clusterScope.NcxInfraClient.GetAllocation(ctx, clusterScope.OrgName, clusterScope.AllocationID())
+	if ipBlock.AllocationID != "" && ipBlock.ChildIPBlockID == "" {
+		alloc, _, err := clusterScope.NcxInfraClient.GetAllocation(ctx, clusterScope.OrgName, ipBlock.AllocationID)
 		if err != nil {
-			return "", fmt.Errorf("failed to get allocation %s: %w", clusterScope.AllocationID(), err)
+			return "", fmt.Errorf("failed to get allocation %s: %w", ipBlock.AllocationID, err)
 		}
 		if alloc != nil {
-			r.extractChildIPBlockID(clusterScope, alloc)
+			extractChildIPBlockID(ipBlock, alloc)
 		}
 	}
 
-	childIPBlockID := clusterScope.ChildIPBlockID()
-	if childIPBlockID == "" {
+	if ipBlock.ChildIPBlockID == "" {
 		return "", fmt.Errorf("child IP block ID not found after allocation creation")
 	}
 
-	return childIPBlockID, nil
+	return ipBlock.ChildIPBlockID, nil
+}
+
+// childIPBlockExists verifies that the child IP block recorded in ipBlock
+// still exists, clearing it and its allocation from ipBlock when it is gone so
+// that they are created again.
+func (r *NcxInfraClusterReconciler) childIPBlockExists(
+	ctx context.Context, clusterScope *scope.ClusterScope, ipBlock *infrastructurev1.IPBlockStatus,
+) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	if ipBlock.ChildIPBlockID == "" {
+		return false, nil
+	}
+	child, httpResp, err := clusterScope.NcxInfraClient.GetIpblock(ctx, clusterScope.OrgName, ipBlock.ChildIPBlockID)
+	exists, err := lookupExisting(httpResp, err, child != nil, "GetIpblock", "child IP block", ipBlock.ChildIPBlockID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		logger.V(1).Info("Child IP block already exists", "childIPBlockID", ipBlock.ChildIPBlockID)
+		return true, nil
+	}
+	logger.Info("Existing child IP block not found, will recreate", "oldChildIPBlockID", ipBlock.ChildIPBlockID)
+	ipBlock.ChildIPBlockID = ""
+	ipBlock.AllocationID = ""
+	return false, nil
+}
+
+// ipBlockDescription identifies the provider and the cluster as the creator of
+// an IP block, to tell it apart from an IP block created by hand.
+func ipBlockDescription(clusterScope *scope.ClusterScope) string {
+	return fmt.Sprintf("Created by %s for cluster %s", infrastructurev1.ResourceCreator,
+		client.ObjectKeyFromObject(clusterScope.NcxInfraCluster))
+}
+
+// reuseExistingIPBlock records the existing IP block matching req in ipBlock.
+// An IP block the provider did not create for the cluster is adopted: the
+// cluster carves its allocation from it but leaves it on deletion.
+func (r *NcxInfraClusterReconciler) reuseExistingIPBlock(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID string,
+	req nico.IpBlockCreateRequest, ipBlock *infrastructurev1.IPBlockStatus,
+) error {
+	logger := log.FromContext(ctx)
+
+	logger.Info("IP block already exists (409 Conflict), querying existing IP blocks", "name", req.Name)
+	ipBlocks, httpResp, err := clusterScope.NcxInfraClient.GetAllIpblock(ctx, clusterScope.OrgName, siteID)
+	if err != nil {
+		return fmt.Errorf("failed to list IP blocks: %w", scope.WithPermissionError(httpResp, err, "GetAllIpblock"))
+	}
+	idx := slices.IndexFunc(ipBlocks, func(existing nico.IpBlock) bool {
+		return existing.GetName() == req.Name
+	})
+	if idx < 0 || ipBlocks[idx].Id == nil {
+		return fmt.Errorf("IP block conflict but could not find existing IP block %s", req.Name)
+	}
+	existing := ipBlocks[idx]
+	if existing.GetPrefix() != req.Prefix || existing.GetPrefixLength() != req.PrefixLength {
+		return fmt.Errorf("existing IP block %s has prefix %s/%d instead of %s/%d", req.Name,
+			existing.GetPrefix(), existing.GetPrefixLength(), req.Prefix, req.PrefixLength)
+	}
+
+	ipBlock.IPBlockID = *existing.Id
+	ipBlock.Adopted = existing.GetDescription() != req.GetDescription()
+	if ipBlock.Adopted {
+		r.recordEvent(clusterScope.NcxInfraCluster, "IPBlockAdopted",
+			"Adopted IP block %s (%s), which is kept on deletion", req.Name, ipBlock.IPBlockID)
+	}
+	logger.Info("Found existing IP block", "ipBlockID", ipBlock.IPBlockID, "adopted", ipBlock.Adopted)
+	return nil
 }
 
 // extractChildIPBlockID extracts the child IP block ID from an allocation's constraints.
-func (r *NcxInfraClusterReconciler) extractChildIPBlockID(
-	clusterScope *scope.ClusterScope, alloc *nico.Allocation,
-) {
+func extractChildIPBlockID(ipBlock *infrastructurev1.IPBlockStatus, alloc *nico.Allocation) {
 	for _, ac := range alloc.AllocationConstraints {
 		if ac.ResourceType != nil && *ac.ResourceType == resourceTypeIPBlock {
 			if derivedID := ac.DerivedResourceId.Get(); derivedID != nil {
-				clusterScope.SetChildIPBlockID(*derivedID)
+				ipBlock.ChildIPBlockID = *derivedID
 				break
 			}
 		}
@@ -516,201 +1116,398 @@ func (r *NcxInfraClusterReconciler) findExistingAllocation(
 func (r *NcxInfraClusterReconciler) reconcileSubnets(
 	ctx context.Context, clusterScope *scope.ClusterScope, siteID string,
 ) error {
-	logger := log.FromContext(ctx)
-
 	vpcID := clusterScope.VPCID()
 	if vpcID == "" {
 		return fmt.Errorf("VPC ID is empty")
 	}
 
 	// Ensure IP block and allocation exist (creates child IP block for tenant)
-	childIPBlockID, err := r.ensureIPBlockAndAllocation(ctx, clusterScope, siteID)
+	ipBlocks, err := r.ensureIPBlockAndAllocation(ctx, clusterScope, siteID)
 	if err != nil {
 		return fmt.Errorf("failed to ensure IP block and allocation: %w", err)
 	}
 
-	subnetIDs := clusterScope.SubnetIDs()
-
-	// Reconcile each subnet
+	// Reconcile each subnet, in the VPC of the cluster in the site of the subnet
+	sites := []string{siteID}
+	controlPlaneSites := map[string]bool{}
 	for _, subnetSpec := range clusterScope.NcxInfraCluster.Spec.Subnets {
-		// Check if subnet already exists
-		if existingID, exists := subnetIDs[subnetSpec.Name]; exists {
-			// Verify subnet still exists in NVIDIA Carbide
-			subnet, _, err := clusterScope.NcxInfraClient.GetSubnet(ctx, clusterScope.OrgName, existingID)
-			if err != nil || subnet == nil {
-				logger.Error(err, "Subnet not found in NVIDIA Carbide, will recreate",
-					"subnetName", subnetSpec.Name, "subnetID", existingID)
-				delete(subnetIDs, subnetSpec.Name)
-			} else {
-				logger.V(1).Info("Subnet already exists", "subnetName", subnetSpec.Name, "subnetID", existingID)
-				continue
-			}
-		}
-
-		// Parse CIDR to get prefix length
-		prefixLength, err := parseCIDR(subnetSpec.CIDR)
+		subnetSiteID, err := clusterScope.SubnetSiteID(ctx, subnetSpec)
 		if err != nil {
-			return fmt.Errorf("failed to parse CIDR for subnet %s: %w", subnetSpec.Name, err)
-		}
-
-		// Create subnet using child IP block (tenant-owned, from allocation)
-		subnetReq := nico.SubnetCreateRequest{
-			Name:         subnetSpec.Name,
-			VpcId:        vpcID,
-			Ipv4BlockId:  &childIPBlockID,
-			PrefixLength: int32(prefixLength),
+			err = fmt.Errorf("failed to resolve the site of subnet %s: %w", subnetSpec.Name, err)
+			clusterScope.SetSubnetError(subnetSpec.Name, err)
+			return err
 		}
-
-		logger.Info("Creating subnet",
-			"name", subnetSpec.Name, "cidr", subnetSpec.CIDR,
-			"prefixLength", prefixLength, "vpcID", vpcID,
-			"childIPBlockID", childIPBlockID)
-		subnet, httpResp, err := clusterScope.NcxInfraClient.CreateSubnet(ctx, clusterScope.OrgName, subnetReq)
-		if err != nil {
-			return fmt.Errorf("failed to create subnet %s: %w", subnetSpec.Name, err)
+		subnetVPCID := vpcID
+		if subnetSiteID != siteID {
+			if subnetVPCID, err = r.reconcileSiteVPC(ctx, clusterScope, subnetSiteID); err != nil {
+				clusterScope.SetSiteVPCError(subnetSiteID, err)
+				return err
+			}
+			if !slices.Contains(sites, subnetSiteID) {
+				sites = append(sites, subnetSiteID)
+			}
 		}
-
-		if httpResp.StatusCode != http.StatusCreated {
-			return fmt.Errorf("failed to create subnet %s, status %d", subnetSpec.Name, httpResp.StatusCode)
+		if subnetSpec.Role != "worker" {
+			controlPlaneSites[subnetSiteID] = true
 		}
-
-		if subnet == nil || subnet.Id == nil {
-			return fmt.Errorf("subnet ID missing in response for %s", subnetSpec.Name)
+		if err := r.reconcileSubnet(ctx, clusterScope, subnetSiteID, subnetVPCID, ipBlocks, subnetSpec); err != nil {
+			clusterScope.SetSubnetError(subnetSpec.Name, err)
+			return err
 		}
-
-		clusterScope.SetSubnetID(subnetSpec.Name, *subnet.Id)
-		logger.Info("Successfully created subnet", "subnetName", subnetSpec.Name, "subnetID", *subnet.Id)
-		r.recordEvent(clusterScope.NcxInfraCluster, "SubnetCreated",
-			"Successfully created subnet %s (%s)", subnetSpec.Name, *subnet.Id)
 	}
 
+	clusterScope.SetFailureDomains(siteFailureDomains(sites, controlPlaneSites))
 	return nil
 }
 
-func (r *NcxInfraClusterReconciler) reconcileVPCPrefixes(
-	ctx context.Context, clusterScope *scope.ClusterScope, siteID string,
-) error {
-	logger := log.FromContext(ctx)
-
-	vpcID := clusterScope.VPCID()
-	if vpcID == "" {
-		return fmt.Errorf("VPC ID is empty")
+// siteFailureDomains returns the failure domains of a cluster whose subnets
+// span several sites, one per site. A site is suitable for control plane
+// machines when one of its subnets is not restricted to workers. A cluster in
+// a single site has no failure domains.
+func siteFailureDomains(sites []string, controlPlaneSites map[string]bool) []clusterv1.FailureDomain {
+	if len(sites) < 2 {
+		return nil
 	}
-
-	// Ensure IP block and allocation exist (creates child IP block for tenant)
-	childIPBlockID, err := r.ensureIPBlockAndAllocation(ctx, clusterScope, siteID)
-	if err != nil {
-		return fmt.Errorf("failed to ensure IP block and allocation: %w", err)
+	slices.Sort(sites)
+	failureDomains := make([]clusterv1.FailureDomain, 0, len(sites))
+	for _, siteID := range sites {
+		failureDomains = append(failureDomains, clusterv1.FailureDomain{
+			Name:         siteID,
+			ControlPlane: ptr.To(controlPlaneSites[siteID]),
+		})
 	}
+	return failureDomains
+}
 
-	vpcPrefixIDs := clusterScope.VPCPrefixIDs()
+// siteVPCName returns the name of the VPC of a cluster in a site other than
+// its own: the name of the VPC of the cluster, suffixed with the beginning of
+// the site ID.
+func siteVPCName(vpcName, siteID string) string {
+	return fmt.Sprintf("%s-%s", vpcName, siteID[:min(8, len(siteID))])
+}
 
-	for _, prefixSpec := range clusterScope.NcxInfraCluster.Spec.VPCPrefixes {
-		// Check if VPC Prefix already exists
-		if existingID, exists := vpcPrefixIDs[prefixSpec.Name]; exists {
-			prefix, _, err := clusterScope.NcxInfraClient.GetVpcPrefix(ctx, clusterScope.OrgName, existingID)
-			if err != nil || prefix == nil {
-				logger.Error(err, "VPC Prefix not found, will recreate",
-					"prefixName", prefixSpec.Name, "prefixID", existingID)
-				delete(vpcPrefixIDs, prefixSpec.Name)
-			} else {
-				logger.V(1).Info("VPC Prefix already exists", "prefixName", prefixSpec.Name, "prefixID", existingID)
-				continue
-			}
-		}
+// reconcileSiteVPC creates the VPC of the cluster in a site other than its
+// own, for the subnets placed in that site, unless it already exists. It has
+// the settings of the VPC of the cluster, but none of its NSG and peerings.
+// Returns the VPC ID.
+func (r *NcxInfraClusterReconciler) reconcileSiteVPC(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID string,
+) (string, error) {
+	logger := log.FromContext(ctx)
 
-		prefixLength, err := parseCIDR(prefixSpec.CIDR)
+	if vpcID := clusterScope.SiteVPCIDs()[siteID]; vpcID != "" {
+		vpc, httpResp, err := clusterScope.NcxInfraClient.GetVpc(ctx, clusterScope.OrgName, vpcID)
+		exists, err := lookupExisting(httpResp, err, vpc != nil, "GetVpc", "VPC", vpcID)
 		if err != nil {
-			return fmt.Errorf("failed to parse CIDR for VPC prefix %s: %w", prefixSpec.Name, err)
-		}
-
-		prefixReq := nico.VpcPrefixCreateRequest{
-			Name:         prefixSpec.Name,
-			VpcId:        vpcID,
-			IpBlockId:    &childIPBlockID,
-			PrefixLength: int32(prefixLength),
+			return "", err
 		}
-
-		logger.Info("Creating VPC Prefix",
-			"name", prefixSpec.Name, "cidr", prefixSpec.CIDR,
-			"prefixLength", prefixLength, "vpcID", vpcID)
-		prefix, httpResp, err := clusterScope.NcxInfraClient.CreateVpcPrefix(ctx, clusterScope.OrgName, prefixReq)
-		if err != nil {
-			return fmt.Errorf("failed to create VPC prefix %s: %w", prefixSpec.Name, err)
+		if exists {
+			logger.V(1).Info("Site VPC already exists", "siteID", siteID, "vpcID", vpcID)
+			clusterScope.SetSiteVPCID(siteID, vpcID)
+			return vpcID, nil
 		}
+		logger.Info("Site VPC not found, will recreate", "siteID", siteID, "vpcID", vpcID)
+		clusterScope.SetSiteVPCID(siteID, "")
+	}
 
-		if httpResp.StatusCode != http.StatusCreated {
-			return fmt.Errorf("failed to create VPC prefix %s, status %d", prefixSpec.Name, httpResp.StatusCode)
-		}
+	vpcSpec := clusterScope.NcxInfraCluster.Spec.VPC
+	vpcReq := nico.VpcCreateRequest{
+		Name:   siteVPCName(vpcSpec.Name, siteID),
+		SiteId: siteID,
+	}
+	if vpcSpec.NetworkVirtualizationType != "" {
+		netVirtType := vpcSpec.NetworkVirtualizationType
+		vpcReq.NetworkVirtualizationType = *nico.NewNullableString(&netVirtType)
+	}
+	if vpcSpec.Description != "" {
+		vpcReq.Description = &vpcSpec.Description
+	}
+	if len(vpcSpec.Labels) > 0 {
+		vpcReq.Labels = vpcSpec.Labels
+	}
+
+	logger.Info("Creating site VPC", "name", vpcReq.Name, "siteID", siteID)
+	createStart := time.Now()
+	vpc, httpResp, err := clusterScope.NcxInfraClient.CreateVpc(ctx, clusterScope.OrgName, vpcReq)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "CreateVpc")
+	recordAPIMetrics("CreateVpc", createStart, apiErr)
+	if apiErr != nil {
+		return "", ncxinfraerrors.Classify(ncxinfraerrors.VPCCreateFailed,
+			fmt.Errorf("failed to create VPC in site %s: %w", siteID, apiErr))
+	}
+	if vpc == nil || vpc.Id == nil {
+		return "", fmt.Errorf("VPC ID missing in response")
+	}
 
-		if prefix == nil || prefix.Id == nil {
-			return fmt.Errorf("VPC prefix ID missing in response for %s", prefixSpec.Name)
+	clusterScope.SetSiteVPCID(siteID, *vpc.Id)
+	clusterScope.SetResourceOrigin(*vpc.Id, "VPC", vpcReq.Name, vpc.Created)
+	logger.Info("Successfully created site VPC", "siteID", siteID, "vpcID", *vpc.Id)
+	r.recordEvent(clusterScope.NcxInfraCluster, "VPCCreated",
+		"Successfully created VPC %s in site %s", *vpc.Id, siteID)
+	ncxinframetrics.VPCCount.WithLabelValues(siteID).Inc()
+	return *vpc.Id, nil
+}
+
+// reconcileSubnet creates a subnet of the cluster unless it already exists.
+func (r *NcxInfraClusterReconciler) reconcileSubnet(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID, vpcID string, ipBlocks clusterIPBlocks,
+	subnetSpec infrastructurev1.SubnetSpec,
+) error {
+	logger := log.FromContext(ctx)
+
+	// Check if subnet already exists
+	if existingID, exists := clusterScope.SubnetIDs()[subnetSpec.Name]; exists {
+		// Verify subnet still exists in NVIDIA Carbide
+		subnet, httpResp, err := clusterScope.NcxInfraClient.GetSubnet(ctx, clusterScope.OrgName, existingID)
+		exists, err := lookupExisting(httpResp, err, subnet != nil, "GetSubnet", "subnet", existingID)
+		if err != nil {
+			return err
 		}
+		if !exists {
+			logger.Info("Subnet not found in NVIDIA Carbide, will recreate",
+				"subnetName", subnetSpec.Name, "subnetID", existingID)
+			clusterScope.SetSubnetID(subnetSpec.Name, "")
+		} else {
+			logger.V(1).Info("Subnet already exists", "subnetName", subnetSpec.Name, "subnetID", existingID)
+			clusterScope.SetSubnetID(subnetSpec.Name, existingID)
+			if prefix := subnetPrefix(subnet); prefix != "" {
+				clusterScope.SetSubnetCIDR(subnetSpec.Name, prefix)
+			}
+			return checkSubnetCIDR(ipBlocks, subnetSpec, subnetPrefix(subnet))
+		}
+	}
 
-		clusterScope.SetVPCPrefixID(prefixSpec.Name, *prefix.Id)
-		logger.Info("Successfully created VPC Prefix", "prefixName", prefixSpec.Name, "prefixID", *prefix.Id)
-		r.recordEvent(clusterScope.NcxInfraCluster, "VPCPrefixCreated",
-			"Successfully created VPC Prefix %s (%s)", prefixSpec.Name, *prefix.Id)
+	// Parse CIDR to get prefix length
+	prefixLength, err := parseCIDR(subnetSpec.CIDR)
+	if err != nil {
+		return fmt.Errorf("failed to parse CIDR for subnet %s: %w", subnetSpec.Name, err)
 	}
 
-	return nil
+	// Subnets with Public egress, placed in another site than the cluster and
+	// thus in another VPC, or outside the supernets of the cluster, are
+	// allocated from an IP block of their own
+	ipv4BlockID, covered := ipBlocks.childIPBlockID(subnetSpec.CIDR)
+	if subnetSpec.Egress == infrastructurev1.SubnetEgressPublic || vpcID != clusterScope.VPCID() || !covered {
+		ipv4BlockID, err = r.ensureSubnetIPBlock(ctx, clusterScope, siteID, subnetSpec)
+		if err != nil {
+			return fmt.Errorf("failed to ensure IP block for subnet %s: %w", subnetSpec.Name, err)
+		}
+	}
+
+	// Create subnet using child IP block (tenant-owned, from allocation)
+	subnetReq := nico.SubnetCreateRequest{
+		Name:         subnetSpec.Name,
+		VpcId:        vpcID,
+		Ipv4BlockId:  &ipv4BlockID,
+		PrefixLength: int32(prefixLength),
+	}
+
+	logger.Info("Creating subnet",
+		"name", subnetSpec.Name, "cidr", subnetSpec.CIDR,
+		"prefixLength", prefixLength, "vpcID", vpcID,
+		"egress", subnetSpec.Egress, "childIPBlockID", ipv4BlockID)
+	subnet, httpResp, err := clusterScope.NcxInfraClient.CreateSubnet(ctx, clusterScope.OrgName, subnetReq)
+	if err != nil {
+		return ncxinfraerrors.Classify(ncxinfraerrors.SubnetCreateFailed, fmt.Errorf("failed to create subnet %s: %w",
+			subnetSpec.Name, scope.WithPermissionError(httpResp, err, "CreateSubnet")))
+	}
+
+	if httpResp.StatusCode != http.StatusCreated {
+		return ncxinfraerrors.Transient(ncxinfraerrors.SubnetCreateFailed,
+			fmt.Errorf("failed to create subnet %s, status %d", subnetSpec.Name, httpResp.StatusCode))
+	}
+
+	if subnet == nil || subnet.Id == nil {
+		return fmt.Errorf("subnet ID missing in response for %s", subnetSpec.Name)
+	}
+
+	clusterScope.SetSubnetID(subnetSpec.Name, *subnet.Id)
+	clusterScope.SetSubnetCIDR(subnetSpec.Name, subnetPrefix(subnet))
+	clusterScope.SetResourceOrigin(*subnet.Id, "Subnet", subnetSpec.Name, subnet.Created)
+	logger.Info("Successfully created subnet", "subnetName", subnetSpec.Name, "subnetID", *subnet.Id,
+		"cidr", subnetPrefix(subnet))
+	r.recordEvent(clusterScope.NcxInfraCluster, "SubnetCreated",
+		"Successfully created subnet %s (%s)", subnetSpec.Name, *subnet.Id)
+	return checkSubnetCIDR(ipBlocks, subnetSpec, subnetPrefix(subnet))
 }
 
-func (r *NcxInfraClusterReconciler) reconcileVPCPeerings(
+// checkSubnetCIDR fails with a terminal error when NVIDIA Carbide allocated a
+// subnet another prefix than its CIDR. The subnets are created with the
+// prefix length of their CIDR only, from a child IP block of that size, so
+// the prefix only differs when the IP block does. The subnets of the clusters
+// created by earlier releases are allocated from a single IP block and only
+// reported in the SubnetCIDRMismatch condition.
+func checkSubnetCIDR(ipBlocks clusterIPBlocks, subnetSpec infrastructurev1.SubnetSpec, prefix string) error {
+	if ipBlocks.legacy != "" || prefix == "" || sameCIDR(prefix, subnetSpec.CIDR) {
+		return nil
+	}
+	return ncxinfraerrors.Terminalf(ncxinfraerrors.SubnetCIDRMismatch,
+		"subnet %s was allocated %s instead of its CIDR %s", subnetSpec.Name, prefix, subnetSpec.CIDR)
+}
+
+func (r *NcxInfraClusterReconciler) reconcileVPCPrefixes(
 	ctx context.Context, clusterScope *scope.ClusterScope, siteID string,
 ) error {
-	logger := log.FromContext(ctx)
-
 	vpcID := clusterScope.VPCID()
 	if vpcID == "" {
 		return fmt.Errorf("VPC ID is empty")
 	}
 
-	peeringIDs := clusterScope.VPCPeeringIDs()
+	// Ensure IP block and allocation exist (creates child IP block for tenant)
+	ipBlocks, err := r.ensureIPBlockAndAllocation(ctx, clusterScope, siteID)
+	if err != nil {
+		return fmt.Errorf("failed to ensure IP block and allocation: %w", err)
+	}
 
-	for _, peeringSpec := range clusterScope.NcxInfraCluster.Spec.VPCPeerings {
-		// Check if peering already exists
-		if existingID, exists := peeringIDs[peeringSpec.PeerVPCID]; exists {
-			peering, _, err := clusterScope.NcxInfraClient.GetVpcPeering(ctx, clusterScope.OrgName, existingID)
-			if err != nil || peering == nil {
-				logger.Error(err, "VPC Peering not found, will recreate",
-					"peerVpcId", peeringSpec.PeerVPCID, "peeringID", existingID)
-				delete(peeringIDs, peeringSpec.PeerVPCID)
-			} else {
-				logger.V(1).Info("VPC Peering already exists",
-					"peerVpcId", peeringSpec.PeerVPCID, "peeringID", existingID)
-				continue
-			}
+	for _, prefixSpec := range clusterScope.NcxInfraCluster.Spec.VPCPrefixes {
+		childIPBlockID, covered := ipBlocks.childIPBlockID(prefixSpec.CIDR)
+		err := fmt.Errorf("no IP block of the cluster covers CIDR %s", prefixSpec.CIDR)
+		if covered {
+			err = r.reconcileVPCPrefix(ctx, clusterScope, vpcID, childIPBlockID, prefixSpec)
 		}
-
-		peeringReq := nico.VpcPeeringCreateRequest{
-			Vpc1Id: vpcID,
-			Vpc2Id: peeringSpec.PeerVPCID,
-			SiteId: siteID,
+		if err != nil {
+			clusterScope.SetVPCPrefixError(prefixSpec.Name, err)
+			return err
 		}
+	}
 
-		logger.Info("Creating VPC Peering",
-			"vpc1Id", vpcID, "vpc2Id", peeringSpec.PeerVPCID, "siteID", siteID)
-		peering, httpResp, err := clusterScope.NcxInfraClient.CreateVpcPeering(ctx, clusterScope.OrgName, peeringReq)
+	return nil
+}
+
+// reconcileVPCPrefix creates a VPC Prefix of the cluster unless it already exists.
+func (r *NcxInfraClusterReconciler) reconcileVPCPrefix(
+	ctx context.Context, clusterScope *scope.ClusterScope, vpcID, childIPBlockID string,
+	prefixSpec infrastructurev1.VPCPrefixSpec,
+) error {
+	logger := log.FromContext(ctx)
+
+	// Check if VPC Prefix already exists
+	if existingID, exists := clusterScope.VPCPrefixIDs()[prefixSpec.Name]; exists {
+		prefix, httpResp, err := clusterScope.NcxInfraClient.GetVpcPrefix(ctx, clusterScope.OrgName, existingID)
+		exists, err := lookupExisting(httpResp, err, prefix != nil, "GetVpcPrefix", "VPC prefix", existingID)
 		if err != nil {
-			return fmt.Errorf("failed to create VPC peering with %s: %w", peeringSpec.PeerVPCID, err)
+			return err
 		}
+		if !exists {
+			logger.Info("VPC Prefix not found, will recreate",
+				"prefixName", prefixSpec.Name, "prefixID", existingID)
+			clusterScope.SetVPCPrefixID(prefixSpec.Name, "")
+		} else {
+			logger.V(1).Info("VPC Prefix already exists", "prefixName", prefixSpec.Name, "prefixID", existingID)
+			clusterScope.SetVPCPrefixID(prefixSpec.Name, existingID)
+			return nil
+		}
+	}
 
-		if httpResp.StatusCode != http.StatusCreated {
-			return fmt.Errorf("failed to create VPC peering with %s, status %d", peeringSpec.PeerVPCID, httpResp.StatusCode)
+	prefixLength, err := parseCIDR(prefixSpec.CIDR)
+	if err != nil {
+		return fmt.Errorf("failed to parse CIDR for VPC prefix %s: %w", prefixSpec.Name, err)
+	}
+
+	prefixReq := nico.VpcPrefixCreateRequest{
+		Name:         prefixSpec.Name,
+		VpcId:        vpcID,
+		IpBlockId:    &childIPBlockID,
+		PrefixLength: int32(prefixLength),
+	}
+
+	logger.Info("Creating VPC Prefix",
+		"name", prefixSpec.Name, "cidr", prefixSpec.CIDR,
+		"prefixLength", prefixLength, "vpcID", vpcID)
+	prefix, httpResp, err := clusterScope.NcxInfraClient.CreateVpcPrefix(ctx, clusterScope.OrgName, prefixReq)
+	if err != nil {
+		return fmt.Errorf("failed to create VPC prefix %s: %w", prefixSpec.Name,
+			scope.WithPermissionError(httpResp, err, "CreateVpcPrefix"))
+	}
+
+	if httpResp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to create VPC prefix %s, status %d", prefixSpec.Name, httpResp.StatusCode)
+	}
+
+	if prefix == nil || prefix.Id == nil {
+		return fmt.Errorf("VPC prefix ID missing in response for %s", prefixSpec.Name)
+	}
+
+	clusterScope.SetVPCPrefixID(prefixSpec.Name, *prefix.Id)
+	clusterScope.SetResourceOrigin(*prefix.Id, "VPCPrefix", prefixSpec.Name, prefix.Created)
+	logger.Info("Successfully created VPC Prefix", "prefixName", prefixSpec.Name, "prefixID", *prefix.Id)
+	r.recordEvent(clusterScope.NcxInfraCluster, "VPCPrefixCreated",
+		"Successfully created VPC Prefix %s (%s)", prefixSpec.Name, *prefix.Id)
+	return nil
+}
+
+func (r *NcxInfraClusterReconciler) reconcileVPCPeerings(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID string,
+) error {
+	vpcID := clusterScope.VPCID()
+	if vpcID == "" {
+		return fmt.Errorf("VPC ID is empty")
+	}
+
+	for _, peeringSpec := range clusterScope.NcxInfraCluster.Spec.VPCPeerings {
+		if err := r.reconcileVPCPeering(ctx, clusterScope, siteID, vpcID, peeringSpec); err != nil {
+			clusterScope.SetVPCPeeringError(peeringSpec.PeerVPCID, err)
+			return err
 		}
+	}
+
+	return nil
+}
 
-		if peering == nil || peering.Id == nil {
-			return fmt.Errorf("VPC peering ID missing in response for peer %s", peeringSpec.PeerVPCID)
+// reconcileVPCPeering creates a VPC Peering of the cluster unless it already exists.
+func (r *NcxInfraClusterReconciler) reconcileVPCPeering(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID, vpcID string,
+	peeringSpec infrastructurev1.VPCPeeringSpec,
+) error {
+	logger := log.FromContext(ctx)
+
+	// Check if peering already exists
+	if existingID, exists := clusterScope.VPCPeeringIDs()[peeringSpec.PeerVPCID]; exists {
+		peering, httpResp, err := clusterScope.NcxInfraClient.GetVpcPeering(ctx, clusterScope.OrgName, existingID)
+		exists, err := lookupExisting(httpResp, err, peering != nil, "GetVpcPeering", "VPC peering", existingID)
+		if err != nil {
+			return err
 		}
+		if !exists {
+			logger.Info("VPC Peering not found, will recreate",
+				"peerVPCID", peeringSpec.PeerVPCID, "peeringID", existingID)
+			clusterScope.SetVPCPeeringID(peeringSpec.PeerVPCID, "")
+		} else {
+			logger.V(1).Info("VPC Peering already exists",
+				"peerVPCID", peeringSpec.PeerVPCID, "peeringID", existingID)
+			clusterScope.SetVPCPeeringID(peeringSpec.PeerVPCID, existingID)
+			return nil
+		}
+	}
 
-		clusterScope.SetVPCPeeringID(peeringSpec.PeerVPCID, *peering.Id)
-		logger.Info("Successfully created VPC Peering",
-			"peerVpcId", peeringSpec.PeerVPCID, "peeringID", *peering.Id)
-		r.recordEvent(clusterScope.NcxInfraCluster, "VPCPeeringCreated",
-			"Successfully created VPC Peering %s with peer %s", *peering.Id, peeringSpec.PeerVPCID)
+	peeringReq := nico.VpcPeeringCreateRequest{
+		Vpc1Id: vpcID,
+		Vpc2Id: peeringSpec.PeerVPCID,
+		SiteId: siteID,
 	}
 
+	logger.Info("Creating VPC Peering",
+		"vpcID", vpcID, "peerVPCID", peeringSpec.PeerVPCID, "siteID", siteID)
+	peering, httpResp, err := clusterScope.NcxInfraClient.CreateVpcPeering(ctx, clusterScope.OrgName, peeringReq)
+	if err != nil {
+		return fmt.Errorf("failed to create VPC peering with %s: %w", peeringSpec.PeerVPCID,
+			scope.WithPermissionError(httpResp, err, "CreateVpcPeering"))
+	}
+
+	if httpResp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to create VPC peering with %s, status %d", peeringSpec.PeerVPCID, httpResp.StatusCode)
+	}
+
+	if peering == nil || peering.Id == nil {
+		return fmt.Errorf("VPC peering ID missing in response for peer %s", peeringSpec.PeerVPCID)
+	}
+
+	clusterScope.SetVPCPeeringID(peeringSpec.PeerVPCID, *peering.Id)
+	clusterScope.SetResourceOrigin(*peering.Id, "VPCPeering", peeringSpec.PeerVPCID, peering.Created)
+	logger.Info("Successfully created VPC Peering",
+		"peerVPCID", peeringSpec.PeerVPCID, "peeringID", *peering.Id)
+	r.recordEvent(clusterScope.NcxInfraCluster, "VPCPeeringCreated",
+		"Successfully created VPC Peering %s with peer %s", *peering.Id, peeringSpec.PeerVPCID)
 	return nil
 }
 
@@ -719,69 +1516,52 @@ func (r *NcxInfraClusterReconciler) reconcileNSG(
 ) error {
 	logger := log.FromContext(ctx)
 
+	// A referenced NSG is managed by its own controller
+	if ref := clusterScope.NcxInfraCluster.Spec.VPC.NetworkSecurityGroupRef; ref != nil {
+		return r.reconcileNSGRef(ctx, clusterScope, ref.Name)
+	}
+
 	nsgSpec := clusterScope.NcxInfraCluster.Spec.VPC.NetworkSecurityGroup
 
 	// Check if NSG already exists
 	if clusterScope.NSGID() != "" {
 		// Verify NSG still exists in NVIDIA Carbide
-		nsg, _, err := clusterScope.NcxInfraClient.GetNetworkSecurityGroup(
+		nsg, httpResp, err := clusterScope.NcxInfraClient.GetNetworkSecurityGroup(
 			ctx, clusterScope.OrgName, clusterScope.NSGID())
-		if err != nil || nsg == nil {
-			logger.Error(err, "NSG not found in NVIDIA Carbide, will recreate", "nsgID", clusterScope.NSGID())
+		exists, err := lookupExisting(httpResp, err, nsg != nil, "GetNetworkSecurityGroup", "NSG", clusterScope.NSGID())
+		if err != nil {
+			return err
+		}
+		if !exists {
+			logger.Info("NSG not found in NVIDIA Carbide, will recreate", "nsgID", clusterScope.NSGID())
 			clusterScope.SetNSGID("")
 		} else {
 			logger.V(1).Info("NSG already exists", "nsgID", clusterScope.NSGID())
+			// Clear the last error
+			clusterScope.SetNSGID(clusterScope.NSGID())
 			return nil
 		}
 	}
 
-	// Convert NSG rules from CRD types to API types
-	rules := make([]nico.NetworkSecurityGroupRule, 0, len(nsgSpec.Rules))
-	for _, rule := range nsgSpec.Rules {
-		// API requires both source and destination prefixes
-		// Use "0.0.0.0/0" as default (any) if not specified
-		sourcePrefix := rule.SourceCIDR
-		if sourcePrefix == "" {
-			sourcePrefix = "0.0.0.0/0"
-		}
-		destPrefix := "0.0.0.0/0" // Default to any destination
-
-		ruleName := rule.Name
-		nsgRule := nico.NetworkSecurityGroupRule{
-			Name:              *nico.NewNullableString(&ruleName),
-			Direction:         strings.ToLower(rule.Direction),
-			Protocol:          strings.ToLower(rule.Protocol),
-			Action:            strings.ToLower(rule.Action),
-			SourcePrefix:      sourcePrefix,
-			DestinationPrefix: destPrefix,
-		}
-
-		// Map port range to destination port range
-		if rule.PortRange != "" {
-			portRange := rule.PortRange
-			nsgRule.DestinationPortRange = *nico.NewNullableString(&portRange)
-		}
-
-		rules = append(rules, nsgRule)
-	}
-
 	// Create NSG
 	nsgReq := nico.NetworkSecurityGroupCreateRequest{
 		Name:   nsgSpec.Name,
 		SiteId: siteID,
 	}
-	if len(rules) > 0 {
+	if rules := convert.NSGRules(nsgSpec.Rules); len(rules) > 0 {
 		nsgReq.Rules = rules
 	}
 
 	logger.Info("Creating NSG", "name", nsgSpec.Name, "siteID", siteID)
 	nsg, httpResp, err := clusterScope.NcxInfraClient.CreateNetworkSecurityGroup(ctx, clusterScope.OrgName, nsgReq)
 	if err != nil {
-		return fmt.Errorf("failed to create NSG: %w", err)
+		return ncxinfraerrors.Classify(ncxinfraerrors.NSGCreateFailed,
+			fmt.Errorf("failed to create NSG: %w", scope.WithPermissionError(httpResp, err, "CreateNetworkSecurityGroup")))
 	}
 
 	if httpResp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("failed to create NSG, status %d", httpResp.StatusCode)
+		return ncxinfraerrors.Transient(ncxinfraerrors.NSGCreateFailed,
+			fmt.Errorf("failed to create NSG, status %d", httpResp.StatusCode))
 	}
 
 	if nsg == nil || nsg.Id == nil {
@@ -789,6 +1569,8 @@ func (r *NcxInfraClusterReconciler) reconcileNSG(
 	}
 
 	clusterScope.SetNSGID(*nsg.Id)
+	clusterScope.SetNSGAppliedRuleHash(convert.NSGRulesHash(nsgSpec.Rules))
+	clusterScope.SetResourceOrigin(*nsg.Id, "NetworkSecurityGroup", nsgSpec.Name, nsg.Created)
 	logger.Info("Successfully created NSG", "nsgID", *nsg.Id)
 	r.recordEvent(clusterScope.NcxInfraCluster, "NSGCreated",
 		"Successfully created NSG %s", *nsg.Id)
@@ -796,105 +1578,269 @@ func (r *NcxInfraClusterReconciler) reconcileNSG(
 	return nil
 }
 
+// reconcileNSGRef uses the Network Security Group of the referenced
+// NcxInfraNetworkSecurityGroup once it is ready.
+//
 //nolint:unparam // ctrl.Result is part of the reconciler interface contract
+func (r *NcxInfraClusterReconciler) reconcileNSGRef(
+	ctx context.Context, clusterScope *scope.ClusterScope, name string,
+) error {
+	nsg := &infrastructurev1.NcxInfraNetworkSecurityGroup{}
+	key := client.ObjectKey{Namespace: clusterScope.NcxInfraCluster.Namespace, Name: name}
+	if err := r.Get(ctx, key, nsg); err != nil {
+		return fmt.Errorf("failed to get NcxInfraNetworkSecurityGroup %s: %w", name, err)
+	}
+	if !nsg.Status.Ready || nsg.Status.NSGID == "" {
+		return fmt.Errorf("NcxInfraNetworkSecurityGroup %s is not ready", name)
+	}
+
+	if clusterScope.NSGID() != nsg.Status.NSGID {
+		log.FromContext(ctx).Info("Using referenced NSG", "name", name, "nsgID", nsg.Status.NSGID)
+	}
+	clusterScope.SetNSGID(nsg.Status.NSGID)
+	clusterScope.SetNSGAppliedRuleHash(convert.NSGRulesHash(nsg.Spec.Rules))
+	return nil
+}
+
 func (r *NcxInfraClusterReconciler) reconcileDelete(
 	ctx context.Context, clusterScope *scope.ClusterScope,
 ) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Deleting NcxInfraCluster")
 
-	// Delete NSG if it exists
-	if clusterScope.NSGID() != "" {
-		logger.Info("Deleting NSG", "nsgID", clusterScope.NSGID())
-		if err := r.deleteResource(ctx, clusterScope, "NSG", clusterScope.NSGID(),
-			clusterScope.NcxInfraClient.DeleteNetworkSecurityGroup); err != nil {
+	// Keep the network resources until the instances using them are deleted
+	if requeueAfter, err := r.waitForMachines(ctx, clusterScope); err != nil || requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+
+	if result, err := r.deleteClusterResources(ctx, clusterScope); err != nil || !result.IsZero() {
+		return result, err
+	}
+
+	// Let the credentials secret go once no cluster needs it
+	if ref := clusterScope.NcxInfraCluster.Status.CredentialsSecretRef; ref != nil {
+		if err := r.releaseCredentialsSecret(ctx, clusterScope.NcxInfraCluster, *ref); err != nil {
 			return ctrl.Result{}, err
 		}
+	}
+
+	// The resource export outlives a disabled gate, delete it regardless
+	if err := r.deleteResourceExport(ctx, clusterScope.NcxInfraCluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Remove finalizer
+	controllerutil.RemoveFinalizer(clusterScope.NcxInfraCluster, NcxInfraClusterFinalizer)
+
+	logger.Info("Successfully deleted NcxInfraCluster")
+	return ctrl.Result{}, nil
+}
+
+// deleteNSG deletes the NSG of the cluster, leaving a referenced NSG to its owner.
+func (r *NcxInfraClusterReconciler) deleteNSG(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	if clusterScope.NcxInfraCluster.Spec.VPC.NetworkSecurityGroupRef != nil {
 		clusterScope.SetNSGID("")
 	}
+	if clusterScope.NSGID() == "" {
+		return nil
+	}
+	log.FromContext(ctx).Info("Deleting NSG", "nsgID", clusterScope.NSGID())
+	if err := r.deleteResource(ctx, clusterScope, "NSG", clusterScope.NSGID(),
+		clusterScope.NcxInfraClient.DeleteNetworkSecurityGroup, "DeleteNetworkSecurityGroup"); err != nil {
+		clusterScope.SetNSGError(err)
+		return err
+	}
+	clusterScope.SetNSGID("")
+	return nil
+}
 
-	// Delete VPC Peerings
-	for peerVPCID, peeringID := range clusterScope.VPCPeeringIDs() {
-		logger.Info("Deleting VPC Peering", "peerVpcId", peerVPCID, "peeringID", peeringID)
-		if err := r.deleteResource(ctx, clusterScope, "VPC peering", peeringID,
-			clusterScope.NcxInfraClient.DeleteVpcPeering); err != nil {
-			return ctrl.Result{}, err
+// deleteVPCPeerings deletes the VPC peerings of the cluster.
+func (r *NcxInfraClusterReconciler) deleteVPCPeerings(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	var errs []error
+	peeringIDs := clusterScope.VPCPeeringIDs()
+	for _, peerVPCID := range sortedKeys(peeringIDs) {
+		log.FromContext(ctx).Info("Deleting VPC Peering", "peerVPCID", peerVPCID, "peeringID", peeringIDs[peerVPCID])
+		if err := r.deleteResource(ctx, clusterScope, "VPC peering", peeringIDs[peerVPCID],
+			clusterScope.NcxInfraClient.DeleteVpcPeering, "DeleteVpcPeering"); err != nil {
+			clusterScope.SetVPCPeeringError(peerVPCID, err)
+			errs = append(errs, err)
+			continue
 		}
-		delete(clusterScope.VPCPeeringIDs(), peerVPCID)
+		clusterScope.SetVPCPeeringID(peerVPCID, "")
 	}
+	return errors.Join(errs...)
+}
 
-	// Delete VPC Prefixes
-	for prefixName, prefixID := range clusterScope.VPCPrefixIDs() {
-		logger.Info("Deleting VPC Prefix", "prefixName", prefixName, "prefixID", prefixID)
-		if err := r.deleteResource(ctx, clusterScope, "VPC prefix", prefixID,
-			clusterScope.NcxInfraClient.DeleteVpcPrefix); err != nil {
-			return ctrl.Result{}, err
+// deleteVPCPrefixes deletes the VPC prefixes of the cluster.
+func (r *NcxInfraClusterReconciler) deleteVPCPrefixes(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	var errs []error
+	vpcPrefixIDs := clusterScope.VPCPrefixIDs()
+	for _, prefixName := range sortedKeys(vpcPrefixIDs) {
+		log.FromContext(ctx).Info("Deleting VPC Prefix", "prefixName", prefixName, "prefixID", vpcPrefixIDs[prefixName])
+		if err := r.deleteResource(ctx, clusterScope, "VPC prefix", vpcPrefixIDs[prefixName],
+			clusterScope.NcxInfraClient.DeleteVpcPrefix, "DeleteVpcPrefix"); err != nil {
+			clusterScope.SetVPCPrefixError(prefixName, err)
+			errs = append(errs, err)
+			continue
 		}
-		delete(clusterScope.VPCPrefixIDs(), prefixName)
+		clusterScope.SetVPCPrefixID(prefixName, "")
 	}
+	return errors.Join(errs...)
+}
 
-	// Delete Subnets
-	for subnetName, subnetID := range clusterScope.SubnetIDs() {
-		logger.Info("Deleting subnet", "subnetName", subnetName, "subnetID", subnetID)
-		if err := r.deleteResource(ctx, clusterScope, "subnet", subnetID,
-			clusterScope.NcxInfraClient.DeleteSubnet); err != nil {
-			return ctrl.Result{}, err
+// deleteSubnets deletes the subnets of the cluster.
+func (r *NcxInfraClusterReconciler) deleteSubnets(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	var errs []error
+	subnetIDs := clusterScope.SubnetIDs()
+	for _, subnetName := range sortedKeys(subnetIDs) {
+		log.FromContext(ctx).Info("Deleting subnet", "subnetName", subnetName, "subnetID", subnetIDs[subnetName])
+		if err := r.deleteResource(ctx, clusterScope, "subnet", subnetIDs[subnetName],
+			clusterScope.NcxInfraClient.DeleteSubnet, "DeleteSubnet"); err != nil {
+			clusterScope.SetSubnetError(subnetName, err)
+			errs = append(errs, err)
+			continue
 		}
-		delete(clusterScope.SubnetIDs(), subnetName)
+		clusterScope.SetSubnetID(subnetName, "")
 	}
+	return errors.Join(errs...)
+}
 
-	// Delete Allocation if it exists
-	if clusterScope.AllocationID() != "" {
-		logger.Info("Deleting allocation", "allocationID", clusterScope.AllocationID())
-		if err := r.deleteResource(ctx, clusterScope, "allocation", clusterScope.AllocationID(),
-			clusterScope.NcxInfraClient.DeleteAllocation); err != nil {
-			return ctrl.Result{}, err
+// deleteIPBlocks deletes the IP blocks of the subnets with Public egress or
+// placed in another site, then the ones the other subnets are allocated from.
+func (r *NcxInfraClusterReconciler) deleteIPBlocks(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	var errs []error
+	for _, ipBlock := range ipBlocksInDeletionOrder(clusterScope.IPBlocks()) {
+		if err := r.deleteIPBlock(ctx, clusterScope, ipBlock); err != nil {
+			errs = append(errs, err)
 		}
-		clusterScope.SetAllocationID("")
 	}
+	return errors.Join(errs...)
+}
 
-	// Delete child IP block if it exists
-	if clusterScope.ChildIPBlockID() != "" {
-		logger.Info("Deleting child IP block", "childIPBlockID", clusterScope.ChildIPBlockID())
-		if err := r.deleteResource(ctx, clusterScope, "child IP block", clusterScope.ChildIPBlockID(),
-			clusterScope.NcxInfraClient.DeleteIpblock); err != nil {
-			return ctrl.Result{}, err
-		}
-		clusterScope.SetChildIPBlockID("")
+// ipBlocksInDeletionOrder returns the IP blocks of the subnets with Public
+// egress or placed in another site and the child IP blocks allocated for the
+// CIDRs first, then the IP blocks of the supernets they are allocated from.
+func ipBlocksInDeletionOrder(ipBlocks []infrastructurev1.IPBlockStatus) []infrastructurev1.IPBlockStatus {
+	ipBlocks = slices.Clone(ipBlocks)
+	isSupernet := func(ipBlock infrastructurev1.IPBlockStatus) bool {
+		return ipBlock.Subnet == "" && ipBlock.IPBlockID != ""
 	}
+	sort.SliceStable(ipBlocks, func(i, j int) bool { return !isSupernet(ipBlocks[i]) && isSupernet(ipBlocks[j]) })
+	return ipBlocks
+}
 
-	// Delete parent IP block if it exists
-	if clusterScope.IPBlockID() != "" {
-		logger.Info("Deleting parent IP block", "ipBlockID", clusterScope.IPBlockID())
-		if err := r.deleteResource(ctx, clusterScope, "parent IP block", clusterScope.IPBlockID(),
-			clusterScope.NcxInfraClient.DeleteIpblock); err != nil {
-			return ctrl.Result{}, err
+// deleteVPCs deletes the VPCs of the cluster in the sites of its subnets
+// placed in another site, then its VPC. A shared VPC is left to the cluster
+// that created it, which waits for the clusters sharing it to be deleted.
+func (r *NcxInfraClusterReconciler) deleteVPCs(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	logger := log.FromContext(ctx)
+
+	var errs []error
+	siteVPCIDs := clusterScope.SiteVPCIDs()
+	for _, siteID := range sortedKeys(siteVPCIDs) {
+		logger.Info("Deleting site VPC", "siteID", siteID, "vpcID", siteVPCIDs[siteID])
+		if err := r.deleteResource(ctx, clusterScope, "VPC", siteVPCIDs[siteID],
+			clusterScope.NcxInfraClient.DeleteVpc, "DeleteVpc"); err != nil {
+			clusterScope.SetSiteVPCError(siteID, err)
+			errs = append(errs, err)
+			continue
 		}
-		clusterScope.SetIPBlockID("")
+		clusterScope.SetSiteVPCID(siteID, "")
 	}
 
-	// Delete VPC
-	if clusterScope.VPCID() != "" {
-		logger.Info("Deleting VPC", "vpcID", clusterScope.VPCID())
-		if err := r.deleteResource(ctx, clusterScope, "VPC", clusterScope.VPCID(),
-			clusterScope.NcxInfraClient.DeleteVpc); err != nil {
-			return ctrl.Result{}, err
-		}
+	if clusterScope.NcxInfraCluster.Spec.VPC.ID != "" {
 		clusterScope.SetVPCID("")
 	}
+	if clusterScope.VPCID() == "" {
+		return errors.Join(errs...)
+	}
+	sharing, err := r.clustersSharingVPC(ctx, clusterScope.VPCID())
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if len(sharing) > 0 {
+		r.recordEvent(clusterScope.NcxInfraCluster, "VPCInUse",
+			"VPC %s is still used by %s", clusterScope.VPCID(), strings.Join(sharing, ", "))
+		return errors.Join(append(errs, fmt.Errorf("VPC %s is %w by %s",
+			clusterScope.VPCID(), errResourceInUse, strings.Join(sharing, ", ")))...)
+	}
 
-	// Remove finalizer
-	controllerutil.RemoveFinalizer(clusterScope.NcxInfraCluster, NcxInfraClusterFinalizer)
+	logger.Info("Deleting VPC", "vpcID", clusterScope.VPCID())
+	if err := r.deleteResource(ctx, clusterScope, "VPC", clusterScope.VPCID(),
+		clusterScope.NcxInfraClient.DeleteVpc, "DeleteVpc"); err != nil {
+		clusterScope.SetVPCError(err)
+		return errors.Join(append(errs, err)...)
+	}
+	clusterScope.SetVPCID("")
+	return errors.Join(errs...)
+}
 
-	logger.Info("Successfully deleted NcxInfraCluster")
-	return ctrl.Result{}, nil
+// clustersSharingVPC returns the namespace/name of the NcxInfraClusters, in
+// all namespaces, that use a VPC as a shared VPC.
+func (r *NcxInfraClusterReconciler) clustersSharingVPC(ctx context.Context, vpcID string) ([]string, error) {
+	clusters := &infrastructurev1.NcxInfraClusterList{}
+	if err := r.List(ctx, clusters, client.MatchingFields{SharedVPCField: vpcID}); err != nil {
+		return nil, fmt.Errorf("failed to list the clusters sharing VPC %s: %w", vpcID, err)
+	}
+	sharing := make([]string, 0, len(clusters.Items))
+	for i := range clusters.Items {
+		sharing = append(sharing, client.ObjectKeyFromObject(&clusters.Items[i]).String())
+	}
+	sort.Strings(sharing)
+	return sharing, nil
+}
+
+// deleteIPBlock deletes the allocation and IP blocks of a subnet with Public
+// egress or placed in another site, or of a supernet the other subnets are
+// allocated from. An adopted parent IP block is left to its owner.
+func (r *NcxInfraClusterReconciler) deleteIPBlock(
+	ctx context.Context, clusterScope *scope.ClusterScope, ipBlock infrastructurev1.IPBlockStatus,
+) (err error) {
+	logger := log.FromContext(ctx).WithValues("cidr", ipBlock.CIDR)
+	subnetName := ipBlock.Subnet
+	defer func() { clusterScope.SetIPBlock(ipBlock, err) }()
+
+	if ipBlock.AllocationID != "" {
+		logger.Info("Deleting allocation", "subnetName", subnetName, "allocationID", ipBlock.AllocationID)
+		if err := r.deleteResource(ctx, clusterScope, "allocation", ipBlock.AllocationID,
+			clusterScope.NcxInfraClient.DeleteAllocation, "DeleteAllocation"); err != nil {
+			return err
+		}
+		ipBlock.AllocationID = ""
+	}
+
+	if ipBlock.ChildIPBlockID != "" {
+		logger.Info("Deleting child IP block", "subnetName", subnetName, "childIPBlockID", ipBlock.ChildIPBlockID)
+		if err := r.deleteResource(ctx, clusterScope, "child IP block", ipBlock.ChildIPBlockID,
+			clusterScope.NcxInfraClient.DeleteIpblock, "DeleteIpblock"); err != nil {
+			return err
+		}
+		ipBlock.ChildIPBlockID = ""
+	}
+
+	if ipBlock.IPBlockID != "" && ipBlock.Adopted {
+		logger.Info("Keeping adopted parent IP block", "subnetName", subnetName, "ipBlockID", ipBlock.IPBlockID)
+		ipBlock.IPBlockID = ""
+	}
+	if ipBlock.IPBlockID != "" {
+		logger.Info("Deleting parent IP block", "subnetName", subnetName, "ipBlockID", ipBlock.IPBlockID)
+		if err := r.deleteResource(ctx, clusterScope, "parent IP block", ipBlock.IPBlockID,
+			clusterScope.NcxInfraClient.DeleteIpblock, "DeleteIpblock"); err != nil {
+			return err
+		}
+		ipBlock.IPBlockID = ""
+	}
+	return nil
 }
 
-// deleteResource calls a delete API method and handles 404 (already deleted) gracefully.
+// deleteResource calls a delete API method and handles 404 (already deleted)
+// gracefully. A 409 Conflict, of a resource other resources still use, is
+// reported as errResourceInUse with a ResourceInUse event.
 func (r *NcxInfraClusterReconciler) deleteResource(
 	ctx context.Context, clusterScope *scope.ClusterScope,
 	resourceType, resourceID string,
 	deleteFn func(ctx context.Context, org string, id string) (*http.Response, error),
+	method string,
 ) error {
 	logger := log.FromContext(ctx)
 
@@ -904,7 +1850,13 @@ func (r *NcxInfraClusterReconciler) deleteResource(
 			logger.Info("Resource already deleted", "type", resourceType, "id", resourceID)
 			return nil
 		}
-		return fmt.Errorf("failed to delete %s %s: %w", resourceType, resourceID, err)
+		if httpResp != nil && httpResp.StatusCode == http.StatusConflict {
+			r.recordEvent(clusterScope.NcxInfraCluster, "ResourceInUse", "%s %s is still in use: %v",
+				resourceType, resourceID, err)
+			return fmt.Errorf("failed to delete %s %s: %w: %w", resourceType, resourceID, errResourceInUse, err)
+		}
+		return fmt.Errorf("failed to delete %s %s: %w", resourceType, resourceID,
+			scope.WithPermissionError(httpResp, err, method))
 	}
 	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusNoContent &&
 		httpResp.StatusCode != http.StatusNotFound {
@@ -913,6 +1865,313 @@ func (r *NcxInfraClusterReconciler) deleteResource(
 	return nil
 }
 
+// patchResult folds the error of the patch persisting a reconciliation into
+// its result, so that the changes are not lost until the next resync. A
+// conflict requeues the object, whose next reconciliation recomputes the
+// status from its latest version, and the other errors are returned to be
+// retried with backoff.
+func patchResult(res ctrl.Result, reterr, patchErr error) (ctrl.Result, error) {
+	if reterr == nil && isConflict(patchErr) {
+		return ctrl.Result{Requeue: true}, nil
+	}
+	return res, kerrors.NewAggregate([]error{reterr, patchErr})
+}
+
+// isConflict reports whether err holds an optimistic-lock conflict, looking
+// into the aggregates returned by the patch helper.
+func isConflict(err error) bool {
+	var agg kerrors.Aggregate
+	if errors.As(err, &agg) {
+		return slices.ContainsFunc(agg.Errors(), isConflict)
+	}
+	return apierrors.IsConflict(err)
+}
+
+// handlePermissionError reflects the outcome of a reconciliation in the
+// AuthenticationValid and InsufficientPermissions conditions. A call rejected
+// for missing permissions names the call and the required role. Both are
+// retried at a slow pace instead of with the error backoff, as they only
+// succeed once the credentials are fixed.
+func handlePermissionError(
+	ctx context.Context, obj conditions.Setter, orgName string, result ctrl.Result, err error,
+) (ctrl.Result, error) {
+	if failure := scope.AuthenticationFailure(err); failure != "" {
+		log.FromContext(ctx).Info("NVIDIA Carbide credentials rejected", "reason", failure)
+		conditions.Set(obj, metav1.Condition{
+			Type:    string(AuthenticationValidCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "CredentialsRejected",
+			Message: fmt.Sprintf("%s, update the credentials secret of org %s", failure, orgName),
+		})
+		return ctrl.Result{RequeueAfter: permissionsRetryInterval}, nil
+	}
+	if err == nil && conditions.IsFalse(obj, string(AuthenticationValidCondition)) {
+		conditions.Set(obj, metav1.Condition{
+			Type:   string(AuthenticationValidCondition),
+			Status: metav1.ConditionTrue,
+			Reason: "CredentialsAccepted",
+		})
+	}
+
+	var apiErr *scope.APIError
+	if errors.As(err, &apiErr) && apiErr.IsForbidden() {
+		log.FromContext(ctx).Info("NVIDIA Carbide API call rejected for missing permissions",
+			"method", apiErr.Method, "requiredRole", apiErr.RequiredRole)
+		conditions.Set(obj, metav1.Condition{
+			Type:   string(InsufficientPermissionsCondition),
+			Status: metav1.ConditionTrue,
+			Reason: "Forbidden",
+			Message: fmt.Sprintf("%s was denied: the credentials need the %s role in org %s",
+				apiErr.Method, apiErr.RequiredRole, orgName),
+		})
+		return ctrl.Result{RequeueAfter: permissionsRetryInterval}, nil
+	}
+	if err == nil && conditions.IsTrue(obj, string(InsufficientPermissionsCondition)) {
+		conditions.Set(obj, metav1.Condition{
+			Type:   string(InsufficientPermissionsCondition),
+			Status: metav1.ConditionFalse,
+			Reason: "PermissionsGranted",
+		})
+	}
+	return result, err
+}
+
+// conditionList is a bare list of conditions, sorted by sortConditions.
+type conditionList []metav1.Condition
+
+func (l *conditionList) GetConditions() []metav1.Condition { return *l }
+
+func (l *conditionList) SetConditions(c []metav1.Condition) { *l = c }
+
+// sortConditions sorts the conditions of an object in the Cluster API order,
+// Ready first and the others by type, so that repeated reconciles write the
+// same status. conditions.Set only sorts the conditions when it changes one,
+// which leaves the conditions recorded by earlier releases in their order.
+func sortConditions(obj conditions.Setter) {
+	if len(obj.GetConditions()) == 0 {
+		return
+	}
+	sorted := &conditionList{}
+	for _, condition := range obj.GetConditions() {
+		conditions.Set(sorted, condition)
+	}
+	obj.SetConditions(*sorted)
+}
+
+// updateInstancesProvisioned summarizes the failed machines of the cluster in
+// the InstancesProvisioned condition.
+func (r *NcxInfraClusterReconciler) updateInstancesProvisioned(
+	ctx context.Context, cluster *clusterv1.Cluster, nvidiaCarbideCluster *infrastructurev1.NcxInfraCluster,
+) {
+	logger := log.FromContext(ctx)
+
+	machineList := &infrastructurev1.NcxInfraMachineList{}
+	if err := r.List(ctx, machineList,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingFields{ClusterNameField: cluster.Name},
+	); err != nil {
+		logger.Error(err, "failed to list NcxInfraMachines")
+		return
+	}
+
+	var failures []string
+	for i := range machineList.Items {
+		machine := &machineList.Items[i]
+		if machine.Status.FailureMessage == nil {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s", machine.Name, *machine.Status.FailureMessage))
+	}
+	sort.Strings(failures)
+
+	if len(failures) == 0 {
+		conditions.Set(nvidiaCarbideCluster, metav1.Condition{
+			Type:    string(InstancesProvisionedCondition),
+			Status:  metav1.ConditionTrue,
+			Reason:  "NoProvisioningFailures",
+			Message: fmt.Sprintf("%d machine(s), none failed", len(machineList.Items)),
+		})
+		return
+	}
+
+	message := fmt.Sprintf("%d of %d machine(s) failed: %s", len(failures), len(machineList.Items),
+		strings.Join(failures[:min(len(failures), maxFailedMachinesInMessage)], "; "))
+	if len(failures) > maxFailedMachinesInMessage {
+		message += fmt.Sprintf("; and %d more", len(failures)-maxFailedMachinesInMessage)
+	}
+	conditions.Set(nvidiaCarbideCluster, metav1.Condition{
+		Type:    string(InstancesProvisionedCondition),
+		Status:  metav1.ConditionFalse,
+		Reason:  "ProvisioningFailed",
+		Message: message,
+	})
+}
+
+// mirrorConditions copies the key provider conditions into the owner Cluster
+// status, prefixed so they cannot collide with the conditions owned by CAPI.
+func (r *NcxInfraClusterReconciler) mirrorConditions(
+	ctx context.Context, cluster *clusterv1.Cluster, nvidiaCarbideCluster *infrastructurev1.NcxInfraCluster,
+) error {
+	patchHelper, err := patch.NewHelper(cluster, r.Client)
+	if err != nil {
+		return err
+	}
+
+	var owned []string
+	for _, conditionType := range mirroredConditions {
+		source := conditions.Get(nvidiaCarbideCluster, string(conditionType))
+		if source == nil {
+			continue
+		}
+		mirrored := mirroredConditionPrefix + string(conditionType)
+		conditions.Set(cluster, metav1.Condition{
+			Type:    mirrored,
+			Status:  source.Status,
+			Reason:  source.Reason,
+			Message: source.Message,
+		})
+		owned = append(owned, mirrored)
+	}
+	if len(owned) == 0 {
+		return nil
+	}
+	return patchHelper.Patch(ctx, cluster, patch.WithOwnedConditions{Conditions: owned})
+}
+
+// ncxInfraMachineToNcxInfraCluster enqueues the NcxInfraCluster of a machine
+// so its InstancesProvisioned condition follows the machine failures, and its
+// deletion resumes as soon as its last machine is gone.
+func (r *NcxInfraClusterReconciler) ncxInfraMachineToNcxInfraCluster(
+	ctx context.Context, obj client.Object,
+) []ctrl.Request {
+	clusterName, ok := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if !ok {
+		return nil
+	}
+	cluster := &clusterv1.Cluster{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: clusterName}, cluster); err != nil {
+		return nil
+	}
+	ref := cluster.Spec.InfrastructureRef
+	if ref.Kind != "NcxInfraCluster" || ref.Name == "" {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}}}
+}
+
+// machineSummaryChanged filters the NcxInfraMachine updates that can change the
+// InstancesProvisioned summary or the resource export, letting the creations
+// and deletions through.
+var machineSummaryChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldMachine, okOld := e.ObjectOld.(*infrastructurev1.NcxInfraMachine)
+		newMachine, okNew := e.ObjectNew.(*infrastructurev1.NcxInfraMachine)
+		if !okOld || !okNew {
+			return false
+		}
+		return !ptr.Equal(oldMachine.Status.FailureMessage, newMachine.Status.FailureMessage) ||
+			oldMachine.Status.InstanceID != newMachine.Status.InstanceID ||
+			oldMachine.Status.MachineID != newMachine.Status.MachineID
+	},
+}
+
+// siteHealthChanged filters the NcxInfraSite events that can change the
+// SiteHealthy condition.
+var siteHealthChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldSite, okOld := e.ObjectOld.(*infrastructurev1.NcxInfraSite)
+		newSite, okNew := e.ObjectNew.(*infrastructurev1.NcxInfraSite)
+		if !okOld || !okNew {
+			return false
+		}
+		return oldSite.Status.Healthy != newSite.Status.Healthy || oldSite.Status.Online != newSite.Status.Online
+	},
+}
+
+// ncxInfraSiteToNcxInfraClusters maps a NcxInfraSite to the NcxInfraClusters
+// of the site, in all namespaces.
+func (r *NcxInfraClusterReconciler) ncxInfraSiteToNcxInfraClusters(
+	ctx context.Context, obj client.Object,
+) []ctrl.Request {
+	site, ok := obj.(*infrastructurev1.NcxInfraSite)
+	if !ok {
+		return nil
+	}
+	clusters := &infrastructurev1.NcxInfraClusterList{}
+	if err := r.List(ctx, clusters); err != nil {
+		return nil
+	}
+	var requests []ctrl.Request
+	for i := range clusters.Items {
+		ref := clusters.Items[i].Spec.SiteRef
+		if ref.ID == site.Spec.ID || (ref.ID == "" && ref.Name != "" && ref.Name == site.Spec.Name) {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&clusters.Items[i])})
+		}
+	}
+	return requests
+}
+
+// ncxInfraNetworkSecurityGroupToNcxInfraClusters maps a NcxInfraNetworkSecurityGroup
+// to the NcxInfraClusters referencing it, so they pick up the NSG once it is ready.
+func (r *NcxInfraClusterReconciler) ncxInfraNetworkSecurityGroupToNcxInfraClusters(
+	ctx context.Context, obj client.Object,
+) []ctrl.Request {
+	clusters := &infrastructurev1.NcxInfraClusterList{}
+	if err := r.List(ctx, clusters,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{NetworkSecurityGroupRefField: obj.GetName()},
+	); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list NcxInfraClusters")
+		return nil
+	}
+	return clusterRequests(clusters)
+}
+
+// secretToNcxInfraClusters maps a credentials secret to the NcxInfraClusters
+// referencing it, so that fixed or rotated credentials are used right away.
+func (r *NcxInfraClusterReconciler) secretToNcxInfraClusters(ctx context.Context, obj client.Object) []ctrl.Request {
+	clusters := &infrastructurev1.NcxInfraClusterList{}
+	if err := r.List(ctx, clusters,
+		client.MatchingFields{CredentialsSecretField: client.ObjectKeyFromObject(obj).String()},
+	); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list NcxInfraClusters")
+		return nil
+	}
+	return clusterRequests(clusters)
+}
+
+// clusterRequests returns the reconcile requests of the listed NcxInfraClusters.
+func clusterRequests(clusters *infrastructurev1.NcxInfraClusterList) []ctrl.Request {
+	requests := make([]ctrl.Request, 0, len(clusters.Items))
+	for _, c := range clusters.Items {
+		requests = append(requests, ctrl.Request{
+			NamespacedName: client.ObjectKey{Namespace: c.Namespace, Name: c.Name},
+		})
+	}
+	return requests
+}
+
+// blockTargetChange reports that the credentials of the cluster point to
+// another org or endpoint than its resources. Neither the resources nor the
+// status are touched, whether the cluster is deleted or not.
+func (r *NcxInfraClusterReconciler) blockTargetChange(
+	ctx context.Context, clusterScope *scope.ClusterScope, change string,
+) ctrl.Result {
+	log.FromContext(ctx).Info("Credentials target changed, not reconciling", "change", change)
+	cluster := clusterScope.NcxInfraCluster
+	if !conditions.IsFalse(cluster, string(CredentialsTargetCondition)) && r.Recorder != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "CredentialsTargetChanged",
+			"Not reconciling: %s. Restore the credentials secret to resume", change)
+	}
+	conditions.Set(cluster, metav1.Condition{
+		Type:    string(CredentialsTargetCondition),
+		Status:  metav1.ConditionFalse,
+		Reason:  "CredentialsTargetChanged",
+		Message: change,
+	})
+	return ctrl.Result{RequeueAfter: permissionsRetryInterval}
+}
+
 // recordEvent records a Normal event on the given object if a Recorder is set.
 func (r *NcxInfraClusterReconciler) recordEvent(obj runtime.Object, reason, messageFmt string, args ...interface{}) {
 	if r.Recorder != nil {
@@ -935,8 +2194,27 @@ func (r *NcxInfraClusterReconciler) SetupWithManager(ctx context.Context, mgr ct
 				),
 			),
 		).
+		Watches(
+			&infrastructurev1.NcxInfraMachine{},
+			handler.EnqueueRequestsFromMapFunc(r.ncxInfraMachineToNcxInfraCluster),
+			builder.WithPredicates(machineSummaryChanged),
+		).
+		Watches(
+			&infrastructurev1.NcxInfraNetworkSecurityGroup{},
+			handler.EnqueueRequestsFromMapFunc(r.ncxInfraNetworkSecurityGroupToNcxInfraClusters),
+		).
+		Watches(
+			&infrastructurev1.NcxInfraSite{},
+			handler.EnqueueRequestsFromMapFunc(r.ncxInfraSiteToNcxInfraClusters),
+			builder.WithPredicates(siteHealthChanged),
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.secretToNcxInfraClusters),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(
-			mgr.GetScheme(), ctrl.Log.WithName("ncxinfracluster"), "")).
+			mgr.GetScheme(), ctrl.Log.WithName("ncxinfracluster"), r.WatchFilterValue)).
 		Named("ncxinfracluster").
 		Complete(r)
 }
@@ -20,16 +20,26 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	bootstrapv1 "sigs.k8s.io/cluster-api/api/bootstrap/kubeadm/v1beta2"
+	controlplanev1 "sigs.k8s.io/cluster-api/api/controlplane/kubeadm/v1beta2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	capierrors "sigs.k8s.io/cluster-api/errors" //nolint:staticcheck // required for CAPI contract FailureReason types
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
@@ -43,9 +53,20 @@ func newTestScheme() *runtime.Scheme {
 	_ = corev1.AddToScheme(scheme)
 	_ = infrastructurev1.AddToScheme(scheme)
 	_ = clusterv1.AddToScheme(scheme)
+	_ = bootstrapv1.AddToScheme(scheme)
+	_ = controlplanev1.AddToScheme(scheme)
 	return scheme
 }
 
+// newFakeClientBuilder returns a fake client builder with the field indexes of the controllers.
+func newFakeClientBuilder(scheme *runtime.Scheme) *fake.ClientBuilder {
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, index := range fieldIndexes {
+		builder = builder.WithIndex(index.obj, index.field, index.extract)
+	}
+	return builder
+}
+
 var _ = Describe("NcxInfraCluster Controller", func() {
 	const (
 		clusterName      = "test-cluster"
@@ -138,6 +159,53 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 		}
 	})
 
+	Context("When the spec has duplicate subnet names", func() {
+		It("should report them without creating anything", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				CreateIpblockFunc: func(
+					ctx context.Context, org string, req nico.IpBlockCreateRequest,
+				) (*nico.IpBlock, *http.Response, error) {
+					Fail("IP block created for a spec with duplicate subnet names")
+					return nil, nil, nil
+				},
+			}
+
+			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
+			nvidiaCarbideCluster.Spec.Subnets = append(nvidiaCarbideCluster.Spec.Subnets,
+				infrastructurev1.SubnetSpec{Name: "control-plane", CIDR: "10.0.2.0/24"})
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+				Build()
+
+			reconciler := &NcxInfraClusterReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &infrastructurev1.NcxInfraCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, updated)).To(Succeed())
+			condition := conditions.Get(updated, string(SubnetsReadyCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("DuplicateSubnetName"))
+			Expect(condition.Message).To(Equal("duplicate names in spec: subnet control-plane"))
+		})
+
+		It("should list each duplicate name once", func() {
+			spec := infrastructurev1.NcxInfraClusterSpec{
+				Subnets:     []infrastructurev1.SubnetSpec{{Name: "a"}, {Name: "b"}, {Name: "a"}, {Name: "a"}},
+				VPCPrefixes: []infrastructurev1.VPCPrefixSpec{{Name: "p"}, {Name: "p"}},
+			}
+			Expect(duplicateNetworkNames(spec)).To(Equal([]string{"subnet a", "VPC prefix p"}))
+			Expect(duplicateNetworkNames(nvidiaCarbideCluster.Spec)).To(BeEmpty())
+		})
+	})
+
 	Context("When reconciling a new NcxInfraCluster", func() {
 		It("should add finalizer on first reconcile", func() {
 			vpcID := uuid.New().String()
@@ -151,13 +219,13 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 					return &nico.VPC{Id: &vpcID}, testutil.MockHTTPResponse(201), nil
 				},
 				GetVPCFunc: func(ctx context.Context, org, id string) (*nico.VPC, *http.Response, error) {
-					return nil, nil, fmt.Errorf("not found")
+					return nil, testutil.MockHTTPResponse(http.StatusNotFound), fmt.Errorf("not found")
 				},
 				CreateIpblockFunc: func(ctx context.Context, org string, req nico.IpBlockCreateRequest) (*nico.IpBlock, *http.Response, error) {
 					return &nico.IpBlock{Id: &ipBlockID}, testutil.MockHTTPResponse(201), nil
 				},
 				GetIpblockFunc: func(ctx context.Context, org, id string) (*nico.IpBlock, *http.Response, error) {
-					return nil, nil, fmt.Errorf("not found")
+					return nil, testutil.MockHTTPResponse(http.StatusNotFound), fmt.Errorf("not found")
 				},
 				CreateAllocationFunc: func(ctx context.Context, org string, req nico.AllocationCreateRequest) (*nico.Allocation, *http.Response, error) {
 					resourceType := resourceTypeIPBlock
@@ -177,8 +245,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 				},
 			}
 
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
 				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
 				Build()
@@ -207,6 +274,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 			allocationID := uuid.New().String()
 			childIPBlockID := uuid.New().String()
 			subnetID := uuid.New().String()
+			vpcCreated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
 
 			createVPCCalled := false
 			createSubnetCalled := false
@@ -217,10 +285,10 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 					Expect(org).To(Equal(orgName))
 					Expect(req.Name).To(Equal("test-vpc"))
 					Expect(req.SiteId).To(Equal(siteID))
-					return &nico.VPC{Id: &vpcID, Name: testutil.Ptr("test-vpc")}, testutil.MockHTTPResponse(201), nil
+					return &nico.VPC{Id: &vpcID, Name: testutil.Ptr("test-vpc"), Created: &vpcCreated}, testutil.MockHTTPResponse(201), nil
 				},
 				GetVPCFunc: func(ctx context.Context, org, id string) (*nico.VPC, *http.Response, error) {
-					return nil, nil, fmt.Errorf("not found")
+					return nil, testutil.MockHTTPResponse(http.StatusNotFound), fmt.Errorf("not found")
 				},
 				CreateIpblockFunc: func(ctx context.Context, org string, req nico.IpBlockCreateRequest) (*nico.IpBlock, *http.Response, error) {
 					Expect(req.Prefix).To(Equal("10.0.0.0"))
@@ -228,10 +296,13 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 					return &nico.IpBlock{Id: &ipBlockID}, testutil.MockHTTPResponse(201), nil
 				},
 				GetIpblockFunc: func(ctx context.Context, org, id string) (*nico.IpBlock, *http.Response, error) {
-					return nil, nil, fmt.Errorf("not found")
+					return nil, testutil.MockHTTPResponse(http.StatusNotFound), fmt.Errorf("not found")
 				},
 				CreateAllocationFunc: func(ctx context.Context, org string, req nico.AllocationCreateRequest) (*nico.Allocation, *http.Response, error) {
 					Expect(req.TenantId).To(Equal(tenantID))
+					Expect(req.Name).To(Equal("test-cluster-10-0-1-0-24-allocation"))
+					Expect(req.AllocationConstraints[0].ResourceTypeId).To(Equal(ipBlockID))
+					Expect(req.AllocationConstraints[0].ConstraintValue).To(Equal(int32(24)))
 					resourceType := resourceTypeIPBlock
 					return &nico.Allocation{
 						Id: &allocationID,
@@ -255,8 +326,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 			// Pre-add finalizer to skip the first reconcile
 			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
 
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
 				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
 				Build()
@@ -278,11 +348,87 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 			updatedCluster := &infrastructurev1.NcxInfraCluster{}
 			Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
 			Expect(updatedCluster.Status.Ready).To(BeTrue())
-			Expect(updatedCluster.Status.VPCID).To(Equal(vpcID))
-			Expect(updatedCluster.Status.NetworkStatus.IPBlockID).To(Equal(ipBlockID))
-			Expect(updatedCluster.Status.NetworkStatus.AllocationID).To(Equal(allocationID))
-			Expect(updatedCluster.Status.NetworkStatus.ChildIPBlockID).To(Equal(childIPBlockID))
-			Expect(updatedCluster.Status.NetworkStatus.SubnetIDs).To(HaveKeyWithValue("control-plane", subnetID))
+			Expect(updatedCluster.Status.Phase).To(Equal(infrastructurev1.ClusterPhaseProvisioned))
+			Expect(conditions.IsTrue(updatedCluster, string(clusterv1.ReadyCondition))).To(BeTrue())
+			network := updatedCluster.Status.NetworkStatus
+			Expect(network.VPC).To(Equal(&infrastructurev1.NetworkResourceStatus{
+				Name: "test-vpc", ID: vpcID, State: infrastructurev1.NetworkResourceReady,
+			}))
+			Expect(network.IPBlocks).To(Equal([]infrastructurev1.IPBlockStatus{{
+				CIDR:      "10.0.0.0/16",
+				IPBlockID: ipBlockID,
+				State:     infrastructurev1.NetworkResourceReady,
+			}, {
+				CIDR:           "10.0.1.0/24",
+				AllocationID:   allocationID,
+				ChildIPBlockID: childIPBlockID,
+				State:          infrastructurev1.NetworkResourceReady,
+			}}))
+			Expect(network.Subnets).To(Equal([]infrastructurev1.NetworkResourceStatus{{
+				Name: "control-plane", ID: subnetID, State: infrastructurev1.NetworkResourceReady,
+			}}))
+
+			Expect(updatedCluster.Status.OrgName).To(Equal(orgName))
+
+			origins := updatedCluster.Status.NetworkStatus.Origins
+			Expect(origins).To(HaveLen(2))
+			Expect(origins[vpcID].Kind).To(Equal("VPC"))
+			Expect(origins[vpcID].Name).To(Equal("test-vpc"))
+			Expect(origins[vpcID].CreatedBy).To(Equal(infrastructurev1.ResourceCreator))
+			Expect(origins[vpcID].Created.Time.Equal(vpcCreated)).To(BeTrue())
+			Expect(origins[subnetID].Kind).To(Equal("Subnet"))
+			Expect(origins[subnetID].Created).To(BeNil())
+		})
+	})
+
+	Context("When the credentials point to another org", func() {
+		It("should leave the cluster resources alone", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				GetVPCFunc: func(ctx context.Context, org, id string) (*nico.VPC, *http.Response, error) {
+					Fail("API called with the credentials of another org")
+					return nil, nil, nil
+				},
+				DeleteVPCFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					Fail("API called with the credentials of another org")
+					return nil, nil
+				},
+			}
+
+			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
+			nvidiaCarbideCluster.Status.OrgName = "previous-org"
+			nvidiaCarbideCluster.Status.NetworkStatus.VPC = &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"}
+
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+				Build()
+
+			reconciler := &NcxInfraClusterReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(permissionsRetryInterval))
+
+			updatedCluster := &infrastructurev1.NcxInfraCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.OrgName).To(Equal("previous-org"))
+			Expect(updatedCluster.Status.NetworkStatus.VPCID()).To(Equal("vpc-uuid"))
+			condition := conditions.Get(updatedCluster, string(CredentialsTargetCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("previous-org"))
+
+			// Deleting the cluster does not delete the resources of the other org either
+			Expect(k8sClient.Delete(ctx, updatedCluster)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Finalizers).To(ContainElement(NcxInfraClusterFinalizer))
 		})
 	})
 
@@ -290,7 +436,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 		It("should return error on 500 response", func() {
 			mockClient := &testutil.MockNcxInfraClient{
 				GetIpblockFunc: func(ctx context.Context, org, id string) (*nico.IpBlock, *http.Response, error) {
-					return nil, nil, fmt.Errorf("not found")
+					return nil, testutil.MockHTTPResponse(http.StatusNotFound), fmt.Errorf("not found")
 				},
 				CreateIpblockFunc: func(ctx context.Context, org string, req nico.IpBlockCreateRequest) (*nico.IpBlock, *http.Response, error) {
 					id := uuid.New().String()
@@ -314,8 +460,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 
 			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
 
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
 				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
 				Build()
@@ -330,6 +475,89 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to create VPC"))
+
+			updated := &infrastructurev1.NcxInfraCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, updated)).To(Succeed())
+			ready := conditions.Get(updated, string(clusterv1.ReadyCondition))
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Message).To(ContainSubstring("VPCReady"))
+		})
+	})
+
+	Context("When the credentials lack the required role", func() {
+		It("should report the denied call and the missing role", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				CreateIpblockFunc: func(ctx context.Context, org string, req nico.IpBlockCreateRequest) (*nico.IpBlock, *http.Response, error) {
+					return nil, testutil.MockHTTPResponse(403), fmt.Errorf("403 Forbidden")
+				},
+			}
+
+			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
+
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+				Build()
+
+			reconciler := &NcxInfraClusterReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(permissionsRetryInterval))
+
+			updated := &infrastructurev1.NcxInfraCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, updated)).To(Succeed())
+			condition := conditions.Get(updated, string(InsufficientPermissionsCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(Equal(
+				"CreateIpblock was denied: the credentials need the FORGE_PROVIDER_ADMIN role in org test-org"))
+		})
+	})
+
+	Context("When the credentials are rejected", func() {
+		It("should report the AuthenticationValid condition until they are accepted", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				CreateIpblockFunc: func(ctx context.Context, org string, req nico.IpBlockCreateRequest) (*nico.IpBlock, *http.Response, error) {
+					return nil, testutil.MockHTTPResponse(401), fmt.Errorf("401 Unauthorized")
+				},
+			}
+
+			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
+
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+				Build()
+
+			reconciler := &NcxInfraClusterReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(permissionsRetryInterval))
+
+			updated := &infrastructurev1.NcxInfraCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, updated)).To(Succeed())
+			condition := conditions.Get(updated, string(AuthenticationValidCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("CredentialsRejected"))
+			Expect(condition.Message).To(ContainSubstring("CreateIpblock: unauthorized (HTTP 401)"))
+
+			_, err = handlePermissionError(ctx, updated, orgName, reconcile.Result{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conditions.IsTrue(updated, string(AuthenticationValidCondition))).To(BeTrue())
 		})
 	})
 
@@ -343,7 +571,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 
 			mockClient := &testutil.MockNcxInfraClient{
 				GetIpblockFunc: func(ctx context.Context, org, id string) (*nico.IpBlock, *http.Response, error) {
-					return nil, nil, fmt.Errorf("not found")
+					return nil, testutil.MockHTTPResponse(http.StatusNotFound), fmt.Errorf("not found")
 				},
 				CreateIpblockFunc: func(ctx context.Context, org string, req nico.IpBlockCreateRequest) (*nico.IpBlock, *http.Response, error) {
 					return &nico.IpBlock{Id: &ipBlockID}, testutil.MockHTTPResponse(201), nil
@@ -356,7 +584,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 					return []nico.Allocation{
 						{
 							Id:   &allocationID,
-							Name: testutil.Ptr("test-cluster-allocation"),
+							Name: testutil.Ptr("test-cluster-10-0-1-0-24-allocation"),
 							AllocationConstraints: []nico.AllocationConstraint{
 								{
 									ResourceType:      &resourceType,
@@ -370,7 +598,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 					return &nico.VPC{Id: &vpcID}, testutil.MockHTTPResponse(201), nil
 				},
 				GetVPCFunc: func(ctx context.Context, org, id string) (*nico.VPC, *http.Response, error) {
-					return nil, nil, fmt.Errorf("not found")
+					return nil, testutil.MockHTTPResponse(http.StatusNotFound), fmt.Errorf("not found")
 				},
 				CreateSubnetFunc: func(ctx context.Context, org string, req nico.SubnetCreateRequest) (*nico.Subnet, *http.Response, error) {
 					return &nico.Subnet{Id: &subnetID}, testutil.MockHTTPResponse(201), nil
@@ -379,8 +607,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 
 			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
 
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
 				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
 				Build()
@@ -399,8 +626,335 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 			updatedCluster := &infrastructurev1.NcxInfraCluster{}
 			Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
 			Expect(updatedCluster.Status.Ready).To(BeTrue())
-			Expect(updatedCluster.Status.NetworkStatus.AllocationID).To(Equal(allocationID))
-			Expect(updatedCluster.Status.NetworkStatus.ChildIPBlockID).To(Equal(childIPBlockID))
+			Expect(updatedCluster.Status.NetworkStatus.IPBlocks).To(HaveLen(2))
+			Expect(updatedCluster.Status.NetworkStatus.IPBlocks[1].AllocationID).To(Equal(allocationID))
+			Expect(updatedCluster.Status.NetworkStatus.IPBlocks[1].ChildIPBlockID).To(Equal(childIPBlockID))
+		})
+	})
+
+	Context("When an IP block of the same name already exists", func() {
+		var (
+			ipBlockID string
+			recorder  *record.FakeRecorder
+		)
+
+		reconcileWithExisting := func(description string) *infrastructurev1.NcxInfraCluster {
+			ipBlockID = uuid.New().String()
+			childIPBlockID := uuid.New().String()
+			mockClient := &testutil.MockNcxInfraClient{
+				CreateIpblockFunc: func(ctx context.Context, org string, req nico.IpBlockCreateRequest) (*nico.IpBlock, *http.Response, error) {
+					return nil, testutil.MockHTTPResponse(http.StatusConflict), fmt.Errorf("conflict")
+				},
+				GetAllIpblockFunc: func(ctx context.Context, org, siteID string) ([]nico.IpBlock, *http.Response, error) {
+					return []nico.IpBlock{
+						{Id: testutil.Ptr(uuid.New().String()), Name: testutil.Ptr("other-ipblock")},
+						{
+							Id:           &ipBlockID,
+							Name:         testutil.Ptr("test-cluster-10-0-0-0-16-ipblock"),
+							Description:  &description,
+							Prefix:       testutil.Ptr("10.0.0.0"),
+							PrefixLength: testutil.Ptr(int32(16)),
+						},
+					}, testutil.MockHTTPResponse(http.StatusOK), nil
+				},
+				CreateAllocationFunc: func(ctx context.Context, org string, req nico.AllocationCreateRequest) (*nico.Allocation, *http.Response, error) {
+					Expect(req.AllocationConstraints[0].ResourceTypeId).To(Equal(ipBlockID))
+					resourceType := resourceTypeIPBlock
+					return &nico.Allocation{
+						Id: testutil.Ptr(uuid.New().String()),
+						AllocationConstraints: []nico.AllocationConstraint{{
+							ResourceType:      &resourceType,
+							DerivedResourceId: *nico.NewNullableString(&childIPBlockID),
+						}},
+					}, testutil.MockHTTPResponse(http.StatusCreated), nil
+				},
+				CreateVPCFunc: func(ctx context.Context, org string, req nico.VpcCreateRequest) (*nico.VPC, *http.Response, error) {
+					return &nico.VPC{Id: testutil.Ptr(uuid.New().String())}, testutil.MockHTTPResponse(201), nil
+				},
+				CreateSubnetFunc: func(ctx context.Context, org string, req nico.SubnetCreateRequest) (*nico.Subnet, *http.Response, error) {
+					return &nico.Subnet{Id: testutil.Ptr(uuid.New().String())}, testutil.MockHTTPResponse(201), nil
+				},
+			}
+
+			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+				Build()
+			recorder = record.NewFakeRecorder(10)
+			reconciler := &NcxInfraClusterReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+				Recorder:       recorder,
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			updatedCluster := &infrastructurev1.NcxInfraCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.NetworkStatus.IPBlocks).To(HaveLen(2))
+			Expect(updatedCluster.Status.NetworkStatus.IPBlocks[0].IPBlockID).To(Equal(ipBlockID))
+			Expect(updatedCluster.Status.NetworkStatus.IPBlocks[1].ChildIPBlockID).To(Equal(childIPBlockID))
+			return updatedCluster
+		}
+
+		It("should adopt an IP block created by hand", func() {
+			updatedCluster := reconcileWithExisting("Shared range")
+
+			Expect(updatedCluster.Status.NetworkStatus.IPBlocks[0].Adopted).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("IPBlockAdopted")))
+		})
+
+		It("should manage an IP block the provider created for the cluster", func() {
+			updatedCluster := reconcileWithExisting(fmt.Sprintf("Created by %s for cluster %s",
+				infrastructurev1.ResourceCreator, namespacedName))
+
+			Expect(updatedCluster.Status.NetworkStatus.IPBlocks[0].Adopted).To(BeFalse())
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("IPBlockAdopted")))
+		})
+	})
+
+	Context("When the labels of an existing VPC drifted", func() {
+		It("should apply the labels of the spec to the VPC", func() {
+			nvidiaCarbideCluster.Spec.VPC.Labels = map[string]string{"team": "ml"}
+			nvidiaCarbideCluster.Status.NetworkStatus.VPC = &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"}
+			vpcLabels := map[string]string{"team": "infra"}
+			var updates []nico.VpcUpdateRequest
+			clusterScope := &scope.ClusterScope{
+				Cluster:         cluster,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				OrgName:         orgName,
+				NcxInfraClient: &testutil.MockNcxInfraClient{
+					GetVPCFunc: func(ctx context.Context, org, vpcId string) (*nico.VPC, *http.Response, error) {
+						return &nico.VPC{Id: testutil.Ptr(vpcId), Labels: vpcLabels}, testutil.MockHTTPResponse(200), nil
+					},
+					UpdateVPCFunc: func(
+						ctx context.Context, org, vpcId string, req nico.VpcUpdateRequest,
+					) (*nico.VPC, *http.Response, error) {
+						Expect(vpcId).To(Equal("vpc-uuid"))
+						updates = append(updates, req)
+						vpcLabels = req.Labels
+						return &nico.VPC{Id: testutil.Ptr(vpcId), Labels: req.Labels}, testutil.MockHTTPResponse(200), nil
+					},
+				},
+			}
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &NcxInfraClusterReconciler{Scheme: scheme, Recorder: recorder}
+
+			Expect(reconciler.reconcileVPC(ctx, clusterScope, siteID)).To(Succeed())
+			Expect(updates).To(HaveLen(1))
+			Expect(updates[0].Labels).To(Equal(map[string]string{"team": "ml"}))
+			Expect(recorder.Events).To(Receive(ContainSubstring("VPCUpdated")))
+
+			// The VPC is left alone once in sync, or when the spec has no labels
+			Expect(reconciler.reconcileVPC(ctx, clusterScope, siteID)).To(Succeed())
+			nvidiaCarbideCluster.Spec.VPC.Labels = nil
+			Expect(reconciler.reconcileVPC(ctx, clusterScope, siteID)).To(Succeed())
+			Expect(updates).To(HaveLen(1))
+		})
+	})
+
+	Context("When a subnet has Public egress", func() {
+		var (
+			clusterScope *scope.ClusterScope
+			allocated    func(id string) *nico.Allocation
+		)
+
+		BeforeEach(func() {
+			nvidiaCarbideCluster.Spec.Subnets = append(nvidiaCarbideCluster.Spec.Subnets, infrastructurev1.SubnetSpec{
+				Name:   "ingress",
+				CIDR:   "203.0.113.0/26",
+				Role:   "worker",
+				Egress: infrastructurev1.SubnetEgressPublic,
+			})
+			nvidiaCarbideCluster.Status.NetworkStatus.VPC = &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"}
+			clusterScope = &scope.ClusterScope{
+				Cluster:         cluster,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				OrgName:         orgName,
+			}
+			allocated = func(id string) *nico.Allocation {
+				resourceType := resourceTypeIPBlock
+				childID := id + "-child"
+				return &nico.Allocation{
+					Id: testutil.Ptr(id),
+					AllocationConstraints: []nico.AllocationConstraint{{
+						ResourceType:      &resourceType,
+						DerivedResourceId: *nico.NewNullableString(&childID),
+					}},
+				}
+			}
+		})
+
+		It("should allocate the subnet from a Public IP block built from its CIDR", func() {
+			subnetBlocks := map[string]string{}
+			clusterScope.NcxInfraClient = &testutil.MockNcxInfraClient{
+				CreateIpblockFunc: func(ctx context.Context, org string, req nico.IpBlockCreateRequest) (*nico.IpBlock, *http.Response, error) {
+					if req.RoutingType == routingTypePublic {
+						Expect(req.Prefix).To(Equal("203.0.113.0"))
+						Expect(req.PrefixLength).To(Equal(int32(26)))
+						return &nico.IpBlock{Id: testutil.Ptr("public-ipblock")}, testutil.MockHTTPResponse(201), nil
+					}
+					Expect(req.RoutingType).To(Equal(routingTypeDatacenterOnly))
+					return &nico.IpBlock{Id: testutil.Ptr("ipblock")}, testutil.MockHTTPResponse(201), nil
+				},
+				CreateAllocationFunc: func(ctx context.Context, org string, req nico.AllocationCreateRequest) (*nico.Allocation, *http.Response, error) {
+					if req.AllocationConstraints[0].ResourceTypeId == "public-ipblock" {
+						Expect(req.AllocationConstraints[0].ConstraintValue).To(Equal(int32(26)))
+						return allocated("public-allocation"), testutil.MockHTTPResponse(201), nil
+					}
+					return allocated("allocation"), testutil.MockHTTPResponse(201), nil
+				},
+				CreateSubnetFunc: func(ctx context.Context, org string, req nico.SubnetCreateRequest) (*nico.Subnet, *http.Response, error) {
+					subnetBlocks[req.Name] = *req.Ipv4BlockId
+					return &nico.Subnet{Id: testutil.Ptr(req.Name + "-uuid")}, testutil.MockHTTPResponse(201), nil
+				},
+			}
+			reconciler := &NcxInfraClusterReconciler{Scheme: scheme}
+
+			Expect(reconciler.reconcileSubnets(ctx, clusterScope, siteID)).To(Succeed())
+			Expect(subnetBlocks).To(Equal(map[string]string{
+				"control-plane": "allocation-child",
+				"ingress":       "public-allocation-child",
+			}))
+			Expect(clusterScope.IPBlock("ingress")).To(Equal(infrastructurev1.IPBlockStatus{
+				Subnet:         "ingress",
+				IPBlockID:      "public-ipblock",
+				AllocationID:   "public-allocation",
+				ChildIPBlockID: "public-allocation-child",
+				State:          infrastructurev1.NetworkResourceReady,
+			}))
+		})
+
+		It("should delete the Public IP block after the subnets", func() {
+			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
+			nvidiaCarbideCluster.Status.NetworkStatus = infrastructurev1.NetworkStatus{
+				VPC:     &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"},
+				Subnets: []infrastructurev1.NetworkResourceStatus{{Name: "ingress", ID: "ingress-uuid"}},
+				IPBlocks: []infrastructurev1.IPBlockStatus{{
+					Subnet: "ingress", IPBlockID: "public-ipblock", AllocationID: "public-allocation", ChildIPBlockID: "public-child",
+				}},
+			}
+			deleteOrder := []string{}
+			clusterScope.NcxInfraClient = &testutil.MockNcxInfraClient{
+				DeleteSubnetFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					deleteOrder = append(deleteOrder, id)
+					return testutil.MockHTTPResponse(204), nil
+				},
+				DeleteAllocationFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					deleteOrder = append(deleteOrder, id)
+					return testutil.MockHTTPResponse(204), nil
+				},
+				DeleteIpblockFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					deleteOrder = append(deleteOrder, id)
+					return testutil.MockHTTPResponse(204), nil
+				},
+				DeleteVPCFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					deleteOrder = append(deleteOrder, id)
+					return testutil.MockHTTPResponse(204), nil
+				},
+			}
+			reconciler := &NcxInfraClusterReconciler{Client: newFakeClientBuilder(scheme).Build(), Scheme: scheme}
+
+			_, err := reconciler.reconcileDelete(ctx, clusterScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleteOrder).To(Equal([]string{
+				"ingress-uuid", "public-allocation", "public-child", "public-ipblock", "vpc-uuid",
+			}))
+			Expect(nvidiaCarbideCluster.Status.NetworkStatus.IPBlocks).To(BeEmpty())
+		})
+	})
+
+	Context("When a subnet is placed in another site", func() {
+		const otherSiteID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+		var clusterScope *scope.ClusterScope
+
+		BeforeEach(func() {
+			nvidiaCarbideCluster.Spec.Subnets = append(nvidiaCarbideCluster.Spec.Subnets, infrastructurev1.SubnetSpec{
+				Name:    "control-plane-hall-b",
+				CIDR:    "10.20.0.0/24",
+				Role:    "control-plane",
+				SiteRef: &infrastructurev1.SiteReference{ID: otherSiteID},
+			})
+			nvidiaCarbideCluster.Status.NetworkStatus.VPC = &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"}
+			clusterScope = &scope.ClusterScope{
+				Cluster:         cluster,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				OrgName:         orgName,
+			}
+		})
+
+		It("should create the subnet in a VPC of the cluster in that site and publish the sites as failure domains", func() {
+			subnetVPCs := map[string]string{}
+			clusterScope.NcxInfraClient = &testutil.MockNcxInfraClient{
+				CreateVPCFunc: func(ctx context.Context, org string, req nico.VpcCreateRequest) (*nico.VPC, *http.Response, error) {
+					Expect(req.SiteId).To(Equal(otherSiteID))
+					Expect(req.Name).To(Equal(nvidiaCarbideCluster.Spec.VPC.Name + "-7c9e6679"))
+					return &nico.VPC{Id: testutil.Ptr("site-vpc-uuid")}, testutil.MockHTTPResponse(201), nil
+				},
+				CreateIpblockFunc: func(ctx context.Context, org string, req nico.IpBlockCreateRequest) (*nico.IpBlock, *http.Response, error) {
+					Expect(req.RoutingType).To(Equal(routingTypeDatacenterOnly))
+					if req.SiteId == otherSiteID {
+						Expect(req.Prefix).To(Equal("10.20.0.0"))
+						return &nico.IpBlock{Id: testutil.Ptr("site-ipblock")}, testutil.MockHTTPResponse(201), nil
+					}
+					return &nico.IpBlock{Id: testutil.Ptr("ipblock")}, testutil.MockHTTPResponse(201), nil
+				},
+				CreateAllocationFunc: func(ctx context.Context, org string, req nico.AllocationCreateRequest) (*nico.Allocation, *http.Response, error) {
+					resourceType := resourceTypeIPBlock
+					childID := req.AllocationConstraints[0].ResourceTypeId + "-child"
+					return &nico.Allocation{
+						Id: testutil.Ptr(req.Name),
+						AllocationConstraints: []nico.AllocationConstraint{{
+							ResourceType:      &resourceType,
+							DerivedResourceId: *nico.NewNullableString(&childID),
+						}},
+					}, testutil.MockHTTPResponse(201), nil
+				},
+				CreateSubnetFunc: func(ctx context.Context, org string, req nico.SubnetCreateRequest) (*nico.Subnet, *http.Response, error) {
+					subnetVPCs[req.Name] = req.VpcId + "/" + *req.Ipv4BlockId
+					return &nico.Subnet{Id: testutil.Ptr(req.Name + "-uuid")}, testutil.MockHTTPResponse(201), nil
+				},
+			}
+			reconciler := &NcxInfraClusterReconciler{Scheme: scheme}
+
+			Expect(reconciler.reconcileSubnets(ctx, clusterScope, siteID)).To(Succeed())
+			Expect(subnetVPCs).To(Equal(map[string]string{
+				"control-plane":        "vpc-uuid/ipblock-child",
+				"control-plane-hall-b": "site-vpc-uuid/site-ipblock-child",
+			}))
+			Expect(clusterScope.SiteVPCIDs()).To(Equal(map[string]string{otherSiteID: "site-vpc-uuid"}))
+			Expect(nvidiaCarbideCluster.Status.FailureDomains).To(Equal([]clusterv1.FailureDomain{
+				{Name: siteID, ControlPlane: ptr.To(true)},
+				{Name: otherSiteID, ControlPlane: ptr.To(true)},
+			}))
+		})
+
+		It("should delete the VPC of the other site after its subnet", func() {
+			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
+			nvidiaCarbideCluster.Status.NetworkStatus = infrastructurev1.NetworkStatus{
+				VPC:      &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"},
+				SiteVPCs: []infrastructurev1.NetworkResourceStatus{{Name: otherSiteID, ID: "site-vpc-uuid"}},
+				Subnets:  []infrastructurev1.NetworkResourceStatus{{Name: "control-plane-hall-b", ID: "subnet-uuid"}},
+			}
+			deleteOrder := []string{}
+			recordDelete := func(ctx context.Context, org, id string) (*http.Response, error) {
+				deleteOrder = append(deleteOrder, id)
+				return testutil.MockHTTPResponse(204), nil
+			}
+			clusterScope.NcxInfraClient = &testutil.MockNcxInfraClient{
+				DeleteSubnetFunc: recordDelete,
+				DeleteVPCFunc:    recordDelete,
+			}
+			reconciler := &NcxInfraClusterReconciler{Client: newFakeClientBuilder(scheme).Build(), Scheme: scheme}
+
+			_, err := reconciler.reconcileDelete(ctx, clusterScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleteOrder).To(Equal([]string{"subnet-uuid", "site-vpc-uuid", "vpc-uuid"}))
+			Expect(clusterScope.SiteVPCIDs()).To(BeEmpty())
 		})
 	})
 
@@ -461,19 +1015,22 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 						Finalizers: []string{NcxInfraClusterFinalizer},
 					},
 					Status: infrastructurev1.NcxInfraClusterStatus{
-						VPCID: vpcID,
 						NetworkStatus: infrastructurev1.NetworkStatus{
-							SubnetIDs:      map[string]string{"control-plane": subnetID},
-							NSGID:          nsgID,
-							AllocationID:   allocationID,
-							ChildIPBlockID: childIPBlockID,
-							IPBlockID:      parentIPBlockID,
+							VPC:     &infrastructurev1.NetworkResourceStatus{ID: vpcID},
+							Subnets: []infrastructurev1.NetworkResourceStatus{{Name: "control-plane", ID: subnetID}},
+							NSG: &infrastructurev1.NSGStatus{
+								NetworkResourceStatus: infrastructurev1.NetworkResourceStatus{ID: nsgID},
+							},
+							IPBlocks: []infrastructurev1.IPBlockStatus{{
+								IPBlockID: parentIPBlockID, AllocationID: allocationID, ChildIPBlockID: childIPBlockID,
+							}},
 						},
 					},
 				},
 			}
 
 			reconciler := &NcxInfraClusterReconciler{
+				Client:         newFakeClientBuilder(scheme).Build(),
 				Scheme:         scheme,
 				NcxInfraClient: mockClient,
 				OrgName:        orgName,
@@ -492,6 +1049,103 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 			Expect(clusterScope.NcxInfraCluster.Finalizers).NotTo(ContainElement(NcxInfraClusterFinalizer))
 		})
 
+		deletingScope := func(mockClient *testutil.MockNcxInfraClient, ipBlock infrastructurev1.IPBlockStatus) *scope.ClusterScope {
+			return &scope.ClusterScope{
+				Cluster:        cluster,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+				NcxInfraCluster: &infrastructurev1.NcxInfraCluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       clusterName,
+						Namespace:  clusterNamespace,
+						Finalizers: []string{NcxInfraClusterFinalizer},
+					},
+					Status: infrastructurev1.NcxInfraClusterStatus{
+						NetworkStatus: infrastructurev1.NetworkStatus{
+							IPBlocks: []infrastructurev1.IPBlockStatus{ipBlock},
+						},
+					},
+				},
+			}
+		}
+
+		It("should keep an adopted IP block", func() {
+			parentIPBlockID := uuid.New().String()
+			childIPBlockID := uuid.New().String()
+			deleted := []string{}
+
+			mockClient := &testutil.MockNcxInfraClient{
+				DeleteAllocationFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					deleted = append(deleted, id)
+					return testutil.MockHTTPResponse(200), nil
+				},
+				DeleteIpblockFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					deleted = append(deleted, id)
+					return testutil.MockHTTPResponse(200), nil
+				},
+			}
+			clusterScope := deletingScope(mockClient, infrastructurev1.IPBlockStatus{
+				CIDR: "10.0.0.0/16", IPBlockID: parentIPBlockID, AllocationID: "allocation",
+				ChildIPBlockID: childIPBlockID, Adopted: true,
+			})
+			reconciler := &NcxInfraClusterReconciler{
+				Client:         newFakeClientBuilder(scheme).Build(),
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			_, err := reconciler.reconcileDelete(ctx, clusterScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal([]string{"allocation", childIPBlockID}))
+			Expect(clusterScope.IPBlocks()).To(BeEmpty())
+			Expect(clusterScope.NcxInfraCluster.Finalizers).NotTo(ContainElement(NcxInfraClusterFinalizer))
+		})
+
+		It("should retry the deletion of an IP block still in use", func() {
+			parentIPBlockID := uuid.New().String()
+			inUse := true
+
+			mockClient := &testutil.MockNcxInfraClient{
+				DeleteIpblockFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					if inUse {
+						return testutil.MockHTTPResponse(http.StatusConflict), fmt.Errorf("IP block still has subnet")
+					}
+					return testutil.MockHTTPResponse(200), nil
+				},
+			}
+			clusterScope := deletingScope(mockClient, infrastructurev1.IPBlockStatus{
+				CIDR: "10.0.0.0/16", IPBlockID: parentIPBlockID,
+			})
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &NcxInfraClusterReconciler{
+				Client:         newFakeClientBuilder(scheme).Build(),
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+				Recorder:       recorder,
+			}
+
+			result, err := reconciler.reconcileDelete(ctx, clusterScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(deletionRetryMinInterval))
+			Expect(recorder.Events).To(Receive(ContainSubstring("ResourceInUse")))
+			Expect(conditions.GetReason(clusterScope.NcxInfraCluster, string(IPBlocksDeletedCondition))).To(Equal("InUse"))
+			Expect(conditions.GetReason(clusterScope.NcxInfraCluster, string(VPCDeletedCondition))).
+				To(Equal("WaitingForDependencies"))
+			Expect(clusterScope.IPBlocks()).To(HaveLen(1))
+			Expect(clusterScope.IPBlocks()[0].IPBlockID).To(Equal(parentIPBlockID))
+			Expect(clusterScope.IPBlocks()[0].LastError).To(ContainSubstring("still has subnet"))
+			Expect(clusterScope.NcxInfraCluster.Finalizers).To(ContainElement(NcxInfraClusterFinalizer))
+
+			inUse = false
+			result, err = reconciler.reconcileDelete(ctx, clusterScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(clusterScope.IPBlocks()).To(BeEmpty())
+			Expect(clusterScope.NcxInfraCluster.Finalizers).NotTo(ContainElement(NcxInfraClusterFinalizer))
+		})
+
 		It("should handle 404 gracefully during deletion", func() {
 			vpcID := uuid.New().String()
 
@@ -513,12 +1167,15 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 						Finalizers: []string{NcxInfraClusterFinalizer},
 					},
 					Status: infrastructurev1.NcxInfraClusterStatus{
-						VPCID: vpcID,
+						NetworkStatus: infrastructurev1.NetworkStatus{
+							VPC: &infrastructurev1.NetworkResourceStatus{ID: vpcID},
+						},
 					},
 				},
 			}
 
 			reconciler := &NcxInfraClusterReconciler{
+				Client:         newFakeClientBuilder(scheme).Build(),
 				Scheme:         scheme,
 				NcxInfraClient: mockClient,
 				OrgName:        orgName,
@@ -530,13 +1187,140 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 		})
 	})
 
+	Context("When the site is in the site inventory", func() {
+		It("should resolve the site name and report the site health", func() {
+			nvidiaCarbideCluster.Spec.SiteRef = infrastructurev1.SiteReference{Name: "us-west-1"}
+			site := &infrastructurev1.NcxInfraSite{
+				ObjectMeta: metav1.ObjectMeta{Name: siteID},
+				Spec:       infrastructurev1.NcxInfraSiteSpec{ID: siteID, Name: "us-west-1", Org: orgName},
+				Status:     infrastructurev1.NcxInfraSiteStatus{State: "Registered", Online: true, Healthy: true},
+			}
+			k8sClient := newFakeClientBuilder(scheme).WithObjects(site).Build()
+			clusterScope := &scope.ClusterScope{
+				Client:          k8sClient,
+				Cluster:         cluster,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				// The site is not looked up through the API
+				NcxInfraClient: &testutil.MockNcxInfraClient{},
+				OrgName:        orgName,
+			}
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &NcxInfraClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: recorder}
+
+			Expect(clusterScope.SiteID(ctx)).To(Equal(siteID))
+			reconciler.reconcileSiteHealth(ctx, clusterScope, siteID)
+			Expect(conditions.IsTrue(nvidiaCarbideCluster, string(SiteHealthyCondition))).To(BeTrue())
+
+			site.Status.Online, site.Status.Healthy = false, false
+			Expect(k8sClient.Update(ctx, site)).To(Succeed())
+			reconciler.reconcileSiteHealth(ctx, clusterScope, siteID)
+			condition := conditions.Get(nvidiaCarbideCluster, string(SiteHealthyCondition))
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("SiteOffline"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("SiteUnhealthy")))
+
+			// The event is not repeated while the site stays unhealthy
+			reconciler.reconcileSiteHealth(ctx, clusterScope, siteID)
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+
+	Context("When the VPC is shared between clusters", func() {
+		var (
+			mockClient *testutil.MockNcxInfraClient
+			deletedVPC []string
+		)
+
+		BeforeEach(func() {
+			deletedVPC = nil
+			mockClient = &testutil.MockNcxInfraClient{
+				CreateVPCFunc: func(ctx context.Context, org string, req nico.VpcCreateRequest) (*nico.VPC, *http.Response, error) {
+					return nil, nil, fmt.Errorf("should not be called")
+				},
+				GetVPCFunc: func(ctx context.Context, org, id string) (*nico.VPC, *http.Response, error) {
+					return &nico.VPC{Id: testutil.Ptr(id), SiteId: testutil.Ptr(siteID)}, testutil.MockHTTPResponse(200), nil
+				},
+				DeleteVPCFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					deletedVPC = append(deletedVPC, id)
+					return testutil.MockHTTPResponse(204), nil
+				},
+			}
+		})
+
+		It("should use the VPC of the spec without creating one", func() {
+			nvidiaCarbideCluster.Spec.VPC.ID = "shared-vpc-uuid"
+			clusterScope := &scope.ClusterScope{
+				Cluster:         cluster,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+			}
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &NcxInfraClusterReconciler{Scheme: scheme, Recorder: recorder}
+
+			Expect(reconciler.reconcileVPC(ctx, clusterScope, siteID)).To(Succeed())
+			Expect(clusterScope.VPCID()).To(Equal("shared-vpc-uuid"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("SharedVPCUsed")))
+
+			// A VPC of another site is rejected
+			err := reconciler.reconcileVPC(ctx, clusterScope, "other-site")
+			Expect(err).To(MatchError(ContainSubstring("not in the site of the cluster")))
+		})
+
+		It("should keep the shared VPC until the last cluster using it is deleted", func() {
+			sharing := nvidiaCarbideCluster.DeepCopy()
+			sharing.Name = "sharing-cluster"
+			sharing.Namespace = "team-b"
+			sharing.OwnerReferences = nil
+			sharing.Spec.VPC.ID = "shared-vpc-uuid"
+			k8sClient := newFakeClientBuilder(scheme).WithObjects(sharing).Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &NcxInfraClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: recorder}
+
+			// The cluster sharing the VPC leaves it alone
+			sharingScope := &scope.ClusterScope{
+				Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "sharing", Namespace: "team-b"}},
+				NcxInfraCluster: sharing,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+			}
+			sharingScope.SetVPCID("shared-vpc-uuid")
+			_, err := reconciler.reconcileDelete(ctx, sharingScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deletedVPC).To(BeEmpty())
+			Expect(sharingScope.VPCID()).To(BeEmpty())
+
+			// The cluster that created it waits for the clusters sharing it
+			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
+			clusterScope := &scope.ClusterScope{
+				Cluster:         cluster,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+			}
+			clusterScope.SetVPCID("shared-vpc-uuid")
+			result, err := reconciler.reconcileDelete(ctx, clusterScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(deletionRetryMinInterval))
+			Expect(deletedVPC).To(BeEmpty())
+			Expect(nvidiaCarbideCluster.Finalizers).To(ContainElement(NcxInfraClusterFinalizer))
+			Expect(recorder.Events).To(Receive(ContainSubstring("team-b/sharing-cluster")))
+
+			Expect(k8sClient.Delete(ctx, sharing)).To(Succeed())
+			result, err = reconciler.reconcileDelete(ctx, clusterScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(deletedVPC).To(Equal([]string{"shared-vpc-uuid"}))
+			Expect(nvidiaCarbideCluster.Finalizers).NotTo(ContainElement(NcxInfraClusterFinalizer))
+		})
+	})
+
 	Context("When cluster is paused", func() {
 		It("should skip reconciliation", func() {
 			paused := true
 			cluster.Spec.Paused = &paused
 
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
 				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
 				Build()
@@ -554,8 +1338,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 
 	Context("When NcxInfraCluster does not exist", func() {
 		It("should return without error", func() {
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster).
 				Build()
 
@@ -602,8 +1385,7 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 				},
 			}
 
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
 				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
 				Build()
@@ -619,6 +1401,396 @@ var _ = Describe("NcxInfraCluster Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(result.Requeue).To(BeFalse()) //nolint:staticcheck // checking Requeue field
 			Expect(createVPCCalled).To(BeFalse())
+
+			// The IDs recorded in the deprecated status fields are migrated
+			updatedCluster := &infrastructurev1.NcxInfraCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.VPCID).To(BeEmpty())
+			Expect(updatedCluster.Status.NetworkStatus.VPCID()).To(Equal(vpcID))
+			Expect(updatedCluster.Status.NetworkStatus.SubnetIDs).To(BeNil())
+			Expect(updatedCluster.Status.NetworkStatus.SubnetID("control-plane")).To(Equal(subnetID))
+			Expect(updatedCluster.Status.NetworkStatus.ChildIPBlockID).To(BeEmpty())
+			Expect(updatedCluster.Status.NetworkStatus.IPBlocks).To(ConsistOf(
+				HaveField("ChildIPBlockID", childIPBlockID)))
+		})
+	})
+
+	Context("When the site of existing resources is unreachable", func() {
+		var (
+			vpcID, childIPBlockID, subnetID string
+			getSubnetStatus                 int
+			createCalls                     int
+			reconciler                      *NcxInfraClusterReconciler
+			k8sClient                       client.Client
+		)
+
+		BeforeEach(func() {
+			vpcID = uuid.New().String()
+			childIPBlockID = uuid.New().String()
+			subnetID = uuid.New().String()
+			getSubnetStatus = http.StatusServiceUnavailable
+			createCalls = 0
+
+			mockClient := &testutil.MockNcxInfraClient{
+				GetVPCFunc: func(ctx context.Context, org, id string) (*nico.VPC, *http.Response, error) {
+					return &nico.VPC{Id: &vpcID}, testutil.MockHTTPResponse(200), nil
+				},
+				GetIpblockFunc: func(ctx context.Context, org, id string) (*nico.IpBlock, *http.Response, error) {
+					return &nico.IpBlock{Id: &childIPBlockID}, testutil.MockHTTPResponse(200), nil
+				},
+				GetSubnetFunc: func(ctx context.Context, org, id string) (*nico.Subnet, *http.Response, error) {
+					if getSubnetStatus != http.StatusOK {
+						return nil, testutil.MockHTTPResponse(getSubnetStatus), fmt.Errorf("%s", http.StatusText(getSubnetStatus))
+					}
+					return &nico.Subnet{Id: &subnetID}, testutil.MockHTTPResponse(200), nil
+				},
+				CreateSubnetFunc: func(ctx context.Context, org string, req nico.SubnetCreateRequest) (*nico.Subnet, *http.Response, error) {
+					createCalls++
+					newID := uuid.New().String()
+					return &nico.Subnet{Id: &newID}, testutil.MockHTTPResponse(201), nil
+				},
+			}
+
+			nvidiaCarbideCluster.Finalizers = []string{NcxInfraClusterFinalizer}
+			nvidiaCarbideCluster.Status = infrastructurev1.NcxInfraClusterStatus{
+				VPCID: vpcID,
+				NetworkStatus: infrastructurev1.NetworkStatus{
+					ChildIPBlockID: childIPBlockID,
+					SubnetIDs:      map[string]string{"control-plane": subnetID},
+				},
+			}
+			k8sClient = newFakeClientBuilder(scheme).
+				WithObjects(cluster, nvidiaCarbideCluster, credsSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+				Build()
+			reconciler = &NcxInfraClusterReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+				Recorder:       record.NewFakeRecorder(10),
+			}
+		})
+
+		It("should keep the resources and report the SiteReachable condition until the site answers", func() {
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(siteUnreachableRetryInterval))
+			Expect(createCalls).To(BeZero())
+
+			updatedCluster := &infrastructurev1.NcxInfraCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.NetworkStatus.SubnetID("control-plane")).To(Equal(subnetID))
+			condition := conditions.Get(updatedCluster, string(SiteReachableCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("SiteUnreachable"))
+			Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("SiteUnreachable")))
+
+			getSubnetStatus = http.StatusOK
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createCalls).To(BeZero())
+			Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.Ready).To(BeTrue())
+			Expect(conditions.IsTrue(updatedCluster, string(SiteReachableCondition))).To(BeTrue())
 		})
+
+		It("should recreate the resources NVIDIA Carbide reports gone", func() {
+			getSubnetStatus = http.StatusNotFound
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createCalls).To(Equal(1))
+
+			updatedCluster := &infrastructurev1.NcxInfraCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.NetworkStatus.SubnetID("control-plane")).NotTo(Equal(subnetID))
+		})
+	})
+
+	Context("When a subnet cannot be created", func() {
+		It("should record the error in the subnet status until it is created", func() {
+			nvidiaCarbideCluster.Status.NetworkStatus = infrastructurev1.NetworkStatus{
+				VPC: &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"},
+				IPBlocks: []infrastructurev1.IPBlockStatus{{
+					IPBlockID: "ipblock", AllocationID: "allocation", ChildIPBlockID: "child",
+				}},
+			}
+			createErr := fmt.Errorf("prefix exhausted")
+			mockClient := &testutil.MockNcxInfraClient{
+				GetIpblockFunc: func(ctx context.Context, org, id string) (*nico.IpBlock, *http.Response, error) {
+					return &nico.IpBlock{Id: &id}, testutil.MockHTTPResponse(200), nil
+				},
+				CreateSubnetFunc: func(ctx context.Context, org string, req nico.SubnetCreateRequest) (*nico.Subnet, *http.Response, error) {
+					if createErr != nil {
+						return nil, testutil.MockHTTPResponse(400), createErr
+					}
+					return &nico.Subnet{Id: testutil.Ptr("subnet-uuid")}, testutil.MockHTTPResponse(201), nil
+				},
+			}
+			clusterScope := &scope.ClusterScope{
+				Cluster:         cluster,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+			}
+			reconciler := &NcxInfraClusterReconciler{Scheme: scheme}
+
+			Expect(reconciler.reconcileSubnets(ctx, clusterScope, siteID)).NotTo(Succeed())
+			subnets := nvidiaCarbideCluster.Status.NetworkStatus.Subnets
+			Expect(subnets).To(HaveLen(1))
+			Expect(subnets[0].Name).To(Equal("control-plane"))
+			Expect(subnets[0].ID).To(BeEmpty())
+			Expect(subnets[0].State).To(Equal(infrastructurev1.NetworkResourceFailed))
+			Expect(subnets[0].LastError).To(ContainSubstring("prefix exhausted"))
+
+			createErr = nil
+			Expect(reconciler.reconcileSubnets(ctx, clusterScope, siteID)).To(Succeed())
+			Expect(nvidiaCarbideCluster.Status.NetworkStatus.Subnets).To(Equal([]infrastructurev1.NetworkResourceStatus{{
+				Name: "control-plane", ID: "subnet-uuid", State: infrastructurev1.NetworkResourceReady,
+			}}))
+		})
+	})
+
+	Context("When NVIDIA Carbide allocates another prefix than the CIDR of a subnet", func() {
+		It("should record the allocated prefix and report the mismatch", func() {
+			nvidiaCarbideCluster.Status.NetworkStatus = infrastructurev1.NetworkStatus{
+				VPC: &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"},
+				IPBlocks: []infrastructurev1.IPBlockStatus{{
+					IPBlockID: "ipblock", AllocationID: "allocation", ChildIPBlockID: "child",
+				}},
+			}
+			allocated := "10.0.7.0"
+			mockClient := &testutil.MockNcxInfraClient{
+				GetIpblockFunc: func(ctx context.Context, org, id string) (*nico.IpBlock, *http.Response, error) {
+					return &nico.IpBlock{Id: &id}, testutil.MockHTTPResponse(200), nil
+				},
+				CreateSubnetFunc: func(ctx context.Context, org string, req nico.SubnetCreateRequest) (*nico.Subnet, *http.Response, error) {
+					subnet := &nico.Subnet{Id: testutil.Ptr("subnet-uuid"), PrefixLength: &req.PrefixLength}
+					subnet.SetIpv4Prefix(allocated)
+					return subnet, testutil.MockHTTPResponse(201), nil
+				},
+				GetSubnetFunc: func(ctx context.Context, org, id string) (*nico.Subnet, *http.Response, error) {
+					subnet := &nico.Subnet{Id: &id}
+					subnet.SetIpv4Prefix(allocated + "/24")
+					return subnet, testutil.MockHTTPResponse(200), nil
+				},
+			}
+			clusterScope := &scope.ClusterScope{
+				Cluster:         cluster,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+			}
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &NcxInfraClusterReconciler{Scheme: scheme, Recorder: recorder}
+
+			Expect(reconciler.reconcileSubnets(ctx, clusterScope, siteID)).To(Succeed())
+			reconciler.reconcileSubnetCIDRs(clusterScope)
+			Expect(nvidiaCarbideCluster.Status.NetworkStatus.SubnetCIDR("control-plane")).To(Equal("10.0.7.0/24"))
+			Expect(conditions.IsTrue(nvidiaCarbideCluster, string(SubnetCIDRMismatchCondition))).To(BeTrue())
+			Expect(conditions.GetMessage(nvidiaCarbideCluster, string(SubnetCIDRMismatchCondition))).To(
+				ContainSubstring("control-plane (requested 10.0.1.0/24, allocated 10.0.7.0/24)"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("SubnetCreated")))
+			Expect(recorder.Events).To(Receive(ContainSubstring("SubnetCIDRMismatch")))
+
+			// The verified subnet reports the prefix it kept
+			allocated = "10.0.1.0"
+			Expect(reconciler.reconcileSubnets(ctx, clusterScope, siteID)).To(Succeed())
+			reconciler.reconcileSubnetCIDRs(clusterScope)
+			Expect(nvidiaCarbideCluster.Status.NetworkStatus.SubnetCIDR("control-plane")).To(Equal("10.0.1.0/24"))
+			Expect(conditions.IsFalse(nvidiaCarbideCluster, string(SubnetCIDRMismatchCondition))).To(BeTrue())
+		})
+	})
+
+	Context("When a machine of the cluster failed", func() {
+		It("should summarize the failure and mirror it into the Cluster conditions", func() {
+			failedMachine := &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "worker-0",
+					Namespace: clusterNamespace,
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{
+					FailureReason:  testutil.Ptr(capierrors.MachineStatusError("CreateInstanceFailed")),
+					FailureMessage: testutil.Ptr("no machine available"),
+				},
+			}
+			healthyMachine := &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "worker-1",
+					Namespace: clusterNamespace,
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+				},
+			}
+			conditions.Set(nvidiaCarbideCluster, metav1.Condition{
+				Type:   string(VPCReadyCondition),
+				Status: metav1.ConditionTrue,
+				Reason: "VPCProvisioned",
+			})
+
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, nvidiaCarbideCluster, failedMachine, healthyMachine).
+				WithStatusSubresource(&clusterv1.Cluster{}).
+				Build()
+
+			reconciler := &NcxInfraClusterReconciler{
+				Client: k8sClient,
+				Scheme: scheme,
+			}
+
+			reconciler.updateInstancesProvisioned(ctx, cluster, nvidiaCarbideCluster)
+			condition := conditions.Get(nvidiaCarbideCluster, string(InstancesProvisionedCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("1 of 2 machine(s) failed: worker-0: no machine available"))
+
+			Expect(reconciler.mirrorConditions(ctx, cluster, nvidiaCarbideCluster)).To(Succeed())
+
+			updated := &clusterv1.Cluster{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), updated)).To(Succeed())
+			mirrored := conditions.Get(updated, "NcxInfraInstancesProvisioned")
+			Expect(mirrored).NotTo(BeNil())
+			Expect(mirrored.Status).To(Equal(metav1.ConditionFalse))
+			Expect(mirrored.Message).To(ContainSubstring("worker-0"))
+			Expect(conditions.IsTrue(updated, "NcxInfraVPCReady")).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("Field index mappings", func() {
+	var reconciler *NcxInfraClusterReconciler
+
+	BeforeEach(func() {
+		newCluster := func(name string, mutate func(*infrastructurev1.NcxInfraCluster)) *infrastructurev1.NcxInfraCluster {
+			c := &infrastructurev1.NcxInfraCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+			mutate(c)
+			return c
+		}
+		reconciler = &NcxInfraClusterReconciler{
+			Client: newFakeClientBuilder(newTestScheme()).WithObjects(
+				newCluster("shared-nsg", func(c *infrastructurev1.NcxInfraCluster) {
+					c.Spec.VPC.NetworkSecurityGroupRef = &corev1.LocalObjectReference{Name: "nsg"}
+					c.Spec.Authentication.SecretRef = corev1.SecretReference{Name: "creds"}
+				}),
+				newCluster("other-nsg", func(c *infrastructurev1.NcxInfraCluster) {
+					c.Spec.VPC.NetworkSecurityGroupRef = &corev1.LocalObjectReference{Name: "other"}
+					c.Spec.Authentication.SecretRef = corev1.SecretReference{Name: "creds", Namespace: "infra"}
+				}),
+				newCluster("default-credentials", func(*infrastructurev1.NcxInfraCluster) {}),
+			).Build(),
+		}
+	})
+
+	It("maps a NcxInfraNetworkSecurityGroup to the clusters referencing it", func() {
+		nsg := &infrastructurev1.NcxInfraNetworkSecurityGroup{ObjectMeta: metav1.ObjectMeta{Name: "nsg", Namespace: "default"}}
+		Expect(reconciler.ncxInfraNetworkSecurityGroupToNcxInfraClusters(context.Background(), nsg)).To(ConsistOf(
+			reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "shared-nsg"}}))
+	})
+
+	It("maps a credentials secret to the clusters referencing it, across namespaces", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "infra"}}
+		Expect(reconciler.secretToNcxInfraClusters(context.Background(), secret)).To(ConsistOf(
+			reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "other-nsg"}}))
+
+		secret.Namespace = "default"
+		Expect(reconciler.secretToNcxInfraClusters(context.Background(), secret)).To(ConsistOf(
+			reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "shared-nsg"}}))
+	})
+})
+
+var _ = Describe("sortConditions", func() {
+	It("should sort the conditions without changing them", func() {
+		transition := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		cluster := &infrastructurev1.NcxInfraCluster{}
+		cluster.Status.Conditions = []metav1.Condition{
+			{Type: string(VPCReadyCondition), Status: metav1.ConditionTrue, Reason: "VPCCreated",
+				LastTransitionTime: transition, ObservedGeneration: 3},
+			{Type: string(SubnetsReadyCondition), Status: metav1.ConditionTrue, Reason: "SubnetsCreated",
+				LastTransitionTime: transition, ObservedGeneration: 3},
+			{Type: string(clusterv1.ReadyCondition), Status: metav1.ConditionTrue, Reason: "Ready",
+				LastTransitionTime: transition, ObservedGeneration: 3},
+		}
+
+		sortConditions(cluster)
+
+		Expect(cluster.Status.Conditions).To(HaveLen(3))
+		Expect(cluster.Status.Conditions[0].Type).To(Equal(string(clusterv1.ReadyCondition)))
+		Expect(cluster.Status.Conditions[1].Type).To(Equal(string(SubnetsReadyCondition)))
+		Expect(cluster.Status.Conditions[2].Type).To(Equal(string(VPCReadyCondition)))
+		for _, condition := range cluster.Status.Conditions {
+			Expect(condition.LastTransitionTime).To(Equal(transition))
+			Expect(condition.ObservedGeneration).To(Equal(int64(3)))
+		}
+
+		// Sorting again changes nothing
+		sorted := cluster.DeepCopy()
+		sortConditions(cluster)
+		Expect(cluster.Status.Conditions).To(Equal(sorted.Status.Conditions))
+	})
+})
+
+var _ = Describe("Status patch errors", func() {
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		key        types.NamespacedName
+		objects    []client.Object
+		patchError error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = newTestScheme()
+		key = types.NamespacedName{Name: "test-cluster", Namespace: "default"}
+		now := metav1.Now()
+		// A protected cluster being deleted only sets the DeletionBlocked condition
+		objects = []client.Object{
+			&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "cluster-uid"}},
+			&infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              key.Name,
+					Namespace:         key.Namespace,
+					Annotations:       map[string]string{infrastructurev1.PreventDeletionAnnotation: ""},
+					Finalizers:        []string{NcxInfraClusterFinalizer},
+					DeletionTimestamp: &now,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: key.Name, UID: "cluster-uid",
+					}},
+				},
+			},
+		}
+	})
+
+	reconcile := func() (reconcile.Result, error) {
+		k8sClient := newFakeClientBuilder(scheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(
+					ctx context.Context, c client.Client, subResourceName string, obj client.Object,
+					patch client.Patch, opts ...client.SubResourcePatchOption,
+				) error {
+					return patchError
+				},
+			}).
+			Build()
+		reconciler := &NcxInfraClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+		return reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	}
+
+	It("should requeue when the status patch conflicts", func() {
+		patchError = apierrors.NewConflict(infrastructurev1.GroupVersion.WithResource("ncxinfraclusters").GroupResource(),
+			key.Name, fmt.Errorf("the object has been modified"))
+		result, err := reconcile()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue()) //nolint:staticcheck // checking Requeue field
+	})
+
+	It("should return the other errors of the status patch", func() {
+		patchError = apierrors.NewServiceUnavailable("etcd is unavailable")
+		_, err := reconcile()
+		Expect(err).To(MatchError(ContainSubstring("failed to patch NcxInfraCluster")))
+		Expect(err).To(MatchError(ContainSubstring("etcd is unavailable")))
 	})
 })
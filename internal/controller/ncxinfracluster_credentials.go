@@ -0,0 +1,121 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// CredentialsFinalizer keeps a credentials secret around while NcxInfraClusters
+// use it, so that a cluster deleted after its secret can still delete its
+// NVIDIA Carbide resources.
+const CredentialsFinalizer = "ncxinfracluster.infrastructure.cluster.x-k8s.io/credentials"
+
+// CredentialsMissingCondition reports that the credentials secret of the
+// cluster does not exist.
+const CredentialsMissingCondition clusterv1.ConditionType = "CredentialsMissing"
+
+// setCredentialsMissing reports the missing credentials secret in the
+// CredentialsMissing condition.
+func setCredentialsMissing(nvidiaCarbideCluster *infrastructurev1.NcxInfraCluster, err error) {
+	conditions.Set(nvidiaCarbideCluster, metav1.Condition{
+		Type:    string(CredentialsMissingCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  "CredentialsSecretNotFound",
+		Message: fmt.Sprintf("%v, restore it to reconcile the cluster", err),
+	})
+}
+
+// reconcileCredentialsFinalizer holds the credentials secret in use with a
+// finalizer and records it in status, releasing the secret used before.
+func (r *NcxInfraClusterReconciler) reconcileCredentialsFinalizer(
+	ctx context.Context, clusterScope *scope.ClusterScope,
+) error {
+	ref := clusterScope.CredentialsSecret
+	if ref.Name == "" {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return fmt.Errorf("failed to get credentials secret: %w", err)
+	}
+	if !controllerutil.ContainsFinalizer(secret, CredentialsFinalizer) {
+		base := secret.DeepCopy()
+		controllerutil.AddFinalizer(secret, CredentialsFinalizer)
+		if err := r.Patch(ctx, secret, client.MergeFrom(base)); err != nil {
+			return fmt.Errorf("failed to add finalizer to credentials secret %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+	}
+
+	status := &clusterScope.NcxInfraCluster.Status
+	if previous := status.CredentialsSecretRef; previous != nil && *previous != ref {
+		if err := r.releaseCredentialsSecret(ctx, clusterScope.NcxInfraCluster, *previous); err != nil {
+			return err
+		}
+	}
+	status.CredentialsSecretRef = &ref
+	return nil
+}
+
+// releaseCredentialsSecret removes the finalizer of a credentials secret that
+// no other NcxInfraCluster references or uses.
+func (r *NcxInfraClusterReconciler) releaseCredentialsSecret(
+	ctx context.Context, nvidiaCarbideCluster *infrastructurev1.NcxInfraCluster, ref corev1.SecretReference,
+) error {
+	key := client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+	clusters := &infrastructurev1.NcxInfraClusterList{}
+	if err := r.List(ctx, clusters, client.MatchingFields{CredentialsSecretField: key.String()}); err != nil {
+		return fmt.Errorf("failed to list the NcxInfraClusters using credentials secret %s: %w", key, err)
+	}
+	for _, c := range clusters.Items {
+		if c.Namespace != nvidiaCarbideCluster.Namespace || c.Name != nvidiaCarbideCluster.Name {
+			return nil
+		}
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get credentials secret: %w", err)
+	}
+	if !controllerutil.ContainsFinalizer(secret, CredentialsFinalizer) {
+		return nil
+	}
+	base := secret.DeepCopy()
+	controllerutil.RemoveFinalizer(secret, CredentialsFinalizer)
+	if err := r.Patch(ctx, secret, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("failed to remove finalizer from credentials secret %s: %w", key, err)
+	}
+	log.FromContext(ctx).Info("Released credentials secret", "secret", key)
+	return nil
+}
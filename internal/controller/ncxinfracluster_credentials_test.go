@@ -0,0 +1,135 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Credentials secret protection", func() {
+	var (
+		ctx             context.Context
+		k8sClient       client.Client
+		reconciler      *NcxInfraClusterReconciler
+		ncxInfraCluster *infrastructurev1.NcxInfraCluster
+		clusterScope    *scope.ClusterScope
+	)
+
+	secretRef := func(name string) corev1.SecretReference {
+		return corev1.SecretReference{Namespace: "default", Name: name}
+	}
+	finalizers := func(name string) []string {
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, secret)).To(Succeed())
+		return secret.Finalizers
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+		}
+		scheme := newTestScheme()
+		k8sClient = newFakeClientBuilder(scheme).WithObjects(
+			ncxInfraCluster,
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds-a", Namespace: "default"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds-b", Namespace: "default"}},
+		).Build()
+		reconciler = &NcxInfraClusterReconciler{Client: k8sClient, Scheme: scheme}
+		clusterScope = &scope.ClusterScope{NcxInfraCluster: ncxInfraCluster, CredentialsSecret: secretRef("creds-a")}
+	})
+
+	It("should hold the secret in use and release the one used before", func() {
+		Expect(reconciler.reconcileCredentialsFinalizer(ctx, clusterScope)).To(Succeed())
+		Expect(finalizers("creds-a")).To(ConsistOf(CredentialsFinalizer))
+		Expect(ncxInfraCluster.Status.CredentialsSecretRef).To(Equal(&corev1.SecretReference{
+			Namespace: "default", Name: "creds-a",
+		}))
+
+		clusterScope.CredentialsSecret = secretRef("creds-b")
+		Expect(reconciler.reconcileCredentialsFinalizer(ctx, clusterScope)).To(Succeed())
+		Expect(finalizers("creds-a")).To(BeEmpty())
+		Expect(finalizers("creds-b")).To(ConsistOf(CredentialsFinalizer))
+		Expect(ncxInfraCluster.Status.CredentialsSecretRef.Name).To(Equal("creds-b"))
+	})
+
+	It("should keep the secret while another cluster uses it", func() {
+		Expect(reconciler.reconcileCredentialsFinalizer(ctx, clusterScope)).To(Succeed())
+		Expect(k8sClient.Create(ctx, &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-cluster", Namespace: "default"},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				Authentication: infrastructurev1.AuthenticationSpec{SecretRef: corev1.SecretReference{Name: "creds-a"}},
+			},
+		})).To(Succeed())
+
+		Expect(reconciler.releaseCredentialsSecret(ctx, ncxInfraCluster, secretRef("creds-a"))).To(Succeed())
+		Expect(finalizers("creds-a")).To(ConsistOf(CredentialsFinalizer))
+
+		Expect(k8sClient.Delete(ctx, &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-cluster", Namespace: "default"},
+		})).To(Succeed())
+		Expect(reconciler.releaseCredentialsSecret(ctx, ncxInfraCluster, secretRef("creds-a"))).To(Succeed())
+		Expect(finalizers("creds-a")).To(BeEmpty())
+	})
+
+	It("should report a missing credentials secret", func() {
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default", UID: "cluster-uid"},
+		}
+		missing := &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "test-cluster", UID: "cluster-uid",
+				}},
+			},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				Authentication: infrastructurev1.AuthenticationSpec{SecretRef: corev1.SecretReference{Name: "deleted"}},
+			},
+		}
+		scheme := newTestScheme()
+		k8sClient = newFakeClientBuilder(scheme).
+			WithObjects(cluster, missing).
+			WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+			Build()
+		reconciler = &NcxInfraClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+		key := types.NamespacedName{Name: "test-cluster", Namespace: "default"}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(scope.ErrCredentialsMissing))
+
+		updated := &infrastructurev1.NcxInfraCluster{}
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(conditions.IsTrue(updated, string(CredentialsMissingCondition))).To(BeTrue())
+		Expect(conditions.GetMessage(updated, string(CredentialsMissingCondition))).To(ContainSubstring("default/deleted"))
+	})
+})
@@ -0,0 +1,262 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// The deletion conditions report, on a NcxInfraCluster being deleted, the
+// progress of the deletion of each kind of NVIDIA Carbide resource.
+const (
+	WarmPoolDeletedCondition    clusterv1.ConditionType = "WarmPoolDeleted"
+	SSHKeyGroupDeletedCondition clusterv1.ConditionType = "SSHKeyGroupDeleted"
+	NSGDeletedCondition         clusterv1.ConditionType = "NetworkSecurityGroupDeleted"
+	VPCPeeringsDeletedCondition clusterv1.ConditionType = "VPCPeeringsDeleted"
+	VPCPrefixesDeletedCondition clusterv1.ConditionType = "VPCPrefixesDeleted"
+	SubnetsDeletedCondition     clusterv1.ConditionType = "SubnetsDeleted"
+	IPBlocksDeletedCondition    clusterv1.ConditionType = "IPBlocksDeleted"
+	VPCDeletedCondition         clusterv1.ConditionType = "VPCDeleted"
+)
+
+// WaitingForMachinesCondition reports a NcxInfraCluster being deleted that
+// keeps its network resources until the NcxInfraMachines of the cluster, whose
+// instances use them, are deleted.
+const WaitingForMachinesCondition clusterv1.ConditionType = "WaitingForMachines"
+
+// The retries of a deletion waiting on other resources back off from
+// deletionRetryMinInterval to deletionRetryMaxInterval.
+const (
+	deletionRetryMinInterval = 10 * time.Second
+	deletionRetryMaxInterval = 5 * time.Minute
+)
+
+// errResourceInUse reports a resource NVIDIA Carbide refuses to delete with
+// 409 Conflict while other resources still use it, or a VPC other clusters share.
+var errResourceInUse = errors.New("in use")
+
+// errDeletionInProgress reports resources whose deletion was requested but
+// has not completed yet.
+var errDeletionInProgress = errors.New("deletion in progress")
+
+// deletionStep deletes a kind of NVIDIA Carbide resources of the cluster once
+// the steps it depends on are done.
+type deletionStep struct {
+	condition clusterv1.ConditionType
+	dependsOn []clusterv1.ConditionType
+	delete    func(ctx context.Context, clusterScope *scope.ClusterScope) error
+}
+
+// deletionSteps returns the deletion steps of the cluster resources: the warm
+// pool instances first, then the resources attached to the VPC, the IP blocks
+// the subnets are carved from, and the VPCs last.
+func (r *NcxInfraClusterReconciler) deletionSteps() []deletionStep {
+	return []deletionStep{
+		{condition: WarmPoolDeletedCondition, delete: r.deleteWarmPool},
+		{
+			condition: SSHKeyGroupDeletedCondition,
+			dependsOn: []clusterv1.ConditionType{WarmPoolDeletedCondition},
+			delete:    r.deleteBreakGlassSSH,
+		},
+		{
+			condition: NSGDeletedCondition,
+			dependsOn: []clusterv1.ConditionType{WarmPoolDeletedCondition},
+			delete:    r.deleteNSG,
+		},
+		{condition: VPCPeeringsDeletedCondition, delete: r.deleteVPCPeerings},
+		{
+			condition: VPCPrefixesDeletedCondition,
+			dependsOn: []clusterv1.ConditionType{WarmPoolDeletedCondition},
+			delete:    r.deleteVPCPrefixes,
+		},
+		{
+			condition: SubnetsDeletedCondition,
+			dependsOn: []clusterv1.ConditionType{WarmPoolDeletedCondition},
+			delete:    r.deleteSubnets,
+		},
+		{
+			condition: IPBlocksDeletedCondition,
+			dependsOn: []clusterv1.ConditionType{SubnetsDeletedCondition, VPCPrefixesDeletedCondition},
+			delete:    r.deleteIPBlocks,
+		},
+		{
+			condition: VPCDeletedCondition,
+			dependsOn: []clusterv1.ConditionType{
+				NSGDeletedCondition, VPCPeeringsDeletedCondition, VPCPrefixesDeletedCondition,
+				SubnetsDeletedCondition, IPBlocksDeletedCondition,
+			},
+			delete: r.deleteVPCs,
+		},
+	}
+}
+
+// deleteClusterResources runs the deletion steps whose dependencies are done,
+// so that a resource failing to delete only holds back the resources that
+// depend on it, and reports the outcome of each step in its condition. The
+// result requeues with backoff while resources are in use or being deleted,
+// and the errors of the failed steps are returned joined.
+func (r *NcxInfraClusterReconciler) deleteClusterResources(
+	ctx context.Context, clusterScope *scope.ClusterScope,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	ncxInfraCluster := clusterScope.NcxInfraCluster
+
+	done := map[clusterv1.ConditionType]bool{}
+	var errs []error
+	var requeueAfter time.Duration
+	for _, step := range r.deletionSteps() {
+		var pending []string
+		for _, dependency := range step.dependsOn {
+			if !done[dependency] {
+				pending = append(pending, string(dependency))
+			}
+		}
+		if len(pending) > 0 {
+			// Unknown rather than false, so that the backoff of the step only
+			// counts the time it waits once attempted
+			conditions.Set(ncxInfraCluster, metav1.Condition{
+				Type:    string(step.condition),
+				Status:  metav1.ConditionUnknown,
+				Reason:  "WaitingForDependencies",
+				Message: "waiting for " + strings.Join(pending, ", "),
+			})
+			continue
+		}
+
+		err := step.delete(ctx, clusterScope)
+		switch {
+		case err == nil:
+			done[step.condition] = true
+			conditions.Set(ncxInfraCluster, metav1.Condition{
+				Type:   string(step.condition),
+				Status: metav1.ConditionTrue,
+				Reason: "Deleted",
+			})
+		case errors.Is(err, errResourceInUse), errors.Is(err, errDeletionInProgress):
+			reason := "Deleting"
+			if errors.Is(err, errResourceInUse) {
+				reason = "InUse"
+			}
+			logger.Info("Waiting to delete resources", "step", step.condition, "reason", err.Error())
+			conditions.Set(ncxInfraCluster, metav1.Condition{
+				Type:    string(step.condition),
+				Status:  metav1.ConditionFalse,
+				Reason:  reason,
+				Message: err.Error(),
+			})
+			retry := deletionRetryInterval(conditions.Get(ncxInfraCluster, string(step.condition)), time.Now())
+			if requeueAfter == 0 || retry < requeueAfter {
+				requeueAfter = retry
+			}
+		default:
+			conditions.Set(ncxInfraCluster, metav1.Condition{
+				Type:    string(step.condition),
+				Status:  metav1.ConditionFalse,
+				Reason:  "DeletionFailed",
+				Message: err.Error(),
+			})
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return ctrl.Result{}, errors.Join(errs...)
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// deletionRetryInterval returns when to retry a deletion step: after as long
+// again as it has already been waiting since its condition turned false,
+// which doubles the interval between the retries, within the retry bounds.
+func deletionRetryInterval(condition *metav1.Condition, now time.Time) time.Duration {
+	if condition == nil {
+		return deletionRetryMinInterval
+	}
+	return min(max(now.Sub(condition.LastTransitionTime.Time), deletionRetryMinInterval), deletionRetryMaxInterval)
+}
+
+// waitForMachines reports the NcxInfraMachines of the cluster still to be
+// deleted in the WaitingForMachines condition, and returns how long to wait
+// before checking them again, zero once they are all gone.
+func (r *NcxInfraClusterReconciler) waitForMachines(
+	ctx context.Context, clusterScope *scope.ClusterScope,
+) (time.Duration, error) {
+	ncxInfraCluster := clusterScope.NcxInfraCluster
+
+	machineList := &infrastructurev1.NcxInfraMachineList{}
+	if err := r.List(ctx, machineList,
+		client.InNamespace(ncxInfraCluster.Namespace),
+		client.MatchingFields{ClusterNameField: clusterScope.Cluster.Name},
+	); err != nil {
+		return 0, fmt.Errorf("failed to list NcxInfraMachines: %w", err)
+	}
+	if len(machineList.Items) == 0 {
+		if conditions.Has(ncxInfraCluster, string(WaitingForMachinesCondition)) {
+			conditions.Set(ncxInfraCluster, metav1.Condition{
+				Type:   string(WaitingForMachinesCondition),
+				Status: metav1.ConditionFalse,
+				Reason: "MachinesDeleted",
+			})
+		}
+		return 0, nil
+	}
+
+	names := make([]string, 0, len(machineList.Items))
+	for i := range machineList.Items {
+		names = append(names, machineList.Items[i].Name)
+	}
+	sort.Strings(names)
+	message := fmt.Sprintf("waiting for %d NcxInfraMachine(s) to be deleted: %s", len(names),
+		strings.Join(names[:min(len(names), maxFailedMachinesInMessage)], ", "))
+	if len(names) > maxFailedMachinesInMessage {
+		message += fmt.Sprintf(" and %d more", len(names)-maxFailedMachinesInMessage)
+	}
+	log.FromContext(ctx).Info("Waiting for the machines of the cluster to be deleted", "machines", len(names))
+	conditions.Set(ncxInfraCluster, metav1.Condition{
+		Type:    string(WaitingForMachinesCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  "MachinesExist",
+		Message: message,
+	})
+	return deletionRetryInterval(conditions.Get(ncxInfraCluster, string(WaitingForMachinesCondition)), time.Now()), nil
+}
+
+// deleteWarmPool deletes the warm pool instances, which hold on to the subnets.
+func (r *NcxInfraClusterReconciler) deleteWarmPool(ctx context.Context, clusterScope *scope.ClusterScope) error {
+	left, err := r.reconcileWarmPool(ctx, clusterScope)
+	if err != nil {
+		return err
+	}
+	if left > 0 {
+		return fmt.Errorf("%w: %d warm pool instances left", errDeletionInProgress, left)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Dependency-aware deletion", func() {
+	var (
+		ctx          context.Context
+		deleted      []string
+		subnetErr    error
+		mockClient   *testutil.MockNcxInfraClient
+		clusterScope *scope.ClusterScope
+		reconciler   *NcxInfraClusterReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		deleted = nil
+		subnetErr = nil
+		deleteFn := func(kind string) func(ctx context.Context, org, id string) (*http.Response, error) {
+			return func(ctx context.Context, org, id string) (*http.Response, error) {
+				if kind == "subnet" && subnetErr != nil {
+					return testutil.MockHTTPResponse(http.StatusInternalServerError), subnetErr
+				}
+				deleted = append(deleted, kind)
+				return testutil.MockHTTPResponse(http.StatusNoContent), nil
+			}
+		}
+		mockClient = &testutil.MockNcxInfraClient{
+			DeleteNetworkSecurityGroupFunc: deleteFn("nsg"),
+			DeleteVpcPeeringFunc:           deleteFn("peering"),
+			DeleteSubnetFunc:               deleteFn("subnet"),
+			DeleteAllocationFunc:           deleteFn("allocation"),
+			DeleteIpblockFunc:              deleteFn("ipblock"),
+			DeleteVPCFunc:                  deleteFn("vpc"),
+		}
+		clusterScope = &scope.ClusterScope{
+			Cluster:        &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+			NcxInfraClient: mockClient,
+			OrgName:        "test-org",
+			NcxInfraCluster: &infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-cluster",
+					Namespace:  "default",
+					Finalizers: []string{NcxInfraClusterFinalizer},
+				},
+				Status: infrastructurev1.NcxInfraClusterStatus{
+					NetworkStatus: infrastructurev1.NetworkStatus{
+						VPC:         &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"},
+						NSG:         &infrastructurev1.NSGStatus{NetworkResourceStatus: infrastructurev1.NetworkResourceStatus{ID: "nsg-uuid"}},
+						VPCPeerings: []infrastructurev1.NetworkResourceStatus{{Name: "peer-vpc", ID: "peering-uuid"}},
+						Subnets:     []infrastructurev1.NetworkResourceStatus{{Name: "control-plane", ID: "subnet-uuid"}},
+						IPBlocks: []infrastructurev1.IPBlockStatus{{
+							CIDR: "10.0.0.0/16", IPBlockID: "ipblock-uuid", AllocationID: "allocation-uuid",
+						}},
+					},
+				},
+			},
+		}
+		reconciler = &NcxInfraClusterReconciler{
+			Client:         newFakeClientBuilder(newTestScheme()).Build(),
+			NcxInfraClient: mockClient,
+		}
+	})
+
+	It("should delete the resources not depending on a failed one", func() {
+		subnetErr = fmt.Errorf("internal error")
+
+		_, err := reconciler.reconcileDelete(ctx, clusterScope)
+		Expect(err).To(MatchError(ContainSubstring("failed to delete subnet subnet-uuid")))
+		Expect(deleted).To(Equal([]string{"nsg", "peering"}))
+
+		ncxInfraCluster := clusterScope.NcxInfraCluster
+		Expect(conditions.IsTrue(ncxInfraCluster, string(NSGDeletedCondition))).To(BeTrue())
+		Expect(conditions.IsTrue(ncxInfraCluster, string(VPCPeeringsDeletedCondition))).To(BeTrue())
+		Expect(conditions.GetReason(ncxInfraCluster, string(SubnetsDeletedCondition))).To(Equal("DeletionFailed"))
+		Expect(conditions.IsUnknown(ncxInfraCluster, string(IPBlocksDeletedCondition))).To(BeTrue())
+		Expect(conditions.GetMessage(ncxInfraCluster, string(VPCDeletedCondition))).
+			To(Equal("waiting for SubnetsDeleted, IPBlocksDeleted"))
+		Expect(ncxInfraCluster.Finalizers).To(ContainElement(NcxInfraClusterFinalizer))
+
+		subnetErr = nil
+		result, err := reconciler.reconcileDelete(ctx, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsZero()).To(BeTrue())
+		Expect(deleted).To(Equal([]string{"nsg", "peering", "subnet", "allocation", "ipblock", "vpc"}))
+		Expect(conditions.IsTrue(ncxInfraCluster, string(VPCDeletedCondition))).To(BeTrue())
+		Expect(ncxInfraCluster.Finalizers).NotTo(ContainElement(NcxInfraClusterFinalizer))
+	})
+
+	It("should keep the network resources until the machines of the cluster are deleted", func() {
+		machine := &infrastructurev1.NcxInfraMachine{ObjectMeta: metav1.ObjectMeta{
+			Name:      "worker-0",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+		}}
+		reconciler.Client = newFakeClientBuilder(newTestScheme()).WithObjects(machine).Build()
+
+		result, err := reconciler.reconcileDelete(ctx, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(deletionRetryMinInterval))
+		Expect(deleted).To(BeEmpty())
+		Expect(conditions.IsTrue(clusterScope.NcxInfraCluster, string(WaitingForMachinesCondition))).To(BeTrue())
+		Expect(conditions.GetMessage(clusterScope.NcxInfraCluster, string(WaitingForMachinesCondition))).
+			To(Equal("waiting for 1 NcxInfraMachine(s) to be deleted: worker-0"))
+
+		Expect(reconciler.Delete(ctx, machine)).To(Succeed())
+		_, err = reconciler.reconcileDelete(ctx, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(ContainElement("subnet"))
+		Expect(conditions.IsFalse(clusterScope.NcxInfraCluster, string(WaitingForMachinesCondition))).To(BeTrue())
+		Expect(clusterScope.NcxInfraCluster.Finalizers).NotTo(ContainElement(NcxInfraClusterFinalizer))
+	})
+
+	It("should requeue while a resource is in use", func() {
+		mockClient.DeleteSubnetFunc = func(ctx context.Context, org, id string) (*http.Response, error) {
+			return testutil.MockHTTPResponse(http.StatusConflict), fmt.Errorf("subnet still has instance")
+		}
+
+		result, err := reconciler.reconcileDelete(ctx, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(deletionRetryMinInterval))
+		Expect(conditions.GetReason(clusterScope.NcxInfraCluster, string(SubnetsDeletedCondition))).To(Equal("InUse"))
+		Expect(clusterScope.NcxInfraCluster.Status.NetworkStatus.Subnets[0].LastError).
+			To(ContainSubstring("subnet still has instance"))
+	})
+})
+
+var _ = Describe("deletionRetryInterval", func() {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	waitingSince := func(d time.Duration) *metav1.Condition {
+		return &metav1.Condition{LastTransitionTime: metav1.NewTime(now.Add(-d))}
+	}
+
+	It("should wait as long again as the step has been waiting, within bounds", func() {
+		Expect(deletionRetryInterval(nil, now)).To(Equal(deletionRetryMinInterval))
+		Expect(deletionRetryInterval(waitingSince(0), now)).To(Equal(deletionRetryMinInterval))
+		Expect(deletionRetryInterval(waitingSince(40*time.Second), now)).To(Equal(40 * time.Second))
+		Expect(deletionRetryInterval(waitingSince(time.Hour), now)).To(Equal(deletionRetryMaxInterval))
+	})
+})
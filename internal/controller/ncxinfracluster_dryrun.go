@@ -0,0 +1,190 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// DryRunCondition reports the NVIDIA Carbide changes planned in dry-run mode.
+const DryRunCondition clusterv1.ConditionType = "DryRun"
+
+// dryRunRefreshInterval paces the refresh of the plans in dry-run mode. The
+// machines are not notified when the annotation is removed from their cluster.
+const dryRunRefreshInterval = time.Minute
+
+// isDryRun returns whether one of the objects carries the dry-run annotation.
+func isDryRun(objs ...metav1.Object) bool {
+	for _, obj := range objs {
+		if _, ok := obj.GetAnnotations()[infrastructurev1.DryRunAnnotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setDryRunCondition reports the planned actions in the DryRun condition. It
+// returns the message of the condition and whether the plan changed since the
+// last reconcile.
+func setDryRunCondition(obj conditions.Setter, plan []string) (string, bool) {
+	condition := metav1.Condition{
+		Type:    string(DryRunCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  "NoChanges",
+		Message: "No NVIDIA Carbide changes planned",
+	}
+	if len(plan) > 0 {
+		condition.Reason = "ChangesPlanned"
+		condition.Message = fmt.Sprintf("%d NVIDIA Carbide change(s) planned: %s", len(plan), strings.Join(plan, "; "))
+	}
+	previous := conditions.Get(obj, string(DryRunCondition))
+	conditions.Set(obj, condition)
+	return condition.Message, previous == nil || previous.Message != condition.Message
+}
+
+// reconcileDryRun reports the changes the reconciliation of the cluster would
+// make to its NVIDIA Carbide resources, without making them.
+func (r *NcxInfraClusterReconciler) reconcileDryRun(
+	ctx context.Context, ncxInfraCluster *infrastructurev1.NcxInfraCluster,
+) ctrl.Result {
+	plan := planClusterChanges(ncxInfraCluster)
+	message, changed := setDryRunCondition(ncxInfraCluster, plan)
+	if changed {
+		log.FromContext(ctx).Info("Dry-run mode, not applying the NVIDIA Carbide changes", "plan", plan)
+		r.recordEvent(ncxInfraCluster, "DryRunPlan", "%s", message)
+	}
+	return ctrl.Result{RequeueAfter: dryRunRefreshInterval}
+}
+
+// planClusterChanges lists the NVIDIA Carbide resources the reconciliation of
+// the cluster would create, or delete once the cluster is deleted. The plan is
+// computed from the status, the resources recorded there are assumed to exist.
+func planClusterChanges(ncxInfraCluster *infrastructurev1.NcxInfraCluster) []string {
+	var plan []string
+	if !ncxInfraCluster.DeletionTimestamp.IsZero() {
+		for _, entry := range buildTeardownReport(ncxInfraCluster, nil).Resources {
+			if entry.Action != TeardownActionDelete {
+				continue
+			}
+			if entry.Name != "" {
+				plan = append(plan, fmt.Sprintf("delete %s %s (%s)", entry.Kind, entry.Name, entry.ID))
+			} else {
+				plan = append(plan, fmt.Sprintf("delete %s %s", entry.Kind, entry.ID))
+			}
+		}
+		return plan
+	}
+
+	spec := ncxInfraCluster.Spec
+	network := ncxInfraCluster.Status.NetworkStatus
+	findIPBlock := func(subnet, cidr string) infrastructurev1.IPBlockStatus {
+		for _, ipBlock := range network.IPBlocks {
+			if ipBlock.Subnet == subnet && ipBlock.CIDR == cidr {
+				return ipBlock
+			}
+		}
+		return infrastructurev1.IPBlockStatus{}
+	}
+	hasIPBlock := func(subnet, cidr string) bool {
+		return findIPBlock(subnet, cidr).ChildIPBlockID != ""
+	}
+
+	// The clusters created by earlier releases keep their single IP block
+	if !hasIPBlock("", "") {
+		supernets := clusterSupernets(spec)
+		allocations := map[netip.Prefix][]netip.Prefix{}
+		for _, cidr := range supernetCIDRs(spec) {
+			if supernet, ok := supernetOf(supernets, cidr); ok {
+				allocations[supernet] = append(allocations[supernet], cidr)
+			}
+		}
+		for _, supernet := range supernets {
+			name := fmt.Sprintf("%s-%s", ncxInfraCluster.Name, supernetName(supernet))
+			if len(allocations[supernet]) == 0 {
+				if !hasIPBlock("", supernet.String()) {
+					plan = append(plan, fmt.Sprintf("create IP block %s (%s) and its allocation", name, supernet))
+				}
+				continue
+			}
+			if findIPBlock("", supernet.String()).IPBlockID == "" {
+				plan = append(plan, fmt.Sprintf("create IP block %s (%s)", name, supernet))
+			}
+			for _, cidr := range allocations[supernet] {
+				if !hasIPBlock("", cidr.String()) {
+					plan = append(plan, fmt.Sprintf("allocate %s from IP block %s", cidr, name))
+				}
+			}
+		}
+	}
+	if network.VPCID() == "" {
+		plan = append(plan, fmt.Sprintf("create VPC %s", spec.VPC.Name))
+	}
+	plannedSiteVPCs := map[infrastructurev1.SiteReference]bool{}
+	for _, subnet := range spec.Subnets {
+		// A site referenced by name is only known to differ from the site of
+		// the cluster once resolved, its subnets are planned as such
+		inOtherSite := subnet.SiteRef != nil && *subnet.SiteRef != spec.SiteRef
+		if inOtherSite && network.SubnetID(subnet.Name) == "" && !plannedSiteVPCs[*subnet.SiteRef] &&
+			(subnet.SiteRef.ID == "" || network.SiteVPCID(subnet.SiteRef.ID) == "") {
+			plannedSiteVPCs[*subnet.SiteRef] = true
+			plan = append(plan, fmt.Sprintf("create VPC %s in site %s",
+				siteVPCName(spec.VPC.Name, cmp.Or(subnet.SiteRef.ID, subnet.SiteRef.Name)),
+				cmp.Or(subnet.SiteRef.ID, subnet.SiteRef.Name)))
+		}
+		if subnet.Egress == infrastructurev1.SubnetEgressPublic && !hasIPBlock(subnet.Name, "") {
+			plan = append(plan, fmt.Sprintf("create Public IP block %s-%s (%s) and its allocation",
+				ncxInfraCluster.Name, subnet.Name, subnet.CIDR))
+		} else if inOtherSite && !hasIPBlock(subnet.Name, "") {
+			plan = append(plan, fmt.Sprintf("create IP block %s-%s (%s) and its allocation",
+				ncxInfraCluster.Name, subnet.Name, subnet.CIDR))
+		}
+		if network.SubnetID(subnet.Name) == "" {
+			plan = append(plan, fmt.Sprintf("create subnet %s (%s)", subnet.Name, subnet.CIDR))
+		}
+	}
+	for _, prefix := range spec.VPCPrefixes {
+		if network.VPCPrefixID(prefix.Name) == "" {
+			plan = append(plan, fmt.Sprintf("create VPC prefix %s (%s)", prefix.Name, prefix.CIDR))
+		}
+	}
+	if nsg := spec.VPC.NetworkSecurityGroup; nsg != nil && (network.NSG == nil || network.NSG.ID == "") {
+		plan = append(plan, fmt.Sprintf("create NSG %s with %d rule(s)", nsg.Name, len(nsg.Rules)))
+	}
+	peeringIDs := map[string]string{}
+	for _, peering := range network.VPCPeerings {
+		peeringIDs[peering.Name] = peering.ID
+	}
+	for _, peering := range spec.VPCPeerings {
+		if peeringIDs[peering.PeerVPCID] == "" {
+			plan = append(plan, fmt.Sprintf("create VPC peering with VPC %s", peering.PeerVPCID))
+		}
+	}
+	return plan
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+)
+
+var _ = Describe("Dry-run mode", func() {
+	const (
+		clusterName = "test-cluster"
+		namespace   = "default"
+	)
+
+	var ncxInfraCluster *infrastructurev1.NcxInfraCluster
+
+	BeforeEach(func() {
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        clusterName,
+				Namespace:   namespace,
+				Annotations: map[string]string{infrastructurev1.DryRunAnnotation: ""},
+				Finalizers:  []string{NcxInfraClusterFinalizer},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       clusterName,
+					UID:        "cluster-uid",
+				}},
+			},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				SiteRef:  infrastructurev1.SiteReference{ID: "site-uuid"},
+				TenantID: "tenant-uuid",
+				VPC: infrastructurev1.VPCSpec{
+					Name:                 "test-vpc",
+					NetworkSecurityGroup: &infrastructurev1.NSGSpec{Name: "test-nsg"},
+				},
+				Subnets: []infrastructurev1.SubnetSpec{
+					{Name: "control-plane", CIDR: "10.0.0.0/26"},
+					{Name: "ingress", CIDR: "192.0.2.0/28", Egress: infrastructurev1.SubnetEgressPublic},
+				},
+				VPCPeerings: []infrastructurev1.VPCPeeringSpec{{PeerVPCID: "peer-vpc"}},
+			},
+		}
+	})
+
+	It("should report the planned changes without calling the mutating APIs", func() {
+		ctx := context.Background()
+		scheme := newTestScheme()
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: namespace, UID: "cluster-uid"},
+		}
+		k8sClient := newFakeClientBuilder(scheme).
+			WithObjects(cluster, ncxInfraCluster).
+			WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+			Build()
+		mockClient := &testutil.MockNcxInfraClient{
+			CreateVPCFunc: func(ctx context.Context, org string, req nico.VpcCreateRequest) (*nico.VPC, *http.Response, error) {
+				Fail("VPC created in dry-run mode")
+				return nil, nil, nil
+			},
+			CreateIpblockFunc: func(
+				ctx context.Context, org string, req nico.IpBlockCreateRequest,
+			) (*nico.IpBlock, *http.Response, error) {
+				Fail("IP block created in dry-run mode")
+				return nil, nil, nil
+			},
+		}
+		reconciler := &NcxInfraClusterReconciler{
+			Client:         k8sClient,
+			Scheme:         scheme,
+			NcxInfraClient: mockClient,
+			OrgName:        "test-org",
+		}
+
+		key := types.NamespacedName{Name: clusterName, Namespace: namespace}
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(dryRunRefreshInterval))
+
+		updated := &infrastructurev1.NcxInfraCluster{}
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.Ready).To(BeFalse())
+		condition := conditions.Get(updated, string(DryRunCondition))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal("ChangesPlanned"))
+		Expect(condition.Message).To(HavePrefix("8 NVIDIA Carbide change(s) planned"))
+		Expect(condition.Message).To(ContainSubstring("create IP block test-cluster-10-0-0-0-16 (10.0.0.0/16)"))
+		Expect(condition.Message).To(ContainSubstring("allocate 10.0.0.0/26 from IP block test-cluster-10-0-0-0-16"))
+		Expect(condition.Message).To(ContainSubstring("create VPC test-vpc"))
+		Expect(condition.Message).To(ContainSubstring("create subnet control-plane (10.0.0.0/26)"))
+		Expect(condition.Message).To(ContainSubstring("create Public IP block test-cluster-ingress (192.0.2.0/28)"))
+
+		// Removing the annotation applies the changes and drops the condition
+		delete(updated.Annotations, infrastructurev1.DryRunAnnotation)
+		Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+		mockClient.CreateIpblockFunc = func(
+			ctx context.Context, org string, req nico.IpBlockCreateRequest,
+		) (*nico.IpBlock, *http.Response, error) {
+			return nil, nil, fmt.Errorf("quota exceeded")
+		}
+		_, _ = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(conditions.Has(updated, string(DryRunCondition))).To(BeFalse())
+	})
+
+	It("should only plan the resources missing from the status", func() {
+		ncxInfraCluster.Status.NetworkStatus = infrastructurev1.NetworkStatus{
+			VPC:      &infrastructurev1.NetworkResourceStatus{Name: "test-vpc", ID: "vpc-uuid"},
+			IPBlocks: []infrastructurev1.IPBlockStatus{{IPBlockID: "ipblock", ChildIPBlockID: "child"}},
+			Subnets:  []infrastructurev1.NetworkResourceStatus{{Name: "control-plane", ID: "subnet-uuid"}},
+		}
+
+		Expect(planClusterChanges(ncxInfraCluster)).To(Equal([]string{
+			"create Public IP block test-cluster-ingress (192.0.2.0/28) and its allocation",
+			"create subnet ingress (192.0.2.0/28)",
+			"create NSG test-nsg with 0 rule(s)",
+			"create VPC peering with VPC peer-vpc",
+		}))
+	})
+
+	It("should plan the deletion of the resources of a deleted cluster", func() {
+		now := metav1.Now()
+		ncxInfraCluster.DeletionTimestamp = &now
+		ncxInfraCluster.Status.NetworkStatus = infrastructurev1.NetworkStatus{
+			VPC:      &infrastructurev1.NetworkResourceStatus{Name: "test-vpc", ID: "vpc-uuid"},
+			IPBlocks: []infrastructurev1.IPBlockStatus{{IPBlockID: "ipblock", ChildIPBlockID: "child"}},
+			Subnets:  []infrastructurev1.NetworkResourceStatus{{Name: "control-plane", ID: "subnet-uuid"}},
+		}
+
+		Expect(planClusterChanges(ncxInfraCluster)).To(Equal([]string{
+			"delete Subnet control-plane (subnet-uuid)",
+			"delete IPBlock child (child)",
+			"delete IPBlock parent (ipblock)",
+			"delete VPC test-vpc (vpc-uuid)",
+		}))
+
+		ncxInfraCluster.Status.NetworkStatus = infrastructurev1.NetworkStatus{}
+		Expect(planClusterChanges(ncxInfraCluster)).To(BeEmpty())
+	})
+
+	It("should plan the instance of a machine", func() {
+		machine := &infrastructurev1.NcxInfraMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: namespace},
+			Spec: infrastructurev1.NcxInfraMachineSpec{
+				InstanceType: infrastructurev1.InstanceTypeSpec{ID: "gb200"},
+			},
+		}
+		Expect(planMachineChanges(machine)).To(Equal([]string{"create instance worker-0 of instance type gb200"}))
+
+		machine.Spec.InstanceType.MachineID = "machine-uuid"
+		Expect(planMachineChanges(machine)).To(Equal([]string{"create instance worker-0 on machine machine-uuid"}))
+
+		machine.Status.InstanceID = "instance-uuid"
+		Expect(planMachineChanges(machine)).To(BeEmpty())
+
+		now := metav1.Now()
+		machine.DeletionTimestamp = &now
+		Expect(planMachineChanges(machine)).To(Equal([]string{"delete instance instance-uuid"}))
+	})
+
+	It("should apply to the machines of a cluster in dry-run mode", func() {
+		Expect(isDryRun(&infrastructurev1.NcxInfraCluster{}, &infrastructurev1.NcxInfraMachine{})).To(BeFalse())
+		Expect(isDryRun(ncxInfraCluster, &infrastructurev1.NcxInfraMachine{})).To(BeTrue())
+		Expect(isDryRun(&infrastructurev1.NcxInfraCluster{}, &infrastructurev1.NcxInfraMachine{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{infrastructurev1.DryRunAnnotation: "true"}},
+		})).To(BeTrue())
+	})
+})
@@ -0,0 +1,220 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// resourceExportKey is the ConfigMap key holding the resource export.
+const resourceExportKey = "resources.yaml"
+
+// resourceExportName returns the name of the ConfigMap holding the resource
+// export of a cluster.
+func resourceExportName(clusterName string) string {
+	return clusterName + "-resource-export"
+}
+
+// resourceExport maps a cluster and its machines to their NVIDIA Carbide resources.
+type resourceExport struct {
+	Cluster  string                         `json:"cluster"`
+	Network  infrastructurev1.NetworkStatus `json:"network"`
+	Machines []resourceExportMachine        `json:"machines,omitempty"`
+}
+
+// resourceExportMachine maps a machine to its instance.
+type resourceExportMachine struct {
+	Name       string `json:"name"`
+	ProviderID string `json:"providerID,omitempty"`
+	InstanceID string `json:"instanceID"`
+	MachineID  string `json:"machineID,omitempty"`
+}
+
+// buildResourceExport exports the network status of the cluster and the
+// instances of its machines, sorted by name.
+func buildResourceExport(
+	ncxInfraCluster *infrastructurev1.NcxInfraCluster, machines []infrastructurev1.NcxInfraMachine,
+) resourceExport {
+	export := resourceExport{
+		Cluster: ncxInfraCluster.Name,
+		Network: ncxInfraCluster.Status.NetworkStatus,
+	}
+	for i := range machines {
+		machine := &machines[i]
+		if machine.Status.InstanceID == "" {
+			continue
+		}
+		export.Machines = append(export.Machines, resourceExportMachine{
+			Name:       machine.Name,
+			ProviderID: ptr.Deref(machine.Spec.ProviderID, ""),
+			InstanceID: machine.Status.InstanceID,
+			MachineID:  machine.Status.MachineID,
+		})
+	}
+	sort.Slice(export.Machines, func(i, j int) bool { return export.Machines[i].Name < export.Machines[j].Name })
+	return export
+}
+
+// reconcileResourceExport writes the resource export of the cluster. The
+// ConfigMap has no owner reference: restored from a backup next to a recreated
+// NcxInfraCluster, it would be garbage collected for referencing the UID of
+// the lost one. It is deleted with the cluster instead.
+func (r *NcxInfraClusterReconciler) reconcileResourceExport(
+	ctx context.Context, clusterScope *scope.ClusterScope,
+) error {
+	ncxInfraCluster := clusterScope.NcxInfraCluster
+
+	machineList := &infrastructurev1.NcxInfraMachineList{}
+	if err := r.List(ctx, machineList,
+		client.InNamespace(ncxInfraCluster.Namespace),
+		client.MatchingFields{ClusterNameField: clusterScope.Cluster.Name},
+	); err != nil {
+		return fmt.Errorf("failed to list NcxInfraMachines: %w", err)
+	}
+
+	data, err := yaml.Marshal(buildResourceExport(ncxInfraCluster, machineList.Items))
+	if err != nil {
+		return fmt.Errorf("failed to encode resource export: %w", err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceExportName(ncxInfraCluster.Name),
+			Namespace: ncxInfraCluster.Namespace,
+		},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[clusterv1.ClusterNameLabel] = clusterScope.Cluster.Name
+		configMap.Data = map[string]string{resourceExportKey: string(data)}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write resource export: %w", err)
+	}
+	if result != controllerutil.OperationResultNone {
+		log.FromContext(ctx).Info("Updated resource export", "configMap", configMap.Name, "result", result)
+	}
+	return nil
+}
+
+// deleteResourceExport deletes the resource export of a deleted cluster.
+func (r *NcxInfraClusterReconciler) deleteResourceExport(
+	ctx context.Context, ncxInfraCluster *infrastructurev1.NcxInfraCluster,
+) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceExportName(ncxInfraCluster.Name),
+			Namespace: ncxInfraCluster.Namespace,
+		},
+	}
+	if err := r.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete resource export: %w", err)
+	}
+	return nil
+}
+
+// getResourceExport reads the resource export of a cluster, nil when there is none.
+func getResourceExport(ctx context.Context, c client.Reader, namespace, clusterName string) (*resourceExport, error) {
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: namespace, Name: resourceExportName(clusterName)}
+	if err := c.Get(ctx, key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get resource export: %w", err)
+	}
+
+	export := &resourceExport{}
+	if err := yaml.Unmarshal([]byte(configMap.Data[resourceExportKey]), export); err != nil {
+		return nil, fmt.Errorf("failed to decode resource export %s: %w", key.Name, err)
+	}
+	if export.Cluster != clusterName {
+		return nil, fmt.Errorf("resource export %s is for cluster %q", key.Name, export.Cluster)
+	}
+	return export, nil
+}
+
+// restoreFromResourceExport restores the network status of a cluster without
+// any, such as a cluster recreated after the management cluster was rebuilt,
+// from its resource export, so that its NVIDIA Carbide resources are found
+// again instead of being created anew. It returns whether the status was restored.
+func (r *NcxInfraClusterReconciler) restoreFromResourceExport(
+	ctx context.Context, clusterScope *scope.ClusterScope,
+) (bool, error) {
+	ncxInfraCluster := clusterScope.NcxInfraCluster
+	network := ncxInfraCluster.Status.NetworkStatus
+	if network.VPC != nil || len(network.IPBlocks) > 0 || len(network.Subnets) > 0 {
+		return false, nil
+	}
+
+	export, err := getResourceExport(ctx, r.Client, ncxInfraCluster.Namespace, ncxInfraCluster.Name)
+	if err != nil || export == nil {
+		return false, err
+	}
+	ncxInfraCluster.Status.NetworkStatus = export.Network
+	log.FromContext(ctx).Info("Restored the network status from the resource export")
+	r.recordEvent(ncxInfraCluster, "StatusRestored",
+		"Restored the network status from ConfigMap %s", resourceExportName(ncxInfraCluster.Name))
+	return true, nil
+}
+
+// restoreInstanceFromExport restores the instance of a machine without any
+// from the resource export of its cluster. It returns whether it was restored.
+func (r *NcxInfraMachineReconciler) restoreInstanceFromExport(
+	ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope,
+) (bool, error) {
+	export, err := getResourceExport(ctx, r.Client,
+		clusterScope.NcxInfraCluster.Namespace, clusterScope.NcxInfraCluster.Name)
+	if err != nil || export == nil {
+		return false, err
+	}
+
+	machine := machineScope.NcxInfraMachine
+	for _, exported := range export.Machines {
+		if exported.Name != machine.Name {
+			continue
+		}
+		machineScope.SetInstanceID(exported.InstanceID)
+		machineScope.SetMachineID(exported.MachineID)
+		if machine.Spec.ProviderID == nil && exported.ProviderID != "" {
+			machine.Spec.ProviderID = ptr.To(exported.ProviderID)
+		}
+		log.FromContext(ctx).Info("Restored the instance from the resource export", "instanceID", exported.InstanceID)
+		r.recordEvent(machine, corev1.EventTypeNormal, "InstanceRestored",
+			"Restored instance %s from ConfigMap %s", exported.InstanceID, resourceExportName(export.Cluster))
+		return true, nil
+	}
+	return false, nil
+}
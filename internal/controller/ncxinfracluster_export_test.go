@@ -0,0 +1,154 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Resource export", func() {
+	var (
+		ctx          context.Context
+		k8sClient    client.Client
+		recorder     *record.FakeRecorder
+		clusterScope *scope.ClusterScope
+		reconciler   *NcxInfraClusterReconciler
+	)
+
+	network := infrastructurev1.NetworkStatus{
+		VPC:      &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"},
+		Subnets:  []infrastructurev1.NetworkResourceStatus{{Name: "control-plane", ID: "subnet-uuid"}},
+		IPBlocks: []infrastructurev1.IPBlockStatus{{CIDR: "10.0.0.0/16", IPBlockID: "ipblock-uuid"}},
+	}
+
+	newClusterScope := func(status infrastructurev1.NetworkStatus) *scope.ClusterScope {
+		return &scope.ClusterScope{
+			Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+			NcxInfraCluster: &infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				Status:     infrastructurev1.NcxInfraClusterStatus{NetworkStatus: status},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		machines := []client.Object{
+			&infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "worker-1",
+					Namespace: "default",
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+				},
+				Spec: infrastructurev1.NcxInfraMachineSpec{
+					ProviderID: testutil.Ptr("nico://test-org/tenant/site/instance-1"),
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{InstanceID: "instance-1", MachineID: "machine-1"},
+			},
+			&infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "worker-2",
+					Namespace: "default",
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+				},
+			},
+		}
+		k8sClient = newFakeClientBuilder(newTestScheme()).WithObjects(machines...).Build()
+		recorder = record.NewFakeRecorder(10)
+		clusterScope = newClusterScope(network)
+		reconciler = &NcxInfraClusterReconciler{Client: k8sClient, Recorder: recorder}
+	})
+
+	It("should export the resources and restore them into an empty status", func() {
+		Expect(reconciler.reconcileResourceExport(ctx, clusterScope)).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test-cluster-resource-export"},
+			configMap)).To(Succeed())
+		Expect(configMap.OwnerReferences).To(BeEmpty())
+		Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "test-cluster"))
+		Expect(configMap.Data[resourceExportKey]).To(ContainSubstring("vpc-uuid"))
+
+		export, err := getResourceExport(ctx, k8sClient, "default", "test-cluster")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(export.Machines).To(Equal([]resourceExportMachine{{
+			Name:       "worker-1",
+			ProviderID: "nico://test-org/tenant/site/instance-1",
+			InstanceID: "instance-1",
+			MachineID:  "machine-1",
+		}}))
+
+		rebuilt := newClusterScope(infrastructurev1.NetworkStatus{})
+		restored, err := reconciler.restoreFromResourceExport(ctx, rebuilt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored).To(BeTrue())
+		Expect(rebuilt.NcxInfraCluster.Status.NetworkStatus).To(Equal(network))
+		Expect(recorder.Events).To(Receive(ContainSubstring("StatusRestored")))
+
+		Expect(reconciler.deleteResourceExport(ctx, rebuilt.NcxInfraCluster)).To(Succeed())
+		Expect(apierrors.IsNotFound(k8sClient.Get(ctx,
+			client.ObjectKey{Namespace: "default", Name: "test-cluster-resource-export"}, configMap))).To(BeTrue())
+	})
+
+	It("should not overwrite the status of a cluster with resources", func() {
+		Expect(reconciler.reconcileResourceExport(ctx, clusterScope)).To(Succeed())
+
+		current := newClusterScope(infrastructurev1.NetworkStatus{
+			VPC: &infrastructurev1.NetworkResourceStatus{ID: "other-vpc-uuid"},
+		})
+		restored, err := reconciler.restoreFromResourceExport(ctx, current)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored).To(BeFalse())
+		Expect(current.NcxInfraCluster.Status.NetworkStatus.VPC.ID).To(Equal("other-vpc-uuid"))
+	})
+
+	It("should restore the instance of a machine", func() {
+		Expect(reconciler.reconcileResourceExport(ctx, clusterScope)).To(Succeed())
+
+		machineScope := &scope.MachineScope{NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Namespace: "default"},
+		}}
+		machineReconciler := &NcxInfraMachineReconciler{Client: k8sClient, Recorder: recorder}
+		restored, err := machineReconciler.restoreInstanceFromExport(ctx, machineScope, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored).To(BeTrue())
+		Expect(machineScope.InstanceID()).To(Equal("instance-1"))
+		Expect(machineScope.MachineID()).To(Equal("machine-1"))
+		Expect(machineScope.NcxInfraMachine.Spec.ProviderID).To(HaveValue(Equal("nico://test-org/tenant/site/instance-1")))
+		Expect(recorder.Events).To(Receive(ContainSubstring("InstanceRestored")))
+
+		machineScope.NcxInfraMachine = &infrastructurev1.NcxInfraMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-2", Namespace: "default"},
+		}
+		restored, err = machineReconciler.restoreInstanceFromExport(ctx, machineScope, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored).To(BeFalse())
+	})
+})
@@ -0,0 +1,311 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// PreflightSucceededCondition reports the outcome of the checks requested
+// through the preflight annotation.
+const PreflightSucceededCondition clusterv1.ConditionType = "PreflightSucceeded"
+
+// preflightRetryInterval paces the preflight checks while they fail.
+const preflightRetryInterval = time.Minute
+
+// preflightRequirements are the NVIDIA Carbide resources the machines of a
+// cluster need, collected from its NcxInfraMachines and NcxInfraMachineTemplates.
+type preflightRequirements struct {
+	// instanceTypes counts the machines waiting for an instance, by instance type ID
+	instanceTypes map[string]int
+	machineIDs    []string
+	sshKeyGroups  []string
+}
+
+// add records the requirements of a machine spec. pending is true when the
+// machine still needs an instance.
+func (p *preflightRequirements) add(spec infrastructurev1.NcxInfraMachineSpec, pending bool) {
+	if id := spec.InstanceType.ID; id != "" {
+		if pending {
+			p.instanceTypes[id]++
+		} else if _, ok := p.instanceTypes[id]; !ok {
+			p.instanceTypes[id] = 0
+		}
+	}
+	if id := spec.InstanceType.MachineID; id != "" && !slices.Contains(p.machineIDs, id) {
+		p.machineIDs = append(p.machineIDs, id)
+	}
+	for _, id := range spec.SSHKeyGroups {
+		if !slices.Contains(p.sshKeyGroups, id) {
+			p.sshKeyGroups = append(p.sshKeyGroups, id)
+		}
+	}
+}
+
+// reconcilePreflight runs the checks requested through the preflight
+// annotation and reports them in the PreflightSucceeded condition. It returns
+// false while the checks fail, and removes the annotation once they pass.
+func (r *NcxInfraClusterReconciler) reconcilePreflight(
+	ctx context.Context, clusterScope *scope.ClusterScope,
+) (bool, error) {
+	logger := log.FromContext(ctx)
+	ncxInfraCluster := clusterScope.NcxInfraCluster
+
+	requirements, err := r.preflightRequirements(ctx, clusterScope)
+	if err != nil {
+		return false, err
+	}
+
+	failures := runPreflightChecks(ctx, clusterScope, requirements)
+	if len(failures) > 0 {
+		message := strings.Join(failures, "; ")
+		if !conditions.IsFalse(ncxInfraCluster, string(PreflightSucceededCondition)) {
+			r.recordEvent(ncxInfraCluster, "PreflightFailed", "Preflight checks failed: %s", message)
+		}
+		logger.Info("Preflight checks failed, not reconciling the NVIDIA Carbide resources", "failures", failures)
+		conditions.Set(ncxInfraCluster, metav1.Condition{
+			Type:    string(PreflightSucceededCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "PreflightFailed",
+			Message: message,
+		})
+		return false, nil
+	}
+
+	message := requirements.summary()
+	logger.Info("Preflight checks passed")
+	r.recordEvent(ncxInfraCluster, "PreflightSucceeded", "%s", message)
+	conditions.Set(ncxInfraCluster, metav1.Condition{
+		Type:    string(PreflightSucceededCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  "PreflightSucceeded",
+		Message: message,
+	})
+	delete(ncxInfraCluster.Annotations, infrastructurev1.PreflightAnnotation)
+	return true, nil
+}
+
+// summary describes the resources validated by the preflight checks.
+func (p *preflightRequirements) summary() string {
+	return fmt.Sprintf("Credentials, site, IP space, %d instance type(s), %d machine(s) and %d SSH key group(s) validated",
+		len(p.instanceTypes), len(p.machineIDs), len(p.sshKeyGroups))
+}
+
+// newPreflightRequirements collects the requirements of the NcxInfraMachines
+// and NcxInfraMachineTemplates of a cluster.
+func newPreflightRequirements(
+	machines []infrastructurev1.NcxInfraMachine, templates []infrastructurev1.NcxInfraMachineTemplate,
+) preflightRequirements {
+	requirements := preflightRequirements{instanceTypes: map[string]int{}}
+	for _, machine := range machines {
+		requirements.add(machine.Spec, machine.Status.InstanceID == "")
+	}
+	for _, template := range templates {
+		requirements.add(template.Spec.Template.Spec, false)
+	}
+	return requirements
+}
+
+// preflightRequirements collects the requirements of the NcxInfraMachines of
+// the cluster and of the NcxInfraMachineTemplates it uses. MachineDeployments
+// add their Cluster as owner of the templates, while ClusterClass based
+// templates carry the cluster name label.
+func (r *NcxInfraClusterReconciler) preflightRequirements(
+	ctx context.Context, clusterScope *scope.ClusterScope,
+) (preflightRequirements, error) {
+	namespace := clusterScope.NcxInfraCluster.Namespace
+
+	machineList := &infrastructurev1.NcxInfraMachineList{}
+	if err := r.List(ctx, machineList,
+		client.InNamespace(namespace),
+		client.MatchingFields{ClusterNameField: clusterScope.Cluster.Name},
+	); err != nil {
+		return preflightRequirements{}, fmt.Errorf("failed to list NcxInfraMachines: %w", err)
+	}
+
+	templateList := &infrastructurev1.NcxInfraMachineTemplateList{}
+	if err := r.List(ctx, templateList, client.InNamespace(namespace)); err != nil {
+		return preflightRequirements{}, fmt.Errorf("failed to list NcxInfraMachineTemplates: %w", err)
+	}
+	clusterKind := clusterv1.GroupVersion.WithKind("Cluster").GroupKind()
+	templates := slices.DeleteFunc(templateList.Items, func(template infrastructurev1.NcxInfraMachineTemplate) bool {
+		return template.Labels[clusterv1.ClusterNameLabel] != clusterScope.Cluster.Name &&
+			!util.IsOwnedByObject(&template, clusterScope.Cluster, clusterKind)
+	})
+	return newPreflightRequirements(machineList.Items, templates), nil
+}
+
+// Preflight runs the preflight checks of a cluster against NVIDIA Carbide
+// with the NcxInfraMachines and NcxInfraMachineTemplates of its manifest, for
+// the preflight command, instead of the ones of the management cluster. It
+// returns the summary of the validated resources, and the failed checks.
+func Preflight(
+	ctx context.Context, clusterScope *scope.ClusterScope,
+	machines []infrastructurev1.NcxInfraMachine, templates []infrastructurev1.NcxInfraMachineTemplate,
+) (string, []string) {
+	requirements := newPreflightRequirements(machines, templates)
+	return requirements.summary(), runPreflightChecks(ctx, clusterScope, requirements)
+}
+
+// runPreflightChecks validates the cluster against NVIDIA Carbide and returns
+// the failed checks.
+func runPreflightChecks(
+	ctx context.Context, clusterScope *scope.ClusterScope, requirements preflightRequirements,
+) []string {
+	var failures []string
+	c := clusterScope.NcxInfraClient
+	org := clusterScope.OrgName
+	spec := clusterScope.NcxInfraCluster.Spec
+
+	// The credentials are valid and belong to the tenant of the cluster
+	tenant, httpResp, err := c.GetCurrentTenant(ctx, org)
+	switch {
+	case err != nil:
+		failures = append(failures, fmt.Sprintf("failed to get the tenant of org %s: %v",
+			org, scope.WithPermissionError(httpResp, err, "GetCurrentTenant")))
+	case tenant != nil && tenant.GetId() != "" && tenant.GetId() != spec.TenantID:
+		failures = append(failures, fmt.Sprintf("the credentials belong to tenant %s of org %s, not to tenant %s",
+			tenant.GetId(), org, spec.TenantID))
+	}
+
+	siteID, err := clusterScope.SiteID(ctx)
+	if err != nil {
+		failures = append(failures, err.Error())
+	} else if site, httpResp, err := c.GetSite(ctx, org, siteID); err != nil {
+		failures = append(failures, fmt.Sprintf("failed to get site %s: %v",
+			siteID, scope.WithPermissionError(httpResp, err, "GetSite")))
+	} else if site != nil && site.Status != nil && *site.Status != nico.SITESTATUS_REGISTERED {
+		failures = append(failures, fmt.Sprintf("site %s is %s", siteID, *site.Status))
+	}
+
+	if err := checkIPSpace(spec); err != nil {
+		failures = append(failures, err.Error())
+	}
+
+	// The remaining checks need the site
+	if siteID == "" {
+		return failures
+	}
+
+	for _, id := range sortedKeys(requirements.instanceTypes) {
+		if _, httpResp, err := c.GetInstanceType(ctx, org, id); err != nil {
+			failures = append(failures, fmt.Sprintf("failed to get instance type %s: %v",
+				id, scope.WithPermissionError(httpResp, err, "GetInstanceType")))
+			continue
+		}
+		machines, httpResp, err := c.GetAllAvailableMachine(ctx, org, siteID, id)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("failed to list the available machines of instance type %s: %v",
+				id, scope.WithPermissionError(httpResp, err, "GetAllAvailableMachine")))
+			continue
+		}
+		if required := max(requirements.instanceTypes[id], 1); len(machines) < required {
+			failures = append(failures, fmt.Sprintf("instance type %s has %d available machine(s) on site %s, %d required",
+				id, len(machines), siteID, required))
+		}
+	}
+
+	for _, id := range requirements.machineIDs {
+		if _, httpResp, err := c.GetMachine(ctx, org, id); err != nil {
+			failures = append(failures, fmt.Sprintf("failed to get machine %s: %v",
+				id, scope.WithPermissionError(httpResp, err, "GetMachine")))
+		}
+	}
+
+	for _, id := range requirements.sshKeyGroups {
+		group, httpResp, err := c.GetSshKeyGroup(ctx, org, id)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("failed to get SSH key group %s: %v",
+				id, scope.WithPermissionError(httpResp, err, "GetSshKeyGroup")))
+			continue
+		}
+		if group != nil && !sshKeyGroupSynced(group, siteID) {
+			failures = append(failures, fmt.Sprintf("SSH key group %s is not synced to site %s", id, siteID))
+		}
+	}
+
+	return failures
+}
+
+// sshKeyGroupSynced returns whether the SSH key group is synced to the site.
+func sshKeyGroupSynced(group *nico.SshKeyGroup, siteID string) bool {
+	for _, association := range group.SiteAssociations {
+		if association.Site != nil && association.Site.GetId() == siteID {
+			return association.Status == nil || *association.Status == nico.SSHKEYGROUPSITEASSOCIATIONSTATUS_SYNCED
+		}
+	}
+	return false
+}
+
+// checkIPSpace checks that the subnets without Public egress in the site of
+// the cluster and the VPC prefixes are IPv4 CIDRs fitting in the IP blocks of
+// their supernets.
+func checkIPSpace(spec infrastructurev1.NcxInfraClusterSpec) error {
+	type allocation struct {
+		kind, name, cidr string
+	}
+	var allocations []allocation
+	for _, subnet := range spec.Subnets {
+		inOtherSite := subnet.SiteRef != nil && *subnet.SiteRef != spec.SiteRef
+		if subnet.Egress != infrastructurev1.SubnetEgressPublic && !inOtherSite {
+			allocations = append(allocations, allocation{"subnet", subnet.Name, subnet.CIDR})
+		}
+	}
+	for _, prefix := range spec.VPCPrefixes {
+		allocations = append(allocations, allocation{"VPC prefix", prefix.Name, prefix.CIDR})
+	}
+
+	supernets := clusterSupernets(spec)
+	required := make(map[netip.Prefix]int, len(supernets))
+	for _, a := range allocations {
+		prefix, err := netip.ParsePrefix(a.cidr)
+		if err != nil {
+			return fmt.Errorf("%s %s: invalid CIDR %s: %w", a.kind, a.name, a.cidr, err)
+		}
+		if !prefix.Addr().Is4() {
+			return fmt.Errorf("%s %s: %s is not an IPv4 CIDR", a.kind, a.name, a.cidr)
+		}
+		for _, supernet := range supernets {
+			if supernet.Overlaps(prefix) {
+				required[supernet] += 1 << (32 - prefix.Bits())
+			}
+		}
+	}
+	for _, supernet := range supernets {
+		if available := 1 << (32 - supernet.Bits()); required[supernet] > available {
+			return fmt.Errorf("the subnets and VPC prefixes of %s need %d addresses, more than the %d of its IP block",
+				supernet, required[supernet], available)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Preflight checks", func() {
+	const (
+		clusterName = "test-cluster"
+		namespace   = "default"
+		siteID      = "site-uuid"
+		tenantID    = "tenant-uuid"
+	)
+
+	var (
+		ctx             context.Context
+		ncxInfraCluster *infrastructurev1.NcxInfraCluster
+		machine         *infrastructurev1.NcxInfraMachine
+		template        *infrastructurev1.NcxInfraMachineTemplate
+		mockClient      *testutil.MockNcxInfraClient
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        clusterName,
+				Namespace:   namespace,
+				Annotations: map[string]string{infrastructurev1.PreflightAnnotation: ""},
+			},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				SiteRef:  infrastructurev1.SiteReference{ID: siteID},
+				TenantID: tenantID,
+				Subnets: []infrastructurev1.SubnetSpec{
+					{Name: "control-plane", CIDR: "10.0.0.0/26"},
+					{Name: "workers", CIDR: "10.0.0.64/26"},
+					{Name: "ingress", CIDR: "192.0.2.0/24", Egress: infrastructurev1.SubnetEgressPublic},
+				},
+			},
+		}
+		machine = &infrastructurev1.NcxInfraMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "control-plane-0",
+				Namespace: namespace,
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+			},
+			Spec: infrastructurev1.NcxInfraMachineSpec{
+				InstanceType: infrastructurev1.InstanceTypeSpec{ID: "gb200"},
+				SSHKeyGroups: []string{"admins"},
+			},
+		}
+		template = &infrastructurev1.NcxInfraMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "workers",
+				Namespace: namespace,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       clusterName,
+				}},
+			},
+			Spec: infrastructurev1.NcxInfraMachineTemplateSpec{
+				Template: infrastructurev1.NcxInfraMachineTemplateResource{
+					Spec: infrastructurev1.NcxInfraMachineSpec{
+						InstanceType: infrastructurev1.InstanceTypeSpec{ID: "h100"},
+						SSHKeyGroups: []string{"admins", "workers"},
+					},
+				},
+			},
+		}
+		mockClient = &testutil.MockNcxInfraClient{
+			GetCurrentTenantFunc: func(ctx context.Context, org string) (*nico.Tenant, *http.Response, error) {
+				return &nico.Tenant{Id: testutil.Ptr(tenantID)}, testutil.MockHTTPResponse(http.StatusOK), nil
+			},
+			GetSiteFunc: func(ctx context.Context, org, id string) (*nico.Site, *http.Response, error) {
+				return &nico.Site{Id: testutil.Ptr(id), Status: nico.SITESTATUS_REGISTERED.Ptr()},
+					testutil.MockHTTPResponse(http.StatusOK), nil
+			},
+			GetInstanceTypeFunc: func(ctx context.Context, org, id string) (*nico.InstanceType, *http.Response, error) {
+				return &nico.InstanceType{Id: testutil.Ptr(id)}, testutil.MockHTTPResponse(http.StatusOK), nil
+			},
+			GetAllAvailableMachineFunc: func(
+				ctx context.Context, org, site, instanceTypeID string,
+			) ([]nico.Machine, *http.Response, error) {
+				return []nico.Machine{{}}, testutil.MockHTTPResponse(http.StatusOK), nil
+			},
+			GetSshKeyGroupFunc: func(ctx context.Context, org, id string) (*nico.SshKeyGroup, *http.Response, error) {
+				return &nico.SshKeyGroup{
+					Id: testutil.Ptr(id),
+					SiteAssociations: []nico.SshKeyGroupSiteAssociation{{
+						Site:   &nico.SiteSummary{Id: testutil.Ptr(siteID)},
+						Status: nico.SSHKEYGROUPSITEASSOCIATIONSTATUS_SYNCED.Ptr(),
+					}},
+				}, testutil.MockHTTPResponse(http.StatusOK), nil
+			},
+		}
+	})
+
+	reconcilePreflight := func() bool {
+		scheme := newTestScheme()
+		reconciler := &NcxInfraClusterReconciler{
+			Client: newFakeClientBuilder(scheme).WithObjects(machine, template).Build(),
+			Scheme: scheme,
+		}
+		clusterScope := &scope.ClusterScope{
+			Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: namespace}},
+			NcxInfraCluster: ncxInfraCluster,
+			NcxInfraClient:  mockClient,
+			OrgName:         "test-org",
+		}
+		passed, err := reconciler.reconcilePreflight(ctx, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		return passed
+	}
+
+	It("should validate the cluster and remove the annotation", func() {
+		var instanceTypes, sshKeyGroups []string
+		mockClient.GetInstanceTypeFunc = func(ctx context.Context, org, id string) (*nico.InstanceType, *http.Response, error) {
+			instanceTypes = append(instanceTypes, id)
+			return &nico.InstanceType{Id: testutil.Ptr(id)}, testutil.MockHTTPResponse(http.StatusOK), nil
+		}
+		getSshKeyGroup := mockClient.GetSshKeyGroupFunc
+		mockClient.GetSshKeyGroupFunc = func(ctx context.Context, org, id string) (*nico.SshKeyGroup, *http.Response, error) {
+			sshKeyGroups = append(sshKeyGroups, id)
+			return getSshKeyGroup(ctx, org, id)
+		}
+
+		Expect(reconcilePreflight()).To(BeTrue())
+		Expect(instanceTypes).To(Equal([]string{"gb200", "h100"}))
+		Expect(sshKeyGroups).To(Equal([]string{"admins", "workers"}))
+		Expect(ncxInfraCluster.Annotations).NotTo(HaveKey(infrastructurev1.PreflightAnnotation))
+		Expect(conditions.IsTrue(ncxInfraCluster, string(PreflightSucceededCondition))).To(BeTrue())
+	})
+
+	It("should ignore the templates of other clusters", func() {
+		template.OwnerReferences[0].Name = "other-cluster"
+		mockClient.GetInstanceTypeFunc = func(ctx context.Context, org, id string) (*nico.InstanceType, *http.Response, error) {
+			if id == "h100" {
+				return nil, testutil.MockHTTPResponse(http.StatusNotFound), fmt.Errorf("not found")
+			}
+			return &nico.InstanceType{Id: testutil.Ptr(id)}, testutil.MockHTTPResponse(http.StatusOK), nil
+		}
+
+		Expect(reconcilePreflight()).To(BeTrue())
+	})
+
+	It("should report every failed check and keep the annotation", func() {
+		mockClient.GetCurrentTenantFunc = func(ctx context.Context, org string) (*nico.Tenant, *http.Response, error) {
+			return &nico.Tenant{Id: testutil.Ptr("other-tenant")}, testutil.MockHTTPResponse(http.StatusOK), nil
+		}
+		mockClient.GetAllAvailableMachineFunc = func(
+			ctx context.Context, org, site, instanceTypeID string,
+		) ([]nico.Machine, *http.Response, error) {
+			if instanceTypeID == "gb200" {
+				return nil, testutil.MockHTTPResponse(http.StatusOK), nil
+			}
+			return []nico.Machine{{}}, testutil.MockHTTPResponse(http.StatusOK), nil
+		}
+		mockClient.GetSshKeyGroupFunc = func(ctx context.Context, org, id string) (*nico.SshKeyGroup, *http.Response, error) {
+			return &nico.SshKeyGroup{Id: testutil.Ptr(id)}, testutil.MockHTTPResponse(http.StatusOK), nil
+		}
+
+		Expect(reconcilePreflight()).To(BeFalse())
+		Expect(ncxInfraCluster.Annotations).To(HaveKey(infrastructurev1.PreflightAnnotation))
+		condition := conditions.Get(ncxInfraCluster, string(PreflightSucceededCondition))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("PreflightFailed"))
+		Expect(condition.Message).To(ContainSubstring("credentials belong to tenant other-tenant"))
+		Expect(condition.Message).To(ContainSubstring("instance type gb200 has 0 available machine(s) on site site-uuid, 1 required"))
+		Expect(condition.Message).To(ContainSubstring("SSH key group admins is not synced to site site-uuid"))
+		Expect(condition.Message).To(ContainSubstring("SSH key group workers is not synced to site site-uuid"))
+	})
+
+	It("should not check the instance types without a site", func() {
+		mockClient.GetSiteFunc = func(ctx context.Context, org, id string) (*nico.Site, *http.Response, error) {
+			return &nico.Site{Id: testutil.Ptr(id), Status: nico.SITESTATUS_ERROR.Ptr()},
+				testutil.MockHTTPResponse(http.StatusOK), nil
+		}
+		ncxInfraCluster.Spec.SiteRef = infrastructurev1.SiteReference{Name: "missing-site"}
+		mockClient.GetInstanceTypeFunc = func(ctx context.Context, org, id string) (*nico.InstanceType, *http.Response, error) {
+			Fail("instance types should not be checked without a site")
+			return nil, nil, nil
+		}
+
+		Expect(reconcilePreflight()).To(BeFalse())
+		condition := conditions.Get(ncxInfraCluster, string(PreflightSucceededCondition))
+		Expect(condition.Message).To(Equal(`site "missing-site" not found`))
+	})
+
+	It("should check the machines and templates of a manifest without changing the cluster", func() {
+		clusterScope := &scope.ClusterScope{
+			Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: namespace}},
+			NcxInfraCluster: ncxInfraCluster,
+			NcxInfraClient:  mockClient,
+			OrgName:         "test-org",
+		}
+		template.OwnerReferences = nil
+
+		summary, failures := Preflight(ctx, clusterScope,
+			[]infrastructurev1.NcxInfraMachine{*machine}, []infrastructurev1.NcxInfraMachineTemplate{*template})
+		Expect(failures).To(BeEmpty())
+		Expect(summary).To(Equal("Credentials, site, IP space, 2 instance type(s), 0 machine(s) and 2 SSH key group(s) validated"))
+		Expect(ncxInfraCluster.Annotations).To(HaveKey(infrastructurev1.PreflightAnnotation))
+		Expect(ncxInfraCluster.Status.Conditions).To(BeEmpty())
+
+		mockClient.GetSshKeyGroupFunc = func(ctx context.Context, org, id string) (*nico.SshKeyGroup, *http.Response, error) {
+			return &nico.SshKeyGroup{Id: testutil.Ptr(id)}, testutil.MockHTTPResponse(http.StatusOK), nil
+		}
+		_, failures = Preflight(ctx, clusterScope, nil, []infrastructurev1.NcxInfraMachineTemplate{*template})
+		Expect(failures).To(ConsistOf(
+			"SSH key group admins is not synced to site site-uuid",
+			"SSH key group workers is not synced to site site-uuid",
+		))
+	})
+})
+
+var _ = Describe("checkIPSpace", func() {
+	It("should accept subnets and VPC prefixes fitting in the cluster allocation", func() {
+		Expect(checkIPSpace(infrastructurev1.NcxInfraClusterSpec{
+			Subnets: []infrastructurev1.SubnetSpec{
+				{Name: "control-plane", CIDR: "10.0.0.0/25"},
+				{Name: "ingress", CIDR: "192.0.2.0/24", Egress: infrastructurev1.SubnetEgressPublic},
+			},
+			VPCPrefixes: []infrastructurev1.VPCPrefixSpec{{Name: "storage", CIDR: "10.0.1.0/25"}},
+		})).To(Succeed())
+	})
+
+	It("should accept a subnet larger than a /24", func() {
+		Expect(checkIPSpace(infrastructurev1.NcxInfraClusterSpec{
+			Subnets: []infrastructurev1.SubnetSpec{{Name: "workers", CIDR: "10.0.0.0/20"}},
+		})).To(Succeed())
+	})
+
+	It("should reject subnets and VPC prefixes exceeding the IP block of their supernet", func() {
+		err := checkIPSpace(infrastructurev1.NcxInfraClusterSpec{
+			Subnets:     []infrastructurev1.SubnetSpec{{Name: "workers", CIDR: "10.0.0.0/16"}},
+			VPCPrefixes: []infrastructurev1.VPCPrefixSpec{{Name: "storage", CIDR: "10.0.1.0/28"}},
+		})
+		Expect(err).To(MatchError(
+			"the subnets and VPC prefixes of 10.0.0.0/16 need 65552 addresses, more than the 65536 of its IP block"))
+	})
+
+	It("should reject an IPv6 subnet", func() {
+		err := checkIPSpace(infrastructurev1.NcxInfraClusterSpec{
+			Subnets: []infrastructurev1.SubnetSpec{{Name: "workers", CIDR: "2001:db8::/64"}},
+		})
+		Expect(err).To(MatchError("subnet workers: 2001:db8::/64 is not an IPv4 CIDR"))
+	})
+})
@@ -0,0 +1,60 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// DeletionBlockedCondition reports a deletion held by the prevent-deletion annotation.
+const DeletionBlockedCondition clusterv1.ConditionType = "DeletionBlocked"
+
+// deletionProtectionRefreshInterval paces the checks of the protected machines,
+// which are not notified when the annotation is removed from their cluster.
+const deletionProtectionRefreshInterval = time.Minute
+
+// isDeletionProtected returns whether one of the objects carries the
+// prevent-deletion annotation.
+func isDeletionProtected(objs ...metav1.Object) bool {
+	for _, obj := range objs {
+		if _, ok := obj.GetAnnotations()[infrastructurev1.PreventDeletionAnnotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setDeletionBlocked reports the held deletion in the DeletionBlocked
+// condition. It returns whether the deletion was not blocked before, to
+// report it once.
+func setDeletionBlocked(obj conditions.Setter) bool {
+	blocked := conditions.IsTrue(obj, string(DeletionBlockedCondition))
+	conditions.Set(obj, metav1.Condition{
+		Type:   string(DeletionBlockedCondition),
+		Status: metav1.ConditionTrue,
+		Reason: "DeletionPrevented",
+		Message: "Deletion held by the " + infrastructurev1.PreventDeletionAnnotation +
+			" annotation, remove it to delete the NVIDIA Carbide resources",
+	})
+	return !blocked
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+)
+
+var _ = Describe("Deletion protection", func() {
+	const (
+		clusterName = "test-cluster"
+		namespace   = "default"
+	)
+
+	It("should hold the deletion of a protected cluster until the annotation is removed", func() {
+		ctx := context.Background()
+		scheme := newTestScheme()
+		now := metav1.Now()
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: namespace, UID: "cluster-uid"},
+		}
+		ncxInfraCluster := &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              clusterName,
+				Namespace:         namespace,
+				Annotations:       map[string]string{infrastructurev1.PreventDeletionAnnotation: ""},
+				Finalizers:        []string{NcxInfraClusterFinalizer},
+				DeletionTimestamp: &now,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       clusterName,
+					UID:        "cluster-uid",
+				}},
+			},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				SiteRef:  infrastructurev1.SiteReference{ID: "site-uuid"},
+				TenantID: "tenant-uuid",
+				VPC:      infrastructurev1.VPCSpec{Name: "test-vpc"},
+			},
+			Status: infrastructurev1.NcxInfraClusterStatus{
+				NetworkStatus: infrastructurev1.NetworkStatus{
+					VPC: &infrastructurev1.NetworkResourceStatus{Name: "test-vpc", ID: "vpc-uuid"},
+				},
+			},
+		}
+		k8sClient := newFakeClientBuilder(scheme).
+			WithObjects(cluster, ncxInfraCluster).
+			WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+			Build()
+		vpcDeleted := false
+		mockClient := &testutil.MockNcxInfraClient{
+			DeleteVPCFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+				vpcDeleted = true
+				return testutil.MockHTTPResponse(http.StatusNoContent), nil
+			},
+		}
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &NcxInfraClusterReconciler{
+			Client:         k8sClient,
+			Scheme:         scheme,
+			Recorder:       recorder,
+			NcxInfraClient: mockClient,
+			OrgName:        "test-org",
+		}
+
+		key := types.NamespacedName{Name: clusterName, Namespace: namespace}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vpcDeleted).To(BeFalse())
+
+		updated := &infrastructurev1.NcxInfraCluster{}
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(conditions.IsTrue(updated, string(DeletionBlockedCondition))).To(BeTrue())
+		Expect(updated.Finalizers).To(ContainElement(NcxInfraClusterFinalizer))
+		Expect(recorder.Events).To(Receive(ContainSubstring("DeletionBlocked")))
+
+		// The event is only emitted when the deletion gets blocked
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		// Removing the annotation lets the deletion proceed
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		delete(updated.Annotations, infrastructurev1.PreventDeletionAnnotation)
+		Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vpcDeleted).To(BeTrue())
+	})
+
+	It("should protect the machines of a protected cluster", func() {
+		ncxInfraCluster := &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{infrastructurev1.PreventDeletionAnnotation: "true"},
+			},
+		}
+		Expect(isDeletionProtected(&infrastructurev1.NcxInfraCluster{}, &infrastructurev1.NcxInfraMachine{})).To(BeFalse())
+		Expect(isDeletionProtected(ncxInfraCluster, &infrastructurev1.NcxInfraMachine{})).To(BeTrue())
+	})
+})
@@ -0,0 +1,188 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	ncxinfraerrors "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/errors"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// QuotaExceededCondition reports that a quota of the NVIDIA Carbide tenant is
+// exhausted: on a cluster, any of its quotas, and on a machine, the instance
+// quota blocking the creation of its instance.
+const QuotaExceededCondition clusterv1.ConditionType = "QuotaExceeded"
+
+// instanceQuota is the quota of the tenant counting its instances.
+const instanceQuota = "instances"
+
+// errQuotaExceeded is returned when the instance quota of the tenant leaves
+// no room for a new instance.
+var errQuotaExceeded = errors.New("instance quota exceeded")
+
+// getQuotas returns the quotas of the tenant of the cluster, nil when the
+// deployment does not report quotas.
+func getQuotas(ctx context.Context, clusterScope *scope.ClusterScope) (map[string]nico.QuotaLimit, error) {
+	getStart := time.Now()
+	info, httpResp, err := clusterScope.NcxInfraClient.GetSelfQuotas(ctx, clusterScope.OrgName)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetSelfQuotas")
+	recordAPIMetrics("GetSelfQuotas", getStart, apiErr)
+	if apiErr != nil {
+		if apiErr.IsNotFound() {
+			return nil, nil
+		}
+		return nil, apiErr
+	}
+	if info == nil {
+		return nil, nil
+	}
+	return info.Quotas, nil
+}
+
+// quotaExhausted returns whether the consumption of a quota reached its limit.
+func quotaExhausted(quota nico.QuotaLimit) bool {
+	return quota.Limit != nil && quota.Current != nil && *quota.Current >= *quota.Limit
+}
+
+// quotaQuantity converts a quota value to a quantity.
+func quotaQuantity(value *float32) resource.Quantity {
+	if value == nil {
+		return resource.Quantity{}
+	}
+	quantity, err := resource.ParseQuantity(strconv.FormatFloat(float64(*value), 'f', -1, 32))
+	if err != nil {
+		return resource.Quantity{}
+	}
+	return quantity
+}
+
+// formatQuota returns the consumption of a quota for messages.
+func formatQuota(name string, quota nico.QuotaLimit) string {
+	limit, current := quotaQuantity(quota.Limit), quotaQuantity(quota.Current)
+	msg := fmt.Sprintf("%s %s/%s", name, current.String(), limit.String())
+	if quota.Unit != nil && *quota.Unit != "" {
+		msg += " " + *quota.Unit
+	}
+	return msg
+}
+
+// reconcileQuotas records the quotas of the tenant and their consumption in
+// the cluster status, and reports the exhausted ones in the QuotaExceeded
+// condition. The last known quotas are kept when they cannot be read.
+func (r *NcxInfraClusterReconciler) reconcileQuotas(ctx context.Context, clusterScope *scope.ClusterScope) {
+	quotas, err := getQuotas(ctx, clusterScope)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to get the quotas of the tenant")
+		return
+	}
+
+	ncxInfraCluster := clusterScope.NcxInfraCluster
+	names := sortedKeys(quotas)
+	statuses := make([]infrastructurev1.QuotaStatus, 0, len(names))
+	var exhausted []string
+	for _, name := range names {
+		quota := quotas[name]
+		statuses = append(statuses, infrastructurev1.QuotaStatus{
+			Name:    name,
+			Limit:   quotaQuantity(quota.Limit),
+			Current: quotaQuantity(quota.Current),
+			Unit:    ptr.Deref(quota.Unit, ""),
+		})
+		if quotaExhausted(quota) {
+			exhausted = append(exhausted, formatQuota(name, quota))
+		}
+	}
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+	ncxInfraCluster.Status.Quotas = statuses
+
+	if quotas == nil {
+		conditions.Delete(ncxInfraCluster, string(QuotaExceededCondition))
+		return
+	}
+	if len(exhausted) == 0 {
+		conditions.Set(ncxInfraCluster, metav1.Condition{
+			Type:   string(QuotaExceededCondition),
+			Status: metav1.ConditionFalse,
+			Reason: "WithinQuota",
+		})
+		return
+	}
+
+	msg := "quotas of the tenant exhausted: " + strings.Join(exhausted, ", ")
+	if !conditions.IsTrue(ncxInfraCluster, string(QuotaExceededCondition)) && r.Recorder != nil {
+		r.Recorder.Event(ncxInfraCluster, corev1.EventTypeWarning, "QuotaExceeded", msg)
+	}
+	conditions.Set(ncxInfraCluster, metav1.Condition{
+		Type:    string(QuotaExceededCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  "QuotaExhausted",
+		Message: msg,
+	})
+}
+
+// checkQuota checks that the instance quota of the tenant leaves room for a
+// new instance, so that the creation waits for room instead of failing. The
+// creation is attempted when the quotas cannot be read.
+func (r *NcxInfraMachineReconciler) checkQuota(
+	ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope,
+) error {
+	quotas, err := getQuotas(ctx, clusterScope)
+	if err != nil {
+		log.FromContext(ctx).V(1).Info("Failed to get the quotas of the tenant, creating the instance anyway",
+			"error", err.Error())
+		return nil
+	}
+
+	machine := machineScope.NcxInfraMachine
+	quota, ok := quotas[instanceQuota]
+	if ok && quotaExhausted(quota) {
+		err := fmt.Errorf("%w: %s", errQuotaExceeded, formatQuota(instanceQuota, quota))
+		conditions.Set(machine, metav1.Condition{
+			Type:    string(QuotaExceededCondition),
+			Status:  metav1.ConditionTrue,
+			Reason:  "InstanceQuotaExhausted",
+			Message: err.Error(),
+		})
+		return ncxinfraerrors.Transient(ncxinfraerrors.QuotaExceeded, err)
+	}
+	if conditions.Has(machine, string(QuotaExceededCondition)) {
+		conditions.Set(machine, metav1.Condition{
+			Type:   string(QuotaExceededCondition),
+			Status: metav1.ConditionFalse,
+			Reason: "WithinQuota",
+		})
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Tenant quotas", func() {
+	var (
+		ctx             context.Context
+		ncxInfraCluster *infrastructurev1.NcxInfraCluster
+		clusterScope    *scope.ClusterScope
+		recorder        *record.FakeRecorder
+		quotas          map[string]nico.QuotaLimit
+		status          int
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		status = http.StatusOK
+		quotas = map[string]nico.QuotaLimit{
+			"instances": {Limit: testutil.Ptr(float32(10)), Current: testutil.Ptr(float32(4))},
+			"gpu-hours": {
+				Limit: testutil.Ptr(float32(1000)), Current: testutil.Ptr(float32(12.5)), Unit: testutil.Ptr("hours"),
+			},
+		}
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+		}
+		mockClient := &testutil.MockNcxInfraClient{
+			GetSelfQuotasFunc: func(ctx context.Context, org string) (*nico.QuotaInfo, *http.Response, error) {
+				if status != http.StatusOK {
+					return nil, testutil.MockHTTPResponse(status), nil
+				}
+				return &nico.QuotaInfo{Quotas: quotas}, testutil.MockHTTPResponse(status), nil
+			},
+		}
+		clusterScope = &scope.ClusterScope{NcxInfraCluster: ncxInfraCluster, NcxInfraClient: mockClient, OrgName: "test-org"}
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	It("should record the consumption of the quotas in the cluster status", func() {
+		reconciler := &NcxInfraClusterReconciler{Recorder: recorder}
+		reconciler.reconcileQuotas(ctx, clusterScope)
+
+		Expect(ncxInfraCluster.Status.Quotas).To(Equal([]infrastructurev1.QuotaStatus{
+			{Name: "gpu-hours", Limit: resource.MustParse("1000"), Current: resource.MustParse("12.5"), Unit: "hours"},
+			{Name: "instances", Limit: resource.MustParse("10"), Current: resource.MustParse("4")},
+		}))
+		Expect(conditions.IsFalse(ncxInfraCluster, string(QuotaExceededCondition))).To(BeTrue())
+		Expect(recorder.Events).To(BeEmpty())
+
+		quotas["instances"] = nico.QuotaLimit{Limit: testutil.Ptr(float32(10)), Current: testutil.Ptr(float32(10))}
+		reconciler.reconcileQuotas(ctx, clusterScope)
+		Expect(conditions.IsTrue(ncxInfraCluster, string(QuotaExceededCondition))).To(BeTrue())
+		Expect(conditions.GetMessage(ncxInfraCluster, string(QuotaExceededCondition))).To(ContainSubstring("instances 10/10"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("QuotaExceeded")))
+
+		// The event is only emitted when the quota gets exhausted
+		reconciler.reconcileQuotas(ctx, clusterScope)
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should not report quotas when the deployment has no showback API", func() {
+		status = http.StatusNotFound
+		ncxInfraCluster.Status.Quotas = []infrastructurev1.QuotaStatus{{Name: "instances"}}
+		conditions.Set(ncxInfraCluster, metav1.Condition{
+			Type: string(QuotaExceededCondition), Status: metav1.ConditionTrue, Reason: "QuotaExhausted",
+		})
+
+		(&NcxInfraClusterReconciler{Recorder: recorder}).reconcileQuotas(ctx, clusterScope)
+		Expect(ncxInfraCluster.Status.Quotas).To(BeEmpty())
+		Expect(conditions.Has(ncxInfraCluster, string(QuotaExceededCondition))).To(BeFalse())
+	})
+
+	It("should keep the last known quotas when they cannot be read", func() {
+		status = http.StatusServiceUnavailable
+		ncxInfraCluster.Status.Quotas = []infrastructurev1.QuotaStatus{{Name: "instances"}}
+
+		(&NcxInfraClusterReconciler{Recorder: recorder}).reconcileQuotas(ctx, clusterScope)
+		Expect(ncxInfraCluster.Status.Quotas).To(HaveLen(1))
+	})
+
+	It("should block the creation of an instance while the instance quota is exhausted", func() {
+		reconciler := &NcxInfraMachineReconciler{Recorder: recorder}
+		machineScope := &scope.MachineScope{NcxInfraMachine: &infrastructurev1.NcxInfraMachine{}}
+		Expect(reconciler.checkQuota(ctx, machineScope, clusterScope)).To(Succeed())
+		Expect(conditions.Has(machineScope.NcxInfraMachine, string(QuotaExceededCondition))).To(BeFalse())
+
+		quotas["instances"] = nico.QuotaLimit{Limit: testutil.Ptr(float32(10)), Current: testutil.Ptr(float32(10))}
+		err := reconciler.checkQuota(ctx, machineScope, clusterScope)
+		Expect(err).To(MatchError(errQuotaExceeded))
+		Expect(conditions.IsTrue(machineScope.NcxInfraMachine, string(QuotaExceededCondition))).To(BeTrue())
+
+		quotas["instances"] = nico.QuotaLimit{Limit: testutil.Ptr(float32(20)), Current: testutil.Ptr(float32(10))}
+		Expect(reconciler.checkQuota(ctx, machineScope, clusterScope)).To(Succeed())
+		Expect(conditions.IsFalse(machineScope.NcxInfraMachine, string(QuotaExceededCondition))).To(BeTrue())
+	})
+})
@@ -0,0 +1,101 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// SiteReachableCondition reports whether NVIDIA Carbide answered the lookups
+// of the network resources of the cluster. While it is false, the resources
+// are kept in the status instead of being recreated.
+const SiteReachableCondition clusterv1.ConditionType = "SiteReachable"
+
+// siteUnreachableRetryInterval paces the lookups of a cluster whose site does
+// not answer.
+const siteUnreachableRetryInterval = 30 * time.Second
+
+// errSiteUnreachable is wrapped by the lookup errors of the existing network
+// resources that NVIDIA Carbide could not answer.
+var errSiteUnreachable = errors.New("NVIDIA Carbide site unreachable")
+
+// lookupExisting interprets the lookup of a network resource recorded in the
+// status. It returns true when the resource exists, and false when NVIDIA
+// Carbide reports it gone, so that it is recreated. Any other failure is
+// returned, so that the recorded ID is kept: timeouts and gateway errors wrap
+// errSiteUnreachable, as an offline site says nothing about its resources.
+func lookupExisting(httpResp *http.Response, err error, found bool, method, kind, id string) (bool, error) {
+	if err == nil {
+		return found, nil
+	}
+	getErr := scope.ClassifyAPIError(httpResp, err, method)
+	switch {
+	case getErr.IsNotFound():
+		return false, nil
+	case getErr.IsUnreachable():
+		return false, fmt.Errorf("%w: failed to get %s %s: %w", errSiteUnreachable, kind, id, getErr)
+	default:
+		return false, fmt.Errorf("failed to get %s %s: %w", kind, id, getErr)
+	}
+}
+
+// handleSiteUnreachable sets the SiteReachable condition of the cluster from
+// the result of its reconciliation. An unreachable site is retried every
+// siteUnreachableRetryInterval rather than with an error backoff, keeping all
+// the recorded resources.
+func (r *NcxInfraClusterReconciler) handleSiteUnreachable(
+	ctx context.Context, ncxInfraCluster *infrastructurev1.NcxInfraCluster, result ctrl.Result, err error,
+) (ctrl.Result, error) {
+	if errors.Is(err, errSiteUnreachable) {
+		log.FromContext(ctx).Info("NVIDIA Carbide site unreachable, keeping the network resources", "reason", err.Error())
+		if !conditions.IsFalse(ncxInfraCluster, string(SiteReachableCondition)) && r.Recorder != nil {
+			r.Recorder.Event(ncxInfraCluster, corev1.EventTypeWarning, "SiteUnreachable", err.Error())
+		}
+		conditions.Set(ncxInfraCluster, metav1.Condition{
+			Type:    string(SiteReachableCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "SiteUnreachable",
+			Message: err.Error(),
+		})
+		return ctrl.Result{RequeueAfter: siteUnreachableRetryInterval}, nil
+	}
+	if err == nil {
+		if conditions.IsFalse(ncxInfraCluster, string(SiteReachableCondition)) {
+			r.recordEvent(ncxInfraCluster, "SiteReachable", "NVIDIA Carbide site reachable again")
+		}
+		conditions.Set(ncxInfraCluster, metav1.Condition{
+			Type:   string(SiteReachableCondition),
+			Status: metav1.ConditionTrue,
+			Reason: "SiteReachable",
+		})
+	}
+	return result, err
+}
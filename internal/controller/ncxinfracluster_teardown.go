@@ -0,0 +1,225 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// Actions reported in the teardown report
+const (
+	TeardownActionDelete = "Delete"
+	TeardownActionDetach = "Detach"
+	TeardownActionRetain = "Retain"
+)
+
+// teardownReportKey is the ConfigMap key holding the teardown report.
+const teardownReportKey = "report.yaml"
+
+// teardownReport lists what deleting a cluster does to its NVIDIA Carbide resources.
+type teardownReport struct {
+	Cluster     string                `json:"cluster"`
+	GeneratedAt metav1.Time           `json:"generatedAt"`
+	Resources   []teardownReportEntry `json:"resources"`
+}
+
+// teardownReportEntry is a single NVIDIA Carbide resource of the teardown report.
+type teardownReportEntry struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name,omitempty"`
+	ID     string `json:"id"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// reconcileTeardownReport writes the teardown report requested through the
+// teardown report annotation, then removes the annotation.
+func (r *NcxInfraClusterReconciler) reconcileTeardownReport(
+	ctx context.Context, clusterScope *scope.ClusterScope,
+) error {
+	logger := log.FromContext(ctx)
+	ncxInfraCluster := clusterScope.NcxInfraCluster
+
+	machineList := &infrastructurev1.NcxInfraMachineList{}
+	if err := r.List(ctx, machineList,
+		client.InNamespace(ncxInfraCluster.Namespace),
+		client.MatchingFields{ClusterNameField: clusterScope.Cluster.Name},
+	); err != nil {
+		return fmt.Errorf("failed to list NcxInfraMachines: %w", err)
+	}
+
+	report := buildTeardownReport(ncxInfraCluster, machineList.Items)
+	data, err := yaml.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode teardown report: %w", err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ncxInfraCluster.Name + "-teardown-report",
+			Namespace: ncxInfraCluster.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[clusterv1.ClusterNameLabel] = clusterScope.Cluster.Name
+		configMap.Data = map[string]string{teardownReportKey: string(data)}
+		return controllerutil.SetOwnerReference(ncxInfraCluster, configMap, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to write teardown report: %w", err)
+	}
+
+	counts := map[string]int{}
+	for _, entry := range report.Resources {
+		counts[entry.Action]++
+	}
+	logger.Info("Generated teardown report", "configMap", configMap.Name)
+	r.recordEvent(ncxInfraCluster, "TeardownReportGenerated",
+		"Teardown report written to ConfigMap %s: %d to delete, %d to detach, %d to retain",
+		configMap.Name, counts[TeardownActionDelete], counts[TeardownActionDetach], counts[TeardownActionRetain])
+
+	delete(ncxInfraCluster.Annotations, infrastructurev1.TeardownReportAnnotation)
+	return nil
+}
+
+// buildTeardownReport lists the NVIDIA Carbide resources of the cluster in the
+// order they are deleted, with the action taken on each given the current spec.
+func buildTeardownReport(
+	ncxInfraCluster *infrastructurev1.NcxInfraCluster, machines []infrastructurev1.NcxInfraMachine,
+) teardownReport {
+	report := teardownReport{
+		Cluster:     ncxInfraCluster.Name,
+		GeneratedAt: metav1.Now(),
+		Resources:   []teardownReportEntry{},
+	}
+	add := func(kind, name, id, action, reason string) {
+		if id == "" {
+			return
+		}
+		report.Resources = append(report.Resources, teardownReportEntry{
+			Kind: kind, Name: name, ID: id, Action: action, Reason: reason,
+		})
+	}
+
+	// Instances are deleted with their machines, before the cluster
+	sort.Slice(machines, func(i, j int) bool { return machines[i].Name < machines[j].Name })
+	for i := range machines {
+		machine := &machines[i]
+		add("Instance", machine.Name, machine.Status.InstanceID, TeardownActionDelete, "")
+		add("Machine", machine.Name, machine.Status.MachineID, TeardownActionDetach, machineTeardownReason(machine))
+	}
+
+	if breakGlass := ncxInfraCluster.Status.BreakGlassSSH; breakGlass != nil {
+		name := breakGlassSSHName(ncxInfraCluster)
+		add("SSHKeyGroup", name, breakGlass.SSHKeyGroupID, TeardownActionDelete, "")
+		add("SSHKey", name, breakGlass.RetiredSSHKeyID, TeardownActionDelete, "")
+		add("SSHKey", name, breakGlass.SSHKeyID, TeardownActionDelete, "")
+	}
+
+	network := ncxInfraCluster.Status.NetworkStatus
+	nsgID := ""
+	if network.NSG != nil {
+		nsgID = network.NSG.ID
+	}
+	if ref := ncxInfraCluster.Spec.VPC.NetworkSecurityGroupRef; ref != nil {
+		add("NetworkSecurityGroup", ref.Name, nsgID, TeardownActionRetain,
+			"owned by NcxInfraNetworkSecurityGroup "+ref.Name)
+	} else {
+		name := ""
+		if ncxInfraCluster.Spec.VPC.NetworkSecurityGroup != nil {
+			name = ncxInfraCluster.Spec.VPC.NetworkSecurityGroup.Name
+		}
+		add("NetworkSecurityGroup", name, nsgID, TeardownActionDelete, "")
+	}
+	for _, peering := range network.VPCPeerings {
+		add("VPCPeering", peering.Name, peering.ID, TeardownActionDelete, "")
+	}
+	for _, prefix := range network.VPCPrefixes {
+		add("VPCPrefix", prefix.Name, prefix.ID, TeardownActionDelete, "")
+	}
+	for _, subnet := range network.Subnets {
+		add("Subnet", subnet.Name, subnet.ID, TeardownActionDelete, "")
+	}
+	for _, ipBlock := range ipBlocksInDeletionOrder(network.IPBlocks) {
+		action, reason := ipBlockTeardownAction(ipBlock)
+		if ipBlock.Subnet != "" {
+			add("Allocation", ipBlock.Subnet, ipBlock.AllocationID, TeardownActionDelete, "")
+			add("IPBlock", ipBlock.Subnet+" child", ipBlock.ChildIPBlockID, TeardownActionDelete, "")
+			add("IPBlock", ipBlock.Subnet+" public", ipBlock.IPBlockID, action, reason)
+			continue
+		}
+		add("Allocation", ipBlock.CIDR, ipBlock.AllocationID, TeardownActionDelete, "")
+		add("IPBlock", strings.TrimSpace(ipBlock.CIDR+" child"), ipBlock.ChildIPBlockID, TeardownActionDelete, "")
+		add("IPBlock", strings.TrimSpace(ipBlock.CIDR+" parent"), ipBlock.IPBlockID, action, reason)
+	}
+	for _, vpc := range network.SiteVPCs {
+		add("VPC", siteVPCName(ncxInfraCluster.Spec.VPC.Name, vpc.Name), vpc.ID, TeardownActionDelete, "")
+	}
+	add("VPC", ncxInfraCluster.Spec.VPC.Name, network.VPCID(), TeardownActionDelete, "")
+
+	return report
+}
+
+// ipBlockTeardownAction returns what happens to a parent IP block: deleted
+// when the provider created it, retained when the cluster adopted it.
+func ipBlockTeardownAction(ipBlock infrastructurev1.IPBlockStatus) (action, reason string) {
+	if ipBlock.Adopted {
+		return TeardownActionRetain, "adopted, existed before the cluster"
+	}
+	return TeardownActionDelete, ""
+}
+
+// machineTeardownReason describes what happens to the physical machine once its instance is deleted.
+func machineTeardownReason(machine *infrastructurev1.NcxInfraMachine) string {
+	deletion := machine.Spec.Deletion
+	if deletion != nil && deletion.Policy == infrastructurev1.DeletionPolicyRepair {
+		if deletion.HealthIssue != nil && deletion.HealthIssue.Category != "" {
+			return fmt.Sprintf("sent to repair (%s issue)", deletion.HealthIssue.Category)
+		}
+		return "sent to repair"
+	}
+	if deletion != nil && deletion.SecureErase == infrastructurev1.SecureEraseVerified {
+		return "returned to the pool after a verified disk wipe"
+	}
+	return "returned to the pool"
+}
+
+// sortedKeys returns the keys of a map in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/yaml"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Teardown report", func() {
+	const (
+		clusterName = "test-cluster"
+		namespace   = "default"
+	)
+
+	var (
+		ctx             context.Context
+		ncxInfraCluster *infrastructurev1.NcxInfraCluster
+		machines        []infrastructurev1.NcxInfraMachine
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        clusterName,
+				Namespace:   namespace,
+				UID:         "cluster-uid",
+				Annotations: map[string]string{infrastructurev1.TeardownReportAnnotation: ""},
+			},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				VPC: infrastructurev1.VPCSpec{
+					Name:                 "test-vpc",
+					NetworkSecurityGroup: &infrastructurev1.NSGSpec{Name: "test-nsg"},
+				},
+			},
+			Status: infrastructurev1.NcxInfraClusterStatus{
+				NetworkStatus: infrastructurev1.NetworkStatus{
+					VPC: &infrastructurev1.NetworkResourceStatus{Name: "test-vpc", ID: "vpc-uuid"},
+					Subnets: []infrastructurev1.NetworkResourceStatus{
+						{Name: "control-plane", ID: "subnet-1"},
+						{Name: "workers", ID: "subnet-2"},
+					},
+					NSG: &infrastructurev1.NSGStatus{
+						NetworkResourceStatus: infrastructurev1.NetworkResourceStatus{Name: "test-nsg", ID: "nsg-uuid"},
+					},
+					IPBlocks: []infrastructurev1.IPBlockStatus{{
+						IPBlockID:      "ipblock-uuid",
+						AllocationID:   "allocation-uuid",
+						ChildIPBlockID: "child-ipblock-uuid",
+					}},
+				},
+			},
+		}
+		machines = []infrastructurev1.NcxInfraMachine{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "worker-0",
+					Namespace: namespace,
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+				},
+				Spec: infrastructurev1.NcxInfraMachineSpec{
+					Deletion: &infrastructurev1.DeletionSpec{
+						Policy:      infrastructurev1.DeletionPolicyRepair,
+						HealthIssue: &infrastructurev1.MachineHealthIssueSpec{Category: "Hardware"},
+					},
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{InstanceID: "instance-1", MachineID: "machine-1"},
+			},
+		}
+	})
+
+	It("should list the resources in deletion order with their action", func() {
+		report := buildTeardownReport(ncxInfraCluster, machines)
+
+		Expect(report.Cluster).To(Equal(clusterName))
+		Expect(report.Resources).To(Equal([]teardownReportEntry{
+			{Kind: "Instance", Name: "worker-0", ID: "instance-1", Action: TeardownActionDelete},
+			{Kind: "Machine", Name: "worker-0", ID: "machine-1", Action: TeardownActionDetach,
+				Reason: "sent to repair (Hardware issue)"},
+			{Kind: "NetworkSecurityGroup", Name: "test-nsg", ID: "nsg-uuid", Action: TeardownActionDelete},
+			{Kind: "Subnet", Name: "control-plane", ID: "subnet-1", Action: TeardownActionDelete},
+			{Kind: "Subnet", Name: "workers", ID: "subnet-2", Action: TeardownActionDelete},
+			{Kind: "Allocation", ID: "allocation-uuid", Action: TeardownActionDelete},
+			{Kind: "IPBlock", Name: "child", ID: "child-ipblock-uuid", Action: TeardownActionDelete},
+			{Kind: "IPBlock", Name: "parent", ID: "ipblock-uuid", Action: TeardownActionDelete},
+			{Kind: "VPC", Name: "test-vpc", ID: "vpc-uuid", Action: TeardownActionDelete},
+		}))
+	})
+
+	It("should retain a referenced NSG", func() {
+		ncxInfraCluster.Spec.VPC.NetworkSecurityGroup = nil
+		ncxInfraCluster.Spec.VPC.NetworkSecurityGroupRef = &corev1.LocalObjectReference{Name: "shared-nsg"}
+
+		report := buildTeardownReport(ncxInfraCluster, nil)
+		Expect(report.Resources).To(ContainElement(teardownReportEntry{
+			Kind: "NetworkSecurityGroup", Name: "shared-nsg", ID: "nsg-uuid", Action: TeardownActionRetain,
+			Reason: "owned by NcxInfraNetworkSecurityGroup shared-nsg",
+		}))
+	})
+
+	It("should retain an adopted IP block", func() {
+		ncxInfraCluster.Status.NetworkStatus.IPBlocks[0].Adopted = true
+
+		report := buildTeardownReport(ncxInfraCluster, nil)
+		Expect(report.Resources).To(ContainElements(
+			teardownReportEntry{Kind: "IPBlock", Name: "child", ID: "child-ipblock-uuid", Action: TeardownActionDelete},
+			teardownReportEntry{Kind: "IPBlock", Name: "parent", ID: "ipblock-uuid", Action: TeardownActionRetain,
+				Reason: "adopted, existed before the cluster"},
+		))
+	})
+
+	It("should write the report to a ConfigMap and remove the annotation", func() {
+		scheme := newTestScheme()
+		reconciler := &NcxInfraClusterReconciler{
+			Client: newFakeClientBuilder(scheme).WithObjects(&machines[0]).Build(),
+			Scheme: scheme,
+		}
+		clusterScope := &scope.ClusterScope{
+			Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: namespace}},
+			NcxInfraCluster: ncxInfraCluster,
+		}
+
+		Expect(reconciler.reconcileTeardownReport(ctx, clusterScope)).To(Succeed())
+		Expect(ncxInfraCluster.Annotations).NotTo(HaveKey(infrastructurev1.TeardownReportAnnotation))
+
+		configMap := &corev1.ConfigMap{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{
+			Name: clusterName + "-teardown-report", Namespace: namespace,
+		}, configMap)).To(Succeed())
+		Expect(configMap.OwnerReferences).To(HaveLen(1))
+
+		report := teardownReport{}
+		Expect(yaml.Unmarshal([]byte(configMap.Data[teardownReportKey]), &report)).To(Succeed())
+		Expect(report.Resources).To(HaveLen(9))
+		Expect(report.Resources[0].ID).To(Equal("instance-1"))
+	})
+})
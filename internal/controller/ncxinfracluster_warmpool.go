@@ -0,0 +1,273 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// warmPoolNameInfix separates the cluster name from the instance ID in the
+// name of the instances held in the warm pool of a cluster. The pool has no
+// other record: its instances are the instances of the cluster VPC named
+// after it, so that it survives controller restarts.
+const warmPoolNameInfix = "-warm-pool-"
+
+// warmPoolInstanceName returns the name of an instance held in the warm pool.
+func warmPoolInstanceName(clusterName, instanceID string) string {
+	if len(instanceID) > 8 {
+		instanceID = instanceID[:8]
+	}
+	return clusterName + warmPoolNameInfix + instanceID
+}
+
+// warmPoolSize returns the number of instances the warm pool of the cluster
+// holds at most, 0 when the pool is disabled.
+func warmPoolSize(ncxInfraCluster *infrastructurev1.NcxInfraCluster) int {
+	if ncxInfraCluster.Spec.WarmPool == nil {
+		return 0
+	}
+	return int(ncxInfraCluster.Spec.WarmPool.MaxSize)
+}
+
+// listWarmPool returns the instances held in the warm pool of the cluster.
+func listWarmPool(
+	ctx context.Context, ncxInfraClient scope.NcxInfraClientInterface, orgName string,
+	ncxInfraCluster *infrastructurev1.NcxInfraCluster,
+) ([]nico.Instance, error) {
+	network := ncxInfraCluster.Status.NetworkStatus
+	if network.VPCID() == "" {
+		return nil, nil
+	}
+	vpcIDs := map[string]bool{network.VPCID(): true}
+	for _, vpc := range network.SiteVPCs {
+		vpcIDs[vpc.ID] = true
+	}
+
+	listStart := time.Now()
+	instances, httpResp, err := ncxInfraClient.GetAllInstance(ctx, orgName)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetAllInstance")
+	recordAPIMetrics("GetAllInstance", listStart, apiErr)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	prefix := ncxInfraCluster.Name + warmPoolNameInfix
+	var pool []nico.Instance
+	for _, instance := range instances {
+		if vpcIDs[instance.GetVpcId()] && strings.HasPrefix(instance.GetName(), prefix) {
+			pool = append(pool, instance)
+		}
+	}
+	return pool, nil
+}
+
+// releaseToWarmPool hands the instance of a deleted machine to the warm pool
+// of its cluster instead of deleting it. The instance is renamed and reimaged
+// without bootstrap data, so that nothing of the node of the machine is left
+// on it. It returns false when the instance must be deleted, because the pool
+// is disabled or full, or the machine is not reusable: without an operating
+// system to reimage it with, placed on a specific machine or in another tenant
+// than the cluster, sent to repair, waiting for a verified erase, or failed.
+func (r *NcxInfraMachineReconciler) releaseToWarmPool(
+	ctx context.Context, machineScope *scope.MachineScope,
+) (bool, error) {
+	ncxInfraCluster := machineScope.NcxInfraCluster
+	machine := machineScope.NcxInfraMachine
+	if warmPoolSize(ncxInfraCluster) == 0 || !ncxInfraCluster.DeletionTimestamp.IsZero() ||
+		(machineScope.Cluster != nil && !machineScope.Cluster.DeletionTimestamp.IsZero()) ||
+		machine.Spec.InstanceType.ID == "" || operatingSystemID(machine) == "" ||
+		machineScope.TenantID() != ncxInfraCluster.Spec.TenantID ||
+		buildDeleteRequest(machineScope) != nil ||
+		(machine.Spec.Deletion != nil && machine.Spec.Deletion.SecureErase == infrastructurev1.SecureEraseVerified) ||
+		machine.Status.FailureReason != nil ||
+		machineScope.InstanceState() != string(nico.INSTANCESTATUS_READY) {
+		return false, nil
+	}
+
+	r.warmPoolMu.Lock()
+	defer r.warmPoolMu.Unlock()
+
+	pool, err := listWarmPool(ctx, machineScope.NcxInfraClient, machineScope.OrgName, ncxInfraCluster)
+	if err != nil {
+		return false, err
+	}
+	if len(pool) >= warmPoolSize(ncxInfraCluster) {
+		return false, nil
+	}
+
+	name := warmPoolInstanceName(ncxInfraCluster.Name, machineScope.InstanceID())
+	noUserData := ""
+	updateReq := nico.InstanceUpdateRequest{
+		Name:                 *nico.NewNullableString(&name),
+		UserData:             *nico.NewNullableString(&noUserData),
+		TriggerReboot:        *nico.NewNullableBool(nico.PtrBool(true)),
+		RebootWithCustomIpxe: *nico.NewNullableBool(nico.PtrBool(true)),
+	}
+	updateStart := time.Now()
+	_, httpResp, err := machineScope.NcxInfraClient.UpdateInstance(
+		ctx, machineScope.OrgName, machineScope.InstanceID(), updateReq)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "UpdateInstance")
+	recordAPIMetrics("UpdateInstance", updateStart, apiErr)
+	if apiErr != nil {
+		return false, apiErr
+	}
+
+	log.FromContext(ctx).Info("Released instance to the warm pool", "name", name, "poolSize", len(pool)+1)
+	r.recordEvent(machine, corev1.EventTypeNormal, "InstanceReleasedToWarmPool",
+		"Released instance %s to the warm pool of cluster %s", machineScope.InstanceID(), ncxInfraCluster.Name)
+	return true, nil
+}
+
+// takeFromWarmPool hands an instance of the warm pool to a new machine, in
+// place of the creation of the instance of req. The instance must be ready,
+// of the instance type of the request and attached to its primary network. It
+// is renamed and reimaged with the bootstrap data, interfaces and settings of
+// the request. It returns nil when no instance of the pool matches, or when
+// the request targets a specific machine, or another tenant than the one of
+// the cluster the instances of the pool belong to. The machine must hold a
+// provisioning slot of the cluster, the instance is reimaged.
+func (r *NcxInfraMachineReconciler) takeFromWarmPool(
+	ctx context.Context, machineScope *scope.MachineScope, req nico.InstanceCreateRequest,
+) (*nico.Instance, error) {
+	if warmPoolSize(machineScope.NcxInfraCluster) == 0 || req.MachineId != nil ||
+		req.InstanceTypeId == nil || len(req.Interfaces) == 0 ||
+		req.TenantId != machineScope.NcxInfraCluster.Spec.TenantID {
+		return nil, nil
+	}
+
+	r.warmPoolMu.Lock()
+	defer r.warmPoolMu.Unlock()
+
+	pool, err := listWarmPool(ctx, machineScope.NcxInfraClient, machineScope.OrgName, machineScope.NcxInfraCluster)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pool {
+		pooled := &pool[i]
+		if pooled.GetInstanceTypeId() != *req.InstanceTypeId || pooled.GetStatus() != nico.INSTANCESTATUS_READY ||
+			!attachedTo(pooled.Interfaces, req.Interfaces[0]) {
+			continue
+		}
+
+		updateStart := time.Now()
+		instance, httpResp, err := machineScope.NcxInfraClient.UpdateInstance(
+			ctx, machineScope.OrgName, pooled.GetId(), warmPoolUpdateRequest(req))
+		apiErr := scope.ClassifyAPIError(httpResp, err, "UpdateInstance")
+		recordAPIMetrics("UpdateInstance", updateStart, apiErr)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if instance == nil {
+			instance = pooled
+		}
+		log.FromContext(ctx).Info("Took instance from the warm pool", "instanceID", pooled.GetId(), "pooledName", pooled.GetName())
+		return instance, nil
+	}
+	return nil, nil
+}
+
+// attachedTo returns whether one of the interfaces is on the subnet or VPC
+// prefix of the requested interface.
+func attachedTo(interfaces []nico.Interface, req nico.InterfaceCreateRequest) bool {
+	for _, iface := range interfaces {
+		if req.SubnetId != nil && iface.SubnetId.Get() != nil && *iface.SubnetId.Get() == *req.SubnetId {
+			return true
+		}
+		if req.VpcPrefixId != nil && iface.VpcPrefixId.Get() != nil && *iface.VpcPrefixId.Get() == *req.VpcPrefixId {
+			return true
+		}
+	}
+	return false
+}
+
+// warmPoolUpdateRequest maps the request creating the instance of a machine to
+// the update of the instance taken from the warm pool. Booting with the custom
+// iPXE script reinstalls the operating system, so that the instance boots as
+// the node of the machine.
+func warmPoolUpdateRequest(req nico.InstanceCreateRequest) nico.InstanceUpdateRequest {
+	updateReq := nico.InstanceUpdateRequest{
+		Name:                           *nico.NewNullableString(&req.Name),
+		Description:                    req.Description,
+		TriggerReboot:                  *nico.NewNullableBool(nico.PtrBool(true)),
+		RebootWithCustomIpxe:           *nico.NewNullableBool(nico.PtrBool(true)),
+		OperatingSystemId:              req.OperatingSystemId,
+		IpxeScript:                     req.IpxeScript,
+		SshKeyGroupIds:                 req.SshKeyGroupIds,
+		NetworkSecurityGroupId:         req.NetworkSecurityGroupId,
+		UserData:                       req.UserData,
+		Labels:                         req.Labels,
+		SecondaryVpcIds:                req.SecondaryVpcIds,
+		Interfaces:                     req.Interfaces,
+		InfinibandInterfaces:           req.InfinibandInterfaces,
+		NvLinkInterfaces:               req.NvLinkInterfaces,
+		DpuExtensionServiceDeployments: req.DpuExtensionServiceDeployments,
+	}
+	if req.AlwaysBootWithCustomIpxe != nil {
+		updateReq.AlwaysBootWithCustomIpxe = *nico.NewNullableBool(req.AlwaysBootWithCustomIpxe)
+	}
+	if req.PhoneHomeEnabled != nil {
+		updateReq.PhoneHomeEnabled = *nico.NewNullableBool(req.PhoneHomeEnabled)
+	}
+	return updateReq
+}
+
+// reconcileWarmPool deletes the instances of the warm pool exceeding its
+// size, all of them when the pool is disabled or the cluster deleted, and
+// reports the size of the pool. It returns the number of instances left.
+func (r *NcxInfraClusterReconciler) reconcileWarmPool(
+	ctx context.Context, clusterScope *scope.ClusterScope,
+) (int, error) {
+	ncxInfraCluster := clusterScope.NcxInfraCluster
+	maxSize := warmPoolSize(ncxInfraCluster)
+	if !ncxInfraCluster.DeletionTimestamp.IsZero() {
+		maxSize = 0
+	}
+	if maxSize == 0 && ncxInfraCluster.Status.WarmPoolInstances == 0 && ncxInfraCluster.Spec.WarmPool == nil {
+		return 0, nil
+	}
+
+	pool, err := listWarmPool(ctx, clusterScope.NcxInfraClient, clusterScope.OrgName, ncxInfraCluster)
+	if err != nil {
+		return 0, err
+	}
+	left := len(pool)
+	for _, instance := range pool[min(maxSize, len(pool)):] {
+		if instance.GetStatus() == nico.INSTANCESTATUS_TERMINATING {
+			continue
+		}
+		log.FromContext(ctx).Info("Deleting warm pool instance", "instanceID", instance.GetId(), "name", instance.GetName())
+		if err := r.deleteResource(ctx, clusterScope, "warm pool instance", instance.GetId(),
+			func(ctx context.Context, org, id string) (*http.Response, error) {
+				return clusterScope.NcxInfraClient.DeleteInstance(ctx, org, id, nil)
+			}, "DeleteInstance"); err != nil {
+			return left, err
+		}
+		r.recordEvent(ncxInfraCluster, "WarmPoolInstanceDeleted", "Deleted instance %s of the warm pool", instance.GetId())
+	}
+	ncxInfraCluster.Status.WarmPoolInstances = int32(min(maxSize, left))
+	return left, nil
+}
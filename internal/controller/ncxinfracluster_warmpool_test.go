@@ -0,0 +1,209 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Warm pool", func() {
+	var (
+		ctx             context.Context
+		ncxInfraCluster *infrastructurev1.NcxInfraCluster
+		machineScope    *scope.MachineScope
+		mockClient      *testutil.MockNcxInfraClient
+		pool            []nico.Instance
+		updates         map[string]nico.InstanceUpdateRequest
+		deleted         []string
+	)
+
+	pooledInstance := func(id, instanceTypeID, subnetID string) nico.Instance {
+		return nico.Instance{
+			Id:             testutil.Ptr(id),
+			Name:           testutil.Ptr(warmPoolInstanceName("test-cluster", id)),
+			VpcId:          testutil.Ptr("vpc-uuid"),
+			InstanceTypeId: testutil.Ptr(instanceTypeID),
+			Status:         nico.INSTANCESTATUS_READY.Ptr(),
+			Interfaces:     []nico.Interface{{SubnetId: *nico.NewNullableString(testutil.Ptr(subnetID))}},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		pool = nil
+		updates = map[string]nico.InstanceUpdateRequest{}
+		deleted = nil
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				WarmPool: &infrastructurev1.WarmPoolSpec{MaxSize: 1},
+			},
+			Status: infrastructurev1.NcxInfraClusterStatus{
+				NetworkStatus: infrastructurev1.NetworkStatus{
+					VPC: &infrastructurev1.NetworkResourceStatus{ID: "vpc-uuid"},
+				},
+			},
+		}
+		mockClient = &testutil.MockNcxInfraClient{
+			GetAllInstanceFunc: func(ctx context.Context, org string) ([]nico.Instance, *http.Response, error) {
+				// Instances of the VPC that are not in the pool
+				other := nico.Instance{Id: testutil.Ptr("other"), Name: testutil.Ptr("worker-1"), VpcId: testutil.Ptr("vpc-uuid")}
+				return append([]nico.Instance{other}, pool...), testutil.MockHTTPResponse(200), nil
+			},
+			UpdateInstanceFunc: func(
+				ctx context.Context, org, instanceId string, req nico.InstanceUpdateRequest,
+			) (*nico.Instance, *http.Response, error) {
+				updates[instanceId] = req
+				return &nico.Instance{Id: testutil.Ptr(instanceId), Name: req.Name.Get()}, testutil.MockHTTPResponse(200), nil
+			},
+			DeleteInstanceFunc: func(
+				ctx context.Context, org, instanceId string, req *nico.InstanceDeleteRequest,
+			) (*http.Response, error) {
+				deleted = append(deleted, instanceId)
+				return testutil.MockHTTPResponse(204), nil
+			},
+		}
+		machineScope = &scope.MachineScope{
+			NcxInfraCluster: ncxInfraCluster,
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: "default"},
+				Spec: infrastructurev1.NcxInfraMachineSpec{
+					InstanceType:    infrastructurev1.InstanceTypeSpec{ID: "gb200"},
+					OperatingSystem: &infrastructurev1.OSSpec{ID: "os-uuid"},
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{
+					InstanceID:    "0123456789abcdef",
+					InstanceState: string(nico.INSTANCESTATUS_READY),
+				},
+			},
+			NcxInfraClient: mockClient,
+			OrgName:        "test-org",
+		}
+	})
+
+	It("should release the instance of a deleted machine to the pool", func() {
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &NcxInfraMachineReconciler{Recorder: recorder}
+
+		_, err := reconciler.reconcileDelete(ctx, machineScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(BeEmpty())
+		Expect(updates).To(HaveKey("0123456789abcdef"))
+		update := updates["0123456789abcdef"]
+		Expect(*update.Name.Get()).To(Equal("test-cluster-warm-pool-01234567"))
+		Expect(*update.UserData.Get()).To(BeEmpty())
+		Expect(*update.TriggerReboot.Get()).To(BeTrue())
+		Expect(update.GetRebootWithCustomIpxe()).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("InstanceReleasedToWarmPool")))
+	})
+
+	It("should delete the instance when the pool is full or the machine is not reusable", func() {
+		reconciler := &NcxInfraMachineReconciler{}
+		pool = []nico.Instance{pooledInstance("pooled", "gb200", "subnet-uuid")}
+		_, err := reconciler.reconcileDelete(ctx, machineScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(Equal([]string{"0123456789abcdef"}))
+
+		pool, deleted = nil, nil
+		machineScope.NcxInfraMachine.Spec.Deletion = &infrastructurev1.DeletionSpec{Policy: infrastructurev1.DeletionPolicyRepair}
+		_, err = reconciler.reconcileDelete(ctx, machineScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(Equal([]string{"0123456789abcdef"}))
+
+		// The instance of a machine without operating system cannot be reimaged
+		deleted = nil
+		machineScope.NcxInfraMachine.Spec.Deletion = nil
+		machineScope.NcxInfraMachine.Spec.OperatingSystem = nil
+		_, err = reconciler.reconcileDelete(ctx, machineScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(Equal([]string{"0123456789abcdef"}))
+		Expect(updates).To(BeEmpty())
+	})
+
+	It("should hand a matching pool instance to a new machine", func() {
+		pool = []nico.Instance{
+			pooledInstance("other-type", "h100", "subnet-uuid"),
+			pooledInstance("other-subnet", "gb200", "storage-uuid"),
+			pooledInstance("match", "gb200", "subnet-uuid"),
+		}
+		userData := "#cloud-config"
+		req := nico.InstanceCreateRequest{
+			Name:           "worker-2",
+			InstanceTypeId: testutil.Ptr("gb200"),
+			UserData:       *nico.NewNullableString(&userData),
+			Interfaces:     []nico.InterfaceCreateRequest{{SubnetId: testutil.Ptr("subnet-uuid")}},
+			Labels:         map[string]string{"team": "ml"},
+		}
+		reconciler := &NcxInfraMachineReconciler{}
+
+		instance, err := reconciler.takeFromWarmPool(ctx, machineScope, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance.GetId()).To(Equal("match"))
+		Expect(updates).To(HaveLen(1))
+		update := updates["match"]
+		Expect(*update.Name.Get()).To(Equal("worker-2"))
+		Expect(*update.UserData.Get()).To(Equal(userData))
+		Expect(update.GetTriggerReboot()).To(BeTrue())
+		Expect(update.GetRebootWithCustomIpxe()).To(BeTrue())
+		Expect(update.Interfaces).To(Equal(req.Interfaces))
+		Expect(update.Labels).To(Equal(req.Labels))
+
+		// A request targeting a specific machine is never served from the pool
+		req.MachineId = testutil.Ptr("machine-uuid")
+		Expect(reconciler.takeFromWarmPool(ctx, machineScope, req)).To(BeNil())
+	})
+
+	It("should delete the pool instances exceeding its size", func() {
+		pool = []nico.Instance{
+			pooledInstance("first", "gb200", "subnet-uuid"),
+			pooledInstance("second", "gb200", "subnet-uuid"),
+		}
+		clusterScope := &scope.ClusterScope{
+			Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+			NcxInfraCluster: ncxInfraCluster,
+			NcxInfraClient:  mockClient,
+			OrgName:         "test-org",
+		}
+		reconciler := &NcxInfraClusterReconciler{Client: newFakeClientBuilder(newTestScheme()).Build()}
+
+		Expect(reconciler.reconcileWarmPool(ctx, clusterScope)).To(Equal(2))
+		Expect(deleted).To(Equal([]string{"second"}))
+		Expect(ncxInfraCluster.Status.WarmPoolInstances).To(Equal(int32(1)))
+
+		// The cluster deletion waits for all of them to be deleted
+		deleted = nil
+		now := metav1.Now()
+		ncxInfraCluster.DeletionTimestamp = &now
+		result, err := reconciler.reconcileDelete(ctx, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).NotTo(BeZero())
+		Expect(deleted).To(Equal([]string{"first", "second"}))
+		Expect(ncxInfraCluster.Status.WarmPoolInstances).To(BeZero())
+	})
+})
@@ -0,0 +1,255 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// instanceTypeDiscoveryInterval paces the refresh of the instance types of a site
+const instanceTypeDiscoveryInterval = 5 * time.Minute
+
+// NcxInfraInstanceTypeReconciler discovers the instance types of the site of
+// each NcxInfraCluster, and reflects them as NcxInfraInstanceTypes in the
+// namespace of the cluster.
+type NcxInfraInstanceTypeReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// NcxInfraClient can be set for testing or simulation mode to inject a client
+	NcxInfraClient scope.NcxInfraClientInterface
+	// OrgName can be set for testing or simulation mode
+	OrgName string
+
+	// DefaultCredentials is the credentials secret of the objects that do not
+	// reference one, when their namespace has no default NcxInfraIdentity
+	DefaultCredentials corev1.SecretReference
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfrainstancetypes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfrainstancetypes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraclusters,verbs=get;list;watch
+
+// Reconcile discovers the instance types of the site of a NcxInfraCluster
+func (r *NcxInfraInstanceTypeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	ncxInfraCluster := &infrastructurev1.NcxInfraCluster{}
+	if err := r.Get(ctx, req.NamespacedName, ncxInfraCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !ncxInfraCluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	if annotations.HasPaused(ncxInfraCluster) {
+		logger.Info("NcxInfraCluster is marked as paused, skipping instance type discovery")
+		return ctrl.Result{}, nil
+	}
+
+	ncxInfraClient, orgName := r.NcxInfraClient, r.OrgName
+	if ncxInfraClient == nil {
+		var err error
+		ncxInfraClient, orgName, err = scope.NewClientFromSecret(
+			ctx, r.Client, ncxInfraCluster.Spec.Authentication, ncxInfraCluster.Namespace, r.DefaultCredentials,
+			ncxInfraCluster.Spec.SiteRef)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	siteID, err := scope.ResolveSiteID(ctx, ncxInfraClient, orgName, ncxInfraCluster.Spec.SiteRef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	listStart := time.Now()
+	instanceTypes, httpResp, err := ncxInfraClient.GetAllInstanceType(ctx, orgName, siteID)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetAllInstanceType")
+	recordAPIMetrics("GetAllInstanceType", listStart, apiErr)
+	if apiErr != nil {
+		return ctrl.Result{}, apiErr
+	}
+
+	discovered := make(map[string]bool, len(instanceTypes))
+	for i := range instanceTypes {
+		if instanceTypes[i].Id == nil {
+			continue
+		}
+		name, err := r.reconcileInstanceType(ctx, ncxInfraCluster, siteID, &instanceTypes[i])
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		discovered[name] = true
+	}
+
+	// Delete the instance types the site no longer offers
+	existing := &infrastructurev1.NcxInfraInstanceTypeList{}
+	if err := r.List(ctx, existing, client.InNamespace(ncxInfraCluster.Namespace),
+		client.MatchingLabels{infrastructurev1.InstanceTypeSiteLabel: siteID}); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range existing.Items {
+		if discovered[existing.Items[i].Name] {
+			continue
+		}
+		logger.Info("Deleting NcxInfraInstanceType no longer offered by the site", "name", existing.Items[i].Name)
+		if err := r.Delete(ctx, &existing.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+
+	logger.V(1).Info("Discovered instance types", "siteID", siteID, "count", len(discovered))
+	return ctrl.Result{RequeueAfter: instanceTypeDiscoveryInterval}, nil
+}
+
+// reconcileInstanceType creates or updates the NcxInfraInstanceType of an
+// instance type, and returns its name. The NcxInfraClusters of the site own
+// it, so it is garbage collected with the last of them.
+func (r *NcxInfraInstanceTypeReconciler) reconcileInstanceType(
+	ctx context.Context, ncxInfraCluster *infrastructurev1.NcxInfraCluster, siteID string, it *nico.InstanceType,
+) (string, error) {
+	instanceType := &infrastructurev1.NcxInfraInstanceType{}
+	key := client.ObjectKey{Namespace: ncxInfraCluster.Namespace, Name: instanceTypeObjectName(siteID, it)}
+	if err := r.Get(ctx, key, instanceType); apierrors.IsNotFound(err) {
+		instanceType = &infrastructurev1.NcxInfraInstanceType{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+				Labels:    map[string]string{infrastructurev1.InstanceTypeSiteLabel: siteID},
+			},
+			Spec: infrastructurev1.NcxInfraInstanceTypeSpec{SiteID: siteID, ID: it.GetId()},
+		}
+		if err := controllerutil.SetOwnerReference(ncxInfraCluster, instanceType, r.Scheme); err != nil {
+			return "", err
+		}
+		if err := r.Create(ctx, instanceType); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	// The status is not set on creation, patch it along with the spec
+	patchHelper, err := patch.NewHelper(instanceType, r.Client)
+	if err != nil {
+		return "", err
+	}
+	if instanceType.Labels == nil {
+		instanceType.Labels = map[string]string{}
+	}
+	instanceType.Labels[infrastructurev1.InstanceTypeSiteLabel] = siteID
+	instanceType.Spec = infrastructurev1.NcxInfraInstanceTypeSpec{
+		SiteID:      siteID,
+		ID:          it.GetId(),
+		Name:        it.GetName(),
+		Description: it.GetDescription(),
+	}
+	instanceType.Status = instanceTypeStatus(it)
+	if err := controllerutil.SetOwnerReference(ncxInfraCluster, instanceType, r.Scheme); err != nil {
+		return "", err
+	}
+	if err := patchHelper.Patch(ctx, instanceType); err != nil {
+		return "", err
+	}
+	return instanceType.Name, nil
+}
+
+// invalidNameChars matches the characters not allowed in object names
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// instanceTypeObjectName returns the name of the NcxInfraInstanceType of an
+// instance type: its name, suffixed with the beginning of the site ID so that
+// the instance types of several sites can share a namespace.
+func instanceTypeObjectName(siteID string, it *nico.InstanceType) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(it.GetName()), "-"), "-")
+	if name == "" {
+		name = it.GetId()
+	}
+	suffix := "-" + siteID[:min(8, len(siteID))]
+	return strings.TrimRight(name[:min(len(name), 253-len(suffix))], "-") + suffix
+}
+
+// instanceTypeStatus converts the capabilities and allocation counts of an
+// instance type into the status of its NcxInfraInstanceType.
+func instanceTypeStatus(it *nico.InstanceType) infrastructurev1.NcxInfraInstanceTypeStatus {
+	now := metav1.Now()
+	status := infrastructurev1.NcxInfraInstanceTypeStatus{
+		Capacity:          capacityFromCapabilities(it.MachineCapabilities),
+		LastDiscoveryTime: &now,
+	}
+	if it.Status != nil {
+		status.State = string(*it.Status)
+	}
+
+	for _, capability := range it.MachineCapabilities {
+		component := infrastructurev1.HardwareComponent{
+			Type:   capability.GetType(),
+			Name:   capability.GetName(),
+			Vendor: capability.GetVendor(),
+			Count:  capability.GetCount(),
+		}
+		component.Cores = capability.GetCores()
+		component.Threads = capability.GetThreads()
+		component.Capacity = capability.GetCapacity()
+		component.DeviceType = capability.GetDeviceType()
+		status.Components = append(status.Components, component)
+
+		if component.Type == "GPU" {
+			if status.GPUModel == "" {
+				status.GPUModel = component.Name
+			}
+			status.GPUCount += max(component.Count, 1)
+		}
+	}
+
+	if stats := it.AllocationStats; stats != nil {
+		status.AvailableMachines = stats.GetUnusedUsable()
+		status.AllocatedMachines = stats.GetTotal()
+		status.UsedMachines = stats.GetUsed()
+	}
+	return status
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NcxInfraInstanceTypeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1.NcxInfraCluster{}).
+		Named("ncxinfrainstancetype").
+		Complete(r)
+}
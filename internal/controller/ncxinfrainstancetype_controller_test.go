@@ -0,0 +1,180 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+)
+
+var _ = Describe("NcxInfraInstanceType Controller", func() {
+	const (
+		namespace = "default"
+		siteID    = "0f5e2a8c-site"
+	)
+
+	var (
+		ctx             context.Context
+		ncxInfraCluster *infrastructurev1.NcxInfraCluster
+		instanceTypes   []nico.InstanceType
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: namespace, UID: "cluster-uid"},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				SiteRef: infrastructurev1.SiteReference{ID: siteID},
+			},
+		}
+		gpu := nico.MachineCapability{
+			Type:     testutil.Ptr("GPU"),
+			Name:     testutil.Ptr("NVIDIA GB200"),
+			Count:    *nico.NewNullableInt32(testutil.Ptr(int32(4))),
+			Capacity: *nico.NewNullableString(testutil.Ptr("186GB")),
+		}
+		cpu := nico.MachineCapability{
+			Type:    testutil.Ptr("CPU"),
+			Name:    testutil.Ptr("NVIDIA Grace"),
+			Count:   *nico.NewNullableInt32(testutil.Ptr(int32(2))),
+			Cores:   *nico.NewNullableInt32(testutil.Ptr(int32(72))),
+			Threads: *nico.NewNullableInt32(testutil.Ptr(int32(72))),
+		}
+		nic := nico.MachineCapability{
+			Type:   testutil.Ptr("InfiniBand"),
+			Name:   testutil.Ptr("ConnectX-7"),
+			Vendor: *nico.NewNullableString(testutil.Ptr("Mellanox")),
+			Count:  *nico.NewNullableInt32(testutil.Ptr(int32(4))),
+		}
+		instanceTypes = []nico.InstanceType{{
+			Id:                  testutil.Ptr("gb200-uuid"),
+			Name:                testutil.Ptr("GB200_NVL72"),
+			Description:         testutil.Ptr("GB200 compute tray"),
+			Status:              nico.INSTANCETYPESTATUS_READY.Ptr(),
+			MachineCapabilities: []nico.MachineCapability{cpu, gpu, nic},
+			AllocationStats: &nico.InstanceTypeAllocationStats{
+				Total:        testutil.Ptr(int32(18)),
+				Used:         testutil.Ptr(int32(12)),
+				UnusedUsable: testutil.Ptr(int32(5)),
+			},
+		}}
+	})
+
+	newReconciler := func(objects ...client.Object) *NcxInfraInstanceTypeReconciler {
+		scheme := newTestScheme()
+		k8sClient := newFakeClientBuilder(scheme).
+			WithObjects(append(objects, ncxInfraCluster)...).
+			WithStatusSubresource(&infrastructurev1.NcxInfraInstanceType{}).
+			Build()
+		return &NcxInfraInstanceTypeReconciler{
+			Client: k8sClient,
+			Scheme: scheme,
+			NcxInfraClient: &testutil.MockNcxInfraClient{
+				GetAllInstanceTypeFunc: func(ctx context.Context, org, site string) ([]nico.InstanceType, *http.Response, error) {
+					Expect(site).To(Equal(siteID))
+					return instanceTypes, testutil.MockHTTPResponse(200), nil
+				},
+			},
+			OrgName: "test-org",
+		}
+	}
+
+	It("should reflect the instance types of the site of the cluster", func() {
+		reconciler := newReconciler()
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "test-cluster", Namespace: namespace},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(instanceTypeDiscoveryInterval))
+
+		instanceType := &infrastructurev1.NcxInfraInstanceType{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "gb200-nvl72-0f5e2a8c", Namespace: namespace},
+			instanceType)).To(Succeed())
+		Expect(instanceType.Labels).To(HaveKeyWithValue(infrastructurev1.InstanceTypeSiteLabel, siteID))
+		Expect(instanceType.OwnerReferences).To(HaveLen(1))
+		Expect(instanceType.OwnerReferences[0].Name).To(Equal("test-cluster"))
+		Expect(instanceType.Spec).To(Equal(infrastructurev1.NcxInfraInstanceTypeSpec{
+			SiteID: siteID, ID: "gb200-uuid", Name: "GB200_NVL72", Description: "GB200 compute tray",
+		}))
+
+		status := instanceType.Status
+		Expect(status.GPUModel).To(Equal("NVIDIA GB200"))
+		Expect(status.GPUCount).To(Equal(int32(4)))
+		Expect(status.Capacity).To(HaveKeyWithValue(corev1.ResourceCPU, resource.MustParse("144")))
+		Expect(status.Components).To(ContainElement(infrastructurev1.HardwareComponent{
+			Type: "InfiniBand", Name: "ConnectX-7", Vendor: "Mellanox", Count: 4,
+		}))
+		Expect(status.AvailableMachines).To(Equal(int32(5)))
+		Expect(status.AllocatedMachines).To(Equal(int32(18)))
+		Expect(status.UsedMachines).To(Equal(int32(12)))
+		Expect(status.State).To(Equal("Ready"))
+		Expect(status.LastDiscoveryTime).NotTo(BeNil())
+	})
+
+	It("should delete the instance types the site no longer offers", func() {
+		stale := &infrastructurev1.NcxInfraInstanceType{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "h100-0f5e2a8c",
+				Namespace: namespace,
+				Labels:    map[string]string{infrastructurev1.InstanceTypeSiteLabel: siteID},
+			},
+			Spec: infrastructurev1.NcxInfraInstanceTypeSpec{SiteID: siteID, ID: "h100-uuid"},
+		}
+		otherSite := &infrastructurev1.NcxInfraInstanceType{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "h100-other",
+				Namespace: namespace,
+				Labels:    map[string]string{infrastructurev1.InstanceTypeSiteLabel: "other-site"},
+			},
+			Spec: infrastructurev1.NcxInfraInstanceTypeSpec{SiteID: "other-site", ID: "h100-uuid"},
+		}
+		reconciler := newReconciler(stale, otherSite)
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "test-cluster", Namespace: namespace},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = reconciler.Get(ctx, client.ObjectKeyFromObject(stale), &infrastructurev1.NcxInfraInstanceType{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(otherSite), &infrastructurev1.NcxInfraInstanceType{})).
+			To(Succeed())
+	})
+})
+
+var _ = Describe("instanceTypeObjectName", func() {
+	It("should derive a valid object name from the instance type name", func() {
+		Expect(instanceTypeObjectName("0f5e2a8c-site", &nico.InstanceType{Name: testutil.Ptr("GB200 / NVL72_")})).
+			To(Equal("gb200-nvl72-0f5e2a8c"))
+		Expect(instanceTypeObjectName("0f5e2a8c-site", &nico.InstanceType{Id: testutil.Ptr("it-uuid")})).
+			To(Equal("it-uuid-0f5e2a8c"))
+	})
+})
@@ -0,0 +1,521 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"net/netip"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/controllers/clustercache"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+const (
+	// NcxInfraLoadBalancerFinalizer allows the controller to withdraw the
+	// addresses of the Services before the load balancer is deleted.
+	NcxInfraLoadBalancerFinalizer = "ncxinfraloadbalancer.infrastructure.cluster.x-k8s.io"
+
+	// AddressesAssignedCondition reports whether every Service of the load
+	// balancer has an address.
+	AddressesAssignedCondition clusterv1.ConditionType = "AddressesAssigned"
+
+	// loadBalancerServicesWatch is the name of the watch of the Services of a
+	// workload cluster.
+	loadBalancerServicesWatch = "ncxinfraloadbalancer-watchServices"
+)
+
+// NcxInfraLoadBalancerReconciler assigns the addresses of an
+// NcxInfraLoadBalancer to the Services of type LoadBalancer of a workload
+// cluster, in place of the cloud controller manager NVIDIA Carbide does not
+// have. Announcing the addresses is left to a speaker in the workload cluster.
+type NcxInfraLoadBalancerReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// ClusterCache gives access to the Services of the workload clusters
+	ClusterCache clustercache.ClusterCache
+
+	// WatchFilterValue is the value of the cluster.x-k8s.io/watch-filter label
+	// of the objects to reconcile, all of them when empty
+	WatchFilterValue string
+
+	// controller watches the Services of the workload clusters
+	controller controller.Controller
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraloadbalancers,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraloadbalancers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraloadbalancers/finalizers,verbs=update
+
+// Reconcile handles NcxInfraLoadBalancer reconciliation
+func (r *NcxInfraLoadBalancerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, reterr error) {
+	logger := log.FromContext(ctx)
+
+	loadBalancer := &infrastructurev1.NcxInfraLoadBalancer{}
+	if err := r.Get(ctx, req.NamespacedName, loadBalancer); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	cluster := &clusterv1.Cluster{}
+	clusterKey := client.ObjectKey{Namespace: loadBalancer.Namespace, Name: loadBalancer.Spec.ClusterName}
+	if err := r.Get(ctx, clusterKey, cluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// The Services went away with the cluster, there is nothing to withdraw
+		cluster = nil
+	}
+	logger = logger.WithValues("cluster", loadBalancer.Spec.ClusterName)
+	ctx = log.IntoContext(ctx, logger)
+
+	if cluster != nil && annotations.IsPaused(cluster, loadBalancer) {
+		logger.Info("NcxInfraLoadBalancer or Cluster is marked as paused, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(loadBalancer, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		sortConditions(loadBalancer)
+		if err := patchHelper.Patch(ctx, loadBalancer); err != nil {
+			res, reterr = patchResult(res, reterr, fmt.Errorf("failed to patch NcxInfraLoadBalancer: %w", err))
+		}
+	}()
+
+	if !loadBalancer.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDelete(ctx, loadBalancer, cluster)
+	}
+	if cluster == nil {
+		logger.Info("Waiting for the Cluster of the NcxInfraLoadBalancer")
+		conditions.Set(loadBalancer, metav1.Condition{
+			Type:    string(AddressesAssignedCondition),
+			Status:  metav1.ConditionUnknown,
+			Reason:  "WaitingForCluster",
+			Message: fmt.Sprintf("Cluster %s not found", loadBalancer.Spec.ClusterName),
+		})
+		return ctrl.Result{}, nil
+	}
+	controllerutil.AddFinalizer(loadBalancer, NcxInfraLoadBalancerFinalizer)
+
+	return ctrl.Result{}, r.reconcileNormal(ctx, loadBalancer, cluster)
+}
+
+// reconcileNormal assigns an address of the pool to each Service of the load
+// balancer and reports it in the status of the Service. A Service keeps its
+// address, and one that requests an address in spec.loadBalancerIP gets that
+// address only.
+func (r *NcxInfraLoadBalancerReconciler) reconcileNormal(
+	ctx context.Context, loadBalancer *infrastructurev1.NcxInfraLoadBalancer, cluster *clusterv1.Cluster,
+) error {
+	logger := log.FromContext(ctx)
+
+	pool, err := parseAddressPool(loadBalancer.Spec.Addresses)
+	if err != nil {
+		conditions.Set(loadBalancer, metav1.Condition{
+			Type:    string(AddressesAssignedCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidAddresses",
+			Message: err.Error(),
+		})
+		return nil
+	}
+
+	remoteClient, err := r.ClusterCache.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		// The cluster source requeues the load balancer once the cluster is connected
+		logger.V(4).Info("Workload cluster is not reachable", "error", err.Error())
+		conditions.Set(loadBalancer, metav1.Condition{
+			Type:    string(AddressesAssignedCondition),
+			Status:  metav1.ConditionUnknown,
+			Reason:  "WorkloadClusterNotReachable",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	if err := r.watchServices(ctx, cluster); err != nil {
+		return err
+	}
+
+	services := &corev1.ServiceList{}
+	if err := remoteClient.List(ctx, services); err != nil {
+		return fmt.Errorf("failed to list the Services of the workload cluster: %w", err)
+	}
+	assigned, pending := assignAddresses(loadBalancer, pool, services.Items)
+
+	var errs []error
+	for i := range services.Items {
+		service := &services.Items[i]
+		key := client.ObjectKeyFromObject(service)
+		if address, ok := assigned[key]; ok {
+			errs = append(errs, setServiceIngress(ctx, remoteClient, service, address.String()))
+			continue
+		}
+		// Withdraw the address of the Services the load balancer no longer serves
+		if previous := serviceAddress(loadBalancer, key); previous != "" && !slices.Contains(pending, key) {
+			if ingressAddress(service) == previous {
+				errs = append(errs, setServiceIngress(ctx, remoteClient, service, ""))
+			}
+		}
+	}
+
+	loadBalancer.Status.Services = nil
+	used := make([]netip.Addr, 0, len(assigned))
+	for key, address := range assigned {
+		loadBalancer.Status.Services = append(loadBalancer.Status.Services, infrastructurev1.LoadBalancerServiceStatus{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Address:   address.String(),
+		})
+		used = append(used, address)
+	}
+	slices.SortFunc(loadBalancer.Status.Services, func(a, b infrastructurev1.LoadBalancerServiceStatus) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	loadBalancer.Status.Available = pool.available(len(used))
+
+	if len(pending) > 0 {
+		names := make([]string, 0, len(pending))
+		for _, key := range pending {
+			names = append(names, key.String())
+		}
+		message := "No address available for Services " + strings.Join(names, ", ")
+		if !conditions.IsFalse(loadBalancer, string(AddressesAssignedCondition)) && r.Recorder != nil {
+			r.Recorder.Event(loadBalancer, corev1.EventTypeWarning, "AddressesExhausted", message)
+		}
+		conditions.Set(loadBalancer, metav1.Condition{
+			Type:    string(AddressesAssignedCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "AddressesExhausted",
+			Message: message,
+		})
+	} else {
+		conditions.Set(loadBalancer, metav1.Condition{
+			Type:   string(AddressesAssignedCondition),
+			Status: metav1.ConditionTrue,
+			Reason: "AddressesAssigned",
+		})
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// reconcileDelete withdraws the addresses of the Services of a deleted load
+// balancer, so that they are no longer advertised, then removes its
+// finalizer. The Services are left alone when the cluster is gone or not
+// reachable.
+func (r *NcxInfraLoadBalancerReconciler) reconcileDelete(
+	ctx context.Context, loadBalancer *infrastructurev1.NcxInfraLoadBalancer, cluster *clusterv1.Cluster,
+) error {
+	logger := log.FromContext(ctx)
+
+	if cluster != nil && cluster.DeletionTimestamp.IsZero() {
+		remoteClient, err := r.ClusterCache.GetClient(ctx, util.ObjectKey(cluster))
+		if err != nil {
+			logger.Info("Workload cluster is not reachable, leaving the addresses of its Services", "error", err.Error())
+		} else {
+			for _, assigned := range loadBalancer.Status.Services {
+				service := &corev1.Service{}
+				key := client.ObjectKey{Namespace: assigned.Namespace, Name: assigned.Name}
+				if err := remoteClient.Get(ctx, key, service); err != nil {
+					if apierrors.IsNotFound(err) {
+						continue
+					}
+					return fmt.Errorf("failed to get Service %s: %w", key, err)
+				}
+				if ingressAddress(service) == assigned.Address {
+					if err := setServiceIngress(ctx, remoteClient, service, ""); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	loadBalancer.Status.Services = nil
+	controllerutil.RemoveFinalizer(loadBalancer, NcxInfraLoadBalancerFinalizer)
+	return nil
+}
+
+// watchServices requeues the load balancers of a cluster on the events of
+// its Services. The watch is only added once per connection to the cluster.
+func (r *NcxInfraLoadBalancerReconciler) watchServices(ctx context.Context, cluster *clusterv1.Cluster) error {
+	return r.ClusterCache.Watch(ctx, util.ObjectKey(cluster), clustercache.NewWatcher(clustercache.WatcherOptions{
+		Name:    loadBalancerServicesWatch,
+		Watcher: r.controller,
+		Kind:    &corev1.Service{},
+		EventHandler: handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []ctrl.Request {
+			return r.clusterToLoadBalancers(ctx, cluster)
+		}),
+	}))
+}
+
+// clusterToLoadBalancers maps a Cluster to the load balancers of its Services.
+func (r *NcxInfraLoadBalancerReconciler) clusterToLoadBalancers(ctx context.Context, o client.Object) []ctrl.Request {
+	loadBalancers := &infrastructurev1.NcxInfraLoadBalancerList{}
+	if err := r.List(ctx, loadBalancers, client.InNamespace(o.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list NcxInfraLoadBalancers", "cluster", o.GetName())
+		return nil
+	}
+	var requests []ctrl.Request
+	for _, loadBalancer := range loadBalancers.Items {
+		if loadBalancer.Spec.ClusterName == o.GetName() {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&loadBalancer)})
+		}
+	}
+	return requests
+}
+
+// servesService reports whether a load balancer assigns an address to a
+// Service: a Service of type LoadBalancer of its class, or without class when
+// the load balancer is the default one.
+func servesService(loadBalancer *infrastructurev1.NcxInfraLoadBalancer, service *corev1.Service) bool {
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer || !service.DeletionTimestamp.IsZero() {
+		return false
+	}
+	class := loadBalancer.Spec.LoadBalancerClass
+	if class == "" {
+		class = infrastructurev1.DefaultLoadBalancerClass
+	}
+	if service.Spec.LoadBalancerClass == nil {
+		return loadBalancer.Spec.DefaultClass
+	}
+	return *service.Spec.LoadBalancerClass == class
+}
+
+// assignAddresses returns the address of each Service a load balancer serves,
+// and the Services left without one. The Services first keep the address
+// recorded in the status of the load balancer, or reported in their own
+// status, then the others get the lowest free address of the pool, in the
+// order of their namespace and name.
+func assignAddresses(
+	loadBalancer *infrastructurev1.NcxInfraLoadBalancer, pool addressPool, services []corev1.Service,
+) (map[types.NamespacedName]netip.Addr, []types.NamespacedName) {
+	var served []*corev1.Service
+	for i := range services {
+		if servesService(loadBalancer, &services[i]) {
+			served = append(served, &services[i])
+		}
+	}
+	slices.SortFunc(served, func(a, b *corev1.Service) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+
+	assigned := make(map[types.NamespacedName]netip.Addr, len(served))
+	used := make(map[netip.Addr]bool, len(served))
+	claim := func(key types.NamespacedName, candidate string) bool {
+		address, err := netip.ParseAddr(candidate)
+		if err != nil || used[address] || !pool.contains(address) {
+			return false
+		}
+		assigned[key], used[address] = address, true
+		return true
+	}
+
+	var unassigned []*corev1.Service
+	for _, service := range served {
+		key := client.ObjectKeyFromObject(service)
+		requested := service.Spec.LoadBalancerIP //nolint:staticcheck // still the way to request an address
+		if requested != "" {
+			if !claim(key, requested) {
+				unassigned = append(unassigned, service)
+			}
+			continue
+		}
+		if !claim(key, serviceAddress(loadBalancer, key)) && !claim(key, ingressAddress(service)) {
+			unassigned = append(unassigned, service)
+		}
+	}
+
+	var pending []types.NamespacedName
+	for _, service := range unassigned {
+		key := client.ObjectKeyFromObject(service)
+		if service.Spec.LoadBalancerIP != "" { //nolint:staticcheck // the requested address is taken
+			pending = append(pending, key)
+			continue
+		}
+		address, ok := pool.next(used)
+		if !ok {
+			pending = append(pending, key)
+			continue
+		}
+		assigned[key], used[address] = address, true
+	}
+	return assigned, pending
+}
+
+// serviceAddress returns the address recorded for a Service in the status of
+// a load balancer, empty when it has none.
+func serviceAddress(loadBalancer *infrastructurev1.NcxInfraLoadBalancer, key types.NamespacedName) string {
+	for _, service := range loadBalancer.Status.Services {
+		if service.Namespace == key.Namespace && service.Name == key.Name {
+			return service.Address
+		}
+	}
+	return ""
+}
+
+// ingressAddress returns the first address reported in the status of a
+// Service, empty when it has none.
+func ingressAddress(service *corev1.Service) string {
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+	}
+	return ""
+}
+
+// setServiceIngress reports the address of a Service in its status, or
+// withdraws it when address is empty. The VIP mode tells kube-proxy that the
+// nodes receive the traffic of the address themselves.
+func setServiceIngress(ctx context.Context, remoteClient client.Client, service *corev1.Service, address string) error {
+	var ingress []corev1.LoadBalancerIngress
+	if address != "" {
+		ingress = []corev1.LoadBalancerIngress{{IP: address, IPMode: ptr.To(corev1.LoadBalancerIPModeVIP)}}
+	}
+	if len(ingress) == 0 && len(service.Status.LoadBalancer.Ingress) == 0 ||
+		len(ingress) == 1 && len(service.Status.LoadBalancer.Ingress) == 1 &&
+			service.Status.LoadBalancer.Ingress[0].IP == address {
+		return nil
+	}
+	original := service.DeepCopy()
+	service.Status.LoadBalancer.Ingress = ingress
+	if err := remoteClient.Status().Patch(ctx, service, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to set the address of Service %s: %w", client.ObjectKeyFromObject(service), err)
+	}
+	return nil
+}
+
+// addressRange is a range of addresses of the pool of a load balancer, from
+// first to last included.
+type addressRange struct {
+	first, last netip.Addr
+}
+
+// addressPool is the pool of addresses of a load balancer.
+type addressPool []addressRange
+
+// parseAddressPool parses the CIDRs and ranges of the addresses of a load
+// balancer.
+func parseAddressPool(addresses []string) (addressPool, error) {
+	pool := make(addressPool, 0, len(addresses))
+	for _, address := range addresses {
+		if first, last, ok := strings.Cut(address, "-"); ok {
+			firstAddr, errFirst := netip.ParseAddr(strings.TrimSpace(first))
+			lastAddr, errLast := netip.ParseAddr(strings.TrimSpace(last))
+			if errFirst != nil || errLast != nil || firstAddr.Is4() != lastAddr.Is4() || firstAddr.Compare(lastAddr) > 0 {
+				return nil, fmt.Errorf("invalid address range %q", address)
+			}
+			pool = append(pool, addressRange{first: firstAddr, last: lastAddr})
+			continue
+		}
+		prefix, err := netip.ParsePrefix(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address CIDR %q: %w", address, err)
+		}
+		prefix = prefix.Masked()
+		last := prefix.Addr().AsSlice()
+		for bit := prefix.Bits(); bit < len(last)*8; bit++ {
+			last[bit/8] |= 0x80 >> (bit % 8)
+		}
+		lastAddr, _ := netip.AddrFromSlice(last)
+		pool = append(pool, addressRange{first: prefix.Addr(), last: lastAddr})
+	}
+	return pool, nil
+}
+
+// contains reports whether an address belongs to the pool.
+func (p addressPool) contains(address netip.Addr) bool {
+	return slices.ContainsFunc(p, func(r addressRange) bool {
+		return address.Is4() == r.first.Is4() && r.first.Compare(address) <= 0 && address.Compare(r.last) <= 0
+	})
+}
+
+// next returns the lowest address of the pool not used, in the order of the
+// ranges.
+func (p addressPool) next(used map[netip.Addr]bool) (netip.Addr, bool) {
+	for _, r := range p {
+		for address := r.first; address.IsValid() && address.Compare(r.last) <= 0; address = address.Next() {
+			if !used[address] {
+				return address, true
+			}
+		}
+	}
+	return netip.Addr{}, false
+}
+
+// available returns the number of addresses of the pool left once used of
+// them are assigned, capped to the maximum value of an int32.
+func (p addressPool) available(used int) int32 {
+	size := new(big.Int)
+	for _, r := range p {
+		first, last := r.first.As16(), r.last.As16()
+		size.Add(size, new(big.Int).Sub(new(big.Int).SetBytes(last[:]), new(big.Int).SetBytes(first[:])))
+		size.Add(size, big.NewInt(1))
+	}
+	size.Sub(size, big.NewInt(int64(used)))
+	if !size.IsInt64() || size.Int64() > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(max(size.Int64(), 0))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NcxInfraLoadBalancerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1.NcxInfraLoadBalancer{}).
+		WatchesRawSource(r.ClusterCache.GetClusterSource("ncxinfraloadbalancer", r.clusterToLoadBalancers)).
+		WithEventFilter(predicates.ResourceHasFilterLabel(
+			mgr.GetScheme(), ctrl.Log.WithName("ncxinfraloadbalancer"), r.WatchFilterValue)).
+		Named("ncxinfraloadbalancer").
+		Build(r)
+	if err != nil {
+		return err
+	}
+	r.controller = c
+	return nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/netip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/controllers/clustercache"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+)
+
+var _ = Describe("NcxInfraLoadBalancer Controller", func() {
+	var (
+		ctx            context.Context
+		cluster        *clusterv1.Cluster
+		loadBalancer   *infrastructurev1.NcxInfraLoadBalancer
+		workloadClient client.Client
+	)
+
+	// service returns a Service of the workload cluster
+	service := func(name string, serviceType corev1.ServiceType, class *string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps"},
+			Spec:       corev1.ServiceSpec{Type: serviceType, LoadBalancerClass: class},
+		}
+	}
+
+	// ingressOf returns the address reported in the status of a Service
+	ingressOf := func(name string) string {
+		svc := &corev1.Service{}
+		Expect(workloadClient.Get(ctx, client.ObjectKey{Namespace: "apps", Name: name}, svc)).To(Succeed())
+		return ingressAddress(svc)
+	}
+
+	// reconcile reconciles the load balancer and returns it
+	reconcile := func(objects ...client.Object) *infrastructurev1.NcxInfraLoadBalancer {
+		scheme := newTestScheme()
+		mgmtClient := newFakeClientBuilder(scheme).
+			WithObjects(append([]client.Object{cluster, loadBalancer}, objects...)...).
+			WithStatusSubresource(&infrastructurev1.NcxInfraLoadBalancer{}).
+			Build()
+		reconciler := &NcxInfraLoadBalancerReconciler{
+			Client: mgmtClient,
+			Scheme: scheme,
+			ClusterCache: clustercache.NewFakeClusterCache(
+				workloadClient, client.ObjectKeyFromObject(cluster), loadBalancerServicesWatch),
+		}
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(loadBalancer)})
+		Expect(err).NotTo(HaveOccurred())
+
+		reconciled := &infrastructurev1.NcxInfraLoadBalancer{}
+		if err := mgmtClient.Get(ctx, client.ObjectKeyFromObject(loadBalancer), reconciled); err != nil {
+			Expect(client.IgnoreNotFound(err)).To(Succeed())
+			return nil
+		}
+		return reconciled
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}}
+		loadBalancer = &infrastructurev1.NcxInfraLoadBalancer{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: infrastructurev1.NcxInfraLoadBalancerSpec{
+				ClusterName:       "test-cluster",
+				Addresses:         []string{"10.0.2.240/30", "10.0.2.250-10.0.2.251"},
+				LoadBalancerClass: infrastructurev1.DefaultLoadBalancerClass,
+			},
+		}
+	})
+
+	It("should assign the lowest free addresses to the Services of its class", func() {
+		requested := service("requested", corev1.ServiceTypeLoadBalancer, testutil.Ptr(infrastructurev1.DefaultLoadBalancerClass))
+		requested.Spec.LoadBalancerIP = "10.0.2.250"
+		workloadClient = fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(
+				service("web", corev1.ServiceTypeLoadBalancer, testutil.Ptr(infrastructurev1.DefaultLoadBalancerClass)),
+				service("api", corev1.ServiceTypeLoadBalancer, testutil.Ptr(infrastructurev1.DefaultLoadBalancerClass)),
+				service("unclassed", corev1.ServiceTypeLoadBalancer, nil),
+				service("other", corev1.ServiceTypeLoadBalancer, testutil.Ptr("example.com/other")),
+				service("internal", corev1.ServiceTypeClusterIP, nil),
+				requested,
+			).
+			WithStatusSubresource(&corev1.Service{}).
+			Build()
+
+		reconciled := reconcile()
+		Expect(reconciled.Finalizers).To(ContainElement(NcxInfraLoadBalancerFinalizer))
+		Expect(ingressOf("api")).To(Equal("10.0.2.240"))
+		Expect(ingressOf("web")).To(Equal("10.0.2.241"))
+		Expect(ingressOf("requested")).To(Equal("10.0.2.250"))
+		Expect(ingressOf("unclassed")).To(BeEmpty())
+		Expect(ingressOf("other")).To(BeEmpty())
+		Expect(ingressOf("internal")).To(BeEmpty())
+
+		Expect(reconciled.Status.Services).To(Equal([]infrastructurev1.LoadBalancerServiceStatus{
+			{Namespace: "apps", Name: "api", Address: "10.0.2.240"},
+			{Namespace: "apps", Name: "requested", Address: "10.0.2.250"},
+			{Namespace: "apps", Name: "web", Address: "10.0.2.241"},
+		}))
+		Expect(reconciled.Status.Available).To(Equal(int32(3)))
+		Expect(conditions.IsTrue(reconciled, string(AddressesAssignedCondition))).To(BeTrue())
+	})
+
+	It("should keep the addresses of the Services and release those of deleted Services", func() {
+		loadBalancer.Spec.DefaultClass = true
+		loadBalancer.Finalizers = []string{NcxInfraLoadBalancerFinalizer}
+		loadBalancer.Status.Services = []infrastructurev1.LoadBalancerServiceStatus{
+			{Namespace: "apps", Name: "web", Address: "10.0.2.242"},
+			{Namespace: "apps", Name: "gone", Address: "10.0.2.240"},
+		}
+		workloadClient = fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(
+				service("web", corev1.ServiceTypeLoadBalancer, testutil.Ptr(infrastructurev1.DefaultLoadBalancerClass)),
+				service("new", corev1.ServiceTypeLoadBalancer, nil),
+			).
+			WithStatusSubresource(&corev1.Service{}).
+			Build()
+
+		reconciled := reconcile()
+		Expect(ingressOf("web")).To(Equal("10.0.2.242"))
+		Expect(ingressOf("new")).To(Equal("10.0.2.240"))
+		Expect(reconciled.Status.Services).To(ConsistOf(
+			infrastructurev1.LoadBalancerServiceStatus{Namespace: "apps", Name: "web", Address: "10.0.2.242"},
+			infrastructurev1.LoadBalancerServiceStatus{Namespace: "apps", Name: "new", Address: "10.0.2.240"},
+		))
+	})
+
+	It("should report the Services left without address", func() {
+		loadBalancer.Spec.Addresses = []string{"10.0.2.240/32"}
+		workloadClient = fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(
+				service("a", corev1.ServiceTypeLoadBalancer, testutil.Ptr(infrastructurev1.DefaultLoadBalancerClass)),
+				service("b", corev1.ServiceTypeLoadBalancer, testutil.Ptr(infrastructurev1.DefaultLoadBalancerClass)),
+			).
+			WithStatusSubresource(&corev1.Service{}).
+			Build()
+
+		reconciled := reconcile()
+		Expect(ingressOf("a")).To(Equal("10.0.2.240"))
+		Expect(ingressOf("b")).To(BeEmpty())
+		Expect(reconciled.Status.Available).To(BeZero())
+		condition := conditions.Get(reconciled, string(AddressesAssignedCondition))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("AddressesExhausted"))
+		Expect(condition.Message).To(ContainSubstring("apps/b"))
+	})
+
+	It("should withdraw the addresses of the Services when deleted", func() {
+		now := metav1.Now()
+		loadBalancer.DeletionTimestamp = &now
+		loadBalancer.Finalizers = []string{NcxInfraLoadBalancerFinalizer}
+		loadBalancer.Status.Services = []infrastructurev1.LoadBalancerServiceStatus{
+			{Namespace: "apps", Name: "web", Address: "10.0.2.240"},
+		}
+		web := service("web", corev1.ServiceTypeLoadBalancer, testutil.Ptr(infrastructurev1.DefaultLoadBalancerClass))
+		web.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.2.240"}}
+		workloadClient = fake.NewClientBuilder().WithScheme(newTestScheme()).
+			WithObjects(web).
+			WithStatusSubresource(&corev1.Service{}).
+			Build()
+
+		Expect(reconcile()).To(BeNil())
+		Expect(ingressOf("web")).To(BeEmpty())
+	})
+
+	It("should parse the CIDRs and ranges of the pool", func() {
+		pool, err := parseAddressPool([]string{"10.0.2.0/30", "10.0.3.10-10.0.3.11", "fd00::/126"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pool.contains(netip.MustParseAddr("10.0.2.3"))).To(BeTrue())
+		Expect(pool.contains(netip.MustParseAddr("10.0.2.4"))).To(BeFalse())
+		Expect(pool.contains(netip.MustParseAddr("10.0.3.11"))).To(BeTrue())
+		Expect(pool.contains(netip.MustParseAddr("fd00::3"))).To(BeTrue())
+		Expect(pool.available(1)).To(Equal(int32(9)))
+
+		large, err := parseAddressPool([]string{"fd00::/64"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(large.available(0)).To(Equal(int32(2147483647)))
+
+		for _, invalid := range []string{"10.0.2.0/33", "10.0.3.11-10.0.3.10", "10.0.3.1-fd00::1", "pool"} {
+			_, err := parseAddressPool([]string{invalid})
+			Expect(err).To(HaveOccurred(), invalid)
+		}
+	})
+})
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+const (
+	consoleReady  = "Ready"
+	consoleFailed = "Failed"
+)
+
+// reconcileConsole processes a serial console request made through the console
+// annotation: it checks that the site of the instance offers the serial
+// console and reports how to reach it in status.console. The annotation is
+// kept on transient errors so that the request is retried.
+func (r *NcxInfraMachineReconciler) reconcileConsole(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+	instance *nico.Instance,
+) {
+	if _, ok := machineScope.NcxInfraMachine.Annotations[infrastructurev1.ConsoleAnnotation]; !ok {
+		return
+	}
+
+	siteID := instance.GetSiteId()
+	if siteID == "" {
+		var err error
+		if siteID, err = clusterScope.SiteID(ctx); err != nil {
+			log.FromContext(ctx).Info("Failed to get the site of the serial console, will retry", "error", err.Error())
+			return
+		}
+	}
+
+	listStart := time.Now()
+	sites, httpResp, err := machineScope.NcxInfraClient.GetAllSite(ctx, machineScope.OrgName)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetAllSite")
+	recordAPIMetrics("GetAllSite", listStart, apiErr)
+	if apiErr != nil && apiErr.IsTransient() {
+		log.FromContext(ctx).Info("Transient error getting the site of the serial console, will retry",
+			"error", apiErr.Message)
+		return
+	}
+	if apiErr != nil {
+		r.finishConsole(machineScope, nil, apiErr)
+		return
+	}
+
+	var site *nico.Site
+	for i := range sites {
+		if sites[i].GetId() == siteID {
+			site = &sites[i]
+			break
+		}
+	}
+	switch {
+	case site == nil:
+		r.finishConsole(machineScope, nil, fmt.Errorf("site %s not found", siteID))
+	case !site.GetIsSerialConsoleEnabled():
+		r.finishConsole(machineScope, nil, fmt.Errorf("serial console is disabled on site %s", site.GetName()))
+	default:
+		console, err := consoleStatus(instance, site, clusterScope.NcxInfraCluster.Status.BreakGlassSSH)
+		r.finishConsole(machineScope, console, err)
+	}
+}
+
+// consoleStatus describes the serial console of an instance on its site. The
+// console accepts the break-glass SSH key of the cluster when the tenant
+// enabled SSH keys for the serial console of the site.
+func consoleStatus(
+	instance *nico.Instance, site *nico.Site, breakGlass *infrastructurev1.BreakGlassSSHStatus,
+) (*infrastructurev1.ConsoleStatus, error) {
+	url := instance.GetSerialConsoleUrl()
+	if url == "" {
+		hostname := site.GetSerialConsoleHostname()
+		if hostname == "" {
+			return nil, fmt.Errorf("site %s does not report a serial console hostname", site.GetName())
+		}
+		url = fmt.Sprintf("ssh://%s@%s", instance.GetId(), hostname)
+	}
+
+	console := &infrastructurev1.ConsoleStatus{URL: url}
+	var details []string
+	if breakGlass != nil && breakGlass.SecretName != "" && site.GetIsSerialConsoleSSHKeysEnabled() {
+		console.SSHKeySecretName = breakGlass.SecretName
+	} else {
+		details = append(details, "SSH key access is not available, authenticate with the tenant credentials")
+	}
+	if idle, ok := site.GetSerialConsoleIdleTimeoutOk(); ok && idle != nil {
+		details = append(details, fmt.Sprintf("idle timeout %s", time.Duration(*idle)*time.Second))
+	}
+	if length, ok := site.GetSerialConsoleMaxSessionLengthOk(); ok && length != nil {
+		details = append(details, fmt.Sprintf("maximum session length %s", time.Duration(*length)*time.Second))
+	}
+	console.Message = strings.Join(details, "; ")
+	return console, nil
+}
+
+// finishConsole records the outcome of a serial console request and removes the annotation.
+func (r *NcxInfraMachineReconciler) finishConsole(
+	machineScope *scope.MachineScope, console *infrastructurev1.ConsoleStatus, err error,
+) {
+	delete(machineScope.NcxInfraMachine.Annotations, infrastructurev1.ConsoleAnnotation)
+
+	if err != nil {
+		console = &infrastructurev1.ConsoleStatus{Result: consoleFailed, Message: err.Error()}
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "SerialConsoleFailed",
+			"Serial console request failed: %v", err)
+	} else {
+		console.Result = consoleReady
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeNormal, "SerialConsoleReady",
+			"Serial console of instance %s available at %s", machineScope.InstanceID(), console.URL)
+	}
+	console.Time = metav1.Now()
+	machineScope.NcxInfraMachine.Status.Console = console
+}
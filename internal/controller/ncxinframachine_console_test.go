@@ -0,0 +1,133 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Serial console", func() {
+	var (
+		ctx          context.Context
+		site         nico.Site
+		sitesErr     error
+		instance     *nico.Instance
+		machineScope *scope.MachineScope
+		clusterScope *scope.ClusterScope
+		recorder     *record.FakeRecorder
+		reconciler   *NcxInfraMachineReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		sitesErr = nil
+		site = nico.Site{
+			Id:                            testutil.Ptr("site-uuid"),
+			Name:                          testutil.Ptr("site-a"),
+			IsSerialConsoleEnabled:        testutil.Ptr(true),
+			SerialConsoleHostname:         testutil.Ptr("console.site-a.example.com"),
+			SerialConsoleIdleTimeout:      *nico.NewNullableInt32(testutil.Ptr(int32(300))),
+			SerialConsoleMaxSessionLength: *nico.NewNullableInt32(testutil.Ptr(int32(3600))),
+			IsSerialConsoleSSHKeysEnabled: testutil.Ptr(true),
+		}
+		instance = &nico.Instance{Id: testutil.Ptr("instance-uuid"), SiteId: testutil.Ptr("site-uuid")}
+		machineScope = &scope.MachineScope{
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-machine",
+					Namespace:   "default",
+					Annotations: map[string]string{infrastructurev1.ConsoleAnnotation: ""},
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{InstanceID: "instance-uuid"},
+			},
+			NcxInfraClient: &testutil.MockNcxInfraClient{
+				GetAllSiteFunc: func(ctx context.Context, org string) ([]nico.Site, *http.Response, error) {
+					if sitesErr != nil {
+						return nil, testutil.MockHTTPResponse(http.StatusServiceUnavailable), sitesErr
+					}
+					return []nico.Site{site}, testutil.MockHTTPResponse(http.StatusOK), nil
+				},
+			},
+			OrgName: "test-org",
+		}
+		clusterScope = &scope.ClusterScope{NcxInfraCluster: &infrastructurev1.NcxInfraCluster{
+			Status: infrastructurev1.NcxInfraClusterStatus{
+				BreakGlassSSH: &infrastructurev1.BreakGlassSSHStatus{SecretName: "test-cluster-break-glass"},
+			},
+		}}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &NcxInfraMachineReconciler{Recorder: recorder}
+	})
+
+	It("should report the console URL and the break-glass key", func() {
+		reconciler.reconcileConsole(ctx, machineScope, clusterScope, instance)
+
+		console := machineScope.NcxInfraMachine.Status.Console
+		Expect(console).NotTo(BeNil())
+		Expect(console.Result).To(Equal(consoleReady))
+		Expect(console.URL).To(Equal("ssh://instance-uuid@console.site-a.example.com"))
+		Expect(console.SSHKeySecretName).To(Equal("test-cluster-break-glass"))
+		Expect(console.Message).To(Equal("idle timeout 5m0s; maximum session length 1h0m0s"))
+		Expect(machineScope.NcxInfraMachine.Annotations).NotTo(HaveKey(infrastructurev1.ConsoleAnnotation))
+		Expect(recorder.Events).To(Receive(ContainSubstring("SerialConsoleReady")))
+	})
+
+	It("should prefer the console URL of the instance", func() {
+		instance.SerialConsoleUrl = *nico.NewNullableString(testutil.Ptr("ssh://instance-uuid@console.example.com"))
+		site.IsSerialConsoleSSHKeysEnabled = testutil.Ptr(false)
+
+		reconciler.reconcileConsole(ctx, machineScope, clusterScope, instance)
+
+		console := machineScope.NcxInfraMachine.Status.Console
+		Expect(console.URL).To(Equal("ssh://instance-uuid@console.example.com"))
+		Expect(console.SSHKeySecretName).To(BeEmpty())
+		Expect(console.Message).To(HavePrefix("SSH key access is not available"))
+	})
+
+	It("should fail when the site disables the serial console", func() {
+		site.IsSerialConsoleEnabled = testutil.Ptr(false)
+
+		reconciler.reconcileConsole(ctx, machineScope, clusterScope, instance)
+
+		console := machineScope.NcxInfraMachine.Status.Console
+		Expect(console.Result).To(Equal(consoleFailed))
+		Expect(console.Message).To(Equal("serial console is disabled on site site-a"))
+		Expect(machineScope.NcxInfraMachine.Annotations).NotTo(HaveKey(infrastructurev1.ConsoleAnnotation))
+		Expect(recorder.Events).To(Receive(ContainSubstring("SerialConsoleFailed")))
+	})
+
+	It("should keep the request on transient errors", func() {
+		sitesErr = fmt.Errorf("service unavailable")
+
+		reconciler.reconcileConsole(ctx, machineScope, clusterScope, instance)
+
+		Expect(machineScope.NcxInfraMachine.Status.Console).To(BeNil())
+		Expect(machineScope.NcxInfraMachine.Annotations).To(HaveKey(infrastructurev1.ConsoleAnnotation))
+	})
+})
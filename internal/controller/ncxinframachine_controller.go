@@ -18,16 +18,25 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/api/core/v1beta2/index"
+	"sigs.k8s.io/cluster-api/controllers/clustercache"
 	capierrors "sigs.k8s.io/cluster-api/errors" //nolint:staticcheck // required for CAPI contract FailureReason types
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -35,14 +44,21 @@ import (
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
 	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	ncxinfraerrors "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/errors"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/feature"
 	ncxinframetrics "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/metrics"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/cloudinit"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/placement"
 	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
 )
 
@@ -59,18 +75,98 @@ const (
 	BootstrapDataAppliedCondition clusterv1.ConditionType = "BootstrapDataApplied"
 	NicoHealthyCondition          clusterv1.ConditionType = "NicoHealthy"
 	NicoFaultRemediationCondition clusterv1.ConditionType = "NicoFaultRemediation"
+	SecureEraseCondition          clusterv1.ConditionType = "SecureEraseCompleted"
+	PowerActionCondition          clusterv1.ConditionType = "PowerActionCompleted"
+	NodeHealthyCondition          clusterv1.ConditionType = "NodeHealthy"
+
+	// SubnetAvailableCondition reports whether the subnets and VPC prefixes the
+	// machine attaches to exist in the cluster status.
+	SubnetAvailableCondition clusterv1.ConditionType = "SubnetAvailable"
+
+	// AttestationVerifiedCondition reports whether the instance meets the
+	// requirements of spec.security. The machine is not Ready until it is true.
+	AttestationVerifiedCondition clusterv1.ConditionType = "AttestationVerified"
+
+	// BootstrapFormatCondition reports the format of the bootstrap data and
+	// whether the operating system of the machine can consume it.
+	BootstrapFormatCondition clusterv1.ConditionType = "BootstrapFormatCompatible"
+
+	// InstanceAllocatedCondition reports whether NVIDIA Carbide allocated a
+	// machine to the instance. It stays false while the site lacks capacity.
+	InstanceAllocatedCondition clusterv1.ConditionType = "InstanceAllocated"
+
+	// InstanceImagedCondition reports whether the operating system image was
+	// written to the machine of the instance.
+	InstanceImagedCondition clusterv1.ConditionType = "InstanceImaged"
+
+	// InstanceBootedCondition reports whether the instance booted and got its
+	// network configured, once its image was written.
+	InstanceBootedCondition clusterv1.ConditionType = "InstanceBooted"
+
+	// BootstrapDataFreshCondition reports whether the bootstrap data was
+	// younger than the bootstrap token TTL when the instance was created.
+	BootstrapDataFreshCondition clusterv1.ConditionType = "BootstrapDataFresh"
 )
 
+// ignitionOSTypes are the operating system types booting from Ignition
+// instead of cloud-init.
+var ignitionOSTypes = map[string]bool{
+	"flatcar":       true,
+	"fcos":          true,
+	"fedora-coreos": true,
+	"rhcos":         true,
+}
+
+// errInvalidClusterRef reports an interface referencing a cluster that cannot
+// share its networks with the machine. It is retried until the reference is fixed.
+var errInvalidClusterRef = errors.New("invalid cluster reference")
+
 // NcxInfraMachineReconciler reconciles a NcxInfraMachine object
 type NcxInfraMachineReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 
-	// NcxInfraClient can be set for testing to inject a mock client
+	// ClusterCache provides access to the workload clusters.
+	// Node correlation is skipped when it is not set.
+	ClusterCache clustercache.ClusterCache
+
+	// NcxInfraClient can be set for testing or simulation mode to inject a client
 	NcxInfraClient scope.NcxInfraClientInterface
-	// OrgName can be set for testing
+	// OrgName can be set for testing or simulation mode
 	OrgName string
+
+	// DefaultCredentials is the credentials secret of the objects that do not
+	// reference one, when their namespace has no default NcxInfraIdentity
+	DefaultCredentials corev1.SecretReference
+
+	// PlacementStrategy selects the machine an instance is created on.
+	// Defaults to placement.Default.
+	PlacementStrategy placement.Strategy
+
+	// CapacityRetryInterval paces the retries of the creation of an instance
+	// while the site has no available machine of its instance type, or the
+	// instance quota of the tenant is exhausted. Defaults to one minute.
+	CapacityRetryInterval time.Duration
+
+	// BootstrapTokenTTL is the lifetime of the bootstrap tokens embedded in
+	// the bootstrap data. Older bootstrap data is reported as stale when the
+	// instance is created. Zero disables the check.
+	BootstrapTokenTTL time.Duration
+
+	// WatchFilterValue is the value of the cluster.x-k8s.io/watch-filter label
+	// of the objects to reconcile, all of them when empty
+	WatchFilterValue string
+
+	// warmPoolMu serializes the changes to the warm pools, so that an instance
+	// is not handed to two machines. Only the leader runs the controllers, so
+	// the other replicas do not need to be serialized with it.
+	warmPoolMu sync.Mutex
+
+	// provisioning counts the instances each cluster provisions, for the
+	// clusters limiting it with spec.provisioning and the MachineDeployments
+	// rolling out.
+	provisioning provisioningTracker
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinframachines,verbs=get;list;watch;create;update;patch;delete
@@ -78,11 +174,13 @@ type NcxInfraMachineReconciler struct {
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinframachines/finalizers,verbs=update
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile handles NcxInfraMachine reconciliation
-func (r *NcxInfraMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *NcxInfraMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, reterr error) {
 	logger := log.FromContext(ctx)
 
 	// Fetch the NcxInfraMachine instance
@@ -113,6 +211,9 @@ func (r *NcxInfraMachineReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		logger.Info("Waiting for Cluster to be set on Machine")
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
+	logger = logger.WithValues("cluster", cluster.Name, "machine", machine.Name)
+	ctx = log.IntoContext(ctx, logger)
+	ctx = scope.WithAuditObject(ctx, "NcxInfraMachine", req.NamespacedName)
 
 	// Fetch the NcxInfraCluster
 	nvidiaCarbideCluster := &infrastructurev1.NcxInfraCluster{}
@@ -130,6 +231,13 @@ func (r *NcxInfraMachineReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, nil
 	}
 
+	// Report the planned changes instead of making them, unless a deletion
+	// has nothing to delete
+	if isDryRun(nvidiaCarbideCluster, nvidiaCarbideMachine) &&
+		(nvidiaCarbideMachine.DeletionTimestamp.IsZero() || nvidiaCarbideMachine.Status.InstanceID != "") {
+		return r.reconcileDryRun(ctx, nvidiaCarbideMachine)
+	}
+
 	// Return early if NcxInfraCluster is not ready
 	if !nvidiaCarbideCluster.Status.Ready {
 		logger.Info("Waiting for NcxInfraCluster to be ready")
@@ -148,25 +256,62 @@ func (r *NcxInfraMachineReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
-	// Always attempt to patch the object and status after each reconciliation
+	// Always attempt to patch the object and status after each reconciliation,
+	// with the Ready condition summarizing the others
 	defer func() {
+		if nvidiaCarbideMachine.DeletionTimestamp.IsZero() {
+			if err := setMachineReadyCondition(nvidiaCarbideMachine); err != nil {
+				logger.Error(err, "failed to summarize the Ready condition")
+			}
+		}
+		nvidiaCarbideMachine.Status.Phase = machinePhase(nvidiaCarbideMachine)
+		nvidiaCarbideMachine.Status.InstancePhase = instancePhase(nvidiaCarbideMachine)
+		sortConditions(nvidiaCarbideMachine)
 		if err := patchHelper.Patch(ctx, nvidiaCarbideMachine); err != nil {
-			logger.Error(err, "failed to patch NcxInfraMachine")
+			res, reterr = patchResult(res, reterr, fmt.Errorf("failed to patch NcxInfraMachine: %w", err))
 		}
 	}()
+	conditions.Delete(nvidiaCarbideMachine, string(DryRunCondition))
+
+	// Hold the deletion of a protected machine, or of a machine of a protected
+	// cluster, until the annotation is removed
+	if !nvidiaCarbideMachine.DeletionTimestamp.IsZero() && isDeletionProtected(nvidiaCarbideCluster, nvidiaCarbideMachine) {
+		if setDeletionBlocked(nvidiaCarbideMachine) {
+			logger.Info("Deletion prevented by annotation", "annotation", infrastructurev1.PreventDeletionAnnotation)
+			r.recordEvent(nvidiaCarbideMachine, corev1.EventTypeWarning, "DeletionBlocked",
+				"Deletion held until the %s annotation is removed", infrastructurev1.PreventDeletionAnnotation)
+		}
+		return ctrl.Result{RequeueAfter: deletionProtectionRefreshInterval}, nil
+	}
+	conditions.Delete(nvidiaCarbideMachine, string(DeletionBlockedCondition))
 
 	// Create cluster scope for credentials
 	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
-		Client:          r.Client,
-		Cluster:         cluster,
-		NcxInfraCluster: nvidiaCarbideCluster,
-		NcxInfraClient:  r.NcxInfraClient, // Will be nil in production, set for tests
-		OrgName:         r.OrgName,        // Will be empty in production (fetched from secret), set for tests
+		Client:             r.Client,
+		Cluster:            cluster,
+		NcxInfraCluster:    nvidiaCarbideCluster,
+		NcxInfraClient:     r.NcxInfraClient, // Will be nil in production, set for tests
+		OrgName:            r.OrgName,        // Will be empty in production (fetched from secret), set for tests
+		DefaultCredentials: r.DefaultCredentials,
 	})
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to create cluster scope: %w", err)
 	}
 
+	// The instance IDs are meaningless in another org, wait for the credentials
+	// to be restored rather than reporting the instance as gone
+	if change := clusterScope.TargetChange(); change != "" {
+		logger.Info("Credentials target changed, not reconciling", "change", change)
+		conditions.Set(nvidiaCarbideMachine, metav1.Condition{
+			Type:    string(CredentialsTargetCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "CredentialsTargetChanged",
+			Message: change,
+		})
+		return ctrl.Result{RequeueAfter: permissionsRetryInterval}, nil
+	}
+	conditions.Delete(nvidiaCarbideMachine, string(CredentialsTargetCondition))
+
 	// Create machine scope
 	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
 		Client:          r.Client,
@@ -183,11 +328,108 @@ func (r *NcxInfraMachineReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	// Handle deletion
 	if !nvidiaCarbideMachine.DeletionTimestamp.IsZero() {
-		return r.reconcileDelete(ctx, machineScope)
+		result, err := r.reconcileDelete(ctx, machineScope)
+		return handlePermissionError(ctx, nvidiaCarbideMachine, machineScope.OrgName, result, err)
 	}
 
 	// Handle normal reconciliation
-	return r.reconcileNormal(ctx, machineScope, clusterScope)
+	result, err := r.reconcileNormal(ctx, machineScope, clusterScope)
+	return handlePermissionError(ctx, nvidiaCarbideMachine, machineScope.OrgName, result, err)
+}
+
+// reconcileDryRun reports the changes the reconciliation of the machine would
+// make to its instance, without making them.
+func (r *NcxInfraMachineReconciler) reconcileDryRun(
+	ctx context.Context, nvidiaCarbideMachine *infrastructurev1.NcxInfraMachine,
+) (ctrl.Result, error) {
+	patchHelper, err := patch.NewHelper(nvidiaCarbideMachine, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	plan := planMachineChanges(nvidiaCarbideMachine)
+	message, changed := setDryRunCondition(nvidiaCarbideMachine, plan)
+	if changed {
+		log.FromContext(ctx).Info("Dry-run mode, not applying the NVIDIA Carbide changes", "plan", plan)
+		r.recordEvent(nvidiaCarbideMachine, corev1.EventTypeNormal, "DryRunPlan", "%s", message)
+	}
+	if err := patchHelper.Patch(ctx, nvidiaCarbideMachine); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to patch NcxInfraMachine: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: dryRunRefreshInterval}, nil
+}
+
+// planMachineChanges lists the changes the reconciliation of the machine would
+// make to its instance. Updates of an existing instance are not planned.
+func planMachineChanges(nvidiaCarbideMachine *infrastructurev1.NcxInfraMachine) []string {
+	instanceID := nvidiaCarbideMachine.Status.InstanceID
+	switch {
+	case !nvidiaCarbideMachine.DeletionTimestamp.IsZero():
+		if instanceID == "" {
+			return nil
+		}
+		return []string{fmt.Sprintf("delete instance %s", instanceID)}
+	case instanceID != "":
+		return nil
+	}
+
+	action := fmt.Sprintf("create instance %s", nvidiaCarbideMachine.Name)
+	instanceType := nvidiaCarbideMachine.Spec.InstanceType
+	if instanceType.MachineID != "" {
+		action += fmt.Sprintf(" on machine %s", instanceType.MachineID)
+	} else if instanceType.ID != "" {
+		action += fmt.Sprintf(" of instance type %s", instanceType.ID)
+	}
+	return []string{action}
+}
+
+// machinePhase summarizes the state of the machine for status.phase.
+func machinePhase(nvidiaCarbideMachine *infrastructurev1.NcxInfraMachine) string {
+	switch {
+	case !nvidiaCarbideMachine.DeletionTimestamp.IsZero():
+		return infrastructurev1.MachinePhaseDeleting
+	case nvidiaCarbideMachine.Status.FailureReason != nil:
+		return infrastructurev1.MachinePhaseFailed
+	case nvidiaCarbideMachine.Status.Ready:
+		return infrastructurev1.MachinePhaseRunning
+	case nvidiaCarbideMachine.Status.InstanceID != "":
+		return infrastructurev1.MachinePhaseProvisioning
+	default:
+		return infrastructurev1.MachinePhasePending
+	}
+}
+
+// instancePhases maps the NVIDIA Carbide states of an instance to its phase.
+var instancePhases = map[nico.InstanceStatus]infrastructurev1.InstancePhase{
+	nico.INSTANCESTATUS_PENDING:      infrastructurev1.InstancePhaseAllocating,
+	nico.INSTANCESTATUS_PROVISIONING: infrastructurev1.InstancePhaseImaging,
+	nico.INSTANCESTATUS_CONFIGURING:  infrastructurev1.InstancePhaseBooting,
+	nico.INSTANCESTATUS_REBOOTING:    infrastructurev1.InstancePhaseBooting,
+	nico.INSTANCESTATUS_READY:        infrastructurev1.InstancePhaseReady,
+	nico.INSTANCESTATUS_UPDATING:     infrastructurev1.InstancePhaseReady,
+	nico.INSTANCESTATUS_ERROR:        infrastructurev1.InstancePhaseFailed,
+	nico.INSTANCESTATUS_TERMINATING:  infrastructurev1.InstancePhaseDeleting,
+}
+
+// instancePhase derives status.instancePhase from the state of the instance.
+// A state NVIDIA Carbide added since keeps the previous phase.
+func instancePhase(nvidiaCarbideMachine *infrastructurev1.NcxInfraMachine) infrastructurev1.InstancePhase {
+	status := nvidiaCarbideMachine.Status
+	switch {
+	case !nvidiaCarbideMachine.DeletionTimestamp.IsZero():
+		return infrastructurev1.InstancePhaseDeleting
+	case status.FailureReason != nil:
+		return infrastructurev1.InstancePhaseFailed
+	case status.InstanceID == "":
+		return infrastructurev1.InstancePhasePending
+	}
+	if phase, ok := instancePhases[nico.InstanceStatus(status.InstanceState)]; ok {
+		return phase
+	}
+	if status.InstancePhase != "" {
+		return status.InstancePhase
+	}
+	return infrastructurev1.InstancePhaseAllocating
 }
 
 func (r *NcxInfraMachineReconciler) reconcileNormal(
@@ -209,6 +451,15 @@ func (r *NcxInfraMachineReconciler) reconcileNormal(
 		return r.reconcileInstance(ctx, machineScope, clusterScope)
 	}
 
+	// Restore the instance of a machine recreated after the management cluster was rebuilt
+	if feature.Gates.Enabled(feature.ResourceExport) {
+		if restored, err := r.restoreInstanceFromExport(ctx, machineScope, clusterScope); err != nil {
+			logger.Error(err, "failed to restore the instance from the resource export")
+		} else if restored {
+			return r.reconcileInstance(ctx, machineScope, clusterScope)
+		}
+	}
+
 	// Check for existing instance with the same name (duplicate prevention)
 	if existingInstance, err := r.findExistingInstance(ctx, machineScope, clusterScope); err != nil {
 		logger.Error(err, "failed to check for existing instance")
@@ -249,6 +500,31 @@ func (r *NcxInfraMachineReconciler) reconcileNormal(
 		}
 	}
 
+	// Resolve the clusters sharing their networks with the machine
+	referenced, err := r.referencedClusters(ctx, machineScope)
+	if errors.Is(err, errInvalidClusterRef) {
+		logger.Info("Invalid cluster reference in the machine network", "reason", err.Error())
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "InvalidClusterRef", "%s", err.Error())
+		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+			Type:    string(InstanceProvisionedCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidClusterRef",
+			Message: err.Error(),
+		})
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Wait for the clusters to create the networks the machine attaches to
+	if missing := missingNetworks(machineScope.NcxInfraMachine.Spec.Network,
+		clusterScope.NcxInfraCluster.Status.NetworkStatus, referenced); len(missing) > 0 {
+		logger.Info("Waiting for the cluster networks of the machine", "missing", missing)
+		r.setSubnetAvailable(machineScope, missing)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	r.setSubnetAvailable(machineScope, nil)
+
 	// Create new instance.
 	// NOTE: BatchCreateInstance is available in the SDK for creating up to 18
 	// instances per call, but CAPI's reconcile-per-machine model makes batching
@@ -256,14 +532,70 @@ func (r *NcxInfraMachineReconciler) reconcileNormal(
 	// no coordination mechanism. A higher-level batching controller would be
 	// needed to detect concurrent pending machines and coordinate batch creation.
 	// For now, instances are created individually per reconcile.
-	if err := r.createInstance(ctx, machineScope, clusterScope); err != nil {
+	if err := r.createInstance(ctx, machineScope, clusterScope, referenced); err != nil {
+		if errors.Is(err, errNoCapacity) {
+			logger.Info("Waiting for an available machine", "reason", err.Error())
+			if condition := conditions.Get(machineScope.NcxInfraMachine, string(InstanceProvisionedCondition)); condition == nil ||
+				condition.Reason != string(ncxinfraerrors.WaitingForCapacity) {
+				r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning,
+					string(ncxinfraerrors.WaitingForCapacity), "%s", err.Error())
+			}
+			conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+				Type:    string(InstanceProvisionedCondition),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(ncxinfraerrors.WaitingForCapacity),
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: r.capacityRetryInterval()}, nil
+		}
+		if errors.Is(err, errQuotaExceeded) {
+			logger.Info("Waiting for room in the instance quota of the tenant", "reason", err.Error())
+			if condition := conditions.Get(machineScope.NcxInfraMachine, string(InstanceProvisionedCondition)); condition == nil ||
+				condition.Reason != string(ncxinfraerrors.QuotaExceeded) {
+				r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning,
+					string(ncxinfraerrors.QuotaExceeded), "%s", err.Error())
+			}
+			conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+				Type:    string(InstanceProvisionedCondition),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(ncxinfraerrors.QuotaExceeded),
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: r.capacityRetryInterval()}, nil
+		}
+		if errors.Is(err, errProvisioningLimit) {
+			logger.Info("Waiting for a provisioning slot of the cluster", "reason", err.Error())
+			if condition := conditions.Get(machineScope.NcxInfraMachine, string(InstanceProvisionedCondition)); condition == nil ||
+				condition.Reason != string(ncxinfraerrors.WaitingForProvisioningSlot) {
+				r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeNormal,
+					string(ncxinfraerrors.WaitingForProvisioningSlot), "%s", err.Error())
+			}
+			conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+				Type:    string(InstanceProvisionedCondition),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(ncxinfraerrors.WaitingForProvisioningSlot),
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: provisioningSlotRetryInterval}, nil
+		}
+		if errors.Is(err, placement.ErrPending) {
+			logger.Info("Waiting for a machine satisfying the placement constraints", "reason", err.Error())
+			conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+				Type:    string(InstanceProvisionedCondition),
+				Status:  metav1.ConditionFalse,
+				Reason:  "PlacementPending",
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
 		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
 			Type:    string(InstanceProvisionedCondition),
 			Status:  metav1.ConditionFalse,
-			Reason:  "InstanceCreationFailed",
+			Reason:  string(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.InstanceCreateFailed)),
 			Message: err.Error(),
 		})
-		if apiErr, ok := err.(*scope.APIError); ok && apiErr.IsTransient() {
+		var apiErr *scope.APIError
+		if errors.As(err, &apiErr) && apiErr.IsTransient() {
 			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 		}
 		return ctrl.Result{}, err
@@ -283,39 +615,49 @@ func (r *NcxInfraMachineReconciler) createInstance(
 	ctx context.Context,
 	machineScope *scope.MachineScope,
 	clusterScope *scope.ClusterScope,
+	referenced map[string]*infrastructurev1.NcxInfraCluster,
 ) error {
 	logger := log.FromContext(ctx)
 
 	// Get bootstrap data
-	bootstrapData, err := machineScope.GetBootstrapData(ctx)
+	bootstrapData, format, err := machineScope.GetBootstrapDataWithFormat(ctx)
 	if err != nil {
 		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
 			Type:    string(BootstrapDataAppliedCondition),
 			Status:  metav1.ConditionFalse,
-			Reason:  "BootstrapDataFailed",
+			Reason:  string(ncxinfraerrors.BootstrapDataFailed),
 			Message: err.Error(),
 		})
-		return fmt.Errorf("failed to get bootstrap data: %w", err)
+		return ncxinfraerrors.Transient(ncxinfraerrors.BootstrapDataFailed, fmt.Errorf("failed to get bootstrap data: %w", err))
+	}
+	if err := checkBootstrapFormat(machineScope.NcxInfraMachine, format); err != nil {
+		return err
 	}
 	conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
 		Type:   string(BootstrapDataAppliedCondition),
 		Status: metav1.ConditionTrue,
 		Reason: "BootstrapDataReady",
 	})
+	r.checkBootstrapDataAge(ctx, machineScope, bootstrapData, format)
 
 	// Validate capabilities before creating
 	if err := r.validateCapabilities(ctx, machineScope, clusterScope); err != nil {
 		return err
 	}
 
-	// Get Site ID (as site name for ProviderID)
-	siteName, err := clusterScope.SiteID(ctx)
+	// Get Site ID (as site name for ProviderID), the site of the subnet of the machine
+	siteName, err := r.placeInFailureDomain(ctx, machineScope, clusterScope)
 	if err != nil {
 		return fmt.Errorf("failed to get site ID: %w", err)
 	}
 
+	// Name the node before the bootstrap data is rendered
+	if err := r.assignHostname(ctx, machineScope, siteName); err != nil {
+		return err
+	}
+
 	// Build network interfaces
-	interfaces, err := r.buildInterfaces(machineScope, clusterScope)
+	interfaces, err := r.buildInterfaces(machineScope, clusterScope, referenced)
 	if err != nil {
 		return err
 	}
@@ -331,23 +673,63 @@ func (r *NcxInfraMachineReconciler) createInstance(
 
 	// Apply optional spec fields to the request
 	r.applyOptionalInstanceFields(machineScope, &instanceReq)
+	if labels := instanceLabels(machineScope, clusterScope); len(labels) > 0 {
+		instanceReq.Labels = labels
+	}
+
+	// Target a specific machine when placement constraints apply
+	if err := r.applyPlacement(ctx, machineScope, clusterScope, siteName, &instanceReq); err != nil {
+		return err
+	}
+
+	// Refuse a target machine whose firmware cannot meet the security requirements
+	if instanceReq.MachineId != nil {
+		if err := r.checkMachineFirmware(ctx, machineScope, *instanceReq.MachineId); err != nil {
+			return err
+		}
+	}
+
+	if err := r.applyUserData(ctx, machineScope, clusterScope, siteName, &instanceReq); err != nil {
+		return err
+	}
 
 	logger.Info("Creating NVIDIA Carbide instance",
 		"name", machineScope.Name(),
 		"vpcID", machineScope.VPCID(),
 		"role", machineScope.Role())
 
-	// Create instance via NVIDIA Carbide API
-	createStart := time.Now()
-	instance, httpResp, err := machineScope.NcxInfraClient.CreateInstance(ctx, machineScope.OrgName, instanceReq)
-	createAPIErr := scope.ClassifyAPIError(httpResp, err, "CreateInstance")
-	recordAPIMetrics("CreateInstance", createStart, createAPIErr)
-	if apiErr := createAPIErr; apiErr != nil {
-		if apiErr.IsTerminal() {
-			errReason := capierrors.MachineStatusError("CreateInstanceFailed")
-			setMachineFailure(machineScope.NcxInfraMachine, errReason, apiErr.Message)
+	// Reuse an instance of the warm pool of the cluster, or create one via
+	// NVIDIA Carbide API. Both provision the machine.
+	release, err := r.acquireProvisioningSlot(ctx, machineScope, clusterScope)
+	if err != nil {
+		return err
+	}
+	var instance *nico.Instance
+	defer func() { release(instance != nil) }()
+	instance, err = r.takeFromWarmPool(ctx, machineScope, instanceReq)
+	if err != nil {
+		logger.Error(err, "failed to take an instance from the warm pool, creating one")
+	}
+	reused := instance != nil
+	if !reused {
+		if err := r.checkQuota(ctx, machineScope, clusterScope); err != nil {
+			return err
 		}
-		return apiErr
+		if err := r.checkCapacity(ctx, clusterScope, siteName, instanceReq); err != nil {
+			return err
+		}
+		createStart := time.Now()
+		created, httpResp, err := machineScope.NcxInfraClient.CreateInstance(ctx, machineScope.OrgName, instanceReq)
+		createAPIErr := scope.ClassifyAPIError(httpResp, err, "CreateInstance")
+		recordAPIMetrics("CreateInstance", createStart, createAPIErr)
+		if apiErr := createAPIErr; apiErr != nil {
+			if apiErr.IsTerminal() {
+				errReason := capierrors.MachineStatusError(ncxinfraerrors.InstanceCreateFailed)
+				setMachineFailure(machineScope.NcxInfraMachine, errReason, apiErr.Message)
+			}
+			return ncxinfraerrors.Classify(ncxinfraerrors.InstanceCreateFailed, apiErr)
+		}
+		instance = created
 	}
 
 	if instance == nil || instance.Id == nil {
@@ -375,12 +757,23 @@ func (r *NcxInfraMachineReconciler) createInstance(
 
 	// Update machine scope with instance details
 	machineScope.SetInstanceID(instanceID)
+	origin := scope.NewResourceOrigin("Instance", machineScope.Name(), instance.Created)
+	machineScope.NcxInfraMachine.Status.InstanceOrigin = &origin
 	machineScope.SetMachineID(machineID)
 	machineScope.SetInstanceState(status)
-	if err := machineScope.SetProviderID(clusterScope.TenantID(), siteName, instanceID); err != nil {
+	if err := machineScope.SetProviderID(machineScope.TenantID(), siteName, instanceID); err != nil {
 		return fmt.Errorf("failed to set provider ID: %w", err)
 	}
+	machineScope.NcxInfraMachine.Status.Provisioned = &infrastructurev1.ProvisionedStatus{
+		BootstrapDataHash: bootstrapDataHash(bootstrapData),
+		OperatingSystemID: operatingSystemID(machineScope.NcxInfraMachine),
+	}
 
+	if reused {
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeNormal, "InstanceReused",
+			"Reused instance %s from the warm pool", instanceID)
+		return nil
+	}
 	logger.Info("Successfully created NVIDIA Carbide instance",
 		"instanceID", instanceID,
 		"machineID", machineID,
@@ -391,12 +784,184 @@ func (r *NcxInfraMachineReconciler) createInstance(
 	return nil
 }
 
+// placeInFailureDomain attaches the instance of a machine to the subnet of its
+// spec, or to the subnet of the same role in the failure domain the Machine is
+// placed in, and returns the site of that subnet.
+func (r *NcxInfraMachineReconciler) placeInFailureDomain(
+	ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope,
+) (string, error) {
+	subnets := clusterScope.NcxInfraCluster.Spec.Subnets
+	index := slices.IndexFunc(subnets, func(subnet infrastructurev1.SubnetSpec) bool {
+		return subnet.Name == machineScope.NcxInfraMachine.Spec.Network.SubnetName
+	})
+	if index < 0 {
+		// Attached to a VPC prefix, which is in the site of the cluster
+		return clusterScope.SiteID(ctx)
+	}
+
+	subnet := subnets[index]
+	siteID, err := clusterScope.SubnetSiteID(ctx, subnet)
+	if err != nil {
+		return "", err
+	}
+	failureDomain := machineScope.Machine.Spec.FailureDomain
+	if failureDomain == "" || failureDomain == siteID {
+		machineScope.SetSubnet(subnet.Name, siteID)
+		return siteID, nil
+	}
+
+	for _, candidate := range subnets {
+		if candidate.Role != subnet.Role {
+			continue
+		}
+		candidateSiteID, err := clusterScope.SubnetSiteID(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if candidateSiteID == failureDomain {
+			machineScope.SetSubnet(candidate.Name, candidateSiteID)
+			return candidateSiteID, nil
+		}
+	}
+	return "", fmt.Errorf("no subnet with the role of subnet %s in failure domain %s", subnet.Name, failureDomain)
+}
+
+// errNoCapacity is returned when the site has no available machine of the
+// instance type of a new instance.
+var errNoCapacity = errors.New("no available machine")
+
+// checkCapacity checks that the site has an available machine of the instance
+// type of the request, so that a shortage is reported instead of failing the
+// creation. Requests targeting a machine are not checked, and the creation is
+// attempted when the available machines cannot be listed.
+func (r *NcxInfraMachineReconciler) checkCapacity(
+	ctx context.Context, clusterScope *scope.ClusterScope, siteID string, req nico.InstanceCreateRequest,
+) error {
+	if req.MachineId != nil || req.InstanceTypeId == nil {
+		return nil
+	}
+
+	listStart := time.Now()
+	machines, httpResp, err := clusterScope.NcxInfraClient.GetAllAvailableMachine(
+		ctx, clusterScope.OrgName, siteID, *req.InstanceTypeId)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetAllMachine")
+	recordAPIMetrics("GetAllMachine", listStart, apiErr)
+	if apiErr != nil {
+		log.FromContext(ctx).V(1).Info("Failed to list the available machines, creating the instance anyway",
+			"instanceTypeID", *req.InstanceTypeId, "error", apiErr.Message)
+		return nil
+	}
+	if len(machines) == 0 {
+		return ncxinfraerrors.Transient(ncxinfraerrors.WaitingForCapacity,
+			fmt.Errorf("%w of instance type %s on site %s", errNoCapacity, *req.InstanceTypeId, siteID))
+	}
+	return nil
+}
+
+// capacityRetryInterval returns the interval of the retries while the site has
+// no available machine or the tenant no instance quota left.
+func (r *NcxInfraMachineReconciler) capacityRetryInterval() time.Duration {
+	if r.CapacityRetryInterval > 0 {
+		return r.CapacityRetryInterval
+	}
+	return time.Minute
+}
+
+// checkBootstrapDataAge records the size and age of the bootstrap data, and
+// reports in the BootstrapDataFresh condition whether the bootstrap token it
+// embeds has likely expired. The instance is still created, but the node would
+// fail to join long after, so the machine is flagged for regeneration first.
+func (r *NcxInfraMachineReconciler) checkBootstrapDataAge(
+	ctx context.Context, machineScope *scope.MachineScope, bootstrapData, format string,
+) {
+	ncxinframetrics.BootstrapDataSize.WithLabelValues(format).Observe(float64(len(bootstrapData)))
+
+	created, err := machineScope.GetBootstrapDataCreationTime(ctx)
+	if err != nil || created.IsZero() {
+		return
+	}
+	age := time.Since(created)
+	ncxinframetrics.BootstrapDataAge.Observe(age.Seconds())
+	if r.BootstrapTokenTTL <= 0 {
+		return
+	}
+
+	machine := machineScope.NcxInfraMachine
+	if age <= r.BootstrapTokenTTL {
+		conditions.Set(machine, metav1.Condition{
+			Type:   string(BootstrapDataFreshCondition),
+			Status: metav1.ConditionTrue,
+			Reason: "BootstrapDataFresh",
+		})
+		return
+	}
+
+	message := fmt.Sprintf("bootstrap data is %s old, older than the %s bootstrap token TTL: "+
+		"the node will likely fail to join, delete the Machine to regenerate its bootstrap data",
+		age.Round(time.Second), r.BootstrapTokenTTL)
+	log.FromContext(ctx).Info("Bootstrap data is likely stale", "age", age.Round(time.Second))
+	if !conditions.IsFalse(machine, string(BootstrapDataFreshCondition)) {
+		r.recordEvent(machine, corev1.EventTypeWarning, "BootstrapDataStale", "%s", message)
+	}
+	conditions.Set(machine, metav1.Condition{
+		Type:    string(BootstrapDataFreshCondition),
+		Status:  metav1.ConditionFalse,
+		Reason:  "BootstrapDataStale",
+		Message: message,
+	})
+}
+
+// checkBootstrapFormat checks that the operating system of the machine
+// consumes bootstrap data of the given format, and reports it in the
+// BootstrapFormatCompatible condition. Machines without OS type are not checked.
+func checkBootstrapFormat(machine *infrastructurev1.NcxInfraMachine, format string) error {
+	reason := "CloudConfig"
+	if format == scope.BootstrapFormatIgnition {
+		reason = "Ignition"
+	}
+
+	osType := ""
+	if machine.Spec.OperatingSystem != nil {
+		osType = strings.ToLower(machine.Spec.OperatingSystem.Type)
+	}
+	if osType != "" {
+		expected := scope.BootstrapFormatCloudConfig
+		if ignitionOSTypes[osType] {
+			expected = scope.BootstrapFormatIgnition
+		}
+		if format != expected {
+			message := fmt.Sprintf("bootstrap data format %s does not match operating system %s, which expects %s",
+				format, machine.Spec.OperatingSystem.Type, expected)
+			conditions.Set(machine, metav1.Condition{
+				Type:    string(BootstrapFormatCondition),
+				Status:  metav1.ConditionFalse,
+				Reason:  "FormatMismatch",
+				Message: message,
+			})
+			return errors.New(message)
+		}
+	}
+
+	message := fmt.Sprintf("Bootstrap data format %s", format)
+	if osType != "" {
+		message += fmt.Sprintf(" matches operating system %s", machine.Spec.OperatingSystem.Type)
+	}
+	conditions.Set(machine, metav1.Condition{
+		Type:    string(BootstrapFormatCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+	return nil
+}
+
 func (r *NcxInfraMachineReconciler) reconcileInstance(
 	ctx context.Context,
 	machineScope *scope.MachineScope,
 	clusterScope *scope.ClusterScope,
 ) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
+	logger := log.FromContext(ctx).WithValues("instanceID", machineScope.InstanceID())
+	ctx = log.IntoContext(ctx, logger)
 
 	// Get instance status from NVIDIA Carbide
 	getStart := time.Now()
@@ -406,283 +971,1668 @@ func (r *NcxInfraMachineReconciler) reconcileInstance(
 	recordAPIMetrics("GetInstance", getStart, apiErr)
 	if apiErr != nil {
 		if apiErr.IsNotFound() {
-			logger.Info("Instance no longer exists", "instanceID", machineScope.InstanceID())
-			errReason := capierrors.MachineStatusError("InstanceNotFound")
+			logger.Info("Instance no longer exists")
+			errReason := capierrors.MachineStatusError(ncxinfraerrors.InstanceNotFound)
 			errMsg := fmt.Sprintf("Instance %s no longer exists", machineScope.InstanceID())
 			setMachineFailure(machineScope.NcxInfraMachine, errReason, errMsg)
 			return ctrl.Result{}, nil
 		}
 		if apiErr.IsTerminal() {
-			logger.Error(apiErr, "terminal error getting instance", "instanceID", machineScope.InstanceID())
+			logger.Error(apiErr, "terminal error getting instance")
+			return ctrl.Result{}, apiErr
+		}
+		if apiErr.IsForbidden() || scope.AuthenticationFailure(apiErr) != "" {
+			// Surfaced in the conditions by handlePermissionError
 			return ctrl.Result{}, apiErr
 		}
-		logger.Info("Transient error getting instance, will retry",
-			"instanceID", machineScope.InstanceID(), "error", apiErr.Message)
+		logger.Info("Transient error getting instance, will retry", "error", apiErr.Message)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	if instance == nil {
+		return ctrl.Result{}, fmt.Errorf("instance response is nil for %s", machineScope.InstanceID())
+	}
+
+	// Update instance state
+	if instance.Status != nil {
+		machineScope.SetInstanceState(string(*instance.Status))
+		setProvisioningPhaseConditions(machineScope.NcxInfraMachine, *instance.Status)
+	}
+	// Set serial console URL annotation if available
+	if instance.SerialConsoleUrl.Get() != nil && *instance.SerialConsoleUrl.Get() != "" {
+		if machineScope.NcxInfraMachine.Annotations == nil {
+			machineScope.NcxInfraMachine.Annotations = map[string]string{}
+		}
+		machineScope.NcxInfraMachine.Annotations["ncx-infra.io/serial-console-url"] = *instance.SerialConsoleUrl.Get()
+	}
+
+	if instance.MachineId.Get() != nil {
+		machineScope.SetMachineID(*instance.MachineId.Get())
+	}
+
+	// Record the inventory of the physical machine once it is known
+	if machineScope.NcxInfraMachine.Spec.Inventory != nil &&
+		machineScope.NcxInfraMachine.Status.Inventory == nil && machineScope.MachineID() != "" {
+		r.updateInventory(ctx, machineScope)
+	}
+
+	// Record the hardware of the physical machine once it is allocated
+	r.updateHardware(ctx, machineScope, instance)
+
+	// The instance stays Ready while its machine is powered off, reflect the power state instead
+	if r.poweredOff(ctx, machineScope, clusterScope, instance) {
+		machineScope.SetInstanceState("PoweredOff")
+	}
+
+	// Apply a power action requested through the annotation
+	if r.reconcilePowerAction(ctx, machineScope, clusterScope) {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	// Report the serial console requested through the annotation
+	r.reconcileConsole(ctx, machineScope, clusterScope, instance)
+
+	// Extract IP addresses from interfaces
+	addresses := machineAddresses(instance, clusterScope.NcxInfraCluster)
+
+	if len(addresses) > 0 {
+		machineScope.SetAddresses(addresses)
+		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+			Type:   string(NetworkConfiguredCondition),
+			Status: metav1.ConditionTrue,
+			Reason: "NetworkReady",
+		})
+	}
+
+	// Update health conditions from fault events (NEP-0007) if supported
+	if r.hasFaultManagement(ctx, clusterScope) {
+		r.updateHealthConditions(ctx, machineScope)
+	}
+
+	// Reimage the instance when its bootstrap data or operating system changed
+	reimaging, err := r.reconcileReprovision(ctx, machineScope, clusterScope, instance)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if reimaging {
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
-
-	if instance == nil {
-		return ctrl.Result{}, fmt.Errorf("instance response is nil for %s", machineScope.InstanceID())
+
+	// Check if instance is ready
+	if instance.Status != nil && string(*instance.Status) == "Ready" {
+		// Hold the machine back until the instance meets its security requirements
+		if !r.verifyInstanceFirmware(ctx, machineScope, instance) || !r.verifyAttestation(machineScope, instance) {
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		return r.handleInstanceReady(ctx, machineScope, clusterScope, instance, addresses)
+	}
+
+	// Instance is still provisioning or in error, requeue
+	instanceIDStr := ""
+	statusStr := ""
+	if instance.Id != nil {
+		instanceIDStr = *instance.Id
+	}
+	if instance.Status != nil {
+		statusStr = string(*instance.Status)
+	}
+
+	// Fetch and expose status history for debugging when in error or prolonged provisioning
+	if statusStr == "Error" || statusStr == "Provisioning" {
+		r.exposeStatusHistory(ctx, machineScope)
+	}
+
+	// Set failure info for error state, enriched with fault events when available
+	if statusStr == "Error" {
+		errReason := capierrors.MachineStatusError(ncxinfraerrors.ProvisioningFailed)
+		errMsg := fmt.Sprintf("Instance %s is in Error state", instanceIDStr)
+
+		// Try to enrich with fault event details if fault management is supported
+		if r.hasFaultManagement(ctx, clusterScope) {
+			if healthMsg := r.getMachineHealthMessage(ctx, machineScope); healthMsg != "" {
+				errMsg = fmt.Sprintf("Instance %s is in Error state: %s", instanceIDStr, healthMsg)
+				errReason = capierrors.UpdateMachineError
+			}
+		}
+
+		setMachineFailure(machineScope.NcxInfraMachine, errReason, errMsg)
+	}
+
+	phase, ok := provisioningPhases[nico.InstanceStatus(statusStr)]
+	if !ok {
+		phase = provisioningPhase{reason: "WaitingForReady", description: "waiting to be ready", requeueAfter: 30 * time.Second}
+	}
+	conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+		Type:    string(InstanceProvisioningCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  phase.reason,
+		Message: fmt.Sprintf("Instance %s is in state %s, %s", instanceIDStr, statusStr, phase.description),
+	})
+
+	logger.Info("Waiting for instance to be ready", "status", statusStr, "phase", phase.reason)
+
+	return ctrl.Result{RequeueAfter: phase.requeueAfter}, nil
+}
+
+// provisioningPhase describes a phase of the creation of an instance.
+type provisioningPhase struct {
+	reason      string
+	description string
+	// requeueAfter is the interval at which the instance is checked during the phase
+	requeueAfter time.Duration
+}
+
+// provisioningPhases maps the NVIDIA Carbide statuses of an instance being
+// created to their phase: the allocation of a machine, which can wait for
+// capacity, the writing of the operating system image, then the configuration
+// of the network.
+var provisioningPhases = map[nico.InstanceStatus]provisioningPhase{
+	nico.INSTANCESTATUS_PENDING: {
+		reason: "WaitingForMachineAllocation", description: "waiting for a machine to be allocated", requeueAfter: time.Minute,
+	},
+	nico.INSTANCESTATUS_PROVISIONING: {
+		reason: "WritingImage", description: "writing the operating system image", requeueAfter: 30 * time.Second,
+	},
+	nico.INSTANCESTATUS_CONFIGURING: {
+		reason: "ConfiguringNetwork", description: "configuring the network", requeueAfter: 15 * time.Second,
+	},
+}
+
+// setProvisioningPhaseConditions sets the InstanceAllocated, InstanceImaged
+// and InstanceBooted conditions from the status of the instance, so that their
+// lastTransitionTime records when each phase of the provisioning ended. The
+// statuses following the creation, such as Updating or Rebooting, leave them
+// unchanged.
+func setProvisioningPhaseConditions(machine *infrastructurev1.NcxInfraMachine, status nico.InstanceStatus) {
+	allocated, imaged, booted := metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionTrue
+	switch status {
+	case nico.INSTANCESTATUS_PENDING:
+		allocated, imaged, booted = metav1.ConditionFalse, metav1.ConditionFalse, metav1.ConditionFalse
+	case nico.INSTANCESTATUS_PROVISIONING:
+		imaged, booted = metav1.ConditionFalse, metav1.ConditionFalse
+	case nico.INSTANCESTATUS_CONFIGURING:
+		booted = metav1.ConditionFalse
+	case nico.INSTANCESTATUS_READY:
+		// All the phases are complete
+	default:
+		return
+	}
+
+	condition := metav1.Condition{Type: string(InstanceAllocatedCondition), Status: allocated, Reason: "MachineAllocated"}
+	if allocated == metav1.ConditionFalse {
+		condition.Reason = "WaitingForMachineAllocation"
+	}
+	conditions.Set(machine, condition)
+
+	condition = metav1.Condition{Type: string(InstanceImagedCondition), Status: imaged, Reason: "ImageWritten"}
+	if status == nico.INSTANCESTATUS_PENDING {
+		condition.Reason = "WaitingForMachineAllocation"
+	} else if imaged == metav1.ConditionFalse {
+		condition.Reason = "WritingImage"
+	}
+	conditions.Set(machine, condition)
+
+	condition = metav1.Condition{Type: string(InstanceBootedCondition), Status: booted, Reason: "InstanceBooted"}
+	switch status {
+	case nico.INSTANCESTATUS_PENDING:
+		condition.Reason = "WaitingForMachineAllocation"
+	case nico.INSTANCESTATUS_PROVISIONING:
+		condition.Reason = "WritingImage"
+	case nico.INSTANCESTATUS_CONFIGURING:
+		condition.Reason = "ConfiguringNetwork"
+	}
+	conditions.Set(machine, condition)
+}
+
+func (r *NcxInfraMachineReconciler) handleInstanceReady(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+	instance *nico.Instance,
+	addresses []clusterv1.MachineAddress,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	machineScope.SetReady(true)
+	ncxinframetrics.MachinesManaged.Inc()
+
+	// Record provisioning duration if instance has a creation timestamp
+	if instance.Created != nil {
+		duration := time.Since(*instance.Created).Seconds()
+		siteLabel := ""
+		if instance.SiteId != nil {
+			siteLabel = *instance.SiteId
+		}
+		instanceTypeLabel := ""
+		if instance.InstanceTypeId != nil {
+			instanceTypeLabel = *instance.InstanceTypeId
+		}
+		ncxinframetrics.InstanceProvisioningDuration.
+			WithLabelValues(siteLabel, instanceTypeLabel).Observe(duration)
+	}
+
+	conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+		Type:   string(InstanceProvisioningCondition),
+		Status: metav1.ConditionFalse,
+		Reason: "ProvisioningComplete",
+	})
+
+	// Apply post-creation updates if spec has changed
+	// Changes from all sources are coalesced into a single update call
+	if updateReq, needsUpdate := r.buildUpdateRequest(machineScope, clusterScope, instance); needsUpdate {
+		logger.Info("Applying post-creation updates to instance")
+		updateStart := time.Now()
+		_, httpResp, err := machineScope.NcxInfraClient.UpdateInstance(
+			ctx, machineScope.OrgName, machineScope.InstanceID(), updateReq)
+		updateErr := scope.ClassifyAPIError(httpResp, err, "UpdateInstance")
+		recordAPIMetrics("UpdateInstance", updateStart, updateErr)
+		if updateErr != nil {
+			logger.Error(updateErr, "failed to update instance")
+			r.recordEvent(machineScope.NcxInfraMachine,
+				corev1.EventTypeWarning, "UpdateFailed",
+				"Failed to update instance %s: %v",
+				machineScope.InstanceID(), updateErr)
+		} else {
+			r.recordEvent(machineScope.NcxInfraMachine,
+				corev1.EventTypeNormal, "InstanceUpdated",
+				"Successfully updated instance %s", machineScope.InstanceID())
+		}
+	}
+
+	// Report the control plane endpoint if not already configured
+	if machineScope.IsControlPlane() {
+		if endpoint := controlPlaneEndpoint(clusterScope.NcxInfraCluster, addresses); endpoint != nil {
+			if err := r.setControlPlaneEndpoint(ctx, clusterScope.NcxInfraCluster, endpoint); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to set the control plane endpoint: %w", err)
+			}
+			logger.Info("Discovered control plane endpoint",
+				"host", endpoint.Host, "port", endpoint.Port,
+				"management", clusterScope.NcxInfraCluster.Spec.ControlPlaneEndpointManagement)
+		}
+	}
+
+	instanceIDStr := ""
+	if instance.Id != nil {
+		instanceIDStr = *instance.Id
+	}
+	logger.Info("NcxInfraMachine is ready", "status", string(*instance.Status))
+	r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeNormal, "InstanceReady",
+		"Instance %s is ready", instanceIDStr)
+
+	// Report the virtual functions of the interfaces with SR-IOV
+	r.updateSRIOVStatus(ctx, machineScope, clusterScope, instance)
+
+	// Gather the topology labels of the machine once, they are applied to the node
+	if machineScope.NcxInfraMachine.Spec.NodeTopologyLabels && machineScope.NcxInfraMachine.Status.Topology == nil {
+		r.updateTopology(ctx, machineScope, instance)
+	}
+
+	// Keep checking the node until it joined the cluster and is healthy
+	if !r.reconcileNode(ctx, machineScope) {
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// machineAddresses returns the IP addresses of the instance interfaces. The
+// addresses on a subnet with Public egress are publicly routable and reported
+// as ExternalIP, the others as InternalIP.
+func machineAddresses(instance *nico.Instance, cluster *infrastructurev1.NcxInfraCluster) []clusterv1.MachineAddress {
+	publicSubnetIDs := map[string]bool{}
+	for _, subnet := range cluster.Spec.Subnets {
+		if subnet.Egress != infrastructurev1.SubnetEgressPublic {
+			continue
+		}
+		if id := cluster.Status.NetworkStatus.SubnetID(subnet.Name); id != "" {
+			publicSubnetIDs[id] = true
+		}
+	}
+
+	addresses := []clusterv1.MachineAddress{}
+	for _, iface := range instance.Interfaces {
+		addressType := clusterv1.MachineInternalIP
+		if subnetID := iface.SubnetId.Get(); subnetID != nil && publicSubnetIDs[*subnetID] {
+			addressType = clusterv1.MachineExternalIP
+		}
+		for _, ipAddr := range iface.IpAddresses {
+			addresses = append(addresses, clusterv1.MachineAddress{
+				Type:    addressType,
+				Address: ipAddr,
+			})
+		}
+	}
+	return addresses
+}
+
+// controlPlaneEndpoint returns the control plane endpoint discovered from the
+// addresses of a ready control plane machine, or nil when the endpoint is
+// already set or reported, or managed externally.
+func controlPlaneEndpoint(
+	cluster *infrastructurev1.NcxInfraCluster, addresses []clusterv1.MachineAddress,
+) *clusterv1.APIEndpoint {
+	if cluster.Spec.ControlPlaneEndpointManagement == infrastructurev1.ControlPlaneEndpointExternal {
+		return nil
+	}
+	cpEndpoint := cluster.Spec.ControlPlaneEndpoint
+	if (cpEndpoint != nil && cpEndpoint.Host != "") || len(addresses) == 0 {
+		return nil
+	}
+	if reported := cluster.Status.ControlPlaneEndpoint; reported != nil && reported.Host != "" {
+		return nil
+	}
+	port := int32(6443)
+	if cpEndpoint != nil && cpEndpoint.Port != 0 {
+		port = cpEndpoint.Port
+	}
+	return &clusterv1.APIEndpoint{Host: addresses[0].Address, Port: port}
+}
+
+// setControlPlaneEndpoint reports the discovered control plane endpoint in the
+// status of the cluster, and also sets it in the spec unless the management is
+// Reported, to leave the spec of those clusters to their owner. A cluster
+// created before the management was introduced has no management and keeps
+// its endpoint set in the spec.
+func (r *NcxInfraMachineReconciler) setControlPlaneEndpoint(
+	ctx context.Context, cluster *infrastructurev1.NcxInfraCluster, endpoint *clusterv1.APIEndpoint,
+) error {
+	patchHelper, err := patch.NewHelper(cluster, r.Client)
+	if err != nil {
+		return err
+	}
+	cluster.Status.ControlPlaneEndpoint = endpoint
+	if cluster.Spec.ControlPlaneEndpointManagement != infrastructurev1.ControlPlaneEndpointReported {
+		cluster.Spec.ControlPlaneEndpoint = endpoint.DeepCopy()
+	}
+	return patchHelper.Patch(ctx, cluster)
+}
+
+// reconcileNode looks up the workload cluster Node matching the machine
+// provider ID and reflects its health in the NodeHealthy condition.
+// It returns true once the Node is found and Ready, or when the workload
+// cluster cannot be inspected.
+func (r *NcxInfraMachineReconciler) reconcileNode(ctx context.Context, machineScope *scope.MachineScope) bool {
+	logger := log.FromContext(ctx)
+
+	if r.ClusterCache == nil {
+		return true
+	}
+
+	providerID := machineScope.ProviderID()
+	if providerID == nil {
+		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+			Type:   string(NodeHealthyCondition),
+			Status: metav1.ConditionUnknown,
+			Reason: "WaitingForProviderID",
+		})
+		return false
+	}
+
+	remoteClient, err := r.ClusterCache.GetClient(ctx, util.ObjectKey(machineScope.Cluster))
+	if err != nil {
+		// The workload cluster API server is not reachable yet during bootstrap
+		logger.V(4).Info("Workload cluster is not reachable", "error", err.Error())
+		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+			Type:    string(NodeHealthyCondition),
+			Status:  metav1.ConditionUnknown,
+			Reason:  "WorkloadClusterNotReachable",
+			Message: err.Error(),
+		})
+		return false
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := remoteClient.List(ctx, nodes,
+		client.MatchingFields{index.NodeProviderIDField: providerID.String()}); err != nil {
+		logger.Error(err, "failed to list workload cluster nodes")
+		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+			Type:    string(NodeHealthyCondition),
+			Status:  metav1.ConditionUnknown,
+			Reason:  "NodeLookupFailed",
+			Message: err.Error(),
+		})
+		return false
+	}
+
+	if len(nodes.Items) == 0 {
+		machineScope.NcxInfraMachine.Status.NodeName = ""
+		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+			Type:    string(NodeHealthyCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "NodeNotFound",
+			Message: fmt.Sprintf("No node with provider ID %s has joined the cluster", providerID.String()),
+		})
+		return false
+	}
+
+	node := &nodes.Items[0]
+	machineScope.NcxInfraMachine.Status.NodeName = node.Name
+
+	if err := applyNodeTopologyLabels(ctx, remoteClient, node, machineScope); err != nil {
+		logger.Error(err, "failed to apply topology labels", "node", node.Name)
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "NodeTopologyLabelsFailed",
+			"Failed to apply topology labels to node %s: %v", node.Name, err)
+	}
+	if err := applyNodeInventoryLabels(ctx, remoteClient, node, machineScope); err != nil {
+		logger.Error(err, "failed to apply inventory labels", "node", node.Name)
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "NodeInventoryLabelsFailed",
+			"Failed to apply inventory labels to node %s: %v", node.Name, err)
+	}
+
+	for _, cond := range node.Status.Conditions {
+		if cond.Type != corev1.NodeReady {
+			continue
+		}
+		if cond.Status == corev1.ConditionTrue {
+			conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+				Type:   string(NodeHealthyCondition),
+				Status: metav1.ConditionTrue,
+				Reason: "NodeReady",
+			})
+			return true
+		}
+		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+			Type:    string(NodeHealthyCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "NodeNotReady",
+			Message: fmt.Sprintf("Node %s is not ready: %s", node.Name, cond.Message),
+		})
+		return false
+	}
+
+	conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+		Type:    string(NodeHealthyCondition),
+		Status:  metav1.ConditionUnknown,
+		Reason:  "NodeReadyConditionMissing",
+		Message: fmt.Sprintf("Node %s has not reported its Ready condition yet", node.Name),
+	})
+	return false
+}
+
+// applyNodeTopologyLabels adds the topology labels recorded in status to the Node.
+func applyNodeTopologyLabels(
+	ctx context.Context, c client.Client, node *corev1.Node, machineScope *scope.MachineScope,
+) error {
+	topology := machineScope.NcxInfraMachine.Status.Topology
+	if !machineScope.NcxInfraMachine.Spec.NodeTopologyLabels || len(topology) == 0 {
+		return nil
+	}
+	return patchNodeLabels(ctx, c, node, topology)
+}
+
+// applyNodeInventoryLabels adds the inventory labels recorded in status to the
+// Node, under the node label prefix. They are already set at registration when
+// the machine was known before the instance was created, and added here
+// otherwise.
+func applyNodeInventoryLabels(
+	ctx context.Context, c client.Client, node *corev1.Node, machineScope *scope.MachineScope,
+) error {
+	inventory := machineScope.NcxInfraMachine.Spec.Inventory
+	if inventory == nil || inventory.NodeLabelPrefix == "" {
+		return nil
+	}
+	labels, _ := inventoryNodeLabels(inventory.NodeLabelPrefix, machineScope.NcxInfraMachine.Status.Inventory)
+	if len(labels) == 0 {
+		return nil
+	}
+	return patchNodeLabels(ctx, c, node, labels)
+}
+
+// patchNodeLabels adds the labels to the Node, patching it only when one of
+// them is missing or different.
+func patchNodeLabels(ctx context.Context, c client.Client, node *corev1.Node, labels map[string]string) error {
+	base := client.MergeFrom(node.DeepCopy())
+	changed := false
+	for key, value := range labels {
+		if current, ok := node.Labels[key]; ok && current == value {
+			continue
+		}
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[key] = value
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return c.Patch(ctx, node, base)
+}
+
+// updateTopology records the topology labels of the instance and its machine in status.
+// The rack is only visible to provider admins and is omitted otherwise.
+func (r *NcxInfraMachineReconciler) updateTopology(
+	ctx context.Context, machineScope *scope.MachineScope, instance *nico.Instance,
+) {
+	logger := log.FromContext(ctx)
+	topology := map[string]string{}
+
+	siteID := ""
+	if instance.SiteId != nil {
+		siteID = *instance.SiteId
+		setTopologyLabel(topology, infrastructurev1.TopologySiteLabel, siteID)
+	}
+	for _, nvlink := range instance.NvLinkInterfaces {
+		if nvlink.NvLinkDomainId != nil {
+			setTopologyLabel(topology, infrastructurev1.TopologyNVLinkDomainLabel, *nvlink.NvLinkDomainId)
+			break
+		}
+	}
+
+	rails := 0
+	partitions := map[string]bool{}
+	for _, ib := range instance.InfinibandInterfaces {
+		if ib.IsPhysical != nil && !*ib.IsPhysical {
+			continue
+		}
+		rails++
+		if ib.PartitionId != nil {
+			partitions[*ib.PartitionId] = true
+		}
+	}
+	if rails > 0 {
+		setTopologyLabel(topology, infrastructurev1.TopologyInfiniBandRailsLabel, strconv.Itoa(rails))
+	}
+	if len(partitions) == 1 {
+		for partition := range partitions {
+			setTopologyLabel(topology, infrastructurev1.TopologyInfiniBandPartitionLabel, partition)
+		}
+	}
+
+	if machineID := machineScope.MachineID(); machineID != "" {
+		getStart := time.Now()
+		machine, httpResp, err := machineScope.NcxInfraClient.GetMachine(ctx, machineScope.OrgName, machineID)
+		apiErr := scope.ClassifyAPIError(httpResp, err, "GetMachine")
+		recordAPIMetrics("GetMachine", getStart, apiErr)
+		if apiErr != nil {
+			logger.Info("Failed to get machine topology, will retry", "machineID", machineID, "error", apiErr.Message)
+			return
+		}
+		if machine.Metadata != nil && len(machine.Metadata.Gpus) > 0 {
+			setTopologyLabel(topology, infrastructurev1.TopologyGPUCountLabel, strconv.Itoa(len(machine.Metadata.Gpus)))
+			if name := machine.Metadata.Gpus[0].Name; name != nil {
+				setTopologyLabel(topology, infrastructurev1.TopologyGPUModelLabel, *name)
+			}
+		}
+
+		trays, _, err := machineScope.NcxInfraClient.GetAllTray(
+			ctx, machineScope.OrgName, siteID, "compute", machineID)
+		if err == nil && len(trays) > 0 && trays[0].RackId != nil {
+			setTopologyLabel(topology, infrastructurev1.TopologyRackLabel, *trays[0].RackId)
+		}
+	}
+
+	machineScope.NcxInfraMachine.Status.Topology = topology
+}
+
+var invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// setTopologyLabel records a topology label, turning the value into a valid label value.
+func setTopologyLabel(labels map[string]string, key, value string) {
+	value = invalidLabelValueChars.ReplaceAllString(value, "-")
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	value = strings.Trim(value, "-_.")
+	if value != "" {
+		labels[key] = value
+	}
+}
+
+//nolint:unparam // ctrl.Result is part of the reconciler interface contract
+func (r *NcxInfraMachineReconciler) reconcileDelete(
+	ctx context.Context, machineScope *scope.MachineScope,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Deleting NcxInfraMachine")
+
+	// Keep the instance until Cluster API drained its Node
+	if machineScope.InstanceID() != "" && waitForDrain(machineScope, time.Now()) {
+		logger.Info("Waiting for the Node to be drained before deleting the instance",
+			"reason", conditions.GetReason(machineScope.NcxInfraMachine, string(WaitingForDrainCondition)))
+		return ctrl.Result{RequeueAfter: drainPollInterval}, nil
+	}
+
+	// Release the instance to the warm pool of the cluster, or delete it
+	pooled := false
+	if machineScope.InstanceID() != "" {
+		var err error
+		if pooled, err = r.releaseToWarmPool(ctx, machineScope); err != nil {
+			logger.Error(err, "failed to release the instance to the warm pool, deleting it")
+		}
+	}
+	if machineScope.InstanceID() != "" && !pooled {
+		logger = logger.WithValues("instanceID", machineScope.InstanceID())
+		ctx = log.IntoContext(ctx, logger)
+		logger.Info("Deleting NVIDIA Carbide instance")
+
+		deleteStart := time.Now()
+		httpResp, err := machineScope.NcxInfraClient.DeleteInstance(
+			ctx, machineScope.OrgName, machineScope.InstanceID(), buildDeleteRequest(machineScope))
+		delAPIErr := scope.ClassifyAPIError(httpResp, err, "DeleteInstance")
+		recordAPIMetrics("DeleteInstance", deleteStart, delAPIErr)
+		if apiErr := delAPIErr; apiErr != nil {
+			if apiErr.IsNotFound() {
+				logger.Info("Instance already deleted")
+			} else if apiErr.IsTransient() && scope.AuthenticationFailure(apiErr) == "" {
+				logger.Info("Transient error deleting instance, will retry", "error", apiErr.Message)
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			} else {
+				logger.Error(apiErr, "failed to delete instance")
+				return ctrl.Result{}, apiErr
+			}
+		}
+	}
+
+	if deletion := machineScope.NcxInfraMachine.Spec.Deletion; deletion != nil &&
+		deletion.SecureErase == infrastructurev1.SecureEraseVerified {
+		erased, err := r.isMachineErased(ctx, machineScope)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !erased {
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+	}
+
+	// Remove finalizer
+	controllerutil.RemoveFinalizer(machineScope.NcxInfraMachine, NcxInfraMachineFinalizer)
+
+	if machineScope.IsReady() {
+		ncxinframetrics.MachinesManaged.Dec()
+	}
+	if conditions.IsFalse(machineScope.NcxInfraMachine, string(SubnetAvailableCondition)) {
+		ncxinframetrics.MachinesBlockedOnNetwork.Dec()
+	}
+
+	logger.Info("Successfully deleted NcxInfraMachine")
+	return ctrl.Result{}, nil
+}
+
+// buildDeleteRequest maps spec.deletion to the delete instance request.
+// It returns nil when the machine is simply released back to the pool.
+func buildDeleteRequest(machineScope *scope.MachineScope) *nico.InstanceDeleteRequest {
+	deletion := machineScope.NcxInfraMachine.Spec.Deletion
+	if deletion == nil || deletion.Policy != infrastructurev1.DeletionPolicyRepair {
+		return nil
+	}
+
+	category := "Hardware"
+	summary := "Machine reported for repair by Cluster API"
+	var details *string
+	if issue := deletion.HealthIssue; issue != nil {
+		if issue.Category != "" {
+			category = issue.Category
+		}
+		if issue.Summary != "" {
+			summary = issue.Summary
+		}
+		if issue.Details != "" {
+			details = &issue.Details
+		}
+	}
+	if details == nil && machineScope.NcxInfraMachine.Status.FailureMessage != nil {
+		details = machineScope.NcxInfraMachine.Status.FailureMessage
+	}
+
+	healthIssue := nico.MachineHealthIssue{
+		Category: &category,
+		Summary:  &summary,
+	}
+	if details != nil {
+		healthIssue.Details = *nico.NewNullableString(details)
+	}
+	return &nico.InstanceDeleteRequest{MachineHealthIssue: &healthIssue}
+}
+
+// isMachineErased reports whether the released machine has completed the
+// NVIDIA Carbide reset cycle, during which its local disks are wiped.
+func (r *NcxInfraMachineReconciler) isMachineErased(
+	ctx context.Context, machineScope *scope.MachineScope,
+) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	machineID := machineScope.MachineID()
+	if machineID == "" {
+		// The instance was never placed on a machine, nothing to erase
+		return true, nil
+	}
+
+	getStart := time.Now()
+	machine, httpResp, err := machineScope.NcxInfraClient.GetMachine(ctx, machineScope.OrgName, machineID)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetMachine")
+	recordAPIMetrics("GetMachine", getStart, apiErr)
+	if apiErr != nil {
+		if apiErr.IsNotFound() {
+			// Machine is no longer visible to the tenant once it left the tenant allocation
+			return true, nil
+		}
+		if apiErr.IsTransient() {
+			logger.Info("Transient error checking machine erase status, will retry",
+				"machineID", machineID, "error", apiErr.Message)
+			return false, nil
+		}
+		return false, apiErr
+	}
+
+	status := machine.GetStatus()
+	if status == "InUse" || status == "Reset" {
+		logger.Info("Waiting for machine disks to be erased", "machineID", machineID, "status", status)
+		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+			Type:    string(SecureEraseCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "WaitingForReset",
+			Message: fmt.Sprintf("Machine %s is in %s state", machineID, status),
+		})
+		return false, nil
+	}
+
+	conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+		Type:   string(SecureEraseCondition),
+		Status: metav1.ConditionTrue,
+		Reason: "MachineReset",
+	})
+	r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeNormal, "SecureEraseCompleted",
+		"Machine %s completed its reset cycle", machineID)
+	return true, nil
+}
+
+const (
+	powerActionSubmitted  = "Submitted"
+	powerActionFailed     = "Failed"
+	powerActionSuperseded = "Superseded"
+)
+
+// powerOffSettleTime is how long the compute tray may still report being on
+// after a power-off was submitted.
+const powerOffSettleTime = 5 * time.Minute
+
+// poweredOff returns true while the machine of the instance is powered off by
+// the last power action. The power-off is superseded once the instance leaves
+// the Ready state, e.g. to be rebooted or reimaged, or once the compute tray
+// hosting the machine is powered on again outside of the provider, after the
+// power-off had time to complete.
+func (r *NcxInfraMachineReconciler) poweredOff(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+	instance *nico.Instance,
+) bool {
+	logger := log.FromContext(ctx)
+
+	last := machineScope.NcxInfraMachine.Status.LastPowerAction
+	if last == nil || last.Action != infrastructurev1.PowerActionPowerOff || last.Result != powerActionSubmitted {
+		return false
+	}
+
+	if instance.Status != nil && *instance.Status != nico.INSTANCESTATUS_READY {
+		last.Result = powerActionSuperseded
+		last.Message = fmt.Sprintf("instance is %s", *instance.Status)
+		return false
+	}
+
+	if time.Since(last.Time.Time) < powerOffSettleTime {
+		return true
+	}
+	tray, _, apiErr := r.computeTray(ctx, machineScope, clusterScope)
+	if apiErr != nil {
+		// Keep reporting the power-off until the tray can be inspected
+		logger.V(4).Info("Failed to get the power state of the machine", "error", apiErr.Message)
+		return true
+	}
+	if state := tray.GetPowerState(); strings.EqualFold(state, "On") || strings.EqualFold(state, "PoweringOn") {
+		last.Result = powerActionSuperseded
+		last.Message = fmt.Sprintf("compute tray is %s", state)
+		return false
+	}
+	return true
+}
+
+// reconcilePowerAction submits the power action requested through the power
+// action annotation. It returns true when the action is still pending and the
+// reconcile should requeue to retry it or to observe its result.
+func (r *NcxInfraMachineReconciler) reconcilePowerAction(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+) bool {
+	logger := log.FromContext(ctx)
+
+	value, ok := machineScope.NcxInfraMachine.Annotations[infrastructurev1.PowerActionAnnotation]
+	if !ok {
+		return false
+	}
+	action := infrastructurev1.PowerAction(value)
+
+	var apiErr *scope.APIError
+	switch action {
+	case infrastructurev1.PowerActionReboot:
+		apiErr = r.rebootInstance(ctx, machineScope)
+	case infrastructurev1.PowerActionPowerOff:
+		apiErr = r.powerControlMachine(ctx, machineScope, clusterScope, "off")
+	case infrastructurev1.PowerActionPowerOn:
+		apiErr = r.powerControlMachine(ctx, machineScope, clusterScope, "on")
+	default:
+		r.finishPowerAction(machineScope, action, fmt.Errorf("unsupported power action %q", value))
+		return false
+	}
+
+	if apiErr != nil && apiErr.IsTransient() {
+		logger.Info("Transient error applying power action, will retry",
+			"action", action, "error", apiErr.Message)
+		return true
+	}
+	if apiErr != nil {
+		r.finishPowerAction(machineScope, action, apiErr)
+		return false
+	}
+
+	logger.Info("Submitted power action", "action", action, "machineID", machineScope.MachineID())
+	r.finishPowerAction(machineScope, action, nil)
+	return true
+}
+
+// rebootInstance reboots the instance through the tenant instance API.
+func (r *NcxInfraMachineReconciler) rebootInstance(
+	ctx context.Context, machineScope *scope.MachineScope,
+) *scope.APIError {
+	updateReq := nico.InstanceUpdateRequest{
+		TriggerReboot: *nico.NewNullableBool(nico.PtrBool(true)),
+	}
+
+	updateStart := time.Now()
+	instance, httpResp, err := machineScope.NcxInfraClient.UpdateInstance(
+		ctx, machineScope.OrgName, machineScope.InstanceID(), updateReq)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "UpdateInstance")
+	recordAPIMetrics("UpdateInstance", updateStart, apiErr)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	if instance != nil && instance.Status != nil {
+		machineScope.SetInstanceState(string(*instance.Status))
+	}
+	return nil
+}
+
+// powerControlMachine applies a power state to the compute tray hosting the
+// machine. Tray power control requires the provider admin role.
+func (r *NcxInfraMachineReconciler) powerControlMachine(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+	state string,
+) *scope.APIError {
+	tray, siteID, apiErr := r.computeTray(ctx, machineScope, clusterScope)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	powerStart := time.Now()
+	_, httpResp, err := machineScope.NcxInfraClient.PowerControlTray(
+		ctx, machineScope.OrgName, *tray.Id, nico.UpdatePowerStateRequest{SiteId: siteID, State: state})
+	apiErr = scope.ClassifyAPIError(httpResp, err, "PowerControlTray")
+	recordAPIMetrics("PowerControlTray", powerStart, apiErr)
+	return apiErr
+}
+
+// computeTray returns the compute tray hosting the machine of the instance,
+// and the site of the instance. Listing the trays requires the provider admin
+// role.
+func (r *NcxInfraMachineReconciler) computeTray(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+) (*nico.Tray, string, *scope.APIError) {
+	machineID := machineScope.MachineID()
+	if machineID == "" {
+		return nil, "", &scope.APIError{
+			Type:    scope.APIErrorTerminal,
+			Message: "instance is not placed on a machine yet",
+		}
+	}
+
+	// The instance is in the site of its provider ID, which is the site of the
+	// cluster unless the machine is in a subnet placed in another site
+	siteID := ""
+	if providerID := machineScope.ProviderID(); providerID != nil {
+		siteID = providerID.SiteName
+	}
+	if siteID == "" {
+		var err error
+		if siteID, err = clusterScope.SiteID(ctx); err != nil {
+			return nil, "", &scope.APIError{
+				Type:    scope.APIErrorTransient,
+				Message: fmt.Sprintf("failed to get site ID: %v", err),
+				Err:     err,
+			}
+		}
+	}
+
+	listStart := time.Now()
+	trays, httpResp, err := machineScope.NcxInfraClient.GetAllTray(
+		ctx, machineScope.OrgName, siteID, "compute", machineID)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetAllTray")
+	recordAPIMetrics("GetAllTray", listStart, apiErr)
+	if apiErr != nil {
+		return nil, "", apiErr
+	}
+	if len(trays) == 0 || trays[0].Id == nil {
+		return nil, "", &scope.APIError{
+			Type:    scope.APIErrorNotFound,
+			Message: fmt.Sprintf("no compute tray found for machine %s", machineID),
+		}
+	}
+	return &trays[0], siteID, nil
+}
+
+// finishPowerAction records the outcome of a power action and removes the annotation.
+func (r *NcxInfraMachineReconciler) finishPowerAction(
+	machineScope *scope.MachineScope, action infrastructurev1.PowerAction, err error,
+) {
+	delete(machineScope.NcxInfraMachine.Annotations, infrastructurev1.PowerActionAnnotation)
+
+	powerStatus := &infrastructurev1.PowerActionStatus{
+		Action: action,
+		Result: powerActionSubmitted,
+		Time:   metav1.Now(),
+	}
+	if err != nil {
+		powerStatus.Result = powerActionFailed
+		powerStatus.Message = err.Error()
+		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+			Type:    string(PowerActionCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "PowerActionFailed",
+			Message: fmt.Sprintf("Power action %s failed: %v", action, err),
+		})
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "PowerActionFailed",
+			"Power action %s failed: %v", action, err)
+	} else {
+		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+			Type:    string(PowerActionCondition),
+			Status:  metav1.ConditionTrue,
+			Reason:  "PowerActionSubmitted",
+			Message: fmt.Sprintf("Power action %s submitted", action),
+		})
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeNormal, "PowerActionSubmitted",
+			"Power action %s submitted for instance %s", action, machineScope.InstanceID())
+	}
+	machineScope.NcxInfraMachine.Status.LastPowerAction = powerStatus
+}
+
+// validateCapabilities checks site and tenant capabilities for advanced features.
+func (r *NcxInfraMachineReconciler) validateCapabilities(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+) error {
+	spec := machineScope.NcxInfraMachine.Spec
+
+	if len(spec.NVLinkInterfaces) > 0 || len(spec.InfiniBandInterfaces) > 0 {
+		siteID, siteErr := clusterScope.SiteID(ctx)
+		if siteErr == nil {
+			site, _, siteErr := clusterScope.NcxInfraClient.GetSite(
+				ctx, clusterScope.OrgName, siteID)
+			if siteErr == nil && site != nil && site.Capabilities != nil {
+				if len(spec.NVLinkInterfaces) > 0 &&
+					site.Capabilities.NvLinkPartition != nil &&
+					!*site.Capabilities.NvLinkPartition {
+					return fmt.Errorf("site %s does not support NVLink partitioning", siteID)
+				}
+			}
+		}
+	}
+
+	if spec.InstanceType.MachineID != "" {
+		tenant, _, tenantErr := clusterScope.NcxInfraClient.GetCurrentTenant(
+			ctx, clusterScope.OrgName)
+		if tenantErr == nil && tenant != nil && tenant.Capabilities != nil {
+			if tenant.Capabilities.TargetedInstanceCreation != nil &&
+				!*tenant.Capabilities.TargetedInstanceCreation {
+				return fmt.Errorf("tenant does not have targeted instance creation enabled; cannot use machineID")
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyPlacement asks the placement strategy which machine, or instance type,
+// the instance is created on. The decision is recorded in status.placement.
+func (r *NcxInfraMachineReconciler) applyPlacement(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+	siteID string,
+	req *nico.InstanceCreateRequest,
+) error {
+	strategy := r.PlacementStrategy
+	if strategy == nil {
+		strategy = placement.Default
+	}
+
+	decision, err := strategy.Place(ctx, placement.Request{
+		Machine:      machineScope.NcxInfraMachine,
+		ClusterName:  machineScope.Cluster.Name,
+		ControlPlane: machineScope.IsControlPlane(),
+		SiteID:       siteID,
+	}, &machineInventory{
+		reconciler:   r,
+		machineScope: machineScope,
+		clusterScope: clusterScope,
+		siteID:       siteID,
+	})
+	if err != nil || decision == nil {
+		return err
+	}
+
+	switch {
+	case decision.MachineID != "":
+		machineID := decision.MachineID
+		req.MachineId = &machineID
+		req.InstanceTypeId = nil
+	case decision.InstanceTypeID != "":
+		instanceTypeID := decision.InstanceTypeID
+		req.InstanceTypeId = &instanceTypeID
+	}
+
+	status := decision.Status
+	machineScope.NcxInfraMachine.Status.Placement = &status
+	if decision.Warning != nil {
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, decision.Warning.Reason,
+			"%s", decision.Warning.Message)
+	}
+	return nil
+}
+
+// machineInventory implements placement.Inventory for a machine being created.
+type machineInventory struct {
+	reconciler   *NcxInfraMachineReconciler
+	machineScope *scope.MachineScope
+	clusterScope *scope.ClusterScope
+	siteID       string
+}
+
+// TargetingEnabled reports false only when the tenant explicitly lacks the
+// targeted instance creation capability.
+func (i *machineInventory) TargetingEnabled(ctx context.Context) bool {
+	tenant, _, err := i.clusterScope.NcxInfraClient.GetCurrentTenant(ctx, i.clusterScope.OrgName)
+	return err != nil || tenant == nil || tenant.Capabilities == nil ||
+		tenant.Capabilities.TargetedInstanceCreation == nil || *tenant.Capabilities.TargetedInstanceCreation
+}
+
+// AvailableMachines lists the available machines of the instance type at the site.
+func (i *machineInventory) AvailableMachines(ctx context.Context, instanceTypeID string) ([]nico.Machine, error) {
+	listStart := time.Now()
+	machines, httpResp, err := i.clusterScope.NcxInfraClient.GetAllAvailableMachine(
+		ctx, i.clusterScope.OrgName, i.siteID, instanceTypeID)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetAllMachine")
+	recordAPIMetrics("GetAllMachine", listStart, apiErr)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return machines, nil
+}
+
+// Peers lists the other NcxInfraMachines of the cluster.
+func (i *machineInventory) Peers(ctx context.Context) ([]placement.Peer, error) {
+	machineList := &infrastructurev1.NcxInfraMachineList{}
+	if err := i.reconciler.List(ctx, machineList,
+		client.InNamespace(i.machineScope.Namespace()),
+		client.MatchingFields{ClusterNameField: i.machineScope.Cluster.Name},
+	); err != nil {
+		return nil, err
+	}
+
+	peers := make([]placement.Peer, 0, len(machineList.Items))
+	for j := range machineList.Items {
+		item := &machineList.Items[j]
+		if item.Name == i.machineScope.Name() {
+			continue
+		}
+		_, controlPlane := item.Labels[clusterv1.MachineControlPlaneLabel]
+		peers = append(peers, placement.Peer{
+			Name:         item.Name,
+			ControlPlane: controlPlane,
+			Labels:       item.Labels,
+			Placement:    item.Status.Placement,
+		})
+	}
+	return peers, nil
+}
+
+// Rack returns the rack of the compute tray of a machine.
+func (i *machineInventory) Rack(ctx context.Context, machineID string) (string, error) {
+	listStart := time.Now()
+	trays, httpResp, err := i.clusterScope.NcxInfraClient.GetAllTray(
+		ctx, i.clusterScope.OrgName, i.siteID, "compute", machineID)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetAllTray")
+	recordAPIMetrics("GetAllTray", listStart, apiErr)
+	if apiErr != nil {
+		return "", apiErr
+	}
+	if len(trays) == 0 || trays[0].RackId == nil {
+		return "", nil
+	}
+	return *trays[0].RackId, nil
+}
+
+// applyUserData completes the bootstrap data of the instance request with the
+// variables and hostname of the machine in site siteID, and its node labels,
+// network services, proxy, storage, GPU and kernel configuration.
+func (r *NcxInfraMachineReconciler) applyUserData(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+	siteID string,
+	req *nico.InstanceCreateRequest,
+) error {
+	// Substitute the variables of the machine and name the node
+	if err := r.applyHostname(machineScope, siteID, req); err != nil {
+		return err
+	}
+
+	// Label the node with the inventory of the target machine, when it is already known
+	if err := r.applyInventoryNodeLabels(ctx, machineScope, req); err != nil {
+		return err
+	}
+
+	// Configure DNS and NTP from the machine, subnet and cluster network services
+	r.applyNetworkServices(ctx, machineScope, clusterScope, req)
+
+	// Reach the site egress through the proxy of the cluster
+	r.applyProxy(ctx, machineScope, clusterScope, req)
+
+	// Lay out the data disks before the node is bootstrapped
+	if err := r.applyStorage(machineScope, req); err != nil {
+		return err
+	}
+
+	// Partition and configure the GPUs before the node is bootstrapped
+	if err := r.applyGPUConfig(machineScope, req); err != nil {
+		return err
+	}
+
+	// Boot the kernel with the tuning of the machine before the node is bootstrapped
+	return r.applyKernelArgs(machineScope, req)
+}
+
+// applyInventoryNodeLabels injects the inventory labels of the target machine
+// into the bootstrap data as node labels.
+func (r *NcxInfraMachineReconciler) applyInventoryNodeLabels(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	req *nico.InstanceCreateRequest,
+) error {
+	logger := log.FromContext(ctx)
+
+	inventory := machineScope.NcxInfraMachine.Spec.Inventory
+	if inventory == nil || inventory.NodeLabelPrefix == "" || req.MachineId == nil {
+		return nil
+	}
+
+	getStart := time.Now()
+	machine, httpResp, err := machineScope.NcxInfraClient.GetMachine(ctx, machineScope.OrgName, *req.MachineId)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetMachine")
+	recordAPIMetrics("GetMachine", getStart, apiErr)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	labels := inventoryLabels(machine, inventory.LabelKeys)
+	machineScope.NcxInfraMachine.Status.Inventory = labels
+	nodeLabels := r.validInventoryNodeLabels(machineScope, labels)
+	if len(nodeLabels) == 0 || req.UserData.Get() == nil {
+		return nil
+	}
+
+	userData, err := cloudinit.ApplyNodeLabels(*req.UserData.Get(), nodeLabels)
+	if err != nil {
+		// Node labels are informational, do not block provisioning on them
+		logger.Info("Skipping inventory node labels", "reason", err.Error())
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "InventoryNodeLabelsSkipped",
+			"Inventory node labels not injected: %v", err)
+		return nil
+	}
+	req.UserData = *nico.NewNullableString(&userData)
+	return nil
+}
+
+// applyNetworkServices injects the DNS and NTP servers of the machine into its
+// bootstrap data.
+func (r *NcxInfraMachineReconciler) applyNetworkServices(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+	req *nico.InstanceCreateRequest,
+) {
+	logger := log.FromContext(ctx)
+
+	network := machineScope.NcxInfraMachine.Spec.Network
+	network.SubnetName = machineScope.SubnetName()
+	services := machineNetworkServices(network, clusterScope.NcxInfraCluster.Spec)
+	if services.IsZero() || req.UserData.Get() == nil {
+		return
+	}
+	userData, err := cloudinit.ApplyNetworkServices(*req.UserData.Get(), services)
+	if err != nil {
+		logger.Info("Skipping network services", "reason", err.Error())
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "DHCPOptionsSkipped",
+			"DNS and NTP servers not applied: %v", err)
+		return
+	}
+	req.UserData = *nico.NewNullableString(&userData)
+}
+
+// applyProxy injects the proxy settings of the cluster into the bootstrap
+// data, or those of the credentials secret when the cluster propagates them.
+// Bootstrap data that is not cloud-config is left unchanged.
+func (r *NcxInfraMachineReconciler) applyProxy(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+	req *nico.InstanceCreateRequest,
+) {
+	logger := log.FromContext(ctx)
+
+	var proxy scope.Proxy
+	if spec := clusterScope.NcxInfraCluster.Spec.Proxy; spec != nil {
+		proxy = scope.Proxy{HTTPProxy: spec.HTTPProxy, HTTPSProxy: spec.HTTPSProxy, NoProxy: strings.Join(spec.NoProxy, ",")}
+	} else if clusterScope.NcxInfraCluster.Spec.Authentication.PropagateProxy {
+		proxy = clusterScope.Proxy
+	}
+	if proxy.IsZero() || req.UserData.Get() == nil {
+		return
+	}
+	userData, err := cloudinit.ApplyProxy(*req.UserData.Get(), cloudinit.Proxy{
+		HTTPProxy:  proxy.HTTPProxy,
+		HTTPSProxy: proxy.HTTPSProxy,
+		NoProxy:    proxy.NoProxy,
+	})
+	if err != nil {
+		logger.Info("Skipping proxy settings", "reason", err.Error())
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "ProxySkipped",
+			"Proxy settings not applied: %v", err)
+		return
+	}
+	req.UserData = *nico.NewNullableString(&userData)
+}
+
+// applyStorage injects the RAID arrays of the machine into its bootstrap data.
+// Unlike the other settings, a storage layout the workloads rely on cannot be
+// skipped, so bootstrap data that is not cloud-config fails the creation.
+func (r *NcxInfraMachineReconciler) applyStorage(machineScope *scope.MachineScope, req *nico.InstanceCreateRequest) error {
+	storage := machineScope.NcxInfraMachine.Spec.Storage
+	if storage == nil || len(storage.Arrays) == 0 || req.UserData.Get() == nil {
+		return nil
+	}
+	arrays := make([]cloudinit.RAIDArray, 0, len(storage.Arrays))
+	for _, array := range storage.Arrays {
+		arrays = append(arrays, cloudinit.RAIDArray{
+			Name:       array.Name,
+			Level:      array.Level,
+			Devices:    array.Devices,
+			Filesystem: array.Filesystem,
+			MountPath:  array.MountPath,
+		})
+	}
+	userData, err := cloudinit.ApplyStorage(*req.UserData.Get(), arrays)
+	if err != nil {
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "StorageNotApplied",
+			"Storage layout not applied: %v", err)
+		return fmt.Errorf("failed to apply the storage layout: %w", err)
 	}
+	req.UserData = *nico.NewNullableString(&userData)
+	return nil
+}
 
-	// Update instance state
-	if instance.Status != nil {
-		machineScope.SetInstanceState(string(*instance.Status))
+// gpuComputeModes maps the GPU compute modes to their nvidia-smi names.
+var gpuComputeModes = map[infrastructurev1.GPUComputeMode]string{
+	infrastructurev1.GPUComputeModeDefault:          "DEFAULT",
+	infrastructurev1.GPUComputeModeExclusiveProcess: "EXCLUSIVE_PROCESS",
+	infrastructurev1.GPUComputeModeProhibited:       "PROHIBITED",
+}
+
+// applyGPUConfig injects the GPU configuration of the machine into its
+// bootstrap data. Workloads are scheduled on the MIG instances the node
+// advertises, so bootstrap data that is not cloud-config fails the creation
+// rather than joining a node with unpartitioned GPUs.
+func (r *NcxInfraMachineReconciler) applyGPUConfig(machineScope *scope.MachineScope, req *nico.InstanceCreateRequest) error {
+	spec := machineScope.NcxInfraMachine.Spec.GPUConfig
+	if spec == nil || req.UserData.Get() == nil {
+		return nil
 	}
-	// Set serial console URL annotation if available
-	if instance.SerialConsoleUrl.Get() != nil && *instance.SerialConsoleUrl.Get() != "" {
-		if machineScope.NcxInfraMachine.Annotations == nil {
-			machineScope.NcxInfraMachine.Annotations = map[string]string{}
+	config := cloudinit.GPUConfig{
+		ComputeMode:     gpuComputeModes[spec.ComputeMode],
+		PersistenceMode: spec.PersistenceMode,
+		FabricManager:   spec.FabricManager,
+	}
+	if len(spec.MIG) > 0 {
+		config.MIG = make(map[int32][]string, len(spec.MIG))
+		for _, mig := range spec.MIG {
+			config.MIG[mig.GPU] = mig.Profiles
 		}
-		machineScope.NcxInfraMachine.Annotations["ncx-infra.io/serial-console-url"] = *instance.SerialConsoleUrl.Get()
 	}
-
-	if instance.MachineId.Get() != nil {
-		machineScope.SetMachineID(*instance.MachineId.Get())
+	userData, err := cloudinit.ApplyGPUConfig(*req.UserData.Get(), config)
+	if err != nil {
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "GPUConfigNotApplied",
+			"GPU configuration not applied: %v", err)
+		return fmt.Errorf("failed to apply the GPU configuration: %w", err)
 	}
+	req.UserData = *nico.NewNullableString(&userData)
+	return nil
+}
 
-	// Extract IP addresses from interfaces
-	addresses := []clusterv1.MachineAddress{}
-	for _, iface := range instance.Interfaces {
-		for _, ipAddr := range iface.IpAddresses {
-			addresses = append(addresses, clusterv1.MachineAddress{
-				Type:    clusterv1.MachineInternalIP,
-				Address: ipAddr,
-			})
-		}
-	}
+// hugePageSizes maps the hugepage sizes to their kernel argument values.
+var hugePageSizes = map[infrastructurev1.HugePageSize]string{
+	infrastructurev1.HugePageSize2Mi: "2M",
+	infrastructurev1.HugePageSize1Gi: "1G",
+}
 
-	if len(addresses) > 0 {
-		machineScope.SetAddresses(addresses)
-		conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
-			Type:   string(NetworkConfiguredCondition),
-			Status: metav1.ConditionTrue,
-			Reason: "NetworkReady",
-		})
+// machineKernelArgs returns the kernel arguments of a machine, followed by the
+// ones that implement its tuning.
+func machineKernelArgs(spec infrastructurev1.NcxInfraMachineSpec) []string {
+	args := slices.Clone(spec.KernelArgs)
+	if tuning := spec.Tuning; tuning != nil {
+		for i, pages := range tuning.HugePages {
+			if i == 0 {
+				args = append(args, "default_hugepagesz="+hugePageSizes[pages.Size])
+			}
+			args = append(args, "hugepagesz="+hugePageSizes[pages.Size], fmt.Sprintf("hugepages=%d", pages.Count))
+		}
+		if cpus := tuning.IsolatedCPUs; cpus != "" {
+			args = append(args, "isolcpus="+cpus, "nohz_full="+cpus, "rcu_nocbs="+cpus)
+		}
 	}
+	return args
+}
 
-	// Update health conditions from fault events (NEP-0007) if supported
-	if r.hasFaultManagement(ctx, clusterScope) {
-		r.updateHealthConditions(ctx, machineScope)
+// applyKernelArgs injects the kernel arguments of the machine into its
+// bootstrap data. Hugepages and isolated CPUs cannot be reserved once the
+// kubelet runs, so bootstrap data that is not cloud-config fails the creation.
+func (r *NcxInfraMachineReconciler) applyKernelArgs(machineScope *scope.MachineScope, req *nico.InstanceCreateRequest) error {
+	args := machineKernelArgs(machineScope.NcxInfraMachine.Spec)
+	if len(args) == 0 || req.UserData.Get() == nil {
+		return nil
 	}
-
-	// Check if instance is ready
-	if instance.Status != nil && string(*instance.Status) == "Ready" {
-		return r.handleInstanceReady(ctx, machineScope, clusterScope, instance, addresses)
+	userData, err := cloudinit.ApplyKernelArgs(*req.UserData.Get(), args)
+	if err != nil {
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "KernelArgsNotApplied",
+			"Kernel arguments not applied: %v", err)
+		return fmt.Errorf("failed to apply the kernel arguments: %w", err)
 	}
+	req.UserData = *nico.NewNullableString(&userData)
+	return nil
+}
 
-	// Instance is still provisioning or in error, requeue
-	instanceIDStr := ""
-	statusStr := ""
-	if instance.Id != nil {
-		instanceIDStr = *instance.Id
+// machineNetworkServices resolves the DNS servers, search domains and NTP
+// servers of a machine. Each list comes from the machine network if set, then
+// from the DHCP options of its subnets, then from the cluster network.
+func machineNetworkServices(
+	network infrastructurev1.NetworkSpec, clusterSpec infrastructurev1.NcxInfraClusterSpec,
+) cloudinit.NetworkServices {
+	var clusterServices infrastructurev1.NetworkServices
+	if clusterSpec.Network != nil {
+		clusterServices = *clusterSpec.Network
+	}
+	subnetServices := subnetNetworkServices(network, clusterSpec.Subnets)
+
+	first := func(lists ...[]string) []string {
+		for _, list := range lists {
+			if len(list) > 0 {
+				return list
+			}
+		}
+		return nil
 	}
-	if instance.Status != nil {
-		statusStr = string(*instance.Status)
+	return cloudinit.NetworkServices{
+		DNSServers:    first(network.DNSServers, subnetServices.DNSServers, clusterServices.DNSServers),
+		SearchDomains: first(network.SearchDomains, subnetServices.SearchDomains, clusterServices.SearchDomains),
+		NTPServers:    first(network.NTPServers, subnetServices.NTPServers, clusterServices.NTPServers),
 	}
+}
 
-	// Fetch and expose status history for debugging when in error or prolonged provisioning
-	if statusStr == "Error" || statusStr == "Provisioning" {
-		r.exposeStatusHistory(ctx, machineScope)
+// subnetNetworkServices merges the DHCP options of the subnets of a machine
+// network. The subnets of other clusters, attached through a clusterRef, are
+// not in the list of subnets of the cluster and do not contribute.
+func subnetNetworkServices(network infrastructurev1.NetworkSpec, subnets []infrastructurev1.SubnetSpec) cloudinit.NetworkServices {
+	names := []string{network.SubnetName}
+	for _, iface := range network.AdditionalInterfaces {
+		if iface.ClusterRef == nil {
+			names = append(names, iface.SubnetName)
+		}
 	}
 
-	// Set failure info for error state, enriched with fault events when available
-	if statusStr == "Error" {
-		errReason := capierrors.MachineStatusError("ProvisioningFailed")
-		errMsg := fmt.Sprintf("Instance %s is in Error state", instanceIDStr)
-
-		// Try to enrich with fault event details if fault management is supported
-		if r.hasFaultManagement(ctx, clusterScope) {
-			if healthMsg := r.getMachineHealthMessage(ctx, machineScope); healthMsg != "" {
-				errMsg = fmt.Sprintf("Instance %s is in Error state: %s", instanceIDStr, healthMsg)
-				errReason = capierrors.UpdateMachineError
+	var services cloudinit.NetworkServices
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		for _, subnet := range subnets {
+			if subnet.Name != name || subnet.DHCPOptions == nil {
+				continue
 			}
+			services.DNSServers = appendUnique(services.DNSServers, subnet.DHCPOptions.DNSServers...)
+			services.SearchDomains = appendUnique(services.SearchDomains, subnet.DHCPOptions.SearchDomains...)
+			services.NTPServers = appendUnique(services.NTPServers, subnet.DHCPOptions.NTPServers...)
 		}
+	}
+	return services
+}
 
-		setMachineFailure(machineScope.NcxInfraMachine, errReason, errMsg)
+// appendUnique appends the values not already in the list.
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
 	}
+	return list
+}
 
-	conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
-		Type:    string(InstanceProvisioningCondition),
-		Status:  metav1.ConditionTrue,
-		Reason:  "WaitingForReady",
-		Message: fmt.Sprintf("Instance %s is in state %s", instanceIDStr, statusStr),
-	})
+// updateInventory records the inventory labels of the physical machine in status.
+func (r *NcxInfraMachineReconciler) updateInventory(ctx context.Context, machineScope *scope.MachineScope) {
+	logger := log.FromContext(ctx)
 
-	logger.Info("Waiting for instance to be ready",
-		"instanceID", instanceIDStr,
-		"status", statusStr)
+	getStart := time.Now()
+	machine, httpResp, err := machineScope.NcxInfraClient.GetMachine(
+		ctx, machineScope.OrgName, machineScope.MachineID())
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetMachine")
+	recordAPIMetrics("GetMachine", getStart, apiErr)
+	if apiErr != nil {
+		logger.Info("Failed to get machine inventory, will retry",
+			"machineID", machineScope.MachineID(), "error", apiErr.Message)
+		return
+	}
 
-	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	labels := inventoryLabels(machine, machineScope.NcxInfraMachine.Spec.Inventory.LabelKeys)
+	machineScope.NcxInfraMachine.Status.Inventory = labels
+	if machineScope.NcxInfraMachine.Spec.Inventory.NodeLabelPrefix != "" {
+		// Reported once, the Node is labeled from status
+		r.validInventoryNodeLabels(machineScope, labels)
+	}
 }
 
-func (r *NcxInfraMachineReconciler) handleInstanceReady(
-	ctx context.Context,
-	machineScope *scope.MachineScope,
-	clusterScope *scope.ClusterScope,
-	instance *nico.Instance,
-	addresses []clusterv1.MachineAddress,
-) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
-
-	machineScope.SetReady(true)
-	ncxinframetrics.MachinesManaged.Inc()
+// validInventoryNodeLabels returns the node labels of the inventory labels, and
+// reports the labels skipped because their value is not a valid label value.
+func (r *NcxInfraMachineReconciler) validInventoryNodeLabels(
+	machineScope *scope.MachineScope, labels map[string]string,
+) map[string]string {
+	nodeLabels, invalid := inventoryNodeLabels(machineScope.NcxInfraMachine.Spec.Inventory.NodeLabelPrefix, labels)
+	if len(invalid) > 0 {
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "InventoryNodeLabelsSkipped",
+			"Inventory labels %s not set on the node: their value is not a valid label value",
+			strings.Join(invalid, ", "))
+	}
+	return nodeLabels
+}
 
-	// Record provisioning duration if instance has a creation timestamp
-	if instance.Created != nil {
-		duration := time.Since(*instance.Created).Seconds()
-		siteLabel := ""
-		if instance.SiteId != nil {
-			siteLabel = *instance.SiteId
+// inventoryNodeLabels returns the inventory labels under the node label prefix,
+// and the sorted keys of the labels whose value is not a valid label value.
+func inventoryNodeLabels(prefix string, labels map[string]string) (map[string]string, []string) {
+	var nodeLabels map[string]string
+	var invalid []string
+	for key, value := range labels {
+		if len(validation.IsValidLabelValue(value)) > 0 {
+			invalid = append(invalid, key)
+			continue
 		}
-		instanceTypeLabel := ""
-		if instance.InstanceTypeId != nil {
-			instanceTypeLabel = *instance.InstanceTypeId
+		if nodeLabels == nil {
+			nodeLabels = map[string]string{}
 		}
-		ncxinframetrics.InstanceProvisioningDuration.
-			WithLabelValues(siteLabel, instanceTypeLabel).Observe(duration)
+		nodeLabels[prefix+"/"+key] = value
 	}
+	slices.Sort(invalid)
+	return nodeLabels, invalid
+}
 
-	conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
-		Type:   string(InstanceProvisioningCondition),
-		Status: metav1.ConditionFalse,
-		Reason: "ProvisioningComplete",
-	})
-	conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
-		Type:   string(clusterv1.ReadyCondition),
-		Status: metav1.ConditionTrue,
-		Reason: "NcxInfraMachineReady",
-	})
-
-	// Apply post-creation updates if spec has changed
-	if updateReq, needsUpdate := r.buildUpdateRequest(machineScope, instance); needsUpdate {
-		logger.Info("Applying post-creation updates to instance",
-			"instanceID", machineScope.InstanceID())
-		_, _, updateErr := machineScope.NcxInfraClient.UpdateInstance(
-			ctx, machineScope.OrgName, machineScope.InstanceID(), updateReq)
-		if updateErr != nil {
-			logger.Error(updateErr, "failed to update instance",
-				"instanceID", machineScope.InstanceID())
-			r.recordEvent(machineScope.NcxInfraMachine,
-				corev1.EventTypeWarning, "UpdateFailed",
-				"Failed to update instance %s: %v",
-				machineScope.InstanceID(), updateErr)
-		} else {
-			r.recordEvent(machineScope.NcxInfraMachine,
-				corev1.EventTypeNormal, "InstanceUpdated",
-				"Successfully updated instance %s", machineScope.InstanceID())
+// inventoryLabels returns the selected machine labels.
+func inventoryLabels(machine *nico.Machine, keys []string) map[string]string {
+	if machine == nil {
+		return nil
+	}
+	var labels map[string]string
+	for _, key := range keys {
+		if value, ok := machine.Labels[key]; ok {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[key] = value
 		}
 	}
+	return labels
+}
 
-	// Set control plane endpoint if not already configured.
-	cpEndpoint := clusterScope.NcxInfraCluster.Spec.ControlPlaneEndpoint
-	if machineScope.IsControlPlane() && (cpEndpoint == nil || cpEndpoint.Host == "") {
-		if len(addresses) > 0 {
-			port := int32(6443)
-			if cpEndpoint != nil && cpEndpoint.Port != 0 {
-				port = cpEndpoint.Port
-			}
-			clusterScope.NcxInfraCluster.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{
-				Host: addresses[0].Address,
-				Port: port,
+// referencedClusters fetches the NcxInfraClusters referenced by the additional
+// interfaces of the machine, by name. The lookup is limited to the namespace of
+// the machine, so a machine cannot attach to the networks of another tenant.
+func (r *NcxInfraMachineReconciler) referencedClusters(
+	ctx context.Context, machineScope *scope.MachineScope,
+) (map[string]*infrastructurev1.NcxInfraCluster, error) {
+	var referenced map[string]*infrastructurev1.NcxInfraCluster
+	for _, iface := range machineScope.NcxInfraMachine.Spec.Network.AdditionalInterfaces {
+		if iface.ClusterRef == nil || referenced[iface.ClusterRef.Name] != nil {
+			continue
+		}
+		ncxInfraCluster := &infrastructurev1.NcxInfraCluster{}
+		key := client.ObjectKey{Namespace: machineScope.NcxInfraMachine.Namespace, Name: iface.ClusterRef.Name}
+		if err := r.Get(ctx, key, ncxInfraCluster); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("%w: NcxInfraCluster %s not found", errInvalidClusterRef, key.Name)
 			}
-			logger.Info("Updated control plane endpoint",
-				"host", addresses[0].Address, "port", port)
+			return nil, fmt.Errorf("failed to get NcxInfraCluster %s: %w", key.Name, err)
+		}
+		if err := validateClusterRef(machineScope.NcxInfraCluster, ncxInfraCluster); err != nil {
+			return nil, err
+		}
+		if referenced == nil {
+			referenced = map[string]*infrastructurev1.NcxInfraCluster{}
 		}
+		referenced[key.Name] = ncxInfraCluster
 	}
+	return referenced, nil
+}
 
-	instanceIDStr := ""
-	if instance.Id != nil {
-		instanceIDStr = *instance.Id
+// validateClusterRef checks that a referenced cluster can share its networks
+// with the machines of a cluster: both must belong to the same tenant and site,
+// and the referenced cluster must not be deleted.
+func validateClusterRef(ncxInfraCluster, referenced *infrastructurev1.NcxInfraCluster) error {
+	switch {
+	case referenced.Spec.TenantID != ncxInfraCluster.Spec.TenantID:
+		return fmt.Errorf("%w: NcxInfraCluster %s belongs to tenant %s, not %s", errInvalidClusterRef,
+			referenced.Name, referenced.Spec.TenantID, ncxInfraCluster.Spec.TenantID)
+	case referenced.Spec.SiteRef != ncxInfraCluster.Spec.SiteRef:
+		return fmt.Errorf("%w: NcxInfraCluster %s is on another site", errInvalidClusterRef, referenced.Name)
+	case !referenced.DeletionTimestamp.IsZero():
+		return fmt.Errorf("%w: NcxInfraCluster %s is being deleted", errInvalidClusterRef, referenced.Name)
 	}
-	logger.Info("NcxInfraMachine is ready",
-		"instanceID", instanceIDStr, "status", string(*instance.Status))
-	r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeNormal, "InstanceReady",
-		"Instance %s is ready", instanceIDStr)
-	return ctrl.Result{}, nil
+	return nil
 }
 
-//nolint:unparam // ctrl.Result is part of the reconciler interface contract
-func (r *NcxInfraMachineReconciler) reconcileDelete(
-	ctx context.Context, machineScope *scope.MachineScope,
-) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
-	logger.Info("Deleting NcxInfraMachine")
-
-	// Delete instance if it exists
-	if machineScope.InstanceID() != "" {
-		logger.Info("Deleting NVIDIA Carbide instance", "instanceID", machineScope.InstanceID())
-
-		deleteStart := time.Now()
-		httpResp, err := machineScope.NcxInfraClient.DeleteInstance(ctx, machineScope.OrgName, machineScope.InstanceID())
-		delAPIErr := scope.ClassifyAPIError(httpResp, err, "DeleteInstance")
-		recordAPIMetrics("DeleteInstance", deleteStart, delAPIErr)
-		if apiErr := delAPIErr; apiErr != nil {
-			if apiErr.IsNotFound() {
-				logger.Info("Instance already deleted", "instanceID", machineScope.InstanceID())
-			} else if apiErr.IsTransient() {
-				logger.Info("Transient error deleting instance, will retry",
-					"instanceID", machineScope.InstanceID(), "error", apiErr.Message)
-				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-			} else {
-				logger.Error(apiErr, "failed to delete instance", "instanceID", machineScope.InstanceID())
-				return ctrl.Result{}, apiErr
+// missingNetworks returns the subnets and VPC prefixes referenced by the
+// machine network that are not available in the cluster status yet, or in the
+// status of the referenced cluster for the interfaces with a clusterRef.
+func missingNetworks(
+	network infrastructurev1.NetworkSpec, status infrastructurev1.NetworkStatus,
+	referenced map[string]*infrastructurev1.NcxInfraCluster,
+) []string {
+	var missing []string
+	check := func(subnetName, vpcPrefixName string, status infrastructurev1.NetworkStatus, suffix string) {
+		if vpcPrefixName != "" {
+			if status.VPCPrefixID(vpcPrefixName) == "" {
+				missing = append(missing, fmt.Sprintf("VPC prefix %s%s", vpcPrefixName, suffix))
 			}
+			return
+		}
+		if status.SubnetID(subnetName) == "" {
+			missing = append(missing, fmt.Sprintf("subnet %s%s", subnetName, suffix))
 		}
 	}
 
-	// Remove finalizer
-	controllerutil.RemoveFinalizer(machineScope.NcxInfraMachine, NcxInfraMachineFinalizer)
-
-	if machineScope.IsReady() {
-		ncxinframetrics.MachinesManaged.Dec()
+	check(network.SubnetName, network.VPCPrefixName, status, "")
+	for _, iface := range network.AdditionalInterfaces {
+		if iface.ClusterRef != nil {
+			check(iface.SubnetName, iface.VPCPrefixName, interfaceNetworkStatus(iface, status, referenced),
+				fmt.Sprintf(" of NcxInfraCluster %s", iface.ClusterRef.Name))
+			continue
+		}
+		check(iface.SubnetName, iface.VPCPrefixName, status, "")
 	}
-
-	logger.Info("Successfully deleted NcxInfraMachine")
-	return ctrl.Result{}, nil
+	return missing
 }
 
-// validateCapabilities checks site and tenant capabilities for advanced features.
-func (r *NcxInfraMachineReconciler) validateCapabilities(
-	ctx context.Context,
-	machineScope *scope.MachineScope,
-	clusterScope *scope.ClusterScope,
-) error {
-	spec := machineScope.NcxInfraMachine.Spec
-
-	if len(spec.NVLinkInterfaces) > 0 || len(spec.InfiniBandInterfaces) > 0 {
-		siteID, siteErr := clusterScope.SiteID(ctx)
-		if siteErr == nil {
-			site, _, siteErr := clusterScope.NcxInfraClient.GetSite(
-				ctx, clusterScope.OrgName, siteID)
-			if siteErr == nil && site != nil && site.Capabilities != nil {
-				if len(spec.NVLinkInterfaces) > 0 &&
-					site.Capabilities.NvLinkPartition != nil &&
-					!*site.Capabilities.NvLinkPartition {
-					return fmt.Errorf("site %s does not support NVLink partitioning", siteID)
-				}
-			}
-		}
+// interfaceNetworkStatus returns the network status of the cluster owning the
+// subnet or VPC prefix of an additional interface.
+func interfaceNetworkStatus(
+	iface infrastructurev1.NetworkInterface, status infrastructurev1.NetworkStatus,
+	referenced map[string]*infrastructurev1.NcxInfraCluster,
+) infrastructurev1.NetworkStatus {
+	if iface.ClusterRef == nil {
+		return status
 	}
+	if ncxInfraCluster := referenced[iface.ClusterRef.Name]; ncxInfraCluster != nil {
+		return ncxInfraCluster.Status.NetworkStatus
+	}
+	return infrastructurev1.NetworkStatus{}
+}
 
-	if spec.InstanceType.MachineID != "" {
-		tenant, _, tenantErr := clusterScope.NcxInfraClient.GetCurrentTenant(
-			ctx, clusterScope.OrgName)
-		if tenantErr == nil && tenant != nil && tenant.Capabilities != nil {
-			if tenant.Capabilities.TargetedInstanceCreation != nil &&
-				!*tenant.Capabilities.TargetedInstanceCreation {
-				return fmt.Errorf("tenant does not have targeted instance creation enabled; cannot use machineID")
-			}
+// setSubnetAvailable reflects the missing networks of the machine in the
+// SubnetAvailable condition and in the count of machines blocked on them.
+func (r *NcxInfraMachineReconciler) setSubnetAvailable(machineScope *scope.MachineScope, missing []string) {
+	machine := machineScope.NcxInfraMachine
+	blocked := conditions.IsFalse(machine, string(SubnetAvailableCondition))
+
+	if len(missing) > 0 {
+		msg := fmt.Sprintf("%s not found in cluster status", strings.Join(missing, ", "))
+		if !blocked {
+			ncxinframetrics.MachinesBlockedOnNetwork.Inc()
+			r.recordEvent(machine, corev1.EventTypeWarning, "SubnetNotAvailable", "%s", msg)
 		}
+		conditions.Set(machine, metav1.Condition{
+			Type:    string(SubnetAvailableCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "SubnetNotFound",
+			Message: msg,
+		})
+		return
 	}
 
-	return nil
+	if blocked {
+		ncxinframetrics.MachinesBlockedOnNetwork.Dec()
+	}
+	conditions.Set(machine, metav1.Condition{
+		Type:   string(SubnetAvailableCondition),
+		Status: metav1.ConditionTrue,
+		Reason: "SubnetAvailable",
+	})
 }
 
-// buildInterfaces constructs the network interface list from machine and cluster specs.
+// buildInterfaces constructs the network interface list from machine and cluster
+// specs, resolving the interfaces with a clusterRef from the referenced clusters.
 func (r *NcxInfraMachineReconciler) buildInterfaces(
 	machineScope *scope.MachineScope,
 	clusterScope *scope.ClusterScope,
+	referenced map[string]*infrastructurev1.NcxInfraCluster,
 ) ([]nico.InterfaceCreateRequest, error) {
 	var interfaces []nico.InterfaceCreateRequest
 
@@ -713,11 +2663,11 @@ func (r *NcxInfraMachineReconciler) buildInterfaces(
 	}
 
 	// Additional interfaces
-	netStatus := clusterScope.NcxInfraCluster.Status.NetworkStatus
 	for _, iface := range machineScope.NcxInfraMachine.Spec.Network.AdditionalInterfaces {
+		netStatus := interfaceNetworkStatus(iface, clusterScope.NcxInfraCluster.Status.NetworkStatus, referenced)
 		if iface.VPCPrefixName != "" {
-			prefixID, ok := netStatus.VPCPrefixIDs[iface.VPCPrefixName]
-			if !ok {
+			prefixID := netStatus.VPCPrefixID(iface.VPCPrefixName)
+			if prefixID == "" {
 				return nil, fmt.Errorf("VPC prefix %s not found in cluster status", iface.VPCPrefixName)
 			}
 			ifReq := nico.InterfaceCreateRequest{
@@ -729,10 +2679,14 @@ func (r *NcxInfraMachineReconciler) buildInterfaces(
 			}
 			interfaces = append(interfaces, ifReq)
 		} else {
-			subnetID, ok := netStatus.SubnetIDs[iface.SubnetName]
-			if !ok {
+			subnetID := netStatus.SubnetID(iface.SubnetName)
+			if subnetID == "" {
 				return nil, fmt.Errorf("subnet %s not found in cluster status", iface.SubnetName)
 			}
+			if iface.SRIOV != nil {
+				interfaces = append(interfaces, sriovInterfaces(subnetID, iface.SRIOV)...)
+				continue
+			}
 			interfaces = append(interfaces, nico.InterfaceCreateRequest{
 				SubnetId:   &subnetID,
 				IsPhysical: &iface.IsPhysical,
@@ -743,6 +2697,17 @@ func (r *NcxInfraMachineReconciler) buildInterfaces(
 	return interfaces, nil
 }
 
+// instanceSSHKeyGroups returns the SSH key groups of the machine, followed by
+// the break-glass SSH key group of its cluster.
+func instanceSSHKeyGroups(machineScope *scope.MachineScope) []string {
+	sshKeyGroups := machineScope.NcxInfraMachine.Spec.SSHKeyGroups
+	if groupID := breakGlassSSHKeyGroupID(machineScope.NcxInfraCluster); groupID != "" &&
+		!slices.Contains(sshKeyGroups, groupID) {
+		sshKeyGroups = append(slices.Clone(sshKeyGroups), groupID)
+	}
+	return sshKeyGroups
+}
+
 // applyOptionalInstanceFields sets optional fields on the InstanceCreateRequest from the machine spec.
 //
 //nolint:gocyclo // field-mapping function, each branch is simple
@@ -752,11 +2717,8 @@ func (r *NcxInfraMachineReconciler) applyOptionalInstanceFields(
 ) {
 	spec := machineScope.NcxInfraMachine.Spec
 
-	if len(spec.SSHKeyGroups) > 0 {
-		req.SshKeyGroupIds = spec.SSHKeyGroups
-	}
-	if len(spec.Labels) > 0 {
-		req.Labels = spec.Labels
+	if sshKeyGroups := instanceSSHKeyGroups(machineScope); len(sshKeyGroups) > 0 {
+		req.SshKeyGroupIds = sshKeyGroups
 	}
 	if spec.InstanceType.ID != "" {
 		req.InstanceTypeId = &spec.InstanceType.ID
@@ -1054,15 +3016,14 @@ func (r *NcxInfraMachineReconciler) exposeStatusHistory(
 // buildUpdateRequest compares the desired spec with the current instance and returns
 // an InstanceUpdateRequest if any mutable fields have changed.
 func (r *NcxInfraMachineReconciler) buildUpdateRequest(
-	machineScope *scope.MachineScope, instance *nico.Instance,
+	machineScope *scope.MachineScope, clusterScope *scope.ClusterScope, instance *nico.Instance,
 ) (nico.InstanceUpdateRequest, bool) {
 	updateReq := nico.InstanceUpdateRequest{}
 	needsUpdate := false
 
 	// Check SSH key groups
-	if len(machineScope.NcxInfraMachine.Spec.SSHKeyGroups) > 0 {
+	if desiredSSHKeys := instanceSSHKeyGroups(machineScope); len(desiredSSHKeys) > 0 {
 		currentSSHKeys := instance.SshKeyGroupIds
-		desiredSSHKeys := machineScope.NcxInfraMachine.Spec.SSHKeyGroups
 		if !stringSlicesEqual(currentSSHKeys, desiredSSHKeys) {
 			updateReq.SshKeyGroupIds = desiredSSHKeys
 			needsUpdate = true
@@ -1070,9 +3031,9 @@ func (r *NcxInfraMachineReconciler) buildUpdateRequest(
 	}
 
 	// Check labels
-	if len(machineScope.NcxInfraMachine.Spec.Labels) > 0 {
-		if !mapsEqual(instance.Labels, machineScope.NcxInfraMachine.Spec.Labels) {
-			updateReq.Labels = machineScope.NcxInfraMachine.Spec.Labels
+	if labels := instanceLabels(machineScope, clusterScope); len(labels) > 0 {
+		if !mapsEqual(instance.Labels, labels) {
+			updateReq.Labels = labels
 			needsUpdate = true
 		}
 	}
@@ -1096,6 +3057,51 @@ func (r *NcxInfraMachineReconciler) buildUpdateRequest(
 	return updateReq, needsUpdate
 }
 
+// instanceLabels merges the labels of the NVIDIA Carbide instance from the
+// cluster defaults and the machine spec, the machine spec taking precedence.
+// The security requirement and VLAN labels always apply.
+func instanceLabels(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) map[string]string {
+	defaults := clusterScope.NcxInfraCluster.Spec.InstanceLabels
+	labels := machineScope.NcxInfraMachine.Spec.Labels
+	security := securityLabels(machineScope.NcxInfraMachine.Spec.Security)
+	vlans := vlanLabels(machineScope.NcxInfraMachine.Spec.Network)
+	if len(defaults) == 0 && len(security) == 0 && len(vlans) == 0 {
+		return labels
+	}
+	merged := make(map[string]string, len(defaults)+len(labels)+len(security)+len(vlans))
+	maps.Copy(merged, defaults)
+	maps.Copy(merged, labels)
+	maps.Copy(merged, security)
+	maps.Copy(merged, vlans)
+	return merged
+}
+
+// vlanLabels returns the instance labels passing the VLANs of the additional
+// interfaces to NVIDIA Carbide.
+func vlanLabels(network infrastructurev1.NetworkSpec) map[string]string {
+	labels := map[string]string{}
+	for i, iface := range network.AdditionalInterfaces {
+		vlan := iface.VLAN
+		if vlan == nil {
+			continue
+		}
+		if vlan.AccessVLAN != 0 {
+			labels[fmt.Sprintf(infrastructurev1.InterfaceAccessVLANLabelFormat, i)] = strconv.Itoa(int(vlan.AccessVLAN))
+		}
+		if len(vlan.TrunkVLANs) > 0 {
+			ids := make([]string, 0, len(vlan.TrunkVLANs))
+			for _, id := range vlan.TrunkVLANs {
+				ids = append(ids, strconv.Itoa(int(id)))
+			}
+			labels[fmt.Sprintf(infrastructurev1.InterfaceTrunkVLANsLabelFormat, i)] = strings.Join(ids, "_")
+		}
+		if vlan.NativeVLAN != 0 {
+			labels[fmt.Sprintf(infrastructurev1.InterfaceNativeVLANLabelFormat, i)] = strconv.Itoa(int(vlan.NativeVLAN))
+		}
+	}
+	return labels
+}
+
 func stringSlicesEqual(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -1159,6 +3165,47 @@ func setMachineFailure(machine *infrastructurev1.NcxInfraMachine, reason capierr
 	machine.Status.FailureMessage = &message
 }
 
+// ncxInfraClusterToNcxInfraMachines maps a NcxInfraCluster to the
+// NcxInfraMachines of its Cluster.
+func (r *NcxInfraMachineReconciler) ncxInfraClusterToNcxInfraMachines(
+	ctx context.Context, obj client.Object,
+) []ctrl.Request {
+	var clusterName string
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "Cluster" && strings.HasPrefix(ref.APIVersion, clusterv1.GroupVersion.Group+"/") {
+			clusterName = ref.Name
+		}
+	}
+	if clusterName == "" {
+		return nil
+	}
+
+	machines := &infrastructurev1.NcxInfraMachineList{}
+	if err := r.List(ctx, machines, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{ClusterNameField: clusterName}); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list NcxInfraMachines", "cluster", clusterName)
+		return nil
+	}
+	requests := make([]ctrl.Request, 0, len(machines.Items))
+	for _, machine := range machines.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&machine)})
+	}
+	return requests
+}
+
+// instanceLabelsChanged filters the NcxInfraCluster events that change the
+// labels of the instances of its machines.
+var instanceLabelsChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldCluster, okOld := e.ObjectOld.(*infrastructurev1.NcxInfraCluster)
+		newCluster, okNew := e.ObjectNew.(*infrastructurev1.NcxInfraCluster)
+		if !okOld || !okNew {
+			return false
+		}
+		return !mapsEqual(oldCluster.Spec.InstanceLabels, newCluster.Spec.InstanceLabels)
+	},
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *NcxInfraMachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -1171,8 +3218,13 @@ func (r *NcxInfraMachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				),
 			),
 		).
+		Watches(
+			&infrastructurev1.NcxInfraCluster{},
+			handler.EnqueueRequestsFromMapFunc(r.ncxInfraClusterToNcxInfraMachines),
+			builder.WithPredicates(instanceLabelsChanged),
+		).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(
-			mgr.GetScheme(), ctrl.Log.WithName("ncxinframachine"), "")).
+			mgr.GetScheme(), ctrl.Log.WithName("ncxinframachine"), r.WatchFilterValue)).
 		Named("ncxinframachine").
 		Complete(r)
 }
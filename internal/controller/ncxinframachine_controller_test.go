@@ -18,8 +18,10 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
@@ -27,13 +29,20 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/cluster-api/api/core/v1beta2/index"
+	"sigs.k8s.io/cluster-api/controllers/clustercache"
+	capierrors "sigs.k8s.io/cluster-api/errors" //nolint:staticcheck // required for CAPI contract FailureReason types
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
 	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
 	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/placement"
 	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
 )
 
@@ -125,9 +134,9 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			},
 			Status: infrastructurev1.NcxInfraClusterStatus{
 				Ready: true,
-				VPCID: vpcID,
 				NetworkStatus: infrastructurev1.NetworkStatus{
-					SubnetIDs: map[string]string{"control-plane": subnetID},
+					VPC:     &infrastructurev1.NetworkResourceStatus{ID: vpcID},
+					Subnets: []infrastructurev1.NetworkResourceStatus{{Name: "control-plane", ID: subnetID}},
 				},
 			},
 		}
@@ -178,6 +187,123 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 		}
 	})
 
+	Context("When the credentials point to another org", func() {
+		It("should not look the instance up in the other org", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				GetInstanceFunc: func(ctx context.Context, org, id string) (*nico.Instance, *http.Response, error) {
+					Fail("instance looked up with the credentials of another org")
+					return nil, nil, nil
+				},
+			}
+
+			nvidiaCarbideCluster.Status.OrgName = "previous-org"
+			nvidiaCarbideMachine.Finalizers = []string{NcxInfraMachineFinalizer}
+			nvidiaCarbideMachine.Status.InstanceID = "instance-uuid"
+
+			scheme := newTestScheme()
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraMachine{}, &infrastructurev1.NcxInfraCluster{}).
+				Build()
+
+			reconciler := &NcxInfraMachineReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			updatedMachine := &infrastructurev1.NcxInfraMachine{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			Expect(conditions.IsFalse(updatedMachine, string(CredentialsTargetCondition))).To(BeTrue())
+			Expect(updatedMachine.Status.FailureReason).To(BeNil())
+		})
+	})
+
+	Context("When the cluster is in dry-run mode", func() {
+		It("should report the instance to create without creating it", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				CreateInstanceFunc: func(ctx context.Context, org string, req nico.InstanceCreateRequest) (*nico.Instance, *http.Response, error) {
+					Fail("instance created in dry-run mode")
+					return nil, nil, nil
+				},
+			}
+
+			// The cluster is not ready, its resources are only planned too
+			nvidiaCarbideCluster.Annotations = map[string]string{infrastructurev1.DryRunAnnotation: ""}
+			nvidiaCarbideCluster.Status.Ready = false
+
+			scheme := newTestScheme()
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraMachine{}, &infrastructurev1.NcxInfraCluster{}).
+				Build()
+
+			reconciler := &NcxInfraMachineReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(dryRunRefreshInterval))
+
+			updatedMachine := &infrastructurev1.NcxInfraMachine{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			condition := conditions.Get(updatedMachine, string(DryRunCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Message).To(Equal(
+				"1 NVIDIA Carbide change(s) planned: create instance " + machineName + " of instance type instance-type-uuid"))
+			Expect(updatedMachine.Status.InstanceID).To(BeEmpty())
+		})
+	})
+
+	Context("When the cluster is protected from deletion", func() {
+		It("should keep the instance of a deleted machine", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				DeleteInstanceFunc: func(
+					ctx context.Context, org, id string, _ *nico.InstanceDeleteRequest,
+				) (*http.Response, error) {
+					Fail("instance of a protected cluster deleted")
+					return nil, nil
+				},
+			}
+
+			now := metav1.Now()
+			nvidiaCarbideCluster.Annotations = map[string]string{infrastructurev1.PreventDeletionAnnotation: ""}
+			nvidiaCarbideMachine.Finalizers = []string{NcxInfraMachineFinalizer}
+			nvidiaCarbideMachine.DeletionTimestamp = &now
+			nvidiaCarbideMachine.Status.InstanceID = uuid.New().String()
+
+			scheme := newTestScheme()
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraMachine{}, &infrastructurev1.NcxInfraCluster{}).
+				Build()
+
+			reconciler := &NcxInfraMachineReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(deletionProtectionRefreshInterval))
+
+			updatedMachine := &infrastructurev1.NcxInfraMachine{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			Expect(conditions.IsTrue(updatedMachine, string(DeletionBlockedCondition))).To(BeTrue())
+			Expect(updatedMachine.Finalizers).To(ContainElement(NcxInfraMachineFinalizer))
+		})
+	})
+
 	Context("When reconciling instance creation", func() {
 		It("should create instance and set providerID in status", func() {
 			instanceID := uuid.New().String()
@@ -188,7 +314,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 				CreateInstanceFunc: func(ctx context.Context, org string, req nico.InstanceCreateRequest) (*nico.Instance, *http.Response, error) {
 					Expect(org).To(Equal(orgName))
 					Expect(req.Name).To(Equal(machineName))
-					Expect(req.VpcId).To(Equal(nvidiaCarbideCluster.Status.VPCID))
+					Expect(req.VpcId).To(Equal(nvidiaCarbideCluster.Status.NetworkStatus.VPCID()))
 					Expect(*req.PhoneHomeEnabled).To(BeTrue())
 					return &nico.Instance{
 						Id:        &instanceID,
@@ -205,8 +331,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			nvidiaCarbideMachine.Finalizers = []string{NcxInfraMachineFinalizer}
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -235,6 +360,216 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			Expect(updatedMachine.Status.ProviderID).NotTo(BeNil())
 			Expect(*updatedMachine.Status.ProviderID).To(ContainSubstring("nico://"))
 			Expect(*updatedMachine.Status.ProviderID).To(ContainSubstring(instanceID))
+			Expect(updatedMachine.Status.InstanceOrigin).NotTo(BeNil())
+			Expect(updatedMachine.Status.InstanceOrigin.Kind).To(Equal("Instance"))
+			Expect(updatedMachine.Status.InstanceOrigin.Name).To(Equal(machineName))
+			Expect(updatedMachine.Status.InstanceOrigin.CreatedBy).To(Equal(infrastructurev1.ResourceCreator))
+		})
+
+		It("should create the instance in the tenant of the machine", func() {
+			instanceID := uuid.New().String()
+			machineTenantID := uuid.New().String()
+			status := nico.InstanceStatus("Provisioning")
+
+			mockClient := &testutil.MockNcxInfraClient{
+				CreateInstanceFunc: func(ctx context.Context, org string, req nico.InstanceCreateRequest) (*nico.Instance, *http.Response, error) {
+					Expect(req.TenantId).To(Equal(machineTenantID))
+					return &nico.Instance{Id: &instanceID, Name: testutil.Ptr(machineName), Status: &status},
+						testutil.MockHTTPResponse(201), nil
+				},
+				GetAllInstanceFunc: func(ctx context.Context, org string) ([]nico.Instance, *http.Response, error) {
+					return []nico.Instance{}, testutil.MockHTTPResponse(200), nil
+				},
+			}
+
+			nvidiaCarbideMachine.Finalizers = []string{NcxInfraMachineFinalizer}
+			nvidiaCarbideMachine.Spec.TenantID = machineTenantID
+
+			scheme := newTestScheme()
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
+				WithStatusSubresource(
+					&infrastructurev1.NcxInfraMachine{},
+					&infrastructurev1.NcxInfraCluster{},
+					&clusterv1.Machine{},
+				).
+				Build()
+
+			reconciler := &NcxInfraMachineReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			updatedMachine := &infrastructurev1.NcxInfraMachine{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			Expect(updatedMachine.Status.ProviderID).NotTo(BeNil())
+			Expect(*updatedMachine.Status.ProviderID).To(ContainSubstring("/" + machineTenantID + "/"))
+			Expect(*updatedMachine.Status.ProviderID).NotTo(ContainSubstring(tenantID))
+		})
+
+		It("should wait for a provisioning slot of the cluster", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				CreateInstanceFunc: func(ctx context.Context, org string, req nico.InstanceCreateRequest) (*nico.Instance, *http.Response, error) {
+					Fail("instance created beyond spec.provisioning.maxConcurrent")
+					return nil, nil, nil
+				},
+				GetAllInstanceFunc: func(ctx context.Context, org string) ([]nico.Instance, *http.Response, error) {
+					return []nico.Instance{}, testutil.MockHTTPResponse(200), nil
+				},
+			}
+
+			provisioning := &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "provisioning-machine",
+					Namespace: clusterNamespace,
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{
+					InstanceID:    uuid.New().String(),
+					InstanceState: string(nico.INSTANCESTATUS_PROVISIONING),
+				},
+			}
+			nvidiaCarbideCluster.Spec.Provisioning = &infrastructurev1.ProvisioningSpec{MaxConcurrent: 1}
+			nvidiaCarbideMachine.Finalizers = []string{NcxInfraMachineFinalizer}
+
+			scheme := newTestScheme()
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, provisioning,
+					credsSecret, bootstrapSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraMachine{}, &infrastructurev1.NcxInfraCluster{}).
+				Build()
+
+			reconciler := &NcxInfraMachineReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(provisioningSlotRetryInterval))
+
+			updatedMachine := &infrastructurev1.NcxInfraMachine{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			Expect(updatedMachine.Status.InstanceID).To(BeEmpty())
+			condition := conditions.Get(updatedMachine, string(InstanceProvisionedCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("WaitingForProvisioningSlot"))
+		})
+	})
+
+	Context("When the site has no available machine of the instance type", func() {
+		It("should wait for capacity instead of failing the creation", func() {
+			nvidiaCarbideMachine.Finalizers = []string{NcxInfraMachineFinalizer}
+
+			var available []nico.Machine
+			created := false
+			mockClient := &testutil.MockNcxInfraClient{
+				GetAllAvailableMachineFunc: func(
+					ctx context.Context, org, site, instanceType string,
+				) ([]nico.Machine, *http.Response, error) {
+					Expect(instanceType).To(Equal("instance-type-uuid"))
+					return available, testutil.MockHTTPResponse(200), nil
+				},
+				CreateInstanceFunc: func(ctx context.Context, org string, req nico.InstanceCreateRequest) (*nico.Instance, *http.Response, error) {
+					Expect(available).NotTo(BeEmpty(), "instance created without capacity")
+					created = true
+					return &nico.Instance{Id: testutil.Ptr(uuid.New().String())}, testutil.MockHTTPResponse(201), nil
+				},
+				GetAllInstanceFunc: func(ctx context.Context, org string) ([]nico.Instance, *http.Response, error) {
+					return []nico.Instance{}, testutil.MockHTTPResponse(200), nil
+				},
+			}
+
+			scheme := newTestScheme()
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraMachine{}, &infrastructurev1.NcxInfraCluster{}).
+				Build()
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &NcxInfraMachineReconciler{
+				Client:                k8sClient,
+				Scheme:                scheme,
+				Recorder:              recorder,
+				NcxInfraClient:        mockClient,
+				OrgName:               orgName,
+				CapacityRetryInterval: 2 * time.Minute,
+			}
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(2 * time.Minute))
+
+			updatedMachine := &infrastructurev1.NcxInfraMachine{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			condition := conditions.Get(updatedMachine, string(InstanceProvisionedCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("WaitingForCapacity"))
+			Expect(condition.Message).To(Equal(
+				"no available machine of instance type instance-type-uuid on site " + siteID))
+			Expect(updatedMachine.Status.FailureReason).To(BeNil())
+			Expect(recorder.Events).To(Receive(ContainSubstring("WaitingForCapacity")))
+
+			// The warning is not repeated while waiting
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+
+			available = []nico.Machine{{Id: testutil.Ptr("machine-uuid")}}
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeTrue())
+		})
+	})
+
+	Context("When the machine subnet does not exist yet", func() {
+		It("should wait with the SubnetAvailable condition instead of failing", func() {
+			nvidiaCarbideMachine.Finalizers = []string{NcxInfraMachineFinalizer}
+			nvidiaCarbideMachine.Spec.Network.AdditionalInterfaces = []infrastructurev1.NetworkInterface{
+				{SubnetName: "storage"},
+			}
+
+			mockClient := &testutil.MockNcxInfraClient{
+				CreateInstanceFunc: func(ctx context.Context, org string, req nico.InstanceCreateRequest) (*nico.Instance, *http.Response, error) {
+					Fail("instance created before its subnets exist")
+					return nil, nil, nil
+				},
+				GetAllInstanceFunc: func(ctx context.Context, org string) ([]nico.Instance, *http.Response, error) {
+					return []nico.Instance{}, testutil.MockHTTPResponse(200), nil
+				},
+			}
+
+			scheme := newTestScheme()
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
+				WithStatusSubresource(&infrastructurev1.NcxInfraMachine{}, &infrastructurev1.NcxInfraCluster{}).
+				Build()
+
+			reconciler := &NcxInfraMachineReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).NotTo(BeZero())
+
+			updatedMachine := &infrastructurev1.NcxInfraMachine{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			condition := conditions.Get(updatedMachine, string(SubnetAvailableCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("SubnetNotFound"))
+			Expect(condition.Message).To(Equal("subnet storage not found in cluster status"))
 		})
 	})
 
@@ -264,8 +599,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			}
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -289,6 +623,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			updatedMachine := &infrastructurev1.NcxInfraMachine{}
 			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
 			Expect(updatedMachine.Status.Ready).To(BeTrue())
+			Expect(updatedMachine.Status.Phase).To(Equal(infrastructurev1.MachinePhaseRunning))
 			Expect(updatedMachine.Status.Addresses).To(HaveLen(1))
 			Expect(updatedMachine.Status.Addresses[0].Address).To(Equal("10.0.1.10"))
 		})
@@ -315,8 +650,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			}
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -335,6 +669,86 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(result.RequeueAfter).NotTo(BeZero())
+
+			updatedMachine := &infrastructurev1.NcxInfraMachine{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			Expect(updatedMachine.Status.Phase).To(Equal(infrastructurev1.MachinePhaseProvisioning))
+		})
+
+		It("should report the provisioning sub-phase of the instance", func() {
+			instanceID := uuid.New().String()
+			status := nico.INSTANCESTATUS_PENDING
+
+			mockClient := &testutil.MockNcxInfraClient{
+				GetInstanceFunc: func(ctx context.Context, org, id string) (*nico.Instance, *http.Response, error) {
+					return &nico.Instance{Id: &instanceID, Status: status.Ptr()}, testutil.MockHTTPResponse(200), nil
+				},
+			}
+
+			nvidiaCarbideMachine.Finalizers = []string{NcxInfraMachineFinalizer}
+			nvidiaCarbideMachine.Status = infrastructurev1.NcxInfraMachineStatus{
+				InstanceID: instanceID,
+			}
+
+			scheme := newTestScheme()
+			k8sClient := newFakeClientBuilder(scheme).
+				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
+				WithStatusSubresource(
+					&infrastructurev1.NcxInfraMachine{},
+					&infrastructurev1.NcxInfraCluster{},
+					&clusterv1.Machine{},
+				).
+				Build()
+
+			reconciler := &NcxInfraMachineReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			// Waiting for a machine to be allocated
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+			updatedMachine := &infrastructurev1.NcxInfraMachine{}
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			provisioning := conditions.Get(updatedMachine, string(InstanceProvisioningCondition))
+			Expect(provisioning).NotTo(BeNil())
+			Expect(provisioning.Reason).To(Equal("WaitingForMachineAllocation"))
+			Expect(conditions.IsFalse(updatedMachine, string(InstanceAllocatedCondition))).To(BeTrue())
+			Expect(conditions.IsFalse(updatedMachine, string(InstanceImagedCondition))).To(BeTrue())
+			Expect(conditions.IsFalse(updatedMachine, string(InstanceBootedCondition))).To(BeTrue())
+			Expect(updatedMachine.Status.InstancePhase).To(Equal(infrastructurev1.InstancePhaseAllocating))
+
+			// Writing the operating system image
+			status = nico.INSTANCESTATUS_PROVISIONING
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			Expect(conditions.Get(updatedMachine, string(InstanceProvisioningCondition)).Reason).To(Equal("WritingImage"))
+			Expect(conditions.IsTrue(updatedMachine, string(InstanceAllocatedCondition))).To(BeTrue())
+			imaged := conditions.Get(updatedMachine, string(InstanceImagedCondition))
+			Expect(imaged.Status).To(Equal(metav1.ConditionFalse))
+			Expect(imaged.Reason).To(Equal("WritingImage"))
+			Expect(updatedMachine.Status.InstancePhase).To(Equal(infrastructurev1.InstancePhaseImaging))
+
+			// Configuring the network
+			status = nico.INSTANCESTATUS_CONFIGURING
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(15 * time.Second))
+
+			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
+			Expect(conditions.Get(updatedMachine, string(InstanceProvisioningCondition)).Reason).To(Equal("ConfiguringNetwork"))
+			Expect(conditions.IsTrue(updatedMachine, string(InstanceImagedCondition))).To(BeTrue())
+			booted := conditions.Get(updatedMachine, string(InstanceBootedCondition))
+			Expect(booted.Status).To(Equal(metav1.ConditionFalse))
+			Expect(booted.Reason).To(Equal("ConfiguringNetwork"))
+			Expect(updatedMachine.Status.InstancePhase).To(Equal(infrastructurev1.InstancePhaseBooting))
 		})
 	})
 
@@ -344,7 +758,9 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			deleteInstanceCalled := false
 
 			mockClient := &testutil.MockNcxInfraClient{
-				DeleteInstanceFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+				DeleteInstanceFunc: func(
+					ctx context.Context, org, id string, _ *nico.InstanceDeleteRequest,
+				) (*http.Response, error) {
 					deleteInstanceCalled = true
 					Expect(id).To(Equal(instanceID))
 					return testutil.MockHTTPResponse(200), nil
@@ -387,7 +803,9 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			instanceID := uuid.New().String()
 
 			mockClient := &testutil.MockNcxInfraClient{
-				DeleteInstanceFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+				DeleteInstanceFunc: func(
+					ctx context.Context, org, id string, _ *nico.InstanceDeleteRequest,
+				) (*http.Response, error) {
 					return testutil.MockHTTPResponse(404), fmt.Errorf("not found")
 				},
 			}
@@ -416,9 +834,587 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 				OrgName:        orgName,
 			}
 
-			result, err := reconciler.reconcileDelete(ctx, machineScope)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(result.Requeue).To(BeFalse()) //nolint:staticcheck // checking Requeue field
+			result, err := reconciler.reconcileDelete(ctx, machineScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse()) //nolint:staticcheck // checking Requeue field
+		})
+		It("should report a health issue when the deletion policy is Repair", func() {
+			instanceID := uuid.New().String()
+			var deleteReq *nico.InstanceDeleteRequest
+
+			mockClient := &testutil.MockNcxInfraClient{
+				DeleteInstanceFunc: func(
+					ctx context.Context, org, id string, req *nico.InstanceDeleteRequest,
+				) (*http.Response, error) {
+					deleteReq = req
+					return testutil.MockHTTPResponse(200), nil
+				},
+			}
+
+			machineScope := &scope.MachineScope{
+				Cluster:         cluster,
+				Machine:         machine,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+				NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       machineName,
+						Namespace:  clusterNamespace,
+						Finalizers: []string{NcxInfraMachineFinalizer},
+					},
+					Spec: infrastructurev1.NcxInfraMachineSpec{
+						Deletion: &infrastructurev1.DeletionSpec{
+							Policy: infrastructurev1.DeletionPolicyRepair,
+							HealthIssue: &infrastructurev1.MachineHealthIssueSpec{
+								Category: "Network",
+								Summary:  "DPU link flapping",
+							},
+						},
+					},
+					Status: infrastructurev1.NcxInfraMachineStatus{
+						InstanceID: instanceID,
+					},
+				},
+			}
+
+			reconciler := &NcxInfraMachineReconciler{
+				Scheme:         newTestScheme(),
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			_, err := reconciler.reconcileDelete(ctx, machineScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleteReq).NotTo(BeNil())
+			Expect(deleteReq.MachineHealthIssue.GetCategory()).To(Equal("Network"))
+			Expect(deleteReq.MachineHealthIssue.GetSummary()).To(Equal("DPU link flapping"))
+			Expect(machineScope.NcxInfraMachine.Finalizers).NotTo(ContainElement(NcxInfraMachineFinalizer))
+		})
+
+		It("should keep the finalizer until the machine is reset with Verified secure erase", func() {
+			instanceID := uuid.New().String()
+			machineStatus := nico.MACHINESTATUS_RESET
+
+			mockClient := &testutil.MockNcxInfraClient{
+				DeleteInstanceFunc: func(
+					ctx context.Context, org, id string, req *nico.InstanceDeleteRequest,
+				) (*http.Response, error) {
+					Expect(req).To(BeNil())
+					return testutil.MockHTTPResponse(200), nil
+				},
+				GetMachineFunc: func(ctx context.Context, org, id string) (*nico.Machine, *http.Response, error) {
+					return &nico.Machine{Id: &id, Status: &machineStatus}, testutil.MockHTTPResponse(200), nil
+				},
+			}
+
+			machineScope := &scope.MachineScope{
+				Cluster:         cluster,
+				Machine:         machine,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+				NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       machineName,
+						Namespace:  clusterNamespace,
+						Finalizers: []string{NcxInfraMachineFinalizer},
+					},
+					Spec: infrastructurev1.NcxInfraMachineSpec{
+						Deletion: &infrastructurev1.DeletionSpec{
+							Policy:      infrastructurev1.DeletionPolicyRelease,
+							SecureErase: infrastructurev1.SecureEraseVerified,
+						},
+					},
+					Status: infrastructurev1.NcxInfraMachineStatus{
+						InstanceID: instanceID,
+						MachineID:  "machine-1",
+					},
+				},
+			}
+
+			reconciler := &NcxInfraMachineReconciler{
+				Scheme:         newTestScheme(),
+				NcxInfraClient: mockClient,
+				OrgName:        orgName,
+			}
+
+			result, err := reconciler.reconcileDelete(ctx, machineScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).NotTo(BeZero())
+			Expect(machineScope.NcxInfraMachine.Finalizers).To(ContainElement(NcxInfraMachineFinalizer))
+
+			machineStatus = nico.MACHINESTATUS_READY
+			result, err = reconciler.reconcileDelete(ctx, machineScope)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(machineScope.NcxInfraMachine.Finalizers).NotTo(ContainElement(NcxInfraMachineFinalizer))
+		})
+	})
+
+	Context("When placing a control plane machine with chassis anti-affinity", func() {
+		var (
+			reconciler   *NcxInfraMachineReconciler
+			machineScope *scope.MachineScope
+			clusterScope *scope.ClusterScope
+		)
+
+		BeforeEach(func() {
+			machine.Labels[clusterv1.MachineControlPlaneLabel] = ""
+			nvidiaCarbideMachine.Labels = map[string]string{
+				clusterv1.ClusterNameLabel:         clusterName,
+				clusterv1.MachineControlPlaneLabel: "",
+			}
+			nvidiaCarbideMachine.Spec.Placement = &infrastructurev1.PlacementSpec{
+				ChassisAntiAffinity: infrastructurev1.AntiAffinityRequired,
+			}
+
+			sibling := &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-machine-1",
+					Namespace: clusterNamespace,
+					Labels: map[string]string{
+						clusterv1.ClusterNameLabel:         clusterName,
+						clusterv1.MachineControlPlaneLabel: "",
+					},
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{
+					Placement: &infrastructurev1.PlacementStatus{ChassisSerial: "chassis-a"},
+				},
+			}
+
+			k8sClient := newFakeClientBuilder(newTestScheme()).
+				WithObjects(nvidiaCarbideMachine, sibling).
+				WithStatusSubresource(&infrastructurev1.NcxInfraMachine{}).
+				Build()
+			reconciler = &NcxInfraMachineReconciler{Client: k8sClient, Scheme: newTestScheme()}
+		})
+
+		newScopes := func(available []nico.Machine) {
+			mockClient := &testutil.MockNcxInfraClient{
+				GetAllAvailableMachineFunc: func(
+					ctx context.Context, org, site, instanceType string,
+				) ([]nico.Machine, *http.Response, error) {
+					Expect(instanceType).To(Equal("instance-type-uuid"))
+					return available, testutil.MockHTTPResponse(200), nil
+				},
+			}
+			machineScope = &scope.MachineScope{
+				Cluster:         cluster,
+				Machine:         machine,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraMachine: nvidiaCarbideMachine,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+			}
+			clusterScope = &scope.ClusterScope{
+				Cluster:         cluster,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+			}
+		}
+
+		availableMachine := func(id, chassis string) nico.Machine {
+			return nico.Machine{
+				Id: testutil.Ptr(id),
+				Metadata: &nico.MachineMetadata{
+					DmiData: &nico.MachineDMIData{ChassisSerial: testutil.Ptr(chassis)},
+				},
+			}
+		}
+
+		It("should target a machine on a chassis not used by another control plane machine", func() {
+			newScopes([]nico.Machine{
+				availableMachine("machine-a", "chassis-a"),
+				availableMachine("machine-b", "chassis-b"),
+			})
+
+			req := nico.InstanceCreateRequest{InstanceTypeId: testutil.Ptr("instance-type-uuid")}
+			err := reconciler.applyPlacement(ctx, machineScope, clusterScope, siteID, &req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(req.MachineId).To(HaveValue(Equal("machine-b")))
+			Expect(req.InstanceTypeId).To(BeNil())
+			Expect(nvidiaCarbideMachine.Status.Placement.ChassisSerial).To(Equal("chassis-b"))
+			Expect(nvidiaCarbideMachine.Status.Placement.Decision).To(Equal("DistinctChassis"))
+		})
+
+		It("should defer creation when the anti-affinity is required and cannot be satisfied", func() {
+			newScopes([]nico.Machine{availableMachine("machine-a", "chassis-a")})
+
+			req := nico.InstanceCreateRequest{InstanceTypeId: testutil.Ptr("instance-type-uuid")}
+			err := reconciler.applyPlacement(ctx, machineScope, clusterScope, siteID, &req)
+			Expect(errors.Is(err, placement.ErrPending)).To(BeTrue())
+			Expect(req.MachineId).To(BeNil())
+		})
+
+		It("should fall back to a shared chassis when the anti-affinity is preferred", func() {
+			nvidiaCarbideMachine.Spec.Placement.ChassisAntiAffinity = infrastructurev1.AntiAffinityPreferred
+			newScopes([]nico.Machine{availableMachine("machine-a", "chassis-a")})
+
+			req := nico.InstanceCreateRequest{InstanceTypeId: testutil.Ptr("instance-type-uuid")}
+			err := reconciler.applyPlacement(ctx, machineScope, clusterScope, siteID, &req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(req.MachineId).To(HaveValue(Equal("machine-a")))
+			Expect(nvidiaCarbideMachine.Status.Placement.Decision).To(Equal("SharedChassis"))
+		})
+
+		It("should use the placement strategy set on the reconciler", func() {
+			newScopes(nil)
+			reconciler.PlacementStrategy = fixedPlacement{
+				InstanceTypeID: "other-instance-type",
+				Status:         infrastructurev1.PlacementStatus{Decision: "Binpack"},
+			}
+
+			req := nico.InstanceCreateRequest{InstanceTypeId: testutil.Ptr("instance-type-uuid")}
+			err := reconciler.applyPlacement(ctx, machineScope, clusterScope, siteID, &req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(req.MachineId).To(BeNil())
+			Expect(req.InstanceTypeId).To(HaveValue(Equal("other-instance-type")))
+			Expect(nvidiaCarbideMachine.Status.Placement.Decision).To(Equal("Binpack"))
+		})
+	})
+
+	Context("When correlating the workload cluster node", func() {
+		var machineScope *scope.MachineScope
+
+		BeforeEach(func() {
+			providerID := "nico://test-org/" + tenantID + "/" + siteID + "/" + uuid.New().String()
+			nvidiaCarbideMachine.Status.ProviderID = &providerID
+			machineScope = &scope.MachineScope{
+				Cluster:         cluster,
+				Machine:         machine,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraMachine: nvidiaCarbideMachine,
+				OrgName:         orgName,
+			}
+		})
+
+		var workloadClient client.Client
+
+		newReconciler := func(nodes ...client.Object) *NcxInfraMachineReconciler {
+			workloadClient = newFakeClientBuilder(newTestScheme()).
+				WithObjects(nodes...).
+				WithIndex(&corev1.Node{}, index.NodeProviderIDField, index.NodeByProviderID).
+				Build()
+			return &NcxInfraMachineReconciler{
+				Scheme:       newTestScheme(),
+				ClusterCache: clustercache.NewFakeClusterCache(workloadClient, client.ObjectKeyFromObject(cluster)),
+			}
+		}
+
+		It("should report a healthy node matching the provider ID", func() {
+			reconciler := newReconciler(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+				Spec:       corev1.NodeSpec{ProviderID: *nvidiaCarbideMachine.Status.ProviderID},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				}},
+			})
+
+			Expect(reconciler.reconcileNode(ctx, machineScope)).To(BeTrue())
+			Expect(nvidiaCarbideMachine.Status.NodeName).To(Equal("worker-0"))
+			Expect(conditions.IsTrue(nvidiaCarbideMachine, string(NodeHealthyCondition))).To(BeTrue())
+		})
+
+		It("should report a missing node while bootstrap has not completed", func() {
+			reconciler := newReconciler(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Spec:       corev1.NodeSpec{ProviderID: "nico://test-org/other"},
+			})
+
+			Expect(reconciler.reconcileNode(ctx, machineScope)).To(BeFalse())
+			Expect(nvidiaCarbideMachine.Status.NodeName).To(BeEmpty())
+			cond := conditions.Get(nvidiaCarbideMachine, string(NodeHealthyCondition))
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("NodeNotFound"))
+		})
+
+		It("should label the node with the machine topology", func() {
+			nvidiaCarbideMachine.Spec.NodeTopologyLabels = true
+			machineScope.SetMachineID("machine-1")
+			machineScope.NcxInfraClient = &testutil.MockNcxInfraClient{
+				GetMachineFunc: func(_ context.Context, _, _ string) (*nico.Machine, *http.Response, error) {
+					gpu := nico.MachineGPUInfo{Name: testutil.Ptr("NVIDIA H100 80GB HBM3")}
+					return &nico.Machine{
+						Metadata: &nico.MachineMetadata{Gpus: []nico.MachineGPUInfo{gpu, gpu}},
+					}, testutil.MockHTTPResponse(http.StatusOK), nil
+				},
+				GetAllTrayFunc: func(_ context.Context, _, _, _, _ string) ([]nico.Tray, *http.Response, error) {
+					return []nico.Tray{{RackId: testutil.Ptr("rack-12")}}, testutil.MockHTTPResponse(http.StatusOK), nil
+				},
+			}
+			instance := &nico.Instance{
+				SiteId:           testutil.Ptr(siteID),
+				NvLinkInterfaces: []nico.NVLinkInterface{{NvLinkDomainId: testutil.Ptr("domain-1")}},
+				InfinibandInterfaces: []nico.InfiniBandInterface{
+					{PartitionId: testutil.Ptr("ib-partition")},
+					{PartitionId: testutil.Ptr("ib-partition")},
+				},
+			}
+			reconciler := newReconciler(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Labels: map[string]string{"existing": "label"}},
+				Spec:       corev1.NodeSpec{ProviderID: *nvidiaCarbideMachine.Status.ProviderID},
+			})
+
+			reconciler.updateTopology(ctx, machineScope, instance)
+			reconciler.reconcileNode(ctx, machineScope)
+
+			node := &corev1.Node{}
+			Expect(workloadClient.Get(ctx, client.ObjectKey{Name: "worker-0"}, node)).To(Succeed())
+			Expect(node.Labels).To(Equal(map[string]string{
+				"existing":                                        "label",
+				infrastructurev1.TopologySiteLabel:                siteID,
+				infrastructurev1.TopologyRackLabel:                "rack-12",
+				infrastructurev1.TopologyNVLinkDomainLabel:        "domain-1",
+				infrastructurev1.TopologyGPUModelLabel:            "NVIDIA-H100-80GB-HBM3",
+				infrastructurev1.TopologyGPUCountLabel:            "2",
+				infrastructurev1.TopologyInfiniBandPartitionLabel: "ib-partition",
+				infrastructurev1.TopologyInfiniBandRailsLabel:     "2",
+			}))
+		})
+
+		It("should label the node with the inventory once it joins", func() {
+			nvidiaCarbideMachine.Spec.Inventory = &infrastructurev1.InventorySpec{
+				LabelKeys:       []string{"rack", "warranty"},
+				NodeLabelPrefix: "inventory.ncx-infra.io",
+			}
+			nvidiaCarbideMachine.Status.Inventory = map[string]string{"rack": "r12", "warranty": "ends 2027"}
+			reconciler := newReconciler(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+				Spec:       corev1.NodeSpec{ProviderID: *nvidiaCarbideMachine.Status.ProviderID},
+			})
+
+			reconciler.reconcileNode(ctx, machineScope)
+
+			node := &corev1.Node{}
+			Expect(workloadClient.Get(ctx, client.ObjectKey{Name: "worker-0"}, node)).To(Succeed())
+			Expect(node.Labels).To(Equal(map[string]string{"inventory.ncx-infra.io/rack": "r12"}))
+		})
+	})
+
+	Context("When copying the machine inventory", func() {
+		It("should record the inventory and label the node through the bootstrap data", func() {
+			nvidiaCarbideMachine.Spec.InstanceType = infrastructurev1.InstanceTypeSpec{MachineID: "machine-uuid"}
+			nvidiaCarbideMachine.Spec.Inventory = &infrastructurev1.InventorySpec{
+				LabelKeys:       []string{"rack", "warranty"},
+				NodeLabelPrefix: "inventory.ncx-infra.io",
+			}
+			mockClient := &testutil.MockNcxInfraClient{
+				GetMachineFunc: func(ctx context.Context, org, machineID string) (*nico.Machine, *http.Response, error) {
+					Expect(machineID).To(Equal("machine-uuid"))
+					return &nico.Machine{
+						Id:     testutil.Ptr(machineID),
+						Labels: map[string]string{"rack": "r12", "owner": "infra"},
+					}, testutil.MockHTTPResponse(200), nil
+				},
+			}
+			machineScope := &scope.MachineScope{
+				Cluster:         cluster,
+				Machine:         machine,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraMachine: nvidiaCarbideMachine,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+			}
+
+			userData := "#cloud-config\nwrite_files:\n- path: /run/kubeadm/kubeadm-join-config.yaml\n" +
+				"  content: |\n    apiVersion: kubeadm.k8s.io/v1beta4\n    kind: JoinConfiguration\n" +
+				"runcmd:\n- kubeadm join\n"
+			req := nico.InstanceCreateRequest{
+				MachineId: testutil.Ptr("machine-uuid"),
+				UserData:  *nico.NewNullableString(&userData),
+			}
+			reconciler := &NcxInfraMachineReconciler{Scheme: newTestScheme()}
+			Expect(reconciler.applyInventoryNodeLabels(ctx, machineScope, &req)).To(Succeed())
+
+			Expect(nvidiaCarbideMachine.Status.Inventory).To(Equal(map[string]string{"rack": "r12"}))
+			Expect(*req.UserData.Get()).To(ContainSubstring("name: node-labels"))
+			Expect(*req.UserData.Get()).To(ContainSubstring("value: inventory.ncx-infra.io/rack=r12"))
+			Expect(*req.UserData.Get()).To(ContainSubstring("kubeadm join"))
+		})
+
+		It("should skip the labels whose value is not a valid label value", func() {
+			nvidiaCarbideMachine.Spec.InstanceType = infrastructurev1.InstanceTypeSpec{MachineID: "machine-uuid"}
+			nvidiaCarbideMachine.Spec.Inventory = &infrastructurev1.InventorySpec{
+				LabelKeys:       []string{"rack", "warranty"},
+				NodeLabelPrefix: "inventory.ncx-infra.io",
+			}
+			machineScope := &scope.MachineScope{
+				Cluster:         cluster,
+				Machine:         machine,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraMachine: nvidiaCarbideMachine,
+				NcxInfraClient: &testutil.MockNcxInfraClient{
+					GetMachineFunc: func(ctx context.Context, org, machineID string) (*nico.Machine, *http.Response, error) {
+						return &nico.Machine{
+							Id:     testutil.Ptr(machineID),
+							Labels: map[string]string{"rack": "r12", "warranty": "ends 2027,rack=r99"},
+						}, testutil.MockHTTPResponse(200), nil
+					},
+				},
+				OrgName: orgName,
+			}
+
+			userData := "#cloud-config\nruncmd:\n- kubeadm join\n"
+			req := nico.InstanceCreateRequest{
+				MachineId: testutil.Ptr("machine-uuid"),
+				UserData:  *nico.NewNullableString(&userData),
+			}
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &NcxInfraMachineReconciler{Scheme: newTestScheme(), Recorder: recorder}
+			Expect(reconciler.applyInventoryNodeLabels(ctx, machineScope, &req)).To(Succeed())
+
+			// The value is still recorded in status, but not passed to the kubelet
+			Expect(nvidiaCarbideMachine.Status.Inventory).To(HaveKeyWithValue("warranty", "ends 2027,rack=r99"))
+			Expect(*req.UserData.Get()).To(ContainSubstring(`"inventory.ncx-infra.io/rack=r12"`))
+			Expect(*req.UserData.Get()).NotTo(ContainSubstring("r99"))
+			Expect(recorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("InventoryNodeLabelsSkipped"), ContainSubstring("warranty"))))
+		})
+	})
+
+	Context("When a power action is requested", func() {
+		var reconciler *NcxInfraMachineReconciler
+
+		BeforeEach(func() {
+			nvidiaCarbideMachine.Status.InstanceID = "instance-uuid"
+			nvidiaCarbideMachine.Status.MachineID = "machine-uuid"
+			reconciler = &NcxInfraMachineReconciler{Scheme: newTestScheme()}
+		})
+
+		newScopes := func(mockClient *testutil.MockNcxInfraClient) (*scope.MachineScope, *scope.ClusterScope) {
+			machineScope := &scope.MachineScope{
+				Cluster:         cluster,
+				Machine:         machine,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraMachine: nvidiaCarbideMachine,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+			}
+			clusterScope := &scope.ClusterScope{
+				Cluster:         cluster,
+				NcxInfraCluster: nvidiaCarbideCluster,
+				NcxInfraClient:  mockClient,
+				OrgName:         orgName,
+			}
+			return machineScope, clusterScope
+		}
+
+		It("should reboot the instance and remove the annotation", func() {
+			nvidiaCarbideMachine.Annotations = map[string]string{
+				infrastructurev1.PowerActionAnnotation: string(infrastructurev1.PowerActionReboot),
+			}
+			var updateReq nico.InstanceUpdateRequest
+			machineScope, clusterScope := newScopes(&testutil.MockNcxInfraClient{
+				UpdateInstanceFunc: func(
+					ctx context.Context, org, instanceID string, req nico.InstanceUpdateRequest,
+				) (*nico.Instance, *http.Response, error) {
+					updateReq = req
+					status := nico.INSTANCESTATUS_REBOOTING
+					return &nico.Instance{Id: testutil.Ptr(instanceID), Status: &status},
+						testutil.MockHTTPResponse(200), nil
+				},
+			})
+
+			Expect(reconciler.reconcilePowerAction(ctx, machineScope, clusterScope)).To(BeTrue())
+			Expect(updateReq.GetTriggerReboot()).To(BeTrue())
+			Expect(nvidiaCarbideMachine.Annotations).NotTo(HaveKey(infrastructurev1.PowerActionAnnotation))
+			Expect(nvidiaCarbideMachine.Status.InstanceState).To(Equal("Rebooting"))
+			Expect(nvidiaCarbideMachine.Status.LastPowerAction.Result).To(Equal("Submitted"))
+		})
+
+		It("should power off the compute tray hosting the machine", func() {
+			nvidiaCarbideMachine.Annotations = map[string]string{
+				infrastructurev1.PowerActionAnnotation: string(infrastructurev1.PowerActionPowerOff),
+			}
+			var powerReq nico.UpdatePowerStateRequest
+			machineScope, clusterScope := newScopes(&testutil.MockNcxInfraClient{
+				GetAllTrayFunc: func(
+					ctx context.Context, org, site, trayType, componentID string,
+				) ([]nico.Tray, *http.Response, error) {
+					Expect(trayType).To(Equal("compute"))
+					Expect(componentID).To(Equal("machine-uuid"))
+					return []nico.Tray{{Id: testutil.Ptr("tray-uuid")}}, testutil.MockHTTPResponse(200), nil
+				},
+				PowerControlTrayFunc: func(
+					ctx context.Context, org, trayID string, req nico.UpdatePowerStateRequest,
+				) (*nico.UpdatePowerStateResponse, *http.Response, error) {
+					Expect(trayID).To(Equal("tray-uuid"))
+					powerReq = req
+					return &nico.UpdatePowerStateResponse{}, testutil.MockHTTPResponse(200), nil
+				},
+			})
+
+			Expect(reconciler.reconcilePowerAction(ctx, machineScope, clusterScope)).To(BeTrue())
+			Expect(powerReq.State).To(Equal("off"))
+			Expect(powerReq.SiteId).To(Equal(siteID))
+			Expect(nvidiaCarbideMachine.Annotations).NotTo(HaveKey(infrastructurev1.PowerActionAnnotation))
+			Expect(conditions.IsTrue(nvidiaCarbideMachine, string(PowerActionCondition))).To(BeTrue())
+		})
+
+		It("should record a failure for an unsupported power action", func() {
+			nvidiaCarbideMachine.Annotations = map[string]string{
+				infrastructurev1.PowerActionAnnotation: "hibernate",
+			}
+			machineScope, clusterScope := newScopes(&testutil.MockNcxInfraClient{})
+
+			Expect(reconciler.reconcilePowerAction(ctx, machineScope, clusterScope)).To(BeFalse())
+			Expect(nvidiaCarbideMachine.Annotations).NotTo(HaveKey(infrastructurev1.PowerActionAnnotation))
+			Expect(nvidiaCarbideMachine.Status.LastPowerAction.Result).To(Equal("Failed"))
+			Expect(conditions.IsFalse(nvidiaCarbideMachine, string(PowerActionCondition))).To(BeTrue())
+		})
+
+		It("should report the machine powered off until its compute tray is powered on again", func() {
+			powerState := "Off"
+			trayCalls := 0
+			machineScope, clusterScope := newScopes(&testutil.MockNcxInfraClient{
+				GetAllTrayFunc: func(
+					ctx context.Context, org, site, trayType, componentID string,
+				) ([]nico.Tray, *http.Response, error) {
+					trayCalls++
+					return []nico.Tray{{Id: testutil.Ptr("tray-uuid"), PowerState: &powerState}},
+						testutil.MockHTTPResponse(200), nil
+				},
+			})
+			ready := nico.INSTANCESTATUS_READY
+			instance := &nico.Instance{Id: testutil.Ptr("instance-uuid"), Status: &ready}
+
+			// The tray is not inspected while the power-off completes
+			nvidiaCarbideMachine.Status.LastPowerAction = &infrastructurev1.PowerActionStatus{
+				Action: infrastructurev1.PowerActionPowerOff,
+				Result: powerActionSubmitted,
+				Time:   metav1.Now(),
+			}
+			Expect(reconciler.poweredOff(ctx, machineScope, clusterScope, instance)).To(BeTrue())
+			Expect(trayCalls).To(BeZero())
+
+			nvidiaCarbideMachine.Status.LastPowerAction.Time = metav1.NewTime(time.Now().Add(-powerOffSettleTime))
+			Expect(reconciler.poweredOff(ctx, machineScope, clusterScope, instance)).To(BeTrue())
+
+			powerState = "On"
+			Expect(reconciler.poweredOff(ctx, machineScope, clusterScope, instance)).To(BeFalse())
+			Expect(nvidiaCarbideMachine.Status.LastPowerAction.Result).To(Equal(powerActionSuperseded))
+			Expect(nvidiaCarbideMachine.Status.LastPowerAction.Message).To(Equal("compute tray is On"))
+
+			// The override is not reapplied once superseded
+			powerState = "Off"
+			Expect(reconciler.poweredOff(ctx, machineScope, clusterScope, instance)).To(BeFalse())
+		})
+
+		It("should stop reporting the machine powered off once the instance leaves the Ready state", func() {
+			machineScope, clusterScope := newScopes(&testutil.MockNcxInfraClient{})
+			nvidiaCarbideMachine.Status.LastPowerAction = &infrastructurev1.PowerActionStatus{
+				Action: infrastructurev1.PowerActionPowerOff,
+				Result: powerActionSubmitted,
+				Time:   metav1.Now(),
+			}
+			rebooting := nico.INSTANCESTATUS_REBOOTING
+			instance := &nico.Instance{Id: testutil.Ptr("instance-uuid"), Status: &rebooting}
+
+			Expect(reconciler.poweredOff(ctx, machineScope, clusterScope, instance)).To(BeFalse())
+			Expect(nvidiaCarbideMachine.Status.LastPowerAction.Result).To(Equal(powerActionSuperseded))
+			Expect(nvidiaCarbideMachine.Status.LastPowerAction.Message).To(Equal("instance is Rebooting"))
 		})
 	})
 
@@ -427,8 +1423,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			machine.Spec.Bootstrap.DataSecretName = nil
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -486,8 +1481,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			nvidiaCarbideMachine.Finalizers = []string{NcxInfraMachineFinalizer}
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -559,8 +1553,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			}
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -624,8 +1617,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			}
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -695,8 +1687,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			}
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -744,8 +1735,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			}
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -787,8 +1777,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			}
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -824,8 +1813,7 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 			}
 
 			scheme := newTestScheme()
-			k8sClient := fake.NewClientBuilder().
-				WithScheme(scheme).
+			k8sClient := newFakeClientBuilder(scheme).
 				WithObjects(cluster, machine, nvidiaCarbideCluster, nvidiaCarbideMachine, credsSecret, bootstrapSecret).
 				WithStatusSubresource(
 					&infrastructurev1.NcxInfraMachine{},
@@ -847,7 +1835,762 @@ var _ = Describe("NcxInfraMachine Controller", func() {
 
 			updatedMachine := &infrastructurev1.NcxInfraMachine{}
 			Expect(k8sClient.Get(ctx, namespacedName, updatedMachine)).To(Succeed())
-			Expect(updatedMachine.Status.FailureReason).NotTo(BeNil())
+			Expect(updatedMachine.Status.FailureReason).To(HaveValue(Equal(capierrors.MachineStatusError("InstanceNotFound"))))
+		})
+	})
+})
+
+var _ = Describe("machineAddresses", func() {
+	It("should report the addresses on a subnet with Public egress as ExternalIP", func() {
+		cluster := &infrastructurev1.NcxInfraCluster{
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				Subnets: []infrastructurev1.SubnetSpec{
+					{Name: "workers", CIDR: "10.0.1.0/24"},
+					{Name: "ingress", CIDR: "192.0.2.0/28", Egress: infrastructurev1.SubnetEgressPublic},
+				},
+			},
+			Status: infrastructurev1.NcxInfraClusterStatus{
+				NetworkStatus: infrastructurev1.NetworkStatus{
+					Subnets: []infrastructurev1.NetworkResourceStatus{
+						{Name: "ingress", ID: "ingress-uuid"},
+						{Name: "workers", ID: "workers-uuid"},
+					},
+				},
+			},
+		}
+		instance := &nico.Instance{
+			Interfaces: []nico.Interface{
+				{SubnetId: *nico.NewNullableString(testutil.Ptr("workers-uuid")), IpAddresses: []string{"10.0.1.10"}},
+				{SubnetId: *nico.NewNullableString(testutil.Ptr("ingress-uuid")), IpAddresses: []string{"192.0.2.5"}},
+				{VpcPrefixId: *nico.NewNullableString(testutil.Ptr("prefix-uuid")), IpAddresses: []string{"10.0.2.3"}},
+			},
+		}
+
+		Expect(machineAddresses(instance, cluster)).To(Equal([]clusterv1.MachineAddress{
+			{Type: clusterv1.MachineInternalIP, Address: "10.0.1.10"},
+			{Type: clusterv1.MachineExternalIP, Address: "192.0.2.5"},
+			{Type: clusterv1.MachineInternalIP, Address: "10.0.2.3"},
+		}))
+	})
+})
+
+var _ = Describe("controlPlaneEndpoint", func() {
+	addresses := []clusterv1.MachineAddress{{Type: clusterv1.MachineInternalIP, Address: "10.0.1.10"}}
+
+	It("should use the first address of the machine when the host is empty", func() {
+		cluster := &infrastructurev1.NcxInfraCluster{}
+		Expect(controlPlaneEndpoint(cluster, addresses)).To(Equal(
+			&clusterv1.APIEndpoint{Host: "10.0.1.10", Port: 6443}))
+
+		cluster.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{Port: 8443}
+		Expect(controlPlaneEndpoint(cluster, addresses)).To(Equal(
+			&clusterv1.APIEndpoint{Host: "10.0.1.10", Port: 8443}))
+		Expect(controlPlaneEndpoint(cluster, nil)).To(BeNil())
+	})
+
+	It("should keep an endpoint that is already set", func() {
+		cluster := &infrastructurev1.NcxInfraCluster{}
+		cluster.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{Host: "10.0.1.5", Port: 6443}
+		Expect(controlPlaneEndpoint(cluster, addresses)).To(BeNil())
+	})
+
+	It("should never set an externally managed endpoint", func() {
+		cluster := &infrastructurev1.NcxInfraCluster{}
+		cluster.Spec.ControlPlaneEndpointManagement = infrastructurev1.ControlPlaneEndpointExternal
+		cluster.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{Port: 6443}
+		Expect(controlPlaneEndpoint(cluster, addresses)).To(BeNil())
+	})
+
+	It("should not report the endpoint again", func() {
+		cluster := &infrastructurev1.NcxInfraCluster{}
+		cluster.Status.ControlPlaneEndpoint = &clusterv1.APIEndpoint{Host: "10.0.1.5", Port: 6443}
+		Expect(controlPlaneEndpoint(cluster, addresses)).To(BeNil())
+	})
+
+	DescribeTable("should only leave the spec alone when the management is Reported",
+		func(management infrastructurev1.ControlPlaneEndpointManagement, setInSpec bool) {
+			ctx := context.Background()
+			cluster := &infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				Spec: infrastructurev1.NcxInfraClusterSpec{
+					ControlPlaneEndpointManagement: management,
+				},
+			}
+			scheme := newTestScheme()
+			reconciler := &NcxInfraMachineReconciler{
+				Client: newFakeClientBuilder(scheme).
+					WithObjects(cluster).
+					WithStatusSubresource(&infrastructurev1.NcxInfraCluster{}).
+					Build(),
+				Scheme: scheme,
+			}
+
+			endpoint := &clusterv1.APIEndpoint{Host: "10.0.1.10", Port: 6443}
+			Expect(reconciler.setControlPlaneEndpoint(ctx, cluster, endpoint)).To(Succeed())
+
+			updated := &infrastructurev1.NcxInfraCluster{}
+			Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), updated)).To(Succeed())
+			Expect(updated.Status.ControlPlaneEndpoint).To(Equal(endpoint))
+			if setInSpec {
+				Expect(updated.Spec.ControlPlaneEndpoint).To(Equal(endpoint))
+			} else {
+				Expect(updated.Spec.ControlPlaneEndpoint).To(BeNil())
+			}
+		},
+		Entry("Reported", infrastructurev1.ControlPlaneEndpointReported, false),
+		Entry("unset", infrastructurev1.ControlPlaneEndpointManagement(""), true),
+		Entry("Auto", infrastructurev1.ControlPlaneEndpointAuto, true),
+	)
+})
+
+var _ = Describe("subnetNetworkServices", func() {
+	It("merges the DHCP options of the attached subnets, primary subnet first", func() {
+		subnets := []infrastructurev1.SubnetSpec{
+			{Name: "storage", DHCPOptions: &infrastructurev1.DHCPOptions{
+				DNSServers: []string{"10.0.2.53", "10.0.1.53"},
+				NTPServers: []string{"ntp.storage.example.com"},
+			}},
+			{Name: "workers", DHCPOptions: &infrastructurev1.DHCPOptions{
+				DNSServers:    []string{"10.0.1.53"},
+				SearchDomains: []string{"corp.example.com"},
+			}},
+			{Name: "unused", DHCPOptions: &infrastructurev1.DHCPOptions{
+				DNSServers: []string{"10.0.9.53"},
+			}},
+		}
+		network := infrastructurev1.NetworkSpec{
+			SubnetName:           "workers",
+			AdditionalInterfaces: []infrastructurev1.NetworkInterface{{SubnetName: "storage"}},
+		}
+
+		services := subnetNetworkServices(network, subnets)
+		Expect(services.DNSServers).To(Equal([]string{"10.0.1.53", "10.0.2.53"}))
+		Expect(services.SearchDomains).To(Equal([]string{"corp.example.com"}))
+		Expect(services.NTPServers).To(Equal([]string{"ntp.storage.example.com"}))
+	})
+
+	It("returns nothing for machines on a VPC prefix", func() {
+		services := subnetNetworkServices(infrastructurev1.NetworkSpec{VPCPrefixName: "physical"}, nil)
+		Expect(services.IsZero()).To(BeTrue())
+	})
+})
+
+var _ = Describe("machineNetworkServices", func() {
+	It("takes each list from the machine, then the subnets, then the cluster", func() {
+		clusterSpec := infrastructurev1.NcxInfraClusterSpec{
+			Network: &infrastructurev1.NetworkServices{
+				DNSServers:    []string{"10.0.0.53"},
+				SearchDomains: []string{"cluster.example.com"},
+				NTPServers:    []string{"ntp.cluster.example.com"},
+			},
+			Subnets: []infrastructurev1.SubnetSpec{
+				{Name: "workers", DHCPOptions: &infrastructurev1.DHCPOptions{
+					SearchDomains: []string{"workers.example.com"},
+				}},
+			},
+		}
+		network := infrastructurev1.NetworkSpec{
+			SubnetName:      "workers",
+			NetworkServices: infrastructurev1.NetworkServices{NTPServers: []string{"ntp.machine.example.com"}},
+		}
+
+		services := machineNetworkServices(network, clusterSpec)
+		Expect(services.DNSServers).To(Equal([]string{"10.0.0.53"}))
+		Expect(services.SearchDomains).To(Equal([]string{"workers.example.com"}))
+		Expect(services.NTPServers).To(Equal([]string{"ntp.machine.example.com"}))
+	})
+
+	It("returns nothing without any network services", func() {
+		services := machineNetworkServices(infrastructurev1.NetworkSpec{SubnetName: "workers"},
+			infrastructurev1.NcxInfraClusterSpec{})
+		Expect(services.IsZero()).To(BeTrue())
+	})
+})
+
+var _ = Describe("applyProxy", func() {
+	var (
+		machineScope *scope.MachineScope
+		clusterScope *scope.ClusterScope
+		req          *nico.InstanceCreateRequest
+	)
+
+	BeforeEach(func() {
+		machineScope = &scope.MachineScope{NcxInfraMachine: &infrastructurev1.NcxInfraMachine{}}
+		clusterScope = &scope.ClusterScope{
+			NcxInfraCluster: &infrastructurev1.NcxInfraCluster{},
+			Proxy:           scope.Proxy{HTTPSProxy: "http://proxy.corp.example.com:3128"},
+		}
+		req = &nico.InstanceCreateRequest{UserData: *nico.NewNullableString(testutil.Ptr("#cloud-config\nruncmd:\n- kubeadm join\n"))}
+	})
+
+	It("injects the proxy of the credentials when the cluster propagates it", func() {
+		clusterScope.NcxInfraCluster.Spec.Authentication.PropagateProxy = true
+
+		(&NcxInfraMachineReconciler{}).applyProxy(context.Background(), machineScope, clusterScope, req)
+		Expect(*req.UserData.Get()).To(ContainSubstring("HTTPS_PROXY=http://proxy.corp.example.com:3128"))
+	})
+
+	It("leaves the bootstrap data unchanged otherwise", func() {
+		(&NcxInfraMachineReconciler{}).applyProxy(context.Background(), machineScope, clusterScope, req)
+		Expect(*req.UserData.Get()).NotTo(ContainSubstring("proxy"))
+	})
+
+	It("injects the proxy of the cluster in priority", func() {
+		clusterScope.NcxInfraCluster.Spec.Authentication.PropagateProxy = true
+		clusterScope.NcxInfraCluster.Spec.Proxy = &infrastructurev1.ProxySpec{
+			HTTPSProxy: "http://egress.site.example.com:8080",
+			NoProxy:    []string{"10.0.0.0/16", ".svc"},
+		}
+
+		(&NcxInfraMachineReconciler{}).applyProxy(context.Background(), machineScope, clusterScope, req)
+		Expect(*req.UserData.Get()).To(ContainSubstring("HTTPS_PROXY=http://egress.site.example.com:8080"))
+		Expect(*req.UserData.Get()).To(ContainSubstring("NO_PROXY=10.0.0.0/16,.svc"))
+		Expect(*req.UserData.Get()).NotTo(ContainSubstring("proxy.corp.example.com"))
+	})
+})
+
+var _ = Describe("applyStorage", func() {
+	var (
+		machineScope *scope.MachineScope
+		recorder     *record.FakeRecorder
+		reconciler   *NcxInfraMachineReconciler
+	)
+
+	BeforeEach(func() {
+		machineScope = &scope.MachineScope{NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+			Spec: infrastructurev1.NcxInfraMachineSpec{Storage: &infrastructurev1.StorageSpec{
+				Arrays: []infrastructurev1.RAIDArraySpec{{
+					Name: "scratch", Level: 0, Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}, MountPath: "/mnt/scratch",
+				}},
+			}},
+		}}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &NcxInfraMachineReconciler{Recorder: recorder}
+	})
+
+	It("assembles the arrays before the bootstrap commands", func() {
+		req := &nico.InstanceCreateRequest{UserData: *nico.NewNullableString(testutil.Ptr("#cloud-config\nruncmd:\n- kubeadm join\n"))}
+		Expect(reconciler.applyStorage(machineScope, req)).To(Succeed())
+		Expect(*req.UserData.Get()).To(ContainSubstring("mdadm --create /dev/md/scratch"))
+		Expect(*req.UserData.Get()).To(ContainSubstring("LABEL=scratch"))
+	})
+
+	It("fails the creation when the bootstrap data is not cloud-config", func() {
+		req := &nico.InstanceCreateRequest{UserData: *nico.NewNullableString(testutil.Ptr(`{"ignition":{}}`))}
+		Expect(reconciler.applyStorage(machineScope, req)).To(MatchError(ContainSubstring("storage layout")))
+		Expect(*req.UserData.Get()).To(Equal(`{"ignition":{}}`))
+		Expect(recorder.Events).To(Receive(ContainSubstring("StorageNotApplied")))
+	})
+})
+
+var _ = Describe("applyGPUConfig", func() {
+	var (
+		machineScope *scope.MachineScope
+		recorder     *record.FakeRecorder
+		reconciler   *NcxInfraMachineReconciler
+	)
+
+	BeforeEach(func() {
+		machineScope = &scope.MachineScope{NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+			Spec: infrastructurev1.NcxInfraMachineSpec{GPUConfig: &infrastructurev1.GPUConfigSpec{
+				MIG:         []infrastructurev1.MIGSpec{{GPU: 0, Profiles: []string{"3g.40gb", "3g.40gb"}}},
+				ComputeMode: infrastructurev1.GPUComputeModeExclusiveProcess,
+			}},
+		}}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &NcxInfraMachineReconciler{Recorder: recorder}
+	})
+
+	It("partitions the GPUs before the bootstrap commands", func() {
+		req := &nico.InstanceCreateRequest{UserData: *nico.NewNullableString(testutil.Ptr("#cloud-config\nruncmd:\n- kubeadm join\n"))}
+		Expect(reconciler.applyGPUConfig(machineScope, req)).To(Succeed())
+		Expect(*req.UserData.Get()).To(ContainSubstring("nvidia-smi mig -i 0 -cgi 3g.40gb,3g.40gb -C"))
+		Expect(*req.UserData.Get()).To(ContainSubstring("nvidia-smi -c EXCLUSIVE_PROCESS"))
+	})
+
+	It("fails the creation when the bootstrap data is not cloud-config", func() {
+		req := &nico.InstanceCreateRequest{UserData: *nico.NewNullableString(testutil.Ptr(`{"ignition":{}}`))}
+		Expect(reconciler.applyGPUConfig(machineScope, req)).To(MatchError(ContainSubstring("GPU configuration")))
+		Expect(recorder.Events).To(Receive(ContainSubstring("GPUConfigNotApplied")))
+	})
+})
+
+var _ = Describe("applyKernelArgs", func() {
+	It("appends the tuning arguments to the kernel arguments", func() {
+		Expect(machineKernelArgs(infrastructurev1.NcxInfraMachineSpec{
+			KernelArgs: []string{"intel_iommu=on", "iommu=pt"},
+			Tuning: &infrastructurev1.TuningSpec{
+				HugePages: []infrastructurev1.HugePagesSpec{
+					{Size: infrastructurev1.HugePageSize1Gi, Count: 16},
+					{Size: infrastructurev1.HugePageSize2Mi, Count: 1024},
+				},
+				IsolatedCPUs: "2-31",
+			},
+		})).To(Equal([]string{
+			"intel_iommu=on", "iommu=pt",
+			"default_hugepagesz=1G", "hugepagesz=1G", "hugepages=16", "hugepagesz=2M", "hugepages=1024",
+			"isolcpus=2-31", "nohz_full=2-31", "rcu_nocbs=2-31",
+		}))
+	})
+
+	It("configures the kernel arguments in the bootstrap data", func() {
+		machineScope := &scope.MachineScope{NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+			Spec: infrastructurev1.NcxInfraMachineSpec{KernelArgs: []string{"iommu=pt"}},
+		}}
+		req := &nico.InstanceCreateRequest{UserData: *nico.NewNullableString(testutil.Ptr("#cloud-config\nruncmd:\n- kubeadm join\n"))}
+		Expect((&NcxInfraMachineReconciler{}).applyKernelArgs(machineScope, req)).To(Succeed())
+		Expect(*req.UserData.Get()).To(ContainSubstring("iommu=pt"))
+	})
+
+	It("fails the creation when the bootstrap data is not cloud-config", func() {
+		machineScope := &scope.MachineScope{NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+			Spec: infrastructurev1.NcxInfraMachineSpec{Tuning: &infrastructurev1.TuningSpec{IsolatedCPUs: "2-7"}},
+		}}
+		recorder := record.NewFakeRecorder(10)
+		req := &nico.InstanceCreateRequest{UserData: *nico.NewNullableString(testutil.Ptr(`{"ignition":{}}`))}
+		Expect((&NcxInfraMachineReconciler{Recorder: recorder}).applyKernelArgs(machineScope, req)).
+			To(MatchError(ContainSubstring("kernel arguments")))
+		Expect(recorder.Events).To(Receive(ContainSubstring("KernelArgsNotApplied")))
+	})
+})
+
+var _ = Describe("checkBootstrapDataAge", func() {
+	newMachineScope := func(age time.Duration) *scope.MachineScope {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "bootstrap",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+			Data: map[string][]byte{"value": []byte("#cloud-config")},
+		}
+		return &scope.MachineScope{
+			Client: newFakeClientBuilder(newTestScheme()).WithObjects(secret).Build(),
+			Machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{DataSecretName: testutil.Ptr("bootstrap")},
+				},
+			},
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{},
+		}
+	}
+
+	It("reports bootstrap data younger than the token TTL as fresh", func() {
+		machineScope := newMachineScope(5 * time.Minute)
+		reconciler := &NcxInfraMachineReconciler{BootstrapTokenTTL: 15 * time.Minute}
+
+		reconciler.checkBootstrapDataAge(context.Background(), machineScope, "#cloud-config", scope.BootstrapFormatCloudConfig)
+		Expect(conditions.IsTrue(machineScope.NcxInfraMachine, string(BootstrapDataFreshCondition))).To(BeTrue())
+	})
+
+	It("warns once about bootstrap data older than the token TTL", func() {
+		machineScope := newMachineScope(time.Hour)
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &NcxInfraMachineReconciler{Recorder: recorder, BootstrapTokenTTL: 15 * time.Minute}
+
+		reconciler.checkBootstrapDataAge(context.Background(), machineScope, "#cloud-config", scope.BootstrapFormatCloudConfig)
+		condition := conditions.Get(machineScope.NcxInfraMachine, string(BootstrapDataFreshCondition))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("BootstrapDataStale"))
+		Expect(condition.Message).To(ContainSubstring("older than the 15m0s bootstrap token TTL"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("BootstrapDataStale")))
+
+		reconciler.checkBootstrapDataAge(context.Background(), machineScope, "#cloud-config", scope.BootstrapFormatCloudConfig)
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("does not report the age without token TTL", func() {
+		machineScope := newMachineScope(time.Hour)
+
+		(&NcxInfraMachineReconciler{}).checkBootstrapDataAge(
+			context.Background(), machineScope, "#cloud-config", scope.BootstrapFormatCloudConfig)
+		Expect(conditions.Get(machineScope.NcxInfraMachine, string(BootstrapDataFreshCondition))).To(BeNil())
+	})
+})
+
+var _ = Describe("buildUpdateRequest", func() {
+	var (
+		machineScope *scope.MachineScope
+		clusterScope *scope.ClusterScope
+	)
+
+	BeforeEach(func() {
+		machineScope = &scope.MachineScope{NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+			Spec: infrastructurev1.NcxInfraMachineSpec{
+				Labels:       map[string]string{"team": "ml", "tier": "gpu"},
+				SSHKeyGroups: []string{"ssh-group"},
+			},
+		}}
+		clusterScope = &scope.ClusterScope{NcxInfraCluster: &infrastructurev1.NcxInfraCluster{
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				InstanceLabels: map[string]string{"env": "prod", "team": "platform"},
+			},
+		}}
+	})
+
+	It("coalesces the cluster defaults and machine labels into a single request", func() {
+		instance := &nico.Instance{Labels: map[string]string{"env": "dev"}}
+
+		updateReq, needsUpdate := (&NcxInfraMachineReconciler{}).buildUpdateRequest(machineScope, clusterScope, instance)
+		Expect(needsUpdate).To(BeTrue())
+		Expect(updateReq.Labels).To(Equal(map[string]string{"env": "prod", "team": "ml", "tier": "gpu"}))
+		Expect(updateReq.SshKeyGroupIds).To(Equal([]string{"ssh-group"}))
+	})
+
+	It("passes the VLANs of the additional interfaces as labels", func() {
+		machineScope.NcxInfraMachine.Spec.Network.AdditionalInterfaces = []infrastructurev1.NetworkInterface{
+			{SubnetName: "storage"},
+			{SubnetName: "tenant", VLAN: &infrastructurev1.VLANSpec{TrunkVLANs: []int32{100, 200}, NativeVLAN: 10}},
+			{SubnetName: "oob", VLAN: &infrastructurev1.VLANSpec{AccessVLAN: 300}},
+		}
+
+		Expect(instanceLabels(machineScope, clusterScope)).To(Equal(map[string]string{
+			"env": "prod", "team": "ml", "tier": "gpu",
+			"ncx-infra.io/interface-1-trunk-vlans": "100_200",
+			"ncx-infra.io/interface-1-native-vlan": "10",
+			"ncx-infra.io/interface-2-access-vlan": "300",
+		}))
+	})
+
+	It("does not update an instance that already has the merged labels", func() {
+		instance := &nico.Instance{
+			Labels:         map[string]string{"env": "prod", "team": "ml", "tier": "gpu"},
+			SshKeyGroupIds: []string{"ssh-group"},
+		}
+
+		_, needsUpdate := (&NcxInfraMachineReconciler{}).buildUpdateRequest(machineScope, clusterScope, instance)
+		Expect(needsUpdate).To(BeFalse())
+	})
+})
+
+var _ = Describe("missingNetworks", func() {
+	It("lists the subnets and VPC prefixes missing from the cluster status", func() {
+		network := infrastructurev1.NetworkSpec{
+			SubnetName: "workers",
+			AdditionalInterfaces: []infrastructurev1.NetworkInterface{
+				{SubnetName: "storage"},
+				{VPCPrefixName: "physical"},
+			},
+		}
+		status := infrastructurev1.NetworkStatus{
+			Subnets: []infrastructurev1.NetworkResourceStatus{{Name: "workers", ID: "subnet-uuid"}},
+		}
+
+		Expect(missingNetworks(network, status, nil)).To(Equal([]string{"subnet storage", "VPC prefix physical"}))
+
+		// A subnet that failed to be created is still missing
+		status.Subnets = append(status.Subnets, infrastructurev1.NetworkResourceStatus{
+			Name: "storage", State: infrastructurev1.NetworkResourceFailed, LastError: "prefix exhausted",
 		})
+		Expect(missingNetworks(network, status, nil)).To(Equal([]string{"subnet storage", "VPC prefix physical"}))
+
+		status.Subnets[1].ID = "storage-uuid"
+		status.VPCPrefixes = []infrastructurev1.NetworkResourceStatus{{Name: "physical", ID: "prefix-uuid"}}
+		Expect(missingNetworks(network, status, nil)).To(BeEmpty())
+	})
+
+	It("looks up the interfaces with a clusterRef in the referenced cluster", func() {
+		network := infrastructurev1.NetworkSpec{
+			SubnetName: "workers",
+			AdditionalInterfaces: []infrastructurev1.NetworkInterface{
+				{SubnetName: "storage", ClusterRef: &corev1.LocalObjectReference{Name: "storage-cluster"}},
+			},
+		}
+		status := infrastructurev1.NetworkStatus{
+			Subnets: []infrastructurev1.NetworkResourceStatus{
+				{Name: "workers", ID: "subnet-uuid"},
+				{Name: "storage", ID: "local-storage-uuid"},
+			},
+		}
+		referenced := map[string]*infrastructurev1.NcxInfraCluster{
+			"storage-cluster": {ObjectMeta: metav1.ObjectMeta{Name: "storage-cluster"}},
+		}
+
+		Expect(missingNetworks(network, status, referenced)).To(Equal([]string{
+			"subnet storage of NcxInfraCluster storage-cluster",
+		}))
+
+		referenced["storage-cluster"].Status.NetworkStatus.Subnets = []infrastructurev1.NetworkResourceStatus{
+			{Name: "storage", ID: "shared-storage-uuid"},
+		}
+		Expect(missingNetworks(network, status, referenced)).To(BeEmpty())
+		ifaceStatus := interfaceNetworkStatus(network.AdditionalInterfaces[0], status, referenced)
+		Expect(ifaceStatus.SubnetID("storage")).To(Equal("shared-storage-uuid"))
+	})
+})
+
+var _ = Describe("referencedClusters", func() {
+	var (
+		ncxInfraCluster *infrastructurev1.NcxInfraCluster
+		storageCluster  *infrastructurev1.NcxInfraCluster
+		machineScope    *scope.MachineScope
+	)
+
+	BeforeEach(func() {
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				SiteRef:  infrastructurev1.SiteReference{ID: "site-uuid"},
+				TenantID: "tenant-uuid",
+			},
+		}
+		storageCluster = ncxInfraCluster.DeepCopy()
+		storageCluster.Name = "storage-cluster"
+		machineScope = &scope.MachineScope{
+			NcxInfraCluster: ncxInfraCluster,
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: "default"},
+				Spec: infrastructurev1.NcxInfraMachineSpec{
+					Network: infrastructurev1.NetworkSpec{
+						SubnetName: "workers",
+						AdditionalInterfaces: []infrastructurev1.NetworkInterface{{
+							SubnetName: "storage",
+							ClusterRef: &corev1.LocalObjectReference{Name: "storage-cluster"},
+						}},
+					},
+				},
+			},
+		}
+	})
+
+	reconcilerWith := func(objs ...client.Object) *NcxInfraMachineReconciler {
+		return &NcxInfraMachineReconciler{Client: newFakeClientBuilder(newTestScheme()).WithObjects(objs...).Build()}
+	}
+
+	It("returns the referenced clusters of the same tenant and site", func() {
+		referenced, err := reconcilerWith(storageCluster).referencedClusters(context.Background(), machineScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(referenced).To(HaveKey("storage-cluster"))
+	})
+
+	It("only looks up the clusters of the namespace of the machine", func() {
+		storageCluster.Namespace = "other-tenant"
+		_, err := reconcilerWith(storageCluster).referencedClusters(context.Background(), machineScope)
+		Expect(err).To(MatchError(errInvalidClusterRef))
+		Expect(err.Error()).To(ContainSubstring("NcxInfraCluster storage-cluster not found"))
+	})
+
+	It("rejects the clusters of another tenant or site", func() {
+		storageCluster.Spec.TenantID = "other-tenant-uuid"
+		_, err := reconcilerWith(storageCluster).referencedClusters(context.Background(), machineScope)
+		Expect(err).To(MatchError(errInvalidClusterRef))
+		Expect(err.Error()).To(ContainSubstring("belongs to tenant other-tenant-uuid"))
+
+		storageCluster.Spec.TenantID = ncxInfraCluster.Spec.TenantID
+		storageCluster.Spec.SiteRef.ID = "other-site-uuid"
+		Expect(validateClusterRef(ncxInfraCluster, storageCluster)).To(MatchError(errInvalidClusterRef))
+	})
+})
+
+var _ = Describe("NcxInfraCluster instance labels watch", func() {
+	It("maps a NcxInfraCluster to the machines of its Cluster", func() {
+		newMachine := func(name, clusterName string) *infrastructurev1.NcxInfraMachine {
+			return &infrastructurev1.NcxInfraMachine{ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+			}}
+		}
+		reconciler := &NcxInfraMachineReconciler{Client: newFakeClientBuilder(newTestScheme()).WithObjects(
+			newMachine("worker-0", "test-cluster"),
+			newMachine("worker-1", "test-cluster"),
+			newMachine("other-0", "other-cluster"),
+		).Build()}
+		ncxInfraCluster := &infrastructurev1.NcxInfraCluster{ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Cluster",
+				Name:       "test-cluster",
+				UID:        "cluster-uid",
+			}},
+		}}
+
+		Expect(reconciler.ncxInfraClusterToNcxInfraMachines(context.Background(), ncxInfraCluster)).To(ConsistOf(
+			reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "worker-0"}},
+			reconcile.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "worker-1"}}))
+
+		ncxInfraCluster.OwnerReferences = nil
+		Expect(reconciler.ncxInfraClusterToNcxInfraMachines(context.Background(), ncxInfraCluster)).To(BeEmpty())
+	})
+
+	It("only passes the updates of the instance labels", func() {
+		oldCluster := &infrastructurev1.NcxInfraCluster{}
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.VPC.Name = "renamed"
+		Expect(instanceLabelsChanged.Update(event.UpdateEvent{ObjectOld: oldCluster, ObjectNew: newCluster})).To(BeFalse())
+
+		newCluster.Spec.InstanceLabels = map[string]string{"team": "ml"}
+		Expect(instanceLabelsChanged.Update(event.UpdateEvent{ObjectOld: oldCluster, ObjectNew: newCluster})).To(BeTrue())
+	})
+})
+
+// fixedPlacement is a placement.Strategy returning the same decision for every machine.
+type fixedPlacement placement.Decision
+
+func (f fixedPlacement) Place(context.Context, placement.Request, placement.Inventory) (*placement.Decision, error) {
+	decision := placement.Decision(f)
+	return &decision, nil
+}
+
+var _ = Describe("checkBootstrapFormat", func() {
+	var machine *infrastructurev1.NcxInfraMachine
+
+	BeforeEach(func() {
+		machine = &infrastructurev1.NcxInfraMachine{
+			Spec: infrastructurev1.NcxInfraMachineSpec{
+				OperatingSystem: &infrastructurev1.OSSpec{Type: "Flatcar"},
+			},
+		}
+	})
+
+	It("accepts Ignition for an Ignition based operating system", func() {
+		Expect(checkBootstrapFormat(machine, scope.BootstrapFormatIgnition)).To(Succeed())
+		condition := conditions.Get(machine, string(BootstrapFormatCondition))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("Ignition"))
+	})
+
+	It("blocks cloud-config for an Ignition based operating system", func() {
+		err := checkBootstrapFormat(machine, scope.BootstrapFormatCloudConfig)
+		Expect(err).To(MatchError("bootstrap data format cloud-config does not match operating system Flatcar, which expects ignition"))
+		condition := conditions.Get(machine, string(BootstrapFormatCondition))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("FormatMismatch"))
+	})
+
+	It("blocks Ignition for a cloud-init based operating system", func() {
+		machine.Spec.OperatingSystem.Type = "ubuntu"
+		Expect(checkBootstrapFormat(machine, scope.BootstrapFormatIgnition)).NotTo(Succeed())
+		Expect(checkBootstrapFormat(machine, scope.BootstrapFormatCloudConfig)).To(Succeed())
+	})
+
+	It("accepts any format without operating system type", func() {
+		machine.Spec.OperatingSystem = nil
+		Expect(checkBootstrapFormat(machine, scope.BootstrapFormatIgnition)).To(Succeed())
+		Expect(conditions.IsTrue(machine, string(BootstrapFormatCondition))).To(BeTrue())
+	})
+})
+
+var _ = Describe("machinePhase", func() {
+	It("should summarize the state of the machine", func() {
+		machine := &infrastructurev1.NcxInfraMachine{}
+		Expect(machinePhase(machine)).To(Equal(infrastructurev1.MachinePhasePending))
+
+		machine.Status.InstanceID = "instance-uuid"
+		Expect(machinePhase(machine)).To(Equal(infrastructurev1.MachinePhaseProvisioning))
+
+		machine.Status.Ready = true
+		Expect(machinePhase(machine)).To(Equal(infrastructurev1.MachinePhaseRunning))
+
+		reason := capierrors.InvalidConfigurationMachineError
+		machine.Status.FailureReason = &reason
+		Expect(machinePhase(machine)).To(Equal(infrastructurev1.MachinePhaseFailed))
+
+		machine.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		Expect(machinePhase(machine)).To(Equal(infrastructurev1.MachinePhaseDeleting))
+	})
+})
+
+var _ = Describe("instancePhase", func() {
+	It("should derive the phase of the instance from its state", func() {
+		machine := &infrastructurev1.NcxInfraMachine{}
+		Expect(instancePhase(machine)).To(Equal(infrastructurev1.InstancePhasePending))
+
+		machine.Status.InstanceID = "instance-uuid"
+		for state, phase := range map[nico.InstanceStatus]infrastructurev1.InstancePhase{
+			nico.INSTANCESTATUS_PENDING:      infrastructurev1.InstancePhaseAllocating,
+			nico.INSTANCESTATUS_PROVISIONING: infrastructurev1.InstancePhaseImaging,
+			nico.INSTANCESTATUS_CONFIGURING:  infrastructurev1.InstancePhaseBooting,
+			nico.INSTANCESTATUS_READY:        infrastructurev1.InstancePhaseReady,
+			nico.INSTANCESTATUS_ERROR:        infrastructurev1.InstancePhaseFailed,
+			nico.INSTANCESTATUS_TERMINATING:  infrastructurev1.InstancePhaseDeleting,
+		} {
+			machine.Status.InstanceState = string(state)
+			Expect(instancePhase(machine)).To(Equal(phase), "state %s", state)
+		}
+
+		// A state unknown to the provider keeps the previous phase
+		machine.Status.InstancePhase = infrastructurev1.InstancePhaseReady
+		machine.Status.InstanceState = "Hibernating"
+		Expect(instancePhase(machine)).To(Equal(infrastructurev1.InstancePhaseReady))
+
+		reason := capierrors.InvalidConfigurationMachineError
+		machine.Status.FailureReason = &reason
+		Expect(instancePhase(machine)).To(Equal(infrastructurev1.InstancePhaseFailed))
+
+		machine.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		Expect(instancePhase(machine)).To(Equal(infrastructurev1.InstancePhaseDeleting))
+	})
+})
+
+var _ = Describe("placeInFailureDomain", func() {
+	const (
+		hallA = "site-hall-a"
+		hallB = "site-hall-b"
+	)
+
+	newScopes := func(failureDomain string) (*scope.MachineScope, *scope.ClusterScope) {
+		ncxInfraCluster := &infrastructurev1.NcxInfraCluster{
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				SiteRef: infrastructurev1.SiteReference{ID: hallA},
+				Subnets: []infrastructurev1.SubnetSpec{
+					{Name: "cp-a", Role: "control-plane"},
+					{Name: "workers-a", Role: "worker"},
+					{Name: "cp-b", Role: "control-plane", SiteRef: &infrastructurev1.SiteReference{ID: hallB}},
+				},
+			},
+			Status: infrastructurev1.NcxInfraClusterStatus{
+				NetworkStatus: infrastructurev1.NetworkStatus{
+					VPC:      &infrastructurev1.NetworkResourceStatus{ID: "vpc-a"},
+					SiteVPCs: []infrastructurev1.NetworkResourceStatus{{Name: hallB, ID: "vpc-b"}},
+					Subnets: []infrastructurev1.NetworkResourceStatus{
+						{Name: "cp-a", ID: "cp-a-uuid"}, {Name: "cp-b", ID: "cp-b-uuid"},
+					},
+				},
+			},
+		}
+		machineScope := &scope.MachineScope{
+			Machine: &clusterv1.Machine{Spec: clusterv1.MachineSpec{FailureDomain: failureDomain}},
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				Spec: infrastructurev1.NcxInfraMachineSpec{
+					Network: infrastructurev1.NetworkSpec{SubnetName: "cp-a"},
+				},
+			},
+			NcxInfraCluster: ncxInfraCluster,
+		}
+		return machineScope, &scope.ClusterScope{NcxInfraCluster: ncxInfraCluster}
+	}
+
+	It("keeps the subnet of the spec without failure domain", func() {
+		machineScope, clusterScope := newScopes("")
+
+		siteID, err := (&NcxInfraMachineReconciler{}).placeInFailureDomain(context.Background(), machineScope, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(siteID).To(Equal(hallA))
+		Expect(machineScope.VPCID()).To(Equal("vpc-a"))
+		Expect(machineScope.GetSubnetID()).To(Equal("cp-a-uuid"))
+	})
+
+	It("moves the machine to the subnet of the same role in its failure domain", func() {
+		machineScope, clusterScope := newScopes(hallB)
+
+		siteID, err := (&NcxInfraMachineReconciler{}).placeInFailureDomain(context.Background(), machineScope, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(siteID).To(Equal(hallB))
+		Expect(machineScope.SubnetName()).To(Equal("cp-b"))
+		Expect(machineScope.VPCID()).To(Equal("vpc-b"))
+		Expect(machineScope.GetSubnetID()).To(Equal("cp-b-uuid"))
+	})
+
+	It("fails when the failure domain has no subnet of the role", func() {
+		machineScope, clusterScope := newScopes(hallB)
+		machineScope.NcxInfraMachine.Spec.Network.SubnetName = "workers-a"
+
+		_, err := (&NcxInfraMachineReconciler{}).placeInFailureDomain(context.Background(), machineScope, clusterScope)
+		Expect(err).To(MatchError(ContainSubstring("in failure domain site-hall-b")))
 	})
 })
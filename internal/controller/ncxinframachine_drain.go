@@ -0,0 +1,110 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// WaitingForDrainCondition reports that the deletion of the instance waits
+// for Cluster API to drain the Node of the machine and detach its volumes.
+const WaitingForDrainCondition clusterv1.ConditionType = "WaitingForDrain"
+
+// drainPollInterval is how often the drain timeouts of a Machine are checked,
+// the Machine events requeue the machine as the drain progresses.
+const drainPollInterval = 30 * time.Second
+
+// waitForDrain reports whether the deletion of the instance of a machine must
+// wait for its Node to be drained, and sets the WaitingForDrain condition.
+// Cluster API deletes the NcxInfraMachine once the Node is drained and its
+// volumes detached, but a foreground deletion of the Machine or of its owners
+// deletes it first: deleting the instance then would kill the pods mid-write,
+// e.g. on local NVMe. The deletion waits while the Machine runs its pre-drain
+// hooks, drains the Node, following the MachineDrainRules, and waits for the
+// volumes to be detached, within the nodeDrainTimeoutSeconds and
+// nodeVolumeDetachTimeoutSeconds of the Machine.
+func waitForDrain(machineScope *scope.MachineScope, now time.Time) bool {
+	reason, message := drainPending(machineScope.Machine, now)
+	if reason == "" {
+		if conditions.Has(machineScope.NcxInfraMachine, string(WaitingForDrainCondition)) {
+			conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+				Type:   string(WaitingForDrainCondition),
+				Status: metav1.ConditionFalse,
+				Reason: "DrainCompleted",
+			})
+		}
+		return false
+	}
+	conditions.Set(machineScope.NcxInfraMachine, metav1.Condition{
+		Type:    string(WaitingForDrainCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+	return true
+}
+
+// drainPending returns the reason and message of the drain step a deleted
+// Machine is in, or an empty reason when the instance can be deleted: the
+// Machine is not being deleted, skips the step, or exceeded its timeout.
+func drainPending(machine *clusterv1.Machine, now time.Time) (string, string) {
+	if machine == nil || machine.DeletionTimestamp.IsZero() {
+		return "", ""
+	}
+	deleting := conditions.Get(machine, clusterv1.MachineDeletingCondition)
+	if deleting == nil || deleting.Status != metav1.ConditionTrue {
+		return "", ""
+	}
+
+	var deletion clusterv1.MachineDeletionStatus
+	if machine.Status.Deletion != nil {
+		deletion = *machine.Status.Deletion
+	}
+	switch deleting.Reason {
+	case clusterv1.MachineDeletingWaitingForPreDrainHookReason:
+		return deleting.Reason, "Waiting for the pre-drain hooks of the Machine"
+	case clusterv1.MachineDeletingDrainingNodeReason:
+		if _, ok := machine.Annotations[clusterv1.ExcludeNodeDrainingAnnotation]; ok ||
+			timedOut(deletion.NodeDrainStartTime, machine.Spec.Deletion.NodeDrainTimeoutSeconds, now) {
+			return "", ""
+		}
+		return deleting.Reason, fmt.Sprintf("Waiting for Cluster API to drain Node %s", machine.Status.NodeRef.Name)
+	case clusterv1.MachineDeletingWaitingForVolumeDetachReason:
+		if _, ok := machine.Annotations[clusterv1.ExcludeWaitForNodeVolumeDetachAnnotation]; ok ||
+			timedOut(deletion.WaitForNodeVolumeDetachStartTime, machine.Spec.Deletion.NodeVolumeDetachTimeoutSeconds, now) {
+			return "", ""
+		}
+		return deleting.Reason, fmt.Sprintf("Waiting for the volumes of Node %s to be detached", machine.Status.NodeRef.Name)
+	}
+	return "", ""
+}
+
+// timedOut reports whether a step started at start exceeded its timeout. A
+// zero or unset timeout never expires.
+func timedOut(start metav1.Time, timeoutSeconds *int32, now time.Time) bool {
+	if start.IsZero() || timeoutSeconds == nil || *timeoutSeconds == 0 {
+		return false
+	}
+	return now.Sub(start.Time) > time.Duration(*timeoutSeconds)*time.Second
+}
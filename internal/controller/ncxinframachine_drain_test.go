@@ -0,0 +1,138 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Machine drain", func() {
+	var (
+		ctx          context.Context
+		deletes      int
+		machine      *clusterv1.Machine
+		machineScope *scope.MachineScope
+		reconciler   *NcxInfraMachineReconciler
+	)
+
+	// setDeleting reports the deletion step of the Machine
+	setDeleting := func(reason string) {
+		conditions.Set(machine, metav1.Condition{
+			Type:   clusterv1.MachineDeletingCondition,
+			Status: metav1.ConditionTrue,
+			Reason: reason,
+		})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		deletes = 0
+		now := metav1.Now()
+		machine = &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "worker-0",
+				Namespace:         "default",
+				DeletionTimestamp: &now,
+				Finalizers:        []string{clusterv1.MachineFinalizer},
+			},
+			Status: clusterv1.MachineStatus{
+				NodeRef:  clusterv1.MachineNodeReference{Name: "node-0"},
+				Deletion: &clusterv1.MachineDeletionStatus{NodeDrainStartTime: now},
+			},
+		}
+		machineScope = &scope.MachineScope{
+			Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+			Machine:         machine,
+			NcxInfraCluster: &infrastructurev1.NcxInfraCluster{},
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "worker-0",
+					Namespace:  "default",
+					Finalizers: []string{NcxInfraMachineFinalizer},
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{InstanceID: "instance-uuid"},
+			},
+			OrgName: "test-org",
+			NcxInfraClient: &testutil.MockNcxInfraClient{
+				DeleteInstanceFunc: func(
+					_ context.Context, _, _ string, _ *nico.InstanceDeleteRequest,
+				) (*http.Response, error) {
+					deletes++
+					return testutil.MockHTTPResponse(http.StatusNoContent), nil
+				},
+			},
+		}
+		reconciler = &NcxInfraMachineReconciler{Scheme: newTestScheme()}
+	})
+
+	It("should keep the instance while Cluster API drains the Node", func() {
+		setDeleting(clusterv1.MachineDeletingDrainingNodeReason)
+		result, err := reconciler.reconcileDelete(ctx, machineScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(drainPollInterval))
+		Expect(deletes).To(BeZero())
+
+		condition := conditions.Get(machineScope.NcxInfraMachine, string(WaitingForDrainCondition))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(clusterv1.MachineDeletingDrainingNodeReason))
+		Expect(condition.Message).To(ContainSubstring("node-0"))
+
+		By("deleting the instance once the Machine waits for its infrastructure")
+		setDeleting(clusterv1.MachineDeletingWaitingForInfrastructureDeletionReason)
+		_, err = reconciler.reconcileDelete(ctx, machineScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletes).To(Equal(1))
+		Expect(conditions.IsFalse(machineScope.NcxInfraMachine, string(WaitingForDrainCondition))).To(BeTrue())
+	})
+
+	It("should keep the instance while the volumes of the Node are detached", func() {
+		setDeleting(clusterv1.MachineDeletingWaitingForVolumeDetachReason)
+		machine.Status.Deletion.WaitForNodeVolumeDetachStartTime = metav1.Now()
+		Expect(waitForDrain(machineScope, time.Now())).To(BeTrue())
+
+		machine.Annotations = map[string]string{clusterv1.ExcludeWaitForNodeVolumeDetachAnnotation: ""}
+		Expect(waitForDrain(machineScope, time.Now())).To(BeFalse())
+	})
+
+	It("should delete the instance once the drain timeout of the Machine is exceeded", func() {
+		setDeleting(clusterv1.MachineDeletingDrainingNodeReason)
+		machine.Spec.Deletion.NodeDrainTimeoutSeconds = testutil.Ptr(int32(600))
+		Expect(waitForDrain(machineScope, time.Now().Add(5*time.Minute))).To(BeTrue())
+		Expect(waitForDrain(machineScope, time.Now().Add(15*time.Minute))).To(BeFalse())
+	})
+
+	It("should not wait when the Machine is not being deleted", func() {
+		machine.DeletionTimestamp = nil
+		setDeleting(clusterv1.MachineDeletingDrainingNodeReason)
+		Expect(waitForDrain(machineScope, time.Now())).To(BeFalse())
+		Expect(conditions.Has(machineScope.NcxInfraMachine, string(WaitingForDrainCondition))).To(BeFalse())
+	})
+})
@@ -0,0 +1,130 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// updateHardware records the hardware of the physical machine of the instance
+// in status.hardware, once per machine, so that the inventory tooling reads it
+// from Kubernetes. The serial number, the adapters and the rack are only
+// visible to provider admins and are omitted otherwise.
+func (r *NcxInfraMachineReconciler) updateHardware(
+	ctx context.Context, machineScope *scope.MachineScope, instance *nico.Instance,
+) {
+	machineID := machineScope.MachineID()
+	if hardware := machineScope.NcxInfraMachine.Status.Hardware; machineID == "" ||
+		(hardware != nil && hardware.MachineID == machineID) {
+		return
+	}
+
+	getStart := time.Now()
+	machine, httpResp, err := machineScope.NcxInfraClient.GetMachine(ctx, machineScope.OrgName, machineID)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetMachine")
+	recordAPIMetrics("GetMachine", getStart, apiErr)
+	if apiErr != nil {
+		log.FromContext(ctx).Info("Failed to get the hardware of the machine, will retry",
+			"machineID", machineID, "error", apiErr.Message)
+		return
+	}
+	if machine == nil {
+		return
+	}
+	hardware := machineHardware(machine)
+	hardware.MachineID = machineID
+
+	if siteID := instance.GetSiteId(); siteID != "" {
+		listStart := time.Now()
+		trays, httpResp, err := machineScope.NcxInfraClient.GetAllTray(
+			ctx, machineScope.OrgName, siteID, "compute", machineID)
+		apiErr := scope.ClassifyAPIError(httpResp, err, "GetAllTray")
+		recordAPIMetrics("GetAllTray", listStart, apiErr)
+		if apiErr == nil && len(trays) > 0 {
+			hardware.Rack = trays[0].GetRackId()
+			if trays[0].Position != nil {
+				hardware.Slot = trays[0].Position.SlotId
+			}
+		}
+	}
+
+	machineScope.NcxInfraMachine.Status.Hardware = hardware
+}
+
+// machineHardware describes the hardware of a NVIDIA Carbide machine. The GPUs
+// come from the metadata of the machine when visible, from its capabilities
+// otherwise.
+func machineHardware(machine *nico.Machine) *infrastructurev1.HardwareStatus {
+	hardware := &infrastructurev1.HardwareStatus{
+		Vendor:       machine.GetVendor(),
+		ProductName:  machine.GetProductName(),
+		SerialNumber: machine.GetSerialNumber(),
+	}
+
+	metadata := machine.Metadata
+	if metadata != nil && len(metadata.Gpus) > 0 {
+		hardware.GPUCount = int32(len(metadata.Gpus))
+		hardware.GPUModel = metadata.Gpus[0].GetName()
+	} else {
+		for _, capability := range machine.MachineCapabilities {
+			if capability.GetType() != "GPU" {
+				continue
+			}
+			count := int32(1)
+			if capability.Count.Get() != nil {
+				count = *capability.Count.Get()
+			}
+			hardware.GPUCount += count
+			if hardware.GPUModel == "" {
+				hardware.GPUModel = capability.GetName()
+			}
+		}
+	}
+	if metadata == nil {
+		return hardware
+	}
+
+	if hardware.SerialNumber == "" && metadata.DmiData != nil {
+		hardware.SerialNumber = metadata.DmiData.GetProductSerial()
+	}
+	for _, nic := range metadata.NetworkInterfaces {
+		hardware.NetworkInterfaces = append(hardware.NetworkInterfaces, infrastructurev1.HardwareInterface{
+			Address:  nic.GetMacAddress(),
+			Vendor:   nic.GetVendor(),
+			Device:   nic.GetDevice(),
+			Slot:     nic.GetSlot(),
+			NUMANode: nic.NumaNode,
+		})
+	}
+	for _, hca := range metadata.InfinibandInterfaces {
+		hardware.InfiniBandInterfaces = append(hardware.InfiniBandInterfaces, infrastructurev1.HardwareInterface{
+			Address:  hca.GetGuid(),
+			Vendor:   hca.GetVendor(),
+			Device:   hca.GetDevice(),
+			Slot:     hca.GetSlot(),
+			NUMANode: hca.NumaNode,
+		})
+	}
+	return hardware
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Machine hardware", func() {
+	var (
+		ctx          context.Context
+		machineScope *scope.MachineScope
+		reconciler   *NcxInfraMachineReconciler
+		getMachines  int
+		instance     *nico.Instance
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		getMachines = 0
+		instance = &nico.Instance{SiteId: testutil.Ptr("site-1")}
+		machineScope = &scope.MachineScope{
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-0", Namespace: "default"},
+			},
+			OrgName: "test-org",
+			NcxInfraClient: &testutil.MockNcxInfraClient{
+				GetMachineFunc: func(_ context.Context, _, machineID string) (*nico.Machine, *http.Response, error) {
+					getMachines++
+					Expect(machineID).To(Equal("machine-1"))
+					gpu := nico.MachineGPUInfo{Name: testutil.Ptr("NVIDIA H100 80GB HBM3")}
+					return &nico.Machine{
+						Vendor:      testutil.Ptr("Dell Inc."),
+						ProductName: testutil.Ptr("PowerEdge XE9680"),
+						Metadata: &nico.MachineMetadata{
+							DmiData: &nico.MachineDMIData{ProductSerial: testutil.Ptr("SN-1234")},
+							Gpus:    []nico.MachineGPUInfo{gpu, gpu},
+							NetworkInterfaces: []nico.MachineNetworkInterface{{
+								MacAddress: testutil.Ptr("b8:3f:d2:00:00:01"),
+								Vendor:     testutil.Ptr("Mellanox Technologies"),
+								Device:     testutil.Ptr("BlueField-3"),
+								Slot:       testutil.Ptr("0000:3b:00.0"),
+								NumaNode:   testutil.Ptr(int32(0)),
+							}},
+							InfinibandInterfaces: []nico.MachineInfiniBandInterface{{
+								Guid:     testutil.Ptr("0x1070fd0300000001"),
+								Device:   testutil.Ptr("ConnectX-7"),
+								NumaNode: testutil.Ptr(int32(1)),
+							}},
+						},
+					}, testutil.MockHTTPResponse(http.StatusOK), nil
+				},
+				GetAllTrayFunc: func(_ context.Context, _, siteID, trayType, machineID string) ([]nico.Tray, *http.Response, error) {
+					Expect(siteID).To(Equal("site-1"))
+					Expect(trayType).To(Equal("compute"))
+					Expect(machineID).To(Equal("machine-1"))
+					return []nico.Tray{{
+						RackId:   testutil.Ptr("rack-12"),
+						Position: &nico.TrayPosition{SlotId: testutil.Ptr(int32(7))},
+					}}, testutil.MockHTTPResponse(http.StatusOK), nil
+				},
+			},
+		}
+		reconciler = &NcxInfraMachineReconciler{}
+	})
+
+	It("should not query the machine before it is allocated", func() {
+		reconciler.updateHardware(ctx, machineScope, instance)
+		Expect(getMachines).To(BeZero())
+		Expect(machineScope.NcxInfraMachine.Status.Hardware).To(BeNil())
+	})
+
+	It("should record the hardware of the machine once", func() {
+		machineScope.SetMachineID("machine-1")
+		reconciler.updateHardware(ctx, machineScope, instance)
+		reconciler.updateHardware(ctx, machineScope, instance)
+
+		Expect(getMachines).To(Equal(1))
+		Expect(machineScope.NcxInfraMachine.Status.Hardware).To(Equal(&infrastructurev1.HardwareStatus{
+			MachineID:    "machine-1",
+			Vendor:       "Dell Inc.",
+			ProductName:  "PowerEdge XE9680",
+			SerialNumber: "SN-1234",
+			GPUModel:     "NVIDIA H100 80GB HBM3",
+			GPUCount:     2,
+			NetworkInterfaces: []infrastructurev1.HardwareInterface{{
+				Address:  "b8:3f:d2:00:00:01",
+				Vendor:   "Mellanox Technologies",
+				Device:   "BlueField-3",
+				Slot:     "0000:3b:00.0",
+				NUMANode: testutil.Ptr(int32(0)),
+			}},
+			InfiniBandInterfaces: []infrastructurev1.HardwareInterface{{
+				Address:  "0x1070fd0300000001",
+				Device:   "ConnectX-7",
+				NUMANode: testutil.Ptr(int32(1)),
+			}},
+			Rack: "rack-12",
+			Slot: testutil.Ptr(int32(7)),
+		}))
+	})
+
+	It("should refresh the hardware when the instance moves to another machine", func() {
+		machineScope.NcxInfraMachine.Status.Hardware = &infrastructurev1.HardwareStatus{MachineID: "machine-0"}
+		machineScope.SetMachineID("machine-1")
+		reconciler.updateHardware(ctx, machineScope, instance)
+
+		Expect(getMachines).To(Equal(1))
+		Expect(machineScope.NcxInfraMachine.Status.Hardware.MachineID).To(Equal("machine-1"))
+	})
+
+	It("should retry when the machine cannot be read", func() {
+		machineScope.SetMachineID("machine-1")
+		machineScope.NcxInfraClient = &testutil.MockNcxInfraClient{
+			GetMachineFunc: func(_ context.Context, _, _ string) (*nico.Machine, *http.Response, error) {
+				return nil, testutil.MockHTTPResponse(http.StatusServiceUnavailable), errors.New("unavailable")
+			},
+		}
+		reconciler.updateHardware(ctx, machineScope, instance)
+		Expect(machineScope.NcxInfraMachine.Status.Hardware).To(BeNil())
+	})
+
+	It("should fall back to the capabilities of the machine without metadata", func() {
+		hardware := machineHardware(&nico.Machine{
+			SerialNumber: testutil.Ptr("SN-5678"),
+			MachineCapabilities: []nico.MachineCapability{
+				{Type: testutil.Ptr("CPU"), Name: testutil.Ptr("Intel Xeon Platinum 8480+")},
+				{Type: testutil.Ptr("GPU"), Name: testutil.Ptr("NVIDIA A100"), Count: *nico.NewNullableInt32(testutil.Ptr(int32(8)))},
+			},
+		})
+		Expect(hardware.SerialNumber).To(Equal("SN-5678"))
+		Expect(hardware.GPUModel).To(Equal("NVIDIA A100"))
+		Expect(hardware.GPUCount).To(Equal(int32(8)))
+		Expect(hardware.NetworkInterfaces).To(BeEmpty())
+	})
+})
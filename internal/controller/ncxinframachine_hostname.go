@@ -0,0 +1,158 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/cloudinit"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// assignHostname records the hostname of the node of a machine in
+// status.hostname, before its instance is first created. It is rendered from
+// spec.hostnameFormat with the lowest index whose hostname no other machine
+// of the cluster has, or is the name of the machine when only spec.domain is
+// set. A recorded hostname is kept, so that the node keeps its name when the
+// instance is created again or reprovisioned.
+func (r *NcxInfraMachineReconciler) assignHostname(
+	ctx context.Context, machineScope *scope.MachineScope, siteID string,
+) error {
+	machine := machineScope.NcxInfraMachine
+	if machine.Status.Hostname != "" {
+		return nil
+	}
+	if machine.Spec.HostnameFormat == "" {
+		if machine.Spec.Domain != "" {
+			machine.Status.Hostname = machineScope.Name()
+		}
+		return nil
+	}
+
+	machineList := &infrastructurev1.NcxInfraMachineList{}
+	if err := r.List(ctx, machineList,
+		client.InNamespace(machineScope.Namespace()),
+		client.MatchingFields{ClusterNameField: machineScope.Machine.Spec.ClusterName},
+	); err != nil {
+		return fmt.Errorf("failed to list the machines of the cluster: %w", err)
+	}
+	used := make(map[string]string, len(machineList.Items))
+	for _, item := range machineList.Items {
+		if item.Name != machine.Name && item.Status.Hostname != "" {
+			used[item.Status.Hostname] = item.Name
+		}
+	}
+
+	vars := infrastructurev1.HostnameVariables{
+		ClusterName: machineScope.Machine.Spec.ClusterName,
+		MachineName: machineScope.Name(),
+		Namespace:   machineScope.Namespace(),
+		Role:        machineScope.Role(),
+		Site:        siteID,
+	}
+	// One of the first len(used)+1 indexes is free, unless the format ignores the index
+	var owner string
+	for vars.Index = 0; vars.Index <= len(used); vars.Index++ {
+		hostname, err := infrastructurev1.RenderHostname(machine.Spec.HostnameFormat, vars)
+		if err != nil {
+			r.recordEvent(machine, corev1.EventTypeWarning, "HostnameNotApplied",
+				"Hostname format not rendered: %v", err)
+			return fmt.Errorf("failed to render the hostname: %w", err)
+		}
+		if owner = used[hostname]; owner == "" {
+			machine.Status.Hostname = hostname
+			return nil
+		}
+	}
+	r.recordEvent(machine, corev1.EventTypeWarning, "HostnameNotApplied",
+		"Hostname format renders the hostname of machine %s, use {{.Index}} or {{.MachineName}}", owner)
+	return fmt.Errorf("hostname format renders the hostname of machine %s", owner)
+}
+
+// applyHostname substitutes the variables of the machine in its bootstrap
+// data, then sets the hostname recorded in its status. The hostname cannot be
+// changed once the node has joined, so bootstrap data that is not
+// cloud-config fails the creation when a hostname is set.
+func (r *NcxInfraMachineReconciler) applyHostname(
+	machineScope *scope.MachineScope, siteID string, req *nico.InstanceCreateRequest,
+) error {
+	if req.UserData.Get() == nil {
+		return nil
+	}
+	userData := userDataVariables(machineScope, siteID).Replace(*req.UserData.Get())
+
+	hostname, fqdn := machineScope.NcxInfraMachine.Status.Hostname, machineFQDN(machineScope.NcxInfraMachine)
+	userData, err := cloudinit.ApplyHostname(userData, hostname, fqdn)
+	if err != nil {
+		r.recordEvent(machineScope.NcxInfraMachine, corev1.EventTypeWarning, "HostnameNotApplied",
+			"Hostname %s not applied: %v", hostname, err)
+		return fmt.Errorf("failed to apply the hostname: %w", err)
+	}
+	req.UserData = *nico.NewNullableString(&userData)
+	return nil
+}
+
+// machineFQDN returns the fully qualified domain name of the node of a
+// machine, or an empty string without hostname or domain.
+func machineFQDN(machine *infrastructurev1.NcxInfraMachine) string {
+	if machine.Status.Hostname == "" || machine.Spec.Domain == "" {
+		return ""
+	}
+	return machine.Status.Hostname + "." + machine.Spec.Domain
+}
+
+// userDataVariables returns the replacer of the ${NCX_INFRA_*} variables of
+// the bootstrap data. Only the known variables are replaced, by an empty
+// string when their value is unknown, so that the other ${...} expressions of
+// the scripts are left alone.
+func userDataVariables(machineScope *scope.MachineScope, siteID string) *strings.Replacer {
+	machine := machineScope.NcxInfraMachine
+	ipAddress := machine.Spec.Network.IpAddress
+	if ipAddress == "" {
+		// Known once the instance is created, i.e. when it is reprovisioned
+		for _, address := range machine.Status.Addresses {
+			if address.Type == clusterv1.MachineInternalIP {
+				ipAddress = address.Address
+				break
+			}
+		}
+	}
+
+	vars := map[string]string{
+		"CLUSTER_NAME": machineScope.Machine.Spec.ClusterName,
+		"MACHINE_NAME": machineScope.Name(),
+		"NAMESPACE":    machineScope.Namespace(),
+		"ROLE":         machineScope.Role(),
+		"SITE":         siteID,
+		"HOSTNAME":     machine.Status.Hostname,
+		"FQDN":         machineFQDN(machine),
+		"IP_ADDRESS":   ipAddress,
+	}
+	oldnew := make([]string, 0, 2*len(vars))
+	for name, value := range vars {
+		oldnew = append(oldnew, "${NCX_INFRA_"+name+"}", value)
+	}
+	return strings.NewReplacer(oldnew...)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/yaml"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Machine hostname", func() {
+	var (
+		ctx          context.Context
+		machineScope *scope.MachineScope
+		reconciler   *NcxInfraMachineReconciler
+	)
+
+	// peer returns another machine of the cluster with a hostname
+	peer := func(name, hostname string) *infrastructurev1.NcxInfraMachine {
+		return &infrastructurev1.NcxInfraMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "prod"},
+			},
+			Status: infrastructurev1.NcxInfraMachineStatus{Hostname: hostname},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		machineScope = &scope.MachineScope{
+			Machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "prod-md-0-abcde", Namespace: "default"},
+				Spec:       clusterv1.MachineSpec{ClusterName: "prod"},
+			},
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "prod-md-0-abcde",
+					Namespace: "default",
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: "prod"},
+				},
+				Spec: infrastructurev1.NcxInfraMachineSpec{
+					HostnameFormat: "{{.ClusterName}}-{{.Role}}-{{.Index}}",
+					Domain:         "dc1.example.com",
+				},
+			},
+		}
+		reconciler = &NcxInfraMachineReconciler{
+			Client: newFakeClientBuilder(newTestScheme()).WithObjects(
+				peer("prod-md-0-fghij", "prod-worker-0"),
+				peer("prod-md-0-klmno", "prod-worker-2"),
+				peer("prod-cp-0", "prod-control-plane-0"),
+			).Build(),
+		}
+	})
+
+	It("should take the lowest index not used by another machine of the cluster", func() {
+		Expect(reconciler.assignHostname(ctx, machineScope, "site-1")).To(Succeed())
+		Expect(machineScope.NcxInfraMachine.Status.Hostname).To(Equal("prod-worker-1"))
+	})
+
+	It("should keep the recorded hostname", func() {
+		machineScope.NcxInfraMachine.Status.Hostname = "prod-worker-7"
+		Expect(reconciler.assignHostname(ctx, machineScope, "site-1")).To(Succeed())
+		Expect(machineScope.NcxInfraMachine.Status.Hostname).To(Equal("prod-worker-7"))
+	})
+
+	It("should name the node after the machine with only a domain", func() {
+		machineScope.NcxInfraMachine.Spec.HostnameFormat = ""
+		Expect(reconciler.assignHostname(ctx, machineScope, "site-1")).To(Succeed())
+		Expect(machineScope.NcxInfraMachine.Status.Hostname).To(Equal("prod-md-0-abcde"))
+	})
+
+	It("should fail when the format renders the hostname of another machine", func() {
+		machineScope.NcxInfraMachine.Spec.HostnameFormat = "{{.ClusterName}}-control-plane-0"
+		err := reconciler.assignHostname(ctx, machineScope, "site-1")
+		Expect(err).To(MatchError(ContainSubstring("prod-cp-0")))
+		Expect(machineScope.NcxInfraMachine.Status.Hostname).To(BeEmpty())
+	})
+
+	It("should set the hostname and substitute the variables of the bootstrap data", func() {
+		machineScope.NcxInfraMachine.Spec.Network.IpAddress = "10.0.1.10"
+		machineScope.NcxInfraMachine.Status.Hostname = "prod-worker-1"
+		userData := "#cloud-config\nruncmd:\n" +
+			"- echo ${NCX_INFRA_FQDN} ${NCX_INFRA_SITE} ${NCX_INFRA_IP_ADDRESS} ${NCX_INFRA_ROLE} > /etc/node-id\n" +
+			"- echo ${HOME}\n"
+		req := &nico.InstanceCreateRequest{UserData: *nico.NewNullableString(&userData)}
+		Expect(reconciler.applyHostname(machineScope, "site-1", req)).To(Succeed())
+
+		var doc struct {
+			Hostname string   `json:"hostname"`
+			FQDN     string   `json:"fqdn"`
+			RunCmd   []string `json:"runcmd"`
+		}
+		Expect(yaml.Unmarshal([]byte(*req.UserData.Get()), &doc)).To(Succeed())
+		Expect(doc.Hostname).To(Equal("prod-worker-1"))
+		Expect(doc.FQDN).To(Equal("prod-worker-1.dc1.example.com"))
+		Expect(doc.RunCmd).To(Equal([]string{
+			"echo prod-worker-1.dc1.example.com site-1 10.0.1.10 worker > /etc/node-id",
+			"echo ${HOME}",
+		}))
+	})
+
+	It("should refuse a hostname, but substitute the variables, in bootstrap data that is not cloud-config", func() {
+		machineScope.NcxInfraMachine.Status.Hostname = "prod-worker-1"
+		userData := "#!/bin/bash\nhostnamectl set-hostname ${NCX_INFRA_MACHINE_NAME}\n"
+		req := &nico.InstanceCreateRequest{UserData: *nico.NewNullableString(&userData)}
+		Expect(reconciler.applyHostname(machineScope, "site-1", req)).NotTo(Succeed())
+
+		machineScope.NcxInfraMachine.Status.Hostname = ""
+		machineScope.NcxInfraMachine.Spec.Domain = ""
+		Expect(reconciler.applyHostname(machineScope, "site-1", req)).To(Succeed())
+		Expect(*req.UserData.Get()).To(Equal("#!/bin/bash\nhostnamectl set-hostname prod-md-0-abcde\n"))
+	})
+})
@@ -0,0 +1,242 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	ncxinfraerrors "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/errors"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// errProvisioningLimit is returned when the cluster of a machine already
+// provisions the maximum number of instances of spec.provisioning, or its
+// MachineDeployment the maximum number of instances of its rollout.
+var errProvisioningLimit = errors.New("provisioning concurrency limit reached")
+
+// provisioningSlotRetryInterval paces the machines waiting for a provisioning slot.
+const provisioningSlotRetryInterval = 30 * time.Second
+
+// provisioningStartTTL is how long an instance whose provisioning was just
+// started counts against the limit, while the cache does not reflect it yet.
+const provisioningStartTTL = time.Minute
+
+// provisioningStates are the states of an instance being provisioned or
+// reimaged.
+var provisioningStates = map[nico.InstanceStatus]bool{
+	nico.INSTANCESTATUS_PENDING:      true,
+	nico.INSTANCESTATUS_PROVISIONING: true,
+	nico.INSTANCESTATUS_CONFIGURING:  true,
+}
+
+// provisioningTracker counts the instances each cluster provisions. The
+// machines are read from the cache, which lags behind the instances the
+// controller just created or reimaged: those are remembered for a while so
+// that the concurrent reconciles do not exceed the limit.
+type provisioningTracker struct {
+	mu sync.Mutex
+	// reserved records the machines holding a slot while their instance is
+	// being created or reimaged, by machine name and cluster, with their
+	// MachineDeployment.
+	reserved map[types.NamespacedName]map[string]string
+	// started records when the provisioning of the instance of a machine was
+	// started, by machine name and cluster.
+	started map[types.NamespacedName]map[string]provisioningStart
+}
+
+// provisioningStart records when the provisioning of the instance of a
+// machine of a MachineDeployment was started.
+type provisioningStart struct {
+	at         time.Time
+	deployment string
+}
+
+// acquireProvisioningSlot checks that the MachineDeployment of a machine
+// provisions fewer instances than the maxUnavailable of its rollout while it
+// rolls out, and that its cluster provisions fewer instances than
+// spec.provisioning.maxConcurrent, before the instance of the machine is
+// created or reimaged. The slot is reserved until the returned function is
+// called with whether the provisioning was started, so that the concurrent
+// reconciles count it without waiting for the NVIDIA Carbide API calls of the
+// machine.
+func (r *NcxInfraMachineReconciler) acquireProvisioningSlot(
+	ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope,
+) (func(started bool), error) {
+	if clusterScope.Cluster == nil {
+		return func(bool) {}, nil
+	}
+	clusterLimit := 0
+	if provisioning := clusterScope.NcxInfraCluster.Spec.Provisioning; provisioning != nil {
+		clusterLimit = int(provisioning.MaxConcurrent)
+	}
+	deployment, deploymentLimit, err := r.rolloutProvisioningLimit(ctx, machineScope.NcxInfraMachine)
+	if err != nil {
+		return nil, err
+	}
+	if clusterLimit == 0 && deploymentLimit == 0 {
+		return func(bool) {}, nil
+	}
+	cluster := client.ObjectKeyFromObject(clusterScope.Cluster)
+	name := machineScope.NcxInfraMachine.Name
+
+	tracker := &r.provisioning
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	inFlight, err := tracker.inFlight(ctx, r.Client, cluster, name)
+	if err != nil {
+		return nil, err
+	}
+	if clusterLimit > 0 && len(inFlight) >= clusterLimit {
+		return nil, ncxinfraerrors.Transient(ncxinfraerrors.WaitingForProvisioningSlot, fmt.Errorf(
+			"%w: %d instance(s) of cluster %s provisioning, at most %d at once",
+			errProvisioningLimit, len(inFlight), cluster.Name, clusterLimit))
+	}
+	if deploymentLimit > 0 {
+		deploymentInFlight := 0
+		for _, d := range inFlight {
+			if d == deployment {
+				deploymentInFlight++
+			}
+		}
+		if deploymentInFlight >= deploymentLimit {
+			return nil, ncxinfraerrors.Transient(ncxinfraerrors.WaitingForProvisioningSlot, fmt.Errorf(
+				"%w: %d instance(s) of MachineDeployment %s provisioning, at most %d at once during its rollout",
+				errProvisioningLimit, deploymentInFlight, deployment, deploymentLimit))
+		}
+	}
+	if tracker.reserved == nil {
+		tracker.reserved = map[types.NamespacedName]map[string]string{}
+	}
+	if tracker.reserved[cluster] == nil {
+		tracker.reserved[cluster] = map[string]string{}
+	}
+	tracker.reserved[cluster][name] = deployment
+
+	return func(started bool) {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		delete(tracker.reserved[cluster], name)
+		if len(tracker.reserved[cluster]) == 0 {
+			delete(tracker.reserved, cluster)
+		}
+		if !started {
+			return
+		}
+		if tracker.started == nil {
+			tracker.started = map[types.NamespacedName]map[string]provisioningStart{}
+		}
+		if tracker.started[cluster] == nil {
+			tracker.started[cluster] = map[string]provisioningStart{}
+		}
+		tracker.started[cluster][name] = provisioningStart{at: time.Now(), deployment: deployment}
+	}, nil
+}
+
+// rolloutProvisioningLimit returns the MachineDeployment of a machine and,
+// while it rolls out with the RollingUpdate strategy, the number of its
+// instances provisioned at once at most: the maxUnavailable of its rollout,
+// resolved against its replicas the way Cluster API does, and at least one so
+// that a rollout that only surges progresses. The limit is 0 when the machine
+// belongs to no MachineDeployment rolling out.
+func (r *NcxInfraMachineReconciler) rolloutProvisioningLimit(
+	ctx context.Context, machine *infrastructurev1.NcxInfraMachine,
+) (string, int, error) {
+	name := machine.Labels[clusterv1.MachineDeploymentNameLabel]
+	if name == "" {
+		return "", 0, nil
+	}
+	md := &clusterv1.MachineDeployment{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: machine.Namespace, Name: name}, md); err != nil {
+		if apierrors.IsNotFound(err) {
+			return name, 0, nil
+		}
+		return "", 0, fmt.Errorf("failed to get MachineDeployment %s: %w", name, err)
+	}
+
+	strategy := md.Spec.Rollout.Strategy
+	rollingOut := ptr.Deref(md.Status.UpToDateReplicas, 0) < ptr.Deref(md.Status.Replicas, 0)
+	if !rollingOut || (strategy.Type != "" && strategy.Type != clusterv1.RollingUpdateMachineDeploymentStrategyType) {
+		return name, 0, nil
+	}
+	maxUnavailable := ptr.To(intstr.FromInt32(0))
+	if strategy.RollingUpdate.MaxUnavailable != nil {
+		maxUnavailable = strategy.RollingUpdate.MaxUnavailable
+	}
+	limit, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, int(ptr.Deref(md.Spec.Replicas, 1)), false)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid maxUnavailable of MachineDeployment %s: %w", name, err)
+	}
+	return name, max(limit, 1), nil
+}
+
+// inFlight returns the machines of a cluster whose instance is being
+// provisioned, other than the one named self, with their MachineDeployment:
+// the instances of the machines in a provisioning state, the ones being
+// created or reimaged, and the ones just started. It must be called with the
+// lock held.
+func (t *provisioningTracker) inFlight(
+	ctx context.Context, c client.Client, cluster types.NamespacedName, self string,
+) (map[string]string, error) {
+	machineList := &infrastructurev1.NcxInfraMachineList{}
+	if err := c.List(ctx, machineList,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingFields{ClusterNameField: cluster.Name},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list the NcxInfraMachines of cluster %s: %w", cluster.Name, err)
+	}
+
+	machines := map[string]string{}
+	for i := range machineList.Items {
+		machine := &machineList.Items[i]
+		if machine.Name != self && machine.DeletionTimestamp.IsZero() && machine.Status.InstanceID != "" &&
+			provisioningStates[nico.InstanceStatus(machine.Status.InstanceState)] {
+			machines[machine.Name] = machine.Labels[clusterv1.MachineDeploymentNameLabel]
+		}
+	}
+	for name, deployment := range t.reserved[cluster] {
+		if name != self {
+			machines[name] = deployment
+		}
+	}
+	now := time.Now()
+	for name, started := range t.started[cluster] {
+		if now.Sub(started.at) > provisioningStartTTL {
+			delete(t.started[cluster], name)
+			continue
+		}
+		if name != self {
+			machines[name] = started.deployment
+		}
+	}
+	if len(t.started[cluster]) == 0 {
+		delete(t.started, cluster)
+	}
+	return machines, nil
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	ncxinfraerrors "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/errors"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Provisioning concurrency", func() {
+	var (
+		ctx          context.Context
+		clusterScope *scope.ClusterScope
+		reconciler   *NcxInfraMachineReconciler
+	)
+
+	clusterMachine := func(name string, state nico.InstanceStatus) *infrastructurev1.NcxInfraMachine {
+		return &infrastructurev1.NcxInfraMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+			},
+			Status: infrastructurev1.NcxInfraMachineStatus{
+				InstanceID:    name + "-instance",
+				InstanceState: string(state),
+			},
+		}
+	}
+	machineScopeOf := func(name string) *scope.MachineScope {
+		return &scope.MachineScope{NcxInfraMachine: clusterMachine(name, "")}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clusterScope = &scope.ClusterScope{
+			Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+			NcxInfraCluster: &infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				Spec: infrastructurev1.NcxInfraClusterSpec{
+					Provisioning: &infrastructurev1.ProvisioningSpec{MaxConcurrent: 2},
+				},
+			},
+		}
+		k8sClient := newFakeClientBuilder(newTestScheme()).WithObjects(
+			clusterMachine("worker-0", nico.INSTANCESTATUS_PROVISIONING),
+			clusterMachine("worker-1", nico.INSTANCESTATUS_READY),
+		).Build()
+		reconciler = &NcxInfraMachineReconciler{Client: k8sClient}
+	})
+
+	It("should not limit the clusters without spec.provisioning", func() {
+		clusterScope.NcxInfraCluster.Spec.Provisioning = nil
+		release, err := reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-2"), clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		release(true)
+		Expect(reconciler.provisioning.started).To(BeEmpty())
+	})
+
+	It("should count the machines provisioning and the instances just created", func() {
+		release, err := reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-2"), clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		release(true)
+
+		// worker-0 is provisioning, and the cache does not show the instance of worker-2 yet
+		_, err = reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-3"), clusterScope)
+		Expect(errors.Is(err, errProvisioningLimit)).To(BeTrue())
+		Expect(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.InstanceCreateFailed)).
+			To(Equal(ncxinfraerrors.WaitingForProvisioningSlot))
+		Expect(err.Error()).To(ContainSubstring("2 instance(s) of cluster test-cluster provisioning, at most 2 at once"))
+	})
+
+	It("should free the slot of a provisioning that was not started", func() {
+		release, err := reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-2"), clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		release(false)
+
+		release, err = reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-3"), clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		release(false)
+	})
+
+	It("should count the slots reserved by the machines being created", func() {
+		release, err := reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-2"), clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+
+		// worker-2 is still creating its instance, the slots of the cluster are not locked meanwhile
+		_, err = reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-3"), clusterScope)
+		Expect(errors.Is(err, errProvisioningLimit)).To(BeTrue())
+
+		otherCluster := &scope.ClusterScope{
+			Cluster:         &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "other-cluster", Namespace: "default"}},
+			NcxInfraCluster: clusterScope.NcxInfraCluster,
+		}
+		otherRelease, err := reconciler.acquireProvisioningSlot(ctx, machineScopeOf("other-0"), otherCluster)
+		Expect(err).NotTo(HaveOccurred())
+		otherRelease(false)
+
+		// The creation failed, the reservation is released
+		release(false)
+		Expect(reconciler.provisioning.reserved).To(BeEmpty())
+		release, err = reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-3"), clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		release(false)
+	})
+
+	It("should not count the machine itself", func() {
+		clusterScope.NcxInfraCluster.Spec.Provisioning.MaxConcurrent = 1
+		release, err := reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-0"), clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		release(false)
+
+		_, err = reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-2"), clusterScope)
+		Expect(errors.Is(err, errProvisioningLimit)).To(BeTrue())
+	})
+
+	Context("When the machines belong to a MachineDeployment", func() {
+		var deployment *clusterv1.MachineDeployment
+
+		deploymentMachine := func(name string, state nico.InstanceStatus) *infrastructurev1.NcxInfraMachine {
+			machine := clusterMachine(name, state)
+			machine.Labels[clusterv1.MachineDeploymentNameLabel] = "md-0"
+			return machine
+		}
+		deploymentScopeOf := func(name string) *scope.MachineScope {
+			return &scope.MachineScope{NcxInfraMachine: deploymentMachine(name, "")}
+		}
+
+		BeforeEach(func() {
+			clusterScope.NcxInfraCluster.Spec.Provisioning = nil
+			maxUnavailable := intstr.FromString("50%")
+			deployment = &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "md-0", Namespace: "default"},
+				Spec: clusterv1.MachineDeploymentSpec{
+					ClusterName: "test-cluster",
+					Replicas:    ptr.To[int32](4),
+					Rollout: clusterv1.MachineDeploymentRolloutSpec{Strategy: clusterv1.MachineDeploymentRolloutStrategy{
+						Type:          clusterv1.RollingUpdateMachineDeploymentStrategyType,
+						RollingUpdate: clusterv1.MachineDeploymentRolloutStrategyRollingUpdate{MaxUnavailable: &maxUnavailable},
+					}},
+				},
+				// Rolling out: one of the four machines is up to date
+				Status: clusterv1.MachineDeploymentStatus{Replicas: ptr.To[int32](4), UpToDateReplicas: ptr.To[int32](1)},
+			}
+		})
+
+		buildReconciler := func() {
+			reconciler.Client = newFakeClientBuilder(newTestScheme()).WithObjects(
+				deployment,
+				deploymentMachine("md-0-a", nico.INSTANCESTATUS_PROVISIONING),
+				deploymentMachine("md-0-b", nico.INSTANCESTATUS_READY),
+				clusterMachine("worker-0", nico.INSTANCESTATUS_PROVISIONING),
+			).Build()
+		}
+
+		It("should provision at most maxUnavailable instances of the MachineDeployment during its rollout", func() {
+			buildReconciler()
+			release, err := reconciler.acquireProvisioningSlot(ctx, deploymentScopeOf("md-0-c"), clusterScope)
+			Expect(err).NotTo(HaveOccurred())
+			release(true)
+
+			_, err = reconciler.acquireProvisioningSlot(ctx, deploymentScopeOf("md-0-d"), clusterScope)
+			Expect(errors.Is(err, errProvisioningLimit)).To(BeTrue())
+			Expect(ncxinfraerrors.ReasonOf(err, ncxinfraerrors.InstanceCreateFailed)).
+				To(Equal(ncxinfraerrors.WaitingForProvisioningSlot))
+			Expect(err.Error()).To(ContainSubstring(
+				"2 instance(s) of MachineDeployment md-0 provisioning, at most 2 at once during its rollout"))
+
+			// The machines outside of the MachineDeployment are not limited by its rollout
+			release, err = reconciler.acquireProvisioningSlot(ctx, machineScopeOf("worker-1"), clusterScope)
+			Expect(err).NotTo(HaveOccurred())
+			release(false)
+		})
+
+		It("should provision one instance at a time when the rollout only surges", func() {
+			deployment.Spec.Rollout.Strategy.RollingUpdate.MaxUnavailable = nil
+			buildReconciler()
+			_, err := reconciler.acquireProvisioningSlot(ctx, deploymentScopeOf("md-0-c"), clusterScope)
+			Expect(err).To(MatchError(ContainSubstring("at most 1 at once during its rollout")))
+		})
+
+		It("should not limit a MachineDeployment that is not rolling out", func() {
+			deployment.Status.UpToDateReplicas = ptr.To[int32](4)
+			deployment.Spec.Rollout.Strategy.RollingUpdate.MaxUnavailable = nil
+			buildReconciler()
+			release, err := reconciler.acquireProvisioningSlot(ctx, deploymentScopeOf("md-0-c"), clusterScope)
+			Expect(err).NotTo(HaveOccurred())
+			release(false)
+		})
+
+		It("should keep maxConcurrent as the cap of the cluster", func() {
+			clusterScope.NcxInfraCluster.Spec.Provisioning = &infrastructurev1.ProvisioningSpec{MaxConcurrent: 2}
+			buildReconciler()
+			_, err := reconciler.acquireProvisioningSlot(ctx, deploymentScopeOf("md-0-c"), clusterScope)
+			Expect(err).To(MatchError(ContainSubstring("2 instance(s) of cluster test-cluster provisioning, at most 2 at once")))
+		})
+	})
+})
@@ -0,0 +1,184 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// bootstrapDataHash returns the hash of the bootstrap data recorded in status.provisioned.
+func bootstrapDataHash(bootstrapData string) string {
+	sum := sha256.Sum256([]byte(bootstrapData))
+	return hex.EncodeToString(sum[:])
+}
+
+// operatingSystemID returns the operating system the machine asks for, if any.
+func operatingSystemID(machine *infrastructurev1.NcxInfraMachine) string {
+	if machine.Spec.OperatingSystem == nil {
+		return ""
+	}
+	return machine.Spec.OperatingSystem.ID
+}
+
+// provisionedChanges lists what changed since the instance was provisioned.
+func provisionedChanges(provisioned *infrastructurev1.ProvisionedStatus, hash, osID string) []string {
+	var changes []string
+	if provisioned.BootstrapDataHash != hash {
+		changes = append(changes, "bootstrap data")
+	}
+	if provisioned.OperatingSystemID != osID {
+		changes = append(changes, fmt.Sprintf("operating system %q", osID))
+	}
+	return changes
+}
+
+// reconcileReprovision reimages a Ready instance in place, with the Reimage
+// reprovision policy, when its bootstrap data or operating system changed
+// since it was provisioned. Machines provisioned before status.provisioned
+// existed get it recorded from their current bootstrap data. It returns true
+// when the reconcile should requeue to observe or retry the reimage.
+func (r *NcxInfraMachineReconciler) reconcileReprovision(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+	instance *nico.Instance,
+) (bool, error) {
+	logger := log.FromContext(ctx)
+	machine := machineScope.NcxInfraMachine
+
+	provisioned := machine.Status.Provisioned
+	if provisioned != nil && machine.Spec.ReprovisionPolicy != infrastructurev1.ReprovisionPolicyReimage {
+		return false, nil
+	}
+
+	bootstrapData, format, err := machineScope.GetBootstrapDataWithFormat(ctx)
+	if err != nil {
+		logger.Info("Failed to get the bootstrap data to check for changes", "error", err.Error())
+		return false, nil
+	}
+	hash := bootstrapDataHash(bootstrapData)
+	osID := operatingSystemID(machine)
+	if provisioned == nil {
+		machine.Status.Provisioned = &infrastructurev1.ProvisionedStatus{BootstrapDataHash: hash, OperatingSystemID: osID}
+		return false, nil
+	}
+
+	changes := provisionedChanges(provisioned, hash, osID)
+	if len(changes) == 0 {
+		return false, nil
+	}
+	// Reimage once the current provisioning is done
+	if instance.Status == nil || string(*instance.Status) != "Ready" {
+		return false, nil
+	}
+	if err := checkBootstrapFormat(machine, format); err != nil {
+		return false, err
+	}
+
+	// Wait for a provisioning slot of the cluster, the reimage provisions the machine again
+	release, err := r.acquireProvisioningSlot(ctx, machineScope, clusterScope)
+	if errors.Is(err, errProvisioningLimit) {
+		logger.Info("Waiting for a provisioning slot of the cluster to reimage the instance", "reason", err.Error())
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	reimaged := false
+	defer func() { release(reimaged) }()
+
+	updateReq, err := r.reimageRequest(ctx, machineScope, clusterScope, bootstrapData)
+	if err != nil {
+		return false, err
+	}
+
+	updateStart := time.Now()
+	updated, httpResp, err := machineScope.NcxInfraClient.UpdateInstance(
+		ctx, machineScope.OrgName, machineScope.InstanceID(), updateReq)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "UpdateInstance")
+	recordAPIMetrics("UpdateInstance", updateStart, apiErr)
+	if apiErr != nil && apiErr.IsTransient() {
+		logger.Info("Transient error reimaging instance, will retry", "error", apiErr.Message)
+		return true, nil
+	}
+	if apiErr != nil {
+		r.recordEvent(machine, corev1.EventTypeWarning, "InstanceReimageFailed",
+			"Failed to reimage instance %s: %s", machineScope.InstanceID(), apiErr.Message)
+		return false, apiErr
+	}
+
+	reimaged = true
+	now := metav1.Now()
+	machine.Status.Provisioned = &infrastructurev1.ProvisionedStatus{
+		BootstrapDataHash: hash,
+		OperatingSystemID: osID,
+		Reimages:          provisioned.Reimages + 1,
+		LastReimageTime:   &now,
+	}
+	if updated != nil && updated.Status != nil {
+		machineScope.SetInstanceState(string(*updated.Status))
+	}
+	logger.Info("Reimaging instance", "instanceID", machineScope.InstanceID(), "changes", changes)
+	r.recordEvent(machine, corev1.EventTypeNormal, "InstanceReimaged",
+		"Reimaging instance %s on machine %s for new %s", machineScope.InstanceID(),
+		machineScope.MachineID(), strings.Join(changes, " and "))
+	return true, nil
+}
+
+// reimageRequest returns the request reinstalling the operating system of the
+// instance of a machine, with its bootstrap data rendered as for a new
+// instance on the same machine.
+func (r *NcxInfraMachineReconciler) reimageRequest(
+	ctx context.Context,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+	bootstrapData string,
+) (nico.InstanceUpdateRequest, error) {
+	createReq := nico.InstanceCreateRequest{UserData: *nico.NewNullableString(&bootstrapData)}
+	r.applyOptionalInstanceFields(machineScope, &createReq)
+	if machineID := machineScope.MachineID(); machineID != "" {
+		createReq.MachineId = &machineID
+	}
+	var siteID string
+	if providerID := machineScope.ProviderID(); providerID != nil {
+		siteID = providerID.SiteName
+	}
+	if err := r.applyUserData(ctx, machineScope, clusterScope, siteID, &createReq); err != nil {
+		return nico.InstanceUpdateRequest{}, err
+	}
+	// Booting with the custom iPXE script reinstalls the operating system
+	return nico.InstanceUpdateRequest{
+		OperatingSystemId:    createReq.OperatingSystemId,
+		UserData:             createReq.UserData,
+		TriggerReboot:        *nico.NewNullableBool(nico.PtrBool(true)),
+		RebootWithCustomIpxe: *nico.NewNullableBool(nico.PtrBool(true)),
+	}, nil
+}
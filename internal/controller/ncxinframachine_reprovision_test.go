@@ -0,0 +1,162 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("Reprovisioning", func() {
+	const bootstrapData = "#cloud-config\nruncmd:\n  - echo hello"
+
+	var (
+		ctx          context.Context
+		updates      []nico.InstanceUpdateRequest
+		instance     *nico.Instance
+		machineScope *scope.MachineScope
+		clusterScope *scope.ClusterScope
+		recorder     *record.FakeRecorder
+		reconciler   *NcxInfraMachineReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		updates = nil
+		instance = &nico.Instance{Id: testutil.Ptr("instance-uuid"), Status: nico.INSTANCESTATUS_READY.Ptr()}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "bootstrap", Namespace: "default"},
+			Data:       map[string][]byte{"value": []byte(bootstrapData)},
+		}
+		machineScope = &scope.MachineScope{
+			Client: newFakeClientBuilder(newTestScheme()).WithObjects(secret).Build(),
+			Machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default"},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{DataSecretName: testutil.Ptr("bootstrap")},
+				},
+			},
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default"},
+				Spec: infrastructurev1.NcxInfraMachineSpec{
+					ReprovisionPolicy: infrastructurev1.ReprovisionPolicyReimage,
+					OperatingSystem:   &infrastructurev1.OSSpec{ID: "os-v2"},
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{
+					InstanceID: "instance-uuid",
+					MachineID:  "machine-1",
+					Provisioned: &infrastructurev1.ProvisionedStatus{
+						BootstrapDataHash: bootstrapDataHash(bootstrapData),
+						OperatingSystemID: "os-v1",
+					},
+				},
+			},
+			NcxInfraClient: &testutil.MockNcxInfraClient{
+				UpdateInstanceFunc: func(
+					ctx context.Context, org, instanceId string, req nico.InstanceUpdateRequest,
+				) (*nico.Instance, *http.Response, error) {
+					updates = append(updates, req)
+					return &nico.Instance{Status: nico.INSTANCESTATUS_PROVISIONING.Ptr()},
+						testutil.MockHTTPResponse(http.StatusOK), nil
+				},
+			},
+			OrgName: "test-org",
+		}
+		clusterScope = &scope.ClusterScope{NcxInfraCluster: &infrastructurev1.NcxInfraCluster{}}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &NcxInfraMachineReconciler{Recorder: recorder}
+	})
+
+	It("should reimage the instance on a new operating system", func() {
+		reimaging, err := reconciler.reconcileReprovision(ctx, machineScope, clusterScope, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reimaging).To(BeTrue())
+
+		Expect(updates).To(HaveLen(1))
+		Expect(updates[0].OperatingSystemId.Get()).To(HaveValue(Equal("os-v2")))
+		Expect(updates[0].UserData.Get()).To(HaveValue(ContainSubstring("echo hello")))
+		Expect(updates[0].TriggerReboot.Get()).To(HaveValue(BeTrue()))
+
+		provisioned := machineScope.NcxInfraMachine.Status.Provisioned
+		Expect(provisioned.OperatingSystemID).To(Equal("os-v2"))
+		Expect(provisioned.Reimages).To(Equal(int32(1)))
+		Expect(provisioned.LastReimageTime).NotTo(BeNil())
+		Expect(machineScope.NcxInfraMachine.Status.InstanceState).To(Equal("Provisioning"))
+		Expect(recorder.Events).To(Receive(ContainSubstring(`InstanceReimaged Reimaging instance instance-uuid on machine machine-1 for new operating system "os-v2"`)))
+
+		reimaging, err = reconciler.reconcileReprovision(ctx, machineScope, clusterScope, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reimaging).To(BeFalse())
+		Expect(updates).To(HaveLen(1))
+	})
+
+	It("should reimage the instance on new bootstrap data", func() {
+		machineScope.NcxInfraMachine.Status.Provisioned.OperatingSystemID = "os-v2"
+		machineScope.NcxInfraMachine.Status.Provisioned.BootstrapDataHash = bootstrapDataHash("#cloud-config")
+
+		reimaging, err := reconciler.reconcileReprovision(ctx, machineScope, clusterScope, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reimaging).To(BeTrue())
+		Expect(updates).To(HaveLen(1))
+		Expect(machineScope.NcxInfraMachine.Status.Provisioned.BootstrapDataHash).To(Equal(bootstrapDataHash(bootstrapData)))
+	})
+
+	It("should wait for the instance to be Ready", func() {
+		instance.Status = nico.INSTANCESTATUS_PROVISIONING.Ptr()
+
+		reimaging, err := reconciler.reconcileReprovision(ctx, machineScope, clusterScope, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reimaging).To(BeFalse())
+		Expect(updates).To(BeEmpty())
+	})
+
+	It("should not reimage without the Reimage policy", func() {
+		machineScope.NcxInfraMachine.Spec.ReprovisionPolicy = infrastructurev1.ReprovisionPolicyNone
+
+		reimaging, err := reconciler.reconcileReprovision(ctx, machineScope, clusterScope, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reimaging).To(BeFalse())
+		Expect(updates).To(BeEmpty())
+		Expect(machineScope.NcxInfraMachine.Status.Provisioned.OperatingSystemID).To(Equal("os-v1"))
+	})
+
+	It("should record what a machine provisioned earlier was provisioned with", func() {
+		machineScope.NcxInfraMachine.Spec.ReprovisionPolicy = ""
+		machineScope.NcxInfraMachine.Status.Provisioned = nil
+
+		reimaging, err := reconciler.reconcileReprovision(ctx, machineScope, clusterScope, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reimaging).To(BeFalse())
+		Expect(updates).To(BeEmpty())
+		Expect(machineScope.NcxInfraMachine.Status.Provisioned).To(Equal(&infrastructurev1.ProvisionedStatus{
+			BootstrapDataHash: bootstrapDataHash(bootstrapData),
+			OperatingSystemID: "os-v2",
+		}))
+	})
+})
@@ -0,0 +1,216 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	capierrors "sigs.k8s.io/cluster-api/errors" //nolint:staticcheck // required for CAPI contract FailureReason types
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// securityLabelRequired is the value of the security requirement labels.
+const securityLabelRequired = "required"
+
+// securityLabels returns the instance labels passing the security requirements
+// to NVIDIA Carbide.
+func securityLabels(security *infrastructurev1.SecuritySpec) map[string]string {
+	if security == nil {
+		return nil
+	}
+	labels := map[string]string{}
+	if security.RequireTPM || security.MeasuredBoot {
+		labels[infrastructurev1.SecurityTPMLabel] = securityLabelRequired
+	}
+	if security.SecureBoot {
+		labels[infrastructurev1.SecuritySecureBootLabel] = securityLabelRequired
+	}
+	if security.MeasuredBoot {
+		labels[infrastructurev1.SecurityMeasuredBootLabel] = securityLabelRequired
+	}
+	if security.BootMode != "" {
+		labels[infrastructurev1.SecurityBootModeLabel] = strings.ToLower(string(security.BootMode))
+	}
+	return labels
+}
+
+// FirmwareCompatibleCondition reports whether the firmware of the NVIDIA
+// Carbide machine of the instance can meet its security requirements.
+const FirmwareCompatibleCondition clusterv1.ConditionType = "FirmwareCompatible"
+
+// firmwareMismatch returns the condition reason and message of the first
+// security requirement that the firmware of a machine, as described by its
+// labels, cannot meet. It returns an empty reason when the machine is
+// compatible.
+func firmwareMismatch(security *infrastructurev1.SecuritySpec, labels map[string]string) (string, string) {
+	if security == nil {
+		return "", ""
+	}
+	if (security.RequireTPM || security.MeasuredBoot) && labels[infrastructurev1.FirmwareTPMLabel] == "false" {
+		return "TPMUnavailable", "the machine has no TPM"
+	}
+	if (security.SecureBoot || security.MeasuredBoot) && labels[infrastructurev1.FirmwareSecureBootLabel] == "false" {
+		return "SecureBootUnsupported", "the firmware of the machine does not support secure boot"
+	}
+	if modes, ok := labels[infrastructurev1.FirmwareBootModeLabel]; ok && security.BootMode != "" {
+		if !slices.Contains(strings.Split(modes, "-"), strings.ToLower(string(security.BootMode))) {
+			return "BootModeUnsupported", fmt.Sprintf("the firmware of the machine boots in %s mode, not %s",
+				modes, security.BootMode)
+		}
+	}
+	return "", ""
+}
+
+// checkMachineFirmware checks the security requirements of the machine against
+// the firmware of a NVIDIA Carbide machine, and reports the result in the
+// FirmwareCompatible condition. Machines without security requirements are not
+// checked.
+func (r *NcxInfraMachineReconciler) checkMachineFirmware(
+	ctx context.Context, machineScope *scope.MachineScope, machineID string,
+) error {
+	machine := machineScope.NcxInfraMachine
+	if len(securityLabels(machine.Spec.Security)) == 0 {
+		return nil
+	}
+
+	getStart := time.Now()
+	carbideMachine, httpResp, err := machineScope.NcxInfraClient.GetMachine(ctx, machineScope.OrgName, machineID)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetMachine")
+	recordAPIMetrics("GetMachine", getStart, apiErr)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	if reason, message := firmwareMismatch(machine.Spec.Security, carbideMachine.Labels); reason != "" {
+		message = fmt.Sprintf("Machine %s cannot meet the security requirements: %s", machineID, message)
+		if !conditions.IsFalse(machine, string(FirmwareCompatibleCondition)) {
+			r.recordEvent(machine, corev1.EventTypeWarning, reason, "%s", message)
+		}
+		conditions.Set(machine, metav1.Condition{
+			Type:    string(FirmwareCompatibleCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
+		})
+		return errors.New(message)
+	}
+	conditions.Set(machine, metav1.Condition{
+		Type:    string(FirmwareCompatibleCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  "FirmwareCompatible",
+		Message: fmt.Sprintf("Machine %s can meet the security requirements", machineID),
+	})
+	return nil
+}
+
+// verifyInstanceFirmware checks the firmware of the machine NVIDIA Carbide
+// picked for the instance, when it was not known before creation. A machine
+// that cannot meet the security requirements fails the NcxInfraMachine, so
+// that it gets replaced. It returns false while the machine is not verified.
+func (r *NcxInfraMachineReconciler) verifyInstanceFirmware(
+	ctx context.Context, machineScope *scope.MachineScope, instance *nico.Instance,
+) bool {
+	machine := machineScope.NcxInfraMachine
+	if conditions.IsTrue(machine, string(FirmwareCompatibleCondition)) || instance.GetMachineId() == "" {
+		return true
+	}
+	if err := r.checkMachineFirmware(ctx, machineScope, instance.GetMachineId()); err != nil {
+		if conditions.IsFalse(machine, string(FirmwareCompatibleCondition)) {
+			setMachineFailure(machine, capierrors.CreateMachineError, err.Error())
+		}
+		machineScope.SetReady(false)
+		return false
+	}
+	return true
+}
+
+// verifyAttestation checks the security requirements of the machine against
+// the ready instance and reports them in the AttestationVerified condition.
+// It returns false while the requirements are not met, keeping the machine
+// from becoming Ready.
+func (r *NcxInfraMachineReconciler) verifyAttestation(machineScope *scope.MachineScope, instance *nico.Instance) bool {
+	machine := machineScope.NcxInfraMachine
+	labels := securityLabels(machine.Spec.Security)
+	if len(labels) == 0 {
+		return true
+	}
+
+	if labels[infrastructurev1.SecurityTPMLabel] != "" {
+		if err := verifyEKCertificate(instance.TpmEkCertificate.Get()); err != nil {
+			if !conditions.IsFalse(machine, string(AttestationVerifiedCondition)) {
+				r.recordEvent(machine, corev1.EventTypeWarning, "AttestationFailed",
+					"Instance %s does not meet the security requirements: %v", machineScope.InstanceID(), err)
+			}
+			conditions.Set(machine, metav1.Condition{
+				Type:    string(AttestationVerifiedCondition),
+				Status:  metav1.ConditionFalse,
+				Reason:  "TPMNotVerified",
+				Message: err.Error(),
+			})
+			machineScope.SetReady(false)
+			return false
+		}
+	}
+
+	message := fmt.Sprintf("Instance %s labeled with the required %s", machineScope.InstanceID(),
+		strings.Join(sortedKeys(labels), ", "))
+	if labels[infrastructurev1.SecurityTPMLabel] != "" {
+		message += ", TPM endorsement key certificate verified"
+	}
+	conditions.Set(machine, metav1.Condition{
+		Type:    string(AttestationVerifiedCondition),
+		Status:  metav1.ConditionTrue,
+		Reason:  "Verified",
+		Message: message,
+	})
+	return true
+}
+
+// verifyEKCertificate checks that the TPM endorsement key certificate reported
+// for an instance is a valid X.509 certificate. NVIDIA Carbide encodes it in
+// base64, either as PEM or DER.
+func verifyEKCertificate(encoded *string) error {
+	if encoded == nil || *encoded == "" {
+		return fmt.Errorf("no TPM endorsement key certificate reported")
+	}
+	der, err := base64.StdEncoding.DecodeString(*encoded)
+	if err != nil {
+		return fmt.Errorf("invalid TPM endorsement key certificate encoding: %w", err)
+	}
+	if block, _ := pem.Decode(der); block != nil {
+		der = block.Bytes
+	}
+	if _, err := x509.ParseCertificate(der); err != nil {
+		return fmt.Errorf("invalid TPM endorsement key certificate: %w", err)
+	}
+	return nil
+}
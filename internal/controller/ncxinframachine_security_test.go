@@ -0,0 +1,214 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// testEKCertificate returns a self-signed certificate encoded the way NVIDIA
+// Carbide reports TPM endorsement key certificates.
+func testEKCertificate() string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "tpm-ek"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+var _ = Describe("verifyAttestation", func() {
+	var (
+		machineScope *scope.MachineScope
+		instance     *nico.Instance
+		reconciler   *NcxInfraMachineReconciler
+	)
+
+	BeforeEach(func() {
+		machineScope = &scope.MachineScope{
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default"},
+				Spec: infrastructurev1.NcxInfraMachineSpec{
+					Security: &infrastructurev1.SecuritySpec{MeasuredBoot: true, SecureBoot: true},
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{InstanceID: "instance-uuid"},
+			},
+		}
+		instance = &nico.Instance{}
+		reconciler = &NcxInfraMachineReconciler{}
+	})
+
+	It("should not gate machines without security requirements", func() {
+		machineScope.NcxInfraMachine.Spec.Security = nil
+
+		Expect(reconciler.verifyAttestation(machineScope, instance)).To(BeTrue())
+		Expect(conditions.Get(machineScope.NcxInfraMachine, string(AttestationVerifiedCondition))).To(BeNil())
+	})
+
+	It("should hold the machine back until the instance reports a TPM", func() {
+		machineScope.SetReady(true)
+
+		Expect(reconciler.verifyAttestation(machineScope, instance)).To(BeFalse())
+		Expect(machineScope.NcxInfraMachine.Status.Ready).To(BeFalse())
+		condition := conditions.Get(machineScope.NcxInfraMachine, string(AttestationVerifiedCondition))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("TPMNotVerified"))
+		Expect(setMachineReadyCondition(machineScope.NcxInfraMachine)).To(Succeed())
+		Expect(conditions.IsFalse(machineScope.NcxInfraMachine, string(clusterv1.ReadyCondition))).To(BeTrue())
+	})
+
+	It("should reject an invalid endorsement key certificate", func() {
+		instance.TpmEkCertificate = *nico.NewNullableString(testutil.Ptr(base64.StdEncoding.EncodeToString([]byte("not a certificate"))))
+
+		Expect(reconciler.verifyAttestation(machineScope, instance)).To(BeFalse())
+		condition := conditions.Get(machineScope.NcxInfraMachine, string(AttestationVerifiedCondition))
+		Expect(condition.Message).To(ContainSubstring("invalid TPM endorsement key certificate"))
+	})
+
+	It("should verify the endorsement key certificate", func() {
+		instance.TpmEkCertificate = *nico.NewNullableString(testutil.Ptr(testEKCertificate()))
+
+		Expect(reconciler.verifyAttestation(machineScope, instance)).To(BeTrue())
+		Expect(conditions.IsTrue(machineScope.NcxInfraMachine, string(AttestationVerifiedCondition))).To(BeTrue())
+	})
+})
+
+var _ = Describe("securityLabels", func() {
+	It("should require a TPM for measured boot", func() {
+		labels := securityLabels(&infrastructurev1.SecuritySpec{MeasuredBoot: true})
+		Expect(labels).To(Equal(map[string]string{
+			infrastructurev1.SecurityTPMLabel:          "required",
+			infrastructurev1.SecurityMeasuredBootLabel: "required",
+		}))
+	})
+
+	It("should pass the boot mode in lower case", func() {
+		labels := securityLabels(&infrastructurev1.SecuritySpec{BootMode: infrastructurev1.BootModeLegacy})
+		Expect(labels).To(Equal(map[string]string{infrastructurev1.SecurityBootModeLabel: "legacy"}))
+	})
+
+	It("should return no labels without requirements", func() {
+		Expect(securityLabels(nil)).To(BeEmpty())
+		Expect(securityLabels(&infrastructurev1.SecuritySpec{})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Firmware compatibility", func() {
+	var (
+		ctx           context.Context
+		machineScope  *scope.MachineScope
+		reconciler    *NcxInfraMachineReconciler
+		machineLabels map[string]string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		machineLabels = map[string]string{
+			infrastructurev1.FirmwareTPMLabel:        "true",
+			infrastructurev1.FirmwareSecureBootLabel: "true",
+			infrastructurev1.FirmwareBootModeLabel:   "uefi-legacy",
+		}
+		machineScope = &scope.MachineScope{
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default"},
+				Spec: infrastructurev1.NcxInfraMachineSpec{
+					Security: &infrastructurev1.SecuritySpec{
+						SecureBoot: true, RequireTPM: true, BootMode: infrastructurev1.BootModeUEFI,
+					},
+				},
+			},
+			NcxInfraClient: &testutil.MockNcxInfraClient{
+				GetMachineFunc: func(ctx context.Context, org, machineId string) (*nico.Machine, *http.Response, error) {
+					return &nico.Machine{Id: testutil.Ptr(machineId), Labels: machineLabels},
+						testutil.MockHTTPResponse(http.StatusOK), nil
+				},
+			},
+			OrgName: "test-org",
+		}
+		reconciler = &NcxInfraMachineReconciler{Recorder: record.NewFakeRecorder(10)}
+	})
+
+	It("should accept a machine that meets the requirements", func() {
+		Expect(reconciler.checkMachineFirmware(ctx, machineScope, "machine-1")).To(Succeed())
+		Expect(conditions.IsTrue(machineScope.NcxInfraMachine, string(FirmwareCompatibleCondition))).To(BeTrue())
+	})
+
+	It("should assume unlabeled machines are compatible", func() {
+		machineLabels = nil
+		Expect(reconciler.checkMachineFirmware(ctx, machineScope, "machine-1")).To(Succeed())
+	})
+
+	DescribeTable("should reject a machine that cannot meet the requirements",
+		func(label, value, reason string) {
+			machineLabels[label] = value
+			Expect(reconciler.checkMachineFirmware(ctx, machineScope, "machine-1")).NotTo(Succeed())
+			condition := conditions.Get(machineScope.NcxInfraMachine, string(FirmwareCompatibleCondition))
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(reason))
+			Expect(condition.Message).To(ContainSubstring("machine-1"))
+		},
+		Entry("without a TPM", infrastructurev1.FirmwareTPMLabel, "false", "TPMUnavailable"),
+		Entry("without secure boot", infrastructurev1.FirmwareSecureBootLabel, "false", "SecureBootUnsupported"),
+		Entry("in legacy boot mode", infrastructurev1.FirmwareBootModeLabel, "legacy", "BootModeUnsupported"),
+	)
+
+	It("should fail the machine when the allocated machine is incompatible", func() {
+		machineLabels[infrastructurev1.FirmwareSecureBootLabel] = "false"
+		instance := &nico.Instance{MachineId: *nico.NewNullableString(testutil.Ptr("machine-1"))}
+
+		Expect(reconciler.verifyInstanceFirmware(ctx, machineScope, instance)).To(BeFalse())
+		Expect(machineScope.NcxInfraMachine.Status.Ready).To(BeFalse())
+		Expect(machineScope.NcxInfraMachine.Status.FailureMessage).NotTo(BeNil())
+		Expect(*machineScope.NcxInfraMachine.Status.FailureMessage).To(ContainSubstring("secure boot"))
+	})
+
+	It("should not check machines without security requirements", func() {
+		machineScope.NcxInfraMachine.Spec.Security = nil
+		instance := &nico.Instance{MachineId: *nico.NewNullableString(testutil.Ptr("machine-1"))}
+
+		Expect(reconciler.verifyInstanceFirmware(ctx, machineScope, instance)).To(BeTrue())
+		Expect(conditions.Get(machineScope.NcxInfraMachine, string(FirmwareCompatibleCondition))).To(BeNil())
+	})
+})
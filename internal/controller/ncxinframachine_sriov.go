@@ -0,0 +1,142 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// sriovInterfaces attaches a subnet to the virtual functions of an interface
+// with SR-IOV.
+func sriovInterfaces(subnetID string, sriov *infrastructurev1.SRIOVSpec) []nico.InterfaceCreateRequest {
+	interfaces := make([]nico.InterfaceCreateRequest, 0, sriov.NumVFs)
+	for vf := range sriov.NumVFs {
+		ifReq := nico.InterfaceCreateRequest{
+			SubnetId:          &subnetID,
+			IsPhysical:        ptr.To(false),
+			DeviceInstance:    sriov.DeviceInstance,
+			VirtualFunctionId: *nico.NewNullableInt32(ptr.To(vf)),
+		}
+		if sriov.Device != "" {
+			ifReq.Device = ptr.To(sriov.Device)
+		}
+		interfaces = append(interfaces, ifReq)
+	}
+	return interfaces
+}
+
+// updateSRIOVStatus reports the virtual functions NVIDIA Carbide allocated to
+// the interfaces with SR-IOV, and the PCI address of their physical function
+// from the inventory of the machine. It does nothing once they are reported,
+// and until NVIDIA Carbide reports the virtual functions of every interface.
+func (r *NcxInfraMachineReconciler) updateSRIOVStatus(
+	ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope, instance *nico.Instance,
+) {
+	machine := machineScope.NcxInfraMachine
+	if len(machine.Status.SRIOVInterfaces) > 0 {
+		return
+	}
+	var specs []infrastructurev1.NetworkInterface
+	for _, iface := range machine.Spec.Network.AdditionalInterfaces {
+		if iface.SRIOV != nil {
+			specs = append(specs, iface)
+		}
+	}
+	if len(specs) == 0 {
+		return
+	}
+
+	var networkInterfaces []nico.MachineNetworkInterface
+	if machineID := machineScope.MachineID(); machineID != "" {
+		getStart := time.Now()
+		carbideMachine, httpResp, err := machineScope.NcxInfraClient.GetMachine(ctx, machineScope.OrgName, machineID)
+		apiErr := scope.ClassifyAPIError(httpResp, err, "GetMachine")
+		recordAPIMetrics("GetMachine", getStart, apiErr)
+		if apiErr != nil {
+			log.FromContext(ctx).Info("Failed to get the machine of the SR-IOV interfaces, will retry",
+				"machineID", machineID, "error", apiErr.Message)
+			return
+		}
+		if carbideMachine.Metadata != nil {
+			networkInterfaces = carbideMachine.Metadata.NetworkInterfaces
+		}
+	}
+
+	referenced, err := r.referencedClusters(ctx, machineScope)
+	if err != nil {
+		log.FromContext(ctx).Info("Failed to get the clusters of the SR-IOV interfaces, will retry", "error", err.Error())
+		return
+	}
+	statuses := make([]infrastructurev1.SRIOVInterfaceStatus, 0, len(specs))
+	for _, iface := range specs {
+		netStatus := interfaceNetworkStatus(iface, clusterScope.NcxInfraCluster.Status.NetworkStatus, referenced)
+		subnetID := netStatus.SubnetID(iface.SubnetName)
+		status := infrastructurev1.SRIOVInterfaceStatus{
+			SubnetName: iface.SubnetName,
+			Device:     iface.SRIOV.Device,
+			Trust:      iface.SRIOV.Trust,
+			VLAN:       iface.SRIOV.VLAN,
+		}
+		for _, allocated := range instance.Interfaces {
+			vf := allocated.VirtualFunctionId.Get()
+			if vf == nil || allocated.SubnetId.Get() == nil || *allocated.SubnetId.Get() != subnetID {
+				continue
+			}
+			if status.Device == "" {
+				status.Device = ptr.Deref(allocated.Device.Get(), "")
+			}
+			status.VirtualFunctions = append(status.VirtualFunctions, infrastructurev1.VirtualFunctionStatus{
+				ID:          *vf,
+				MACAddress:  ptr.Deref(allocated.MacAddress.Get(), ""),
+				IPAddresses: allocated.IpAddresses,
+			})
+		}
+		if len(status.VirtualFunctions) == 0 {
+			return
+		}
+		status.PCIAddress = physicalFunctionAddress(networkInterfaces, status.Device, ptr.Deref(iface.SRIOV.DeviceInstance, 0))
+		statuses = append(statuses, status)
+	}
+	machine.Status.SRIOVInterfaces = statuses
+}
+
+// physicalFunctionAddress returns the PCI address of the instance of a
+// network device of a machine, the instances being in the order of their PCI
+// addresses.
+func physicalFunctionAddress(networkInterfaces []nico.MachineNetworkInterface, device string, instance int32) string {
+	var slots []string
+	for _, iface := range networkInterfaces {
+		if device != "" && iface.GetDevice() == device && iface.GetSlot() != "" {
+			slots = append(slots, iface.GetSlot())
+		}
+	}
+	slices.Sort(slots)
+	slots = slices.Compact(slots)
+	if int(instance) >= len(slots) {
+		return ""
+	}
+	return slots[instance]
+}
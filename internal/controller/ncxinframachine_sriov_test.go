@@ -0,0 +1,123 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("SR-IOV interfaces", func() {
+	var (
+		ctx          context.Context
+		machineScope *scope.MachineScope
+		clusterScope *scope.ClusterScope
+		reconciler   *NcxInfraMachineReconciler
+		sriov        *infrastructurev1.SRIOVSpec
+	)
+
+	vf := func(id int32, mac, ip string) nico.Interface {
+		return nico.Interface{
+			SubnetId:          *nico.NewNullableString(testutil.Ptr("dpdk-subnet-uuid")),
+			IsPhysical:        testutil.Ptr(false),
+			Device:            *nico.NewNullableString(testutil.Ptr("BlueField3")),
+			VirtualFunctionId: *nico.NewNullableInt32(testutil.Ptr(id)),
+			MacAddress:        *nico.NewNullableString(testutil.Ptr(mac)),
+			IpAddresses:       []string{ip},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		sriov = &infrastructurev1.SRIOVSpec{Device: "BlueField3", DeviceInstance: testutil.Ptr(int32(1)), NumVFs: 2, VLAN: 100}
+		machineScope = &scope.MachineScope{
+			NcxInfraMachine: &infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default"},
+				Spec: infrastructurev1.NcxInfraMachineSpec{
+					Network: infrastructurev1.NetworkSpec{
+						SubnetName:           "nodes",
+						AdditionalInterfaces: []infrastructurev1.NetworkInterface{{SubnetName: "dpdk", SRIOV: sriov}},
+					},
+				},
+				Status: infrastructurev1.NcxInfraMachineStatus{MachineID: "machine-1"},
+			},
+			NcxInfraClient: &testutil.MockNcxInfraClient{
+				GetMachineFunc: func(ctx context.Context, org, machineId string) (*nico.Machine, *http.Response, error) {
+					return &nico.Machine{Metadata: &nico.MachineMetadata{NetworkInterfaces: []nico.MachineNetworkInterface{
+						{Device: testutil.Ptr("ConnectX-7"), Slot: testutil.Ptr("0000:17:00.0")},
+						{Device: testutil.Ptr("BlueField3"), Slot: testutil.Ptr("0000:c1:00.0")},
+						{Device: testutil.Ptr("BlueField3"), Slot: testutil.Ptr("0000:41:00.0")},
+					}}}, testutil.MockHTTPResponse(http.StatusOK), nil
+				},
+			},
+			OrgName: "test-org",
+		}
+		clusterScope = &scope.ClusterScope{NcxInfraCluster: &infrastructurev1.NcxInfraCluster{
+			Status: infrastructurev1.NcxInfraClusterStatus{NetworkStatus: infrastructurev1.NetworkStatus{
+				Subnets: []infrastructurev1.NetworkResourceStatus{{Name: "dpdk", ID: "dpdk-subnet-uuid"}},
+			}},
+		}}
+		scheme := newTestScheme()
+		reconciler = &NcxInfraMachineReconciler{Client: newFakeClientBuilder(scheme).Build(), Scheme: scheme}
+	})
+
+	It("should attach the subnet to each virtual function of the device", func() {
+		interfaces := sriovInterfaces("dpdk-subnet-uuid", sriov)
+		Expect(interfaces).To(HaveLen(2))
+		for i, iface := range interfaces {
+			Expect(*iface.SubnetId).To(Equal("dpdk-subnet-uuid"))
+			Expect(*iface.IsPhysical).To(BeFalse())
+			Expect(*iface.Device).To(Equal("BlueField3"))
+			Expect(*iface.DeviceInstance).To(Equal(int32(1)))
+			Expect(*iface.VirtualFunctionId.Get()).To(Equal(int32(i)))
+		}
+	})
+
+	It("should report the virtual functions and the address of their physical function", func() {
+		instance := &nico.Instance{Interfaces: []nico.Interface{
+			{SubnetId: *nico.NewNullableString(testutil.Ptr("nodes-subnet-uuid")), IpAddresses: []string{"10.0.0.5"}},
+			vf(0, "02:00:00:00:00:01", "10.1.0.5"),
+			vf(1, "02:00:00:00:00:02", "10.1.0.6"),
+		}}
+
+		reconciler.updateSRIOVStatus(ctx, machineScope, clusterScope, instance)
+		Expect(machineScope.NcxInfraMachine.Status.SRIOVInterfaces).To(Equal([]infrastructurev1.SRIOVInterfaceStatus{{
+			SubnetName: "dpdk",
+			Device:     "BlueField3",
+			PCIAddress: "0000:c1:00.0",
+			VLAN:       100,
+			VirtualFunctions: []infrastructurev1.VirtualFunctionStatus{
+				{ID: 0, MACAddress: "02:00:00:00:00:01", IPAddresses: []string{"10.1.0.5"}},
+				{ID: 1, MACAddress: "02:00:00:00:00:02", IPAddresses: []string{"10.1.0.6"}},
+			},
+		}}))
+	})
+
+	It("should wait for NVIDIA Carbide to report the virtual functions", func() {
+		reconciler.updateSRIOVStatus(ctx, machineScope, clusterScope, &nico.Instance{})
+		Expect(machineScope.NcxInfraMachine.Status.SRIOVInterfaces).To(BeEmpty())
+	})
+})
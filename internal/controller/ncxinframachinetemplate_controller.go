@@ -0,0 +1,271 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// GPUResourceName is the extended resource advertised by the NVIDIA device plugin.
+const GPUResourceName corev1.ResourceName = "nvidia.com/gpu"
+
+// NcxInfraMachineTemplateReconciler reports the capacity of the machines created
+// from a NcxInfraMachineTemplate, so the cluster autoscaler can scale
+// MachineDeployments from zero.
+type NcxInfraMachineTemplateReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// NcxInfraClient can be set for testing or simulation mode to inject a client
+	NcxInfraClient scope.NcxInfraClientInterface
+	// OrgName can be set for testing or simulation mode
+	OrgName string
+
+	// DefaultCredentials is the credentials secret of the objects that do not
+	// reference one, when their namespace has no default NcxInfraIdentity
+	DefaultCredentials corev1.SecretReference
+
+	// WatchFilterValue is the value of the cluster.x-k8s.io/watch-filter label
+	// of the objects to reconcile, all of them when empty
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinframachinetemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinframachinetemplates/status,verbs=get;update;patch
+
+// Reconcile handles NcxInfraMachineTemplate reconciliation
+func (r *NcxInfraMachineTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	template := &infrastructurev1.NcxInfraMachineTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, template); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !template.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// MachineDeployments add their Cluster as owner of the template, while
+	// ClusterClass based templates carry the cluster name label
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, template.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cluster == nil {
+		if _, ok := template.Labels[clusterv1.ClusterNameLabel]; !ok {
+			logger.Info("Waiting for NcxInfraMachineTemplate to be used by a Cluster")
+			return ctrl.Result{}, nil
+		}
+		cluster, err = util.GetClusterFromMetadata(ctx, r.Client, template.ObjectMeta)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	logger = logger.WithValues("cluster", cluster.Name)
+	ctx = log.IntoContext(ctx, logger)
+
+	if annotations.IsPaused(cluster, template) {
+		logger.Info("NcxInfraMachineTemplate or Cluster is marked as paused, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
+	nvidiaCarbideCluster := &infrastructurev1.NcxInfraCluster{}
+	nvidiaCarbideClusterKey := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Get(ctx, nvidiaCarbideClusterKey, nvidiaCarbideCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Waiting for NcxInfraCluster to be created")
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:             r.Client,
+		Cluster:            cluster,
+		NcxInfraCluster:    nvidiaCarbideCluster,
+		NcxInfraClient:     r.NcxInfraClient,
+		OrgName:            r.OrgName,
+		DefaultCredentials: r.DefaultCredentials,
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create cluster scope: %w", err)
+	}
+
+	capabilities, err := r.machineCapabilities(ctx, clusterScope, template.Spec.Template.Spec.InstanceType)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	capacity := capacityFromCapabilities(capabilities)
+
+	patchHelper, err := patch.NewHelper(template, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	template.Status.Capacity = capacity
+	if err := patchHelper.Patch(ctx, template); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to patch NcxInfraMachineTemplate: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// machineCapabilities returns the hardware capabilities of the instance type,
+// or of the targeted machine when the template pins one.
+func (r *NcxInfraMachineTemplateReconciler) machineCapabilities(
+	ctx context.Context,
+	clusterScope *scope.ClusterScope,
+	instanceType infrastructurev1.InstanceTypeSpec,
+) ([]nico.MachineCapability, error) {
+	switch {
+	case instanceType.ID != "":
+		getStart := time.Now()
+		it, httpResp, err := clusterScope.NcxInfraClient.GetInstanceType(ctx, clusterScope.OrgName, instanceType.ID)
+		apiErr := scope.ClassifyAPIError(httpResp, err, "GetInstanceType")
+		recordAPIMetrics("GetInstanceType", getStart, apiErr)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		return it.MachineCapabilities, nil
+	case instanceType.MachineID != "":
+		getStart := time.Now()
+		machine, httpResp, err := clusterScope.NcxInfraClient.GetMachine(ctx, clusterScope.OrgName, instanceType.MachineID)
+		apiErr := scope.ClassifyAPIError(httpResp, err, "GetMachine")
+		recordAPIMetrics("GetMachine", getStart, apiErr)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		return machine.MachineCapabilities, nil
+	}
+	return nil, nil
+}
+
+// capacityFromCapabilities converts the machine capabilities reported by
+// NVIDIA Carbide into node capacity. Resources that cannot be derived are omitted.
+func capacityFromCapabilities(capabilities []nico.MachineCapability) corev1.ResourceList {
+	var cpu, gpu int64
+	memory := resource.NewQuantity(0, resource.BinarySI)
+	storage := resource.NewQuantity(0, resource.DecimalSI)
+
+	for _, capability := range capabilities {
+		count := int64(1)
+		if capability.Count.Get() != nil {
+			count = int64(*capability.Count.Get())
+		}
+		switch capability.GetType() {
+		case "CPU":
+			// Kubernetes counts logical CPUs
+			switch {
+			case capability.Threads.Get() != nil:
+				cpu += count * int64(*capability.Threads.Get())
+			case capability.Cores.Get() != nil:
+				cpu += count * int64(*capability.Cores.Get())
+			default:
+				cpu += count
+			}
+		case "GPU":
+			gpu += count
+		case "Memory":
+			if q, ok := parseCapacity(capability.Capacity.Get()); ok {
+				memory.Add(*resource.NewQuantity(q.Value()*count, resource.BinarySI))
+			}
+		case "Storage":
+			if q, ok := parseCapacity(capability.Capacity.Get()); ok {
+				storage.Add(*resource.NewQuantity(q.Value()*count, resource.DecimalSI))
+			}
+		}
+	}
+
+	capacity := corev1.ResourceList{}
+	if cpu > 0 {
+		capacity[corev1.ResourceCPU] = *resource.NewQuantity(cpu, resource.DecimalSI)
+	}
+	if !memory.IsZero() {
+		capacity[corev1.ResourceMemory] = *memory
+	}
+	if !storage.IsZero() {
+		capacity[corev1.ResourceEphemeralStorage] = *storage
+	}
+	if gpu > 0 {
+		capacity[GPUResourceName] = *resource.NewQuantity(gpu, resource.DecimalSI)
+	}
+	if len(capacity) == 0 {
+		return nil
+	}
+	return capacity
+}
+
+// capacityUnits maps the units used by NVIDIA Carbide capacities to quantity suffixes.
+var capacityUnits = []struct{ unit, suffix string }{
+	{"KiB", "Ki"}, {"MiB", "Mi"}, {"GiB", "Gi"}, {"TiB", "Ti"},
+	{"KB", "k"}, {"MB", "M"}, {"GB", "G"}, {"TB", "T"}, {"B", ""},
+}
+
+// parseCapacity parses a capacity such as "1.92TB" or "64 GiB".
+func parseCapacity(value *string) (resource.Quantity, bool) {
+	if value == nil {
+		return resource.Quantity{}, false
+	}
+	s := strings.ReplaceAll(*value, " ", "")
+	for _, u := range capacityUnits {
+		if strings.HasSuffix(s, u.unit) {
+			s = strings.TrimSuffix(s, u.unit) + u.suffix
+			break
+		}
+	}
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+	return q, true
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NcxInfraMachineTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1.NcxInfraMachineTemplate{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(
+			mgr.GetScheme(), ctrl.Log.WithName("ncxinframachinetemplate"), r.WatchFilterValue)).
+		Named("ncxinframachinetemplate").
+		Complete(r)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+)
+
+var _ = Describe("NcxInfraMachineTemplate Controller", func() {
+	const (
+		clusterName      = "test-cluster"
+		templateName     = "test-workers"
+		clusterNamespace = "default"
+		orgName          = "test-org"
+		instanceTypeID   = "instance-type-uuid"
+	)
+
+	var (
+		ctx            context.Context
+		objects        []client.Object
+		template       *infrastructurev1.NcxInfraMachineTemplate
+		namespacedName types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespacedName = types.NamespacedName{Name: templateName, Namespace: clusterNamespace}
+
+		template = &infrastructurev1.NcxInfraMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      templateName,
+				Namespace: clusterNamespace,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: "cluster.x-k8s.io/v1beta2",
+						Kind:       "Cluster",
+						Name:       clusterName,
+						UID:        "cluster-uid",
+					},
+				},
+			},
+			Spec: infrastructurev1.NcxInfraMachineTemplateSpec{
+				Template: infrastructurev1.NcxInfraMachineTemplateResource{
+					Spec: infrastructurev1.NcxInfraMachineSpec{
+						InstanceType: infrastructurev1.InstanceTypeSpec{ID: instanceTypeID},
+					},
+				},
+			},
+		}
+
+		objects = []client.Object{
+			&clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: clusterNamespace, UID: "cluster-uid"},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+						APIGroup: "infrastructure.cluster.x-k8s.io",
+						Kind:     "NcxInfraCluster",
+						Name:     clusterName,
+					},
+				},
+			},
+			&infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: clusterNamespace},
+			},
+		}
+	})
+
+	newReconciler := func(mockClient *testutil.MockNcxInfraClient) *NcxInfraMachineTemplateReconciler {
+		scheme := newTestScheme()
+		k8sClient := newFakeClientBuilder(scheme).
+			WithObjects(append(objects, template)...).
+			WithStatusSubresource(&infrastructurev1.NcxInfraMachineTemplate{}).
+			Build()
+		return &NcxInfraMachineTemplateReconciler{
+			Client:         k8sClient,
+			Scheme:         scheme,
+			NcxInfraClient: mockClient,
+			OrgName:        orgName,
+		}
+	}
+
+	Context("When the template references an instance type", func() {
+		It("should report the instance type capacity", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				GetInstanceTypeFunc: func(
+					ctx context.Context, org, id string,
+				) (*nico.InstanceType, *http.Response, error) {
+					Expect(id).To(Equal(instanceTypeID))
+					return &nico.InstanceType{
+						Id: testutil.Ptr(instanceTypeID),
+						MachineCapabilities: []nico.MachineCapability{
+							{
+								Type:    testutil.Ptr("CPU"),
+								Cores:   *nico.NewNullableInt32(nico.PtrInt32(18)),
+								Threads: *nico.NewNullableInt32(nico.PtrInt32(36)),
+								Count:   *nico.NewNullableInt32(nico.PtrInt32(2)),
+							},
+							{
+								Type:     testutil.Ptr("Memory"),
+								Capacity: *nico.NewNullableString(nico.PtrString("64GiB")),
+								Count:    *nico.NewNullableInt32(nico.PtrInt32(4)),
+							},
+							{
+								Type:     testutil.Ptr("Storage"),
+								Capacity: *nico.NewNullableString(nico.PtrString("1.92TB")),
+								Count:    *nico.NewNullableInt32(nico.PtrInt32(2)),
+							},
+							{
+								Type:     testutil.Ptr("GPU"),
+								Capacity: *nico.NewNullableString(nico.PtrString("80GB")),
+								Count:    *nico.NewNullableInt32(nico.PtrInt32(8)),
+							},
+						},
+					}, testutil.MockHTTPResponse(200), nil
+				},
+			}
+			reconciler := newReconciler(mockClient)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &infrastructurev1.NcxInfraMachineTemplate{}
+			Expect(reconciler.Get(ctx, namespacedName, updated)).To(Succeed())
+			capacity := updated.Status.Capacity
+			Expect(capacity.Cpu().Value()).To(Equal(int64(72)))
+			Expect(capacity.Memory().Equal(resource.MustParse("256Gi"))).To(BeTrue())
+			Expect(capacity.StorageEphemeral().Equal(resource.MustParse("3.84T"))).To(BeTrue())
+			gpu := capacity[GPUResourceName]
+			Expect(gpu.Value()).To(Equal(int64(8)))
+		})
+
+		It("should return the error when the instance type cannot be fetched", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				GetInstanceTypeFunc: func(
+					ctx context.Context, org, id string,
+				) (*nico.InstanceType, *http.Response, error) {
+					return nil, testutil.MockHTTPResponse(503), http.ErrHandlerTimeout
+				},
+			}
+			reconciler := newReconciler(mockClient)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When the template is not used by a Cluster", func() {
+		It("should wait without calling the API", func() {
+			template.OwnerReferences = nil
+			called := false
+			mockClient := &testutil.MockNcxInfraClient{
+				GetInstanceTypeFunc: func(
+					ctx context.Context, org, id string,
+				) (*nico.InstanceType, *http.Response, error) {
+					called = true
+					return nil, nil, nil
+				},
+			}
+			reconciler := newReconciler(mockClient)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(called).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("parseCapacity", func() {
+	DescribeTable("converts NVIDIA Carbide capacities",
+		func(value, expected string) {
+			q, ok := parseCapacity(&value)
+			Expect(ok).To(BeTrue())
+			Expect(q.Equal(resource.MustParse(expected))).To(BeTrue(), "got %s", q.String())
+		},
+		Entry("decimal terabytes", "1.92TB", "1.92T"),
+		Entry("binary gibibytes", "64 GiB", "64Gi"),
+		Entry("megabytes", "512MB", "512M"),
+		Entry("bytes", "1024B", "1024"),
+	)
+
+	It("rejects unparsable capacities", func() {
+		value := "unknown"
+		_, ok := parseCapacity(&value)
+		Expect(ok).To(BeFalse())
+		_, ok = parseCapacity(nil)
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("capacityFromCapabilities", func() {
+	It("omits resources that cannot be derived", func() {
+		capacity := capacityFromCapabilities([]nico.MachineCapability{
+			{Type: testutil.Ptr("InfiniBand"), Count: *nico.NewNullableInt32(nico.PtrInt32(8))},
+		})
+		Expect(capacity).To(BeNil())
+		Expect(capacityFromCapabilities(nil)).To(BeNil())
+	})
+
+	It("falls back to cores when threads are not reported", func() {
+		capacity := capacityFromCapabilities([]nico.MachineCapability{
+			{Type: testutil.Ptr("CPU"), Cores: *nico.NewNullableInt32(nico.PtrInt32(16))},
+		})
+		Expect(capacity.Cpu().Value()).To(Equal(int64(16)))
+	})
+})
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// MachineTemplateResolver looks up the instance type and SSH key groups of the
+// NcxInfraMachineTemplates in NVIDIA Carbide, for their validation webhook.
+type MachineTemplateResolver struct {
+	client.Client
+
+	// NcxInfraClient can be set for testing or simulation mode to inject a client
+	NcxInfraClient scope.NcxInfraClientInterface
+	// OrgName can be set for testing or simulation mode
+	OrgName string
+
+	// DefaultCredentials is the credentials secret of the clusters that do not
+	// reference one, when their namespace has no default NcxInfraIdentity
+	DefaultCredentials corev1.SecretReference
+}
+
+var _ infrastructurev1.MachineTemplateResolver = &MachineTemplateResolver{}
+
+// ResolveMachineSpec reports the instance type and the SSH key groups that
+// the organization of the cluster does not know. The machines pinned with
+// machineID are left to the machine controller.
+func (r *MachineTemplateResolver) ResolveMachineSpec(
+	ctx context.Context, cluster *infrastructurev1.NcxInfraCluster, spec *infrastructurev1.NcxInfraMachineSpec,
+	specPath *field.Path,
+) (field.ErrorList, error) {
+	ncxInfraClient, orgName := r.NcxInfraClient, r.OrgName
+	if ncxInfraClient == nil {
+		var err error
+		ncxInfraClient, orgName, err = scope.NewClientFromSecret(ctx, r.Client,
+			cluster.Spec.Authentication, cluster.Namespace, r.DefaultCredentials, cluster.Spec.SiteRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var allErrs field.ErrorList
+	if id := spec.InstanceType.ID; id != "" {
+		getStart := time.Now()
+		_, httpResp, err := ncxInfraClient.GetInstanceType(ctx, orgName, id)
+		apiErr := scope.ClassifyAPIError(httpResp, err, "GetInstanceType")
+		recordAPIMetrics("GetInstanceType", getStart, apiErr)
+		switch {
+		case apiErr == nil:
+		case apiErr.IsNotFound():
+			allErrs = append(allErrs, field.NotFound(specPath.Child("instanceType", "id"), id))
+		default:
+			return allErrs, apiErr
+		}
+	}
+
+	for i, id := range spec.SSHKeyGroups {
+		getStart := time.Now()
+		_, httpResp, err := ncxInfraClient.GetSshKeyGroup(ctx, orgName, id)
+		apiErr := scope.ClassifyAPIError(httpResp, err, "GetSshKeyGroup")
+		recordAPIMetrics("GetSshKeyGroup", getStart, apiErr)
+		switch {
+		case apiErr == nil:
+		case apiErr.IsNotFound():
+			allErrs = append(allErrs, field.NotFound(specPath.Child("sshKeyGroups").Index(i), id))
+		default:
+			return allErrs, apiErr
+		}
+	}
+	return allErrs, nil
+}
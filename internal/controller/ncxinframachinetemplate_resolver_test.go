@@ -0,0 +1,110 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+)
+
+var _ = Describe("NcxInfraMachineTemplate resolver", func() {
+	const orgName = "test-org"
+
+	var (
+		ctx      context.Context
+		cluster  *infrastructurev1.NcxInfraCluster
+		spec     *infrastructurev1.NcxInfraMachineSpec
+		specPath *field.Path
+		status   map[string]int
+		resolver *MachineTemplateResolver
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+		}
+		spec = &infrastructurev1.NcxInfraMachineSpec{
+			InstanceType: infrastructurev1.InstanceTypeSpec{ID: "instance-type-uuid"},
+			SSHKeyGroups: []string{"key-group-1", "key-group-2"},
+		}
+		specPath = field.NewPath("spec", "template", "spec")
+		status = map[string]int{}
+
+		lookup := func(id string) (*http.Response, error) {
+			code, ok := status[id]
+			if !ok {
+				return testutil.MockHTTPResponse(http.StatusOK), nil
+			}
+			return testutil.MockHTTPResponse(code), fmt.Errorf("%s", http.StatusText(code))
+		}
+		resolver = &MachineTemplateResolver{
+			OrgName: orgName,
+			NcxInfraClient: &testutil.MockNcxInfraClient{
+				GetInstanceTypeFunc: func(
+					ctx context.Context, org, id string,
+				) (*nico.InstanceType, *http.Response, error) {
+					Expect(org).To(Equal(orgName))
+					httpResp, err := lookup(id)
+					return &nico.InstanceType{Id: testutil.Ptr(id)}, httpResp, err
+				},
+				GetSshKeyGroupFunc: func(
+					ctx context.Context, org, id string,
+				) (*nico.SshKeyGroup, *http.Response, error) {
+					Expect(org).To(Equal(orgName))
+					httpResp, err := lookup(id)
+					return &nico.SshKeyGroup{Id: testutil.Ptr(id)}, httpResp, err
+				},
+			},
+		}
+	})
+
+	It("should accept the references known to NVIDIA Carbide", func() {
+		allErrs, err := resolver.ResolveMachineSpec(ctx, cluster, spec, specPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allErrs).To(BeEmpty())
+	})
+
+	It("should report the unknown instance type and SSH key groups", func() {
+		status["instance-type-uuid"] = http.StatusNotFound
+		status["key-group-2"] = http.StatusNotFound
+
+		allErrs, err := resolver.ResolveMachineSpec(ctx, cluster, spec, specPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allErrs).To(HaveLen(2))
+		Expect(allErrs[0].Type).To(Equal(field.ErrorTypeNotFound))
+		Expect(allErrs[0].Field).To(Equal("spec.template.spec.instanceType.id"))
+		Expect(allErrs[1].Field).To(Equal("spec.template.spec.sshKeyGroups[1]"))
+	})
+
+	It("should return an error when NVIDIA Carbide cannot tell", func() {
+		status["key-group-1"] = http.StatusServiceUnavailable
+
+		_, err := resolver.ResolveMachineSpec(ctx, cluster, spec, specPath)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,562 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	bootstrapv1 "sigs.k8s.io/cluster-api/api/bootstrap/kubeadm/v1beta2"
+	controlplanev1 "sigs.k8s.io/cluster-api/api/controlplane/kubeadm/v1beta2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// Condition types
+const (
+	// ResourcesAppliedCondition reports whether the objects generated for a
+	// NcxInfraManagedCluster are up to date with its spec.
+	ResourcesAppliedCondition clusterv1.ConditionType = "ResourcesApplied"
+
+	// GPUOperatorConfiguredCondition reports whether the HelmChartProxy
+	// installing the NVIDIA GPU Operator exists.
+	GPUOperatorConfiguredCondition clusterv1.ConditionType = "GPUOperatorConfigured"
+)
+
+// helmChartProxyGVK is the HelmChartProxy of the Cluster API add-on provider
+// for Helm, which is not a dependency of the provider: it is handled as an
+// unstructured object, and its absence is reported instead of failing.
+var helmChartProxyGVK = schema.GroupVersionKind{
+	Group: "addons.cluster.x-k8s.io", Version: "v1alpha1", Kind: "HelmChartProxy",
+}
+
+// gpuOperatorValues leave the driver and container toolkit to the hosts of
+// the GPU worker pools.
+const gpuOperatorValues = `driver:
+  enabled: false
+toolkit:
+  enabled: false
+`
+
+// NcxInfraManagedClusterReconciler generates the Cluster API objects of a
+// cluster from a NcxInfraManagedCluster.
+type NcxInfraManagedClusterReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// WatchFilterValue is the value of the cluster.x-k8s.io/watch-filter label
+	// of the objects to reconcile, all of them when empty
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinframanagedclusters,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinframanagedclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraclusters;ncxinframachinetemplates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machinedeployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=kubeadmcontrolplanes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=bootstrap.cluster.x-k8s.io,resources=kubeadmconfigtemplates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=addons.cluster.x-k8s.io,resources=helmchartproxies,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile applies the objects generated for a NcxInfraManagedCluster.
+func (r *NcxInfraManagedClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, reterr error) {
+	logger := log.FromContext(ctx)
+
+	managed := &infrastructurev1.NcxInfraManagedCluster{}
+	if err := r.Get(ctx, req.NamespacedName, managed); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The generated objects are owned by the NcxInfraManagedCluster, and
+	// garbage collected with it
+	if !managed.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	if annotations.HasPaused(managed) {
+		logger.Info("NcxInfraManagedCluster is marked as paused, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(managed, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		sortConditions(managed)
+		if err := patchHelper.Patch(ctx, managed); err != nil {
+			res, reterr = patchResult(res, reterr, fmt.Errorf("failed to patch NcxInfraManagedCluster: %w", err))
+		}
+	}()
+
+	if err := r.applyResources(ctx, managed); err != nil {
+		conditions.Set(managed, metav1.Condition{
+			Type:    string(ResourcesAppliedCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "ApplyFailed",
+			Message: err.Error(),
+		})
+		return ctrl.Result{}, err
+	}
+	conditions.Set(managed, metav1.Condition{
+		Type:   string(ResourcesAppliedCondition),
+		Status: metav1.ConditionTrue,
+		Reason: "ResourcesApplied",
+	})
+	managed.Status.ObservedGeneration = managed.Generation
+
+	r.reconcileGPUOperator(ctx, managed)
+
+	// Report the availability of the generated Cluster
+	cluster := &clusterv1.Cluster{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: managed.Namespace, Name: managed.Name}, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	managed.Status.ClusterName = cluster.Name
+	managed.Status.Ready = conditions.IsTrue(cluster, clusterv1.ClusterAvailableCondition)
+	return ctrl.Result{}, nil
+}
+
+// applyResources creates the objects generated for a NcxInfraManagedCluster,
+// or updates their fields following its spec, and deletes the
+// MachineDeployments of the worker pools removed from the spec.
+func (r *NcxInfraManagedClusterReconciler) applyResources(
+	ctx context.Context, managed *infrastructurev1.NcxInfraManagedCluster,
+) error {
+	// The machine and bootstrap templates are named after a hash of their
+	// spec: a change creates new templates, which the control plane and
+	// MachineDeployments roll out to
+	controlPlaneTemplate := managedMachineTemplate(managed, managed.Name+"-control-plane",
+		managed.Spec.ControlPlane.InstanceTypeID, "control-plane")
+	objects := []client.Object{
+		managedNcxInfraCluster(managed),
+		controlPlaneTemplate,
+		managedControlPlane(managed, controlPlaneTemplate.Name),
+		managedCluster(managed),
+	}
+	pools := map[string]bool{}
+	for i := range managed.Spec.Workers {
+		pool := &managed.Spec.Workers[i]
+		name := managed.Name + "-" + pool.Name
+		machineTemplate := managedMachineTemplate(managed, name, pool.InstanceTypeID, "worker")
+		configTemplate := managedConfigTemplate(managed, name, pool)
+		objects = append(objects, machineTemplate, configTemplate,
+			managedMachineDeployment(managed, pool, machineTemplate.Name, configTemplate.Name))
+		pools[name] = true
+	}
+
+	for _, desired := range objects {
+		if err := r.apply(ctx, managed, desired); err != nil {
+			return err
+		}
+	}
+
+	deployments := &clusterv1.MachineDeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(managed.Namespace),
+		client.MatchingLabels{infrastructurev1.ManagedClusterNameLabel: managed.Name}); err != nil {
+		return fmt.Errorf("failed to list the MachineDeployments of the managed cluster: %w", err)
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if pools[deployment.Name] || !metav1.IsControlledBy(deployment, managed) {
+			continue
+		}
+		if err := r.Delete(ctx, deployment); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete MachineDeployment %s: %w", deployment.Name, err)
+		}
+		if r.Recorder != nil {
+			r.Recorder.Eventf(managed, corev1.EventTypeNormal, "WorkerPoolDeleted", "Deleted MachineDeployment %s", deployment.Name)
+		}
+	}
+	return nil
+}
+
+// apply creates a generated object, or updates the fields of an existing one
+// that follow the spec of the NcxInfraManagedCluster. The other fields are
+// only set at creation: the networks of a cluster and the kubeadm
+// configuration of its control plane do not change afterwards.
+func (r *NcxInfraManagedClusterReconciler) apply(
+	ctx context.Context, managed *infrastructurev1.NcxInfraManagedCluster, desired client.Object,
+) error {
+	kind := reflect.TypeOf(desired).Elem().Name()
+	current, _ := reflect.New(reflect.TypeOf(desired).Elem()).Interface().(client.Object)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get %s %s: %w", kind, desired.GetName(), err)
+		}
+		if err := controllerutil.SetControllerReference(managed, desired, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create %s %s: %w", kind, desired.GetName(), err)
+		}
+		log.FromContext(ctx).Info("Created generated object", "kind", kind, "name", desired.GetName())
+		return nil
+	}
+
+	base, _ := current.DeepCopyObject().(client.Object)
+	switch current := current.(type) {
+	case *controlplanev1.KubeadmControlPlane:
+		spec := desired.(*controlplanev1.KubeadmControlPlane).Spec
+		current.Spec.Replicas = spec.Replicas
+		current.Spec.Version = spec.Version
+		current.Spec.MachineTemplate.Spec.InfrastructureRef = spec.MachineTemplate.Spec.InfrastructureRef
+	case *clusterv1.MachineDeployment:
+		spec := desired.(*clusterv1.MachineDeployment).Spec
+		current.Spec.Replicas = spec.Replicas
+		current.Spec.Template.Spec.Version = spec.Template.Spec.Version
+		current.Spec.Template.Spec.Bootstrap = spec.Template.Spec.Bootstrap
+		current.Spec.Template.Spec.InfrastructureRef = spec.Template.Spec.InfrastructureRef
+	}
+	if equality.Semantic.DeepEqual(base, current) {
+		return nil
+	}
+	if err := r.Patch(ctx, current, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("failed to update %s %s: %w", kind, desired.GetName(), err)
+	}
+	log.FromContext(ctx).Info("Updated generated object", "kind", kind, "name", desired.GetName())
+	return nil
+}
+
+// reconcileGPUOperator creates the HelmChartProxy installing the NVIDIA GPU
+// Operator in the workload cluster, when requested.
+func (r *NcxInfraManagedClusterReconciler) reconcileGPUOperator(
+	ctx context.Context, managed *infrastructurev1.NcxInfraManagedCluster,
+) {
+	if managed.Spec.GPUOperator == nil {
+		conditions.Delete(managed, string(GPUOperatorConfiguredCondition))
+		return
+	}
+
+	proxy := &unstructured.Unstructured{}
+	proxy.SetGroupVersionKind(helmChartProxyGVK)
+	proxy.SetNamespace(managed.Namespace)
+	proxy.SetName(managed.Name + "-gpu-operator")
+	_, err := controllerutil.CreateOrPatch(ctx, r.Client, proxy, func() error {
+		proxy.SetLabels(managedLabels(managed))
+		spec := map[string]interface{}{
+			"clusterSelector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{infrastructurev1.ManagedClusterNameLabel: managed.Name},
+			},
+			"repoURL":           "https://helm.ngc.nvidia.com/nvidia",
+			"chartName":         "gpu-operator",
+			"releaseName":       "gpu-operator",
+			"namespace":         "gpu-operator",
+			"valuesTemplate":    gpuOperatorValues,
+			"options":           map[string]interface{}{"install": map[string]interface{}{"createNamespace": true}},
+			"reconcileStrategy": "Continuous",
+		}
+		if version := managed.Spec.GPUOperator.Version; version != "" {
+			spec["version"] = version
+		}
+		proxy.Object["spec"] = spec
+		return controllerutil.SetControllerReference(managed, proxy, r.Scheme)
+	})
+	if meta.IsNoMatchError(err) {
+		conditions.Set(managed, metav1.Condition{
+			Type:    string(GPUOperatorConfiguredCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "HelmAddonProviderNotInstalled",
+			Message: "install the Cluster API add-on provider for Helm to install the NVIDIA GPU Operator",
+		})
+		return
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to apply the HelmChartProxy of the NVIDIA GPU Operator")
+		conditions.Set(managed, metav1.Condition{
+			Type:    string(GPUOperatorConfiguredCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "HelmChartProxyApplyFailed",
+			Message: err.Error(),
+		})
+		return
+	}
+	conditions.Set(managed, metav1.Condition{
+		Type:   string(GPUOperatorConfiguredCondition),
+		Status: metav1.ConditionTrue,
+		Reason: "HelmChartProxyApplied",
+	})
+}
+
+// managedLabels returns the labels of the objects generated for a
+// NcxInfraManagedCluster, including its watch filter label so that they are
+// reconciled by the same provider deployment.
+func managedLabels(managed *infrastructurev1.NcxInfraManagedCluster) map[string]string {
+	labels := map[string]string{
+		clusterv1.ClusterNameLabel:               managed.Name,
+		infrastructurev1.ManagedClusterNameLabel: managed.Name,
+	}
+	if value, ok := managed.Labels[clusterv1.WatchLabel]; ok {
+		labels[clusterv1.WatchLabel] = value
+	}
+	return labels
+}
+
+// managedObjectMeta returns the metadata of an object generated for a
+// NcxInfraManagedCluster.
+func managedObjectMeta(managed *infrastructurev1.NcxInfraManagedCluster, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Namespace: managed.Namespace, Name: name, Labels: managedLabels(managed)}
+}
+
+// specHash returns a short hash of a template spec, to name the template.
+func specHash(spec interface{}) string {
+	data, _ := json.Marshal(spec)
+	hash := fnv.New32a()
+	_, _ = hash.Write(data)
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
+
+// managedCluster returns the Cluster of a NcxInfraManagedCluster.
+func managedCluster(managed *infrastructurev1.NcxInfraManagedCluster) *clusterv1.Cluster {
+	network := managed.Spec.Network
+	return &clusterv1.Cluster{
+		ObjectMeta: managedObjectMeta(managed, managed.Name),
+		Spec: clusterv1.ClusterSpec{
+			ClusterNetwork: clusterv1.ClusterNetwork{
+				Pods:     clusterv1.NetworkRanges{CIDRBlocks: []string{network.PodCIDR}},
+				Services: clusterv1.NetworkRanges{CIDRBlocks: []string{network.ServiceCIDR}},
+			},
+			InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+				APIGroup: infrastructurev1.GroupVersion.Group, Kind: "NcxInfraCluster", Name: managed.Name,
+			},
+			ControlPlaneRef: clusterv1.ContractVersionedObjectReference{
+				APIGroup: controlplanev1.GroupVersion.Group, Kind: "KubeadmControlPlane", Name: managed.Name + "-control-plane",
+			},
+		},
+	}
+}
+
+// managedNcxInfraCluster returns the NcxInfraCluster of a
+// NcxInfraManagedCluster: a VPC with a control plane and a worker subnet, and
+// a security group allowing SSH and the API server from the allowed CIDR, and
+// all the traffic between the machines.
+func managedNcxInfraCluster(managed *infrastructurev1.NcxInfraManagedCluster) *infrastructurev1.NcxInfraCluster {
+	network := managed.Spec.Network
+	rule := func(name, protocol, portRange, source string) infrastructurev1.NSGRule {
+		return infrastructurev1.NSGRule{
+			Name: name, Direction: "ingress", Protocol: protocol, PortRange: portRange, SourceCIDR: source, Action: "allow",
+		}
+	}
+	return &infrastructurev1.NcxInfraCluster{
+		ObjectMeta: managedObjectMeta(managed, managed.Name),
+		Spec: infrastructurev1.NcxInfraClusterSpec{
+			SiteRef:  managed.Spec.SiteRef,
+			TenantID: managed.Spec.TenantID,
+			VPC: infrastructurev1.VPCSpec{
+				Name:                      managed.Name + "-vpc",
+				NetworkVirtualizationType: "ETHERNET_VIRTUALIZER",
+				NetworkSecurityGroup: &infrastructurev1.NSGSpec{
+					Name: managed.Name + "-nsg",
+					Rules: []infrastructurev1.NSGRule{
+						rule("allow-ssh", "tcp", "22", network.AllowedSourceCIDR),
+						rule("allow-k8s-api", "tcp", "6443", network.AllowedSourceCIDR),
+						rule("allow-control-plane", "all", "", network.ControlPlaneSubnetCIDR),
+						rule("allow-workers", "all", "", network.WorkerSubnetCIDR),
+					},
+				},
+			},
+			Subnets: []infrastructurev1.SubnetSpec{
+				{Name: "control-plane", CIDR: network.ControlPlaneSubnetCIDR, Role: "control-plane"},
+				{Name: "worker", CIDR: network.WorkerSubnetCIDR, Role: "worker"},
+			},
+			ControlPlaneEndpointManagement: infrastructurev1.ControlPlaneEndpointAuto,
+			Organization:                   managed.Spec.Organization,
+			Authentication:                 managed.Spec.Authentication,
+		},
+	}
+}
+
+// managedMachineTemplate returns a NcxInfraMachineTemplate of a
+// NcxInfraManagedCluster, on the subnet of a role.
+func managedMachineTemplate(
+	managed *infrastructurev1.NcxInfraManagedCluster, prefix, instanceTypeID, role string,
+) *infrastructurev1.NcxInfraMachineTemplate {
+	spec := infrastructurev1.NcxInfraMachineSpec{
+		InstanceType: infrastructurev1.InstanceTypeSpec{ID: instanceTypeID},
+		Network:      infrastructurev1.NetworkSpec{SubnetName: role},
+		SSHKeyGroups: managed.Spec.SSHKeyGroups,
+		Labels:       map[string]string{"role": role, "cluster": managed.Name},
+	}
+	return &infrastructurev1.NcxInfraMachineTemplate{
+		ObjectMeta: managedObjectMeta(managed, prefix+"-"+specHash(spec)),
+		Spec: infrastructurev1.NcxInfraMachineTemplateSpec{
+			Template: infrastructurev1.NcxInfraMachineTemplateResource{Spec: spec},
+		},
+	}
+}
+
+// externalCloudProvider delegates the node lifecycle to the NVIDIA Carbide
+// cloud controller manager.
+var externalCloudProvider = []bootstrapv1.Arg{{Name: "cloud-provider", Value: ptr.To("external")}}
+
+// managedControlPlane returns the KubeadmControlPlane of a
+// NcxInfraManagedCluster.
+func managedControlPlane(
+	managed *infrastructurev1.NcxInfraManagedCluster, machineTemplate string,
+) *controlplanev1.KubeadmControlPlane {
+	return &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: managedObjectMeta(managed, managed.Name+"-control-plane"),
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Replicas: ptr.To(managed.Spec.ControlPlane.Replicas),
+			Version:  managed.Spec.Version,
+			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+				Spec: controlplanev1.KubeadmControlPlaneMachineTemplateSpec{
+					InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+						APIGroup: infrastructurev1.GroupVersion.Group, Kind: "NcxInfraMachineTemplate", Name: machineTemplate,
+					},
+				},
+			},
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				ClusterConfiguration: bootstrapv1.ClusterConfiguration{
+					APIServer:         bootstrapv1.APIServer{ExtraArgs: externalCloudProvider},
+					ControllerManager: bootstrapv1.ControllerManager{ExtraArgs: externalCloudProvider},
+				},
+				InitConfiguration: bootstrapv1.InitConfiguration{
+					NodeRegistration: bootstrapv1.NodeRegistrationOptions{KubeletExtraArgs: externalCloudProvider},
+				},
+				JoinConfiguration: bootstrapv1.JoinConfiguration{
+					NodeRegistration: bootstrapv1.NodeRegistrationOptions{KubeletExtraArgs: externalCloudProvider},
+				},
+			},
+		},
+	}
+}
+
+// gpuSetupCommands install the NVIDIA driver and container toolkit from the
+// packages of Ubuntu and the NVIDIA repository, and make the NVIDIA runtime
+// the default runtime of containerd, before kubeadm joins the node.
+func gpuSetupCommands(gpu *infrastructurev1.ManagedGPUSpec) []string {
+	keyring := "/usr/share/keyrings/nvidia-container-toolkit-keyring.gpg"
+	return []string{
+		"curl -fsSL https://nvidia.github.io/libnvidia-container/gpgkey | gpg --dearmor -o " + keyring,
+		"curl -fsSL https://nvidia.github.io/libnvidia-container/stable/deb/nvidia-container-toolkit.list | " +
+			"sed 's#deb https://#deb [signed-by=" + keyring + "] https://#g' > /etc/apt/sources.list.d/nvidia-container-toolkit.list",
+		"apt-get update",
+		fmt.Sprintf("DEBIAN_FRONTEND=noninteractive apt-get install -y nvidia-driver-%s-server nvidia-container-toolkit",
+			gpu.DriverBranch),
+		"modprobe nvidia",
+		"nvidia-ctk runtime configure --runtime=containerd --set-as-default",
+		"systemctl restart containerd",
+	}
+}
+
+// managedConfigTemplate returns the KubeadmConfigTemplate of a worker pool of
+// a NcxInfraManagedCluster.
+func managedConfigTemplate(
+	managed *infrastructurev1.NcxInfraManagedCluster, prefix string, pool *infrastructurev1.ManagedWorkerPool,
+) *bootstrapv1.KubeadmConfigTemplate {
+	nodeLabels := map[string]string{}
+	for key, value := range pool.NodeLabels {
+		nodeLabels[key] = value
+	}
+	spec := bootstrapv1.KubeadmConfigSpec{}
+	if pool.GPU != nil {
+		nodeLabels["nvidia.com/gpu.present"] = "true"
+		spec.PreKubeadmCommands = gpuSetupCommands(pool.GPU)
+	}
+
+	kubeletArgs := append([]bootstrapv1.Arg{}, externalCloudProvider...)
+	if len(nodeLabels) > 0 {
+		labels := make([]string, 0, len(nodeLabels))
+		for key, value := range nodeLabels {
+			labels = append(labels, key+"="+value)
+		}
+		sort.Strings(labels)
+		kubeletArgs = append(kubeletArgs, bootstrapv1.Arg{Name: "node-labels", Value: ptr.To(strings.Join(labels, ","))})
+	}
+	spec.JoinConfiguration.NodeRegistration.KubeletExtraArgs = kubeletArgs
+
+	return &bootstrapv1.KubeadmConfigTemplate{
+		ObjectMeta: managedObjectMeta(managed, prefix+"-"+specHash(spec)),
+		Spec: bootstrapv1.KubeadmConfigTemplateSpec{
+			Template: bootstrapv1.KubeadmConfigTemplateResource{Spec: spec},
+		},
+	}
+}
+
+// managedMachineDeployment returns the MachineDeployment of a worker pool of a
+// NcxInfraManagedCluster.
+func managedMachineDeployment(
+	managed *infrastructurev1.NcxInfraManagedCluster, pool *infrastructurev1.ManagedWorkerPool,
+	machineTemplate, configTemplate string,
+) *clusterv1.MachineDeployment {
+	name := managed.Name + "-" + pool.Name
+	selector := map[string]string{
+		clusterv1.ClusterNameLabel:           managed.Name,
+		clusterv1.MachineDeploymentNameLabel: name,
+	}
+	return &clusterv1.MachineDeployment{
+		ObjectMeta: managedObjectMeta(managed, name),
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: managed.Name,
+			Replicas:    pool.Replicas,
+			Selector:    metav1.LabelSelector{MatchLabels: selector},
+			Template: clusterv1.MachineTemplateSpec{
+				ObjectMeta: clusterv1.ObjectMeta{Labels: selector},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: managed.Name,
+					Version:     managed.Spec.Version,
+					Bootstrap: clusterv1.Bootstrap{ConfigRef: clusterv1.ContractVersionedObjectReference{
+						APIGroup: bootstrapv1.GroupVersion.Group, Kind: "KubeadmConfigTemplate", Name: configTemplate,
+					}},
+					InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+						APIGroup: infrastructurev1.GroupVersion.Group, Kind: "NcxInfraMachineTemplate", Name: machineTemplate,
+					},
+				},
+			},
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NcxInfraManagedClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1.NcxInfraManagedCluster{}).
+		Owns(&clusterv1.Cluster{}).
+		Owns(&controlplanev1.KubeadmControlPlane{}).
+		Owns(&clusterv1.MachineDeployment{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(
+			mgr.GetScheme(), ctrl.Log.WithName("ncxinframanagedcluster"), r.WatchFilterValue)).
+		Named("ncxinframanagedcluster").
+		Complete(r)
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	bootstrapv1 "sigs.k8s.io/cluster-api/api/bootstrap/kubeadm/v1beta2"
+	controlplanev1 "sigs.k8s.io/cluster-api/api/controlplane/kubeadm/v1beta2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+var _ = Describe("NcxInfraManagedCluster Controller", func() {
+	var (
+		ctx        context.Context
+		k8sClient  client.Client
+		reconciler *NcxInfraManagedClusterReconciler
+		managed    *infrastructurev1.NcxInfraManagedCluster
+		key        types.NamespacedName
+	)
+
+	reconcileManaged := func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	updateManaged := func(mutate func(*infrastructurev1.NcxInfraManagedCluster)) {
+		current := &infrastructurev1.NcxInfraManagedCluster{}
+		Expect(k8sClient.Get(ctx, key, current)).To(Succeed())
+		mutate(current)
+		Expect(k8sClient.Update(ctx, current)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		key = types.NamespacedName{Namespace: "default", Name: "managed"}
+		managed = &infrastructurev1.NcxInfraManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "managed-uid"},
+			Spec: infrastructurev1.NcxInfraManagedClusterSpec{
+				SiteRef:      infrastructurev1.SiteReference{Name: "my-site"},
+				TenantID:     "tenant-uuid",
+				Organization: "child-org",
+				Version:      "v1.33.1",
+				ControlPlane: infrastructurev1.ManagedControlPlaneSpec{
+					Replicas: 3, InstanceTypeID: "cpu-type",
+				},
+				Workers: []infrastructurev1.ManagedWorkerPool{
+					{
+						Name: "gpu", Replicas: ptr.To(int32(2)), InstanceTypeID: "gpu-type",
+						GPU:        &infrastructurev1.ManagedGPUSpec{DriverBranch: "570"},
+						NodeLabels: map[string]string{"pool": "gpu"},
+					},
+					{Name: "cpu", Replicas: ptr.To(int32(1)), InstanceTypeID: "cpu-type"},
+				},
+				Network: infrastructurev1.ManagedNetworkSpec{
+					ControlPlaneSubnetCIDR: "10.100.1.0/24",
+					WorkerSubnetCIDR:       "10.100.2.0/24",
+					PodCIDR:                "10.244.0.0/16",
+					ServiceCIDR:            "10.96.0.0/12",
+					AllowedSourceCIDR:      "0.0.0.0/0",
+				},
+			},
+		}
+		scheme := newTestScheme()
+		k8sClient = newFakeClientBuilder(scheme).
+			WithObjects(managed).
+			WithStatusSubresource(&infrastructurev1.NcxInfraManagedCluster{}).
+			Build()
+		reconciler = &NcxInfraManagedClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	It("should generate the objects of the cluster", func() {
+		reconcileManaged()
+
+		cluster := &clusterv1.Cluster{}
+		Expect(k8sClient.Get(ctx, key, cluster)).To(Succeed())
+		Expect(metav1.IsControlledBy(cluster, managed)).To(BeTrue())
+		Expect(cluster.Spec.ClusterNetwork.Pods.CIDRBlocks).To(Equal([]string{"10.244.0.0/16"}))
+		Expect(cluster.Spec.InfrastructureRef.Kind).To(Equal("NcxInfraCluster"))
+		Expect(cluster.Spec.ControlPlaneRef.Name).To(Equal("managed-control-plane"))
+
+		ncxInfraCluster := &infrastructurev1.NcxInfraCluster{}
+		Expect(k8sClient.Get(ctx, key, ncxInfraCluster)).To(Succeed())
+		Expect(ncxInfraCluster.Spec.SiteRef.Name).To(Equal("my-site"))
+		Expect(ncxInfraCluster.Spec.Organization).To(Equal("child-org"))
+		Expect(ncxInfraCluster.Spec.Subnets).To(HaveLen(2))
+		Expect(ncxInfraCluster.Spec.VPC.NetworkSecurityGroup.Rules).To(HaveLen(4))
+
+		kcp := &controlplanev1.KubeadmControlPlane{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "managed-control-plane"}, kcp)).To(Succeed())
+		Expect(*kcp.Spec.Replicas).To(Equal(int32(3)))
+		Expect(kcp.Spec.Version).To(Equal("v1.33.1"))
+		machineTemplate := &infrastructurev1.NcxInfraMachineTemplate{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{
+			Namespace: "default", Name: kcp.Spec.MachineTemplate.Spec.InfrastructureRef.Name,
+		}, machineTemplate)).To(Succeed())
+		Expect(machineTemplate.Spec.Template.Spec.InstanceType.ID).To(Equal("cpu-type"))
+		Expect(machineTemplate.Spec.Template.Spec.Network.SubnetName).To(Equal("control-plane"))
+
+		deployment := &clusterv1.MachineDeployment{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "managed-gpu"}, deployment)).To(Succeed())
+		Expect(metav1.IsControlledBy(deployment, managed)).To(BeTrue())
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(2)))
+		configTemplate := &bootstrapv1.KubeadmConfigTemplate{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{
+			Namespace: "default", Name: deployment.Spec.Template.Spec.Bootstrap.ConfigRef.Name,
+		}, configTemplate)).To(Succeed())
+		spec := configTemplate.Spec.Template.Spec
+		Expect(spec.PreKubeadmCommands).To(ContainElement(ContainSubstring("nvidia-driver-570-server")))
+		Expect(spec.JoinConfiguration.NodeRegistration.KubeletExtraArgs).To(ContainElement(bootstrapv1.Arg{
+			Name: "node-labels", Value: ptr.To("nvidia.com/gpu.present=true,pool=gpu"),
+		}))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "managed-cpu"}, deployment)).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKey{
+			Namespace: "default", Name: deployment.Spec.Template.Spec.Bootstrap.ConfigRef.Name,
+		}, configTemplate)).To(Succeed())
+		Expect(configTemplate.Spec.Template.Spec.PreKubeadmCommands).To(BeEmpty())
+
+		updated := &infrastructurev1.NcxInfraManagedCluster{}
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(conditions.IsTrue(updated, string(ResourcesAppliedCondition))).To(BeTrue())
+		Expect(updated.Status.ClusterName).To(Equal("managed"))
+	})
+
+	It("should propagate the watch filter label to the generated objects", func() {
+		updateManaged(func(m *infrastructurev1.NcxInfraManagedCluster) {
+			m.Labels = map[string]string{clusterv1.WatchLabel: "team-a"}
+		})
+		reconcileManaged()
+
+		ncxInfraCluster := &infrastructurev1.NcxInfraCluster{}
+		Expect(k8sClient.Get(ctx, key, ncxInfraCluster)).To(Succeed())
+		Expect(ncxInfraCluster.Labels).To(HaveKeyWithValue(clusterv1.WatchLabel, "team-a"))
+		deployment := &clusterv1.MachineDeployment{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "managed-gpu"}, deployment)).To(Succeed())
+		Expect(deployment.Labels).To(HaveKeyWithValue(clusterv1.WatchLabel, "team-a"))
+	})
+
+	It("should roll out spec changes and delete removed pools", func() {
+		reconcileManaged()
+		deployment := &clusterv1.MachineDeployment{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "managed-gpu"}, deployment)).To(Succeed())
+		oldTemplate := deployment.Spec.Template.Spec.InfrastructureRef.Name
+
+		updateManaged(func(m *infrastructurev1.NcxInfraManagedCluster) {
+			m.Spec.Version = "v1.34.0"
+			m.Spec.ControlPlane.Replicas = 5
+			m.Spec.Workers = m.Spec.Workers[:1]
+			m.Spec.Workers[0].Replicas = ptr.To(int32(4))
+			m.Spec.Workers[0].InstanceTypeID = "gpu-type-2"
+		})
+		reconcileManaged()
+
+		kcp := &controlplanev1.KubeadmControlPlane{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "managed-control-plane"}, kcp)).To(Succeed())
+		Expect(*kcp.Spec.Replicas).To(Equal(int32(5)))
+		Expect(kcp.Spec.Version).To(Equal("v1.34.0"))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "managed-gpu"}, deployment)).To(Succeed())
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(4)))
+		Expect(deployment.Spec.Template.Spec.Version).To(Equal("v1.34.0"))
+		Expect(deployment.Spec.Template.Spec.InfrastructureRef.Name).NotTo(Equal(oldTemplate))
+
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "managed-cpu"}, deployment)
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should install the NVIDIA GPU Operator through a HelmChartProxy", func() {
+		updateManaged(func(m *infrastructurev1.NcxInfraManagedCluster) {
+			m.Spec.GPUOperator = &infrastructurev1.GPUOperatorSpec{Version: "v25.3.0"}
+		})
+		reconcileManaged()
+
+		proxy := &unstructured.Unstructured{}
+		proxy.SetGroupVersionKind(helmChartProxyGVK)
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "managed-gpu-operator"}, proxy)).To(Succeed())
+		spec, _, _ := unstructured.NestedMap(proxy.Object, "spec")
+		Expect(spec).To(HaveKeyWithValue("chartName", "gpu-operator"))
+		Expect(spec).To(HaveKeyWithValue("version", "v25.3.0"))
+		Expect(spec).To(HaveKeyWithValue("valuesTemplate", ContainSubstring("enabled: false")))
+
+		updated := &infrastructurev1.NcxInfraManagedCluster{}
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(conditions.IsTrue(updated, string(GPUOperatorConfiguredCondition))).To(BeTrue())
+	})
+
+	It("should report a missing add-on provider for Helm", func() {
+		noMatch := func(obj client.Object) error {
+			if _, ok := obj.(*unstructured.Unstructured); ok {
+				return &meta.NoKindMatchError{GroupKind: helmChartProxyGVK.GroupKind()}
+			}
+			return nil
+		}
+		scheme := newTestScheme()
+		k8sClient = newFakeClientBuilder(scheme).
+			WithObjects(managed).
+			WithStatusSubresource(&infrastructurev1.NcxInfraManagedCluster{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(
+					ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption,
+				) error {
+					if err := noMatch(obj); err != nil {
+						return err
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			}).
+			Build()
+		reconciler = &NcxInfraManagedClusterReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+		updateManaged(func(m *infrastructurev1.NcxInfraManagedCluster) {
+			m.Spec.GPUOperator = &infrastructurev1.GPUOperatorSpec{}
+		})
+		reconcileManaged()
+
+		updated := &infrastructurev1.NcxInfraManagedCluster{}
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(conditions.IsFalse(updated, string(GPUOperatorConfiguredCondition))).To(BeTrue())
+		Expect(conditions.GetReason(updated, string(GPUOperatorConfiguredCondition))).To(Equal("HelmAddonProviderNotInstalled"))
+		Expect(conditions.IsTrue(updated, string(ResourcesAppliedCondition))).To(BeTrue())
+	})
+})
@@ -0,0 +1,313 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/convert"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+const (
+	// NcxInfraNetworkSecurityGroupFinalizer allows deletion of the NSG in NVIDIA Carbide
+	// once no cluster references it anymore
+	NcxInfraNetworkSecurityGroupFinalizer = "ncxinfranetworksecuritygroup.infrastructure.cluster.x-k8s.io"
+
+	// nsgInUseRetryInterval paces the deletion retries of an NSG still referenced by clusters
+	nsgInUseRetryInterval = 30 * time.Second
+)
+
+// NcxInfraNetworkSecurityGroupReconciler reconciles a NcxInfraNetworkSecurityGroup object
+type NcxInfraNetworkSecurityGroupReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// NcxInfraClient can be set for testing or simulation mode to inject a client
+	NcxInfraClient scope.NcxInfraClientInterface
+	// OrgName can be set for testing or simulation mode
+	OrgName string
+
+	// DefaultCredentials is the credentials secret of the objects that do not
+	// reference one, when their namespace has no default NcxInfraIdentity
+	DefaultCredentials corev1.SecretReference
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfranetworksecuritygroups,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfranetworksecuritygroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfranetworksecuritygroups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraidentities,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraclusteridentities,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Reconcile handles NcxInfraNetworkSecurityGroup reconciliation
+func (r *NcxInfraNetworkSecurityGroupReconciler) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (res ctrl.Result, reterr error) {
+	logger := log.FromContext(ctx)
+	ctx = scope.WithAuditObject(ctx, "NcxInfraNetworkSecurityGroup", req.NamespacedName)
+
+	nsg := &infrastructurev1.NcxInfraNetworkSecurityGroup{}
+	if err := r.Get(ctx, req.NamespacedName, nsg); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if annotations.HasPaused(nsg) {
+		logger.Info("NcxInfraNetworkSecurityGroup is marked as paused, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(nsg, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		sortConditions(nsg)
+		if err := patchHelper.Patch(ctx, nsg); err != nil {
+			res, reterr = patchResult(res, reterr, fmt.Errorf("failed to patch NcxInfraNetworkSecurityGroup: %w", err))
+		}
+	}()
+
+	ncxInfraClient, orgName := r.NcxInfraClient, r.OrgName
+	if ncxInfraClient == nil {
+		ncxInfraClient, orgName, err = scope.NewClientFromSecret(
+			ctx, r.Client, nsg.Spec.Authentication, nsg.Namespace, r.DefaultCredentials, nsg.Spec.SiteRef)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if !nsg.DeletionTimestamp.IsZero() {
+		result, err := r.reconcileDelete(ctx, nsg, ncxInfraClient, orgName)
+		return handlePermissionError(ctx, nsg, orgName, result, err)
+	}
+
+	result, err := r.reconcileNormal(ctx, nsg, ncxInfraClient, orgName)
+	return handlePermissionError(ctx, nsg, orgName, result, err)
+}
+
+func (r *NcxInfraNetworkSecurityGroupReconciler) reconcileNormal(
+	ctx context.Context, nsg *infrastructurev1.NcxInfraNetworkSecurityGroup,
+	ncxInfraClient scope.NcxInfraClientInterface, orgName string,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(nsg, NcxInfraNetworkSecurityGroupFinalizer) {
+		controllerutil.AddFinalizer(nsg, NcxInfraNetworkSecurityGroupFinalizer)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Check if NSG already exists
+	if nsg.Status.NSGID != "" {
+		existing, httpResp, err := ncxInfraClient.GetNetworkSecurityGroup(ctx, orgName, nsg.Status.NSGID)
+		apiErr := scope.ClassifyAPIError(httpResp, err, "GetNetworkSecurityGroup")
+		switch {
+		case apiErr == nil && existing != nil:
+			nsg.Status.Ready = true
+			conditions.Set(nsg, metav1.Condition{
+				Type:   string(NSGReadyCondition),
+				Status: metav1.ConditionTrue,
+				Reason: "NSGReady",
+			})
+			return ctrl.Result{}, nil
+		case apiErr != nil && !apiErr.IsNotFound():
+			return ctrl.Result{}, apiErr
+		}
+		logger.Info("NSG not found in NVIDIA Carbide, will recreate", "nsgID", nsg.Status.NSGID)
+		nsg.Status.NSGID = ""
+		nsg.Status.Origin = nil
+		nsg.Status.Ready = false
+	}
+
+	siteID, err := scope.ResolveSiteID(ctx, ncxInfraClient, orgName, nsg.Spec.SiteRef)
+	if err != nil {
+		conditions.Set(nsg, metav1.Condition{
+			Type:    string(NSGReadyCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "SiteNotFound",
+			Message: err.Error(),
+		})
+		return ctrl.Result{}, err
+	}
+
+	nsgReq := nico.NetworkSecurityGroupCreateRequest{
+		Name:   nsg.NSGName(),
+		SiteId: siteID,
+	}
+	if nsg.Spec.Description != "" {
+		nsgReq.Description = &nsg.Spec.Description
+	}
+	if rules := convert.NSGRules(nsg.Spec.Rules); len(rules) > 0 {
+		nsgReq.Rules = rules
+	}
+
+	logger.Info("Creating NSG", "name", nsgReq.Name, "siteID", siteID)
+	created, httpResp, err := ncxInfraClient.CreateNetworkSecurityGroup(ctx, orgName, nsgReq)
+	if err == nil && httpResp.StatusCode != http.StatusCreated {
+		err = fmt.Errorf("status %d", httpResp.StatusCode)
+	} else if err == nil && (created == nil || created.Id == nil) {
+		err = fmt.Errorf("NSG ID missing in response")
+	}
+	if err != nil {
+		conditions.Set(nsg, metav1.Condition{
+			Type:    string(NSGReadyCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "NSGReconcileFailed",
+			Message: err.Error(),
+		})
+		return ctrl.Result{}, fmt.Errorf("failed to create NSG: %w",
+			scope.WithPermissionError(httpResp, err, "CreateNetworkSecurityGroup"))
+	}
+
+	nsg.Status.NSGID = *created.Id
+	origin := scope.NewResourceOrigin("NetworkSecurityGroup", nsg.NSGName(), created.Created)
+	nsg.Status.Origin = &origin
+	nsg.Status.Ready = true
+	conditions.Set(nsg, metav1.Condition{
+		Type:   string(NSGReadyCondition),
+		Status: metav1.ConditionTrue,
+		Reason: "NSGReady",
+	})
+	logger.Info("Successfully created NSG", "nsgID", *created.Id)
+	r.recordEvent(nsg, corev1.EventTypeNormal, "NSGCreated", "Successfully created NSG %s", *created.Id)
+	return ctrl.Result{}, nil
+}
+
+func (r *NcxInfraNetworkSecurityGroupReconciler) reconcileDelete(
+	ctx context.Context, nsg *infrastructurev1.NcxInfraNetworkSecurityGroup,
+	ncxInfraClient scope.NcxInfraClientInterface, orgName string,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// Keep the NSG while clusters still use it
+	users, err := r.referencingClusters(ctx, nsg)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(users) > 0 {
+		logger.Info("NSG is still referenced, waiting before deletion", "clusters", users)
+		conditions.Set(nsg, metav1.Condition{
+			Type:    string(NSGReadyCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "InUse",
+			Message: fmt.Sprintf("referenced by NcxInfraClusters %s", strings.Join(users, ", ")),
+		})
+		return ctrl.Result{RequeueAfter: nsgInUseRetryInterval}, nil
+	}
+
+	if nsg.Status.NSGID != "" {
+		logger.Info("Deleting NSG", "nsgID", nsg.Status.NSGID)
+		httpResp, err := ncxInfraClient.DeleteNetworkSecurityGroup(ctx, orgName, nsg.Status.NSGID)
+		apiErr := scope.ClassifyAPIError(httpResp, err, "DeleteNetworkSecurityGroup")
+		if apiErr != nil && !apiErr.IsNotFound() {
+			return ctrl.Result{}, fmt.Errorf("failed to delete NSG %s: %w", nsg.Status.NSGID,
+				scope.WithPermissionError(httpResp, err, "DeleteNetworkSecurityGroup"))
+		}
+		nsg.Status.NSGID = ""
+		nsg.Status.Origin = nil
+		nsg.Status.Ready = false
+	}
+
+	controllerutil.RemoveFinalizer(nsg, NcxInfraNetworkSecurityGroupFinalizer)
+	logger.Info("Successfully deleted NcxInfraNetworkSecurityGroup")
+	return ctrl.Result{}, nil
+}
+
+// referencingClusters returns the names of the NcxInfraClusters whose VPC
+// references the NSG, excluding the clusters that already released it.
+func (r *NcxInfraNetworkSecurityGroupReconciler) referencingClusters(
+	ctx context.Context, nsg *infrastructurev1.NcxInfraNetworkSecurityGroup,
+) ([]string, error) {
+	clusters := &infrastructurev1.NcxInfraClusterList{}
+	if err := r.List(ctx, clusters,
+		client.InNamespace(nsg.Namespace),
+		client.MatchingFields{NetworkSecurityGroupRefField: nsg.Name},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list NcxInfraClusters: %w", err)
+	}
+
+	var names []string
+	for i := range clusters.Items {
+		c := &clusters.Items[i]
+		// A deleting cluster releases the NSG as soon as its finalizer is gone
+		if !c.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(c, NcxInfraClusterFinalizer) {
+			continue
+		}
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
+
+// ncxInfraClusterToNcxInfraNetworkSecurityGroup maps a NcxInfraCluster to the
+// NSG it references, so that a pending NSG deletion resumes once the cluster is gone.
+func (r *NcxInfraNetworkSecurityGroupReconciler) ncxInfraClusterToNcxInfraNetworkSecurityGroup(
+	_ context.Context, obj client.Object,
+) []ctrl.Request {
+	c, ok := obj.(*infrastructurev1.NcxInfraCluster)
+	if !ok || c.Spec.VPC.NetworkSecurityGroupRef == nil {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{
+		Namespace: c.Namespace,
+		Name:      c.Spec.VPC.NetworkSecurityGroupRef.Name,
+	}}}
+}
+
+// recordEvent records an event if the recorder is available
+func (r *NcxInfraNetworkSecurityGroupReconciler) recordEvent(
+	obj runtime.Object, eventType, reason, messageFmt string, args ...interface{},
+) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NcxInfraNetworkSecurityGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1.NcxInfraNetworkSecurityGroup{}).
+		Watches(
+			&infrastructurev1.NcxInfraCluster{},
+			handler.EnqueueRequestsFromMapFunc(r.ncxInfraClusterToNcxInfraNetworkSecurityGroup),
+		).
+		Named("ncxinfranetworksecuritygroup").
+		Complete(r)
+}
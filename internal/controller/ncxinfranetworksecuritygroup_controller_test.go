@@ -0,0 +1,266 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+var _ = Describe("NcxInfraNetworkSecurityGroup Controller", func() {
+	const (
+		nsgName   = "shared-nsg"
+		namespace = "default"
+		orgName   = "test-org"
+		siteID    = "site-uuid"
+		nsgID     = "nsg-uuid"
+	)
+
+	var (
+		ctx            context.Context
+		nsg            *infrastructurev1.NcxInfraNetworkSecurityGroup
+		namespacedName types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespacedName = types.NamespacedName{Name: nsgName, Namespace: namespace}
+		nsg = &infrastructurev1.NcxInfraNetworkSecurityGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: nsgName, Namespace: namespace},
+			Spec: infrastructurev1.NcxInfraNetworkSecurityGroupSpec{
+				SiteRef:     infrastructurev1.SiteReference{ID: siteID},
+				Description: "shared",
+				Rules: []infrastructurev1.NSGRule{
+					{Name: "allow-k8s-api", Direction: "ingress", Protocol: "tcp", PortRange: "6443", Action: "allow"},
+				},
+				Authentication: infrastructurev1.AuthenticationSpec{
+					SecretRef: corev1.SecretReference{Name: "ncx-infra-credentials"},
+				},
+			},
+		}
+	})
+
+	newReconciler := func(
+		mockClient *testutil.MockNcxInfraClient, objects ...client.Object,
+	) *NcxInfraNetworkSecurityGroupReconciler {
+		scheme := newTestScheme()
+		k8sClient := newFakeClientBuilder(scheme).
+			WithObjects(append(objects, nsg)...).
+			WithStatusSubresource(&infrastructurev1.NcxInfraNetworkSecurityGroup{}).
+			Build()
+		return &NcxInfraNetworkSecurityGroupReconciler{
+			Client:         k8sClient,
+			Scheme:         scheme,
+			NcxInfraClient: mockClient,
+			OrgName:        orgName,
+		}
+	}
+
+	referencingCluster := func(name string) *infrastructurev1.NcxInfraCluster {
+		return &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				VPC: infrastructurev1.VPCSpec{
+					NetworkSecurityGroupRef: &corev1.LocalObjectReference{Name: nsgName},
+				},
+			},
+		}
+	}
+
+	Context("When creating a NcxInfraNetworkSecurityGroup", func() {
+		It("should create the NSG and report its ID", func() {
+			nsg.Finalizers = []string{NcxInfraNetworkSecurityGroupFinalizer}
+			created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			mockClient := &testutil.MockNcxInfraClient{
+				CreateNetworkSecurityGroupFunc: func(
+					ctx context.Context, org string, req nico.NetworkSecurityGroupCreateRequest,
+				) (*nico.NetworkSecurityGroup, *http.Response, error) {
+					Expect(req.Name).To(Equal(nsgName))
+					Expect(req.SiteId).To(Equal(siteID))
+					Expect(req.Description).To(Equal(testutil.Ptr("shared")))
+					Expect(req.Rules).To(HaveLen(1))
+					Expect(req.Rules[0].DestinationPortRange.Get()).To(Equal(testutil.Ptr("6443")))
+					return &nico.NetworkSecurityGroup{Id: testutil.Ptr(nsgID), Created: &created}, testutil.MockHTTPResponse(201), nil
+				},
+			}
+			reconciler := newReconciler(mockClient)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &infrastructurev1.NcxInfraNetworkSecurityGroup{}
+			Expect(reconciler.Get(ctx, namespacedName, updated)).To(Succeed())
+			Expect(updated.Status.NSGID).To(Equal(nsgID))
+			Expect(updated.Status.Ready).To(BeTrue())
+			Expect(conditions.IsTrue(updated, string(NSGReadyCondition))).To(BeTrue())
+			Expect(updated.Status.Origin).NotTo(BeNil())
+			Expect(updated.Status.Origin.Name).To(Equal(nsgName))
+			Expect(updated.Status.Origin.Created.Time.Equal(created)).To(BeTrue())
+		})
+	})
+
+	Context("When deleting a NcxInfraNetworkSecurityGroup", func() {
+		BeforeEach(func() {
+			now := metav1.Now()
+			nsg.DeletionTimestamp = &now
+			nsg.Finalizers = []string{NcxInfraNetworkSecurityGroupFinalizer}
+			nsg.Status.NSGID = nsgID
+			nsg.Status.Ready = true
+		})
+
+		It("should keep the NSG while a cluster references it", func() {
+			mockClient := &testutil.MockNcxInfraClient{
+				DeleteNetworkSecurityGroupFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					Fail("NSG deleted while still referenced")
+					return nil, nil
+				},
+			}
+			reconciler := newReconciler(mockClient, referencingCluster("cluster-a"))
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(nsgInUseRetryInterval))
+
+			updated := &infrastructurev1.NcxInfraNetworkSecurityGroup{}
+			Expect(reconciler.Get(ctx, namespacedName, updated)).To(Succeed())
+			condition := conditions.Get(updated, string(NSGReadyCondition))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("InUse"))
+			Expect(condition.Message).To(ContainSubstring("cluster-a"))
+		})
+
+		It("should delete the NSG once it is no longer referenced", func() {
+			deleted := ""
+			mockClient := &testutil.MockNcxInfraClient{
+				DeleteNetworkSecurityGroupFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+					deleted = id
+					return testutil.MockHTTPResponse(204), nil
+				},
+			}
+			other := referencingCluster("cluster-b")
+			other.Spec.VPC.NetworkSecurityGroupRef.Name = "other-nsg"
+			reconciler := newReconciler(mockClient, other)
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(nsgID))
+
+			err = reconciler.Get(ctx, namespacedName, &infrastructurev1.NcxInfraNetworkSecurityGroup{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("NcxInfraCluster with a referenced NSG", func() {
+	const (
+		clusterName = "test-cluster"
+		namespace   = "default"
+		nsgName     = "shared-nsg"
+		nsgID       = "nsg-uuid"
+	)
+
+	var (
+		ctx          context.Context
+		clusterScope *scope.ClusterScope
+		nsg          *infrastructurev1.NcxInfraNetworkSecurityGroup
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		nsg = &infrastructurev1.NcxInfraNetworkSecurityGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: nsgName, Namespace: namespace},
+		}
+		clusterScope = &scope.ClusterScope{
+			Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: namespace}},
+			NcxInfraCluster: &infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       clusterName,
+					Namespace:  namespace,
+					Finalizers: []string{NcxInfraClusterFinalizer},
+				},
+				Spec: infrastructurev1.NcxInfraClusterSpec{
+					VPC: infrastructurev1.VPCSpec{
+						NetworkSecurityGroupRef: &corev1.LocalObjectReference{Name: nsgName},
+					},
+				},
+			},
+		}
+	})
+
+	newReconciler := func(mockClient *testutil.MockNcxInfraClient) *NcxInfraClusterReconciler {
+		clusterScope.NcxInfraClient = mockClient
+		return &NcxInfraClusterReconciler{
+			Client:         newFakeClientBuilder(newTestScheme()).WithObjects(nsg).Build(),
+			NcxInfraClient: mockClient,
+		}
+	}
+
+	It("should wait for the referenced NSG to be ready", func() {
+		reconciler := newReconciler(&testutil.MockNcxInfraClient{})
+
+		err := reconciler.reconcileNSG(ctx, clusterScope, "site-uuid")
+		Expect(err).To(MatchError(ContainSubstring("is not ready")))
+		Expect(clusterScope.NSGID()).To(BeEmpty())
+	})
+
+	It("should use the ID of the referenced NSG without creating one", func() {
+		nsg.Status = infrastructurev1.NcxInfraNetworkSecurityGroupStatus{Ready: true, NSGID: nsgID}
+		reconciler := newReconciler(&testutil.MockNcxInfraClient{
+			CreateNetworkSecurityGroupFunc: func(
+				ctx context.Context, org string, req nico.NetworkSecurityGroupCreateRequest,
+			) (*nico.NetworkSecurityGroup, *http.Response, error) {
+				Fail("NSG created for a cluster referencing a shared NSG")
+				return nil, nil, nil
+			},
+		})
+
+		Expect(reconciler.reconcileNSG(ctx, clusterScope, "site-uuid")).To(Succeed())
+		Expect(clusterScope.NSGID()).To(Equal(nsgID))
+	})
+
+	It("should not delete the referenced NSG with the cluster", func() {
+		clusterScope.SetNSGID(nsgID)
+		reconciler := newReconciler(&testutil.MockNcxInfraClient{
+			DeleteNetworkSecurityGroupFunc: func(ctx context.Context, org, id string) (*http.Response, error) {
+				Fail("shared NSG deleted with the cluster")
+				return nil, nil
+			},
+		})
+
+		_, err := reconciler.reconcileDelete(ctx, clusterScope)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusterScope.NSGID()).To(BeEmpty())
+		Expect(clusterScope.NcxInfraCluster.Finalizers).NotTo(ContainElement(NcxInfraClusterFinalizer))
+	})
+})
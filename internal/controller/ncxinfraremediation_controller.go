@@ -0,0 +1,384 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// Condition types
+const (
+	InstanceRemediatedCondition clusterv1.ConditionType = "InstanceRemediated"
+)
+
+const (
+	defaultRemediationTimeout = 30 * time.Minute
+
+	// remediationGracePeriod leaves time for the instance to leave the Ready
+	// state after a reboot was submitted, so that the pre-reboot state is not
+	// mistaken for a successful remediation.
+	remediationGracePeriod = time.Minute
+)
+
+// NcxInfraRemediationReconciler implements the Cluster API external remediation
+// contract: it remediates unhealthy machines in place by rebooting or reimaging
+// the same physical machine instead of deleting it.
+type NcxInfraRemediationReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// NcxInfraClient can be set for testing or simulation mode to inject a client
+	NcxInfraClient scope.NcxInfraClientInterface
+	// OrgName can be set for testing or simulation mode
+	OrgName string
+
+	// DefaultCredentials is the credentials secret of the objects that do not
+	// reference one, when their namespace has no default NcxInfraIdentity
+	DefaultCredentials corev1.SecretReference
+
+	// WatchFilterValue is the value of the cluster.x-k8s.io/watch-filter label
+	// of the objects to reconcile, all of them when empty
+	WatchFilterValue string
+
+	// Machines is the NcxInfraMachine reconciler. Reimages render the
+	// bootstrap data as it does for a new instance, and take a provisioning
+	// slot of the cluster from it.
+	Machines *NcxInfraMachineReconciler
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraremediations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraremediations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraremediationtemplates,verbs=get;list;watch
+
+// Reconcile handles NcxInfraRemediation reconciliation
+func (r *NcxInfraRemediationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, reterr error) {
+	logger := log.FromContext(ctx)
+
+	remediation := &infrastructurev1.NcxInfraRemediation{}
+	if err := r.Get(ctx, req.NamespacedName, remediation); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The MachineHealthCheck deletes the remediation once the node is healthy again
+	if !remediation.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the unhealthy Machine, which owns the remediation
+	machine, err := util.GetOwnerMachine(ctx, r.Client, remediation.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machine == nil {
+		logger.Info("Waiting for MachineHealthCheck to set OwnerRef on NcxInfraRemediation")
+		return ctrl.Result{}, nil
+	}
+
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machine.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cluster == nil {
+		logger.Info("Waiting for Cluster to be set on Machine")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	logger = logger.WithValues("cluster", cluster.Name, "machine", machine.Name)
+	ctx = log.IntoContext(ctx, logger)
+	ctx = scope.WithAuditObject(ctx, "NcxInfraRemediation", req.NamespacedName)
+
+	if annotations.IsPaused(cluster, remediation) {
+		logger.Info("NcxInfraRemediation or Cluster is marked as paused, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
+	nvidiaCarbideMachine := &infrastructurev1.NcxInfraMachine{}
+	nvidiaCarbideMachineKey := client.ObjectKey{
+		Namespace: machine.Namespace,
+		Name:      machine.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Get(ctx, nvidiaCarbideMachineKey, nvidiaCarbideMachine); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	nvidiaCarbideCluster := &infrastructurev1.NcxInfraCluster{}
+	nvidiaCarbideClusterKey := client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Get(ctx, nvidiaCarbideClusterKey, nvidiaCarbideCluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	patchHelper, err := patch.NewHelper(remediation, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		sortConditions(remediation)
+		if err := patchHelper.Patch(ctx, remediation); err != nil {
+			res, reterr = patchResult(res, reterr, fmt.Errorf("failed to patch NcxInfraRemediation: %w", err))
+		}
+	}()
+
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:             r.Client,
+		Cluster:            cluster,
+		NcxInfraCluster:    nvidiaCarbideCluster,
+		NcxInfraClient:     r.NcxInfraClient,
+		OrgName:            r.OrgName,
+		DefaultCredentials: r.DefaultCredentials,
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create cluster scope: %w", err)
+	}
+
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:          r.Client,
+		Cluster:         cluster,
+		Machine:         machine,
+		NcxInfraCluster: nvidiaCarbideCluster,
+		NcxInfraMachine: nvidiaCarbideMachine,
+		NcxInfraClient:  clusterScope.NcxInfraClient,
+		OrgName:         clusterScope.OrgName,
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create machine scope: %w", err)
+	}
+
+	return r.reconcileNormal(ctx, remediation, machineScope, clusterScope)
+}
+
+func (r *NcxInfraRemediationReconciler) reconcileNormal(
+	ctx context.Context,
+	remediation *infrastructurev1.NcxInfraRemediation,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if remediation.Status.Phase == infrastructurev1.RemediationPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	if machineScope.InstanceID() == "" {
+		r.markFailed(remediation, "NcxInfraMachine has no instance to remediate")
+		return ctrl.Result{}, nil
+	}
+	logger = logger.WithValues("instanceID", machineScope.InstanceID())
+	ctx = log.IntoContext(ctx, logger)
+
+	// First attempt
+	if remediation.Status.LastRemediated == nil {
+		return r.remediate(ctx, remediation, machineScope, clusterScope)
+	}
+
+	timeout := defaultRemediationTimeout
+	if remediation.Spec.Timeout != nil {
+		timeout = remediation.Spec.Timeout.Duration
+	}
+	retryLimit := remediation.Spec.RetryLimit
+	if retryLimit < 1 {
+		retryLimit = 1
+	}
+
+	elapsed := time.Since(remediation.Status.LastRemediated.Time)
+	if elapsed >= timeout {
+		if remediation.Status.RetryCount < retryLimit {
+			logger.Info("Remediation attempt timed out, retrying",
+				"attempt", remediation.Status.RetryCount, "retryLimit", retryLimit)
+			return r.remediate(ctx, remediation, machineScope, clusterScope)
+		}
+		r.markFailed(remediation, fmt.Sprintf("machine is still unhealthy after %d remediation attempt(s)",
+			remediation.Status.RetryCount))
+		return ctrl.Result{}, nil
+	}
+
+	if remediation.Status.Phase == infrastructurev1.RemediationPhaseRunning && elapsed >= remediationGracePeriod {
+		getStart := time.Now()
+		instance, httpResp, err := machineScope.NcxInfraClient.GetInstance(
+			ctx, machineScope.OrgName, machineScope.InstanceID())
+		apiErr := scope.ClassifyAPIError(httpResp, err, "GetInstance")
+		recordAPIMetrics("GetInstance", getStart, apiErr)
+		if apiErr != nil {
+			logger.Info("Failed to get instance status, will retry", "error", apiErr.Message)
+		} else if instance != nil && instance.Status != nil && *instance.Status == nico.INSTANCESTATUS_READY {
+			logger.Info("Remediated instance is ready, waiting for the node to become healthy")
+			remediation.Status.Phase = infrastructurev1.RemediationPhaseWaiting
+			conditions.Set(remediation, metav1.Condition{
+				Type:   string(InstanceRemediatedCondition),
+				Status: metav1.ConditionTrue,
+				Reason: "InstanceReady",
+			})
+		}
+	}
+
+	requeueAfter := 30 * time.Second
+	if remaining := timeout - elapsed; remaining < requeueAfter {
+		requeueAfter = remaining
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// remediate submits a remediation attempt for the instance of the machine.
+func (r *NcxInfraRemediationReconciler) remediate(
+	ctx context.Context,
+	remediation *infrastructurev1.NcxInfraRemediation,
+	machineScope *scope.MachineScope,
+	clusterScope *scope.ClusterScope,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	strategy := remediation.Spec.Strategy
+	if strategy == "" {
+		strategy = infrastructurev1.RemediationStrategyReimage
+	}
+
+	updateReq := nico.InstanceUpdateRequest{
+		TriggerReboot: *nico.NewNullableBool(nico.PtrBool(true)),
+	}
+	submitted := false
+	if strategy == infrastructurev1.RemediationStrategyReimage {
+		// The reimage provisions the machine again, wait for a provisioning slot of the cluster
+		release, err := r.Machines.acquireProvisioningSlot(ctx, machineScope, clusterScope)
+		if errors.Is(err, errProvisioningLimit) {
+			logger.Info("Waiting for a provisioning slot of the cluster to reimage the instance", "reason", err.Error())
+			conditions.Set(remediation, metav1.Condition{
+				Type:    string(InstanceRemediatedCondition),
+				Status:  metav1.ConditionFalse,
+				Reason:  "WaitingForProvisioningSlot",
+				Message: err.Error(),
+			})
+			return ctrl.Result{RequeueAfter: provisioningSlotRetryInterval}, nil
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		defer func() { release(submitted) }()
+
+		// The bootstrap data brings the node back into the cluster, rendered as for a new instance
+		bootstrapData, err := machineScope.GetBootstrapData(ctx)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to get bootstrap data: %w", err)
+		}
+		updateReq, err = r.Machines.reimageRequest(ctx, machineScope, clusterScope, bootstrapData)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	updateStart := time.Now()
+	_, httpResp, err := machineScope.NcxInfraClient.UpdateInstance(
+		ctx, machineScope.OrgName, machineScope.InstanceID(), updateReq)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "UpdateInstance")
+	recordAPIMetrics("UpdateInstance", updateStart, apiErr)
+	if apiErr != nil {
+		conditions.Set(remediation, metav1.Condition{
+			Type:    string(InstanceRemediatedCondition),
+			Status:  metav1.ConditionFalse,
+			Reason:  "RemediationRequestFailed",
+			Message: apiErr.Message,
+		})
+		if apiErr.IsTransient() {
+			logger.Info("Transient error submitting remediation, will retry", "error", apiErr.Message)
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		if apiErr.IsNotFound() {
+			r.markFailed(remediation, fmt.Sprintf("instance %s no longer exists", machineScope.InstanceID()))
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, apiErr
+	}
+
+	submitted = true
+	now := metav1.Now()
+	remediation.Status.RetryCount++
+	remediation.Status.LastRemediated = &now
+	remediation.Status.Phase = infrastructurev1.RemediationPhaseRunning
+	conditions.Set(remediation, metav1.Condition{
+		Type:    string(InstanceRemediatedCondition),
+		Status:  metav1.ConditionFalse,
+		Reason:  "RemediationInProgress",
+		Message: fmt.Sprintf("%s attempt %d submitted", strategy, remediation.Status.RetryCount),
+	})
+
+	logger.Info("Submitted instance remediation",
+		"strategy", strategy,
+		"attempt", remediation.Status.RetryCount)
+	r.recordEvent(remediation, corev1.EventTypeNormal, "RemediationStarted",
+		"%s of instance %s submitted (attempt %d)", strategy, machineScope.InstanceID(), remediation.Status.RetryCount)
+
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// markFailed gives up on remediating the machine.
+func (r *NcxInfraRemediationReconciler) markFailed(remediation *infrastructurev1.NcxInfraRemediation, message string) {
+	remediation.Status.Phase = infrastructurev1.RemediationPhaseFailed
+	conditions.Set(remediation, metav1.Condition{
+		Type:    string(InstanceRemediatedCondition),
+		Status:  metav1.ConditionFalse,
+		Reason:  "RemediationFailed",
+		Message: message,
+	})
+	r.recordEvent(remediation, corev1.EventTypeWarning, "RemediationFailed", "%s", message)
+}
+
+func (r *NcxInfraRemediationReconciler) recordEvent(
+	obj runtime.Object, eventType, reason, messageFmt string, args ...interface{},
+) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NcxInfraRemediationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Machines == nil {
+		return errors.New("the NcxInfraMachine reconciler is required to reimage the instances")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1.NcxInfraRemediation{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(
+			mgr.GetScheme(), ctrl.Log.WithName("ncxinfraremediation"), r.WatchFilterValue)).
+		Named("ncxinfraremediation").
+		Complete(r)
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+)
+
+var _ = Describe("NcxInfraRemediation Controller", func() {
+	const (
+		clusterName      = "test-cluster"
+		machineName      = "test-machine-0"
+		clusterNamespace = "default"
+		orgName          = "test-org"
+		instanceID       = "instance-uuid"
+	)
+
+	var (
+		ctx            context.Context
+		objects        []client.Object
+		remediation    *infrastructurev1.NcxInfraRemediation
+		namespacedName types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespacedName = types.NamespacedName{Name: machineName, Namespace: clusterNamespace}
+		bootstrapSecretName := "bootstrap-data"
+
+		remediation = &infrastructurev1.NcxInfraRemediation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      machineName,
+				Namespace: clusterNamespace,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: "cluster.x-k8s.io/v1beta2",
+						Kind:       "Machine",
+						Name:       machineName,
+						UID:        "machine-uid",
+					},
+				},
+			},
+			Spec: infrastructurev1.NcxInfraRemediationSpec{
+				Strategy:   infrastructurev1.RemediationStrategyReimage,
+				RetryLimit: 2,
+			},
+		}
+
+		objects = []client.Object{
+			&clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: clusterNamespace},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+						APIGroup: "infrastructure.cluster.x-k8s.io",
+						Kind:     "NcxInfraCluster",
+						Name:     clusterName,
+					},
+				},
+			},
+			&clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      machineName,
+					Namespace: clusterNamespace,
+					UID:       "machine-uid",
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: clusterName,
+					Bootstrap:   clusterv1.Bootstrap{DataSecretName: &bootstrapSecretName},
+					InfrastructureRef: clusterv1.ContractVersionedObjectReference{
+						APIGroup: "infrastructure.cluster.x-k8s.io",
+						Kind:     "NcxInfraMachine",
+						Name:     machineName,
+					},
+				},
+			},
+			&infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: clusterNamespace},
+			},
+			&infrastructurev1.NcxInfraMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: machineName, Namespace: clusterNamespace},
+				Status:     infrastructurev1.NcxInfraMachineStatus{InstanceID: instanceID},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: bootstrapSecretName, Namespace: clusterNamespace},
+				Data:       map[string][]byte{"value": []byte("#cloud-config")},
+			},
+		}
+	})
+
+	newReconciler := func(mockClient *testutil.MockNcxInfraClient) *NcxInfraRemediationReconciler {
+		scheme := newTestScheme()
+		k8sClient := newFakeClientBuilder(scheme).
+			WithObjects(append(objects, remediation)...).
+			WithStatusSubresource(&infrastructurev1.NcxInfraRemediation{}).
+			Build()
+		return &NcxInfraRemediationReconciler{
+			Client:         k8sClient,
+			Scheme:         scheme,
+			NcxInfraClient: mockClient,
+			OrgName:        orgName,
+			Machines:       &NcxInfraMachineReconciler{Client: k8sClient, Scheme: scheme},
+		}
+	}
+
+	getRemediation := func(reconciler *NcxInfraRemediationReconciler) *infrastructurev1.NcxInfraRemediation {
+		updated := &infrastructurev1.NcxInfraRemediation{}
+		Expect(reconciler.Get(ctx, namespacedName, updated)).To(Succeed())
+		return updated
+	}
+
+	It("should reimage the instance with its bootstrap data", func() {
+		var updateReq nico.InstanceUpdateRequest
+		reconciler := newReconciler(&testutil.MockNcxInfraClient{
+			UpdateInstanceFunc: func(
+				ctx context.Context, org, id string, req nico.InstanceUpdateRequest,
+			) (*nico.Instance, *http.Response, error) {
+				Expect(id).To(Equal(instanceID))
+				updateReq = req
+				return &nico.Instance{Id: testutil.Ptr(id)}, testutil.MockHTTPResponse(200), nil
+			},
+		})
+
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).NotTo(BeZero())
+
+		Expect(updateReq.GetTriggerReboot()).To(BeTrue())
+		Expect(updateReq.GetRebootWithCustomIpxe()).To(BeTrue())
+		Expect(updateReq.GetUserData()).To(Equal("#cloud-config"))
+
+		updated := getRemediation(reconciler)
+		Expect(updated.Status.Phase).To(Equal(infrastructurev1.RemediationPhaseRunning))
+		Expect(updated.Status.RetryCount).To(Equal(int32(1)))
+	})
+
+	It("should render the bootstrap data as for a new instance", func() {
+		nvidiaCarbideMachine := objects[3].(*infrastructurev1.NcxInfraMachine)
+		nvidiaCarbideMachine.Spec.OperatingSystem = &infrastructurev1.OSSpec{ID: "os-uuid"}
+		objects[4].(*corev1.Secret).Data["value"] = []byte("#cloud-config\nruncmd:\n- echo ${NCX_INFRA_MACHINE_NAME}\n")
+
+		var updateReq nico.InstanceUpdateRequest
+		reconciler := newReconciler(&testutil.MockNcxInfraClient{
+			UpdateInstanceFunc: func(
+				ctx context.Context, org, id string, req nico.InstanceUpdateRequest,
+			) (*nico.Instance, *http.Response, error) {
+				updateReq = req
+				return &nico.Instance{Id: testutil.Ptr(id)}, testutil.MockHTTPResponse(200), nil
+			},
+		})
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updateReq.GetRebootWithCustomIpxe()).To(BeTrue())
+		Expect(updateReq.GetOperatingSystemId()).To(Equal("os-uuid"))
+		Expect(updateReq.GetUserData()).To(ContainSubstring("echo " + machineName))
+	})
+
+	It("should wait for a provisioning slot of the cluster before reimaging", func() {
+		objects[2].(*infrastructurev1.NcxInfraCluster).Spec.Provisioning = &infrastructurev1.ProvisioningSpec{MaxConcurrent: 1}
+		objects = append(objects, &infrastructurev1.NcxInfraMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-machine-1",
+				Namespace: clusterNamespace,
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: clusterName},
+			},
+			Status: infrastructurev1.NcxInfraMachineStatus{
+				InstanceID:    "other-instance-uuid",
+				InstanceState: string(nico.INSTANCESTATUS_PROVISIONING),
+			},
+		})
+		reconciler := newReconciler(&testutil.MockNcxInfraClient{
+			UpdateInstanceFunc: func(
+				ctx context.Context, org, id string, req nico.InstanceUpdateRequest,
+			) (*nico.Instance, *http.Response, error) {
+				Fail("no reimage should be submitted while the cluster provisions at its limit")
+				return nil, nil, nil
+			},
+		})
+
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(provisioningSlotRetryInterval))
+
+		updated := getRemediation(reconciler)
+		Expect(updated.Status.RetryCount).To(BeZero())
+		condition := conditions.Get(updated, string(InstanceRemediatedCondition))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal("WaitingForProvisioningSlot"))
+	})
+
+	It("should wait for the node once the instance is ready again", func() {
+		lastRemediated := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+		remediation.Status = infrastructurev1.NcxInfraRemediationStatus{
+			Phase:          infrastructurev1.RemediationPhaseRunning,
+			RetryCount:     1,
+			LastRemediated: &lastRemediated,
+		}
+		reconciler := newReconciler(&testutil.MockNcxInfraClient{
+			GetInstanceFunc: func(ctx context.Context, org, id string) (*nico.Instance, *http.Response, error) {
+				status := nico.INSTANCESTATUS_READY
+				return &nico.Instance{Id: testutil.Ptr(id), Status: &status}, testutil.MockHTTPResponse(200), nil
+			},
+		})
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getRemediation(reconciler).Status.Phase).To(Equal(infrastructurev1.RemediationPhaseWaiting))
+	})
+
+	It("should fail once the retry limit is exhausted", func() {
+		lastRemediated := metav1.NewTime(time.Now().Add(-time.Hour))
+		remediation.Status = infrastructurev1.NcxInfraRemediationStatus{
+			Phase:          infrastructurev1.RemediationPhaseWaiting,
+			RetryCount:     2,
+			LastRemediated: &lastRemediated,
+		}
+		reconciler := newReconciler(&testutil.MockNcxInfraClient{
+			UpdateInstanceFunc: func(
+				ctx context.Context, org, id string, req nico.InstanceUpdateRequest,
+			) (*nico.Instance, *http.Response, error) {
+				Fail("no remediation should be submitted after the retry limit")
+				return nil, nil, nil
+			},
+		})
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getRemediation(reconciler).Status.Phase).To(Equal(infrastructurev1.RemediationPhaseFailed))
+	})
+})
@@ -0,0 +1,338 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// siteSyncInterval paces the refresh of the site inventory
+const siteSyncInterval = 5 * time.Minute
+
+// NcxInfraSiteReconciler mirrors the sites visible to the credentials of each
+// NcxInfraCluster, and to the default credentials of the controller, into
+// cluster-scoped NcxInfraSites. A cluster is synchronized when it is created
+// or its spec changes, then the sites of each organization are refreshed once
+// per siteSyncInterval.
+type NcxInfraSiteReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// NcxInfraClient can be set for testing or simulation mode to inject a client
+	NcxInfraClient scope.NcxInfraClientInterface
+	// OrgName can be set for testing or simulation mode
+	OrgName string
+
+	// DefaultCredentials is the credentials secret of the objects that do not
+	// reference one, when their namespace has no default NcxInfraIdentity. The
+	// sites it gives access to are mirrored even before a cluster uses them.
+	DefaultCredentials corev1.SecretReference
+
+	// WatchFilterValue is the value of the cluster.x-k8s.io/watch-filter label
+	// of the objects to reconcile, all of them when empty
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfrasites,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfrasites/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ncxinfraclusters,verbs=get;list;watch
+
+// Reconcile mirrors the sites visible to the credentials of a NcxInfraCluster
+func (r *NcxInfraSiteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	ncxInfraCluster := &infrastructurev1.NcxInfraCluster{}
+	if err := r.Get(ctx, req.NamespacedName, ncxInfraCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !ncxInfraCluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	if annotations.HasPaused(ncxInfraCluster) {
+		logger.Info("NcxInfraCluster is marked as paused, skipping site inventory sync")
+		return ctrl.Result{}, nil
+	}
+
+	ncxInfraClient, orgName, err := r.clusterClient(ctx, ncxInfraCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// The sites are refreshed by syncAllSites afterwards
+	return ctrl.Result{}, r.syncSites(ctx, ncxInfraClient, orgName)
+}
+
+// clusterClient returns the NVIDIA Carbide client and the organization of the
+// credentials of a NcxInfraCluster.
+func (r *NcxInfraSiteReconciler) clusterClient(
+	ctx context.Context, ncxInfraCluster *infrastructurev1.NcxInfraCluster,
+) (scope.NcxInfraClientInterface, string, error) {
+	ncxInfraClient, orgName := r.NcxInfraClient, r.OrgName
+	if ncxInfraClient == nil {
+		var err error
+		ncxInfraClient, orgName, err = scope.NewClientFromSecret(
+			ctx, r.Client, ncxInfraCluster.Spec.Authentication, ncxInfraCluster.Namespace, r.DefaultCredentials,
+			ncxInfraCluster.Spec.SiteRef)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if ncxInfraCluster.Spec.Organization != "" {
+		orgName = ncxInfraCluster.Spec.Organization
+	}
+	return ncxInfraClient, orgName, nil
+}
+
+// siteObjectName returns the name of the NcxInfraSite of a site seen by an
+// organization: the site ID, suffixed with the organization so that the
+// organizations sharing a site each mirror it in their own object.
+func siteObjectName(orgName, siteID string) string {
+	org := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(orgName), "-"), "-")
+	if org == "" {
+		return siteID
+	}
+	return strings.TrimRight(siteID+"-"+org[:min(len(org), 252-len(siteID))], "-")
+}
+
+// syncSites creates or updates the NcxInfraSites of the sites of an
+// organization, and deletes the ones of the organization it no longer sees.
+// The NcxInfraSites of the other organizations are left alone, including the
+// ones of the sites they share with it.
+func (r *NcxInfraSiteReconciler) syncSites(
+	ctx context.Context, ncxInfraClient scope.NcxInfraClientInterface, orgName string,
+) error {
+	logger := log.FromContext(ctx)
+
+	listStart := time.Now()
+	sites, httpResp, err := ncxInfraClient.GetAllSite(ctx, orgName)
+	apiErr := scope.ClassifyAPIError(httpResp, err, "GetAllSite")
+	recordAPIMetrics("GetAllSite", listStart, apiErr)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	discovered := make(map[string]bool, len(sites))
+	for i := range sites {
+		if sites[i].GetId() == "" {
+			continue
+		}
+		if err := r.reconcileSite(ctx, orgName, &sites[i]); err != nil {
+			return err
+		}
+		discovered[siteObjectName(orgName, sites[i].GetId())] = true
+	}
+
+	existing := &infrastructurev1.NcxInfraSiteList{}
+	if err := r.List(ctx, existing); err != nil {
+		return err
+	}
+	for i := range existing.Items {
+		site := &existing.Items[i]
+		if site.Spec.Org != orgName || discovered[site.Name] {
+			continue
+		}
+		logger.Info("Deleting NcxInfraSite no longer visible to the organization", "name", site.Name, "org", orgName)
+		if err := r.Delete(ctx, site); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	logger.V(1).Info("Synchronized sites", "org", orgName, "count", len(discovered))
+	return nil
+}
+
+// reconcileSite creates or updates the NcxInfraSite of a site
+func (r *NcxInfraSiteReconciler) reconcileSite(ctx context.Context, orgName string, s *nico.Site) error {
+	site := &infrastructurev1.NcxInfraSite{}
+	name := siteObjectName(orgName, s.GetId())
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, site); apierrors.IsNotFound(err) {
+		site = &infrastructurev1.NcxInfraSite{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       infrastructurev1.NcxInfraSiteSpec{ID: s.GetId(), Org: orgName},
+		}
+		if err := r.Create(ctx, site); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	// The status is not set on creation, patch it along with the spec
+	patchHelper, err := patch.NewHelper(site, r.Client)
+	if err != nil {
+		return err
+	}
+	site.Spec = infrastructurev1.NcxInfraSiteSpec{
+		ID:          s.GetId(),
+		Name:        s.GetName(),
+		Description: s.GetDescription(),
+		Org:         orgName,
+	}
+	site.Status = siteStatus(s)
+	return patchHelper.Patch(ctx, site)
+}
+
+// siteStatus converts the state, location and capabilities of a site into the
+// status of its NcxInfraSite.
+func siteStatus(s *nico.Site) infrastructurev1.NcxInfraSiteStatus {
+	now := metav1.Now()
+	status := infrastructurev1.NcxInfraSiteStatus{
+		Online:       s.GetIsOnline(),
+		LastSyncTime: &now,
+	}
+	if s.Status != nil {
+		status.State = string(*s.Status)
+	}
+	status.Healthy = status.Online && s.GetStatus() == nico.SITESTATUS_REGISTERED
+	if location := s.Location; location != nil {
+		status.Location = infrastructurev1.SiteLocation{
+			City:    location.GetCity(),
+			State:   location.GetState(),
+			Country: location.GetCountry(),
+		}
+	}
+	if capabilities := s.Capabilities; capabilities != nil {
+		for _, capability := range []struct {
+			name    string
+			enabled bool
+		}{
+			{"NativeNetworking", capabilities.GetNativeNetworking()},
+			{"NetworkSecurityGroup", capabilities.GetNetworkSecurityGroup()},
+			{"NvLinkPartition", capabilities.GetNvLinkPartition()},
+			{"RackLevelAdministration", capabilities.GetRackLevelAdministration()},
+			{"ImageBasedOperatingSystem", capabilities.GetImageBasedOperatingSystem()},
+			{"FaultManagement", capabilities.GetFaultManagement()},
+		} {
+			if capability.enabled {
+				status.Capabilities = append(status.Capabilities, capability.name)
+			}
+		}
+	}
+	if stats := s.MachineStats; stats != nil {
+		status.Machines = stats.GetTotal()
+	}
+	return status
+}
+
+// syncAllSites refreshes the sites of the default credentials and of the
+// credentials of the NcxInfraClusters every siteSyncInterval, until the
+// context is done.
+func (r *NcxInfraSiteReconciler) syncAllSites(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("site-inventory")
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.syncOrganizations(ctx); err != nil {
+			logger.Error(err, "failed to list the NcxInfraClusters to synchronize their sites")
+		}
+	}, siteSyncInterval)
+	return nil
+}
+
+// syncOrganizations synchronizes the sites of each organization once: the
+// organization of the default credentials, then the ones of the credentials
+// of the NcxInfraClusters the controller reconciles. The organizations whose
+// sites cannot be synchronized are logged and retried on the next refresh.
+func (r *NcxInfraSiteReconciler) syncOrganizations(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("site-inventory")
+	synced := map[string]bool{}
+	sync := func(ncxInfraClient scope.NcxInfraClientInterface, orgName string) {
+		if synced[orgName] {
+			return
+		}
+		synced[orgName] = true
+		if err := r.syncSites(ctx, ncxInfraClient, orgName); err != nil {
+			logger.Error(err, "failed to synchronize the sites of the organization", "org", orgName)
+		}
+	}
+
+	if r.DefaultCredentials.Name != "" {
+		ncxInfraClient, orgName := r.NcxInfraClient, r.OrgName
+		if ncxInfraClient == nil {
+			var err error
+			ncxInfraClient, orgName, err = scope.NewClientFromSecret(ctx, r.Client,
+				infrastructurev1.AuthenticationSpec{SecretRef: r.DefaultCredentials},
+				r.DefaultCredentials.Namespace, r.DefaultCredentials, infrastructurev1.SiteReference{})
+			if err != nil {
+				logger.Error(err, "failed to read the default credentials")
+			}
+		}
+		if ncxInfraClient != nil {
+			sync(ncxInfraClient, orgName)
+		}
+	}
+
+	var opts []client.ListOption
+	if r.WatchFilterValue != "" {
+		opts = append(opts, client.MatchingLabels{clusterv1.WatchLabel: r.WatchFilterValue})
+	}
+	clusters := &infrastructurev1.NcxInfraClusterList{}
+	if err := r.List(ctx, clusters, opts...); err != nil {
+		return err
+	}
+	for i := range clusters.Items {
+		ncxInfraCluster := &clusters.Items[i]
+		if !ncxInfraCluster.DeletionTimestamp.IsZero() || annotations.HasPaused(ncxInfraCluster) {
+			continue
+		}
+		ncxInfraClient, orgName, err := r.clusterClient(ctx, ncxInfraCluster)
+		if err != nil {
+			logger.Error(err, "failed to read the credentials of the NcxInfraCluster",
+				"namespace", ncxInfraCluster.Namespace, "name", ncxInfraCluster.Name)
+			continue
+		}
+		sync(ncxInfraClient, orgName)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NcxInfraSiteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.Add(manager.RunnableFunc(r.syncAllSites)); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1.NcxInfraCluster{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(
+			mgr.GetScheme(), ctrl.Log.WithName("ncxinfrasite"), r.WatchFilterValue)).
+		Named("ncxinfrasite").
+		Complete(r)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller/testutil"
+)
+
+var _ = Describe("NcxInfraSite Controller", func() {
+	var (
+		ctx             context.Context
+		ncxInfraCluster *infrastructurev1.NcxInfraCluster
+		sites           []nico.Site
+		syncedOrgs      []string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		syncedOrgs = nil
+		ncxInfraCluster = &infrastructurev1.NcxInfraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: infrastructurev1.NcxInfraClusterSpec{
+				SiteRef: infrastructurev1.SiteReference{Name: "us-west-1"},
+			},
+		}
+		sites = []nico.Site{
+			{
+				Id:       testutil.Ptr("site-west"),
+				Name:     testutil.Ptr("us-west-1"),
+				IsOnline: testutil.Ptr(true),
+				Status:   nico.SITESTATUS_REGISTERED.Ptr(),
+				Location: &nico.SiteLocation{City: testutil.Ptr("Santa Clara"), Country: testutil.Ptr("US")},
+				Capabilities: &nico.SiteCapabilities{
+					NativeNetworking:     testutil.Ptr(true),
+					NetworkSecurityGroup: testutil.Ptr(true),
+					NvLinkPartition:      testutil.Ptr(false),
+				},
+				MachineStats: &nico.SiteMachineStats{Total: testutil.Ptr(int32(72))},
+			},
+			{
+				Id:       testutil.Ptr("site-east"),
+				Name:     testutil.Ptr("us-east-1"),
+				IsOnline: testutil.Ptr(false),
+				Status:   nico.SITESTATUS_REGISTERED.Ptr(),
+			},
+		}
+	})
+
+	newReconciler := func(objects ...client.Object) *NcxInfraSiteReconciler {
+		scheme := newTestScheme()
+		k8sClient := newFakeClientBuilder(scheme).
+			WithObjects(append(objects, ncxInfraCluster)...).
+			WithStatusSubresource(&infrastructurev1.NcxInfraSite{}).
+			Build()
+		return &NcxInfraSiteReconciler{
+			Client: k8sClient,
+			Scheme: scheme,
+			NcxInfraClient: &testutil.MockNcxInfraClient{
+				GetAllSiteFunc: func(ctx context.Context, org string) ([]nico.Site, *http.Response, error) {
+					syncedOrgs = append(syncedOrgs, org)
+					return sites, testutil.MockHTTPResponse(200), nil
+				},
+			},
+			OrgName: "test-org",
+		}
+	}
+
+	It("should mirror the sites of the organization", func() {
+		reconciler := newReconciler()
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "test-cluster", Namespace: "default"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		west := &infrastructurev1.NcxInfraSite{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "site-west-test-org"}, west)).To(Succeed())
+		Expect(west.Spec).To(Equal(infrastructurev1.NcxInfraSiteSpec{ID: "site-west", Name: "us-west-1", Org: "test-org"}))
+		Expect(west.Status.Healthy).To(BeTrue())
+		Expect(west.Status.State).To(Equal("Registered"))
+		Expect(west.Status.Location).To(Equal(infrastructurev1.SiteLocation{City: "Santa Clara", Country: "US"}))
+		Expect(west.Status.Capabilities).To(Equal([]string{"NativeNetworking", "NetworkSecurityGroup"}))
+		Expect(west.Status.Machines).To(Equal(int32(72)))
+		Expect(west.Status.LastSyncTime).NotTo(BeNil())
+
+		east := &infrastructurev1.NcxInfraSite{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "site-east-test-org"}, east)).To(Succeed())
+		Expect(east.Status.Online).To(BeFalse())
+		Expect(east.Status.Healthy).To(BeFalse())
+	})
+
+	It("should delete the sites the organization no longer sees", func() {
+		stale := &infrastructurev1.NcxInfraSite{
+			ObjectMeta: metav1.ObjectMeta{Name: "site-gone"},
+			Spec:       infrastructurev1.NcxInfraSiteSpec{ID: "site-gone", Org: "test-org"},
+		}
+		otherOrg := &infrastructurev1.NcxInfraSite{
+			ObjectMeta: metav1.ObjectMeta{Name: "site-other"},
+			Spec:       infrastructurev1.NcxInfraSiteSpec{ID: "site-other", Org: "other-org"},
+		}
+		reconciler := newReconciler(stale, otherOrg)
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "test-cluster", Namespace: "default"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = reconciler.Get(ctx, client.ObjectKeyFromObject(stale), &infrastructurev1.NcxInfraSite{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(otherOrg), &infrastructurev1.NcxInfraSite{})).
+			To(Succeed())
+	})
+
+	It("should mirror a site shared by several organizations once per organization", func() {
+		reconciler := newReconciler()
+		Expect(reconciler.syncSites(ctx, reconciler.NcxInfraClient, "org-a")).To(Succeed())
+		Expect(reconciler.syncSites(ctx, reconciler.NcxInfraClient, "org-b")).To(Succeed())
+
+		for _, org := range []string{"org-a", "org-b"} {
+			site := &infrastructurev1.NcxInfraSite{}
+			Expect(reconciler.Get(ctx, types.NamespacedName{Name: "site-west-" + org}, site)).To(Succeed())
+			Expect(site.Spec.Org).To(Equal(org))
+		}
+
+		// The site is no longer visible to org-a, org-b still sees it
+		sites = sites[1:]
+		Expect(reconciler.syncSites(ctx, reconciler.NcxInfraClient, "org-a")).To(Succeed())
+		err := reconciler.Get(ctx, types.NamespacedName{Name: "site-west-org-a"}, &infrastructurev1.NcxInfraSite{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "site-west-org-b"}, &infrastructurev1.NcxInfraSite{})).
+			To(Succeed())
+	})
+
+	It("should refresh the sites of each organization once", func() {
+		reconciler := newReconciler(
+			&infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "same-org", Namespace: "default"},
+			},
+			&infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-org", Namespace: "default"},
+				Spec:       infrastructurev1.NcxInfraClusterSpec{Organization: "other-org"},
+			},
+			&infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "paused",
+					Namespace:   "default",
+					Annotations: map[string]string{clusterv1.PausedAnnotation: "true"},
+				},
+				Spec: infrastructurev1.NcxInfraClusterSpec{Organization: "paused-org"},
+			},
+		)
+
+		Expect(reconciler.syncOrganizations(ctx)).To(Succeed())
+		Expect(syncedOrgs).To(ConsistOf("test-org", "other-org"))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "site-west-other-org"}, &infrastructurev1.NcxInfraSite{})).
+			To(Succeed())
+	})
+
+	It("should name the sites after the site ID and the organization", func() {
+		Expect(siteObjectName("My_Org", "site-uuid")).To(Equal("site-uuid-my-org"))
+		Expect(siteObjectName("", "site-uuid")).To(Equal("site-uuid"))
+	})
+})
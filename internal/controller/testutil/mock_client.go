@@ -32,6 +32,9 @@ type MockNcxInfraClient struct {
 	GetVPCFunc func(
 		ctx context.Context, org string, vpcId string,
 	) (*nico.VPC, *http.Response, error)
+	UpdateVPCFunc func(
+		ctx context.Context, org string, vpcId string, req nico.VpcUpdateRequest,
+	) (*nico.VPC, *http.Response, error)
 	DeleteVPCFunc func(
 		ctx context.Context, org string, vpcId string,
 	) (*http.Response, error)
@@ -55,7 +58,7 @@ type MockNcxInfraClient struct {
 		ctx context.Context, org string, instanceId string,
 	) (*nico.Instance, *http.Response, error)
 	DeleteInstanceFunc func(
-		ctx context.Context, org string, instanceId string,
+		ctx context.Context, org string, instanceId string, req *nico.InstanceDeleteRequest,
 	) (*http.Response, error)
 
 	// Network Security Group methods
@@ -90,6 +93,9 @@ type MockNcxInfraClient struct {
 	GetIpblockFunc func(
 		ctx context.Context, org string, ipBlockId string,
 	) (*nico.IpBlock, *http.Response, error)
+	GetAllIpblockFunc func(
+		ctx context.Context, org string, siteId string,
+	) ([]nico.IpBlock, *http.Response, error)
 	DeleteIpblockFunc func(
 		ctx context.Context, org string, ipBlockId string,
 	) (*http.Response, error)
@@ -113,6 +119,9 @@ type MockNcxInfraClient struct {
 	GetCurrentTenantFunc func(
 		ctx context.Context, org string,
 	) (*nico.Tenant, *http.Response, error)
+	GetSelfQuotasFunc func(
+		ctx context.Context, org string,
+	) (*nico.QuotaInfo, *http.Response, error)
 
 	// Instance update and history
 	UpdateInstanceFunc func(
@@ -132,6 +141,47 @@ type MockNcxInfraClient struct {
 	GetMachineFunc func(
 		ctx context.Context, org string, machineId string,
 	) (*nico.Machine, *http.Response, error)
+	GetAllAvailableMachineFunc func(
+		ctx context.Context, org string, siteId string, instanceTypeId string,
+	) ([]nico.Machine, *http.Response, error)
+
+	// Instance Type
+	GetInstanceTypeFunc func(
+		ctx context.Context, org string, instanceTypeId string,
+	) (*nico.InstanceType, *http.Response, error)
+	GetAllInstanceTypeFunc func(
+		ctx context.Context, org string, siteId string,
+	) ([]nico.InstanceType, *http.Response, error)
+
+	// SSH Key Group
+	CreateSshKeyGroupFunc func(
+		ctx context.Context, org string, req nico.SshKeyGroupCreateRequest,
+	) (*nico.SshKeyGroup, *http.Response, error)
+	GetSshKeyGroupFunc func(
+		ctx context.Context, org string, sshKeyGroupId string,
+	) (*nico.SshKeyGroup, *http.Response, error)
+	UpdateSshKeyGroupFunc func(
+		ctx context.Context, org string, sshKeyGroupId string, req nico.SshKeyGroupUpdateRequest,
+	) (*nico.SshKeyGroup, *http.Response, error)
+	DeleteSshKeyGroupFunc func(
+		ctx context.Context, org string, sshKeyGroupId string,
+	) (*http.Response, error)
+
+	// SSH Key methods
+	CreateSshKeyFunc func(
+		ctx context.Context, org string, req nico.SshKeyCreateRequest,
+	) (*nico.SshKey, *http.Response, error)
+	DeleteSshKeyFunc func(
+		ctx context.Context, org string, sshKeyId string,
+	) (*http.Response, error)
+
+	// Tray methods
+	GetAllTrayFunc func(
+		ctx context.Context, org string, siteId string, trayType string, componentId string,
+	) ([]nico.Tray, *http.Response, error)
+	PowerControlTrayFunc func(
+		ctx context.Context, org string, trayId string, req nico.UpdatePowerStateRequest,
+	) (*nico.UpdatePowerStateResponse, *http.Response, error)
 
 	// Health / Fault events
 	ListFaultEventsFunc func(
@@ -180,6 +230,15 @@ func (m *MockNcxInfraClient) GetVpc(
 	return nil, nil, nil
 }
 
+func (m *MockNcxInfraClient) UpdateVpc(
+	ctx context.Context, org string, vpcId string, req nico.VpcUpdateRequest,
+) (*nico.VPC, *http.Response, error) {
+	if m.UpdateVPCFunc != nil {
+		return m.UpdateVPCFunc(ctx, org, vpcId, req)
+	}
+	return nil, nil, nil
+}
+
 func (m *MockNcxInfraClient) DeleteVpc(
 	ctx context.Context, org string, vpcId string,
 ) (*http.Response, error) {
@@ -237,10 +296,10 @@ func (m *MockNcxInfraClient) GetInstance(
 }
 
 func (m *MockNcxInfraClient) DeleteInstance(
-	ctx context.Context, org string, instanceId string,
+	ctx context.Context, org string, instanceId string, req *nico.InstanceDeleteRequest,
 ) (*http.Response, error) {
 	if m.DeleteInstanceFunc != nil {
-		return m.DeleteInstanceFunc(ctx, org, instanceId)
+		return m.DeleteInstanceFunc(ctx, org, instanceId, req)
 	}
 	return nil, nil
 }
@@ -329,6 +388,15 @@ func (m *MockNcxInfraClient) GetIpblock(
 	return nil, nil, nil
 }
 
+func (m *MockNcxInfraClient) GetAllIpblock(
+	ctx context.Context, org string, siteId string,
+) ([]nico.IpBlock, *http.Response, error) {
+	if m.GetAllIpblockFunc != nil {
+		return m.GetAllIpblockFunc(ctx, org, siteId)
+	}
+	return nil, nil, nil
+}
+
 func (m *MockNcxInfraClient) DeleteIpblock(
 	ctx context.Context, org string, ipBlockId string,
 ) (*http.Response, error) {
@@ -375,6 +443,15 @@ func (m *MockNcxInfraClient) GetCurrentTenant(
 	return nil, nil, nil
 }
 
+func (m *MockNcxInfraClient) GetSelfQuotas(
+	ctx context.Context, org string,
+) (*nico.QuotaInfo, *http.Response, error) {
+	if m.GetSelfQuotasFunc != nil {
+		return m.GetSelfQuotasFunc(ctx, org)
+	}
+	return nil, nil, nil
+}
+
 func (m *MockNcxInfraClient) UpdateInstance(
 	ctx context.Context, org string, instanceId string, req nico.InstanceUpdateRequest,
 ) (*nico.Instance, *http.Response, error) {
@@ -412,6 +489,109 @@ func (m *MockNcxInfraClient) GetMachine(
 	return nil, nil, nil
 }
 
+func (m *MockNcxInfraClient) GetAllAvailableMachine(
+	ctx context.Context, org string, siteId string, instanceTypeId string,
+) ([]nico.Machine, *http.Response, error) {
+	if m.GetAllAvailableMachineFunc != nil {
+		return m.GetAllAvailableMachineFunc(ctx, org, siteId, instanceTypeId)
+	}
+	return nil, nil, nil
+}
+
+// Instance Type methods
+func (m *MockNcxInfraClient) GetInstanceType(
+	ctx context.Context, org string, instanceTypeId string,
+) (*nico.InstanceType, *http.Response, error) {
+	if m.GetInstanceTypeFunc != nil {
+		return m.GetInstanceTypeFunc(ctx, org, instanceTypeId)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockNcxInfraClient) GetAllInstanceType(
+	ctx context.Context, org string, siteId string,
+) ([]nico.InstanceType, *http.Response, error) {
+	if m.GetAllInstanceTypeFunc != nil {
+		return m.GetAllInstanceTypeFunc(ctx, org, siteId)
+	}
+	return nil, nil, nil
+}
+
+// SSH Key Group methods
+func (m *MockNcxInfraClient) CreateSshKeyGroup(
+	ctx context.Context, org string, req nico.SshKeyGroupCreateRequest,
+) (*nico.SshKeyGroup, *http.Response, error) {
+	if m.CreateSshKeyGroupFunc != nil {
+		return m.CreateSshKeyGroupFunc(ctx, org, req)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockNcxInfraClient) GetSshKeyGroup(
+	ctx context.Context, org string, sshKeyGroupId string,
+) (*nico.SshKeyGroup, *http.Response, error) {
+	if m.GetSshKeyGroupFunc != nil {
+		return m.GetSshKeyGroupFunc(ctx, org, sshKeyGroupId)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockNcxInfraClient) UpdateSshKeyGroup(
+	ctx context.Context, org string, sshKeyGroupId string, req nico.SshKeyGroupUpdateRequest,
+) (*nico.SshKeyGroup, *http.Response, error) {
+	if m.UpdateSshKeyGroupFunc != nil {
+		return m.UpdateSshKeyGroupFunc(ctx, org, sshKeyGroupId, req)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockNcxInfraClient) DeleteSshKeyGroup(
+	ctx context.Context, org string, sshKeyGroupId string,
+) (*http.Response, error) {
+	if m.DeleteSshKeyGroupFunc != nil {
+		return m.DeleteSshKeyGroupFunc(ctx, org, sshKeyGroupId)
+	}
+	return nil, nil
+}
+
+// SSH Key methods
+func (m *MockNcxInfraClient) CreateSshKey(
+	ctx context.Context, org string, req nico.SshKeyCreateRequest,
+) (*nico.SshKey, *http.Response, error) {
+	if m.CreateSshKeyFunc != nil {
+		return m.CreateSshKeyFunc(ctx, org, req)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockNcxInfraClient) DeleteSshKey(
+	ctx context.Context, org string, sshKeyId string,
+) (*http.Response, error) {
+	if m.DeleteSshKeyFunc != nil {
+		return m.DeleteSshKeyFunc(ctx, org, sshKeyId)
+	}
+	return nil, nil
+}
+
+// Tray methods
+func (m *MockNcxInfraClient) GetAllTray(
+	ctx context.Context, org string, siteId string, trayType string, componentId string,
+) ([]nico.Tray, *http.Response, error) {
+	if m.GetAllTrayFunc != nil {
+		return m.GetAllTrayFunc(ctx, org, siteId, trayType, componentId)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockNcxInfraClient) PowerControlTray(
+	ctx context.Context, org string, trayId string, req nico.UpdatePowerStateRequest,
+) (*nico.UpdatePowerStateResponse, *http.Response, error) {
+	if m.PowerControlTrayFunc != nil {
+		return m.PowerControlTrayFunc(ctx, org, trayId, req)
+	}
+	return nil, nil, nil
+}
+
 // Health / Fault event methods
 func (m *MockNcxInfraClient) ListFaultEvents(
 	ctx context.Context, org string, machineId string, state string, severity string,
@@ -0,0 +1,174 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errors types the errors of the controllers with a reason, reported
+// as the reason of their conditions and as the FailureReason of the machines,
+// and tells terminal errors, which retrying does not fix until the spec, the
+// credentials or the NVIDIA Carbide resources change, from transient ones.
+package errors
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// Reason is a CamelCase reason, valid as the reason of a condition.
+type Reason string
+
+// Credentials
+const (
+	// CredentialsMissing reports a credentials secret that does not exist.
+	CredentialsMissing Reason = "CredentialsMissing"
+	// InvalidCredentials reports credentials rejected by NVIDIA Carbide or
+	// its OAuth2 token URL.
+	InvalidCredentials Reason = "InvalidCredentials"
+	// PermissionDenied reports credentials lacking the role an API call requires.
+	PermissionDenied Reason = "PermissionDenied"
+)
+
+// Site and capacity
+const (
+	// SiteNotFound reports a site that could not be resolved.
+	SiteNotFound Reason = "SiteNotFound"
+	// QuotaExceeded reports an instance quota of the tenant without room left.
+	QuotaExceeded Reason = "QuotaExceeded"
+	// WaitingForCapacity reports a site without an available machine of the
+	// requested instance type.
+	WaitingForCapacity Reason = "WaitingForCapacity"
+	// WaitingForProvisioningSlot reports a cluster already provisioning the
+	// maximum number of instances of spec.provisioning.maxConcurrent.
+	WaitingForProvisioningSlot Reason = "WaitingForProvisioningSlot"
+)
+
+// Cluster resources
+const (
+	// AllocationFailed reports a failure to ensure the IP block and the
+	// allocation of the tenant.
+	AllocationFailed Reason = "AllocationFailed"
+	// VPCCreateFailed reports a failure to create a VPC.
+	VPCCreateFailed Reason = "VPCCreateFailed"
+	// VPCReconcileFailed reports any other failure to reconcile the VPCs.
+	VPCReconcileFailed Reason = "VPCReconcileFailed"
+	// SubnetCreateFailed reports a failure to create a subnet.
+	SubnetCreateFailed Reason = "SubnetCreateFailed"
+	// SubnetReconcileFailed reports any other failure to reconcile the subnets.
+	SubnetReconcileFailed Reason = "SubnetReconcileFailed"
+	// SubnetCIDRMismatch reports a subnet allocated another prefix than its CIDR.
+	SubnetCIDRMismatch Reason = "SubnetCIDRMismatch"
+	// NSGCreateFailed reports a failure to create a network security group.
+	NSGCreateFailed Reason = "NSGCreateFailed"
+	// NSGReconcileFailed reports any other failure to reconcile the network
+	// security group.
+	NSGReconcileFailed Reason = "NSGReconcileFailed"
+	// VPCPrefixReconcileFailed reports a failure to reconcile the VPC prefixes.
+	VPCPrefixReconcileFailed Reason = "VPCPrefixReconcileFailed"
+	// VPCPeeringReconcileFailed reports a failure to reconcile the VPC peerings.
+	VPCPeeringReconcileFailed Reason = "VPCPeeringReconcileFailed"
+	// BreakGlassSSHReconcileFailed reports a failure to reconcile the
+	// break-glass SSH key group.
+	BreakGlassSSHReconcileFailed Reason = "BreakGlassSSHReconcileFailed"
+)
+
+// Machine resources
+const (
+	// InstanceCreateFailed reports a failure to create an instance.
+	InstanceCreateFailed Reason = "InstanceCreateFailed"
+	// InstanceNotFound reports an instance deleted outside of the provider.
+	InstanceNotFound Reason = "InstanceNotFound"
+	// ProvisioningFailed reports an instance in the Error state.
+	ProvisioningFailed Reason = "ProvisioningFailed"
+	// BootstrapDataFailed reports bootstrap data that could not be read.
+	BootstrapDataFailed Reason = "BootstrapDataFailed"
+)
+
+// Error is an error with a reason, terminal or transient.
+type Error struct {
+	Reason   Reason
+	Terminal bool
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Terminal returns err with a reason, as an error that retrying does not fix.
+func Terminal(reason Reason, err error) error {
+	return &Error{Reason: reason, Terminal: true, Err: err}
+}
+
+// Transient returns err with a reason, as an error that may be retried.
+func Transient(reason Reason, err error) error {
+	return &Error{Reason: reason, Err: err}
+}
+
+// Terminalf formats a terminal error with a reason.
+func Terminalf(reason Reason, format string, args ...any) error {
+	return Terminal(reason, fmt.Errorf(format, args...))
+}
+
+// Classify returns err with the reason of the operation that failed, unless
+// it already has one. Missing or rejected credentials and denied API calls
+// get their own reason instead. The errors of the API rejecting a request are
+// terminal, the others transient. It returns nil for a nil error.
+func Classify(reason Reason, err error) error {
+	if err == nil {
+		return nil
+	}
+	var typed *Error
+	if errors.As(err, &typed) {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, scope.ErrCredentialsMissing):
+		return Terminal(CredentialsMissing, err)
+	case scope.AuthenticationFailure(err) != "":
+		return Terminal(InvalidCredentials, err)
+	}
+	var apiErr *scope.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.IsForbidden():
+			return Terminal(PermissionDenied, err)
+		case apiErr.IsTerminal():
+			return Terminal(reason, err)
+		}
+	}
+	return Transient(reason, err)
+}
+
+// ReasonOf returns the reason err is classified with, fallback being the
+// reason of the operation that failed.
+func ReasonOf(err error, fallback Reason) Reason {
+	var typed *Error
+	if errors.As(Classify(fallback, err), &typed) {
+		return typed.Reason
+	}
+	return fallback
+}
+
+// IsTerminal returns whether err is a terminal error.
+func IsTerminal(err error) bool {
+	var typed *Error
+	return errors.As(err, &typed) && typed.Terminal
+}
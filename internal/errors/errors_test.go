@@ -0,0 +1,112 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+func apiError(statusCode int, method string) error {
+	return scope.ClassifyAPIError(&http.Response{StatusCode: statusCode}, fmt.Errorf("HTTP %d", statusCode), method)
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantReason   Reason
+		wantTerminal bool
+	}{
+		{
+			name:       "unknown error is transient",
+			err:        errors.New("connection reset"),
+			wantReason: VPCCreateFailed,
+		},
+		{
+			name:       "server error is transient",
+			err:        fmt.Errorf("failed to create VPC: %w", apiError(http.StatusServiceUnavailable, "CreateVpc")),
+			wantReason: VPCCreateFailed,
+		},
+		{
+			name:         "bad request is terminal",
+			err:          fmt.Errorf("failed to create VPC: %w", apiError(http.StatusBadRequest, "CreateVpc")),
+			wantReason:   VPCCreateFailed,
+			wantTerminal: true,
+		},
+		{
+			name:         "rejected credentials",
+			err:          apiError(http.StatusUnauthorized, "CreateVpc"),
+			wantReason:   InvalidCredentials,
+			wantTerminal: true,
+		},
+		{
+			name:         "denied call",
+			err:          apiError(http.StatusForbidden, "CreateVpc"),
+			wantReason:   PermissionDenied,
+			wantTerminal: true,
+		},
+		{
+			name:         "missing credentials",
+			err:          fmt.Errorf("%w: secret default/creds not found", scope.ErrCredentialsMissing),
+			wantReason:   CredentialsMissing,
+			wantTerminal: true,
+		},
+		{
+			name:       "classified error keeps its reason",
+			err:        fmt.Errorf("failed to reconcile VPC: %w", Transient(QuotaExceeded, errors.New("quota"))),
+			wantReason: QuotaExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Classify(VPCCreateFailed, tt.err)
+			if err.Error() != tt.err.Error() {
+				t.Errorf("Classify() message = %q, want %q", err.Error(), tt.err.Error())
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Classify() does not wrap %v", tt.err)
+			}
+			if got := ReasonOf(err, VPCReconcileFailed); got != tt.wantReason {
+				t.Errorf("ReasonOf() = %s, want %s", got, tt.wantReason)
+			}
+			if got := IsTerminal(err); got != tt.wantTerminal {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.wantTerminal)
+			}
+		})
+	}
+}
+
+func TestClassifyNil(t *testing.T) {
+	if err := Classify(VPCCreateFailed, nil); err != nil {
+		t.Errorf("Classify(nil) = %v, want nil", err)
+	}
+}
+
+func TestReasonOf(t *testing.T) {
+	if got := ReasonOf(errors.New("boom"), SubnetReconcileFailed); got != SubnetReconcileFailed {
+		t.Errorf("ReasonOf() = %s, want the fallback %s", got, SubnetReconcileFailed)
+	}
+	err := fmt.Errorf("failed to reconcile subnets: %w", apiError(http.StatusUnauthorized, "CreateSubnet"))
+	if got := ReasonOf(err, SubnetReconcileFailed); got != InvalidCredentials {
+		t.Errorf("ReasonOf() = %s, want %s", got, InvalidCredentials)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package feature holds the provider feature gates. Gates are toggled with the
+// --feature-gates manager flag, which is also what the Cluster API Operator sets
+// from InfrastructureProvider.spec.manager.featureGates.
+package feature
+
+import (
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+)
+
+var (
+	// MutableGates is the mutable version of Gates, bound to the --feature-gates flag.
+	MutableGates featuregate.MutableFeatureGate = featuregate.NewFeatureGate()
+
+	// Gates is the read-only view used by controllers to check whether a feature is enabled.
+	Gates featuregate.FeatureGate = MutableGates
+)
+
+// Every feature gate should be declared here as a featuregate.Feature constant
+// and registered in defaultFeatureGates with its default state and maturity.
+
+const (
+	// BreakGlassSSH generates an SSH keypair per cluster, stored in a Secret,
+	// and attaches it to all the instances of the cluster as an NVIDIA Carbide
+	// SSH key group, for emergency access.
+	BreakGlassSSH featuregate.Feature = "BreakGlassSSH"
+
+	// LoadBalancer runs the NcxInfraLoadBalancer controller, which assigns
+	// addresses to the Services of type LoadBalancer of the workload clusters.
+	LoadBalancer featuregate.Feature = "LoadBalancer"
+
+	// ManagedCluster runs the NcxInfraManagedCluster controller, which
+	// generates kubeadm clusters and needs the kubeadm bootstrap and control
+	// plane providers to be installed.
+	ManagedCluster featuregate.Feature = "ManagedCluster"
+
+	// ResourceExport keeps the IDs of the NVIDIA Carbide resources of every
+	// cluster in a ConfigMap, and restores the status of the clusters and
+	// machines from it after the management cluster is rebuilt.
+	ResourceExport featuregate.Feature = "ResourceExport"
+)
+
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	BreakGlassSSH:  {Default: false, PreRelease: featuregate.Alpha},
+	LoadBalancer:   {Default: false, PreRelease: featuregate.Alpha},
+	ManagedCluster: {Default: false, PreRelease: featuregate.Alpha},
+	ResourceExport: {Default: false, PreRelease: featuregate.Alpha},
+}
+
+func init() {
+	runtime.Must(MutableGates.Add(defaultFeatureGates))
+}
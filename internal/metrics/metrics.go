@@ -64,6 +64,27 @@ var (
 			Help: "Number of NcxInfraMachines with active health faults",
 		},
 	)
+	MachinesBlockedOnNetwork = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "capi_ncx_infra_machines_blocked_on_network",
+			Help: "Number of NcxInfraMachines waiting for a subnet or VPC prefix of their cluster",
+		},
+	)
+	BootstrapDataSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "capi_ncx_infra_bootstrap_data_bytes",
+			Help:    "Size of the bootstrap data of the instances, at their creation",
+			Buckets: prometheus.ExponentialBuckets(4096, 2, 6),
+		},
+		[]string{"format"},
+	)
+	BootstrapDataAge = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "capi_ncx_infra_bootstrap_data_age_seconds",
+			Help:    "Time from the creation of the bootstrap secret to the creation of the instance",
+			Buckets: []float64{60, 300, 900, 1800, 3600, 7200, 86400},
+		},
+	)
 )
 
 func init() {
@@ -74,5 +95,8 @@ func init() {
 		APILatency,
 		MachinesManaged,
 		MachinesUnhealthy,
+		MachinesBlockedOnNetwork,
+		BootstrapDataSize,
+		BootstrapDataAge,
 	)
 }
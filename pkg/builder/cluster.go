@@ -0,0 +1,176 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package builder constructs NcxInfraCluster, NcxInfraMachine and
+// NcxInfraMachineTemplate objects for platforms that create clusters
+// programmatically.
+//
+// The builders fill the type metadata and validate the objects with the rules
+// of the admission webhooks when they are built, so a missing required field
+// is reported before the object reaches the API server. A builder can be
+// reused: each Build returns a new object.
+package builder
+
+import (
+	"context"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// ClusterBuilder builds a NcxInfraCluster.
+type ClusterBuilder struct {
+	cluster infrastructurev1.NcxInfraCluster
+}
+
+// Cluster starts the NcxInfraCluster name in namespace.
+func Cluster(namespace, name string) *ClusterBuilder {
+	return &ClusterBuilder{cluster: infrastructurev1.NcxInfraCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: infrastructurev1.GroupVersion.String(), Kind: "NcxInfraCluster"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+	}}
+}
+
+// Labels adds labels to the NcxInfraCluster object.
+func (b *ClusterBuilder) Labels(labels map[string]string) *ClusterBuilder {
+	b.cluster.Labels = mergeMaps(b.cluster.Labels, labels)
+	return b
+}
+
+// Annotations adds annotations to the NcxInfraCluster object, such as
+// infrastructurev1.DryRunAnnotation.
+func (b *ClusterBuilder) Annotations(annotations map[string]string) *ClusterBuilder {
+	b.cluster.Annotations = mergeMaps(b.cluster.Annotations, annotations)
+	return b
+}
+
+// SiteID selects the site by its ID.
+func (b *ClusterBuilder) SiteID(id string) *ClusterBuilder {
+	b.cluster.Spec.SiteRef = infrastructurev1.SiteReference{ID: id}
+	return b
+}
+
+// SiteName selects the site by its name.
+func (b *ClusterBuilder) SiteName(name string) *ClusterBuilder {
+	b.cluster.Spec.SiteRef = infrastructurev1.SiteReference{Name: name}
+	return b
+}
+
+// Tenant sets the tenant ID of the cluster.
+func (b *ClusterBuilder) Tenant(id string) *ClusterBuilder {
+	b.cluster.Spec.TenantID = id
+	return b
+}
+
+// VPC sets the name and the network virtualization type of the VPC,
+// ETHERNET_VIRTUALIZER or FNN.
+func (b *ClusterBuilder) VPC(name, networkVirtualizationType string) *ClusterBuilder {
+	b.cluster.Spec.VPC.Name = name
+	b.cluster.Spec.VPC.NetworkVirtualizationType = networkVirtualizationType
+	return b
+}
+
+// VPCLabels adds labels to the VPC.
+func (b *ClusterBuilder) VPCLabels(labels map[string]string) *ClusterBuilder {
+	b.cluster.Spec.VPC.Labels = mergeMaps(b.cluster.Spec.VPC.Labels, labels)
+	return b
+}
+
+// NetworkSecurityGroupRef references a shared NcxInfraNetworkSecurityGroup.
+func (b *ClusterBuilder) NetworkSecurityGroupRef(name string) *ClusterBuilder {
+	b.cluster.Spec.VPC.NetworkSecurityGroupRef = &corev1.LocalObjectReference{Name: name}
+	return b
+}
+
+// Subnet adds a subnet with a role, control-plane or worker.
+func (b *ClusterBuilder) Subnet(name, cidr, role string) *ClusterBuilder {
+	return b.SubnetSpec(infrastructurev1.SubnetSpec{Name: name, CIDR: cidr, Role: role})
+}
+
+// SubnetSpec adds a subnet, for the options without a dedicated method.
+func (b *ClusterBuilder) SubnetSpec(subnet infrastructurev1.SubnetSpec) *ClusterBuilder {
+	b.cluster.Spec.Subnets = append(b.cluster.Spec.Subnets, subnet)
+	return b
+}
+
+// VPCPrefix adds a VPC prefix.
+func (b *ClusterBuilder) VPCPrefix(name, cidr string) *ClusterBuilder {
+	b.cluster.Spec.VPCPrefixes = append(b.cluster.Spec.VPCPrefixes, infrastructurev1.VPCPrefixSpec{Name: name, CIDR: cidr})
+	return b
+}
+
+// VPCPeering adds a peering with another VPC.
+func (b *ClusterBuilder) VPCPeering(peerVPCID string) *ClusterBuilder {
+	b.cluster.Spec.VPCPeerings = append(b.cluster.Spec.VPCPeerings, infrastructurev1.VPCPeeringSpec{PeerVPCID: peerVPCID})
+	return b
+}
+
+// InstanceLabels adds default labels to the instances of the cluster.
+func (b *ClusterBuilder) InstanceLabels(labels map[string]string) *ClusterBuilder {
+	b.cluster.Spec.InstanceLabels = mergeMaps(b.cluster.Spec.InstanceLabels, labels)
+	return b
+}
+
+// ExternalControlPlaneEndpoint sets a control plane endpoint managed outside
+// the provider, by a load balancer for instance.
+func (b *ClusterBuilder) ExternalControlPlaneEndpoint(host string, port int32) *ClusterBuilder {
+	b.cluster.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{Host: host, Port: port}
+	b.cluster.Spec.ControlPlaneEndpointManagement = infrastructurev1.ControlPlaneEndpointExternal
+	return b
+}
+
+// AutoControlPlaneEndpoint sets the control plane endpoint in the spec to the
+// address of the first ready control plane machine, on the given port.
+func (b *ClusterBuilder) AutoControlPlaneEndpoint(port int32) *ClusterBuilder {
+	b.cluster.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{Port: port}
+	b.cluster.Spec.ControlPlaneEndpointManagement = infrastructurev1.ControlPlaneEndpointAuto
+	return b
+}
+
+// CredentialsSecret reads the credentials from a secret. An empty namespace
+// selects the namespace of the cluster.
+func (b *ClusterBuilder) CredentialsSecret(namespace, name string) *ClusterBuilder {
+	b.cluster.Spec.Authentication.SecretRef = corev1.SecretReference{Namespace: namespace, Name: name}
+	return b
+}
+
+// Identity reads the credentials from a NcxInfraClusterIdentity.
+func (b *ClusterBuilder) Identity(name string) *ClusterBuilder {
+	b.cluster.Spec.Authentication.IdentityRef = &corev1.LocalObjectReference{Name: name}
+	return b
+}
+
+// Build returns the NcxInfraCluster, or the validation errors of its spec.
+func (b *ClusterBuilder) Build() (*infrastructurev1.NcxInfraCluster, error) {
+	cluster := b.cluster.DeepCopy()
+	if _, err := cluster.ValidateCreate(context.Background(), cluster); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// mergeMaps returns dst with the entries of src, allocating it when needed.
+func mergeMaps(dst, src map[string]string) map[string]string {
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	maps.Copy(dst, src)
+	return dst
+}
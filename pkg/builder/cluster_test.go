@@ -0,0 +1,87 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+func validCluster() *ClusterBuilder {
+	return Cluster("default", "test").
+		SiteID("site-uuid").
+		Tenant("tenant-uuid").
+		VPC("test-vpc", "FNN").
+		Subnet("control-plane", "10.0.0.0/24", "control-plane")
+}
+
+func TestClusterBuilder_Reuse(t *testing.T) {
+	b := validCluster().InstanceLabels(map[string]string{"team": "ml"})
+	first, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	first.Spec.InstanceLabels["team"] = "changed"
+
+	second, err := b.Subnet("workers", "10.0.1.0/24", "worker").Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if second.Spec.InstanceLabels["team"] != "ml" {
+		t.Errorf("InstanceLabels = %v, want the labels of the builder", second.Spec.InstanceLabels)
+	}
+	if len(first.Spec.Subnets) != 1 || len(second.Spec.Subnets) != 2 {
+		t.Errorf("Subnets = %d and %d, want 1 and 2", len(first.Spec.Subnets), len(second.Spec.Subnets))
+	}
+}
+
+func TestClusterBuilder_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *ClusterBuilder
+		wantErr string
+	}{
+		{
+			name:    "invalid subnet CIDR",
+			builder: validCluster().Subnet("workers", "10.0.1.0", "worker"),
+			wantErr: "spec.subnets[1].cidr",
+		},
+		{
+			name:    "duplicate subnet",
+			builder: validCluster().Subnet("control-plane", "10.0.1.0/24", "worker"),
+			wantErr: "Duplicate value",
+		},
+		{
+			name:    "secret and identity",
+			builder: validCluster().CredentialsSecret("", "creds").Identity("shared"),
+			wantErr: "spec.authentication.identityRef",
+		},
+		{
+			name:    "external endpoint without host",
+			builder: validCluster().ExternalControlPlaneEndpoint("", 6443),
+			wantErr: "spec.controlPlaneEndpoint.host",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Build() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
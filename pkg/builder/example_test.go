@@ -0,0 +1,80 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder_test
+
+import (
+	"fmt"
+
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/builder"
+)
+
+func ExampleCluster() {
+	cluster, err := builder.Cluster("team-a", "gpu-cluster").
+		SiteName("us-west-1").
+		Tenant("tenant-uuid").
+		VPC("gpu-cluster-vpc", "ETHERNET_VIRTUALIZER").
+		Subnet("control-plane", "10.0.0.0/24", "control-plane").
+		Subnet("workers", "10.0.1.0/24", "worker").
+		CredentialsSecret("", "ncx-infra-creds").
+		Build()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(cluster.Kind, cluster.Namespace+"/"+cluster.Name, len(cluster.Spec.Subnets), "subnets")
+	// Output: NcxInfraCluster team-a/gpu-cluster 2 subnets
+}
+
+func ExampleClusterBuilder_Build() {
+	_, err := builder.Cluster("team-a", "gpu-cluster").
+		SiteName("us-west-1").
+		VPC("gpu-cluster-vpc", "ETHERNET_VIRTUALIZER").
+		Build()
+	fmt.Println(err)
+	// Output: [spec.tenantID: Required value: tenant ID must not be empty, spec.subnets: Required value: at least one subnet must be specified]
+}
+
+func ExampleMachine() {
+	machine, err := builder.Machine("team-a", "gpu-cluster-worker-0").
+		ClusterName("gpu-cluster").
+		InstanceType("gb200-instance-type-uuid").
+		OperatingSystem("ubuntu-os-uuid").
+		Subnet("workers").
+		SharedSubnet("storage-cluster", "storage").
+		InstanceLabels(map[string]string{"team": "ml"}).
+		Build()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(machine.Spec.InstanceType.ID, machine.Spec.Network.SubnetName,
+		machine.Spec.Network.AdditionalInterfaces[0].ClusterRef.Name)
+	// Output: gb200-instance-type-uuid workers storage-cluster
+}
+
+func ExampleMachineBuilder_BuildTemplate() {
+	template, err := builder.Machine("team-a", "gpu-cluster-workers").
+		InstanceType("gb200-instance-type-uuid").
+		VPCPrefix("workers").
+		BuildTemplate()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(template.Kind, template.Name, template.Spec.Template.Spec.Network.VPCPrefixName)
+	// Output: NcxInfraMachineTemplate gpu-cluster-workers workers
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// MachineBuilder builds a NcxInfraMachine, or a NcxInfraMachineTemplate
+// creating machines with the same spec.
+type MachineBuilder struct {
+	machine infrastructurev1.NcxInfraMachine
+}
+
+// Machine starts the NcxInfraMachine, or NcxInfraMachineTemplate, name in
+// namespace.
+func Machine(namespace, name string) *MachineBuilder {
+	return &MachineBuilder{machine: infrastructurev1.NcxInfraMachine{
+		TypeMeta:   metav1.TypeMeta{APIVersion: infrastructurev1.GroupVersion.String(), Kind: "NcxInfraMachine"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+	}}
+}
+
+// Labels adds labels to the object.
+func (b *MachineBuilder) Labels(labels map[string]string) *MachineBuilder {
+	b.machine.Labels = mergeMaps(b.machine.Labels, labels)
+	return b
+}
+
+// Annotations adds annotations to the object.
+func (b *MachineBuilder) Annotations(annotations map[string]string) *MachineBuilder {
+	b.machine.Annotations = mergeMaps(b.machine.Annotations, annotations)
+	return b
+}
+
+// ClusterName labels the object with the name of its Cluster.
+func (b *MachineBuilder) ClusterName(name string) *MachineBuilder {
+	return b.Labels(map[string]string{clusterv1.ClusterNameLabel: name})
+}
+
+// InstanceType requests an instance of the instance type id. It replaces a
+// previous MachineID.
+func (b *MachineBuilder) InstanceType(id string) *MachineBuilder {
+	b.machine.Spec.InstanceType.ID = id
+	b.machine.Spec.InstanceType.MachineID = ""
+	return b
+}
+
+// MachineID requests an instance on a specific machine. It replaces a
+// previous InstanceType.
+func (b *MachineBuilder) MachineID(id string) *MachineBuilder {
+	b.machine.Spec.InstanceType.MachineID = id
+	b.machine.Spec.InstanceType.ID = ""
+	return b
+}
+
+// OperatingSystem selects the operating system by its ID.
+func (b *MachineBuilder) OperatingSystem(id string) *MachineBuilder {
+	b.machine.Spec.OperatingSystem = &infrastructurev1.OSSpec{ID: id}
+	return b
+}
+
+// Subnet attaches the primary interface to a subnet of the cluster.
+func (b *MachineBuilder) Subnet(name string) *MachineBuilder {
+	b.machine.Spec.Network.SubnetName = name
+	b.machine.Spec.Network.VPCPrefixName = ""
+	return b
+}
+
+// VPCPrefix attaches the primary interface to a VPC prefix of the cluster.
+func (b *MachineBuilder) VPCPrefix(name string) *MachineBuilder {
+	b.machine.Spec.Network.VPCPrefixName = name
+	b.machine.Spec.Network.SubnetName = ""
+	return b
+}
+
+// IPAddress sets the static address of the primary interface.
+func (b *MachineBuilder) IPAddress(address string) *MachineBuilder {
+	b.machine.Spec.Network.IpAddress = address
+	return b
+}
+
+// AdditionalInterface adds a network interface.
+func (b *MachineBuilder) AdditionalInterface(iface infrastructurev1.NetworkInterface) *MachineBuilder {
+	b.machine.Spec.Network.AdditionalInterfaces = append(b.machine.Spec.Network.AdditionalInterfaces, iface)
+	return b
+}
+
+// SharedSubnet adds an interface on a subnet of another NcxInfraCluster of the
+// namespace.
+func (b *MachineBuilder) SharedSubnet(cluster, subnet string) *MachineBuilder {
+	return b.AdditionalInterface(infrastructurev1.NetworkInterface{
+		SubnetName: subnet,
+		ClusterRef: &corev1.LocalObjectReference{Name: cluster},
+	})
+}
+
+// SSHKeyGroups adds SSH key groups to the instance.
+func (b *MachineBuilder) SSHKeyGroups(ids ...string) *MachineBuilder {
+	b.machine.Spec.SSHKeyGroups = append(b.machine.Spec.SSHKeyGroups, ids...)
+	return b
+}
+
+// InstanceLabels adds labels to the instance. They take precedence over the
+// instance labels of the cluster.
+func (b *MachineBuilder) InstanceLabels(labels map[string]string) *MachineBuilder {
+	b.machine.Spec.Labels = mergeMaps(b.machine.Spec.Labels, labels)
+	return b
+}
+
+// Description sets the description of the instance.
+func (b *MachineBuilder) Description(description string) *MachineBuilder {
+	b.machine.Spec.Description = description
+	return b
+}
+
+// Spec applies a function to the spec, for the options without a dedicated
+// method.
+func (b *MachineBuilder) Spec(mutate func(*infrastructurev1.NcxInfraMachineSpec)) *MachineBuilder {
+	mutate(&b.machine.Spec)
+	return b
+}
+
+// Build returns the NcxInfraMachine, or the validation errors of its spec.
+func (b *MachineBuilder) Build() (*infrastructurev1.NcxInfraMachine, error) {
+	machine := b.machine.DeepCopy()
+	if _, err := machine.ValidateCreate(context.Background(), machine); err != nil {
+		return nil, err
+	}
+	return machine, nil
+}
+
+// BuildTemplate returns a NcxInfraMachineTemplate whose machines have the spec
+// of the builder, or the validation errors of the spec. The labels and
+// annotations are set on the template object.
+func (b *MachineBuilder) BuildTemplate() (*infrastructurev1.NcxInfraMachineTemplate, error) {
+	machine, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &infrastructurev1.NcxInfraMachineTemplate{
+		TypeMeta:   metav1.TypeMeta{APIVersion: infrastructurev1.GroupVersion.String(), Kind: "NcxInfraMachineTemplate"},
+		ObjectMeta: machine.ObjectMeta,
+		Spec: infrastructurev1.NcxInfraMachineTemplateSpec{
+			Template: infrastructurev1.NcxInfraMachineTemplateResource{Spec: machine.Spec},
+		},
+	}, nil
+}
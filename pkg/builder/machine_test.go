@@ -0,0 +1,53 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+func TestMachineBuilder_Build(t *testing.T) {
+	machine, err := Machine("default", "worker-0").
+		ClusterName("test").
+		InstanceType("type-uuid").
+		MachineID("machine-uuid").
+		Subnet("workers").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if machine.Spec.InstanceType != (infrastructurev1.InstanceTypeSpec{MachineID: "machine-uuid"}) {
+		t.Errorf("InstanceType = %+v, want only the machine ID", machine.Spec.InstanceType)
+	}
+	if machine.Labels[clusterv1.ClusterNameLabel] != "test" {
+		t.Errorf("Labels = %v, want the cluster name label", machine.Labels)
+	}
+
+	if _, err := Machine("default", "worker-0").Subnet("workers").Build(); err == nil ||
+		!strings.Contains(err.Error(), "spec.instanceType") {
+		t.Errorf("Build() error = %v, want a missing instance type", err)
+	}
+	if _, err := Machine("default", "worker-0").InstanceType("type-uuid").BuildTemplate(); err == nil ||
+		!strings.Contains(err.Error(), "spec.network") {
+		t.Errorf("BuildTemplate() error = %v, want a missing network", err)
+	}
+}
@@ -0,0 +1,561 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cloudinit injects provider-managed files into cloud-config bootstrap data.
+package cloudinit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Header is the first line of a cloud-config document.
+const Header = "#cloud-config"
+
+// ErrUnsupportedFormat is returned when the bootstrap data is not a cloud-config document.
+var ErrUnsupportedFormat = errors.New("bootstrap data is not a cloud-config document")
+
+// File is a cloud-init write_files entry.
+type File struct {
+	Path        string `json:"path"`
+	Content     string `json:"content"`
+	Owner       string `json:"owner,omitempty"`
+	Permissions string `json:"permissions,omitempty"`
+}
+
+// AppendWriteFiles adds files to the write_files section of a cloud-config document.
+func AppendWriteFiles(userData string, files ...File) (string, error) {
+	if len(files) == 0 {
+		return userData, nil
+	}
+	doc, err := parse(userData)
+	if err != nil {
+		return "", err
+	}
+	if err := appendWriteFiles(doc, files); err != nil {
+		return "", err
+	}
+	return render(doc)
+}
+
+// NetworkServices are the name resolution and time servers of a node.
+type NetworkServices struct {
+	DNSServers    []string
+	SearchDomains []string
+	NTPServers    []string
+}
+
+// IsZero returns true when no server or search domain is configured.
+func (s NetworkServices) IsZero() bool {
+	return len(s.DNSServers) == 0 && len(s.SearchDomains) == 0 && len(s.NTPServers) == 0
+}
+
+// resolvedDropIn is the systemd-resolved configuration written for custom DNS.
+const resolvedDropIn = "/etc/systemd/resolved.conf.d/90-ncx-infra.conf"
+
+// ApplyNetworkServices configures the DNS servers, search domains and NTP
+// servers of a cloud-config document. DNS is configured through a
+// systemd-resolved drop-in, reloaded before the bootstrap commands run, and
+// NTP through the cloud-init ntp module, which picks the time daemon of the
+// distribution.
+func ApplyNetworkServices(userData string, services NetworkServices) (string, error) {
+	if services.IsZero() {
+		return userData, nil
+	}
+	doc, err := parse(userData)
+	if err != nil {
+		return "", err
+	}
+
+	if len(services.DNSServers) > 0 || len(services.SearchDomains) > 0 {
+		var conf strings.Builder
+		conf.WriteString("[Resolve]\n")
+		if len(services.DNSServers) > 0 {
+			fmt.Fprintf(&conf, "DNS=%s\n", strings.Join(services.DNSServers, " "))
+		}
+		if len(services.SearchDomains) > 0 {
+			fmt.Fprintf(&conf, "Domains=%s\n", strings.Join(services.SearchDomains, " "))
+		}
+		if err := appendWriteFiles(doc, []File{{
+			Path:        resolvedDropIn,
+			Content:     conf.String(),
+			Permissions: "0644",
+		}}); err != nil {
+			return "", err
+		}
+		if err := prependRunCmd(doc, "systemctl try-reload-or-restart systemd-resolved"); err != nil {
+			return "", err
+		}
+	}
+
+	if len(services.NTPServers) > 0 {
+		servers := make([]interface{}, 0, len(services.NTPServers))
+		for _, server := range services.NTPServers {
+			servers = append(servers, server)
+		}
+		doc["ntp"] = map[string]interface{}{
+			"enabled": true,
+			"servers": servers,
+		}
+	}
+
+	return render(doc)
+}
+
+// Proxy are the proxy settings of a node.
+type Proxy struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+// Files written to configure the proxy of a node.
+const (
+	proxyProfile          = "/etc/profile.d/90-ncx-infra-proxy.sh"
+	proxyDropIn           = "/etc/systemd/system.conf.d/90-ncx-infra-proxy.conf"
+	proxyContainerdDropIn = "/etc/systemd/system/containerd.service.d/90-ncx-infra-proxy.conf"
+)
+
+// ApplyProxy configures the proxy of a cloud-config document, for login
+// shells through a profile script and for the system services (containerd,
+// kubelet) through the default environment of systemd, reloaded before the
+// bootstrap commands run. containerd also gets a service drop-in, where its
+// documentation configures the proxy.
+func ApplyProxy(userData string, proxy Proxy) (string, error) {
+	if proxy.HTTPProxy == "" && proxy.HTTPSProxy == "" {
+		return userData, nil
+	}
+	doc, err := parse(userData)
+	if err != nil {
+		return "", err
+	}
+
+	var profile, dropIn, containerdDropIn strings.Builder
+	dropIn.WriteString("[Manager]\n")
+	containerdDropIn.WriteString("[Service]\n")
+	for _, v := range []struct{ name, value string }{
+		{"http_proxy", proxy.HTTPProxy},
+		{"https_proxy", proxy.HTTPSProxy},
+		{"no_proxy", proxy.NoProxy},
+	} {
+		if v.value == "" {
+			continue
+		}
+		// Tools disagree on the case of the variables, set both
+		for _, name := range []string{v.name, strings.ToUpper(v.name)} {
+			fmt.Fprintf(&profile, "export %s=%q\n", name, v.value)
+			fmt.Fprintf(&dropIn, "DefaultEnvironment=\"%s=%s\"\n", name, v.value)
+			fmt.Fprintf(&containerdDropIn, "Environment=\"%s=%s\"\n", name, v.value)
+		}
+	}
+	if err := appendWriteFiles(doc, []File{
+		{Path: proxyProfile, Content: profile.String(), Permissions: "0644"},
+		{Path: proxyDropIn, Content: dropIn.String(), Permissions: "0644"},
+		{Path: proxyContainerdDropIn, Content: containerdDropIn.String(), Permissions: "0644"},
+	}); err != nil {
+		return "", err
+	}
+	if err := prependRunCmd(doc,
+		"systemctl daemon-reexec",
+		"systemctl try-restart containerd",
+	); err != nil {
+		return "", err
+	}
+
+	return render(doc)
+}
+
+// RAIDArray is a software RAID array of a node and its filesystem.
+type RAIDArray struct {
+	Name       string
+	Level      int32
+	Devices    []string
+	Filesystem string
+	MountPath  string
+}
+
+// ApplyStorage assembles the RAID arrays of a cloud-config document with
+// mdadm and formats them before the bootstrap commands run. The filesystems
+// are labeled after their array, and mounted by label from fstab so that they
+// come back after a reboot whatever name the array is assembled under. An
+// array whose filesystem already exists is left alone.
+func ApplyStorage(userData string, arrays []RAIDArray) (string, error) {
+	if len(arrays) == 0 {
+		return userData, nil
+	}
+	doc, err := parse(userData)
+	if err != nil {
+		return "", err
+	}
+
+	mounts, err := list(doc, "mounts")
+	if err != nil {
+		return "", err
+	}
+	var cmds []string
+	for _, array := range arrays {
+		fs := array.Filesystem
+		if fs == "" {
+			fs = "xfs"
+		}
+		dev := "/dev/md/" + array.Name
+		cmds = append(cmds, fmt.Sprintf(
+			"blkid -L %s >/dev/null || { mdadm --create %s --run --level=%d --raid-devices=%d %s && mkfs.%s -L %s %s; }",
+			array.Name, dev, array.Level, len(array.Devices), strings.Join(array.Devices, " "), fs, array.Name, dev))
+		if array.MountPath == "" {
+			continue
+		}
+		mounts = append(mounts, []interface{}{"LABEL=" + array.Name, array.MountPath, fs, "defaults,nofail", "0", "2"})
+		cmds = append(cmds, fmt.Sprintf("mkdir -p %[1]s && { mountpoint -q %[1]s || mount %[1]s; }", array.MountPath))
+	}
+	if len(mounts) > 0 {
+		doc["mounts"] = mounts
+	}
+	if err := prependRunCmd(doc, cmds...); err != nil {
+		return "", err
+	}
+
+	return render(doc)
+}
+
+// GPUConfig is the configuration of the GPUs of a node.
+type GPUConfig struct {
+	// MIG maps GPU indexes to the MIG profiles to create on them.
+	MIG map[int32][]string
+	// ComputeMode is the nvidia-smi compute mode of all the GPUs, e.g.
+	// EXCLUSIVE_PROCESS. The driver default is kept when empty.
+	ComputeMode     string
+	PersistenceMode bool
+	FabricManager   bool
+}
+
+// IsZero returns true if the configuration leaves the GPUs as they are.
+func (c GPUConfig) IsZero() bool {
+	return len(c.MIG) == 0 && c.ComputeMode == "" && !c.PersistenceMode && !c.FabricManager
+}
+
+// ApplyGPUConfig configures the GPUs of a cloud-config document with
+// nvidia-smi before the bootstrap commands run, so that the GPUs are
+// partitioned before the kubelet and the device plugins discover them. The
+// existing MIG instances of the listed GPUs are replaced.
+func ApplyGPUConfig(userData string, config GPUConfig) (string, error) {
+	if config.IsZero() {
+		return userData, nil
+	}
+	doc, err := parse(userData)
+	if err != nil {
+		return "", err
+	}
+
+	var cmds []string
+	if config.PersistenceMode {
+		cmds = append(cmds, "nvidia-smi -pm 1")
+	}
+	gpus := make([]int32, 0, len(config.MIG))
+	for gpu := range config.MIG {
+		gpus = append(gpus, gpu)
+	}
+	sort.Slice(gpus, func(i, j int) bool { return gpus[i] < gpus[j] })
+	for _, gpu := range gpus {
+		cmds = append(cmds, fmt.Sprintf(
+			"nvidia-smi -i %[1]d -mig 1 && { nvidia-smi mig -i %[1]d -dci; nvidia-smi mig -i %[1]d -dgi; nvidia-smi mig -i %[1]d -cgi %[2]s -C; }",
+			gpu, strings.Join(config.MIG[gpu], ",")))
+	}
+	if config.ComputeMode != "" {
+		cmds = append(cmds, "nvidia-smi -c "+config.ComputeMode)
+	}
+	if config.FabricManager {
+		cmds = append(cmds, "systemctl enable --now nvidia-fabricmanager")
+	}
+	if err := prependRunCmd(doc, cmds...); err != nil {
+		return "", err
+	}
+
+	return render(doc)
+}
+
+// ApplyHostname sets the hostname of a cloud-config document, and its fully
+// qualified domain name when fqdn is set. cloud-init sets them before the
+// bootstrap commands run, so the node registers under the hostname unless the
+// bootstrap provider sets a node name.
+func ApplyHostname(userData, hostname, fqdn string) (string, error) {
+	if hostname == "" {
+		return userData, nil
+	}
+	doc, err := parse(userData)
+	if err != nil {
+		return "", err
+	}
+
+	doc["hostname"] = hostname
+	if fqdn != "" {
+		doc["fqdn"] = fqdn
+		// Keep the short name as the hostname, the node name follows it
+		doc["prefer_fqdn_over_hostname"] = false
+	}
+
+	return render(doc)
+}
+
+// kernelArgsMarker records that the kernel arguments of the instance were
+// configured, so that they are configured and rebooted into only once.
+const kernelArgsMarker = "/var/lib/cloud/instance/kernel-args-configured"
+
+// ApplyKernelArgs appends arguments to the kernel command line of a
+// cloud-config document. They are configured with grubby where available, or
+// a GRUB drop-in otherwise, by a boot command that then reboots the machine.
+// Boot commands run before the other modules, and the boot command waits for
+// the reboot so that the bootstrap commands only run on the next boot, with the
+// arguments applied.
+func ApplyKernelArgs(userData string, args []string) (string, error) {
+	if len(args) == 0 {
+		return userData, nil
+	}
+	doc, err := parse(userData)
+	if err != nil {
+		return "", err
+	}
+
+	bootCmd, err := list(doc, "bootcmd")
+	if err != nil {
+		return "", err
+	}
+	joined := strings.Join(args, " ")
+	doc["bootcmd"] = append(bootCmd, fmt.Sprintf(
+		"[ -e %[1]s ] || { if command -v grubby >/dev/null; then grubby --update-kernel=ALL --args='%[2]s'; "+
+			"else mkdir -p /etc/default/grub.d && "+
+			"echo 'GRUB_CMDLINE_LINUX_DEFAULT=\"$GRUB_CMDLINE_LINUX_DEFAULT %[2]s\"' > /etc/default/grub.d/99-kernel-args.cfg && "+
+			"update-grub; fi && touch %[1]s && reboot && sleep infinity; }",
+		kernelArgsMarker, joined))
+
+	return render(doc)
+}
+
+// parse decodes a cloud-config document.
+func parse(userData string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(strings.TrimSpace(userData), Header) {
+		return nil, ErrUnsupportedFormat
+	}
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(userData), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse cloud-config: %w", err)
+	}
+	return doc, nil
+}
+
+// render encodes a cloud-config document.
+func render(doc map[string]interface{}) (string, error) {
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to render cloud-config: %w", err)
+	}
+	return Header + "\n" + string(out), nil
+}
+
+// list returns a list section of a cloud-config document.
+func list(doc map[string]interface{}, key string) ([]interface{}, error) {
+	existing, ok := doc[key]
+	if !ok || existing == nil {
+		return nil, nil
+	}
+	items, ok := existing.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cloud-config %s is not a list", key)
+	}
+	return items, nil
+}
+
+func appendWriteFiles(doc map[string]interface{}, files []File) error {
+	writeFiles, err := list(doc, "write_files")
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		writeFiles = append(writeFiles, f)
+	}
+	doc["write_files"] = writeFiles
+	return nil
+}
+
+// prependRunCmd runs commands before the existing ones, i.e. before the
+// bootstrap provider joins the node.
+func prependRunCmd(doc map[string]interface{}, cmds ...string) error {
+	runCmd, err := list(doc, "runcmd")
+	if err != nil {
+		return err
+	}
+	prepended := make([]interface{}, 0, len(cmds)+len(runCmd))
+	for _, cmd := range cmds {
+		prepended = append(prepended, cmd)
+	}
+	doc["runcmd"] = append(prepended, runCmd...)
+	return nil
+}
+
+// kubeadmConfigFiles are the kubeadm configuration files written by the
+// kubeadm bootstrap provider, for the first control plane node and the joining
+// nodes.
+var kubeadmConfigFiles = map[string]bool{
+	"/run/kubeadm/kubeadm.yaml":             true,
+	"/run/kubeadm/kubeadm-join-config.yaml": true,
+}
+
+// kubeadmDocumentSeparator splits a multi-document kubeadm configuration.
+var kubeadmDocumentSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// ApplyNodeLabels registers labels on the node at kubelet startup. For kubeadm
+// nodes, the node-labels argument is added to the kubeletExtraArgs of the
+// nodeRegistration of the kubeadm configuration, which kubeadm writes to the
+// kubelet flags file, so that neither /etc/default/kubelet nor the
+// KUBELET_EXTRA_ARGS of the image override it. For k3s and RKE2 nodes, a
+// config.yaml.d drop-in is written.
+func ApplyNodeLabels(userData string, labels map[string]string) (string, error) {
+	if len(labels) == 0 {
+		return userData, nil
+	}
+	doc, err := parse(userData)
+	if err != nil {
+		return "", err
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	var k3sConfig strings.Builder
+	k3sConfig.WriteString("node-label:\n")
+	for _, k := range keys {
+		pair := k + "=" + labels[k]
+		pairs = append(pairs, pair)
+		fmt.Fprintf(&k3sConfig, "  - %q\n", pair)
+	}
+
+	writeFiles, err := list(doc, "write_files")
+	if err != nil {
+		return "", err
+	}
+	for _, item := range writeFiles {
+		file, ok := item.(map[string]interface{})
+		if !ok || !kubeadmConfigFiles[fmt.Sprint(file["path"])] {
+			continue
+		}
+		// The kubeadm bootstrap provider writes its configuration as plain text
+		if encoding, ok := file["encoding"].(string); ok && encoding != "" && encoding != "text/plain" {
+			return "", fmt.Errorf("kubeadm configuration %s is %s encoded", file["path"], encoding)
+		}
+		content, _ := file["content"].(string)
+		patched, err := addKubeletNodeLabels(content, strings.Join(pairs, ","))
+		if err != nil {
+			return "", fmt.Errorf("failed to add node labels to %s: %w", file["path"], err)
+		}
+		file["content"] = patched
+	}
+
+	if err := appendWriteFiles(doc, []File{{
+		Path:        "/etc/rancher/k3s/config.yaml.d/20-ncx-infra-node-labels.yaml",
+		Content:     k3sConfig.String(),
+		Permissions: "0644",
+	}}); err != nil {
+		return "", err
+	}
+	return render(doc)
+}
+
+// addKubeletNodeLabels adds the node labels to the kubelet arguments of the
+// InitConfiguration and JoinConfiguration documents of a kubeadm configuration.
+// The other documents are kept as is.
+func addKubeletNodeLabels(config, nodeLabels string) (string, error) {
+	documents := kubeadmDocumentSeparator.Split(config, -1)
+	for i, document := range documents {
+		if strings.TrimSpace(document) == "" {
+			continue
+		}
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(document), &obj); err != nil {
+			return "", err
+		}
+		if kind := obj["kind"]; kind != "InitConfiguration" && kind != "JoinConfiguration" {
+			continue
+		}
+
+		registration, _ := obj["nodeRegistration"].(map[string]interface{})
+		if registration == nil {
+			registration = map[string]interface{}{}
+		}
+		switch args := registration["kubeletExtraArgs"].(type) {
+		case map[string]interface{}:
+			registration["kubeletExtraArgs"] = addNodeLabelsArg(args, nodeLabels)
+		case []interface{}:
+			registration["kubeletExtraArgs"] = addNodeLabelsArgList(args, nodeLabels)
+		case nil:
+			// kubeadm v1beta3 takes a map of arguments, v1beta4 a list
+			if obj["apiVersion"] == "kubeadm.k8s.io/v1beta3" {
+				registration["kubeletExtraArgs"] = addNodeLabelsArg(map[string]interface{}{}, nodeLabels)
+			} else {
+				registration["kubeletExtraArgs"] = addNodeLabelsArgList(nil, nodeLabels)
+			}
+		default:
+			return "", fmt.Errorf("%s kubeletExtraArgs is neither a map nor a list", obj["kind"])
+		}
+		obj["nodeRegistration"] = registration
+
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			return "", err
+		}
+		prefix := ""
+		if i > 0 {
+			prefix = "\n"
+		}
+		documents[i] = prefix + string(out)
+	}
+	return strings.Join(documents, "---"), nil
+}
+
+// addNodeLabelsArg adds the node labels to a kubeadm v1beta3 argument map,
+// after the labels already set.
+func addNodeLabelsArg(args map[string]interface{}, nodeLabels string) map[string]interface{} {
+	if existing, ok := args["node-labels"].(string); ok && existing != "" {
+		nodeLabels = existing + "," + nodeLabels
+	}
+	args["node-labels"] = nodeLabels
+	return args
+}
+
+// addNodeLabelsArgList adds the node labels to a kubeadm v1beta4 argument list,
+// after the labels already set.
+func addNodeLabelsArgList(args []interface{}, nodeLabels string) []interface{} {
+	for _, item := range args {
+		arg, ok := item.(map[string]interface{})
+		if !ok || arg["name"] != "node-labels" {
+			continue
+		}
+		if existing, ok := arg["value"].(string); ok && existing != "" {
+			nodeLabels = existing + "," + nodeLabels
+		}
+		arg["value"] = nodeLabels
+		return args
+	}
+	return append(args, map[string]interface{}{"name": "node-labels", "value": nodeLabels})
+}
@@ -0,0 +1,370 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestAppendWriteFiles(t *testing.T) {
+	userData := "#cloud-config\nwrite_files:\n- path: /etc/existing\n  content: hello\nruncmd:\n- kubeadm join\n"
+
+	out, err := AppendWriteFiles(userData, File{Path: "/etc/added", Content: "world"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, Header+"\n") {
+		t.Errorf("expected output to start with %q, got %q", Header, out)
+	}
+
+	var doc struct {
+		WriteFiles []File   `json:"write_files"`
+		RunCmd     []string `json:"runcmd"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	if len(doc.WriteFiles) != 2 || doc.WriteFiles[0].Path != "/etc/existing" || doc.WriteFiles[1].Path != "/etc/added" {
+		t.Errorf("unexpected write_files: %+v", doc.WriteFiles)
+	}
+	if len(doc.RunCmd) != 1 {
+		t.Errorf("expected runcmd to be preserved, got %v", doc.RunCmd)
+	}
+}
+
+func TestAppendWriteFiles_UnsupportedFormat(t *testing.T) {
+	_, err := AppendWriteFiles("#!/bin/bash\necho hello\n", File{Path: "/etc/added"})
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestApplyNodeLabels(t *testing.T) {
+	joinConfig := "apiVersion: kubeadm.k8s.io/v1beta4\nkind: JoinConfiguration\nnodeRegistration:\n" +
+		"  kubeletExtraArgs:\n  - name: node-labels\n    value: pool=gpu\n  - name: provider-id\n    value: nico://x\n"
+	initConfig := "apiVersion: kubeadm.k8s.io/v1beta3\nkind: ClusterConfiguration\nclusterName: prod\n" +
+		"---\napiVersion: kubeadm.k8s.io/v1beta3\nkind: InitConfiguration\nnodeRegistration:\n  name: cp-0\n"
+	userData, err := AppendWriteFiles("#cloud-config\nruncmd:\n- kubeadm join\n",
+		File{Path: "/run/kubeadm/kubeadm-join-config.yaml", Content: joinConfig},
+		File{Path: "/run/kubeadm/kubeadm.yaml", Content: initConfig})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := ApplyNodeLabels(userData, map[string]string{"inventory/rack": "r12", "inventory/datacenter": "dc1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc struct {
+		WriteFiles []File   `json:"write_files"`
+		RunCmd     []string `json:"runcmd"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	if len(doc.WriteFiles) != 3 {
+		t.Fatalf("expected 3 files, got %+v", doc.WriteFiles)
+	}
+
+	var join struct {
+		NodeRegistration struct {
+			KubeletExtraArgs []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"kubeletExtraArgs"`
+		} `json:"nodeRegistration"`
+	}
+	if err := yaml.Unmarshal([]byte(doc.WriteFiles[0].Content), &join); err != nil {
+		t.Fatalf("join configuration is not valid YAML: %v", err)
+	}
+	args := join.NodeRegistration.KubeletExtraArgs
+	if len(args) != 2 || args[0].Name != "node-labels" ||
+		args[0].Value != "pool=gpu,inventory/datacenter=dc1,inventory/rack=r12" {
+		t.Errorf("expected the labels to be added to the kubelet arguments, got %+v", args)
+	}
+
+	documents := strings.Split(doc.WriteFiles[1].Content, "---")
+	if len(documents) != 2 || !strings.Contains(documents[0], "clusterName: prod") {
+		t.Fatalf("expected the cluster configuration to be kept, got %q", doc.WriteFiles[1].Content)
+	}
+	var initConfiguration struct {
+		NodeRegistration struct {
+			Name             string            `json:"name"`
+			KubeletExtraArgs map[string]string `json:"kubeletExtraArgs"`
+		} `json:"nodeRegistration"`
+	}
+	if err := yaml.Unmarshal([]byte(documents[1]), &initConfiguration); err != nil {
+		t.Fatalf("init configuration is not valid YAML: %v", err)
+	}
+	if initConfiguration.NodeRegistration.Name != "cp-0" ||
+		initConfiguration.NodeRegistration.KubeletExtraArgs["node-labels"] != "inventory/datacenter=dc1,inventory/rack=r12" {
+		t.Errorf("unexpected init node registration: %+v", initConfiguration.NodeRegistration)
+	}
+
+	if doc.WriteFiles[2].Path != "/etc/rancher/k3s/config.yaml.d/20-ncx-infra-node-labels.yaml" ||
+		!strings.Contains(doc.WriteFiles[2].Content, `- "inventory/rack=r12"`) {
+		t.Errorf("unexpected k3s config: %+v", doc.WriteFiles[2])
+	}
+	if !reflect.DeepEqual(doc.RunCmd, []string{"kubeadm join"}) {
+		t.Errorf("expected the bootstrap commands to be left as they are, got %q", doc.RunCmd)
+	}
+
+	if out, err := ApplyNodeLabels("#!/bin/bash\n", nil); err != nil || out != "#!/bin/bash\n" {
+		t.Errorf("expected the bootstrap data to be left as it is without labels, got %q (%v)", out, err)
+	}
+	if _, err := ApplyNodeLabels("#!/bin/bash\n", map[string]string{"a": "b"}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestApplyNetworkServices(t *testing.T) {
+	userData := "#cloud-config\nruncmd:\n- kubeadm join\n"
+
+	out, err := ApplyNetworkServices(userData, NetworkServices{
+		DNSServers:    []string{"10.0.0.53", "10.0.1.53"},
+		SearchDomains: []string{"corp.example.com"},
+		NTPServers:    []string{"ntp.corp.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc struct {
+		WriteFiles []File   `json:"write_files"`
+		RunCmd     []string `json:"runcmd"`
+		NTP        struct {
+			Enabled bool     `json:"enabled"`
+			Servers []string `json:"servers"`
+		} `json:"ntp"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	if len(doc.WriteFiles) != 1 ||
+		doc.WriteFiles[0].Content != "[Resolve]\nDNS=10.0.0.53 10.0.1.53\nDomains=corp.example.com\n" {
+		t.Errorf("unexpected write_files: %+v", doc.WriteFiles)
+	}
+	if len(doc.RunCmd) != 2 || doc.RunCmd[1] != "kubeadm join" {
+		t.Errorf("expected resolved to be reloaded before the bootstrap commands, got %v", doc.RunCmd)
+	}
+	if !doc.NTP.Enabled || len(doc.NTP.Servers) != 1 || doc.NTP.Servers[0] != "ntp.corp.example.com" {
+		t.Errorf("unexpected ntp: %+v", doc.NTP)
+	}
+
+	if out, err := ApplyNetworkServices("#!/bin/bash\n", NetworkServices{}); err != nil || out != "#!/bin/bash\n" {
+		t.Errorf("expected bootstrap data without services to be unchanged, got %q (%v)", out, err)
+	}
+}
+
+func TestApplyProxy(t *testing.T) {
+	userData := "#cloud-config\nruncmd:\n- kubeadm join\n"
+
+	out, err := ApplyProxy(userData, Proxy{
+		HTTPProxy:  "http://proxy.corp.example.com:3128",
+		HTTPSProxy: "http://proxy.corp.example.com:3128",
+		NoProxy:    "10.0.0.0/16,.svc",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc struct {
+		WriteFiles []File   `json:"write_files"`
+		RunCmd     []string `json:"runcmd"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	if len(doc.WriteFiles) != 3 {
+		t.Fatalf("expected the profile, systemd and containerd drop-ins, got %+v", doc.WriteFiles)
+	}
+	if !strings.Contains(doc.WriteFiles[0].Content, `export HTTPS_PROXY="http://proxy.corp.example.com:3128"`) {
+		t.Errorf("unexpected profile: %q", doc.WriteFiles[0].Content)
+	}
+	if !strings.Contains(doc.WriteFiles[1].Content, `DefaultEnvironment="no_proxy=10.0.0.0/16,.svc"`) {
+		t.Errorf("unexpected systemd drop-in: %q", doc.WriteFiles[1].Content)
+	}
+	if doc.WriteFiles[2].Path != "/etc/systemd/system/containerd.service.d/90-ncx-infra-proxy.conf" ||
+		!strings.Contains(doc.WriteFiles[2].Content, `Environment="HTTPS_PROXY=http://proxy.corp.example.com:3128"`) {
+		t.Errorf("unexpected containerd drop-in: %+v", doc.WriteFiles[2])
+	}
+	if len(doc.RunCmd) != 3 || doc.RunCmd[2] != "kubeadm join" {
+		t.Errorf("expected systemd to be reloaded before the bootstrap commands, got %v", doc.RunCmd)
+	}
+
+	if out, err := ApplyProxy("#!/bin/bash\n", Proxy{NoProxy: "10.0.0.0/16"}); err != nil || out != "#!/bin/bash\n" {
+		t.Errorf("expected bootstrap data without proxy to be unchanged, got %q (%v)", out, err)
+	}
+}
+
+func TestApplyStorage(t *testing.T) {
+	userData := "#cloud-config\nruncmd:\n- kubeadm join\n"
+
+	out, err := ApplyStorage(userData, []RAIDArray{
+		{Name: "scratch", Level: 0, Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}, MountPath: "/mnt/scratch"},
+		{Name: "logs", Level: 1, Devices: []string{"/dev/sdb", "/dev/sdc"}, Filesystem: "ext4"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc struct {
+		Mounts [][]string `json:"mounts"`
+		RunCmd []string   `json:"runcmd"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	wantRunCmd := []string{
+		"blkid -L scratch >/dev/null || { mdadm --create /dev/md/scratch --run --level=0 --raid-devices=2 " +
+			"/dev/nvme1n1 /dev/nvme2n1 && mkfs.xfs -L scratch /dev/md/scratch; }",
+		"mkdir -p /mnt/scratch && { mountpoint -q /mnt/scratch || mount /mnt/scratch; }",
+		"blkid -L logs >/dev/null || { mdadm --create /dev/md/logs --run --level=1 --raid-devices=2 " +
+			"/dev/sdb /dev/sdc && mkfs.ext4 -L logs /dev/md/logs; }",
+		"kubeadm join",
+	}
+	if !reflect.DeepEqual(doc.RunCmd, wantRunCmd) {
+		t.Errorf("expected the arrays to be created before the bootstrap commands, got %q", doc.RunCmd)
+	}
+	wantMounts := [][]string{{"LABEL=scratch", "/mnt/scratch", "xfs", "defaults,nofail", "0", "2"}}
+	if !reflect.DeepEqual(doc.Mounts, wantMounts) {
+		t.Errorf("expected the scratch volume in fstab, got %v", doc.Mounts)
+	}
+
+	if _, err := ApplyStorage("#!/bin/bash\n", []RAIDArray{{Name: "scratch"}}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestApplyGPUConfig(t *testing.T) {
+	userData := "#cloud-config\nruncmd:\n- kubeadm join\n"
+
+	out, err := ApplyGPUConfig(userData, GPUConfig{
+		MIG:             map[int32][]string{1: {"7g.80gb"}, 0: {"3g.40gb", "1g.10gb"}},
+		ComputeMode:     "EXCLUSIVE_PROCESS",
+		PersistenceMode: true,
+		FabricManager:   true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc struct {
+		RunCmd []string `json:"runcmd"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	wantRunCmd := []string{
+		"nvidia-smi -pm 1",
+		"nvidia-smi -i 0 -mig 1 && { nvidia-smi mig -i 0 -dci; nvidia-smi mig -i 0 -dgi; " +
+			"nvidia-smi mig -i 0 -cgi 3g.40gb,1g.10gb -C; }",
+		"nvidia-smi -i 1 -mig 1 && { nvidia-smi mig -i 1 -dci; nvidia-smi mig -i 1 -dgi; " +
+			"nvidia-smi mig -i 1 -cgi 7g.80gb -C; }",
+		"nvidia-smi -c EXCLUSIVE_PROCESS",
+		"systemctl enable --now nvidia-fabricmanager",
+		"kubeadm join",
+	}
+	if !reflect.DeepEqual(doc.RunCmd, wantRunCmd) {
+		t.Errorf("expected the GPUs to be configured before the bootstrap commands, got %q", doc.RunCmd)
+	}
+
+	if out, err := ApplyGPUConfig("#!/bin/bash\n", GPUConfig{}); err != nil || out != "#!/bin/bash\n" {
+		t.Errorf("expected an empty configuration to leave the bootstrap data as is, got %q, %v", out, err)
+	}
+	if _, err := ApplyGPUConfig("#!/bin/bash\n", GPUConfig{FabricManager: true}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestApplyKernelArgs(t *testing.T) {
+	userData := "#cloud-config\nbootcmd:\n- echo booting\nruncmd:\n- kubeadm join\n"
+
+	out, err := ApplyKernelArgs(userData, []string{"intel_iommu=on", "hugepages=16"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc struct {
+		BootCmd []string `json:"bootcmd"`
+		RunCmd  []string `json:"runcmd"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	if len(doc.BootCmd) != 2 || doc.BootCmd[0] != "echo booting" {
+		t.Fatalf("expected the kernel arguments after the existing boot commands, got %q", doc.BootCmd)
+	}
+	for _, want := range []string{
+		"[ -e " + kernelArgsMarker + " ] ||",
+		"grubby --update-kernel=ALL --args='intel_iommu=on hugepages=16'",
+		`GRUB_CMDLINE_LINUX_DEFAULT="$GRUB_CMDLINE_LINUX_DEFAULT intel_iommu=on hugepages=16"`,
+		"touch " + kernelArgsMarker + " && reboot",
+	} {
+		if !strings.Contains(doc.BootCmd[1], want) {
+			t.Errorf("expected the boot command to contain %q, got %q", want, doc.BootCmd[1])
+		}
+	}
+	if !reflect.DeepEqual(doc.RunCmd, []string{"kubeadm join"}) {
+		t.Errorf("expected the bootstrap commands to be left as they are, got %q", doc.RunCmd)
+	}
+
+	if _, err := ApplyKernelArgs("#!/bin/bash\n", []string{"iommu=pt"}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestApplyHostname(t *testing.T) {
+	userData := "#cloud-config\nruncmd:\n- kubeadm join\n"
+
+	out, err := ApplyHostname(userData, "prod-worker-0", "prod-worker-0.dc1.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc struct {
+		Hostname               string   `json:"hostname"`
+		FQDN                   string   `json:"fqdn"`
+		PreferFQDNOverHostname *bool    `json:"prefer_fqdn_over_hostname"`
+		RunCmd                 []string `json:"runcmd"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	if doc.Hostname != "prod-worker-0" || doc.FQDN != "prod-worker-0.dc1.example.com" {
+		t.Errorf("unexpected hostname %q and fqdn %q", doc.Hostname, doc.FQDN)
+	}
+	if doc.PreferFQDNOverHostname == nil || *doc.PreferFQDNOverHostname {
+		t.Errorf("expected the short hostname to be preferred, got %v", doc.PreferFQDNOverHostname)
+	}
+	if !reflect.DeepEqual(doc.RunCmd, []string{"kubeadm join"}) {
+		t.Errorf("expected the bootstrap commands to be left as they are, got %q", doc.RunCmd)
+	}
+
+	if out, err := ApplyHostname("#!/bin/bash\n", "", ""); err != nil || out != "#!/bin/bash\n" {
+		t.Errorf("expected the bootstrap data to be left as it is without hostname, got %q (%v)", out, err)
+	}
+	if _, err := ApplyHostname("#!/bin/bash\n", "node", ""); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package convert converts the provider API types to and from the NVIDIA
+// Carbide API types.
+package convert
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// AnyPrefix is the prefix of a rule that does not filter on addresses.
+// The API requires both the source and destination prefixes.
+const AnyPrefix = "0.0.0.0/0"
+
+// NSGRules converts the rules of a Network Security Group spec to the NVIDIA
+// Carbide API rules.
+func NSGRules(specRules []infrastructurev1.NSGRule) []nico.NetworkSecurityGroupRule {
+	rules := make([]nico.NetworkSecurityGroupRule, 0, len(specRules))
+	for _, rule := range specRules {
+		rules = append(rules, NSGRule(rule))
+	}
+	return rules
+}
+
+// NSGRulesHash returns a short hash of the NVIDIA Carbide API rules of a
+// Network Security Group spec, which tells whether two specs apply the same rules.
+func NSGRulesHash(specRules []infrastructurev1.NSGRule) string {
+	data, _ := json.Marshal(NSGRules(specRules))
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// NSGRule converts a Network Security Group rule to the NVIDIA Carbide API.
+// The direction, protocol and action are lowercased, the prefixes default to
+// any, and the port range is the destination port range.
+func NSGRule(rule infrastructurev1.NSGRule) nico.NetworkSecurityGroupRule {
+	sourcePrefix := rule.SourceCIDR
+	if sourcePrefix == "" {
+		sourcePrefix = AnyPrefix
+	}
+	destPrefix := rule.DestinationCIDR
+	if destPrefix == "" {
+		destPrefix = AnyPrefix
+	}
+
+	ruleName := rule.Name
+	nsgRule := nico.NetworkSecurityGroupRule{
+		Name:              *nico.NewNullableString(&ruleName),
+		Direction:         strings.ToLower(rule.Direction),
+		Protocol:          strings.ToLower(rule.Protocol),
+		Action:            strings.ToLower(rule.Action),
+		SourcePrefix:      sourcePrefix,
+		DestinationPrefix: destPrefix,
+	}
+
+	if rule.PortRange != "" {
+		portRange := rule.PortRange
+		nsgRule.DestinationPortRange = *nico.NewNullableString(&portRange)
+	}
+	if rule.SourcePortRange != "" {
+		sourcePortRange := rule.SourcePortRange
+		nsgRule.SourcePortRange = *nico.NewNullableString(&sourcePortRange)
+	}
+	if rule.Priority != nil {
+		priority := *rule.Priority
+		nsgRule.Priority = &priority
+	}
+	return nsgRule
+}
+
+// NSGRuleFromAPI converts a NVIDIA Carbide API rule back to a Network Security
+// Group rule. Prefixes matching any address are left empty, so that converting
+// the result back gives the same API rule.
+func NSGRuleFromAPI(apiRule nico.NetworkSecurityGroupRule) infrastructurev1.NSGRule {
+	rule := infrastructurev1.NSGRule{
+		Direction: apiRule.Direction,
+		Protocol:  apiRule.Protocol,
+		Action:    apiRule.Action,
+	}
+	if name := apiRule.Name.Get(); name != nil {
+		rule.Name = *name
+	}
+	if apiRule.SourcePrefix != AnyPrefix {
+		rule.SourceCIDR = apiRule.SourcePrefix
+	}
+	if apiRule.DestinationPrefix != AnyPrefix {
+		rule.DestinationCIDR = apiRule.DestinationPrefix
+	}
+	if portRange := apiRule.DestinationPortRange.Get(); portRange != nil {
+		rule.PortRange = *portRange
+	}
+	if sourcePortRange := apiRule.SourcePortRange.Get(); sourcePortRange != nil {
+		rule.SourcePortRange = *sourcePortRange
+	}
+	if apiRule.Priority != nil {
+		priority := *apiRule.Priority
+		rule.Priority = &priority
+	}
+	return rule
+}
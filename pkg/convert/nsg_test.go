@@ -0,0 +1,222 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestNSGRule(t *testing.T) {
+	tests := []struct {
+		name string
+		rule infrastructurev1.NSGRule
+		want nico.NetworkSecurityGroupRule
+	}{
+		{
+			name: "defaults both prefixes to any",
+			rule: infrastructurev1.NSGRule{
+				Name: "allow-ssh", Direction: "ingress", Protocol: "tcp", PortRange: "22", Action: "allow",
+			},
+			want: nico.NetworkSecurityGroupRule{
+				Name:                 *nico.NewNullableString(ptr("allow-ssh")),
+				Direction:            "ingress",
+				Protocol:             "tcp",
+				Action:               "allow",
+				SourcePrefix:         AnyPrefix,
+				DestinationPrefix:    AnyPrefix,
+				DestinationPortRange: *nico.NewNullableString(ptr("22")),
+			},
+		},
+		{
+			name: "maps the egress destination, source ports and priority",
+			rule: infrastructurev1.NSGRule{
+				Name:            "allow-registry",
+				Direction:       "egress",
+				Protocol:        "tcp",
+				PortRange:       "443",
+				SourcePortRange: "1024-65535",
+				DestinationCIDR: "192.168.10.0/24",
+				Priority:        ptr(int32(100)),
+				Action:          "allow",
+			},
+			want: nico.NetworkSecurityGroupRule{
+				Name:                 *nico.NewNullableString(ptr("allow-registry")),
+				Direction:            "egress",
+				Protocol:             "tcp",
+				Action:               "allow",
+				SourcePrefix:         AnyPrefix,
+				DestinationPrefix:    "192.168.10.0/24",
+				SourcePortRange:      *nico.NewNullableString(ptr("1024-65535")),
+				DestinationPortRange: *nico.NewNullableString(ptr("443")),
+				Priority:             ptr(int32(100)),
+			},
+		},
+		{
+			name: "keeps the source prefix of an ingress rule",
+			rule: infrastructurev1.NSGRule{
+				Name: "allow-api", Direction: "ingress", Protocol: "tcp", PortRange: "6443",
+				SourceCIDR: "10.0.0.0/16", Action: "allow",
+			},
+			want: nico.NetworkSecurityGroupRule{
+				Name:                 *nico.NewNullableString(ptr("allow-api")),
+				Direction:            "ingress",
+				Protocol:             "tcp",
+				Action:               "allow",
+				SourcePrefix:         "10.0.0.0/16",
+				DestinationPrefix:    AnyPrefix,
+				DestinationPortRange: *nico.NewNullableString(ptr("6443")),
+			},
+		},
+		{
+			name: "lowercases the direction, protocol and action",
+			rule: infrastructurev1.NSGRule{Name: "deny-all", Direction: "EGRESS", Protocol: "ALL", Action: "Deny"},
+			want: nico.NetworkSecurityGroupRule{
+				Name:              *nico.NewNullableString(ptr("deny-all")),
+				Direction:         "egress",
+				Protocol:          "all",
+				Action:            "deny",
+				SourcePrefix:      AnyPrefix,
+				DestinationPrefix: AnyPrefix,
+			},
+		},
+		{
+			name: "leaves the ports of an icmp rule unset",
+			rule: infrastructurev1.NSGRule{Name: "allow-ping", Direction: "ingress", Protocol: "icmp", Action: "allow"},
+			want: nico.NetworkSecurityGroupRule{
+				Name:              *nico.NewNullableString(ptr("allow-ping")),
+				Direction:         "ingress",
+				Protocol:          "icmp",
+				Action:            "allow",
+				SourcePrefix:      AnyPrefix,
+				DestinationPrefix: AnyPrefix,
+			},
+		},
+		{
+			name: "keeps a zero priority",
+			rule: infrastructurev1.NSGRule{
+				Name: "first", Direction: "ingress", Protocol: "udp", PortRange: "53", Priority: ptr(int32(0)), Action: "allow",
+			},
+			want: nico.NetworkSecurityGroupRule{
+				Name:                 *nico.NewNullableString(ptr("first")),
+				Direction:            "ingress",
+				Protocol:             "udp",
+				Action:               "allow",
+				SourcePrefix:         AnyPrefix,
+				DestinationPrefix:    AnyPrefix,
+				DestinationPortRange: *nico.NewNullableString(ptr("53")),
+				Priority:             ptr(int32(0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NSGRule(tt.rule); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NSGRule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNSGRules(t *testing.T) {
+	if rules := NSGRules(nil); len(rules) != 0 {
+		t.Errorf("expected no rules, got %+v", rules)
+	}
+
+	rules := NSGRules([]infrastructurev1.NSGRule{
+		{Name: "b", Direction: "ingress", Protocol: "tcp", Action: "allow"},
+		{Name: "a", Direction: "egress", Protocol: "udp", Action: "deny"},
+	})
+	if len(rules) != 2 || *rules[0].Name.Get() != "b" || *rules[1].Name.Get() != "a" {
+		t.Errorf("expected the rules in spec order, got %+v", rules)
+	}
+}
+
+func TestNSGRulesHash(t *testing.T) {
+	rules := []infrastructurev1.NSGRule{
+		{Name: "allow-ssh", Direction: "ingress", Protocol: "tcp", PortRange: "22", Action: "allow"},
+	}
+
+	hash := NSGRulesHash(rules)
+	if len(hash) != 16 {
+		t.Errorf("expected a 16 characters hash, got %q", hash)
+	}
+	if got := NSGRulesHash([]infrastructurev1.NSGRule{
+		{Name: "allow-ssh", Direction: "INGRESS", Protocol: "TCP", PortRange: "22", Action: "Allow", SourceCIDR: AnyPrefix},
+	}); got != hash {
+		t.Errorf("expected the rules applied the same way to have the same hash, got %q and %q", got, hash)
+	}
+	rules[0].PortRange = "2222"
+	if got := NSGRulesHash(rules); got == hash {
+		t.Errorf("expected a different hash for different rules, got %q", got)
+	}
+}
+
+func TestNSGRuleRoundTrip(t *testing.T) {
+	directions := []string{"ingress", "egress"}
+	protocols := []string{"tcp", "udp", "icmp", "all"}
+	actions := []string{"allow", "deny"}
+	cidrs := []string{"", "10.0.0.0/16", AnyPrefix}
+
+	for _, direction := range directions {
+		for _, protocol := range protocols {
+			for _, action := range actions {
+				for _, cidr := range cidrs {
+					rule := infrastructurev1.NSGRule{
+						Name:            "rule",
+						Direction:       direction,
+						Protocol:        protocol,
+						Action:          action,
+						SourceCIDR:      cidr,
+						DestinationCIDR: cidr,
+					}
+					if protocol == "tcp" || protocol == "udp" {
+						rule.PortRange = "1000-2000"
+						rule.SourcePortRange = "1024-65535"
+						rule.Priority = ptr(int32(10))
+					}
+
+					apiRule := NSGRule(rule)
+					back := NSGRuleFromAPI(apiRule)
+					if again := NSGRule(back); !reflect.DeepEqual(again, apiRule) {
+						t.Errorf("%+v: round trip gave %+v, want %+v", rule, again, apiRule)
+					}
+
+					// Converting back only drops the explicit any prefixes
+					want := rule
+					if want.SourceCIDR == AnyPrefix {
+						want.SourceCIDR = ""
+					}
+					if want.DestinationCIDR == AnyPrefix {
+						want.DestinationCIDR = ""
+					}
+					if !reflect.DeepEqual(back, want) {
+						t.Errorf("NSGRuleFromAPI() = %+v, want %+v", back, want)
+					}
+				}
+			}
+		}
+	}
+}
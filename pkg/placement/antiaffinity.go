@@ -0,0 +1,152 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// FailureUnitAntiAffinity enforces spec.placement.antiAffinity by targeting an
+// available machine whose rack, chassis or power domain hosts no other machine
+// of the group of the machine: the control plane, or its MachineDeployment.
+// Without such a machine, a Preferred rule targets the machine on the failure
+// unit hosting the fewest machines of the group.
+type FailureUnitAntiAffinity struct{}
+
+var _ Strategy = FailureUnitAntiAffinity{}
+
+// Place implements Strategy.
+func (FailureUnitAntiAffinity) Place(ctx context.Context, req Request, inventory Inventory) (*Decision, error) {
+	spec := req.Machine.Spec
+	if spec.Placement == nil || spec.Placement.AntiAffinity == nil || spec.InstanceType.ID == "" {
+		return nil, nil
+	}
+	rule := spec.Placement.AntiAffinity
+	required := rule.Mode == infrastructurev1.AntiAffinityRequired
+
+	if !inventory.TargetingEnabled(ctx) {
+		return fallback(required, "tenant does not have targeted instance creation enabled")
+	}
+
+	peers, err := inventory.Peers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the machines of the cluster: %w", err)
+	}
+	group := machineGroup(req.ControlPlane, req.Machine.Labels)
+	used := map[string]int{}
+	for _, peer := range peers {
+		if peer.Placement == nil || machineGroup(peer.ControlPlane, peer.Labels) != group {
+			continue
+		}
+		if unit := placedUnit(rule.Level, peer.Placement); unit != "" {
+			used[unit]++
+		}
+	}
+
+	machines, err := inventory.AvailableMachines(ctx, spec.InstanceType.ID)
+	if err != nil {
+		if required {
+			return nil, err
+		}
+		log.FromContext(ctx).Info("Failed to list available machines, placing without anti-affinity",
+			"error", err.Error())
+		return fallback(false, "failed to list available machines: "+err.Error())
+	}
+
+	var shared *Decision
+	for i := range machines {
+		candidate := &machines[i]
+		if candidate.Id == nil {
+			continue
+		}
+		status := infrastructurev1.PlacementStatus{
+			ChassisSerial: ChassisSerial(candidate),
+			PowerDomain:   candidate.Labels[infrastructurev1.MachinePowerDomainLabel],
+		}
+		if rule.Level == infrastructurev1.FailureUnitRack {
+			if status.Rack, err = inventory.Rack(ctx, *candidate.Id); err != nil {
+				log.FromContext(ctx).Info("Failed to get the rack of a machine", "machineID", *candidate.Id, "error", err.Error())
+				continue
+			}
+		}
+		unit := placedUnit(rule.Level, &status)
+		if unit == "" {
+			continue
+		}
+		if used[unit] == 0 {
+			status.Decision = "Distinct" + string(rule.Level)
+			status.Message = fmt.Sprintf("Machine %s is on a %s without other machines of its group",
+				*candidate.Id, unitName(rule.Level))
+			return &Decision{MachineID: *candidate.Id, Status: status}, nil
+		}
+		if shared == nil || used[unit] < used[placedUnit(rule.Level, &shared.Status)] {
+			shared = &Decision{MachineID: *candidate.Id, Status: status}
+		}
+	}
+
+	if required || shared == nil {
+		return fallback(required, fmt.Sprintf("no available machine on a distinct %s", unitName(rule.Level)))
+	}
+	shared.Status.Decision = "Shared" + string(rule.Level)
+	shared.Status.Message = fmt.Sprintf("No available machine on a distinct %s", unitName(rule.Level))
+	shared.Warning = &Warning{
+		Reason: "AntiAffinityNotSatisfied",
+		Message: fmt.Sprintf("Machine %s shares its %s with %d other machines of its group",
+			shared.MachineID, unitName(rule.Level), used[placedUnit(rule.Level, &shared.Status)]),
+	}
+	return shared, nil
+}
+
+// machineGroup returns the group a machine is spread from the other machines
+// of: the control plane, its MachineDeployment, or else all the worker
+// machines of the cluster.
+func machineGroup(controlPlane bool, labels map[string]string) string {
+	if controlPlane {
+		return "control-plane"
+	}
+	return "deployment/" + labels[clusterv1.MachineDeploymentNameLabel]
+}
+
+// placedUnit returns the failure unit of a placement at a level.
+func placedUnit(level infrastructurev1.FailureUnit, status *infrastructurev1.PlacementStatus) string {
+	switch level {
+	case infrastructurev1.FailureUnitRack:
+		return status.Rack
+	case infrastructurev1.FailureUnitChassis:
+		return status.ChassisSerial
+	case infrastructurev1.FailureUnitPowerDomain:
+		return status.PowerDomain
+	}
+	return ""
+}
+
+// unitName returns the name of a failure unit in messages.
+func unitName(level infrastructurev1.FailureUnit) string {
+	switch level {
+	case infrastructurev1.FailureUnitPowerDomain:
+		return "power domain"
+	case infrastructurev1.FailureUnitChassis:
+		return "chassis"
+	}
+	return "rack"
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+func deploymentRequest(level infrastructurev1.FailureUnit, mode infrastructurev1.AntiAffinityMode) Request {
+	machine := &infrastructurev1.NcxInfraMachine{}
+	machine.Labels = map[string]string{clusterv1.MachineDeploymentNameLabel: "gpu-workers"}
+	machine.Spec.InstanceType.ID = "instance-type-uuid"
+	machine.Spec.Placement = &infrastructurev1.PlacementSpec{
+		AntiAffinity: &infrastructurev1.AntiAffinitySpec{Level: level, Mode: mode},
+	}
+	return Request{Machine: machine, ClusterName: "test-cluster", SiteID: "site-uuid"}
+}
+
+func deploymentPeer(name, deployment string, placement infrastructurev1.PlacementStatus) Peer {
+	return Peer{
+		Name:      name,
+		Labels:    map[string]string{clusterv1.MachineDeploymentNameLabel: deployment},
+		Placement: &placement,
+	}
+}
+
+func TestFailureUnitAntiAffinity_DistinctRack(t *testing.T) {
+	inventory := &fakeInventory{
+		machines: []nico.Machine{
+			availableMachine("machine-a", "chassis-a"),
+			availableMachine("machine-b", "chassis-b"),
+		},
+		racks: map[string]string{"machine-a": "rack-1", "machine-b": "rack-2"},
+		peers: []Peer{
+			deploymentPeer("gpu-workers-0", "gpu-workers", infrastructurev1.PlacementStatus{Rack: "rack-1"}),
+			deploymentPeer("cpu-workers-0", "cpu-workers", infrastructurev1.PlacementStatus{Rack: "rack-2"}),
+		},
+	}
+
+	decision, err := FailureUnitAntiAffinity{}.Place(context.Background(),
+		deploymentRequest(infrastructurev1.FailureUnitRack, infrastructurev1.AntiAffinityRequired), inventory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.MachineID != "machine-b" || decision.Status.Decision != "DistinctRack" || decision.Status.Rack != "rack-2" {
+		t.Errorf("expected machine-b on rack-2, got %+v", decision)
+	}
+}
+
+func TestFailureUnitAntiAffinity_PowerDomain(t *testing.T) {
+	machineA := availableMachine("machine-a", "chassis-a")
+	machineA.Labels = map[string]string{infrastructurev1.MachinePowerDomainLabel: "pdu-1"}
+	machineB := availableMachine("machine-b", "chassis-b")
+	machineB.Labels = map[string]string{infrastructurev1.MachinePowerDomainLabel: "pdu-2"}
+	machineC := availableMachine("machine-c", "chassis-c")
+	machineC.Labels = map[string]string{infrastructurev1.MachinePowerDomainLabel: "pdu-1"}
+	inventory := &fakeInventory{
+		machines: []nico.Machine{machineA, machineB, machineC},
+		peers: []Peer{
+			deploymentPeer("gpu-workers-0", "gpu-workers", infrastructurev1.PlacementStatus{PowerDomain: "pdu-1"}),
+			deploymentPeer("gpu-workers-1", "gpu-workers", infrastructurev1.PlacementStatus{PowerDomain: "pdu-2"}),
+			deploymentPeer("gpu-workers-2", "gpu-workers", infrastructurev1.PlacementStatus{PowerDomain: "pdu-2"}),
+		},
+	}
+	req := deploymentRequest(infrastructurev1.FailureUnitPowerDomain, infrastructurev1.AntiAffinityRequired)
+
+	if _, err := (FailureUnitAntiAffinity{}).Place(context.Background(), req, inventory); !errors.Is(err, ErrPending) {
+		t.Errorf("expected ErrPending, got %v", err)
+	}
+
+	req.Machine.Spec.Placement.AntiAffinity.Mode = infrastructurev1.AntiAffinityPreferred
+	decision, err := FailureUnitAntiAffinity{}.Place(context.Background(), req, inventory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.MachineID != "machine-a" || decision.Status.Decision != "SharedPowerDomain" || decision.Warning == nil {
+		t.Errorf("expected machine-a on the least used power domain with a warning, got %+v", decision)
+	}
+}
+
+func TestFailureUnitAntiAffinity_NotApplicable(t *testing.T) {
+	req := deploymentRequest(infrastructurev1.FailureUnitRack, infrastructurev1.AntiAffinityRequired)
+	req.Machine.Spec.Placement.AntiAffinity = nil
+
+	decision, err := FailureUnitAntiAffinity{}.Place(context.Background(), req, &fakeInventory{})
+	if err != nil || decision != nil {
+		t.Errorf("expected no decision without anti-affinity, got %+v, %v", decision, err)
+	}
+}
+
+func TestChain(t *testing.T) {
+	req := controlPlaneRequest(infrastructurev1.AntiAffinityRequired)
+	inventory := &fakeInventory{machines: []nico.Machine{availableMachine("machine-a", "chassis-a")}}
+
+	decision, err := Default.Place(context.Background(), req, inventory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.MachineID != "machine-a" || decision.Status.Decision != "DistinctChassis" {
+		t.Errorf("expected the chassis anti-affinity to apply, got %+v", decision)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// ChassisAntiAffinity enforces spec.placement.chassisAntiAffinity by targeting
+// an available machine whose chassis hosts no other control plane machine of
+// the cluster. It is the default Strategy.
+type ChassisAntiAffinity struct{}
+
+var _ Strategy = ChassisAntiAffinity{}
+
+// Place implements Strategy.
+func (ChassisAntiAffinity) Place(ctx context.Context, req Request, inventory Inventory) (*Decision, error) {
+	logger := log.FromContext(ctx)
+	spec := req.Machine.Spec
+
+	if spec.Placement == nil || spec.Placement.ChassisAntiAffinity == "" ||
+		spec.Placement.ChassisAntiAffinity == infrastructurev1.AntiAffinityNone ||
+		!req.ControlPlane || spec.InstanceType.ID == "" {
+		return nil, nil
+	}
+	required := spec.Placement.ChassisAntiAffinity == infrastructurev1.AntiAffinityRequired
+
+	if !inventory.TargetingEnabled(ctx) {
+		return fallback(required, "tenant does not have targeted instance creation enabled")
+	}
+
+	peers, err := inventory.Peers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list control plane machines: %w", err)
+	}
+	usedChassis := map[string]bool{}
+	for _, peer := range peers {
+		if peer.ControlPlane && peer.Placement != nil && peer.Placement.ChassisSerial != "" {
+			usedChassis[peer.Placement.ChassisSerial] = true
+		}
+	}
+
+	machines, err := inventory.AvailableMachines(ctx, spec.InstanceType.ID)
+	if err != nil {
+		if required {
+			return nil, err
+		}
+		logger.Info("Failed to list available machines, placing without chassis anti-affinity",
+			"error", err.Error())
+		return fallback(false, "failed to list available machines: "+err.Error())
+	}
+
+	var shared *nico.Machine
+	for i := range machines {
+		candidate := &machines[i]
+		if candidate.Id == nil {
+			continue
+		}
+		chassis := ChassisSerial(candidate)
+		if chassis != "" && !usedChassis[chassis] {
+			return &Decision{
+				MachineID: *candidate.Id,
+				Status: infrastructurev1.PlacementStatus{
+					ChassisSerial: chassis,
+					Decision:      "DistinctChassis",
+					Message:       fmt.Sprintf("Machine %s is on a chassis without other control plane machines", *candidate.Id),
+				},
+			}, nil
+		}
+		if shared == nil {
+			shared = candidate
+		}
+	}
+
+	if required || shared == nil {
+		return fallback(required, "no available machine on a distinct chassis")
+	}
+
+	return &Decision{
+		MachineID: *shared.Id,
+		Status: infrastructurev1.PlacementStatus{
+			ChassisSerial: ChassisSerial(shared),
+			Decision:      "SharedChassis",
+			Message:       "No available machine on a distinct chassis",
+		},
+		Warning: &Warning{
+			Reason:  "ChassisAntiAffinityNotSatisfied",
+			Message: fmt.Sprintf("Machine %s shares its chassis with another control plane machine", *shared.Id),
+		},
+	}, nil
+}
+
+// fallback records that no suitable machine could be targeted. With a Required
+// constraint the instance creation is deferred, otherwise NVIDIA Carbide
+// allocates any machine of the instance type.
+func fallback(required bool, reason string) (*Decision, error) {
+	if required {
+		return nil, fmt.Errorf("%w: %s", ErrPending, reason)
+	}
+	return &Decision{
+		Status: infrastructurev1.PlacementStatus{
+			Decision: "Unconstrained",
+			Message:  reason,
+		},
+	}, nil
+}
+
+// ChassisSerial returns the chassis serial number reported in the machine DMI data.
+func ChassisSerial(machine *nico.Machine) string {
+	if machine.Metadata == nil || machine.Metadata.DmiData == nil || machine.Metadata.DmiData.ChassisSerial == nil {
+		return ""
+	}
+	return *machine.Metadata.DmiData.ChassisSerial
+}
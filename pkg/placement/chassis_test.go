@@ -0,0 +1,142 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+type fakeInventory struct {
+	noTargeting bool
+	machines    []nico.Machine
+	listErr     error
+	peers       []Peer
+	racks       map[string]string
+}
+
+func (f *fakeInventory) TargetingEnabled(context.Context) bool { return !f.noTargeting }
+
+func (f *fakeInventory) AvailableMachines(context.Context, string) ([]nico.Machine, error) {
+	return f.machines, f.listErr
+}
+
+func (f *fakeInventory) Peers(context.Context) ([]Peer, error) { return f.peers, nil }
+
+func (f *fakeInventory) Rack(_ context.Context, machineID string) (string, error) {
+	return f.racks[machineID], nil
+}
+
+func availableMachine(id, chassis string) nico.Machine {
+	return nico.Machine{
+		Id:       &id,
+		Metadata: &nico.MachineMetadata{DmiData: &nico.MachineDMIData{ChassisSerial: &chassis}},
+	}
+}
+
+func controlPlaneRequest(mode infrastructurev1.AntiAffinityMode) Request {
+	machine := &infrastructurev1.NcxInfraMachine{}
+	machine.Spec.InstanceType.ID = "instance-type-uuid"
+	machine.Spec.Placement = &infrastructurev1.PlacementSpec{ChassisAntiAffinity: mode}
+	return Request{Machine: machine, ClusterName: "test-cluster", ControlPlane: true, SiteID: "site-uuid"}
+}
+
+func TestChassisAntiAffinity_DistinctChassis(t *testing.T) {
+	inventory := &fakeInventory{
+		machines: []nico.Machine{
+			availableMachine("machine-a", "chassis-a"),
+			availableMachine("machine-b", "chassis-b"),
+		},
+		peers: []Peer{
+			{Name: "cp-0", ControlPlane: true, Placement: &infrastructurev1.PlacementStatus{ChassisSerial: "chassis-a"}},
+			{Name: "worker-0", Placement: &infrastructurev1.PlacementStatus{ChassisSerial: "chassis-b"}},
+		},
+	}
+
+	decision, err := ChassisAntiAffinity{}.Place(context.Background(),
+		controlPlaneRequest(infrastructurev1.AntiAffinityRequired), inventory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.MachineID != "machine-b" || decision.Status.Decision != "DistinctChassis" {
+		t.Errorf("expected machine-b on a distinct chassis, got %+v", decision)
+	}
+}
+
+func TestChassisAntiAffinity_Required(t *testing.T) {
+	inventory := &fakeInventory{
+		machines: []nico.Machine{availableMachine("machine-a", "chassis-a")},
+		peers: []Peer{
+			{Name: "cp-0", ControlPlane: true, Placement: &infrastructurev1.PlacementStatus{ChassisSerial: "chassis-a"}},
+		},
+	}
+
+	_, err := ChassisAntiAffinity{}.Place(context.Background(),
+		controlPlaneRequest(infrastructurev1.AntiAffinityRequired), inventory)
+	if !errors.Is(err, ErrPending) {
+		t.Errorf("expected ErrPending, got %v", err)
+	}
+
+	inventory.noTargeting = true
+	_, err = ChassisAntiAffinity{}.Place(context.Background(),
+		controlPlaneRequest(infrastructurev1.AntiAffinityRequired), inventory)
+	if !errors.Is(err, ErrPending) {
+		t.Errorf("expected ErrPending without targeted instance creation, got %v", err)
+	}
+}
+
+func TestChassisAntiAffinity_Preferred(t *testing.T) {
+	inventory := &fakeInventory{
+		machines: []nico.Machine{availableMachine("machine-a", "chassis-a")},
+		peers: []Peer{
+			{Name: "cp-0", ControlPlane: true, Placement: &infrastructurev1.PlacementStatus{ChassisSerial: "chassis-a"}},
+		},
+	}
+
+	decision, err := ChassisAntiAffinity{}.Place(context.Background(),
+		controlPlaneRequest(infrastructurev1.AntiAffinityPreferred), inventory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.MachineID != "machine-a" || decision.Status.Decision != "SharedChassis" || decision.Warning == nil {
+		t.Errorf("expected machine-a on a shared chassis with a warning, got %+v", decision)
+	}
+
+	inventory.listErr = errors.New("unavailable")
+	decision, err = ChassisAntiAffinity{}.Place(context.Background(),
+		controlPlaneRequest(infrastructurev1.AntiAffinityPreferred), inventory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.MachineID != "" || decision.Status.Decision != "Unconstrained" {
+		t.Errorf("expected an unconstrained placement, got %+v", decision)
+	}
+}
+
+func TestChassisAntiAffinity_NotApplicable(t *testing.T) {
+	req := controlPlaneRequest(infrastructurev1.AntiAffinityRequired)
+	req.ControlPlane = false
+
+	decision, err := ChassisAntiAffinity{}.Place(context.Background(), req, &fakeInventory{})
+	if err != nil || decision != nil {
+		t.Errorf("expected no decision for a worker machine, got %+v, %v", decision, err)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package placement decides which physical machine an instance is created on.
+//
+// The NcxInfraMachine controller calls a Strategy before creating an instance.
+// The default strategy enforces spec.placement.antiAffinity and
+// spec.placement.chassisAntiAffinity; a fork or downstream build can replace
+// it with its own through the PlacementStrategy field of the reconciler.
+package placement
+
+import (
+	"context"
+	"errors"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// ErrPending is returned when no available machine satisfies a required
+// placement constraint yet. The controller retries the creation later.
+var ErrPending = errors.New("no available machine satisfies the placement constraints")
+
+// Strategy selects the machine, or the instance type, of a new instance.
+type Strategy interface {
+	// Place returns the placement of the instance, or nil to let NVIDIA Carbide
+	// allocate any machine of the instance type from the spec.
+	Place(ctx context.Context, req Request, inventory Inventory) (*Decision, error)
+}
+
+// Request describes the instance to place.
+type Request struct {
+	// Machine is the NcxInfraMachine the instance is created for.
+	Machine *infrastructurev1.NcxInfraMachine
+	// ClusterName is the name of the CAPI cluster the machine belongs to.
+	ClusterName string
+	// ControlPlane is true for control plane machines.
+	ControlPlane bool
+	// SiteID is the NVIDIA Carbide site the instance is created at.
+	SiteID string
+}
+
+// Inventory gives a strategy access to the site and the cluster. Each method
+// queries on demand, so a strategy only pays for the calls it makes.
+type Inventory interface {
+	// TargetingEnabled reports whether the tenant can create an instance on a
+	// given machine.
+	TargetingEnabled(ctx context.Context) bool
+	// AvailableMachines lists the available machines of an instance type at the site.
+	AvailableMachines(ctx context.Context, instanceTypeID string) ([]nico.Machine, error)
+	// Peers lists the other machines of the cluster.
+	Peers(ctx context.Context) ([]Peer, error)
+	// Rack returns the rack hosting the compute tray of a machine, or an
+	// empty string when it is not known.
+	Rack(ctx context.Context, machineID string) (string, error)
+}
+
+// Peer is another machine of the cluster.
+type Peer struct {
+	Name         string
+	ControlPlane bool
+	// Labels are the labels of the NcxInfraMachine.
+	Labels map[string]string
+	// Placement is the recorded placement of the machine, if any.
+	Placement *infrastructurev1.PlacementStatus
+}
+
+// Decision is the outcome of a Strategy.
+type Decision struct {
+	// MachineID targets a specific machine. When empty, NVIDIA Carbide
+	// allocates a machine of the instance type.
+	MachineID string
+	// InstanceTypeID overrides the instance type of the spec. It is ignored
+	// when MachineID is set.
+	InstanceTypeID string
+	// Status is recorded in status.placement of the NcxInfraMachine.
+	Status infrastructurev1.PlacementStatus
+	// Warning, when set, is reported as a warning event on the NcxInfraMachine.
+	Warning *Warning
+}
+
+// Warning is a placement that does not fully satisfy the constraints.
+type Warning struct {
+	Reason  string
+	Message string
+}
+
+// Default is the default Strategy.
+var Default Strategy = Chain{FailureUnitAntiAffinity{}, ChassisAntiAffinity{}}
+
+// Chain applies the first of its strategies that returns a decision.
+type Chain []Strategy
+
+var _ Strategy = Chain{}
+
+// Place implements Strategy.
+func (c Chain) Place(ctx context.Context, req Request, inventory Inventory) (*Decision, error) {
+	for _, strategy := range c {
+		decision, err := strategy.Place(ctx, req, inventory)
+		if err != nil || decision != nil {
+			return decision, err
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Outcomes of an audited API call.
+const (
+	AuditOutcomeSuccess = "Success"
+	AuditOutcomeFailure = "Failure"
+)
+
+// AuditRecord records a mutating NVIDIA Carbide API call.
+type AuditRecord struct {
+	// Time is when the call was made.
+	Time time.Time `json:"time"`
+	// Endpoint is the URL of the NVIDIA Carbide API.
+	Endpoint string `json:"endpoint"`
+	// Org is the organization the call was made in.
+	Org string `json:"org"`
+	// Principal identifies the credentials of the call: the OAuth2 client ID,
+	// or the SHA-256 fingerprint of the API token.
+	Principal string `json:"principal"`
+	// Kind, Namespace and Name identify the object whose reconciliation made
+	// the call, when known.
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// ReconcileID identifies the reconciliation in the controller logs.
+	ReconcileID string `json:"reconcileID,omitempty"`
+	// Method and Path are the HTTP method and the URL path of the call.
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// ResourceID is the ID of the resource created by a successful POST.
+	ResourceID string `json:"resourceID,omitempty"`
+	// RequestID identifies the call in the NVIDIA Carbide audit logs.
+	RequestID string `json:"requestID,omitempty"`
+	// StatusCode is the HTTP status of the response, zero without response.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Outcome is Success or Failure.
+	Outcome string `json:"outcome"`
+	// Error is the error of a call that got no response.
+	Error string `json:"error,omitempty"`
+	// DurationMilliseconds is how long the call took.
+	DurationMilliseconds int64 `json:"durationMilliseconds"`
+}
+
+// AuditSink receives the records of the mutating NVIDIA Carbide API calls.
+type AuditSink interface {
+	Record(record AuditRecord)
+}
+
+// auditSink is the sink of the clients, none unless SetAuditSink is called.
+var auditSink atomic.Pointer[AuditSink]
+
+// SetAuditSink sets the sink recording the mutating NVIDIA Carbide API calls
+// of all the clients, nil disabling the audit.
+func SetAuditSink(sink AuditSink) {
+	if sink == nil {
+		auditSink.Store(nil)
+		return
+	}
+	auditSink.Store(&sink)
+}
+
+// NewAuditSink returns the sink of an --audit-sink target: stdout for JSON
+// lines on the standard output, or the http(s) URL of a webhook receiving each
+// record in a POST request.
+func NewAuditSink(target string) (AuditSink, error) {
+	if target == "stdout" {
+		return NewWriterAuditSink(os.Stdout), nil
+	}
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("audit sink %q is neither stdout nor an http(s) URL", target)
+	}
+	return NewWebhookAuditSink(target, nil), nil
+}
+
+// WriterAuditSink writes the records as JSON lines.
+type WriterAuditSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewWriterAuditSink returns a sink writing the records to w, one JSON
+// object per line.
+func NewWriterAuditSink(w io.Writer) *WriterAuditSink {
+	return &WriterAuditSink{encoder: json.NewEncoder(w)}
+}
+
+func (s *WriterAuditSink) Record(record AuditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.encoder.Encode(record)
+}
+
+// auditQueueSize bounds the records waiting for the webhook.
+const auditQueueSize = 1024
+
+// auditWebhookTimeout bounds each POST to the webhook.
+const auditWebhookTimeout = 10 * time.Second
+
+// WebhookAuditSink posts each record as JSON to a webhook. The records are
+// queued so that a slow webhook does not hold the reconciliations back, and
+// are posted once the sink is started as a runnable of the manager. The
+// records that cannot be delivered, for a full queue or a failed POST, are
+// logged instead so they are not lost.
+type WebhookAuditSink struct {
+	url        string
+	httpClient *http.Client
+	queue      chan AuditRecord
+}
+
+// NewWebhookAuditSink returns a sink posting the records to url through
+// httpClient, http.DefaultClient when nil.
+func NewWebhookAuditSink(url string, httpClient *http.Client) *WebhookAuditSink {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &WebhookAuditSink{
+		url:        url,
+		httpClient: httpClient,
+		queue:      make(chan AuditRecord, auditQueueSize),
+	}
+}
+
+func (s *WebhookAuditSink) Record(record AuditRecord) {
+	select {
+	case s.queue <- record:
+	default:
+		log.Log.WithName("audit").Info("Audit webhook queue full, logging the record instead", "record", record)
+	}
+}
+
+// Start posts the queued records until ctx is done, then posts the records
+// still queued.
+func (s *WebhookAuditSink) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("audit")
+	deliver := func(record AuditRecord) {
+		if err := s.post(record); err != nil {
+			logger.Info("Failed to post the audit record, logging it instead", "record", record, "error", err.Error())
+		}
+	}
+	for {
+		select {
+		case record := <-s.queue:
+			deliver(record)
+		case <-ctx.Done():
+			for {
+				select {
+				case record := <-s.queue:
+					deliver(record)
+				default:
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false, so that the calls of a replica losing
+// the leader election still get delivered.
+func (s *WebhookAuditSink) NeedLeaderElection() bool {
+	return false
+}
+
+// post posts a record to the webhook.
+func (s *WebhookAuditSink) post(record AuditRecord) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), auditWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook answered HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// auditObjectKey is the context key of the object being reconciled.
+type auditObjectKey struct{}
+
+type auditObject struct {
+	kind string
+	key  types.NamespacedName
+}
+
+// WithAuditObject returns a context whose NVIDIA Carbide API calls are
+// recorded as made for the reconciliation of an object.
+func WithAuditObject(ctx context.Context, kind string, key types.NamespacedName) context.Context {
+	return context.WithValue(ctx, auditObjectKey{}, auditObject{kind: kind, key: key})
+}
+
+// tokenPrincipal identifies an API token by its fingerprint, without
+// recording the token.
+func tokenPrincipal(token string) string {
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(sum[:])[:16]
+}
+
+// principal identifies the credentials in the audit records.
+func (c *credentials) principal() string {
+	if c.oauth2 != nil {
+		return c.oauth2.ClientID
+	}
+	return tokenPrincipal(c.token)
+}
+
+// auditTransport records the mutating NVIDIA Carbide API calls in the sink
+// set by SetAuditSink.
+type auditTransport struct {
+	// base is the transport performing the calls, http.DefaultTransport when nil
+	base      http.RoundTripper
+	endpoint  string
+	orgName   string
+	principal string
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	sink := auditSink.Load()
+	if sink == nil || !mutatingMethod(req.Method) {
+		return base.RoundTrip(req)
+	}
+
+	record := AuditRecord{
+		Time:      time.Now().UTC(),
+		Endpoint:  t.endpoint,
+		Org:       t.orgName,
+		Principal: t.principal,
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		Outcome:   AuditOutcomeFailure,
+	}
+	if object, ok := req.Context().Value(auditObjectKey{}).(auditObject); ok {
+		record.Kind, record.Namespace, record.Name = object.kind, object.key.Namespace, object.key.Name
+	}
+	record.ReconcileID = string(controller.ReconcileIDFromContext(req.Context()))
+
+	resp, err := base.RoundTrip(req)
+	record.DurationMilliseconds = time.Since(record.Time).Milliseconds()
+	if err != nil {
+		record.Error = err.Error()
+		(*sink).Record(record)
+		return resp, err
+	}
+
+	record.StatusCode = resp.StatusCode
+	record.RequestID = RequestID(resp)
+	if resp.StatusCode < http.StatusBadRequest {
+		record.Outcome = AuditOutcomeSuccess
+	}
+	if req.Method == http.MethodPost && record.Outcome == AuditOutcomeSuccess {
+		body, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			(*sink).Record(record)
+			return resp, readErr
+		}
+		var created struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(body, &created) == nil {
+			record.ResourceID = created.ID
+		}
+	}
+	(*sink).Record(record)
+	return resp, nil
+}
+
+// mutatingMethod returns whether an HTTP method changes resources.
+func mutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// recordingAuditSink keeps the records in memory.
+type recordingAuditSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+func (s *recordingAuditSink) Record(record AuditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+}
+
+func TestAuditTransport(t *testing.T) {
+	sink := &recordingAuditSink{}
+	SetAuditSink(sink)
+	defer SetAuditSink(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RequestIDHeader, "req-"+strings.ToLower(r.Method))
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"vpc-1","name":"test-vpc"}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: &auditTransport{
+		endpoint:  server.URL,
+		orgName:   "test-org",
+		principal: "capi-client",
+	}}
+	ctx := WithAuditObject(context.Background(), "NcxInfraCluster",
+		types.NamespacedName{Namespace: "default", Name: "test-cluster"})
+
+	for _, call := range []struct{ method, path string }{
+		{http.MethodGet, "/v2/org/test-org/carbide/vpc"},
+		{http.MethodPost, "/v2/org/test-org/carbide/vpc"},
+		{http.MethodDelete, "/v2/org/test-org/carbide/vpc/vpc-0"},
+	} {
+		req, err := http.NewRequestWithContext(ctx, call.method, server.URL+call.path, strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if call.method == http.MethodPost && !strings.Contains(string(body), "test-vpc") {
+			t.Errorf("response body = %q, want it kept for the caller", body)
+		}
+	}
+
+	if len(sink.records) != 2 {
+		t.Fatalf("got %d records, want the POST and the DELETE: %+v", len(sink.records), sink.records)
+	}
+	created, deleted := sink.records[0], sink.records[1]
+	want := AuditRecord{
+		Endpoint:   server.URL,
+		Org:        "test-org",
+		Principal:  "capi-client",
+		Kind:       "NcxInfraCluster",
+		Namespace:  "default",
+		Name:       "test-cluster",
+		Method:     http.MethodPost,
+		Path:       "/v2/org/test-org/carbide/vpc",
+		ResourceID: "vpc-1",
+		RequestID:  "req-post",
+		StatusCode: http.StatusCreated,
+		Outcome:    AuditOutcomeSuccess,
+	}
+	created.Time, created.DurationMilliseconds = want.Time, want.DurationMilliseconds
+	if created != want {
+		t.Errorf("POST record = %+v, want %+v", created, want)
+	}
+	if deleted.Method != http.MethodDelete || deleted.Outcome != AuditOutcomeFailure ||
+		deleted.StatusCode != http.StatusNotFound || deleted.ResourceID != "" {
+		t.Errorf("DELETE record = %+v, want a failure without resource ID", deleted)
+	}
+}
+
+func TestAuditTransportWithoutSink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: &auditTransport{}}
+	resp, err := httpClient.Post(server.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+}
+
+func TestWriterAuditSink(t *testing.T) {
+	var out bytes.Buffer
+	sink := NewWriterAuditSink(&out)
+	sink.Record(AuditRecord{Method: http.MethodPost, Path: "/instance", Outcome: AuditOutcomeSuccess})
+	sink.Record(AuditRecord{Method: http.MethodDelete, Path: "/instance/i-1", Outcome: AuditOutcomeFailure})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), out.String())
+	}
+	var record AuditRecord
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("line %q is not a JSON record: %v", lines[1], err)
+	}
+	if record.Method != http.MethodDelete || record.Outcome != AuditOutcomeFailure {
+		t.Errorf("record = %+v, want the DELETE failure", record)
+	}
+}
+
+func TestWebhookAuditSink(t *testing.T) {
+	received := make(chan AuditRecord, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var record AuditRecord
+		if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+			t.Errorf("body is not a JSON record: %v", err)
+		}
+		received <- record
+	}))
+	defer server.Close()
+
+	sink := NewWebhookAuditSink(server.URL, server.Client())
+	sink.Record(AuditRecord{Method: http.MethodPost, RequestID: "req-1"})
+	sink.Record(AuditRecord{Method: http.MethodDelete, RequestID: "req-2"})
+
+	// The records queued before the manager stops are still delivered
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sink.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	close(received)
+	var requestIDs []string
+	for record := range received {
+		requestIDs = append(requestIDs, record.RequestID)
+	}
+	if strings.Join(requestIDs, ",") != "req-1,req-2" {
+		t.Errorf("webhook received %v, want [req-1 req-2]", requestIDs)
+	}
+}
+
+func TestNewAuditSink(t *testing.T) {
+	tests := []struct {
+		target  string
+		wantErr bool
+	}{
+		{target: "stdout"},
+		{target: "https://audit.example.com/records"},
+		{target: "stderr", wantErr: true},
+		{target: "ftp://audit.example.com", wantErr: true},
+		{target: "https://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			_, err := NewAuditSink(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewAuditSink(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenPrincipal(t *testing.T) {
+	principal := tokenPrincipal("s3cr3t-token")
+	if !strings.HasPrefix(principal, "sha256:") || strings.Contains(principal, "s3cr3t") {
+		t.Errorf("tokenPrincipal() = %q, want a fingerprint of the token", principal)
+	}
+	if tokenPrincipal("s3cr3t-token") != principal {
+		t.Error("tokenPrincipal() is not stable")
+	}
+}
@@ -17,13 +17,30 @@ limitations under the License.
 package scope
 
 import (
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"fmt"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -36,6 +53,7 @@ type NcxInfraClientInterface interface {
 	// VPC
 	CreateVpc(ctx context.Context, org string, req nico.VpcCreateRequest) (*nico.VPC, *http.Response, error)
 	GetVpc(ctx context.Context, org string, vpcId string) (*nico.VPC, *http.Response, error)
+	UpdateVpc(ctx context.Context, org string, vpcId string, req nico.VpcUpdateRequest) (*nico.VPC, *http.Response, error)
 	DeleteVpc(ctx context.Context, org string, vpcId string) (*http.Response, error)
 
 	// Subnet
@@ -46,6 +64,7 @@ type NcxInfraClientInterface interface {
 	// IPBlock
 	CreateIpblock(ctx context.Context, org string, req nico.IpBlockCreateRequest) (*nico.IpBlock, *http.Response, error)
 	GetIpblock(ctx context.Context, org string, ipBlockId string) (*nico.IpBlock, *http.Response, error)
+	GetAllIpblock(ctx context.Context, org string, siteId string) ([]nico.IpBlock, *http.Response, error)
 	DeleteIpblock(ctx context.Context, org string, ipBlockId string) (*http.Response, error)
 
 	// NetworkSecurityGroup
@@ -76,13 +95,16 @@ type NcxInfraClientInterface interface {
 	// Instance
 	CreateInstance(ctx context.Context, org string, req nico.InstanceCreateRequest) (*nico.Instance, *http.Response, error)
 	GetInstance(ctx context.Context, org string, instanceId string) (*nico.Instance, *http.Response, error)
-	DeleteInstance(ctx context.Context, org string, instanceId string) (*http.Response, error)
+	DeleteInstance(
+		ctx context.Context, org string, instanceId string, req *nico.InstanceDeleteRequest,
+	) (*http.Response, error)
 
 	// Site details
 	GetSite(ctx context.Context, org string, siteId string) (*nico.Site, *http.Response, error)
 
 	// Tenant
 	GetCurrentTenant(ctx context.Context, org string) (*nico.Tenant, *http.Response, error)
+	GetSelfQuotas(ctx context.Context, org string) (*nico.QuotaInfo, *http.Response, error)
 
 	// Instance update and history
 	UpdateInstance(
@@ -99,6 +121,35 @@ type NcxInfraClientInterface interface {
 
 	// Machine (physical)
 	GetMachine(ctx context.Context, org string, machineId string) (*nico.Machine, *http.Response, error)
+	GetAllAvailableMachine(
+		ctx context.Context, org string, siteId string, instanceTypeId string,
+	) ([]nico.Machine, *http.Response, error)
+
+	// Instance Type
+	GetInstanceType(ctx context.Context, org string, instanceTypeId string) (*nico.InstanceType, *http.Response, error)
+	GetAllInstanceType(ctx context.Context, org string, siteId string) ([]nico.InstanceType, *http.Response, error)
+
+	// SSH Key Group
+	CreateSshKeyGroup(
+		ctx context.Context, org string, req nico.SshKeyGroupCreateRequest,
+	) (*nico.SshKeyGroup, *http.Response, error)
+	GetSshKeyGroup(ctx context.Context, org string, sshKeyGroupId string) (*nico.SshKeyGroup, *http.Response, error)
+	UpdateSshKeyGroup(
+		ctx context.Context, org string, sshKeyGroupId string, req nico.SshKeyGroupUpdateRequest,
+	) (*nico.SshKeyGroup, *http.Response, error)
+	DeleteSshKeyGroup(ctx context.Context, org string, sshKeyGroupId string) (*http.Response, error)
+
+	// SSH Key
+	CreateSshKey(ctx context.Context, org string, req nico.SshKeyCreateRequest) (*nico.SshKey, *http.Response, error)
+	DeleteSshKey(ctx context.Context, org string, sshKeyId string) (*http.Response, error)
+
+	// Tray (rack component) power control
+	GetAllTray(
+		ctx context.Context, org string, siteId string, trayType string, componentId string,
+	) ([]nico.Tray, *http.Response, error)
+	PowerControlTray(
+		ctx context.Context, org string, trayId string, req nico.UpdatePowerStateRequest,
+	) (*nico.UpdatePowerStateResponse, *http.Response, error)
 
 	// Health / Fault events
 	ListFaultEvents(
@@ -128,13 +179,17 @@ type NcxInfraClientInterface interface {
 	) (*http.Response, error)
 }
 
-// ncxInfraClient wraps the SDK APIClient and injects auth context
+// ncxInfraClient wraps the SDK APIClient and injects auth context. Clients
+// using OAuth2 have no static token: their transport adds the access token.
 type ncxInfraClient struct {
 	client *nico.APIClient
 	token  string
 }
 
 func (c *ncxInfraClient) authCtx(ctx context.Context) context.Context {
+	if c.token == "" {
+		return ctx
+	}
 	return context.WithValue(ctx, nico.ContextAccessToken, c.token)
 }
 
@@ -147,6 +202,11 @@ func (c *ncxInfraClient) CreateVpc(
 func (c *ncxInfraClient) GetVpc(ctx context.Context, org, vpcId string) (*nico.VPC, *http.Response, error) {
 	return c.client.VPCAPI.GetVpc(c.authCtx(ctx), org, vpcId).Execute()
 }
+func (c *ncxInfraClient) UpdateVpc(
+	ctx context.Context, org, vpcId string, req nico.VpcUpdateRequest,
+) (*nico.VPC, *http.Response, error) {
+	return c.client.VPCAPI.UpdateVpc(c.authCtx(ctx), org, vpcId).VpcUpdateRequest(req).Execute()
+}
 func (c *ncxInfraClient) DeleteVpc(ctx context.Context, org, vpcId string) (*http.Response, error) {
 	return c.client.VPCAPI.DeleteVpc(c.authCtx(ctx), org, vpcId).Execute()
 }
@@ -173,6 +233,9 @@ func (c *ncxInfraClient) CreateIpblock(
 func (c *ncxInfraClient) GetIpblock(ctx context.Context, org, ipBlockId string) (*nico.IpBlock, *http.Response, error) {
 	return c.client.IPBlockAPI.GetIpblock(c.authCtx(ctx), org, ipBlockId).Execute()
 }
+func (c *ncxInfraClient) GetAllIpblock(ctx context.Context, org, siteId string) ([]nico.IpBlock, *http.Response, error) {
+	return c.client.IPBlockAPI.GetAllIpblock(c.authCtx(ctx), org).SiteId(siteId).Execute()
+}
 func (c *ncxInfraClient) DeleteIpblock(ctx context.Context, org, ipBlockId string) (*http.Response, error) {
 	return c.client.IPBlockAPI.DeleteIpblock(c.authCtx(ctx), org, ipBlockId).Execute()
 }
@@ -231,8 +294,14 @@ func (c *ncxInfraClient) GetInstance(
 ) (*nico.Instance, *http.Response, error) {
 	return c.client.InstanceAPI.GetInstance(c.authCtx(ctx), org, instanceId).Execute()
 }
-func (c *ncxInfraClient) DeleteInstance(ctx context.Context, org, instanceId string) (*http.Response, error) {
-	return c.client.InstanceAPI.DeleteInstance(c.authCtx(ctx), org, instanceId).Execute()
+func (c *ncxInfraClient) DeleteInstance(
+	ctx context.Context, org, instanceId string, req *nico.InstanceDeleteRequest,
+) (*http.Response, error) {
+	apiReq := c.client.InstanceAPI.DeleteInstance(c.authCtx(ctx), org, instanceId)
+	if req != nil {
+		apiReq = apiReq.InstanceDeleteRequest(*req)
+	}
+	return apiReq.Execute()
 }
 func (c *ncxInfraClient) GetAllInstance(ctx context.Context, org string) ([]nico.Instance, *http.Response, error) {
 	return c.client.InstanceAPI.GetAllInstance(c.authCtx(ctx), org).Execute()
@@ -246,6 +315,10 @@ func (c *ncxInfraClient) GetCurrentTenant(ctx context.Context, org string) (*nic
 	return c.client.TenantAPI.GetCurrentTenant(c.authCtx(ctx), org).Execute()
 }
 
+func (c *ncxInfraClient) GetSelfQuotas(ctx context.Context, org string) (*nico.QuotaInfo, *http.Response, error) {
+	return c.client.ShowbackAPI.GetSelfQuotas(c.authCtx(ctx), org).Execute()
+}
+
 func (c *ncxInfraClient) UpdateInstance(
 	ctx context.Context, org, instanceId string, req nico.InstanceUpdateRequest,
 ) (*nico.Instance, *http.Response, error) {
@@ -264,9 +337,103 @@ func (c *ncxInfraClient) BatchCreateInstance(
 	return c.client.InstanceAPI.BatchCreateInstance(c.authCtx(ctx), org).BatchInstanceCreateRequest(req).Execute()
 }
 
+// GetInstanceType returns an instance type, including the capabilities of its machines.
+func (c *ncxInfraClient) GetInstanceType(
+	ctx context.Context, org, instanceTypeId string,
+) (*nico.InstanceType, *http.Response, error) {
+	return c.client.InstanceTypeAPI.GetInstanceType(c.authCtx(ctx), org, instanceTypeId).Execute()
+}
+
+// GetAllInstanceType lists the instance types of a site, including the
+// capabilities and allocation counts of their machines.
+func (c *ncxInfraClient) GetAllInstanceType(
+	ctx context.Context, org, siteId string,
+) ([]nico.InstanceType, *http.Response, error) {
+	return c.client.InstanceTypeAPI.GetAllInstanceType(c.authCtx(ctx), org).
+		SiteId(siteId).
+		IncludeAllocationStats(true).
+		Execute()
+}
+
+func (c *ncxInfraClient) CreateSshKeyGroup(
+	ctx context.Context, org string, req nico.SshKeyGroupCreateRequest,
+) (*nico.SshKeyGroup, *http.Response, error) {
+	return c.client.SSHKeyGroupAPI.CreateSshKeyGroup(c.authCtx(ctx), org).SshKeyGroupCreateRequest(req).Execute()
+}
+
+// GetSshKeyGroup returns an SSH key group, including the sites it is synced to.
+func (c *ncxInfraClient) GetSshKeyGroup(
+	ctx context.Context, org, sshKeyGroupId string,
+) (*nico.SshKeyGroup, *http.Response, error) {
+	return c.client.SSHKeyGroupAPI.GetSshKeyGroup(c.authCtx(ctx), org, sshKeyGroupId).Execute()
+}
+
+// UpdateSshKeyGroup replaces the sites and SSH keys of an SSH key group. The
+// request must carry the version of the group being modified.
+func (c *ncxInfraClient) UpdateSshKeyGroup(
+	ctx context.Context, org, sshKeyGroupId string, req nico.SshKeyGroupUpdateRequest,
+) (*nico.SshKeyGroup, *http.Response, error) {
+	return c.client.SSHKeyGroupAPI.UpdateSshKeyGroup(
+		c.authCtx(ctx), org, sshKeyGroupId,
+	).SshKeyGroupUpdateRequest(req).Execute()
+}
+
+func (c *ncxInfraClient) DeleteSshKeyGroup(ctx context.Context, org, sshKeyGroupId string) (*http.Response, error) {
+	return c.client.SSHKeyGroupAPI.DeleteSshKeyGroup(c.authCtx(ctx), org, sshKeyGroupId).Execute()
+}
+
+// SSH Key methods
+
+func (c *ncxInfraClient) CreateSshKey(
+	ctx context.Context, org string, req nico.SshKeyCreateRequest,
+) (*nico.SshKey, *http.Response, error) {
+	return c.client.SSHKeyAPI.CreateSshKey(c.authCtx(ctx), org).SshKeyCreateRequest(req).Execute()
+}
+
+func (c *ncxInfraClient) DeleteSshKey(ctx context.Context, org, sshKeyId string) (*http.Response, error) {
+	return c.client.SSHKeyAPI.DeleteSshKey(c.authCtx(ctx), org, sshKeyId).Execute()
+}
+
 // Machine methods
+
+// GetMachine gets a machine, including its metadata (DMI data, GPUs, network
+// adapters...) when the credentials have the provider role.
 func (c *ncxInfraClient) GetMachine(ctx context.Context, org, machineId string) (*nico.Machine, *http.Response, error) {
-	return c.client.MachineAPI.GetMachine(c.authCtx(ctx), org, machineId).Execute()
+	return c.client.MachineAPI.GetMachine(c.authCtx(ctx), org, machineId).IncludeMetadata(true).Execute()
+}
+
+// GetAllAvailableMachine lists the machines of an instance type that are not
+// assigned to an instance, including their metadata (chassis, GPUs, ...).
+func (c *ncxInfraClient) GetAllAvailableMachine(
+	ctx context.Context, org, siteId, instanceTypeId string,
+) ([]nico.Machine, *http.Response, error) {
+	return c.client.MachineAPI.GetAllMachine(c.authCtx(ctx), org).
+		SiteId(siteId).
+		InstanceTypeId(instanceTypeId).
+		HasInstance(false).
+		IncludeMetadata(true).
+		Execute()
+}
+
+// GetAllTray lists the trays of a site, filtered by type and component ID
+// when they are set. The component ID of a compute tray is its machine ID.
+func (c *ncxInfraClient) GetAllTray(
+	ctx context.Context, org, siteId, trayType, componentId string,
+) ([]nico.Tray, *http.Response, error) {
+	req := c.client.TrayAPI.GetAllTray(c.authCtx(ctx), org).SiteId(siteId)
+	if trayType != "" {
+		req = req.Type_(trayType)
+	}
+	if componentId != "" {
+		req = req.ComponentId(componentId)
+	}
+	return req.Execute()
+}
+
+func (c *ncxInfraClient) PowerControlTray(
+	ctx context.Context, org, trayId string, req nico.UpdatePowerStateRequest,
+) (*nico.UpdatePowerStateResponse, *http.Response, error) {
+	return c.client.TrayAPI.PowerControlTray(c.authCtx(ctx), org, trayId).UpdatePowerStateRequest(req).Execute()
 }
 
 // Health / Fault event methods
@@ -323,6 +490,10 @@ type ClusterScopeParams struct {
 	NcxInfraCluster *infrastructurev1.NcxInfraCluster
 	NcxInfraClient  NcxInfraClientInterface // Optional: skip creating new client
 	OrgName         string                  // Optional: org name
+
+	// DefaultCredentials is the credentials secret used when neither the
+	// cluster nor the NcxInfraIdentity of its namespace set one. Optional.
+	DefaultCredentials corev1.SecretReference
 }
 
 // ClusterScope defines the scope for cluster operations
@@ -333,6 +504,24 @@ type ClusterScope struct {
 	NcxInfraCluster *infrastructurev1.NcxInfraCluster
 	NcxInfraClient  NcxInfraClientInterface
 	OrgName         string // Organization name for API calls
+	Endpoint        string // API endpoint of the credentials secret
+	Proxy           Proxy  // Proxy settings of the credentials secret
+
+	// CredentialsSecret is the resolved credentials secret, empty when the
+	// NVIDIA Carbide client was provided
+	CredentialsSecret corev1.SecretReference
+}
+
+// Proxy holds the proxy settings used to reach the NVIDIA Carbide API
+type Proxy struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+// IsZero returns true when no proxy is configured
+func (p Proxy) IsZero() bool {
+	return p.HTTPProxy == "" && p.HTTPSProxy == ""
 }
 
 // NewClusterScope creates a new cluster scope
@@ -348,90 +537,570 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 	}
 
 	var nvidiaCarbideClient NcxInfraClientInterface
-	var orgName string
+	var orgName, endpoint string
+	var proxy Proxy
+	var credentialsSecret corev1.SecretReference
+
+	// The organization of the cluster takes precedence over the one of the
+	// credentials, which may belong to a parent organization
+	orgOverride := params.NcxInfraCluster.Spec.Organization
 
 	// Use provided client if available (for testing), otherwise create a new one
 	if params.NcxInfraClient != nil {
 		nvidiaCarbideClient = params.NcxInfraClient
 		orgName = params.OrgName
+		if orgOverride != "" {
+			orgName = orgOverride
+		}
 	} else {
-		// Fetch credentials from secret
-		secret := &corev1.Secret{}
-		secretKey := types.NamespacedName{
-			Name:      params.NcxInfraCluster.Spec.Authentication.SecretRef.Name,
-			Namespace: params.NcxInfraCluster.Spec.Authentication.SecretRef.Namespace,
+		secretRef, err := ResolveCredentialsRef(ctx, params.Client,
+			params.NcxInfraCluster.Spec.Authentication, params.NcxInfraCluster.Namespace,
+			params.DefaultCredentials)
+		if err != nil {
+			return nil, err
 		}
-		if secretKey.Namespace == "" {
-			secretKey.Namespace = params.NcxInfraCluster.Namespace
+		credentialsSecret = secretRef
+		if credentialsSecret.Namespace == "" {
+			credentialsSecret.Namespace = params.NcxInfraCluster.Namespace
 		}
+		creds, err := readCredentials(ctx, params.Client, credentialsSecret, params.NcxInfraCluster.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if orgOverride != "" {
+			creds.orgName = orgOverride
+		}
+		creds = creds.forSite(params.NcxInfraCluster.Spec.SiteRef)
+		nvidiaCarbideClient = creds.newClient()
+		orgName = creds.orgName
+		endpoint = creds.endpoint
+		proxy = creds.proxy
+	}
+
+	migrateNetworkStatus(params.NcxInfraCluster)
+
+	return &ClusterScope{
+		Client:          params.Client,
+		Cluster:         params.Cluster,
+		NcxInfraCluster: params.NcxInfraCluster,
+		NcxInfraClient:  nvidiaCarbideClient,
+		OrgName:         orgName,
+		Endpoint:        endpoint,
+		Proxy:           proxy,
 
-		if err := params.Client.Get(ctx, secretKey, secret); err != nil {
-			return nil, fmt.Errorf("failed to get credentials secret: %w", err)
+		CredentialsSecret: credentialsSecret,
+	}, nil
+}
+
+// NewClientFromSecret creates a NVIDIA Carbide API client from the credentials
+// secret of an authentication spec, resolved as described in
+// ResolveCredentialsRef, and returns it with the organization name. The client
+// targets the endpoint of the site in the secret, if any.
+func NewClientFromSecret(
+	ctx context.Context, c client.Client, auth infrastructurev1.AuthenticationSpec, namespace string,
+	defaultCredentials corev1.SecretReference, site infrastructurev1.SiteReference,
+) (NcxInfraClientInterface, string, error) {
+	secretRef, err := ResolveCredentialsRef(ctx, c, auth, namespace, defaultCredentials)
+	if err != nil {
+		return nil, "", err
+	}
+	creds, err := readCredentials(ctx, c, secretRef, namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	return creds.forSite(site).newClient(), creds.orgName, nil
+}
+
+// ResolveCredentialsRef returns the credentials secret of an object of the
+// given namespace. An object referencing a NcxInfraClusterIdentity gets its
+// secret, provided the identity allows the namespace. An object without secret
+// name inherits the secret of the "default" NcxInfraIdentity of its namespace,
+// and otherwise the default credentials secret of the controller.
+func ResolveCredentialsRef(
+	ctx context.Context, c client.Client, auth infrastructurev1.AuthenticationSpec, namespace string,
+	defaultCredentials corev1.SecretReference,
+) (corev1.SecretReference, error) {
+	if auth.IdentityRef != nil {
+		return resolveClusterIdentity(ctx, c, auth.IdentityRef.Name, namespace)
+	}
+	if auth.SecretRef.Name != "" {
+		if err := authorizeSecretNamespace(ctx, c, auth.SecretRef, namespace); err != nil {
+			return corev1.SecretReference{}, err
 		}
+		return auth.SecretRef, nil
+	}
 
-		// Validate secret contains required fields
-		endpoint, ok := secret.Data["endpoint"]
-		if !ok {
-			return nil, fmt.Errorf("secret %s is missing 'endpoint' field", secretKey.Name)
+	identity := &infrastructurev1.NcxInfraIdentity{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: infrastructurev1.DefaultIdentityName}, identity)
+	switch {
+	case err == nil:
+		ref := identity.Spec.SecretRef
+		if ref.Namespace == "" {
+			ref.Namespace = namespace
 		}
-		orgNameBytes, ok := secret.Data["orgName"]
-		if !ok {
-			return nil, fmt.Errorf("secret %s is missing 'orgName' field", secretKey.Name)
+		if err := authorizeSecretNamespace(ctx, c, ref, namespace); err != nil {
+			return corev1.SecretReference{}, err
 		}
-		token, ok := secret.Data["token"]
-		if !ok {
-			return nil, fmt.Errorf("secret %s is missing 'token' field", secretKey.Name)
+		return ref, nil
+	case !apierrors.IsNotFound(err):
+		return corev1.SecretReference{}, fmt.Errorf("failed to get the default NcxInfraIdentity: %w", err)
+	}
+
+	if defaultCredentials.Name == "" {
+		return corev1.SecretReference{}, fmt.Errorf(
+			"no credentials: authentication.secretRef is not set, namespace %s has no %q NcxInfraIdentity "+
+				"and the controller has no default credentials secret", namespace, infrastructurev1.DefaultIdentityName)
+	}
+	return defaultCredentials, nil
+}
+
+// resolveClusterIdentity returns the credentials secret of a
+// NcxInfraClusterIdentity, after checking that it allows the namespace.
+func resolveClusterIdentity(
+	ctx context.Context, c client.Client, name, namespace string,
+) (corev1.SecretReference, error) {
+	identity := &infrastructurev1.NcxInfraClusterIdentity{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, identity); err != nil {
+		return corev1.SecretReference{}, fmt.Errorf("failed to get NcxInfraClusterIdentity %s: %w", name, err)
+	}
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return corev1.SecretReference{}, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	allowed, err := identity.Spec.AllowsNamespace(namespace, ns.Labels)
+	if err != nil {
+		return corev1.SecretReference{}, fmt.Errorf("NcxInfraClusterIdentity %s has an invalid namespace selector: %w",
+			name, err)
+	}
+	if !allowed {
+		return corev1.SecretReference{}, fmt.Errorf("NcxInfraClusterIdentity %s does not allow namespace %s",
+			name, namespace)
+	}
+	if identity.Spec.SecretRef.Namespace == "" {
+		return corev1.SecretReference{}, fmt.Errorf("NcxInfraClusterIdentity %s has no secret namespace", name)
+	}
+	return identity.Spec.SecretRef, nil
+}
+
+// credentials are the NVIDIA Carbide API settings of a credentials secret
+type credentials struct {
+	endpoint  string
+	orgName   string
+	token     string
+	oauth2    *clientcredentials.Config
+	proxy     Proxy
+	tlsConfig *tls.Config
+	// qps and burst override the rate limit of the organization when set
+	qps   float64
+	burst int
+	// siteEndpoints are the endpoints of the sites served by another API, by
+	// site ID or name
+	siteEndpoints map[string]string
+}
+
+// authorizeSecretNamespace checks that a credentials secret of another
+// namespace allows the namespace in its allowed-namespaces annotation. A
+// missing secret is left to readCredentials to report.
+func authorizeSecretNamespace(
+	ctx context.Context, c client.Client, ref corev1.SecretReference, namespace string,
+) error {
+	if ref.Namespace == "" || ref.Namespace == namespace {
+		return nil
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
 		}
+		return fmt.Errorf("failed to get credentials secret: %w", err)
+	}
+	if !infrastructurev1.SecretAllowsNamespace(secret, namespace) {
+		return fmt.Errorf("credentials secret %s/%s does not allow namespace %s, list it in its %s annotation",
+			ref.Namespace, ref.Name, namespace, infrastructurev1.AllowedNamespacesAnnotation)
+	}
+	return nil
+}
+
+// readCredentials reads and validates a credentials secret. The secret
+// defaults to the given namespace.
+func readCredentials(
+	ctx context.Context, c client.Client, secretRef corev1.SecretReference, namespace string,
+) (*credentials, error) {
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{
+		Name:      secretRef.Name,
+		Namespace: secretRef.Namespace,
+	}
+	if secretKey.Namespace == "" {
+		secretKey.Namespace = namespace
+	}
 
-		orgName = string(orgNameBytes)
+	if err := c.Get(ctx, secretKey, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: secret %s not found", ErrCredentialsMissing, secretKey)
+		}
+		return nil, fmt.Errorf("failed to get credentials secret: %w", err)
+	}
+
+	// Validate secret contains required fields
+	endpoint, ok := secret.Data["endpoint"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s is missing 'endpoint' field", secretKey.Name)
+	}
+	orgName, ok := secret.Data["orgName"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s is missing 'orgName' field", secretKey.Name)
+	}
+	endpointStr := string(endpoint)
+	if !strings.HasPrefix(endpointStr, "https://") {
+		return nil, fmt.Errorf("endpoint must use https:// scheme, got: %s", endpointStr)
+	}
+
+	// Either a static token or OAuth2 client credentials
+	token, hasToken := secret.Data["token"]
+	oauth2Config, err := credentialsOAuth2Config(secret)
+	if err != nil {
+		return nil, fmt.Errorf("secret %s has invalid OAuth2 settings: %w", secretKey.Name, err)
+	}
+	if !hasToken && oauth2Config == nil {
+		return nil, fmt.Errorf("secret %s is missing 'token' field", secretKey.Name)
+	}
+	if hasToken && oauth2Config != nil {
+		return nil, fmt.Errorf("secret %s cannot set both 'token' and 'clientID' fields", secretKey.Name)
+	}
 
-		endpointStr := string(endpoint)
-		if !strings.HasPrefix(endpointStr, "https://") {
-			return nil, fmt.Errorf("endpoint must use https:// scheme, got: %s", endpointStr)
+	proxy := Proxy{
+		HTTPProxy:  string(secret.Data["httpProxy"]),
+		HTTPSProxy: string(secret.Data["httpsProxy"]),
+		NoProxy:    string(secret.Data["noProxy"]),
+	}
+	for key, proxyURL := range map[string]string{"httpProxy": proxy.HTTPProxy, "httpsProxy": proxy.HTTPSProxy} {
+		if proxyURL == "" {
+			continue
+		}
+		if _, err := url.Parse(proxyURL); err != nil {
+			return nil, fmt.Errorf("secret %s has an invalid '%s' field: %w", secretKey.Name, key, err)
 		}
+	}
 
-		// Create NVIDIA Carbide API client with authentication
-		sdkCfg := nico.NewConfiguration()
-		sdkCfg.Servers = nico.ServerConfigurations{
-			{URL: string(endpoint)},
+	tlsConfig, err := credentialsTLSConfig(secret)
+	if err != nil {
+		return nil, fmt.Errorf("secret %s has invalid TLS settings: %w", secretKey.Name, err)
+	}
+
+	creds := &credentials{
+		endpoint:  endpointStr,
+		orgName:   string(orgName),
+		token:     string(token),
+		oauth2:    oauth2Config,
+		proxy:     proxy,
+		tlsConfig: tlsConfig,
+	}
+	if value, ok := secret.Data["qps"]; ok {
+		if creds.qps, err = strconv.ParseFloat(string(value), 64); err != nil || creds.qps <= 0 {
+			return nil, fmt.Errorf("secret %s has an invalid 'qps' field, expected a positive number", secretKey.Name)
+		}
+	}
+	if value, ok := secret.Data["burst"]; ok {
+		if creds.burst, err = strconv.Atoi(string(value)); err != nil || creds.burst <= 0 {
+			return nil, fmt.Errorf("secret %s has an invalid 'burst' field, expected a positive integer", secretKey.Name)
 		}
-		nvidiaCarbideClient = &ncxInfraClient{
-			client: nico.NewAPIClient(sdkCfg),
-			token:  string(token),
+	}
+	if value, ok := secret.Data["siteEndpoints"]; ok {
+		if creds.siteEndpoints, err = parseSiteEndpoints(string(value)); err != nil {
+			return nil, fmt.Errorf("secret %s has an invalid 'siteEndpoints' field: %w", secretKey.Name, err)
+		}
+	}
+	return creds, nil
+}
+
+// parseSiteEndpoints parses the siteEndpoints field of a credentials secret,
+// one <site ID or name>=<endpoint> entry per line. Empty lines and lines
+// starting with # are ignored.
+func parseSiteEndpoints(value string) (map[string]string, error) {
+	endpoints := map[string]string{}
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		site, endpoint, ok := strings.Cut(line, "=")
+		site, endpoint = strings.TrimSpace(site), strings.TrimSpace(endpoint)
+		if !ok || site == "" {
+			return nil, fmt.Errorf("expected <site>=<endpoint>, got: %s", line)
+		}
+		if !strings.HasPrefix(endpoint, "https://") {
+			return nil, fmt.Errorf("endpoint of site %s must use https:// scheme, got: %s", site, endpoint)
+		}
+		if _, ok := endpoints[site]; ok {
+			return nil, fmt.Errorf("site %s is listed twice", site)
+		}
+		endpoints[site] = endpoint
+	}
+	return endpoints, nil
+}
+
+// forSite returns the credentials targeting the endpoint of a site, looked up
+// in siteEndpoints by ID then by name, or the credentials themselves when the
+// site uses the default endpoint.
+func (c *credentials) forSite(site infrastructurev1.SiteReference) *credentials {
+	for _, key := range []string{site.ID, site.Name} {
+		if endpoint, ok := c.siteEndpoints[key]; ok && key != "" {
+			siteCredentials := *c
+			siteCredentials.endpoint = endpoint
+			return &siteCredentials
 		}
 	}
+	return c
+}
 
-	return &ClusterScope{
-		Client:          params.Client,
-		Cluster:         params.Cluster,
-		NcxInfraCluster: params.NcxInfraCluster,
-		NcxInfraClient:  nvidiaCarbideClient,
-		OrgName:         orgName,
+// credentialsOAuth2Config returns the OAuth2 client credentials flow of a
+// credentials secret, or nil when the secret has no clientID. The flow needs
+// clientSecret and an https:// tokenURL; scopes are optional and separated by
+// spaces.
+func credentialsOAuth2Config(secret *corev1.Secret) (*clientcredentials.Config, error) {
+	clientID := string(secret.Data["clientID"])
+	if clientID == "" {
+		return nil, nil
+	}
+	clientSecret := string(secret.Data["clientSecret"])
+	if clientSecret == "" {
+		return nil, fmt.Errorf("missing 'clientSecret' field")
+	}
+	tokenURL := string(secret.Data["tokenURL"])
+	if !strings.HasPrefix(tokenURL, "https://") {
+		return nil, fmt.Errorf("'tokenURL' must use https:// scheme, got: %s", tokenURL)
+	}
+	return &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       strings.Fields(string(secret.Data["scopes"])),
 	}, nil
 }
 
-// SiteID returns the Site ID from the site reference
+// credentialsTLSConfig returns the TLS configuration of a credentials secret,
+// or nil when the system roots are used without client certificate. The secret
+// may carry a PEM caBundle trusted instead of the system roots, a client
+// certificate for mTLS (tls.crt and tls.key), and insecureSkipVerify.
+func credentialsTLSConfig(secret *corev1.Secret) (*tls.Config, error) {
+	caBundle := secret.Data["caBundle"]
+	clientCert, clientKey := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	insecure := false
+	if value, ok := secret.Data["insecureSkipVerify"]; ok {
+		var err error
+		if insecure, err = strconv.ParseBool(string(value)); err != nil {
+			return nil, fmt.Errorf("invalid 'insecureSkipVerify' field: %w", err)
+		}
+	}
+	if len(caBundle) == 0 && len(clientCert) == 0 && len(clientKey) == 0 && !insecure {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecure, //nolint:gosec // explicitly requested in the credentials secret
+	}
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("'caBundle' field has no PEM certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(clientCert) > 0 || len(clientKey) > 0 {
+		cert, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// newClient creates a NVIDIA Carbide API client with authentication. The
+// client goes through the proxy of the credentials when set, and through the
+// proxy of the controller environment otherwise, and uses the TLS settings of
+// the credentials. With OAuth2 client credentials, the transport requests the
+// access tokens, through the same proxy and TLS settings, and refreshes them
+// when they expire. The API calls are logged by a loggingTransport, the
+// mutating ones recorded by an auditTransport, and all of them throttled per
+// organization by a throttlingTransport.
+func (c *credentials) newClient() NcxInfraClientInterface {
+	sdkCfg := nico.NewConfiguration()
+	sdkCfg.Servers = nico.ServerConfigurations{
+		{URL: c.endpoint},
+	}
+	base := http.DefaultClient
+	if !c.proxy.IsZero() || c.tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if !c.proxy.IsZero() {
+			proxyFunc := (&httpproxy.Config{
+				HTTPProxy:  c.proxy.HTTPProxy,
+				HTTPSProxy: c.proxy.HTTPSProxy,
+				NoProxy:    c.proxy.NoProxy,
+			}).ProxyFunc()
+			transport.Proxy = func(req *http.Request) (*url.URL, error) {
+				return proxyFunc(req.URL)
+			}
+		}
+		if c.tlsConfig != nil {
+			transport.TLSClientConfig = c.tlsConfig
+		}
+		base = &http.Client{Transport: transport}
+	}
+	transport := base.Transport
+	if c.oauth2 != nil {
+		transport = &oauth2.Transport{
+			Source: c.tokenSource(base),
+			Base:   base.Transport,
+		}
+	}
+	sdkCfg.HTTPClient = &http.Client{Transport: &throttlingTransport{
+		throttle: throttleFor(c.endpoint, c.orgName, c.qps, c.burst),
+		base: &auditTransport{
+			base:      &loggingTransport{base: transport},
+			endpoint:  c.endpoint,
+			orgName:   c.orgName,
+			principal: c.principal(),
+		},
+	}}
+	return &ncxInfraClient{
+		client: nico.NewAPIClient(sdkCfg),
+		token:  c.token,
+	}
+}
+
+// oauth2Tokens caches the OAuth2 access tokens across the clients created by
+// the reconciliations, keyed by oauth2TokenKey.
+var oauth2Tokens sync.Map
+
+// oauth2TokenKey identifies the OAuth2 client credentials of an access token
+// without keeping the client secret in memory.
+func oauth2TokenKey(cfg *clientcredentials.Config) string {
+	sum := sha256.Sum256([]byte(strings.Join(
+		append([]string{cfg.TokenURL, cfg.ClientID, cfg.ClientSecret}, cfg.Scopes...), "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// cachingTokenSource stores the tokens of its source in oauth2Tokens
+type cachingTokenSource struct {
+	key    string
+	source oauth2.TokenSource
+}
+
+func (s *cachingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	oauth2Tokens.Store(s.key, token)
+	return token, nil
+}
+
+// tokenSource returns the OAuth2 token source of the credentials. It starts
+// from the cached token, if any, and requests a new one from the token URL
+// through httpClient once it expires.
+func (c *credentials) tokenSource(httpClient *http.Client) oauth2.TokenSource {
+	key := oauth2TokenKey(c.oauth2)
+	var cached *oauth2.Token
+	if token, ok := oauth2Tokens.Load(key); ok {
+		cached = token.(*oauth2.Token)
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	return &cachingTokenSource{
+		key:    key,
+		source: oauth2.ReuseTokenSource(cached, c.oauth2.TokenSource(ctx)),
+	}
+}
+
+// TargetChange describes how the org or endpoint of the credentials differ
+// from the ones the cluster resources were created in, or returns an empty
+// string when they match or were not recorded yet.
+func (s *ClusterScope) TargetChange() string {
+	status := s.NcxInfraCluster.Status
+	if status.OrgName != "" && s.OrgName != "" && status.OrgName != s.OrgName {
+		return fmt.Sprintf("credentials point to org %s but the cluster resources live in org %s",
+			s.OrgName, status.OrgName)
+	}
+	if status.Endpoint != "" && s.Endpoint != "" && status.Endpoint != s.Endpoint {
+		return fmt.Sprintf("credentials point to endpoint %s but the cluster resources live behind %s",
+			s.Endpoint, status.Endpoint)
+	}
+	return ""
+}
+
+// RecordTarget records the org and endpoint of the credentials in status
+func (s *ClusterScope) RecordTarget() {
+	if s.OrgName != "" {
+		s.NcxInfraCluster.Status.OrgName = s.OrgName
+	}
+	if s.Endpoint != "" {
+		s.NcxInfraCluster.Status.Endpoint = s.Endpoint
+	}
+}
+
+// SiteID returns the Site ID from the site reference. A site name is looked up
+// in the site inventory first, then through the NVIDIA Carbide API.
 func (s *ClusterScope) SiteID(ctx context.Context) (string, error) {
+	return s.resolveSiteRef(ctx, s.NcxInfraCluster.Spec.SiteRef)
+}
+
+// SubnetSiteID returns the Site ID of a subnet: the site of its site reference,
+// or the site of the cluster when it has none.
+func (s *ClusterScope) SubnetSiteID(ctx context.Context, subnet infrastructurev1.SubnetSpec) (string, error) {
+	if subnet.SiteRef == nil {
+		return s.SiteID(ctx)
+	}
+	return s.resolveSiteRef(ctx, *subnet.SiteRef)
+}
+
+func (s *ClusterScope) resolveSiteRef(ctx context.Context, ref infrastructurev1.SiteReference) (string, error) {
+	if ref.ID == "" && ref.Name != "" && s.Client != nil {
+		if site, err := LookupSite(ctx, s.Client, ref, s.OrgName); err == nil && site != nil {
+			return site.Spec.ID, nil
+		}
+	}
+	return ResolveSiteID(ctx, s.NcxInfraClient, s.OrgName, ref)
+}
+
+// LookupSite returns the NcxInfraSite of a site reference among the sites of
+// an organization, or nil when the site inventory does not have it.
+func LookupSite(
+	ctx context.Context, c client.Reader, ref infrastructurev1.SiteReference, org string,
+) (*infrastructurev1.NcxInfraSite, error) {
+	sites := &infrastructurev1.NcxInfraSiteList{}
+	if err := c.List(ctx, sites); err != nil {
+		return nil, fmt.Errorf("failed to list the NcxInfraSites: %w", err)
+	}
+	return sites.Lookup(ref, org), nil
+}
+
+// ResolveSiteID returns the Site UUID of a site reference, looking the site up
+// by name when no ID is given.
+func ResolveSiteID(
+	ctx context.Context, c NcxInfraClientInterface, orgName string, ref infrastructurev1.SiteReference,
+) (string, error) {
 	// If ID is directly specified, use it
-	if s.NcxInfraCluster.Spec.SiteRef.ID != "" {
-		return s.NcxInfraCluster.Spec.SiteRef.ID, nil
+	if ref.ID != "" {
+		return ref.ID, nil
 	}
 
 	// Resolve site name to UUID via the Carbide API
-	if s.NcxInfraCluster.Spec.SiteRef.Name != "" {
-		sites, _, err := s.NcxInfraClient.GetAllSite(ctx, s.OrgName)
+	if ref.Name != "" {
+		sites, httpResp, err := c.GetAllSite(ctx, orgName)
 		if err != nil {
-			return "", fmt.Errorf("failed to list sites: %w", err)
+			return "", fmt.Errorf("failed to list sites: %w", WithPermissionError(httpResp, err, "GetAllSite"))
 		}
 		for _, site := range sites {
-			if site.Name != nil && *site.Name == s.NcxInfraCluster.Spec.SiteRef.Name {
+			if site.Name != nil && *site.Name == ref.Name {
 				if site.Id == nil {
-					return "", fmt.Errorf("site %q found but has no ID", s.NcxInfraCluster.Spec.SiteRef.Name)
+					return "", fmt.Errorf("site %q found but has no ID", ref.Name)
 				}
 				return *site.Id, nil
 			}
 		}
-		return "", fmt.Errorf("site %q not found", s.NcxInfraCluster.Spec.SiteRef.Name)
+		return "", fmt.Errorf("site %q not found", ref.Name)
 	}
 
 	return "", fmt.Errorf("site reference is empty")
@@ -454,12 +1123,56 @@ func (s *ClusterScope) TenantID() string {
 
 // VPCID returns the VPC ID from status
 func (s *ClusterScope) VPCID() string {
-	return s.NcxInfraCluster.Status.VPCID
+	return s.NcxInfraCluster.Status.NetworkStatus.VPCID()
 }
 
-// SetVPCID sets the VPC ID in status
+// SetVPCID sets the VPC ID in status and marks the VPC ready. An empty ID
+// removes the VPC.
 func (s *ClusterScope) SetVPCID(vpcID string) {
-	s.NcxInfraCluster.Status.VPCID = vpcID
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	if vpcID == "" {
+		network.VPC = nil
+		return
+	}
+	network.VPC = &infrastructurev1.NetworkResourceStatus{
+		Name:  s.NcxInfraCluster.Spec.VPC.Name,
+		ID:    vpcID,
+		State: infrastructurev1.NetworkResourceReady,
+	}
+}
+
+// SetVPCError records the error reconciling the VPC in status
+func (s *ClusterScope) SetVPCError(err error) {
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	if network.VPC == nil {
+		network.VPC = &infrastructurev1.NetworkResourceStatus{Name: s.NcxInfraCluster.Spec.VPC.Name}
+	}
+	s.setResourceError(network.VPC, err)
+}
+
+// SiteVPCIDs returns the IDs of the VPCs of the cluster in the sites other
+// than its own, by site ID
+func (s *ClusterScope) SiteVPCIDs() map[string]string {
+	return resourceIDs(s.NcxInfraCluster.Status.NetworkStatus.SiteVPCs)
+}
+
+// SetSiteVPCID sets the ID of the VPC of the cluster in a site other than its
+// own and marks the VPC ready. An empty ID removes the VPC.
+func (s *ClusterScope) SetSiteVPCID(siteID, vpcID string) {
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	network.SiteVPCs = setResourceID(network.SiteVPCs, siteID, vpcID)
+}
+
+// SetSiteVPCError records the error reconciling the VPC of the cluster in a
+// site other than its own in status
+func (s *ClusterScope) SetSiteVPCError(siteID string, err error) {
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	network.SiteVPCs = s.setResourceErrorByName(network.SiteVPCs, siteID, err)
+}
+
+// SetFailureDomains sets the failure domains of the cluster in status
+func (s *ClusterScope) SetFailureDomains(failureDomains []clusterv1.FailureDomain) {
+	s.NcxInfraCluster.Status.FailureDomains = failureDomains
 }
 
 // SetReady sets the ready status
@@ -472,93 +1185,426 @@ func (s *ClusterScope) IsReady() bool {
 	return s.NcxInfraCluster.Status.Ready
 }
 
-// SubnetIDs returns the subnet IDs from status
+// SubnetIDs returns the IDs of the subnets created in NVIDIA Carbide, by name
 func (s *ClusterScope) SubnetIDs() map[string]string {
-	if s.NcxInfraCluster.Status.NetworkStatus.SubnetIDs == nil {
-		s.NcxInfraCluster.Status.NetworkStatus.SubnetIDs = make(map[string]string)
-	}
-	return s.NcxInfraCluster.Status.NetworkStatus.SubnetIDs
+	return resourceIDs(s.NcxInfraCluster.Status.NetworkStatus.Subnets)
 }
 
-// SetSubnetID sets a subnet ID in status
+// SetSubnetID sets a subnet ID in status and marks the subnet ready. An empty
+// ID removes the subnet.
 func (s *ClusterScope) SetSubnetID(name, id string) {
-	if s.NcxInfraCluster.Status.NetworkStatus.SubnetIDs == nil {
-		s.NcxInfraCluster.Status.NetworkStatus.SubnetIDs = make(map[string]string)
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	network.Subnets = setResourceID(network.Subnets, name, id)
+}
+
+// SetSubnetCIDR records the prefix allocated to a subnet in status
+func (s *ClusterScope) SetSubnetCIDR(name, cidr string) {
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	for i := range network.Subnets {
+		if network.Subnets[i].Name == name {
+			network.Subnets[i].CIDR = cidr
+		}
 	}
-	s.NcxInfraCluster.Status.NetworkStatus.SubnetIDs[name] = id
+}
+
+// SetSubnetError records the error reconciling a subnet in status
+func (s *ClusterScope) SetSubnetError(name string, err error) {
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	network.Subnets = s.setResourceErrorByName(network.Subnets, name, err)
 }
 
 // NSGID returns the network security group ID from status
 func (s *ClusterScope) NSGID() string {
-	return s.NcxInfraCluster.Status.NetworkStatus.NSGID
+	if nsg := s.NcxInfraCluster.Status.NetworkStatus.NSG; nsg != nil {
+		return nsg.ID
+	}
+	return ""
 }
 
-// SetNSGID sets the network security group ID in status
+// SetNSGID sets the network security group ID in status and marks the network
+// security group ready. An empty ID removes the network security group.
 func (s *ClusterScope) SetNSGID(nsgID string) {
-	s.NcxInfraCluster.Status.NetworkStatus.NSGID = nsgID
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	if nsgID == "" {
+		network.NSG = nil
+		return
+	}
+	appliedRuleHash := ""
+	if network.NSG != nil && network.NSG.ID == nsgID {
+		appliedRuleHash = network.NSG.AppliedRuleHash
+	}
+	network.NSG = &infrastructurev1.NSGStatus{
+		NetworkResourceStatus: infrastructurev1.NetworkResourceStatus{
+			Name:  nsgName(s.NcxInfraCluster.Spec.VPC),
+			ID:    nsgID,
+			State: infrastructurev1.NetworkResourceReady,
+		},
+		AppliedRuleHash: appliedRuleHash,
+	}
+}
+
+// NSGAppliedRuleHash returns the hash of the rules of the network security group from status
+func (s *ClusterScope) NSGAppliedRuleHash() string {
+	if nsg := s.NcxInfraCluster.Status.NetworkStatus.NSG; nsg != nil {
+		return nsg.AppliedRuleHash
+	}
+	return ""
 }
 
-// IPBlockID returns the IP block ID from status
-func (s *ClusterScope) IPBlockID() string {
-	return s.NcxInfraCluster.Status.NetworkStatus.IPBlockID
+// SetNSGAppliedRuleHash sets the hash of the rules of the network security group in status
+func (s *ClusterScope) SetNSGAppliedRuleHash(hash string) {
+	if nsg := s.NcxInfraCluster.Status.NetworkStatus.NSG; nsg != nil {
+		nsg.AppliedRuleHash = hash
+	}
 }
 
-// SetIPBlockID sets the IP block ID in status
-func (s *ClusterScope) SetIPBlockID(ipBlockID string) {
-	s.NcxInfraCluster.Status.NetworkStatus.IPBlockID = ipBlockID
+// SetNSGError records the error reconciling the network security group in status
+func (s *ClusterScope) SetNSGError(err error) {
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	if network.NSG == nil {
+		network.NSG = &infrastructurev1.NSGStatus{
+			NetworkResourceStatus: infrastructurev1.NetworkResourceStatus{Name: nsgName(s.NcxInfraCluster.Spec.VPC)},
+		}
+	}
+	s.setResourceError(&network.NSG.NetworkResourceStatus, err)
 }
 
-func (s *ClusterScope) AllocationID() string {
-	return s.NcxInfraCluster.Status.NetworkStatus.AllocationID
+// nsgName returns the name of the inline or referenced network security group
+func nsgName(vpc infrastructurev1.VPCSpec) string {
+	if vpc.NetworkSecurityGroupRef != nil {
+		return vpc.NetworkSecurityGroupRef.Name
+	}
+	if vpc.NetworkSecurityGroup != nil {
+		return vpc.NetworkSecurityGroup.Name
+	}
+	return ""
 }
 
-func (s *ClusterScope) SetAllocationID(allocationID string) {
-	s.NcxInfraCluster.Status.NetworkStatus.AllocationID = allocationID
+// IPBlocks returns the IP blocks of the cluster from status
+func (s *ClusterScope) IPBlocks() []infrastructurev1.IPBlockStatus {
+	return slices.Clone(s.NcxInfraCluster.Status.NetworkStatus.IPBlocks)
 }
 
-func (s *ClusterScope) ChildIPBlockID() string {
-	return s.NcxInfraCluster.Status.NetworkStatus.ChildIPBlockID
+// IPBlock returns the IP block of a subnet with Public egress or placed in
+// another site from status, or the 10.0.0.0/16 IP block of the clusters
+// created by earlier releases when subnetName is empty
+func (s *ClusterScope) IPBlock(subnetName string) infrastructurev1.IPBlockStatus {
+	return s.findIPBlock(infrastructurev1.IPBlockStatus{Subnet: subnetName})
 }
 
-func (s *ClusterScope) SetChildIPBlockID(childIPBlockID string) {
-	s.NcxInfraCluster.Status.NetworkStatus.ChildIPBlockID = childIPBlockID
+// SupernetIPBlock returns the IP block of a supernet of the CIDRs of the
+// subnets and VPC prefixes, or the allocation of one of these CIDRs, from
+// status
+func (s *ClusterScope) SupernetIPBlock(cidr string) infrastructurev1.IPBlockStatus {
+	return s.findIPBlock(infrastructurev1.IPBlockStatus{CIDR: cidr})
 }
 
-// VPCPrefixIDs returns the VPC Prefix IDs from status
-func (s *ClusterScope) VPCPrefixIDs() map[string]string {
-	if s.NcxInfraCluster.Status.NetworkStatus.VPCPrefixIDs == nil {
-		s.NcxInfraCluster.Status.NetworkStatus.VPCPrefixIDs = make(map[string]string)
+// findIPBlock returns the IP block with the subnet and CIDR of key from
+// status, or key when there is none
+func (s *ClusterScope) findIPBlock(key infrastructurev1.IPBlockStatus) infrastructurev1.IPBlockStatus {
+	for _, ipBlock := range s.NcxInfraCluster.Status.NetworkStatus.IPBlocks {
+		if sameIPBlock(ipBlock, key) {
+			return ipBlock
+		}
 	}
-	return s.NcxInfraCluster.Status.NetworkStatus.VPCPrefixIDs
+	return key
 }
 
-// SetVPCPrefixID sets a VPC Prefix ID in status
-func (s *ClusterScope) SetVPCPrefixID(name, id string) {
-	if s.NcxInfraCluster.Status.NetworkStatus.VPCPrefixIDs == nil {
-		s.NcxInfraCluster.Status.NetworkStatus.VPCPrefixIDs = make(map[string]string)
+// SetIPBlock sets an IP block in status, with the error reconciling it if any.
+// An IP block without IDs nor error is removed. The IP blocks are kept sorted
+// by subnet, the IP blocks the subnets are allocated from first.
+func (s *ClusterScope) SetIPBlock(ipBlock infrastructurev1.IPBlockStatus, err error) {
+	ipBlock.State, ipBlock.LastError = infrastructurev1.NetworkResourceReady, ""
+	if err != nil {
+		ipBlock.State, ipBlock.LastError = s.errorState(), err.Error()
 	}
-	s.NcxInfraCluster.Status.NetworkStatus.VPCPrefixIDs[name] = id
+
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	index := slices.IndexFunc(network.IPBlocks, func(existing infrastructurev1.IPBlockStatus) bool {
+		return sameIPBlock(existing, ipBlock)
+	})
+	if ipBlock.IPBlockID == "" && ipBlock.AllocationID == "" && ipBlock.ChildIPBlockID == "" && err == nil {
+		if index >= 0 {
+			network.IPBlocks = slices.Delete(network.IPBlocks, index, index+1)
+		}
+		return
+	}
+	if index >= 0 {
+		network.IPBlocks[index] = ipBlock
+		return
+	}
+	network.IPBlocks = append(network.IPBlocks, ipBlock)
+	slices.SortStableFunc(network.IPBlocks, compareIPBlocks)
 }
 
-// VPCPeeringIDs returns the VPC Peering IDs from status
-func (s *ClusterScope) VPCPeeringIDs() map[string]string {
-	if s.NcxInfraCluster.Status.NetworkStatus.VPCPeeringIDs == nil {
-		s.NcxInfraCluster.Status.NetworkStatus.VPCPeeringIDs = make(map[string]string)
+// NewResourceOrigin returns the creation record of a NVIDIA Carbide resource
+// created by the provider.
+func NewResourceOrigin(kind, name string, created *time.Time) infrastructurev1.ResourceOrigin {
+	origin := infrastructurev1.ResourceOrigin{
+		Kind:      kind,
+		Name:      name,
+		CreatedBy: infrastructurev1.ResourceCreator,
+	}
+	if created != nil {
+		origin.Created = &metav1.Time{Time: *created}
+	}
+	return origin
+}
+
+// SetResourceOrigin records the creation of a NVIDIA Carbide resource of the cluster in status
+func (s *ClusterScope) SetResourceOrigin(id, kind, name string, created *time.Time) {
+	if s.NcxInfraCluster.Status.NetworkStatus.Origins == nil {
+		s.NcxInfraCluster.Status.NetworkStatus.Origins = make(map[string]infrastructurev1.ResourceOrigin)
 	}
-	return s.NcxInfraCluster.Status.NetworkStatus.VPCPeeringIDs
+	s.NcxInfraCluster.Status.NetworkStatus.Origins[id] = NewResourceOrigin(kind, name, created)
+}
+
+// PruneResourceOrigins removes the creation records of the resources no longer
+// referenced by the status.
+func (s *ClusterScope) PruneResourceOrigins() {
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	if len(network.Origins) == 0 {
+		return
+	}
+	known := map[string]bool{network.VPCID(): true, s.NSGID(): true}
+	for _, resources := range [][]infrastructurev1.NetworkResourceStatus{
+		network.SiteVPCs, network.Subnets, network.VPCPrefixes, network.VPCPeerings,
+	} {
+		for _, resource := range resources {
+			known[resource.ID] = true
+		}
+	}
+	for id := range network.Origins {
+		if !known[id] {
+			delete(network.Origins, id)
+		}
+	}
+}
+
+// VPCPrefixIDs returns the IDs of the VPC Prefixes created in NVIDIA Carbide, by name
+func (s *ClusterScope) VPCPrefixIDs() map[string]string {
+	return resourceIDs(s.NcxInfraCluster.Status.NetworkStatus.VPCPrefixes)
+}
+
+// SetVPCPrefixID sets a VPC Prefix ID in status and marks the VPC Prefix ready.
+// An empty ID removes the VPC Prefix.
+func (s *ClusterScope) SetVPCPrefixID(name, id string) {
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	network.VPCPrefixes = setResourceID(network.VPCPrefixes, name, id)
+}
+
+// SetVPCPrefixError records the error reconciling a VPC Prefix in status
+func (s *ClusterScope) SetVPCPrefixError(name string, err error) {
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	network.VPCPrefixes = s.setResourceErrorByName(network.VPCPrefixes, name, err)
+}
+
+// VPCPeeringIDs returns the IDs of the VPC Peerings created in NVIDIA Carbide, by peer VPC ID
+func (s *ClusterScope) VPCPeeringIDs() map[string]string {
+	return resourceIDs(s.NcxInfraCluster.Status.NetworkStatus.VPCPeerings)
 }
 
-// SetVPCPeeringID sets a VPC Peering ID in status
+// SetVPCPeeringID sets a VPC Peering ID in status and marks the VPC Peering
+// ready. An empty ID removes the VPC Peering.
 func (s *ClusterScope) SetVPCPeeringID(peerVPCID, peeringID string) {
-	if s.NcxInfraCluster.Status.NetworkStatus.VPCPeeringIDs == nil {
-		s.NcxInfraCluster.Status.NetworkStatus.VPCPeeringIDs = make(map[string]string)
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	network.VPCPeerings = setResourceID(network.VPCPeerings, peerVPCID, peeringID)
+}
+
+// SetVPCPeeringError records the error reconciling a VPC Peering in status
+func (s *ClusterScope) SetVPCPeeringError(peerVPCID string, err error) {
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	network.VPCPeerings = s.setResourceErrorByName(network.VPCPeerings, peerVPCID, err)
+}
+
+// errorState returns the state of a resource that failed to reconcile
+func (s *ClusterScope) errorState() infrastructurev1.NetworkResourceState {
+	if !s.NcxInfraCluster.DeletionTimestamp.IsZero() {
+		return infrastructurev1.NetworkResourceDeleting
+	}
+	return infrastructurev1.NetworkResourceFailed
+}
+
+func (s *ClusterScope) setResourceError(resource *infrastructurev1.NetworkResourceStatus, err error) {
+	resource.State = s.errorState()
+	resource.LastError = err.Error()
+}
+
+func (s *ClusterScope) setResourceErrorByName(
+	resources []infrastructurev1.NetworkResourceStatus, name string, err error,
+) []infrastructurev1.NetworkResourceStatus {
+	index := slices.IndexFunc(resources, func(r infrastructurev1.NetworkResourceStatus) bool { return r.Name == name })
+	if index < 0 {
+		resources = append(resources, infrastructurev1.NetworkResourceStatus{Name: name})
+		slices.SortStableFunc(resources, compareResources)
+		index = slices.IndexFunc(resources, func(r infrastructurev1.NetworkResourceStatus) bool { return r.Name == name })
+	}
+	s.setResourceError(&resources[index], err)
+	return resources
+}
+
+// resourceIDs returns the IDs of the created resources, by name
+func resourceIDs(resources []infrastructurev1.NetworkResourceStatus) map[string]string {
+	ids := make(map[string]string, len(resources))
+	for _, resource := range resources {
+		if resource.ID != "" {
+			ids[resource.Name] = resource.ID
+		}
+	}
+	return ids
+}
+
+// compareResources orders the network resources by name, so that the status
+// does not depend on the order the resources were reconciled in and repeated
+// reconciles write the same status.
+func compareResources(a, b infrastructurev1.NetworkResourceStatus) int {
+	return strings.Compare(a.Name, b.Name)
+}
+
+// compareIPBlocks orders the IP blocks by subnet, the IP blocks the subnets
+// are allocated from first, by CIDR.
+func compareIPBlocks(a, b infrastructurev1.IPBlockStatus) int {
+	return cmp.Or(strings.Compare(a.Subnet, b.Subnet), strings.Compare(a.CIDR, b.CIDR))
+}
+
+// sameIPBlock returns whether two IP block statuses are for the same subnet or
+// supernet.
+func sameIPBlock(a, b infrastructurev1.IPBlockStatus) bool {
+	return a.Subnet == b.Subnet && a.CIDR == b.CIDR
+}
+
+// setResourceID records the ID of a resource and marks it ready, or removes
+// the resource when the ID is empty. The CIDR of the resource is kept while its
+// ID does not change.
+func setResourceID(
+	resources []infrastructurev1.NetworkResourceStatus, name, id string,
+) []infrastructurev1.NetworkResourceStatus {
+	index := slices.IndexFunc(resources, func(r infrastructurev1.NetworkResourceStatus) bool { return r.Name == name })
+	if id == "" {
+		if index >= 0 {
+			resources = slices.Delete(resources, index, index+1)
+		}
+		return resources
+	}
+	resource := infrastructurev1.NetworkResourceStatus{
+		Name:  name,
+		ID:    id,
+		State: infrastructurev1.NetworkResourceReady,
+	}
+	if index >= 0 {
+		if resources[index].ID == id {
+			resource.CIDR = resources[index].CIDR
+		}
+		resources[index] = resource
+		return resources
+	}
+	resources = append(resources, resource)
+	slices.SortStableFunc(resources, compareResources)
+	return resources
+}
+
+// migrateNetworkStatus moves the resource IDs that earlier releases recorded
+// in the deprecated status fields to the network resources, and sorts the
+// network resources that earlier releases recorded in reconcile order.
+func migrateNetworkStatus(cluster *infrastructurev1.NcxInfraCluster) {
+	status := &cluster.Status
+	network := &status.NetworkStatus
+	if status.VPCID != "" {
+		if network.VPC == nil {
+			network.VPC = &infrastructurev1.NetworkResourceStatus{
+				Name:  cluster.Spec.VPC.Name,
+				ID:    status.VPCID,
+				State: infrastructurev1.NetworkResourceReady,
+			}
+		}
+		status.VPCID = ""
+	}
+
+	for _, legacy := range []struct {
+		ids       *map[string]string
+		resources *[]infrastructurev1.NetworkResourceStatus
+	}{
+		{&network.SubnetIDs, &network.Subnets},
+		{&network.VPCPrefixIDs, &network.VPCPrefixes},
+		{&network.VPCPeeringIDs, &network.VPCPeerings},
+	} {
+		for _, name := range slices.Sorted(maps.Keys(*legacy.ids)) {
+			if resourceIDs(*legacy.resources)[name] == "" {
+				*legacy.resources = setResourceID(*legacy.resources, name, (*legacy.ids)[name])
+			}
+		}
+		*legacy.ids = nil
+	}
+
+	if network.NSGID != "" {
+		if network.NSG == nil {
+			network.NSG = &infrastructurev1.NSGStatus{
+				NetworkResourceStatus: infrastructurev1.NetworkResourceStatus{
+					Name:  nsgName(cluster.Spec.VPC),
+					ID:    network.NSGID,
+					State: infrastructurev1.NetworkResourceReady,
+				},
+			}
+		}
+		network.NSGID = ""
 	}
-	s.NcxInfraCluster.Status.NetworkStatus.VPCPeeringIDs[peerVPCID] = peeringID
+
+	legacyIPBlocks := []infrastructurev1.IPBlockStatus{{
+		IPBlockID:      network.IPBlockID,
+		AllocationID:   network.AllocationID,
+		ChildIPBlockID: network.ChildIPBlockID,
+	}}
+	for _, subnetName := range slices.Sorted(maps.Keys(network.PublicIPBlocks)) {
+		ipBlock := network.PublicIPBlocks[subnetName]
+		ipBlock.Subnet = subnetName
+		legacyIPBlocks = append(legacyIPBlocks, ipBlock)
+	}
+	for _, ipBlock := range legacyIPBlocks {
+		if ipBlock.IPBlockID == "" && ipBlock.AllocationID == "" && ipBlock.ChildIPBlockID == "" {
+			continue
+		}
+		if slices.ContainsFunc(network.IPBlocks, func(existing infrastructurev1.IPBlockStatus) bool {
+			return sameIPBlock(existing, ipBlock)
+		}) {
+			continue
+		}
+		ipBlock.State = infrastructurev1.NetworkResourceReady
+		network.IPBlocks = append(network.IPBlocks, ipBlock)
+	}
+	network.IPBlockID, network.AllocationID, network.ChildIPBlockID = "", "", ""
+	network.PublicIPBlocks = nil
+
+	slices.SortStableFunc(network.IPBlocks, compareIPBlocks)
+	slices.SortStableFunc(network.SiteVPCs, compareResources)
+	slices.SortStableFunc(network.Subnets, compareResources)
+	slices.SortStableFunc(network.VPCPrefixes, compareResources)
+	slices.SortStableFunc(network.VPCPeerings, compareResources)
 }
 
 // PatchObject persists the cluster status
 func (s *ClusterScope) PatchObject(ctx context.Context) error {
-	return s.Client.Status().Update(ctx, s.NcxInfraCluster)
+	return updateStatus(ctx, s.Client, s.NcxInfraCluster)
+}
+
+// updateStatus writes the status of obj, retrying on optimistic-lock
+// conflicts. The status owned by this scope is authoritative, so a conflict
+// only advances obj to the latest resourceVersion before the next attempt.
+func updateStatus(ctx context.Context, c client.Client, obj client.Object) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := c.Status().Update(ctx, obj)
+		if !apierrors.IsConflict(err) {
+			return err
+		}
+		latest, ok := obj.DeepCopyObject().(client.Object)
+		if !ok {
+			return err
+		}
+		if getErr := c.Get(ctx, client.ObjectKeyFromObject(obj), latest); getErr != nil {
+			return getErr
+		}
+		obj.SetResourceVersion(latest.GetResourceVersion())
+		return err
+	})
 }
 
 // Close closes the scope
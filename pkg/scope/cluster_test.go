@@ -0,0 +1,832 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2/clientcredentials"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+)
+
+// newClientTransport returns the transport of the API client of the credentials,
+// below its throttling, audit and logging transports.
+func newClientTransport(t *testing.T, creds *credentials) http.RoundTripper {
+	t.Helper()
+	throttling, ok := creds.newClient().(*ncxInfraClient).client.GetConfig().HTTPClient.Transport.(*throttlingTransport)
+	if !ok {
+		t.Fatalf("expected the API calls to be throttled")
+	}
+	audit, ok := throttling.base.(*auditTransport)
+	if !ok {
+		t.Fatalf("expected the API calls to be audited")
+	}
+	if audit.orgName != creds.orgName || audit.principal != creds.principal() {
+		t.Errorf("expected the calls audited as %s/%s, got %s/%s",
+			creds.orgName, creds.principal(), audit.orgName, audit.principal)
+	}
+	transport, ok := audit.base.(*loggingTransport)
+	if !ok {
+		t.Fatalf("expected the API calls to be logged")
+	}
+	return transport.base
+}
+
+func TestReadCredentials_Proxy(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ncx-infra-credentials", Namespace: "default"},
+		Data: map[string][]byte{
+			"endpoint":   []byte("https://api.ncx-infra.test"),
+			"orgName":    []byte("test-org"),
+			"token":      []byte("test-token"),
+			"httpsProxy": []byte("http://proxy.corp.example.com:3128"),
+			"noProxy":    []byte("internal.example.com"),
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	creds, err := readCredentials(context.Background(), c,
+		corev1.SecretReference{Name: "ncx-infra-credentials"}, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Proxy{HTTPSProxy: "http://proxy.corp.example.com:3128", NoProxy: "internal.example.com"}
+	if creds.proxy != want {
+		t.Errorf("expected proxy %+v, got %+v", want, creds.proxy)
+	}
+
+	transport := newClientTransport(t, creds).(*http.Transport)
+	for target, expected := range map[string]string{
+		"https://api.ncx-infra.test/v2":  "http://proxy.corp.example.com:3128",
+		"https://internal.example.com/x": "",
+	} {
+		req := &http.Request{URL: mustParseURL(t, target)}
+		proxyURL, err := transport.Proxy(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := ""
+		if proxyURL != nil {
+			got = proxyURL.String()
+		}
+		if got != expected {
+			t.Errorf("expected %s to go through %q, got %q", target, expected, got)
+		}
+	}
+}
+
+func TestReadCredentials_NoProxy(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ncx-infra-credentials", Namespace: "default"},
+		Data: map[string][]byte{
+			"endpoint": []byte("https://api.ncx-infra.test"),
+			"orgName":  []byte("test-org"),
+			"token":    []byte("test-token"),
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	creds, err := readCredentials(context.Background(), c,
+		corev1.SecretReference{Name: "ncx-infra-credentials"}, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !creds.proxy.IsZero() {
+		t.Errorf("expected no proxy, got %+v", creds.proxy)
+	}
+	if transport := newClientTransport(t, creds); transport != nil {
+		t.Errorf("expected the default transport, got %+v", transport)
+	}
+}
+
+func TestReadCredentials_RateLimit(t *testing.T) {
+	data := map[string][]byte{
+		"endpoint": []byte("https://api.ncx-infra.test"),
+		"orgName":  []byte("test-org"),
+		"token":    []byte("test-token"),
+		"qps":      []byte("2.5"),
+		"burst":    []byte("5"),
+	}
+	read := func() (*credentials, error) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ncx-infra-credentials", Namespace: "default"},
+			Data:       data,
+		}
+		c := fake.NewClientBuilder().WithObjects(secret).Build()
+		return readCredentials(context.Background(), c,
+			corev1.SecretReference{Name: "ncx-infra-credentials"}, "default")
+	}
+
+	creds, err := read()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.qps != 2.5 || creds.burst != 5 {
+		t.Errorf("expected a rate limit of 2.5/5, got %v/%d", creds.qps, creds.burst)
+	}
+
+	data["burst"] = []byte("0")
+	if _, err := read(); err == nil {
+		t.Error("expected an error for a zero burst")
+	}
+}
+
+func TestNewClusterScope_Organization(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = infrastructurev1.AddToScheme(scheme)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ncx-infra-credentials", Namespace: "default"},
+		Data: map[string][]byte{
+			"endpoint": []byte("https://api.ncx-infra.test"),
+			"orgName":  []byte("parent-org"),
+			"token":    []byte("test-token"),
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	for _, tt := range []struct {
+		organization string
+		want         string
+	}{
+		{organization: "", want: "parent-org"},
+		{organization: "child-org", want: "child-org"},
+	} {
+		clusterScope, err := NewClusterScope(context.Background(), ClusterScopeParams{
+			Client:  c,
+			Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+			NcxInfraCluster: &infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				Spec: infrastructurev1.NcxInfraClusterSpec{
+					Organization: tt.organization,
+					Authentication: infrastructurev1.AuthenticationSpec{
+						SecretRef: corev1.SecretReference{Name: "ncx-infra-credentials"},
+					},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if clusterScope.OrgName != tt.want {
+			t.Errorf("organization %q: expected org %s, got %s", tt.organization, tt.want, clusterScope.OrgName)
+		}
+		throttling := clusterScope.NcxInfraClient.(*ncxInfraClient).client.GetConfig().HTTPClient.Transport.(*throttlingTransport)
+		if audit := throttling.base.(*auditTransport); audit.orgName != tt.want {
+			t.Errorf("organization %q: expected the calls audited in org %s, got %s", tt.organization, tt.want, audit.orgName)
+		}
+	}
+}
+
+func TestParseSiteEndpoints(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "sites by ID and name",
+			value: "# EMEA\n8a880c71-fe4b-4e43-9e24-ebfcb8a84c5f = https://emea.ncx-infra.test\n\n" +
+				"us-west-1=https://us-west.ncx-infra.test\n",
+			want: map[string]string{
+				"8a880c71-fe4b-4e43-9e24-ebfcb8a84c5f": "https://emea.ncx-infra.test",
+				"us-west-1":                            "https://us-west.ncx-infra.test",
+			},
+		},
+		{name: "empty", value: "", want: map[string]string{}},
+		{name: "missing endpoint", value: "us-west-1", wantErr: true},
+		{name: "missing site", value: "=https://us-west.ncx-infra.test", wantErr: true},
+		{name: "plain HTTP", value: "us-west-1=http://us-west.ncx-infra.test", wantErr: true},
+		{name: "duplicate site", value: "us-west-1=https://a.test\nus-west-1=https://b.test", wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSiteEndpoints(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewClusterScope_SiteEndpoint(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = infrastructurev1.AddToScheme(scheme)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ncx-infra-credentials", Namespace: "default"},
+		Data: map[string][]byte{
+			"endpoint":      []byte("https://api.ncx-infra.test"),
+			"orgName":       []byte("test-org"),
+			"token":         []byte("test-token"),
+			"siteEndpoints": []byte("site-emea=https://emea.ncx-infra.test\nus-west-1=https://us-west.ncx-infra.test"),
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	for _, tt := range []struct {
+		site infrastructurev1.SiteReference
+		want string
+	}{
+		{site: infrastructurev1.SiteReference{ID: "site-emea"}, want: "https://emea.ncx-infra.test"},
+		{site: infrastructurev1.SiteReference{Name: "us-west-1"}, want: "https://us-west.ncx-infra.test"},
+		{site: infrastructurev1.SiteReference{ID: "site-apac"}, want: "https://api.ncx-infra.test"},
+	} {
+		clusterScope, err := NewClusterScope(context.Background(), ClusterScopeParams{
+			Client:  c,
+			Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}},
+			NcxInfraCluster: &infrastructurev1.NcxInfraCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				Spec: infrastructurev1.NcxInfraClusterSpec{
+					SiteRef: tt.site,
+					Authentication: infrastructurev1.AuthenticationSpec{
+						SecretRef: corev1.SecretReference{Name: "ncx-infra-credentials"},
+					},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if clusterScope.Endpoint != tt.want {
+			t.Errorf("site %+v: expected endpoint %s, got %s", tt.site, tt.want, clusterScope.Endpoint)
+		}
+		if url := clusterScope.NcxInfraClient.(*ncxInfraClient).client.GetConfig().Servers[0].URL; url != tt.want {
+			t.Errorf("site %+v: expected the client to call %s, got %s", tt.site, tt.want, url)
+		}
+	}
+}
+
+func TestCredentialsTLSConfig(t *testing.T) {
+	certPEM, keyPEM := testCertificate(t)
+	secret := func(data map[string]string) *corev1.Secret {
+		s := &corev1.Secret{Data: map[string][]byte{}}
+		for k, v := range data {
+			s.Data[k] = []byte(v)
+		}
+		return s
+	}
+
+	tlsConfig, err := credentialsTLSConfig(secret(nil))
+	if err != nil || tlsConfig != nil {
+		t.Errorf("expected no TLS configuration by default, got %+v (%v)", tlsConfig, err)
+	}
+
+	tlsConfig, err = credentialsTLSConfig(secret(map[string]string{
+		"caBundle":              string(certPEM),
+		corev1.TLSCertKey:       string(certPEM),
+		corev1.TLSPrivateKeyKey: string(keyPEM),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tlsConfig.RootCAs == nil || len(tlsConfig.Certificates) != 1 || tlsConfig.InsecureSkipVerify {
+		t.Errorf("expected the CA bundle and client certificate, got %+v", tlsConfig)
+	}
+
+	tlsConfig, err = credentialsTLSConfig(secret(map[string]string{"insecureSkipVerify": "true"}))
+	if err != nil || !tlsConfig.InsecureSkipVerify {
+		t.Errorf("expected verification to be skipped, got %+v (%v)", tlsConfig, err)
+	}
+
+	for name, data := range map[string]map[string]string{
+		"invalid CA bundle":        {"caBundle": "not a certificate"},
+		"certificate without key":  {corev1.TLSCertKey: string(certPEM)},
+		"invalid skip verify flag": {"insecureSkipVerify": "maybe"},
+	} {
+		if _, err := credentialsTLSConfig(secret(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestNewClient_TLS(t *testing.T) {
+	creds := &credentials{endpoint: "https://api.ncx-infra.test", tlsConfig: &tls.Config{MinVersion: tls.VersionTLS12}}
+
+	transport := newClientTransport(t, creds).(*http.Transport)
+	if transport.TLSClientConfig != creds.tlsConfig {
+		t.Errorf("expected the TLS configuration of the credentials")
+	}
+}
+
+func TestReadCredentials_OAuth2(t *testing.T) {
+	data := func(extra map[string]string) map[string][]byte {
+		d := map[string][]byte{
+			"endpoint": []byte("https://api.ncx-infra.test"),
+			"orgName":  []byte("test-org"),
+		}
+		for k, v := range extra {
+			d[k] = []byte(v)
+		}
+		return d
+	}
+	read := func(d map[string][]byte) (*credentials, error) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ncx-infra-credentials", Namespace: "default"},
+			Data:       d,
+		}
+		c := fake.NewClientBuilder().WithObjects(secret).Build()
+		return readCredentials(context.Background(), c,
+			corev1.SecretReference{Name: "ncx-infra-credentials"}, "default")
+	}
+
+	creds, err := read(data(map[string]string{
+		"clientID":     "capi-provider",
+		"clientSecret": "s3cr3t",
+		"tokenURL":     "https://auth.ncx-infra.test/token",
+		"scopes":       "tenant provider",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.token != "" || creds.oauth2 == nil || creds.oauth2.ClientID != "capi-provider" ||
+		len(creds.oauth2.Scopes) != 2 {
+		t.Errorf("expected the OAuth2 client credentials, got %+v", creds.oauth2)
+	}
+
+	for name, extra := range map[string]map[string]string{
+		"no token nor client ID": nil,
+		"missing client secret":  {"clientID": "capi-provider", "tokenURL": "https://auth.ncx-infra.test/token"},
+		"plain HTTP token URL": {
+			"clientID": "capi-provider", "clientSecret": "s3cr3t", "tokenURL": "http://auth.ncx-infra.test/token",
+		},
+		"token and client ID": {
+			"token": "test-token", "clientID": "capi-provider", "clientSecret": "s3cr3t",
+			"tokenURL": "https://auth.ncx-infra.test/token",
+		},
+	} {
+		if _, err := read(data(extra)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestNewClient_OAuth2(t *testing.T) {
+	var tokenRequests atomic.Int32
+	tokenStatus := http.StatusOK
+	var authorization string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if tokenStatus != http.StatusOK {
+			w.WriteHeader(tokenStatus)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`,
+			tokenRequests.Load())
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	})
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	newCreds := func(clientSecret string) *credentials {
+		return &credentials{
+			endpoint:  server.URL,
+			tlsConfig: &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool},
+			oauth2: &clientcredentials.Config{
+				ClientID:     "capi-provider",
+				ClientSecret: clientSecret,
+				TokenURL:     server.URL + "/token",
+			},
+		}
+	}
+
+	// The token is requested once and reused across clients
+	for range 2 {
+		if _, _, err := newCreds("s3cr3t").newClient().GetAllSite(context.Background(), "test-org"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if authorization != "Bearer token-1" {
+			t.Errorf("expected the OAuth2 access token, got %q", authorization)
+		}
+	}
+	if n := tokenRequests.Load(); n != 1 {
+		t.Errorf("expected a single token request, got %d", n)
+	}
+
+	// Rejected client credentials are reported as an authentication failure
+	tokenStatus = http.StatusUnauthorized
+	_, _, err := newCreds("wrong").newClient().GetAllSite(context.Background(), "test-org")
+	if failure := AuthenticationFailure(err); failure != "OAuth2 token request rejected (HTTP 401): invalid_client" {
+		t.Errorf("expected the rejected token request, got %q (%v)", failure, err)
+	}
+}
+
+func TestResolveCredentialsRef(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = infrastructurev1.AddToScheme(scheme)
+	identity := &infrastructurev1.NcxInfraIdentity{
+		ObjectMeta: metav1.ObjectMeta{Name: infrastructurev1.DefaultIdentityName, Namespace: "team-a"},
+		Spec: infrastructurev1.NcxInfraIdentitySpec{
+			SecretRef: corev1.SecretReference{Name: "team-a-credentials"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(identity).Build()
+	controllerDefault := corev1.SecretReference{Namespace: "capi-ncx-infra-system", Name: "ncx-infra-credentials"}
+
+	tests := []struct {
+		name               string
+		secretRef          corev1.SecretReference
+		namespace          string
+		defaultCredentials corev1.SecretReference
+		want               corev1.SecretReference
+		wantErr            bool
+	}{
+		{
+			name:               "explicit secret",
+			secretRef:          corev1.SecretReference{Name: "own-credentials"},
+			namespace:          "team-a",
+			defaultCredentials: controllerDefault,
+			want:               corev1.SecretReference{Name: "own-credentials"},
+		},
+		{
+			name:               "namespace identity",
+			namespace:          "team-a",
+			defaultCredentials: controllerDefault,
+			want:               corev1.SecretReference{Namespace: "team-a", Name: "team-a-credentials"},
+		},
+		{
+			name:               "controller default",
+			namespace:          "team-b",
+			defaultCredentials: controllerDefault,
+			want:               controllerDefault,
+		},
+		{
+			name:      "no credentials",
+			namespace: "team-b",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveCredentialsRef(context.Background(), c,
+				infrastructurev1.AuthenticationSpec{SecretRef: tt.secretRef}, tt.namespace, tt.defaultCredentials)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResolveCredentialsRef_CrossNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = infrastructurev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        "shared",
+			Namespace:   "platform",
+			Annotations: map[string]string{infrastructurev1.AllowedNamespacesAnnotation: "team-a"},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "private", Namespace: "platform"}},
+		&infrastructurev1.NcxInfraIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: infrastructurev1.DefaultIdentityName, Namespace: "team-b"},
+			Spec: infrastructurev1.NcxInfraIdentitySpec{
+				SecretRef: corev1.SecretReference{Namespace: "platform", Name: "shared"},
+			},
+		},
+	).Build()
+	resolve := func(name, namespace string) error {
+		auth := infrastructurev1.AuthenticationSpec{SecretRef: corev1.SecretReference{Namespace: "platform", Name: name}}
+		if name == "" {
+			auth = infrastructurev1.AuthenticationSpec{}
+		}
+		_, err := ResolveCredentialsRef(context.Background(), c, auth, namespace, corev1.SecretReference{})
+		return err
+	}
+
+	if err := resolve("shared", "team-a"); err != nil {
+		t.Errorf("expected the annotation to allow team-a, got %v", err)
+	}
+	if err := resolve("private", "team-a"); err == nil {
+		t.Error("expected an error for a secret without annotation")
+	}
+	if err := resolve("private", "platform"); err != nil {
+		t.Errorf("expected a secret to be usable from its own namespace, got %v", err)
+	}
+	// The secret of a namespace identity is checked as well
+	if err := resolve("", "team-b"); err == nil {
+		t.Error("expected an error for an identity secret that does not allow the namespace")
+	}
+}
+
+func TestResolveCredentialsRef_ClusterIdentity(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = infrastructurev1.AddToScheme(scheme)
+	secretRef := corev1.SecretReference{Namespace: "capi-ncx-infra-system", Name: "ncx-infra-credentials"}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		&infrastructurev1.NcxInfraClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+			Spec: infrastructurev1.NcxInfraClusterIdentitySpec{
+				SecretRef:         secretRef,
+				AllowedNamespaces: &infrastructurev1.AllowedNamespaces{NamesList: []string{"team-a"}},
+			},
+		},
+	).Build()
+	auth := infrastructurev1.AuthenticationSpec{IdentityRef: &corev1.LocalObjectReference{Name: "shared"}}
+
+	got, err := ResolveCredentialsRef(context.Background(), c, auth, "team-a", corev1.SecretReference{})
+	if err != nil || got != secretRef {
+		t.Errorf("expected the identity secret %+v, got %+v (%v)", secretRef, got, err)
+	}
+	if _, err := ResolveCredentialsRef(context.Background(), c, auth, "team-b", corev1.SecretReference{}); err == nil {
+		t.Error("expected an error for a namespace the identity does not allow")
+	}
+
+	auth.IdentityRef.Name = "missing"
+	if _, err := ResolveCredentialsRef(context.Background(), c, auth, "team-a", corev1.SecretReference{}); err == nil {
+		t.Error("expected an error for a missing identity")
+	}
+}
+
+// testCertificate returns a self-signed certificate and its key, PEM encoded.
+func testCertificate(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ncx-infra-test"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("invalid URL %s: %v", raw, err)
+	}
+	return u
+}
+
+func TestMigrateNetworkStatus(t *testing.T) {
+	cluster := &infrastructurev1.NcxInfraCluster{
+		Spec: infrastructurev1.NcxInfraClusterSpec{
+			VPC: infrastructurev1.VPCSpec{
+				Name:                 "vpc",
+				NetworkSecurityGroup: &infrastructurev1.NSGSpec{Name: "nsg"},
+			},
+		},
+		Status: infrastructurev1.NcxInfraClusterStatus{
+			VPCID: "vpc-uuid",
+			NetworkStatus: infrastructurev1.NetworkStatus{
+				SubnetIDs:      map[string]string{"workers": "subnet-2", "control-plane": "subnet-1"},
+				VPCPrefixIDs:   map[string]string{"physical": "prefix-1"},
+				VPCPeeringIDs:  map[string]string{"peer-vpc": "peering-1"},
+				NSGID:          "nsg-uuid",
+				IPBlockID:      "ipblock",
+				AllocationID:   "allocation",
+				ChildIPBlockID: "child",
+				PublicIPBlocks: map[string]infrastructurev1.IPBlockStatus{
+					"ingress": {IPBlockID: "public-ipblock", AllocationID: "public-allocation"},
+				},
+			},
+		},
+	}
+
+	migrateNetworkStatus(cluster)
+
+	ready := infrastructurev1.NetworkResourceReady
+	want := infrastructurev1.NcxInfraClusterStatus{
+		NetworkStatus: infrastructurev1.NetworkStatus{
+			VPC: &infrastructurev1.NetworkResourceStatus{Name: "vpc", ID: "vpc-uuid", State: ready},
+			IPBlocks: []infrastructurev1.IPBlockStatus{
+				{IPBlockID: "ipblock", AllocationID: "allocation", ChildIPBlockID: "child", State: ready},
+				{Subnet: "ingress", IPBlockID: "public-ipblock", AllocationID: "public-allocation", State: ready},
+			},
+			Subnets: []infrastructurev1.NetworkResourceStatus{
+				{Name: "control-plane", ID: "subnet-1", State: ready},
+				{Name: "workers", ID: "subnet-2", State: ready},
+			},
+			VPCPrefixes: []infrastructurev1.NetworkResourceStatus{{Name: "physical", ID: "prefix-1", State: ready}},
+			VPCPeerings: []infrastructurev1.NetworkResourceStatus{{Name: "peer-vpc", ID: "peering-1", State: ready}},
+			NSG: &infrastructurev1.NSGStatus{
+				NetworkResourceStatus: infrastructurev1.NetworkResourceStatus{Name: "nsg", ID: "nsg-uuid", State: ready},
+			},
+		},
+	}
+	if !reflect.DeepEqual(cluster.Status, want) {
+		t.Errorf("migrateNetworkStatus() = %+v, want %+v", cluster.Status, want)
+	}
+
+	// Migrating again changes nothing
+	migrateNetworkStatus(cluster)
+	if !reflect.DeepEqual(cluster.Status, want) {
+		t.Errorf("second migrateNetworkStatus() = %+v, want %+v", cluster.Status, want)
+	}
+}
+
+func TestMigrateNetworkStatus_Sorts(t *testing.T) {
+	ready := infrastructurev1.NetworkResourceReady
+	cluster := &infrastructurev1.NcxInfraCluster{
+		Status: infrastructurev1.NcxInfraClusterStatus{
+			NetworkStatus: infrastructurev1.NetworkStatus{
+				IPBlocks: []infrastructurev1.IPBlockStatus{
+					{Subnet: "ingress", IPBlockID: "public-ipblock", State: ready},
+					{IPBlockID: "ipblock", State: ready},
+				},
+				Subnets: []infrastructurev1.NetworkResourceStatus{
+					{Name: "workers", ID: "subnet-2", State: ready},
+					{Name: "control-plane", ID: "subnet-1", State: ready},
+				},
+			},
+		},
+	}
+
+	migrateNetworkStatus(cluster)
+
+	network := cluster.Status.NetworkStatus
+	if network.IPBlocks[0].Subnet != "" || network.IPBlocks[1].Subnet != "ingress" {
+		t.Errorf("expected the IP blocks sorted by subnet, got %+v", network.IPBlocks)
+	}
+	if network.Subnets[0].Name != "control-plane" || network.Subnets[1].Name != "workers" {
+		t.Errorf("expected the subnets sorted by name, got %+v", network.Subnets)
+	}
+}
+
+func TestClusterScopeNetworkResourceOrder(t *testing.T) {
+	s := &ClusterScope{NcxInfraCluster: &infrastructurev1.NcxInfraCluster{}}
+
+	s.SetSubnetID("workers", "subnet-2")
+	s.SetSubnetError("storage", fmt.Errorf("prefix exhausted"))
+	s.SetSubnetID("control-plane", "subnet-1")
+	var names []string
+	for _, subnet := range s.NcxInfraCluster.Status.NetworkStatus.Subnets {
+		names = append(names, subnet.Name)
+	}
+	if want := []string{"control-plane", "storage", "workers"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("subnets = %v, want %v", names, want)
+	}
+	if got := s.NcxInfraCluster.Status.NetworkStatus.Subnets[1]; got.LastError != "prefix exhausted" {
+		t.Errorf("expected the error on the storage subnet, got %+v", got)
+	}
+
+	s.SetIPBlock(infrastructurev1.IPBlockStatus{Subnet: "ingress", IPBlockID: "public-ipblock"}, nil)
+	s.SetIPBlock(infrastructurev1.IPBlockStatus{IPBlockID: "ipblock"}, nil)
+	if got := s.IPBlocks(); got[0].Subnet != "" || got[1].Subnet != "ingress" {
+		t.Errorf("expected the IP blocks sorted by subnet, got %+v", got)
+	}
+}
+
+func TestClusterScopeNetworkResourceErrors(t *testing.T) {
+	s := &ClusterScope{NcxInfraCluster: &infrastructurev1.NcxInfraCluster{}}
+
+	s.SetSubnetError("workers", fmt.Errorf("prefix exhausted"))
+	if got := s.NcxInfraCluster.Status.NetworkStatus.Subnets; len(got) != 1 ||
+		got[0].State != infrastructurev1.NetworkResourceFailed || got[0].LastError != "prefix exhausted" {
+		t.Errorf("expected a failed subnet, got %+v", got)
+	}
+	if ids := s.SubnetIDs(); len(ids) != 0 {
+		t.Errorf("expected no subnet ID, got %v", ids)
+	}
+
+	s.SetSubnetID("workers", "subnet-uuid")
+	if got := s.NcxInfraCluster.Status.NetworkStatus.Subnets; len(got) != 1 ||
+		got[0].State != infrastructurev1.NetworkResourceReady || got[0].LastError != "" {
+		t.Errorf("expected a ready subnet, got %+v", got)
+	}
+
+	now := metav1.Now()
+	s.NcxInfraCluster.DeletionTimestamp = &now
+	s.SetSubnetError("workers", fmt.Errorf("subnet in use"))
+	if got := s.NcxInfraCluster.Status.NetworkStatus.Subnets[0]; got.State != infrastructurev1.NetworkResourceDeleting ||
+		got.ID != "subnet-uuid" {
+		t.Errorf("expected a subnet being deleted, got %+v", got)
+	}
+
+	s.SetSubnetID("workers", "")
+	if got := s.NcxInfraCluster.Status.NetworkStatus.Subnets; len(got) != 0 {
+		t.Errorf("expected the subnet to be removed, got %+v", got)
+	}
+
+	s.SetIPBlock(infrastructurev1.IPBlockStatus{}, fmt.Errorf("no allocation"))
+	if got := s.IPBlock(""); got.State != infrastructurev1.NetworkResourceDeleting || got.LastError != "no allocation" {
+		t.Errorf("expected the IP block error to be recorded, got %+v", got)
+	}
+	s.SetIPBlock(infrastructurev1.IPBlockStatus{}, nil)
+	if got := s.IPBlocks(); len(got) != 0 {
+		t.Errorf("expected the IP block to be removed, got %+v", got)
+	}
+}
+
+func TestClusterScopeSubnetCIDR(t *testing.T) {
+	s := &ClusterScope{NcxInfraCluster: &infrastructurev1.NcxInfraCluster{}}
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+
+	s.SetSubnetID("workers", "subnet-1")
+	s.SetSubnetCIDR("workers", "10.0.4.0/24")
+	s.SetSubnetID("workers", "subnet-1")
+	if got := network.SubnetCIDR("workers"); got != "10.0.4.0/24" {
+		t.Errorf("SubnetCIDR() = %q after the subnet was verified, want 10.0.4.0/24", got)
+	}
+
+	s.SetSubnetID("workers", "subnet-2")
+	if got := network.SubnetCIDR("workers"); got != "" {
+		t.Errorf("SubnetCIDR() = %q after the subnet was recreated, want empty", got)
+	}
+}
+
+func TestClusterScopePatchObjectRetriesOnConflict(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrastructurev1.AddToScheme(scheme)
+	stored := &infrastructurev1.NcxInfraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stored).
+		WithStatusSubresource(stored).Build()
+
+	// Another writer updates the cluster after this scope read it
+	cluster := &infrastructurev1.NcxInfraCluster{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(stored), cluster); err != nil {
+		t.Fatal(err)
+	}
+	concurrent := cluster.DeepCopy()
+	concurrent.Labels = map[string]string{"updated": "true"}
+	if err := c.Update(context.Background(), concurrent); err != nil {
+		t.Fatal(err)
+	}
+
+	cluster.Status.Ready = true
+	s := &ClusterScope{Client: c, NcxInfraCluster: cluster}
+	if err := s.PatchObject(context.Background()); err != nil {
+		t.Fatalf("expected the conflict to be retried, got %v", err)
+	}
+	got := &infrastructurev1.NcxInfraCluster{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(stored), got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Status.Ready || got.Labels["updated"] != "true" {
+		t.Errorf("expected the status on top of the concurrent update, got %+v", got)
+	}
+}
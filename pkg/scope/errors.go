@@ -17,11 +17,17 @@ limitations under the License.
 package scope
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
+
+	"golang.org/x/oauth2"
 )
 
+// ErrCredentialsMissing is returned when the credentials secret does not exist.
+var ErrCredentialsMissing = errors.New("credentials missing")
+
 // APIErrorType classifies NICo API errors for retry decisions.
 type APIErrorType int
 
@@ -32,17 +38,57 @@ const (
 	APIErrorTerminal
 	// APIErrorNotFound indicates the resource no longer exists (404).
 	APIErrorNotFound
+	// APIErrorForbidden indicates the credentials lack the role required by the call (403).
+	APIErrorForbidden
+	// APIErrorUnauthorized indicates the credentials were rejected (401).
+	APIErrorUnauthorized
+)
+
+// NVIDIA Carbide roles required by the API calls of the provider.
+const (
+	// TenantAdminRole manages tenant resources: VPCs, subnets, NSGs and instances.
+	TenantAdminRole = "FORGE_TENANT_ADMIN"
+	// ProviderAdminRole manages site resources: IP blocks, allocations and trays.
+	ProviderAdminRole = "FORGE_PROVIDER_ADMIN"
 )
 
+// providerAdminMethods are the API calls restricted to the provider admin role.
+var providerAdminMethods = map[string]bool{
+	"CreateIpblock":    true,
+	"DeleteIpblock":    true,
+	"CreateAllocation": true,
+	"DeleteAllocation": true,
+	"GetAllTray":       true,
+	"PowerControlTray": true,
+}
+
+// RequiredRole returns the role the credentials need to perform an API call.
+func RequiredRole(method string) string {
+	if providerAdminMethods[method] {
+		return ProviderAdminRole
+	}
+	return TenantAdminRole
+}
+
 // APIError wraps an API error with classification and retry metadata.
 type APIError struct {
 	Type       APIErrorType
 	StatusCode int
 	Message    string
 	Err        error
+
+	// Method is the API call that failed, set for permission errors.
+	Method string
+	// RequiredRole is the role the call requires, set for permission errors.
+	RequiredRole string
+	// RequestID is the NVIDIA Carbide request ID of the failed call, if reported.
+	RequestID string
 }
 
 func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("%s (request ID %s)", e.Message, e.RequestID)
+	}
 	return e.Message
 }
 
@@ -65,6 +111,33 @@ func (e *APIError) IsNotFound() bool {
 	return e.Type == APIErrorNotFound
 }
 
+// IsForbidden returns true if the credentials lack the role required by the call.
+func (e *APIError) IsForbidden() bool {
+	return e.Type == APIErrorForbidden
+}
+
+// IsUnauthorized returns true if the credentials were rejected.
+func (e *APIError) IsUnauthorized() bool {
+	return e.Type == APIErrorUnauthorized
+}
+
+// IsUnreachable returns true if NVIDIA Carbide, or the site behind it, could
+// not answer: no response (timeout, connection refused, open circuit) or a
+// gateway error (502, 503, 504). Such an error tells nothing about the
+// resource, unlike a 404.
+func (e *APIError) IsUnreachable() bool {
+	if e.Type != APIErrorTransient {
+		return false
+	}
+	switch e.StatusCode {
+	case 0:
+		return AuthenticationFailure(e.Err) == ""
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
 // ClassifyAPIError classifies an HTTP response and error into an APIError.
 // Returns nil if the response indicates success (2xx).
 func ClassifyAPIError(httpResp *http.Response, err error, method string) *APIError {
@@ -76,6 +149,7 @@ func ClassifyAPIError(httpResp *http.Response, err error, method string) *APIErr
 	if httpResp != nil {
 		statusCode = httpResp.StatusCode
 	}
+	requestID := RequestID(httpResp)
 
 	switch {
 	case statusCode == http.StatusTooManyRequests,
@@ -85,6 +159,7 @@ func ClassifyAPIError(httpResp *http.Response, err error, method string) *APIErr
 		return &APIError{
 			Type:       APIErrorTransient,
 			StatusCode: statusCode,
+			RequestID:  requestID,
 			Message:    fmt.Sprintf("%s: transient error (HTTP %d)", method, statusCode),
 			Err:        err,
 		}
@@ -92,6 +167,7 @@ func ClassifyAPIError(httpResp *http.Response, err error, method string) *APIErr
 		return &APIError{
 			Type:       APIErrorTransient,
 			StatusCode: statusCode,
+			RequestID:  requestID,
 			Message:    fmt.Sprintf("%s: conflict (HTTP 409), resource being modified", method),
 			Err:        err,
 		}
@@ -99,13 +175,35 @@ func ClassifyAPIError(httpResp *http.Response, err error, method string) *APIErr
 		return &APIError{
 			Type:       APIErrorNotFound,
 			StatusCode: statusCode,
+			RequestID:  requestID,
 			Message:    fmt.Sprintf("%s: resource not found (HTTP 404)", method),
 			Err:        err,
 		}
+	case statusCode == http.StatusForbidden:
+		role := RequiredRole(method)
+		return &APIError{
+			Type:         APIErrorForbidden,
+			StatusCode:   statusCode,
+			RequestID:    requestID,
+			Message:      fmt.Sprintf("%s: forbidden (HTTP 403), requires the %s role", method, role),
+			Err:          err,
+			Method:       method,
+			RequiredRole: role,
+		}
+	case statusCode == http.StatusUnauthorized:
+		return &APIError{
+			Type:       APIErrorUnauthorized,
+			StatusCode: statusCode,
+			RequestID:  requestID,
+			Message:    fmt.Sprintf("%s: unauthorized (HTTP 401), credentials rejected", method),
+			Err:        err,
+			Method:     method,
+		}
 	case statusCode == http.StatusBadRequest:
 		return &APIError{
 			Type:       APIErrorTerminal,
 			StatusCode: statusCode,
+			RequestID:  requestID,
 			Message:    fmt.Sprintf("%s: bad request (HTTP 400)", method),
 			Err:        err,
 		}
@@ -113,20 +211,62 @@ func ClassifyAPIError(httpResp *http.Response, err error, method string) *APIErr
 		return &APIError{
 			Type:       APIErrorTransient,
 			StatusCode: statusCode,
+			RequestID:  requestID,
 			Message:    fmt.Sprintf("%s: server error (HTTP %d)", method, statusCode),
 			Err:        err,
 		}
+	case errors.Is(err, ErrCircuitOpen):
+		return &APIError{
+			Type:    APIErrorTransient,
+			Message: fmt.Sprintf("%s: skipped, %v", method, err),
+			Err:     err,
+		}
 	default:
 		// No HTTP response (timeout, connection refused, etc.)
 		return &APIError{
 			Type:       APIErrorTransient,
 			StatusCode: 0,
+			RequestID:  requestID,
 			Message:    fmt.Sprintf("%s: connection error", method),
 			Err:        err,
 		}
 	}
 }
 
+// WithPermissionError returns the classified error when an API call was
+// rejected for missing permissions or invalid credentials, and err unchanged
+// otherwise. It lets callers that wrap SDK errors keep permission errors
+// identifiable.
+func WithPermissionError(httpResp *http.Response, err error, method string) error {
+	if httpResp != nil &&
+		(httpResp.StatusCode == http.StatusForbidden || httpResp.StatusCode == http.StatusUnauthorized) {
+		return ClassifyAPIError(httpResp, err, method)
+	}
+	return err
+}
+
+// AuthenticationFailure describes why the credentials were rejected, either
+// by the NVIDIA Carbide API or by the OAuth2 token URL, and returns an empty
+// string when err is not an authentication failure.
+func AuthenticationFailure(err error) string {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		statusCode := 0
+		if retrieveErr.Response != nil {
+			statusCode = retrieveErr.Response.StatusCode
+		}
+		if retrieveErr.ErrorCode != "" {
+			return fmt.Sprintf("OAuth2 token request rejected (HTTP %d): %s", statusCode, retrieveErr.ErrorCode)
+		}
+		return fmt.Sprintf("OAuth2 token request rejected (HTTP %d)", statusCode)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.IsUnauthorized() {
+		return apiErr.Message
+	}
+	return ""
+}
+
 // RequeueAfterForAttempt returns an exponential backoff duration for a given retry attempt.
 // Caps at maxBackoff.
 func RequeueAfterForAttempt(attempt int) time.Duration {
@@ -17,10 +17,14 @@ limitations under the License.
 package scope
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
+
+	"golang.org/x/oauth2"
 )
 
 func TestClassifyAPIError(t *testing.T) {
@@ -85,6 +89,18 @@ func TestClassifyAPIError(t *testing.T) {
 			err:        fmt.Errorf("bad request"),
 			wantType:   APIErrorTerminal,
 		},
+		{
+			name:       "403 Forbidden is a permission error",
+			statusCode: 403,
+			err:        fmt.Errorf("forbidden"),
+			wantType:   APIErrorForbidden,
+		},
+		{
+			name:       "401 Unauthorized is an authentication error",
+			statusCode: 401,
+			err:        fmt.Errorf("unauthorized"),
+			wantType:   APIErrorUnauthorized,
+		},
 		{
 			name:       "500 Internal Server Error is transient",
 			statusCode: 500,
@@ -141,6 +157,86 @@ func TestAPIErrorMethods(t *testing.T) {
 	}
 }
 
+func TestAPIErrorIsUnreachable(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+		want       bool
+	}{
+		{name: "connection refused", err: errors.New("connection refused"), want: true},
+		{name: "open circuit", err: ErrCircuitOpen, want: true},
+		{name: "bad gateway", statusCode: http.StatusBadGateway, want: true},
+		{name: "service unavailable", statusCode: http.StatusServiceUnavailable, want: true},
+		{name: "gateway timeout", statusCode: http.StatusGatewayTimeout, want: true},
+		{name: "not found", statusCode: http.StatusNotFound},
+		{name: "rate limited", statusCode: http.StatusTooManyRequests},
+		{name: "internal server error", statusCode: http.StatusInternalServerError},
+		{name: "token rejected", err: &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusUnauthorized}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.statusCode != 0 {
+				resp = &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			}
+			apiErr := ClassifyAPIError(resp, tt.err, "GetVpc")
+			if got := apiErr.IsUnreachable(); got != tt.want {
+				t.Errorf("IsUnreachable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForbiddenRequiredRole(t *testing.T) {
+	forbidden := &http.Response{StatusCode: http.StatusForbidden}
+
+	apiErr := ClassifyAPIError(forbidden, fmt.Errorf("403 Forbidden"), "CreateVpc")
+	if !apiErr.IsForbidden() || apiErr.IsTransient() || apiErr.IsTerminal() {
+		t.Fatalf("expected a permission error, got %+v", apiErr)
+	}
+	if apiErr.Method != "CreateVpc" || apiErr.RequiredRole != TenantAdminRole {
+		t.Errorf("expected CreateVpc to require %s, got %s", TenantAdminRole, apiErr.RequiredRole)
+	}
+	if role := ClassifyAPIError(forbidden, nil, "CreateIpblock").RequiredRole; role != ProviderAdminRole {
+		t.Errorf("expected CreateIpblock to require %s, got %s", ProviderAdminRole, role)
+	}
+
+	sdkErr := fmt.Errorf("400 Bad Request")
+	if err := WithPermissionError(&http.Response{StatusCode: http.StatusBadRequest}, sdkErr, "CreateVpc"); err != sdkErr {
+		t.Errorf("expected non-permission errors to be returned unchanged, got %v", err)
+	}
+	var wrapped *APIError
+	if err := WithPermissionError(forbidden, sdkErr, "CreateVpc"); !errors.As(err, &wrapped) || !wrapped.IsForbidden() {
+		t.Errorf("expected a permission error, got %v", err)
+	}
+}
+
+func TestAuthenticationFailure(t *testing.T) {
+	unauthorized := &http.Response{StatusCode: http.StatusUnauthorized}
+	err := fmt.Errorf("failed to list sites: %w",
+		WithPermissionError(unauthorized, fmt.Errorf("401 Unauthorized"), "GetAllSite"))
+	if got := AuthenticationFailure(err); got != "GetAllSite: unauthorized (HTTP 401), credentials rejected" {
+		t.Errorf("unexpected failure for a rejected API call: %q", got)
+	}
+
+	tokenErr := &url.Error{Op: "Get", URL: "https://api.ncx-infra.test", Err: &oauth2.RetrieveError{
+		Response:  &http.Response{StatusCode: http.StatusUnauthorized},
+		ErrorCode: "invalid_client",
+	}}
+	if got := AuthenticationFailure(ClassifyAPIError(nil, tokenErr, "GetInstance")); got !=
+		"OAuth2 token request rejected (HTTP 401): invalid_client" {
+		t.Errorf("unexpected failure for a rejected token request: %q", got)
+	}
+
+	forbidden := WithPermissionError(&http.Response{StatusCode: http.StatusForbidden}, fmt.Errorf("403"), "CreateVpc")
+	for _, err := range []error{nil, fmt.Errorf("connection refused"), forbidden} {
+		if got := AuthenticationFailure(err); got != "" {
+			t.Errorf("expected %v not to be an authentication failure, got %q", err, got)
+		}
+	}
+}
+
 func TestRequeueAfterForAttempt(t *testing.T) {
 	tests := []struct {
 		attempt  int
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// RequestIDHeader is the response header carrying the ID NVIDIA Carbide gives
+// to a request, which identifies it in the NVIDIA Carbide audit logs.
+const RequestIDHeader = "X-Request-Id"
+
+// redactedValue replaces the secrets of the logged payloads.
+const redactedValue = "REDACTED"
+
+// logAPIPayloads enables logging the bodies of the API calls.
+var logAPIPayloads atomic.Bool
+
+// SetLogAPIPayloads enables logging the request and response bodies of the
+// NVIDIA Carbide API calls, with their secrets redacted. Meant for debugging.
+func SetLogAPIPayloads(enabled bool) {
+	logAPIPayloads.Store(enabled)
+}
+
+// RequestID returns the NVIDIA Carbide request ID of a response, or an empty
+// string without response.
+func RequestID(httpResp *http.Response) string {
+	if httpResp == nil {
+		return ""
+	}
+	return httpResp.Header.Get(RequestIDHeader)
+}
+
+// loggingTransport logs the failed NVIDIA Carbide API calls with their request
+// ID, using the logger of the request context so the lines carry the keys of
+// the object being reconciled. It logs the bodies of every call when
+// SetLogAPIPayloads is enabled.
+type loggingTransport struct {
+	// base is the transport performing the calls, http.DefaultTransport when nil
+	base http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	logger := log.FromContext(req.Context()).WithValues("httpMethod", req.Method, "path", req.URL.Path)
+	payloads := logAPIPayloads.Load()
+
+	if payloads {
+		var body []byte
+		if req.GetBody != nil {
+			if reader, err := req.GetBody(); err == nil {
+				body, _ = io.ReadAll(reader)
+				_ = reader.Close()
+			}
+		}
+		logger.Info("NVIDIA Carbide API request", "body", redactPayload(body))
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	duration := time.Since(start)
+	if err != nil {
+		logger.Info("NVIDIA Carbide API call failed", "duration", duration, "error", err.Error())
+		return resp, err
+	}
+
+	logger = logger.WithValues("statusCode", resp.StatusCode, "requestID", RequestID(resp), "duration", duration)
+	if payloads {
+		body, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			return resp, readErr
+		}
+		logger.Info("NVIDIA Carbide API response", "body", redactPayload(body))
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		logger.Info("NVIDIA Carbide API call failed")
+	}
+	return resp, nil
+}
+
+// redactPayload returns a JSON payload with the values of its secret fields
+// replaced. Payloads that are not JSON are not logged.
+func redactPayload(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Sprintf("<%d bytes, not JSON>", len(body))
+	}
+	redacted, err := json.Marshal(redactValue(payload))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+	return string(redacted)
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if secretField(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// secretField returns whether a payload field holds a secret. The user data of
+// instances holds the bootstrap data of the machines, with join tokens and
+// certificates.
+func secretField(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range []string{"password", "secret", "token", "privatekey", "userdata"} {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
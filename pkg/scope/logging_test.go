@@ -0,0 +1,117 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactPayload(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "empty body",
+			body: "",
+			want: "",
+		},
+		{
+			name: "not JSON",
+			body: "plain text",
+			want: "<10 bytes, not JSON>",
+		},
+		{
+			name: "top-level secrets",
+			body: `{"name":"worker-0","userData":"#cloud-config","clientSecret":"s3cr3t"}`,
+			want: `{"clientSecret":"REDACTED","name":"worker-0","userData":"REDACTED"}`,
+		},
+		{
+			name: "nested objects and arrays",
+			body: `{"interfaces":[{"subnetId":"subnet-1","password":"p"}],"auth":{"accessToken":"t"}}`,
+			want: `{"auth":{"accessToken":"REDACTED"},"interfaces":[{"password":"REDACTED","subnetId":"subnet-1"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactPayload([]byte(tt.body)); got != tt.want {
+				t.Errorf("redactPayload() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggingTransportKeepsResponseBody(t *testing.T) {
+	SetLogAPIPayloads(true)
+	defer SetLogAPIPayloads(false)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RequestIDHeader, "req-123")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"message":"already exists"}`))
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: &loggingTransport{}}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/instance", strings.NewReader(`{"userData":"x"}`))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(body) != `{"message":"already exists"}` {
+		t.Errorf("response body = %q, want the server body", body)
+	}
+	if got := RequestID(resp); got != "req-123" {
+		t.Errorf("RequestID() = %q, want %q", got, "req-123")
+	}
+}
+
+func TestAPIErrorIncludesRequestID(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}}
+	resp.Header.Set(RequestIDHeader, "req-456")
+
+	apiErr := ClassifyAPIError(resp, errors.New("internal error"), "CreateInstance")
+	if apiErr == nil {
+		t.Fatal("ClassifyAPIError() = nil, want an error")
+	}
+	if apiErr.RequestID != "req-456" {
+		t.Errorf("RequestID = %q, want %q", apiErr.RequestID, "req-456")
+	}
+	if !strings.Contains(apiErr.Error(), "request ID req-456") {
+		t.Errorf("Error() = %q, want it to include the request ID", apiErr.Error())
+	}
+
+	if RequestID(nil) != "" {
+		t.Error("RequestID(nil) should be empty")
+	}
+}
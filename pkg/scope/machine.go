@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	corev1 "k8s.io/api/core/v1"
@@ -50,6 +51,11 @@ type MachineScope struct {
 	NcxInfraMachine *infrastructurev1.NcxInfraMachine
 	NcxInfraClient  NcxInfraClientInterface
 	OrgName         string // Organization name for API calls
+
+	// subnetName and siteID are the subnet the instance is created on and its
+	// site, when set by SetSubnet
+	subnetName string
+	siteID     string
 }
 
 // NewMachineScope creates a new machine scope
@@ -99,7 +105,8 @@ func (s *MachineScope) Namespace() string {
 
 // IsControlPlane returns whether the machine is a control plane node
 func (s *MachineScope) IsControlPlane() bool {
-	return s.Machine.Labels[clusterv1.MachineControlPlaneLabel] != ""
+	_, ok := s.Machine.Labels[clusterv1.MachineControlPlaneLabel]
+	return ok
 }
 
 // Role returns the machine role (control-plane or worker)
@@ -195,10 +202,58 @@ func (s *MachineScope) SetAddresses(addresses []clusterv1.MachineAddress) {
 	s.Machine.Status.Addresses = addresses
 }
 
+// Bootstrap data formats of the CAPI bootstrap provider contract
+const (
+	BootstrapFormatCloudConfig = "cloud-config"
+	BootstrapFormatIgnition    = "ignition"
+)
+
 // GetBootstrapData returns the bootstrap data for the machine
 func (s *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
+	data, _, err := s.GetBootstrapDataWithFormat(ctx)
+	return data, err
+}
+
+// GetBootstrapDataWithFormat returns the bootstrap data for the machine and
+// its format. Per the CAPI contract, the format is read from the optional
+// 'format' key of the bootstrap secret and defaults to cloud-config.
+func (s *MachineScope) GetBootstrapDataWithFormat(ctx context.Context) (string, string, error) {
+	bootstrapSecret, err := s.getBootstrapSecret(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	data, ok := bootstrapSecret.Data["value"]
+	if !ok {
+		return "", "", fmt.Errorf("bootstrap secret missing 'value' key")
+	}
+
+	format := BootstrapFormatCloudConfig
+	if value, ok := bootstrapSecret.Data["format"]; ok {
+		format = string(value)
+		if format != BootstrapFormatCloudConfig && format != BootstrapFormatIgnition {
+			return "", "", fmt.Errorf("bootstrap secret has unsupported format %q, expected %s or %s",
+				format, BootstrapFormatCloudConfig, BootstrapFormatIgnition)
+		}
+	}
+
+	return string(data), format, nil
+}
+
+// GetBootstrapDataCreationTime returns the creation time of the bootstrap
+// secret of the machine, which the bootstrap provider creates along with the
+// bootstrap token embedded in the data.
+func (s *MachineScope) GetBootstrapDataCreationTime(ctx context.Context) (time.Time, error) {
+	bootstrapSecret, err := s.getBootstrapSecret(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return bootstrapSecret.CreationTimestamp.Time, nil
+}
+
+func (s *MachineScope) getBootstrapSecret(ctx context.Context) (*corev1.Secret, error) {
 	if s.Machine.Spec.Bootstrap.DataSecretName == nil {
-		return "", fmt.Errorf("bootstrap data secret name is not set")
+		return nil, fmt.Errorf("bootstrap data secret name is not set")
 	}
 
 	secret := &client.ObjectKey{
@@ -208,25 +263,34 @@ func (s *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
 
 	bootstrapSecret := &corev1.Secret{}
 	if err := s.Get(ctx, *secret, bootstrapSecret); err != nil {
-		return "", fmt.Errorf("failed to get bootstrap secret: %w", err)
+		return nil, fmt.Errorf("failed to get bootstrap secret: %w", err)
 	}
+	return bootstrapSecret, nil
+}
 
-	data, ok := bootstrapSecret.Data["value"]
-	if !ok {
-		return "", fmt.Errorf("bootstrap secret missing 'value' key")
-	}
+// SetSubnet attaches the instance being created to a subnet of the cluster in
+// a site, which can differ from the subnet of the spec to follow the failure
+// domain of the Machine.
+func (s *MachineScope) SetSubnet(subnetName, siteID string) {
+	s.subnetName, s.siteID = subnetName, siteID
+}
 
-	return string(data), nil
+// SubnetName returns the subnet the instance is attached to: the one set by
+// SetSubnet, or the one of the spec
+func (s *MachineScope) SubnetName() string {
+	if s.subnetName != "" {
+		return s.subnetName
+	}
+	return s.NcxInfraMachine.Spec.Network.SubnetName
 }
 
 // GetSubnetID returns the subnet ID for the machine's network
 func (s *MachineScope) GetSubnetID() (string, error) {
-	subnetName := s.NcxInfraMachine.Spec.Network.SubnetName
+	subnetName := s.SubnetName()
 
 	// Look up subnet ID from cluster status
-	subnetIDs := s.NcxInfraCluster.Status.NetworkStatus.SubnetIDs
-	subnetID, ok := subnetIDs[subnetName]
-	if !ok {
+	subnetID := s.NcxInfraCluster.Status.NetworkStatus.SubnetID(subnetName)
+	if subnetID == "" {
 		return "", fmt.Errorf("subnet %s not found in cluster status", subnetName)
 	}
 
@@ -237,34 +301,42 @@ func (s *MachineScope) GetSubnetID() (string, error) {
 func (s *MachineScope) GetVPCPrefixID() (string, error) {
 	prefixName := s.NcxInfraMachine.Spec.Network.VPCPrefixName
 
-	vpcPrefixIDs := s.NcxInfraCluster.Status.NetworkStatus.VPCPrefixIDs
-	prefixID, ok := vpcPrefixIDs[prefixName]
-	if !ok {
+	prefixID := s.NcxInfraCluster.Status.NetworkStatus.VPCPrefixID(prefixName)
+	if prefixID == "" {
 		return "", fmt.Errorf("VPC prefix %s not found in cluster status", prefixName)
 	}
 
 	return prefixID, nil
 }
 
-// VPCID returns the VPC ID from the cluster
+// VPCID returns the VPC ID from the cluster: the VPC of the cluster in the
+// site set by SetSubnet when it is another site than the one of the cluster
 func (s *MachineScope) VPCID() string {
-	return s.NcxInfraCluster.Status.VPCID
+	network := &s.NcxInfraCluster.Status.NetworkStatus
+	if vpcID := network.SiteVPCID(s.siteID); s.siteID != "" && vpcID != "" {
+		return vpcID
+	}
+	return network.VPCID()
 }
 
-// TenantID returns the tenant ID from the cluster
+// TenantID returns the tenant ID of the machine, the one of the cluster
+// unless the machine overrides it
 func (s *MachineScope) TenantID() string {
+	if s.NcxInfraMachine.Spec.TenantID != "" {
+		return s.NcxInfraMachine.Spec.TenantID
+	}
 	return s.NcxInfraCluster.Spec.TenantID
 }
 
 // PatchObject persists the machine status
 func (s *MachineScope) PatchObject(ctx context.Context) error {
 	// Update NcxInfraMachine status
-	if err := s.Client.Status().Update(ctx, s.NcxInfraMachine); err != nil {
+	if err := updateStatus(ctx, s.Client, s.NcxInfraMachine); err != nil {
 		return fmt.Errorf("failed to update ncx infra machine status: %w", err)
 	}
 
 	// Update Machine status
-	if err := s.Client.Status().Update(ctx, s.Machine); err != nil {
+	if err := updateStatus(ctx, s.Client, s.Machine); err != nil {
 		return fmt.Errorf("failed to update machine status: %w", err)
 	}
 
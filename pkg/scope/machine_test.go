@@ -0,0 +1,94 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetBootstrapDataWithFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       map[string][]byte
+		wantFormat string
+		wantErr    string
+	}{
+		{
+			name:       "no format defaults to cloud-config",
+			data:       map[string][]byte{"value": []byte("#cloud-config")},
+			wantFormat: BootstrapFormatCloudConfig,
+		},
+		{
+			name:       "ignition",
+			data:       map[string][]byte{"value": []byte(`{"ignition":{}}`), "format": []byte("ignition")},
+			wantFormat: BootstrapFormatIgnition,
+		},
+		{
+			name:    "unsupported format",
+			data:    map[string][]byte{"value": []byte("#!/bin/sh"), "format": []byte("shell")},
+			wantErr: `unsupported format "shell"`,
+		},
+		{
+			name:    "missing value",
+			data:    map[string][]byte{"format": []byte("cloud-config")},
+			wantErr: "missing 'value' key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bootstrap", Namespace: "default"},
+				Data:       tt.data,
+			}
+			s := &MachineScope{
+				Client: fake.NewClientBuilder().WithObjects(secret).Build(),
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"},
+					Spec: clusterv1.MachineSpec{
+						Bootstrap: clusterv1.Bootstrap{DataSecretName: ptr.To("bootstrap")},
+					},
+				},
+			}
+
+			data, format, err := s.GetBootstrapDataWithFormat(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if data != string(tt.data["value"]) {
+				t.Errorf("data = %q, want %q", data, tt.data["value"])
+			}
+			if format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", format, tt.wantFormat)
+			}
+		})
+	}
+}
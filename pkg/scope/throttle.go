@@ -0,0 +1,193 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ErrCircuitOpen is returned for the API reads skipped while the NVIDIA
+// Carbide API of an organization keeps failing.
+var ErrCircuitOpen = errors.New("NVIDIA Carbide API circuit breaker open")
+
+// ThrottleConfig configures the rate limiting and the circuit breaker of the
+// NVIDIA Carbide API calls, applied to each organization of each endpoint.
+type ThrottleConfig struct {
+	// QPS is the sustained rate of the calls. Zero disables rate limiting.
+	QPS float64
+	// Burst is the number of calls allowed above QPS after an idle period.
+	Burst int
+	// FailureThreshold is the number of consecutive server errors (HTTP 5xx)
+	// opening the circuit. Zero disables the circuit breaker.
+	FailureThreshold int
+	// OpenDuration is how long the reads are skipped once the circuit opens,
+	// before a call probes the API again.
+	OpenDuration time.Duration
+}
+
+// DefaultThrottleConfig is the throttling of the API calls unless
+// SetThrottleConfig is called.
+var DefaultThrottleConfig = ThrottleConfig{
+	QPS:              10,
+	Burst:            20,
+	FailureThreshold: 5,
+	OpenDuration:     30 * time.Second,
+}
+
+// throttleConfig is the throttling applied to the organizations without
+// qps or burst in their credentials secret.
+var throttleConfig atomic.Pointer[ThrottleConfig]
+
+func init() {
+	SetThrottleConfig(DefaultThrottleConfig)
+}
+
+// SetThrottleConfig sets the throttling of the NVIDIA Carbide API calls. It
+// applies to the clients created afterwards.
+func SetThrottleConfig(cfg ThrottleConfig) {
+	throttleConfig.Store(&cfg)
+}
+
+// throttles holds the throttle of each organization of each endpoint, keyed by
+// throttleKey, so that it outlives the clients created by the reconciliations.
+var throttles sync.Map
+
+func throttleKey(endpoint, orgName string) string {
+	return endpoint + "\x00" + orgName
+}
+
+// orgThrottle rate limits the API calls of an organization, and skips its
+// reads while the API keeps failing.
+type orgThrottle struct {
+	limiter *rate.Limiter
+
+	mu sync.Mutex
+	// failures is the number of consecutive server errors
+	failures int
+	// openUntil is the end of the period the reads are skipped
+	openUntil time.Time
+	cfg       ThrottleConfig
+}
+
+// throttleFor returns the throttle of an organization, with the limits of its
+// credentials or of the controller.
+func throttleFor(endpoint, orgName string, qps float64, burst int) *orgThrottle {
+	cfg := *throttleConfig.Load()
+	if qps > 0 {
+		cfg.QPS = qps
+	}
+	if burst > 0 {
+		cfg.Burst = burst
+	}
+	limit := rate.Limit(cfg.QPS)
+	if cfg.QPS <= 0 {
+		limit = rate.Inf
+	}
+	if cfg.Burst < 1 {
+		cfg.Burst = 1
+	}
+
+	value, loaded := throttles.LoadOrStore(throttleKey(endpoint, orgName), &orgThrottle{
+		limiter: rate.NewLimiter(limit, cfg.Burst),
+		cfg:     cfg,
+	})
+	throttle := value.(*orgThrottle)
+	if loaded {
+		throttle.limiter.SetLimit(limit)
+		throttle.limiter.SetBurst(cfg.Burst)
+		throttle.mu.Lock()
+		throttle.cfg = cfg
+		throttle.mu.Unlock()
+	}
+	return throttle
+}
+
+// allow returns ErrCircuitOpen for a read while the circuit is open. Writes
+// always go through: creating, updating and deleting resources is what the
+// reconciliations cannot defer, while the reads are mostly status polling.
+func (t *orgThrottle) allow(req *http.Request) error {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if remaining := time.Until(t.openUntil); remaining > 0 {
+		return fmt.Errorf("%w, retrying in %s", ErrCircuitOpen, remaining.Round(time.Second))
+	}
+	return nil
+}
+
+// record counts the consecutive server errors, and opens the circuit when
+// they reach the threshold. After OpenDuration, the reads probe the API
+// again: a server error opens the circuit again, and a success closes it.
+func (t *orgThrottle) record(req *http.Request, resp *http.Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cfg.FailureThreshold <= 0 {
+		return
+	}
+	logger := log.FromContext(req.Context())
+	if resp.StatusCode < http.StatusInternalServerError {
+		if t.failures >= t.cfg.FailureThreshold {
+			logger.Info("NVIDIA Carbide API recovered, resuming the reads")
+		}
+		t.failures = 0
+		return
+	}
+	t.failures++
+	if t.failures >= t.cfg.FailureThreshold && !time.Now().Before(t.openUntil) {
+		t.openUntil = time.Now().Add(t.cfg.OpenDuration)
+		logger.Info("NVIDIA Carbide API keeps failing, pausing the reads",
+			"consecutiveFailures", t.failures, "pause", t.cfg.OpenDuration)
+	}
+}
+
+// throttlingTransport rate limits the NVIDIA Carbide API calls of an
+// organization, and skips its reads while its circuit is open, so that the
+// reconciliations restarting together, or retrying an unavailable API, do
+// not overload an API shared with other tenants.
+type throttlingTransport struct {
+	throttle *orgThrottle
+	// base is the transport performing the calls, http.DefaultTransport when nil
+	base http.RoundTripper
+}
+
+func (t *throttlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if err := t.throttle.allow(req); err != nil {
+		return nil, err
+	}
+	if err := t.throttle.limiter.Wait(req.Context()); err != nil {
+		return nil, fmt.Errorf("rate limit of the NVIDIA Carbide API: %w", err)
+	}
+	resp, err := base.RoundTrip(req)
+	if err == nil {
+		t.throttle.record(req, resp)
+	}
+	return resp, err
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestThrottlingTransportCircuitBreaker(t *testing.T) {
+	SetThrottleConfig(ThrottleConfig{FailureThreshold: 2, OpenDuration: 50 * time.Millisecond})
+	defer SetThrottleConfig(DefaultThrottleConfig)
+
+	var calls, status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: &throttlingTransport{
+		throttle: throttleFor(server.URL, t.Name(), 0, 0),
+	}}
+	do := func(method string) error {
+		req, err := http.NewRequest(method, server.URL+"/instance", nil)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		resp, err := httpClient.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	for range 2 {
+		if err := do(http.MethodGet); err != nil {
+			t.Fatalf("Do() error = %v before the circuit opens", err)
+		}
+	}
+	if err := do(http.MethodGet); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Do(GET) error = %v, want ErrCircuitOpen", err)
+	}
+	if err := do(http.MethodDelete); err != nil {
+		t.Errorf("Do(DELETE) error = %v, want the writes to go through an open circuit", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("API calls = %d, want 3", got)
+	}
+	if apiErr := ClassifyAPIError(nil, do(http.MethodGet), "GetInstance"); apiErr == nil || !apiErr.IsTransient() {
+		t.Errorf("ClassifyAPIError() = %v, want a transient error", apiErr)
+	}
+
+	// The probe after the pause closes the circuit once the API recovers
+	time.Sleep(60 * time.Millisecond)
+	status.Store(http.StatusOK)
+	for range 3 {
+		if err := do(http.MethodGet); err != nil {
+			t.Errorf("Do() error = %v after the API recovered", err)
+		}
+	}
+}
+
+func TestThrottleForCredentialsLimits(t *testing.T) {
+	SetThrottleConfig(ThrottleConfig{QPS: 5, Burst: 10})
+	defer SetThrottleConfig(DefaultThrottleConfig)
+
+	throttle := throttleFor("https://carbide.example.com", t.Name(), 0, 0)
+	if throttle.limiter.Limit() != 5 || throttle.limiter.Burst() != 10 {
+		t.Errorf("limits = %v/%d, want the controller limits 5/10", throttle.limiter.Limit(), throttle.limiter.Burst())
+	}
+
+	// The limits of the credentials secret apply to the same throttle
+	same := throttleFor("https://carbide.example.com", t.Name(), 50, 100)
+	if same != throttle {
+		t.Fatal("throttleFor() returned a new throttle for the same organization")
+	}
+	if throttle.limiter.Limit() != 50 || throttle.limiter.Burst() != 100 {
+		t.Errorf("limits = %v/%d, want the credentials limits 50/100", throttle.limiter.Limit(), throttle.limiter.Burst())
+	}
+
+	if other := throttleFor("https://carbide.example.com", t.Name()+"-other", 0, 0); other == throttle {
+		t.Error("throttleFor() shared the throttle of another organization")
+	}
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+)
+
+// basePath is the prefix of the NVIDIA Carbide REST API routes of an organization.
+const basePath = "/v2/org/{org}/carbide"
+
+// endpoint serves an API call from the simulated client. It returns the body
+// of the response, or the error of the call with its HTTP response.
+type endpoint func(r *http.Request) (any, *http.Response, error)
+
+// NewHandler serves the NVIDIA Carbide REST API from a simulated client, so
+// that the SDK client built from a credentials secret can be pointed at an
+// httptest server. The calls go through the same state as the in-memory
+// client: resources created over HTTP can be read back from it, and the
+// reverse. Errors are returned in the format of the real API.
+func NewHandler(c *Client) http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, e endpoint) {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			body, resp, err := e(r)
+			writeResponse(w, body, resp, err)
+		})
+	}
+
+	handle("GET "+basePath+"/site", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetAllSite(r.Context(), r.PathValue("org"))
+	})
+	handle("GET "+basePath+"/site/{siteId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetSite(r.Context(), r.PathValue("org"), r.PathValue("siteId"))
+	})
+	handle("GET "+basePath+"/tenant/current", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetCurrentTenant(r.Context(), r.PathValue("org"))
+	})
+	handle("GET "+basePath+"/self/quotas", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetSelfQuotas(r.Context(), r.PathValue("org"))
+	})
+
+	handle("POST "+basePath+"/vpc", withBody(
+		func(r *http.Request, req nico.VpcCreateRequest) (any, *http.Response, error) {
+			return c.CreateVpc(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("GET "+basePath+"/vpc/{vpcId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetVpc(r.Context(), r.PathValue("org"), r.PathValue("vpcId"))
+	})
+	handle("PATCH "+basePath+"/vpc/{vpcId}", withBody(
+		func(r *http.Request, req nico.VpcUpdateRequest) (any, *http.Response, error) {
+			return c.UpdateVpc(r.Context(), r.PathValue("org"), r.PathValue("vpcId"), req)
+		}))
+	handle("DELETE "+basePath+"/vpc/{vpcId}", func(r *http.Request) (any, *http.Response, error) {
+		return noBody(c.DeleteVpc(r.Context(), r.PathValue("org"), r.PathValue("vpcId")))
+	})
+
+	handle("POST "+basePath+"/subnet", withBody(
+		func(r *http.Request, req nico.SubnetCreateRequest) (any, *http.Response, error) {
+			return c.CreateSubnet(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("GET "+basePath+"/subnet/{subnetId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetSubnet(r.Context(), r.PathValue("org"), r.PathValue("subnetId"))
+	})
+	handle("DELETE "+basePath+"/subnet/{subnetId}", func(r *http.Request) (any, *http.Response, error) {
+		return noBody(c.DeleteSubnet(r.Context(), r.PathValue("org"), r.PathValue("subnetId")))
+	})
+
+	handle("POST "+basePath+"/ipblock", withBody(
+		func(r *http.Request, req nico.IpBlockCreateRequest) (any, *http.Response, error) {
+			return c.CreateIpblock(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("GET "+basePath+"/ipblock", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetAllIpblock(r.Context(), r.PathValue("org"), r.URL.Query().Get("siteId"))
+	})
+	handle("GET "+basePath+"/ipblock/{ipBlockId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetIpblock(r.Context(), r.PathValue("org"), r.PathValue("ipBlockId"))
+	})
+	handle("DELETE "+basePath+"/ipblock/{ipBlockId}", func(r *http.Request) (any, *http.Response, error) {
+		return noBody(c.DeleteIpblock(r.Context(), r.PathValue("org"), r.PathValue("ipBlockId")))
+	})
+
+	handle("POST "+basePath+"/network-security-group", withBody(
+		func(r *http.Request, req nico.NetworkSecurityGroupCreateRequest) (any, *http.Response, error) {
+			return c.CreateNetworkSecurityGroup(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("GET "+basePath+"/network-security-group/{nsgId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetNetworkSecurityGroup(r.Context(), r.PathValue("org"), r.PathValue("nsgId"))
+	})
+	handle("DELETE "+basePath+"/network-security-group/{nsgId}", func(r *http.Request) (any, *http.Response, error) {
+		return noBody(c.DeleteNetworkSecurityGroup(r.Context(), r.PathValue("org"), r.PathValue("nsgId")))
+	})
+
+	handle("POST "+basePath+"/allocation", withBody(
+		func(r *http.Request, req nico.AllocationCreateRequest) (any, *http.Response, error) {
+			return c.CreateAllocation(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("GET "+basePath+"/allocation", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetAllAllocation(r.Context(), r.PathValue("org"))
+	})
+	handle("GET "+basePath+"/allocation/{allocationId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetAllocation(r.Context(), r.PathValue("org"), r.PathValue("allocationId"))
+	})
+	handle("DELETE "+basePath+"/allocation/{allocationId}", func(r *http.Request) (any, *http.Response, error) {
+		return noBody(c.DeleteAllocation(r.Context(), r.PathValue("org"), r.PathValue("allocationId")))
+	})
+
+	handle("POST "+basePath+"/vpc-prefix", withBody(
+		func(r *http.Request, req nico.VpcPrefixCreateRequest) (any, *http.Response, error) {
+			return c.CreateVpcPrefix(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("GET "+basePath+"/vpc-prefix/{vpcPrefixId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetVpcPrefix(r.Context(), r.PathValue("org"), r.PathValue("vpcPrefixId"))
+	})
+	handle("DELETE "+basePath+"/vpc-prefix/{vpcPrefixId}", func(r *http.Request) (any, *http.Response, error) {
+		return noBody(c.DeleteVpcPrefix(r.Context(), r.PathValue("org"), r.PathValue("vpcPrefixId")))
+	})
+
+	handle("POST "+basePath+"/vpc-peering", withBody(
+		func(r *http.Request, req nico.VpcPeeringCreateRequest) (any, *http.Response, error) {
+			return c.CreateVpcPeering(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("GET "+basePath+"/vpc-peering/{id}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetVpcPeering(r.Context(), r.PathValue("org"), r.PathValue("id"))
+	})
+	handle("DELETE "+basePath+"/vpc-peering/{id}", func(r *http.Request) (any, *http.Response, error) {
+		return noBody(c.DeleteVpcPeering(r.Context(), r.PathValue("org"), r.PathValue("id")))
+	})
+
+	handle("POST "+basePath+"/instance", withBody(
+		func(r *http.Request, req nico.InstanceCreateRequest) (any, *http.Response, error) {
+			return c.CreateInstance(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("POST "+basePath+"/instance/batch", withBody(
+		func(r *http.Request, req nico.BatchInstanceCreateRequest) (any, *http.Response, error) {
+			return c.BatchCreateInstance(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("GET "+basePath+"/instance", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetAllInstance(r.Context(), r.PathValue("org"))
+	})
+	handle("GET "+basePath+"/instance/{instanceId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetInstance(r.Context(), r.PathValue("org"), r.PathValue("instanceId"))
+	})
+	// The status history shares its route shape with the instance types
+	handle("GET "+basePath+"/instance/{instanceId}/{resource}", func(r *http.Request) (any, *http.Response, error) {
+		if r.PathValue("resource") != "status-history" {
+			resp, err := apiError(http.StatusNotFound, "no route for %s", r.URL.Path)
+			return nil, resp, err
+		}
+		return c.GetInstanceStatusHistory(r.Context(), r.PathValue("org"), r.PathValue("instanceId"))
+	})
+	handle("PATCH "+basePath+"/instance/{instanceId}", withBody(
+		func(r *http.Request, req nico.InstanceUpdateRequest) (any, *http.Response, error) {
+			return c.UpdateInstance(r.Context(), r.PathValue("org"), r.PathValue("instanceId"), req)
+		}))
+	handle("DELETE "+basePath+"/instance/{instanceId}", func(r *http.Request) (any, *http.Response, error) {
+		// The delete request is optional
+		var req *nico.InstanceDeleteRequest
+		if body, err := io.ReadAll(r.Body); err == nil && len(body) > 0 {
+			req = &nico.InstanceDeleteRequest{}
+			if err := json.Unmarshal(body, req); err != nil {
+				resp, err := apiError(http.StatusBadRequest, "invalid request body: %v", err)
+				return nil, resp, err
+			}
+		}
+		return noBody(c.DeleteInstance(r.Context(), r.PathValue("org"), r.PathValue("instanceId"), req))
+	})
+
+	handle("GET "+basePath+"/instance/type", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetAllInstanceType(r.Context(), r.PathValue("org"), r.URL.Query().Get("siteId"))
+	})
+	handle("GET "+basePath+"/instance/type/{instanceTypeId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetInstanceType(r.Context(), r.PathValue("org"), r.PathValue("instanceTypeId"))
+	})
+
+	// Machines are only listed by the provider to find available ones
+	handle("GET "+basePath+"/machine", func(r *http.Request) (any, *http.Response, error) {
+		query := r.URL.Query()
+		return c.GetAllAvailableMachine(r.Context(), r.PathValue("org"), query.Get("siteId"), query.Get("instanceTypeId"))
+	})
+	handle("GET "+basePath+"/machine/{machineId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetMachine(r.Context(), r.PathValue("org"), r.PathValue("machineId"))
+	})
+
+	handle("GET "+basePath+"/tray", func(r *http.Request) (any, *http.Response, error) {
+		query := r.URL.Query()
+		return c.GetAllTray(r.Context(), r.PathValue("org"), query.Get("siteId"), query.Get("type"), query.Get("componentId"))
+	})
+	handle("PATCH "+basePath+"/tray/{id}/power", withBody(
+		func(r *http.Request, req nico.UpdatePowerStateRequest) (any, *http.Response, error) {
+			return c.PowerControlTray(r.Context(), r.PathValue("org"), r.PathValue("id"), req)
+		}))
+	handle("GET "+basePath+"/health/events", func(r *http.Request) (any, *http.Response, error) {
+		query := r.URL.Query()
+		return c.ListFaultEvents(r.Context(), r.PathValue("org"),
+			query.Get("machineId"), query.Get("state"), query.Get("severity"))
+	})
+
+	handle("POST "+basePath+"/sshkeygroup", withBody(
+		func(r *http.Request, req nico.SshKeyGroupCreateRequest) (any, *http.Response, error) {
+			return c.CreateSshKeyGroup(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("GET "+basePath+"/sshkeygroup/{sshKeyGroupId}", func(r *http.Request) (any, *http.Response, error) {
+		return c.GetSshKeyGroup(r.Context(), r.PathValue("org"), r.PathValue("sshKeyGroupId"))
+	})
+	handle("PATCH "+basePath+"/sshkeygroup/{sshKeyGroupId}", withBody(
+		func(r *http.Request, req nico.SshKeyGroupUpdateRequest) (any, *http.Response, error) {
+			return c.UpdateSshKeyGroup(r.Context(), r.PathValue("org"), r.PathValue("sshKeyGroupId"), req)
+		}))
+	handle("DELETE "+basePath+"/sshkeygroup/{sshKeyGroupId}", func(r *http.Request) (any, *http.Response, error) {
+		return noBody(c.DeleteSshKeyGroup(r.Context(), r.PathValue("org"), r.PathValue("sshKeyGroupId")))
+	})
+	handle("POST "+basePath+"/sshkey", withBody(
+		func(r *http.Request, req nico.SshKeyCreateRequest) (any, *http.Response, error) {
+			return c.CreateSshKey(r.Context(), r.PathValue("org"), req)
+		}))
+	handle("DELETE "+basePath+"/sshkey/{sshKeyId}", func(r *http.Request) (any, *http.Response, error) {
+		return noBody(c.DeleteSshKey(r.Context(), r.PathValue("org"), r.PathValue("sshKeyId")))
+	})
+
+	return mux
+}
+
+// NewServer starts a TLS server of the NVIDIA Carbide REST API backed by a
+// simulated client. The caller closes it.
+func NewServer(c *Client) *httptest.Server {
+	return httptest.NewTLSServer(NewHandler(c))
+}
+
+// CredentialsData returns the data of a credentials secret pointing at a
+// server of NewServer, trusting its certificate.
+func CredentialsData(server *httptest.Server, orgName string) map[string][]byte {
+	return map[string][]byte{
+		"endpoint": []byte(server.URL),
+		"orgName":  []byte(orgName),
+		"token":    []byte("simulation"),
+		"caBundle": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+	}
+}
+
+// withBody decodes the JSON body of the request before serving the call.
+func withBody[T any](call func(r *http.Request, req T) (any, *http.Response, error)) endpoint {
+	return func(r *http.Request) (any, *http.Response, error) {
+		var req T
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp, err := apiError(http.StatusBadRequest, "invalid request body: %v", err)
+			return nil, resp, err
+		}
+		return call(r, req)
+	}
+}
+
+// noBody adapts the calls without response body.
+func noBody(resp *http.Response, err error) (any, *http.Response, error) {
+	return nil, resp, err
+}
+
+// writeResponse writes the response of a call, and its error as a NVIDIA
+// Carbide API error.
+func writeResponse(w http.ResponseWriter, body any, resp *http.Response, err error) {
+	code := http.StatusOK
+	if resp != nil {
+		code = resp.StatusCode
+		for key, values := range resp.Header {
+			w.Header()[key] = values
+		}
+	}
+	if err != nil {
+		if code < http.StatusBadRequest {
+			code = http.StatusInternalServerError
+		}
+		body = nico.CarbideAPIError{Source: nico.PtrString("carbide"), Message: nico.PtrString(err.Error())}
+	}
+	if body == nil || code == http.StatusNoContent {
+		w.WriteHeader(code)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrastructurev1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// newServerClient serves a simulator over HTTPS and returns the SDK client
+// built from a credentials secret pointing at it.
+func newServerClient(t *testing.T, c *Client) scope.NcxInfraClientInterface {
+	t.Helper()
+	server := NewServer(c)
+	t.Cleanup(server.Close)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ncx-infra-creds", Namespace: "default"},
+		Data:       CredentialsData(server, "org"),
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+	auth := infrastructurev1.AuthenticationSpec{SecretRef: corev1.SecretReference{Name: secret.Name}}
+	api, orgName, err := scope.NewClientFromSecret(context.Background(), reader, auth, "default",
+		corev1.SecretReference{}, infrastructurev1.SiteReference{})
+	if err != nil {
+		t.Fatalf("NewClientFromSecret: %v", err)
+	}
+	if orgName != "org" {
+		t.Fatalf("expected organization org, got %s", orgName)
+	}
+	return api
+}
+
+func TestServerNetworkLifecycle(t *testing.T) {
+	c, now := newTestClient()
+	api := newServerClient(t, c)
+	ctx := context.Background()
+
+	vpcID, subnetID := newSubnet(t, api)
+	vpc, httpResp, err := api.GetVpc(ctx, "org", vpcID)
+	if err != nil || httpResp.StatusCode != http.StatusOK {
+		t.Fatalf("GetVpc: %v (%v)", err, httpResp)
+	}
+	if vpc.GetStatus() != nico.VPCSTATUS_PENDING {
+		t.Errorf("expected Pending, got %s", vpc.GetStatus())
+	}
+
+	// The state is shared with the in-memory client
+	*now = now.Add(c.opts.NetworkProvisioningTime)
+	if vpc, _, _ := c.GetVpc(ctx, "org", vpcID); vpc.GetStatus() != nico.VPCSTATUS_READY {
+		t.Errorf("expected the simulator to see a Ready VPC, got %s", vpc.GetStatus())
+	}
+	subnet, _, err := api.GetSubnet(ctx, "org", subnetID)
+	if err != nil || subnet.GetStatus() != nico.SUBNETSTATUS_READY {
+		t.Errorf("GetSubnet: expected Ready, got %s (%v)", subnet.GetStatus(), err)
+	}
+
+	nsg, httpResp, err := api.CreateNetworkSecurityGroup(ctx, "org", nico.NetworkSecurityGroupCreateRequest{
+		Name: "nsg", SiteId: "site-1",
+	})
+	if err != nil || httpResp.StatusCode != http.StatusCreated {
+		t.Fatalf("CreateNetworkSecurityGroup: %v (%v)", err, httpResp)
+	}
+	if _, err := api.DeleteNetworkSecurityGroup(ctx, "org", nsg.GetId()); err != nil {
+		t.Fatalf("DeleteNetworkSecurityGroup: %v", err)
+	}
+	_, httpResp, err = api.GetNetworkSecurityGroup(ctx, "org", nsg.GetId())
+	if apiErr := scope.ClassifyAPIError(httpResp, err, "GetNetworkSecurityGroup"); !apiErr.IsNotFound() {
+		t.Errorf("expected a deleted NSG to be not found, got %v", apiErr)
+	}
+}
+
+func TestServerInstanceLifecycle(t *testing.T) {
+	c, now := newTestClient()
+	api := newServerClient(t, c)
+	ctx := context.Background()
+	vpcID, subnetID := newSubnet(t, api)
+
+	instance, httpResp, err := api.CreateInstance(ctx, "org", nico.InstanceCreateRequest{
+		Name: "worker-0", TenantId: "tenant", VpcId: vpcID, InstanceTypeId: nico.PtrString("gpu-large"),
+		Interfaces: []nico.InterfaceCreateRequest{{SubnetId: &subnetID}},
+	})
+	if err != nil || httpResp.StatusCode != http.StatusCreated {
+		t.Fatalf("CreateInstance: %v (%v)", err, httpResp)
+	}
+
+	*now = now.Add(c.opts.InstanceProvisioningTime)
+	instance, _, err = api.GetInstance(ctx, "org", instance.GetId())
+	if err != nil || instance.GetStatus() != nico.INSTANCESTATUS_READY {
+		t.Fatalf("GetInstance: expected Ready, got %s (%v)", instance.GetStatus(), err)
+	}
+	history, _, err := api.GetInstanceStatusHistory(ctx, "org", instance.GetId())
+	if err != nil || len(history) == 0 {
+		t.Errorf("GetInstanceStatusHistory: expected the transitions, got %v (%v)", history, err)
+	}
+	machine, _, err := api.GetMachine(ctx, "org", *instance.MachineId.Get())
+	if err != nil || machine.GetInstanceId() != instance.GetId() {
+		t.Errorf("GetMachine: expected the machine of the instance, got %v (%v)", machine, err)
+	}
+	if _, _, err := api.GetInstanceType(ctx, "org", "gpu-large"); err != nil {
+		t.Errorf("GetInstanceType: %v", err)
+	}
+
+	httpResp, err = api.DeleteInstance(ctx, "org", instance.GetId(), &nico.InstanceDeleteRequest{
+		MachineHealthIssue: &nico.MachineHealthIssue{Summary: nico.PtrString("GPU fell off the bus")},
+	})
+	if err != nil {
+		t.Fatalf("DeleteInstance: %v (%v)", err, httpResp)
+	}
+	if rec := c.instances[instance.GetId()]; rec.terminated.IsZero() || rec.healthIssue != "GPU fell off the bus" {
+		t.Errorf("expected the instance to terminate with the health issue, got %q", rec.healthIssue)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	c, _ := newTestClient()
+	api := newServerClient(t, c)
+	ctx := context.Background()
+
+	_, httpResp, err := api.GetVpc(ctx, "org", "missing")
+	if apiErr := scope.ClassifyAPIError(httpResp, err, "GetVpc"); !apiErr.IsNotFound() {
+		t.Errorf("expected a missing VPC to be not found, got %v", apiErr)
+	}
+
+	_, httpResp, err = api.CreateInstance(ctx, "org", nico.InstanceCreateRequest{
+		Name: "worker-0", TenantId: "tenant", VpcId: "missing", InstanceTypeId: nico.PtrString("gpu-large"),
+	})
+	if httpResp == nil || httpResp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected a bad request, got %v (%v)", httpResp, err)
+	}
+	var openAPIErr *nico.GenericOpenAPIError
+	if !errors.As(err, &openAPIErr) {
+		t.Fatalf("expected an SDK error, got %v", err)
+	}
+	if model, ok := openAPIErr.Model().(nico.CarbideAPIError); !ok ||
+		!strings.Contains(model.GetMessage(), "VPC missing not found") {
+		t.Errorf("expected the message of the simulator in the error, got %s", openAPIErr.Body())
+	}
+
+	server := NewServer(c)
+	defer server.Close()
+	resp, err := server.Client().Post(server.URL+"/v2/org/org/carbide/vpc", "application/json", strings.NewReader("{"))
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected an invalid body to be a bad request, got %d", resp.StatusCode)
+	}
+}
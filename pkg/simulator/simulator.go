@@ -0,0 +1,1807 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulator provides an in-memory NVIDIA Carbide API used by the
+// manager simulation mode. Resources go through the same state transitions as
+// on a real site, driven by the wall clock, so a full cluster lifecycle can be
+// demonstrated without hardware. NewServer serves it over HTTP to the SDK
+// client, for the integration tests.
+package simulator
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+	"github.com/google/uuid"
+
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// Options configures the simulated latencies and pool size.
+type Options struct {
+	// APILatency is the average latency of an API call, with up to 50% jitter.
+	APILatency time.Duration
+	// NetworkProvisioningTime is the time for VPCs, subnets and other network resources to become Ready.
+	NetworkProvisioningTime time.Duration
+	// InstanceProvisioningTime is the time for an instance to go from Pending to Ready.
+	InstanceProvisioningTime time.Duration
+	// InstanceRebootTime is the time an instance stays Rebooting.
+	InstanceRebootTime time.Duration
+	// InstanceTerminationTime is the time an instance stays Terminating before it disappears.
+	InstanceTerminationTime time.Duration
+	// MachineResetTime is the time a released machine stays in Reset while its disks are wiped.
+	MachineResetTime time.Duration
+	// MachinesPerInstanceType is the number of machines created for each instance type.
+	MachinesPerInstanceType int
+	// MachinesPerChassis is the number of machines sharing a chassis.
+	MachinesPerChassis int
+	// Sites are the names of the sites returned when listing sites.
+	Sites []string
+}
+
+// DefaultOptions returns latencies in the range observed on real sites, shortened
+// so that a cluster comes up in a few minutes.
+func DefaultOptions() Options {
+	return Options{
+		APILatency:               100 * time.Millisecond,
+		NetworkProvisioningTime:  10 * time.Second,
+		InstanceProvisioningTime: 3 * time.Minute,
+		InstanceRebootTime:       45 * time.Second,
+		InstanceTerminationTime:  30 * time.Second,
+		MachineResetTime:         time.Minute,
+		MachinesPerInstanceType:  8,
+		MachinesPerChassis:       2,
+		Sites:                    []string{"simulation"},
+	}
+}
+
+// transition is a status change scheduled at a point in time.
+type transition struct {
+	status  string
+	message string
+	at      time.Time
+}
+
+// timeline is the ordered list of status changes of a resource.
+type timeline []transition
+
+// status returns the status in effect at the given time.
+func (t timeline) status(now time.Time) string {
+	current := ""
+	for _, tr := range t {
+		if tr.at.After(now) {
+			break
+		}
+		current = tr.status
+	}
+	return current
+}
+
+// history returns the status changes that already happened, most recent first.
+func (t timeline) history(now time.Time) []nico.StatusDetail {
+	var details []nico.StatusDetail
+	for _, tr := range t {
+		if tr.at.After(now) {
+			break
+		}
+		details = append([]nico.StatusDetail{{
+			Status:  nico.PtrString(tr.status),
+			Message: nico.PtrString(tr.message),
+			Created: nico.PtrTime(tr.at),
+			Updated: nico.PtrTime(tr.at),
+		}}, details...)
+	}
+	return details
+}
+
+// schedule drops the transitions after now and appends new ones.
+func (t timeline) schedule(now time.Time, next ...transition) timeline {
+	kept := timeline{}
+	for _, tr := range t {
+		if !tr.at.After(now) {
+			kept = append(kept, tr)
+		}
+	}
+	return append(kept, next...)
+}
+
+// provisioning returns the Pending, Provisioning and Ready transitions of a network resource.
+func provisioning(now time.Time, d time.Duration) timeline {
+	return timeline{
+		{status: "Pending", message: "Request received", at: now},
+		{status: "Provisioning", message: "Provisioning on site", at: now.Add(d / 2)},
+		{status: "Ready", message: "Ready for use", at: now.Add(d)},
+	}
+}
+
+type instanceRecord struct {
+	instance   nico.Instance
+	timeline   timeline
+	terminated time.Time
+	// healthIssue is the summary of the health issue reported on deletion
+	healthIssue string
+}
+
+type machineRecord struct {
+	machine  nico.Machine
+	timeline timeline
+	trayID   string
+}
+
+type subnetRecord struct {
+	subnet   nico.Subnet
+	timeline timeline
+	nextHost uint32
+}
+
+type ipBlockRecord struct {
+	ipBlock    nico.IpBlock
+	timeline   timeline
+	nextOffset uint32
+}
+
+// Client is an in-memory implementation of the NVIDIA Carbide API client.
+type Client struct {
+	opts Options
+	now  func() time.Time
+
+	mu          sync.Mutex
+	sites       map[string]*nico.Site
+	vpcs        map[string]*nico.VPC
+	vpcStatus   map[string]timeline
+	subnets     map[string]*subnetRecord
+	ipBlocks    map[string]*ipBlockRecord
+	nsgs        map[string]*nico.NetworkSecurityGroup
+	allocations map[string]*nico.Allocation
+	prefixes    map[string]*nico.VpcPrefix
+	peerings    map[string]*nico.VpcPeering
+	sshKeys     map[string]*nico.SshKey
+	keyGroups   map[string]*nico.SshKeyGroup
+	instances   map[string]*instanceRecord
+	machines    map[string]*machineRecord
+	trays       map[string]*nico.Tray
+	pools       map[string][]string
+}
+
+var _ scope.NcxInfraClientInterface = &Client{}
+
+// New returns an empty simulated NVIDIA Carbide API.
+func New(opts Options) *Client {
+	return &Client{
+		opts:        opts,
+		now:         time.Now,
+		sites:       map[string]*nico.Site{},
+		vpcs:        map[string]*nico.VPC{},
+		vpcStatus:   map[string]timeline{},
+		subnets:     map[string]*subnetRecord{},
+		ipBlocks:    map[string]*ipBlockRecord{},
+		nsgs:        map[string]*nico.NetworkSecurityGroup{},
+		allocations: map[string]*nico.Allocation{},
+		prefixes:    map[string]*nico.VpcPrefix{},
+		peerings:    map[string]*nico.VpcPeering{},
+		sshKeys:     map[string]*nico.SshKey{},
+		keyGroups:   map[string]*nico.SshKeyGroup{},
+		instances:   map[string]*instanceRecord{},
+		machines:    map[string]*machineRecord{},
+		trays:       map[string]*nico.Tray{},
+		pools:       map[string][]string{},
+	}
+}
+
+// call waits for the simulated API latency, then locks the state and applies
+// the transitions that completed in the meantime.
+func (c *Client) call(ctx context.Context) (time.Time, error) {
+	if c.opts.APILatency > 0 {
+		latency := c.opts.APILatency/2 + rand.N(c.opts.APILatency) //nolint:gosec // simulated jitter
+		select {
+		case <-ctx.Done():
+			return time.Time{}, ctx.Err()
+		case <-time.After(latency):
+		}
+	}
+	c.mu.Lock()
+	now := c.now()
+	c.reapInstances(now)
+	return now, nil
+}
+
+// reapInstances removes the terminated instances and sends their machines through a reset.
+func (c *Client) reapInstances(now time.Time) {
+	for id, rec := range c.instances {
+		if rec.terminated.IsZero() || rec.terminated.After(now) {
+			continue
+		}
+		delete(c.instances, id)
+		if machineID := rec.instance.MachineId.Get(); machineID != nil {
+			if m, ok := c.machines[*machineID]; ok {
+				m.machine.InstanceId.Unset()
+				if rec.healthIssue != "" {
+					// Reported machines are sent to repair instead of the pool
+					m.timeline = m.timeline.schedule(rec.terminated,
+						transition{status: "Maintenance", message: rec.healthIssue, at: rec.terminated})
+					continue
+				}
+				m.timeline = m.timeline.schedule(rec.terminated,
+					transition{status: "Reset", message: "Wiping local disks", at: rec.terminated},
+					transition{status: "Ready", message: "Machine available", at: rec.terminated.Add(c.opts.MachineResetTime)})
+			}
+		}
+	}
+}
+
+func response(code int) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Header:     make(http.Header),
+	}
+}
+
+func apiError(code int, format string, args ...interface{}) (*http.Response, error) {
+	return response(code), fmt.Errorf("%d %s: %s", code, http.StatusText(code), fmt.Sprintf(format, args...))
+}
+
+func notFound(kind, id string) (*http.Response, error) {
+	return apiError(http.StatusNotFound, "%s %s not found", kind, id)
+}
+
+// site returns the site with the given ID, registering it on first use so any
+// site referenced by ID is available.
+func (c *Client) site(id, name string) *nico.Site {
+	if s, ok := c.sites[id]; ok {
+		return s
+	}
+	if name == "" {
+		name = id
+	}
+	s := &nico.Site{
+		Id:       nico.PtrString(id),
+		Name:     nico.PtrString(name),
+		IsOnline: nico.PtrBool(true),
+		Status:   nico.SITESTATUS_REGISTERED.Ptr(),
+		Capabilities: &nico.SiteCapabilities{
+			NativeNetworking:     nico.PtrBool(true),
+			NetworkSecurityGroup: nico.PtrBool(true),
+			NvLinkPartition:      nico.PtrBool(true),
+			FaultManagement:      nico.PtrBool(false),
+		},
+	}
+	c.sites[id] = s
+	return s
+}
+
+// ensureSites registers the configured sites.
+func (c *Client) ensureSites() {
+	for _, name := range c.opts.Sites {
+		found := false
+		for _, s := range c.sites {
+			if s.GetName() == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.site(uuid.NewSHA1(uuid.NameSpaceOID, []byte("site/"+name)).String(), name)
+		}
+	}
+}
+
+// GetAllSite lists the configured sites and the sites referenced so far.
+func (c *Client) GetAllSite(ctx context.Context, _ string) ([]nico.Site, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	c.ensureSites()
+	sites := make([]nico.Site, 0, len(c.sites))
+	for _, s := range c.sites {
+		sites = append(sites, *s)
+	}
+	sort.Slice(sites, func(i, j int) bool { return sites[i].GetName() < sites[j].GetName() })
+	return sites, response(http.StatusOK), nil
+}
+
+// GetSite returns a site, registering it when it was not seen before.
+func (c *Client) GetSite(ctx context.Context, _ string, siteId string) (*nico.Site, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	s := *c.site(siteId, "")
+	return &s, response(http.StatusOK), nil
+}
+
+// GetCurrentTenant returns a tenant with targeted instance creation enabled.
+func (c *Client) GetCurrentTenant(ctx context.Context, org string) (*nico.Tenant, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	return &nico.Tenant{
+		Id:  nico.PtrString(uuid.NewSHA1(uuid.NameSpaceOID, []byte("tenant/"+org)).String()),
+		Org: nico.PtrString(org),
+		Capabilities: &nico.TenantCapabilities{
+			TargetedInstanceCreation: nico.PtrBool(true),
+			FaultManagement:          nico.PtrBool(false),
+		},
+	}, response(http.StatusOK), nil
+}
+
+// GetSelfQuotas returns no quota, the simulated tenant is not limited.
+func (c *Client) GetSelfQuotas(ctx context.Context, org string) (*nico.QuotaInfo, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	return &nico.QuotaInfo{
+		TenantId: nico.PtrString(uuid.NewSHA1(uuid.NameSpaceOID, []byte("tenant/"+org)).String()),
+		Quotas:   map[string]nico.QuotaLimit{},
+	}, response(http.StatusOK), nil
+}
+
+// CreateVpc creates a VPC that becomes Ready after the network provisioning time.
+func (c *Client) CreateVpc(ctx context.Context, org string, req nico.VpcCreateRequest) (*nico.VPC, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	if req.Name == "" || req.SiteId == "" {
+		httpResp, err := apiError(http.StatusBadRequest, "name and siteId are required")
+		return nil, httpResp, err
+	}
+	c.site(req.SiteId, "")
+	id := uuid.New().String()
+	vpc := &nico.VPC{
+		Id:                        nico.PtrString(id),
+		Name:                      nico.PtrString(req.Name),
+		Description:               req.Description,
+		Org:                       nico.PtrString(org),
+		SiteId:                    nico.PtrString(req.SiteId),
+		NetworkVirtualizationType: req.NetworkVirtualizationType.Get(),
+		NvLinkLogicalPartitionId:  req.NvLinkLogicalPartitionId,
+		RequestedVni:              req.Vni,
+		Labels:                    req.Labels,
+		Created:                   nico.PtrTime(now),
+		Updated:                   nico.PtrTime(now),
+	}
+	c.vpcs[id] = vpc
+	c.vpcStatus[id] = provisioning(now, c.opts.NetworkProvisioningTime)
+	return c.vpcView(id, now), response(http.StatusCreated), nil
+}
+
+func (c *Client) vpcView(id string, now time.Time) *nico.VPC {
+	vpc := *c.vpcs[id]
+	vpc.Status = nico.VpcStatus(c.vpcStatus[id].status(now)).Ptr()
+	vpc.StatusHistory = c.vpcStatus[id].history(now)
+	return &vpc
+}
+
+// GetVpc returns a VPC.
+func (c *Client) GetVpc(ctx context.Context, _ string, vpcId string) (*nico.VPC, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	if _, ok := c.vpcs[vpcId]; !ok {
+		httpResp, err := notFound("VPC", vpcId)
+		return nil, httpResp, err
+	}
+	return c.vpcView(vpcId, now), response(http.StatusOK), nil
+}
+
+// UpdateVpc updates the name, description and labels of a VPC.
+func (c *Client) UpdateVpc(
+	ctx context.Context, _ string, vpcId string, req nico.VpcUpdateRequest,
+) (*nico.VPC, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	vpc, ok := c.vpcs[vpcId]
+	if !ok {
+		httpResp, err := notFound("VPC", vpcId)
+		return nil, httpResp, err
+	}
+	if req.Name != nil {
+		vpc.Name = req.Name
+	}
+	if req.Description != nil {
+		vpc.Description = req.Description
+	}
+	if req.Labels != nil {
+		vpc.Labels = req.Labels
+	}
+	vpc.Updated = nico.PtrTime(now)
+	return c.vpcView(vpcId, now), response(http.StatusOK), nil
+}
+
+// DeleteVpc deletes a VPC once no subnet, prefix or instance uses it.
+func (c *Client) DeleteVpc(ctx context.Context, _ string, vpcId string) (*http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	if _, ok := c.vpcs[vpcId]; !ok {
+		return notFound("VPC", vpcId)
+	}
+	for _, s := range c.subnets {
+		if s.subnet.GetVpcId() == vpcId {
+			return apiError(http.StatusConflict, "VPC %s still has subnet %s", vpcId, s.subnet.GetId())
+		}
+	}
+	for _, p := range c.prefixes {
+		if p.GetVpcId() == vpcId {
+			return apiError(http.StatusConflict, "VPC %s still has prefix %s", vpcId, p.GetId())
+		}
+	}
+	for _, rec := range c.instances {
+		if rec.instance.GetVpcId() == vpcId {
+			return apiError(http.StatusConflict, "VPC %s still has instance %s", vpcId, rec.instance.GetId())
+		}
+	}
+	delete(c.vpcs, vpcId)
+	delete(c.vpcStatus, vpcId)
+	return response(http.StatusNoContent), nil
+}
+
+// CreateIpblock creates an IP block.
+func (c *Client) CreateIpblock(
+	ctx context.Context, _ string, req nico.IpBlockCreateRequest,
+) (*nico.IpBlock, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	if net.ParseIP(req.Prefix).To4() == nil || req.PrefixLength < 8 || req.PrefixLength > 30 {
+		httpResp, err := apiError(http.StatusBadRequest, "invalid IPv4 prefix %s/%d", req.Prefix, req.PrefixLength)
+		return nil, httpResp, err
+	}
+	for _, rec := range c.ipBlocks {
+		if rec.ipBlock.GetName() == req.Name && rec.ipBlock.GetSiteId() == req.SiteId {
+			httpResp, err := apiError(http.StatusConflict, "IP block %s already exists", req.Name)
+			return nil, httpResp, err
+		}
+	}
+	rec := c.newIPBlock(now, req.Name, req.SiteId, req.Prefix, req.PrefixLength, nil)
+	rec.ipBlock.Description = req.Description
+	rec.ipBlock.RoutingType = nico.PtrString(req.RoutingType)
+	return c.ipBlockView(rec, now), response(http.StatusCreated), nil
+}
+
+func (c *Client) newIPBlock(now time.Time, name, siteID, prefix string, length int32, tenantID *string) *ipBlockRecord {
+	id := uuid.New().String()
+	rec := &ipBlockRecord{
+		ipBlock: nico.IpBlock{
+			Id:              nico.PtrString(id),
+			Name:            nico.PtrString(name),
+			SiteId:          nico.PtrString(siteID),
+			TenantId:        *nico.NewNullableString(tenantID),
+			Prefix:          nico.PtrString(prefix),
+			PrefixLength:    nico.PtrInt32(length),
+			ProtocolVersion: nico.PtrString("IPv4"),
+			Created:         nico.PtrTime(now),
+			Updated:         nico.PtrTime(now),
+		},
+		timeline: provisioning(now, c.opts.NetworkProvisioningTime),
+	}
+	c.ipBlocks[id] = rec
+	return rec
+}
+
+func (c *Client) ipBlockView(rec *ipBlockRecord, now time.Time) *nico.IpBlock {
+	ipBlock := rec.ipBlock
+	ipBlock.Status = nico.IpBlockStatus(rec.timeline.status(now)).Ptr()
+	ipBlock.StatusHistory = rec.timeline.history(now)
+	return &ipBlock
+}
+
+// carve reserves the next prefix of the given length in an IP block.
+func (rec *ipBlockRecord) carve(length int32) (string, error) {
+	blockLength := rec.ipBlock.GetPrefixLength()
+	if length < blockLength || length > 30 {
+		return "", fmt.Errorf("prefix length /%d does not fit in IP block /%d", length, blockLength)
+	}
+	size := uint32(1) << (32 - length)
+	offset := (rec.nextOffset + size - 1) / size * size
+	if uint64(offset)+uint64(size) > uint64(1)<<(32-blockLength) {
+		return "", fmt.Errorf("IP block %s is exhausted", rec.ipBlock.GetId())
+	}
+	rec.nextOffset = offset + size
+	return addIP(rec.ipBlock.GetPrefix(), offset), nil
+}
+
+func addIP(base string, offset uint32) string {
+	ip := net.ParseIP(base).To4()
+	out := make(net.IP, 4)
+	binary.BigEndian.PutUint32(out, binary.BigEndian.Uint32(ip)+offset)
+	return out.String()
+}
+
+// GetIpblock returns an IP block.
+func (c *Client) GetIpblock(ctx context.Context, _ string, ipBlockId string) (*nico.IpBlock, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	rec, ok := c.ipBlocks[ipBlockId]
+	if !ok {
+		httpResp, err := notFound("IP block", ipBlockId)
+		return nil, httpResp, err
+	}
+	return c.ipBlockView(rec, now), response(http.StatusOK), nil
+}
+
+// GetAllIpblock lists the IP blocks of a site.
+func (c *Client) GetAllIpblock(ctx context.Context, _ string, siteId string) ([]nico.IpBlock, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	ipBlocks := make([]nico.IpBlock, 0, len(c.ipBlocks))
+	for _, rec := range c.ipBlocks {
+		if siteId == "" || rec.ipBlock.GetSiteId() == siteId {
+			ipBlocks = append(ipBlocks, *c.ipBlockView(rec, now))
+		}
+	}
+	return ipBlocks, response(http.StatusOK), nil
+}
+
+// DeleteIpblock deletes an IP block, refusing while subnets or VPC prefixes
+// are carved from it or allocations derive child IP blocks from it.
+func (c *Client) DeleteIpblock(ctx context.Context, _ string, ipBlockId string) (*http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	if _, ok := c.ipBlocks[ipBlockId]; !ok {
+		return notFound("IP block", ipBlockId)
+	}
+	for _, s := range c.subnets {
+		if s.subnet.GetIpv4BlockId() == ipBlockId {
+			return apiError(http.StatusConflict, "IP block %s still has subnet %s", ipBlockId, s.subnet.GetId())
+		}
+	}
+	for _, p := range c.prefixes {
+		if p.GetIpBlockId() == ipBlockId {
+			return apiError(http.StatusConflict, "IP block %s still has prefix %s", ipBlockId, p.GetId())
+		}
+	}
+	for _, a := range c.allocations {
+		for _, ac := range a.AllocationConstraints {
+			if ac.GetResourceTypeId() == ipBlockId {
+				return apiError(http.StatusConflict, "IP block %s still has allocation %s", ipBlockId, a.GetId())
+			}
+		}
+	}
+	delete(c.ipBlocks, ipBlockId)
+	return response(http.StatusNoContent), nil
+}
+
+// CreateAllocation allocates child IP blocks of the requested size to the tenant.
+func (c *Client) CreateAllocation(
+	ctx context.Context, _ string, req nico.AllocationCreateRequest,
+) (*nico.Allocation, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	for _, a := range c.allocations {
+		if a.GetName() == req.Name && a.GetTenantId() == req.TenantId {
+			httpResp, err := apiError(http.StatusConflict, "allocation %s already exists", req.Name)
+			return nil, httpResp, err
+		}
+	}
+
+	id := uuid.New().String()
+	alloc := &nico.Allocation{
+		Id:          nico.PtrString(id),
+		Name:        nico.PtrString(req.Name),
+		Description: req.Description,
+		TenantId:    nico.PtrString(req.TenantId),
+		SiteId:      nico.PtrString(req.SiteId),
+		Status:      nico.ALLOCATIONSTATUS_READY.Ptr(),
+		Created:     nico.PtrTime(now),
+		Updated:     nico.PtrTime(now),
+	}
+	for _, constraint := range req.AllocationConstraints {
+		ac := nico.AllocationConstraint{
+			Id:              nico.PtrString(uuid.New().String()),
+			AllocationId:    nico.PtrString(id),
+			ResourceType:    constraint.ResourceType,
+			ResourceTypeId:  nico.PtrString(constraint.ResourceTypeId),
+			ConstraintType:  nico.PtrString(constraint.ConstraintType),
+			ConstraintValue: nico.PtrInt32(constraint.ConstraintValue),
+		}
+		if constraint.ResourceType != nil && *constraint.ResourceType == "IPBlock" {
+			parent, ok := c.ipBlocks[constraint.ResourceTypeId]
+			if !ok {
+				httpResp, err := apiError(http.StatusBadRequest, "IP block %s not found", constraint.ResourceTypeId)
+				return nil, httpResp, err
+			}
+			prefix, err := parent.carve(constraint.ConstraintValue)
+			if err != nil {
+				httpResp, err := apiError(http.StatusBadRequest, "%v", err)
+				return nil, httpResp, err
+			}
+			child := c.newIPBlock(now, req.Name, req.SiteId, prefix, constraint.ConstraintValue,
+				nico.PtrString(req.TenantId))
+			ac.DerivedResourceId = *nico.NewNullableString(child.ipBlock.Id)
+		}
+		alloc.AllocationConstraints = append(alloc.AllocationConstraints, ac)
+	}
+	c.allocations[id] = alloc
+
+	out := *alloc
+	return &out, response(http.StatusCreated), nil
+}
+
+// GetAllocation returns an allocation.
+func (c *Client) GetAllocation(
+	ctx context.Context, _ string, allocationId string,
+) (*nico.Allocation, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	alloc, ok := c.allocations[allocationId]
+	if !ok {
+		httpResp, err := notFound("allocation", allocationId)
+		return nil, httpResp, err
+	}
+	out := *alloc
+	return &out, response(http.StatusOK), nil
+}
+
+// GetAllAllocation lists the allocations.
+func (c *Client) GetAllAllocation(ctx context.Context, _ string) ([]nico.Allocation, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	allocations := make([]nico.Allocation, 0, len(c.allocations))
+	for _, a := range c.allocations {
+		allocations = append(allocations, *a)
+	}
+	return allocations, response(http.StatusOK), nil
+}
+
+// DeleteAllocation deletes an allocation and the child IP blocks it derived.
+func (c *Client) DeleteAllocation(ctx context.Context, _ string, allocationId string) (*http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	alloc, ok := c.allocations[allocationId]
+	if !ok {
+		return notFound("allocation", allocationId)
+	}
+	for _, ac := range alloc.AllocationConstraints {
+		if derived := ac.DerivedResourceId.Get(); derived != nil {
+			delete(c.ipBlocks, *derived)
+		}
+	}
+	delete(c.allocations, allocationId)
+	return response(http.StatusNoContent), nil
+}
+
+// CreateSubnet carves a subnet out of its IPv4 block.
+func (c *Client) CreateSubnet(
+	ctx context.Context, _ string, req nico.SubnetCreateRequest,
+) (*nico.Subnet, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	vpc, ok := c.vpcs[req.VpcId]
+	if !ok {
+		httpResp, err := apiError(http.StatusBadRequest, "VPC %s not found", req.VpcId)
+		return nil, httpResp, err
+	}
+	if req.Ipv4BlockId == nil {
+		httpResp, err := apiError(http.StatusBadRequest, "ipv4BlockId is required")
+		return nil, httpResp, err
+	}
+	block, ok := c.ipBlocks[*req.Ipv4BlockId]
+	if !ok {
+		httpResp, err := apiError(http.StatusBadRequest, "IP block %s not found", *req.Ipv4BlockId)
+		return nil, httpResp, err
+	}
+	prefix, err := block.carve(req.PrefixLength)
+	if err != nil {
+		httpResp, err := apiError(http.StatusBadRequest, "%v", err)
+		return nil, httpResp, err
+	}
+
+	id := uuid.New().String()
+	gateway := addIP(prefix, 1)
+	rec := &subnetRecord{
+		subnet: nico.Subnet{
+			Id:           nico.PtrString(id),
+			Name:         nico.PtrString(req.Name),
+			Description:  req.Description,
+			SiteId:       vpc.SiteId,
+			VpcId:        nico.PtrString(req.VpcId),
+			Ipv4Prefix:   *nico.NewNullableString(&prefix),
+			Ipv4BlockId:  *nico.NewNullableString(req.Ipv4BlockId),
+			Ipv4Gateway:  *nico.NewNullableString(&gateway),
+			Mtu:          nico.PtrInt32(9000),
+			PrefixLength: nico.PtrInt32(req.PrefixLength),
+			Created:      nico.PtrTime(now),
+			Updated:      nico.PtrTime(now),
+		},
+		timeline: provisioning(now, c.opts.NetworkProvisioningTime),
+		nextHost: 2,
+	}
+	c.subnets[id] = rec
+	return c.subnetView(rec, now), response(http.StatusCreated), nil
+}
+
+func (c *Client) subnetView(rec *subnetRecord, now time.Time) *nico.Subnet {
+	subnet := rec.subnet
+	subnet.Status = nico.SubnetStatus(rec.timeline.status(now)).Ptr()
+	subnet.StatusHistory = rec.timeline.history(now)
+	return &subnet
+}
+
+// GetSubnet returns a subnet.
+func (c *Client) GetSubnet(ctx context.Context, _ string, subnetId string) (*nico.Subnet, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	rec, ok := c.subnets[subnetId]
+	if !ok {
+		httpResp, err := notFound("subnet", subnetId)
+		return nil, httpResp, err
+	}
+	return c.subnetView(rec, now), response(http.StatusOK), nil
+}
+
+// DeleteSubnet deletes a subnet once no instance is attached to it.
+func (c *Client) DeleteSubnet(ctx context.Context, _ string, subnetId string) (*http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	if _, ok := c.subnets[subnetId]; !ok {
+		return notFound("subnet", subnetId)
+	}
+	for _, rec := range c.instances {
+		for _, iface := range rec.instance.Interfaces {
+			if s := iface.SubnetId.Get(); s != nil && *s == subnetId {
+				return apiError(http.StatusConflict, "subnet %s is used by instance %s", subnetId, rec.instance.GetId())
+			}
+		}
+	}
+	delete(c.subnets, subnetId)
+	return response(http.StatusNoContent), nil
+}
+
+// CreateNetworkSecurityGroup creates a network security group.
+func (c *Client) CreateNetworkSecurityGroup(
+	ctx context.Context, _ string, req nico.NetworkSecurityGroupCreateRequest,
+) (*nico.NetworkSecurityGroup, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	id := uuid.New().String()
+	nsg := &nico.NetworkSecurityGroup{
+		Id:             nico.PtrString(id),
+		Name:           nico.PtrString(req.Name),
+		Description:    req.Description,
+		SiteId:         nico.PtrString(req.SiteId),
+		Status:         nico.NETWORKSECURITYGROUPSTATUS_READY.Ptr(),
+		StatefulEgress: req.StatefulEgress,
+		Rules:          req.Rules,
+		Labels:         req.Labels,
+		Created:        nico.PtrTime(now),
+		Updated:        nico.PtrTime(now),
+	}
+	c.nsgs[id] = nsg
+	out := *nsg
+	return &out, response(http.StatusCreated), nil
+}
+
+// GetNetworkSecurityGroup returns a network security group.
+func (c *Client) GetNetworkSecurityGroup(
+	ctx context.Context, _ string, nsgId string,
+) (*nico.NetworkSecurityGroup, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	nsg, ok := c.nsgs[nsgId]
+	if !ok {
+		httpResp, err := notFound("network security group", nsgId)
+		return nil, httpResp, err
+	}
+	out := *nsg
+	return &out, response(http.StatusOK), nil
+}
+
+// DeleteNetworkSecurityGroup deletes a network security group.
+func (c *Client) DeleteNetworkSecurityGroup(ctx context.Context, _ string, nsgId string) (*http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	if _, ok := c.nsgs[nsgId]; !ok {
+		return notFound("network security group", nsgId)
+	}
+	delete(c.nsgs, nsgId)
+	return response(http.StatusNoContent), nil
+}
+
+// CreateVpcPrefix creates a VPC prefix carved out of its IP block.
+func (c *Client) CreateVpcPrefix(
+	ctx context.Context, _ string, req nico.VpcPrefixCreateRequest,
+) (*nico.VpcPrefix, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	vpc, ok := c.vpcs[req.VpcId]
+	if !ok {
+		httpResp, err := apiError(http.StatusBadRequest, "VPC %s not found", req.VpcId)
+		return nil, httpResp, err
+	}
+	var prefix *string
+	if req.IpBlockId != nil {
+		block, ok := c.ipBlocks[*req.IpBlockId]
+		if !ok {
+			httpResp, err := apiError(http.StatusBadRequest, "IP block %s not found", *req.IpBlockId)
+			return nil, httpResp, err
+		}
+		carved, err := block.carve(req.PrefixLength)
+		if err != nil {
+			httpResp, err := apiError(http.StatusBadRequest, "%v", err)
+			return nil, httpResp, err
+		}
+		prefix = &carved
+	}
+
+	id := uuid.New().String()
+	vpcPrefix := &nico.VpcPrefix{
+		Id:           nico.PtrString(id),
+		Name:         nico.PtrString(req.Name),
+		SiteId:       vpc.SiteId,
+		VpcId:        nico.PtrString(req.VpcId),
+		IpBlockId:    *nico.NewNullableString(req.IpBlockId),
+		Prefix:       *nico.NewNullableString(prefix),
+		PrefixLength: nico.PtrInt32(req.PrefixLength),
+		Status:       nico.VPCPREFIXSTATUS_READY.Ptr(),
+		Created:      nico.PtrTime(now),
+		Updated:      nico.PtrTime(now),
+	}
+	c.prefixes[id] = vpcPrefix
+	out := *vpcPrefix
+	return &out, response(http.StatusCreated), nil
+}
+
+// GetVpcPrefix returns a VPC prefix.
+func (c *Client) GetVpcPrefix(ctx context.Context, _ string, vpcPrefixId string) (*nico.VpcPrefix, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	vpcPrefix, ok := c.prefixes[vpcPrefixId]
+	if !ok {
+		httpResp, err := notFound("VPC prefix", vpcPrefixId)
+		return nil, httpResp, err
+	}
+	out := *vpcPrefix
+	return &out, response(http.StatusOK), nil
+}
+
+// DeleteVpcPrefix deletes a VPC prefix.
+func (c *Client) DeleteVpcPrefix(ctx context.Context, _ string, vpcPrefixId string) (*http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	if _, ok := c.prefixes[vpcPrefixId]; !ok {
+		return notFound("VPC prefix", vpcPrefixId)
+	}
+	delete(c.prefixes, vpcPrefixId)
+	return response(http.StatusNoContent), nil
+}
+
+// CreateVpcPeering peers two VPCs.
+func (c *Client) CreateVpcPeering(
+	ctx context.Context, _ string, req nico.VpcPeeringCreateRequest,
+) (*nico.VpcPeering, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	id := uuid.New().String()
+	peering := &nico.VpcPeering{
+		Id:      nico.PtrString(id),
+		Vpc1Id:  nico.PtrString(req.Vpc1Id),
+		Vpc2Id:  nico.PtrString(req.Vpc2Id),
+		SiteId:  nico.PtrString(req.SiteId),
+		Status:  nico.VPCPEERINGSTATUS_READY.Ptr(),
+		Created: nico.PtrTime(now),
+		Updated: nico.PtrTime(now),
+	}
+	c.peerings[id] = peering
+	out := *peering
+	return &out, response(http.StatusCreated), nil
+}
+
+// GetVpcPeering returns a VPC peering.
+func (c *Client) GetVpcPeering(ctx context.Context, _ string, peeringId string) (*nico.VpcPeering, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	peering, ok := c.peerings[peeringId]
+	if !ok {
+		httpResp, err := notFound("VPC peering", peeringId)
+		return nil, httpResp, err
+	}
+	out := *peering
+	return &out, response(http.StatusOK), nil
+}
+
+// DeleteVpcPeering deletes a VPC peering.
+func (c *Client) DeleteVpcPeering(ctx context.Context, _ string, peeringId string) (*http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	if _, ok := c.peerings[peeringId]; !ok {
+		return notFound("VPC peering", peeringId)
+	}
+	delete(c.peerings, peeringId)
+	return response(http.StatusNoContent), nil
+}
+
+// pool returns the machines of an instance type, creating them on first use.
+// Machines are spread over chassis and racks and carry inventory labels.
+func (c *Client) pool(siteID, instanceTypeID string) []string {
+	key := siteID + "/" + instanceTypeID
+	if ids, ok := c.pools[key]; ok {
+		return ids
+	}
+	perChassis := max(c.opts.MachinesPerChassis, 1)
+	ids := make([]string, 0, c.opts.MachinesPerInstanceType)
+	for i := range c.opts.MachinesPerInstanceType {
+		id := uuid.New().String()
+		chassis := fmt.Sprintf("SIM-%s-CH%02d", instanceTypeID[:min(8, len(instanceTypeID))], i/perChassis)
+		rack := fmt.Sprintf("rack-%02d", i/(perChassis*4))
+		m := &machineRecord{
+			machine: nico.Machine{
+				Id:             nico.PtrString(id),
+				SiteId:         nico.PtrString(siteID),
+				InstanceTypeId: *nico.NewNullableString(nico.PtrString(instanceTypeID)),
+				Vendor:         nico.PtrString("NVIDIA"),
+				ProductName:    nico.PtrString("Simulated Server"),
+				SerialNumber:   nico.PtrString(fmt.Sprintf("SIM%08d", len(c.machines))),
+				Metadata: &nico.MachineMetadata{
+					DmiData: &nico.MachineDMIData{ChassisSerial: nico.PtrString(chassis)},
+					Gpus:    simulatedGPUs(),
+				},
+				MachineCapabilities: simulatedCapabilities(),
+				Labels: map[string]string{
+					"rack":       rack,
+					"datacenter": "simulation",
+					"sku":        instanceTypeID,
+				},
+				IsUsableByTenant: nico.PtrBool(true),
+			},
+			timeline: timeline{{status: "Ready", message: "Machine available", at: time.Time{}}},
+			trayID:   uuid.New().String(),
+		}
+		c.machines[id] = m
+		c.trays[m.trayID] = &nico.Tray{
+			Id:           nico.PtrString(m.trayID),
+			ComponentId:  nico.PtrString(id),
+			Type:         nico.PtrString("compute"),
+			Name:         nico.PtrString(fmt.Sprintf("compute-tray-%d", i)),
+			SerialNumber: m.machine.SerialNumber,
+			PowerState:   nico.PtrString("On"),
+			RackId:       nico.PtrString(rack),
+		}
+		ids = append(ids, id)
+	}
+	c.pools[key] = ids
+	return ids
+}
+
+// simulatedGPUs returns the GPUs of a simulated HGX machine.
+func simulatedGPUs() []nico.MachineGPUInfo {
+	gpus := make([]nico.MachineGPUInfo, 8)
+	for i := range gpus {
+		gpus[i] = nico.MachineGPUInfo{
+			Name:        nico.PtrString("NVIDIA H100 80GB HBM3"),
+			TotalMemory: nico.PtrString("81559 MiB"),
+			PciBusId:    nico.PtrString(fmt.Sprintf("00000000:%02X:00.0", 0x18+i*0x10)),
+		}
+	}
+	return gpus
+}
+
+// simulatedCapabilities returns the hardware capabilities of a simulated HGX machine.
+func simulatedCapabilities() []nico.MachineCapability {
+	capability := func(kind, name, capacity string, count int32) nico.MachineCapability {
+		mc := nico.MachineCapability{
+			Type:  nico.PtrString(kind),
+			Name:  nico.PtrString(name),
+			Count: *nico.NewNullableInt32(nico.PtrInt32(count)),
+		}
+		if capacity != "" {
+			mc.Capacity = *nico.NewNullableString(nico.PtrString(capacity))
+		}
+		return mc
+	}
+	cpu := capability("CPU", "Intel(R) Xeon(R) Platinum 8480+", "", 2)
+	cpu.Cores = *nico.NewNullableInt32(nico.PtrInt32(56))
+	cpu.Threads = *nico.NewNullableInt32(nico.PtrInt32(112))
+	return []nico.MachineCapability{
+		cpu,
+		capability("Memory", "DDR5", "64GB", 32),
+		capability("Storage", "Samsung PM1743 NVMe", "3.84TB", 8),
+		capability("GPU", "NVIDIA H100 80GB HBM3", "80GB", 8),
+		capability("InfiniBand", "MT4129 Family [ConnectX-7]", "", 8),
+	}
+}
+
+// GetInstanceType returns an instance type. Any ID is accepted and describes
+// simulated HGX machines.
+func (c *Client) GetInstanceType(
+	ctx context.Context, _ string, instanceTypeId string,
+) (*nico.InstanceType, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	return simulatedInstanceType(instanceTypeId), response(http.StatusOK), nil
+}
+
+// GetAllInstanceType lists the instance types of a site whose machines were
+// already requested, with their allocation counts.
+func (c *Client) GetAllInstanceType(
+	ctx context.Context, _ string, siteId string,
+) ([]nico.InstanceType, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	var instanceTypes []nico.InstanceType
+	for key, ids := range c.pools {
+		site, instanceTypeID, _ := strings.Cut(key, "/")
+		if site != siteId {
+			continue
+		}
+		var ready int32
+		for _, id := range ids {
+			if c.machines[id].timeline.status(now) == "Ready" {
+				ready++
+			}
+		}
+		total := int32(len(ids))
+		instanceType := simulatedInstanceType(instanceTypeID)
+		instanceType.SiteId = nico.PtrString(siteId)
+		instanceType.AllocationStats = &nico.InstanceTypeAllocationStats{
+			Total:          nico.PtrInt32(total),
+			Used:           nico.PtrInt32(total - ready),
+			Unused:         nico.PtrInt32(ready),
+			UnusedUsable:   nico.PtrInt32(ready),
+			MaxAllocatable: nico.PtrInt32(total),
+		}
+		instanceTypes = append(instanceTypes, *instanceType)
+	}
+	sort.Slice(instanceTypes, func(i, j int) bool {
+		return instanceTypes[i].GetName() < instanceTypes[j].GetName()
+	})
+	return instanceTypes, response(http.StatusOK), nil
+}
+
+// simulatedInstanceType returns an instance type of simulated HGX machines.
+func simulatedInstanceType(id string) *nico.InstanceType {
+	return &nico.InstanceType{
+		Id:                  nico.PtrString(id),
+		Name:                nico.PtrString("sim-" + id[:min(8, len(id))]),
+		Status:              nico.INSTANCETYPESTATUS_READY.Ptr(),
+		MachineCapabilities: simulatedCapabilities(),
+	}
+}
+
+// CreateSshKeyGroup creates an SSH key group, synced to its sites at once.
+func (c *Client) CreateSshKeyGroup(
+	ctx context.Context, org string, req nico.SshKeyGroupCreateRequest,
+) (*nico.SshKeyGroup, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	id := uuid.New().String()
+	group := &nico.SshKeyGroup{
+		Id:      nico.PtrString(id),
+		Name:    nico.PtrString(req.Name),
+		Org:     nico.PtrString(org),
+		Version: nico.PtrString("1"),
+		Status:  nico.SSHKEYGROUPSTATUS_SYNCED.Ptr(),
+		Created: nico.PtrTime(now),
+		Updated: nico.PtrTime(now),
+	}
+	if req.Description != nil {
+		group.Description = *nico.NewNullableString(req.Description)
+	}
+	if httpResp, err := c.setKeyGroupMembers(group, req.SiteIds, req.SshKeyIds); err != nil {
+		return nil, httpResp, err
+	}
+	c.keyGroups[id] = group
+	out := *group
+	return &out, response(http.StatusCreated), nil
+}
+
+// UpdateSshKeyGroup replaces the sites and SSH keys of an SSH key group, when
+// the request carries its current version.
+func (c *Client) UpdateSshKeyGroup(
+	ctx context.Context, _ string, sshKeyGroupId string, req nico.SshKeyGroupUpdateRequest,
+) (*nico.SshKeyGroup, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	group, ok := c.keyGroups[sshKeyGroupId]
+	if !ok {
+		httpResp, err := notFound("SSH key group", sshKeyGroupId)
+		return nil, httpResp, err
+	}
+	if req.Version != group.GetVersion() {
+		httpResp, err := apiError(http.StatusConflict,
+			"SSH key group %s is at version %s, not %s", sshKeyGroupId, group.GetVersion(), req.Version)
+		return nil, httpResp, err
+	}
+	siteIDs, keyIDs := req.SiteIds, req.SshKeyIds
+	if siteIDs == nil {
+		for _, association := range group.SiteAssociations {
+			siteIDs = append(siteIDs, association.Site.GetId())
+		}
+	}
+	if keyIDs == nil {
+		for _, key := range group.SshKeys {
+			keyIDs = append(keyIDs, key.GetId())
+		}
+	}
+	if httpResp, err := c.setKeyGroupMembers(group, siteIDs, keyIDs); err != nil {
+		return nil, httpResp, err
+	}
+	version, _ := strconv.Atoi(group.GetVersion())
+	group.Version = nico.PtrString(strconv.Itoa(version + 1))
+	group.Updated = nico.PtrTime(now)
+	out := *group
+	return &out, response(http.StatusOK), nil
+}
+
+// setKeyGroupMembers sets the sites and SSH keys of an SSH key group.
+func (c *Client) setKeyGroupMembers(group *nico.SshKeyGroup, siteIDs, keyIDs []string) (*http.Response, error) {
+	associations := make([]nico.SshKeyGroupSiteAssociation, 0, len(siteIDs))
+	for _, siteID := range siteIDs {
+		site := c.site(siteID, "")
+		associations = append(associations, nico.SshKeyGroupSiteAssociation{
+			Site:   &nico.SiteSummary{Id: site.Id, Name: site.Name},
+			Status: nico.SSHKEYGROUPSITEASSOCIATIONSTATUS_SYNCED.Ptr(),
+		})
+	}
+	keys := make([]nico.SshKey, 0, len(keyIDs))
+	for _, keyID := range keyIDs {
+		key, ok := c.sshKeys[keyID]
+		if !ok {
+			return apiError(http.StatusBadRequest, "SSH key %s not found", keyID)
+		}
+		keys = append(keys, *key)
+	}
+	group.SiteAssociations, group.SshKeys = associations, keys
+	return nil, nil
+}
+
+// DeleteSshKeyGroup deletes an SSH key group.
+func (c *Client) DeleteSshKeyGroup(ctx context.Context, _ string, sshKeyGroupId string) (*http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	if _, ok := c.keyGroups[sshKeyGroupId]; !ok {
+		return notFound("SSH key group", sshKeyGroupId)
+	}
+	delete(c.keyGroups, sshKeyGroupId)
+	return response(http.StatusNoContent), nil
+}
+
+// CreateSshKey registers a public SSH key, whose name must be unique.
+func (c *Client) CreateSshKey(
+	ctx context.Context, org string, req nico.SshKeyCreateRequest,
+) (*nico.SshKey, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	for _, key := range c.sshKeys {
+		if key.GetName() == req.Name {
+			httpResp, err := apiError(http.StatusConflict, "SSH key %s already exists", req.Name)
+			return nil, httpResp, err
+		}
+	}
+	id := uuid.New().String()
+	key := &nico.SshKey{
+		Id:      nico.PtrString(id),
+		Name:    nico.PtrString(req.Name),
+		Org:     nico.PtrString(org),
+		Created: nico.PtrTime(now),
+		Updated: nico.PtrTime(now),
+	}
+	c.sshKeys[id] = key
+	if groupID := req.SshKeyGroupId.Get(); groupID != nil {
+		group, ok := c.keyGroups[*groupID]
+		if !ok {
+			delete(c.sshKeys, id)
+			httpResp, err := apiError(http.StatusBadRequest, "SSH key group %s not found", *groupID)
+			return nil, httpResp, err
+		}
+		group.SshKeys = append(group.SshKeys, *key)
+	}
+	out := *key
+	return &out, response(http.StatusCreated), nil
+}
+
+// DeleteSshKey deletes an SSH key, removing it from its SSH key groups.
+func (c *Client) DeleteSshKey(ctx context.Context, _ string, sshKeyId string) (*http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	if _, ok := c.sshKeys[sshKeyId]; !ok {
+		return notFound("SSH key", sshKeyId)
+	}
+	delete(c.sshKeys, sshKeyId)
+	for _, group := range c.keyGroups {
+		group.SshKeys = slices.DeleteFunc(group.SshKeys, func(key nico.SshKey) bool { return key.GetId() == sshKeyId })
+	}
+	return response(http.StatusNoContent), nil
+}
+
+// GetSshKeyGroup returns an SSH key group. Any ID is accepted and describes a
+// group synced to every site.
+func (c *Client) GetSshKeyGroup(
+	ctx context.Context, org string, sshKeyGroupId string,
+) (*nico.SshKeyGroup, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	if group, ok := c.keyGroups[sshKeyGroupId]; ok {
+		out := *group
+		return &out, response(http.StatusOK), nil
+	}
+
+	c.ensureSites()
+	group := &nico.SshKeyGroup{
+		Id:     nico.PtrString(sshKeyGroupId),
+		Name:   nico.PtrString("sim-" + sshKeyGroupId[:min(8, len(sshKeyGroupId))]),
+		Org:    nico.PtrString(org),
+		Status: nico.SSHKEYGROUPSTATUS_SYNCED.Ptr(),
+	}
+	for _, s := range c.sites {
+		group.SiteAssociations = append(group.SiteAssociations, nico.SshKeyGroupSiteAssociation{
+			Site:   &nico.SiteSummary{Id: s.Id, Name: s.Name},
+			Status: nico.SSHKEYGROUPSITEASSOCIATIONSTATUS_SYNCED.Ptr(),
+		})
+	}
+	sort.Slice(group.SiteAssociations, func(i, j int) bool {
+		return group.SiteAssociations[i].Site.GetName() < group.SiteAssociations[j].Site.GetName()
+	})
+	return group, response(http.StatusOK), nil
+}
+
+func (c *Client) machineView(m *machineRecord, now time.Time) *nico.Machine {
+	machine := m.machine
+	machine.Status = nico.MachineStatus(m.timeline.status(now)).Ptr()
+	machine.StatusHistory = m.timeline.history(now)
+	return &machine
+}
+
+// GetMachine returns a machine.
+func (c *Client) GetMachine(ctx context.Context, _ string, machineId string) (*nico.Machine, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	m, ok := c.machines[machineId]
+	if !ok {
+		httpResp, err := notFound("machine", machineId)
+		return nil, httpResp, err
+	}
+	return c.machineView(m, now), response(http.StatusOK), nil
+}
+
+// GetAllAvailableMachine lists the Ready machines of an instance type.
+func (c *Client) GetAllAvailableMachine(
+	ctx context.Context, _ string, siteId string, instanceTypeId string,
+) ([]nico.Machine, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	var machines []nico.Machine
+	for _, id := range c.pool(siteId, instanceTypeId) {
+		if m := c.machines[id]; m.timeline.status(now) == "Ready" {
+			machines = append(machines, *c.machineView(m, now))
+		}
+	}
+	return machines, response(http.StatusOK), nil
+}
+
+// GetAllTray lists the trays, optionally filtered by type and hosted component.
+func (c *Client) GetAllTray(
+	ctx context.Context, _ string, _ string, trayType string, componentId string,
+) ([]nico.Tray, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	var trays []nico.Tray
+	for _, t := range c.trays {
+		if (trayType == "" || t.GetType() == trayType) && (componentId == "" || t.GetComponentId() == componentId) {
+			trays = append(trays, *t)
+		}
+	}
+	return trays, response(http.StatusOK), nil
+}
+
+// PowerControlTray changes the power state of a tray.
+func (c *Client) PowerControlTray(
+	ctx context.Context, _ string, trayId string, req nico.UpdatePowerStateRequest,
+) (*nico.UpdatePowerStateResponse, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	t, ok := c.trays[trayId]
+	if !ok {
+		httpResp, err := notFound("tray", trayId)
+		return nil, httpResp, err
+	}
+	switch req.State {
+	case "on", "On":
+		t.PowerState = nico.PtrString("On")
+	case "off", "Off":
+		t.PowerState = nico.PtrString("Off")
+	default:
+		httpResp, err := apiError(http.StatusBadRequest, "unsupported power state %q", req.State)
+		return nil, httpResp, err
+	}
+	return &nico.UpdatePowerStateResponse{TaskIds: []string{uuid.New().String()}}, response(http.StatusOK), nil
+}
+
+// ListFaultEvents returns no fault events, the simulated machines stay healthy.
+func (c *Client) ListFaultEvents(
+	ctx context.Context, _ string, _ string, _ string, _ string,
+) ([]nico.FaultEvent, *http.Response, error) {
+	if _, err := c.call(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	return []nico.FaultEvent{}, response(http.StatusOK), nil
+}
+
+// CreateInstance places an instance on a machine and starts its provisioning.
+func (c *Client) CreateInstance(
+	ctx context.Context, _ string, req nico.InstanceCreateRequest,
+) (*nico.Instance, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	rec, httpResp, err := c.createInstance(now, req)
+	if err != nil {
+		return nil, httpResp, err
+	}
+	return c.instanceView(rec, now), response(http.StatusCreated), nil
+}
+
+func (c *Client) createInstance(now time.Time, req nico.InstanceCreateRequest) (*instanceRecord, *http.Response, error) {
+	vpc, ok := c.vpcs[req.VpcId]
+	if !ok {
+		httpResp, err := apiError(http.StatusBadRequest, "VPC %s not found", req.VpcId)
+		return nil, httpResp, err
+	}
+	siteID := vpc.GetSiteId()
+
+	var machine *machineRecord
+	switch {
+	case req.MachineId != nil:
+		m, ok := c.machines[*req.MachineId]
+		if !ok {
+			httpResp, err := apiError(http.StatusBadRequest, "machine %s not found", *req.MachineId)
+			return nil, httpResp, err
+		}
+		if m.timeline.status(now) != "Ready" {
+			httpResp, err := apiError(http.StatusConflict, "machine %s is not available", *req.MachineId)
+			return nil, httpResp, err
+		}
+		machine = m
+	case req.InstanceTypeId != nil:
+		for _, id := range c.pool(siteID, *req.InstanceTypeId) {
+			if m := c.machines[id]; m.timeline.status(now) == "Ready" {
+				machine = m
+				break
+			}
+		}
+		if machine == nil {
+			httpResp, err := apiError(http.StatusConflict,
+				"no machine of instance type %s available on site %s", *req.InstanceTypeId, siteID)
+			return nil, httpResp, err
+		}
+	default:
+		httpResp, err := apiError(http.StatusBadRequest, "one of instanceTypeId or machineId is required")
+		return nil, httpResp, err
+	}
+
+	id := uuid.New().String()
+	interfaces, err := c.allocateInterfaces(id, req.Interfaces)
+	if err != nil {
+		httpResp, err := apiError(http.StatusBadRequest, "%v", err)
+		return nil, httpResp, err
+	}
+
+	machine.machine.InstanceId = *nico.NewNullableString(&id)
+	machine.timeline = machine.timeline.schedule(now,
+		transition{status: "InUse", message: "Assigned to instance " + id, at: now})
+
+	d := c.opts.InstanceProvisioningTime
+	rec := &instanceRecord{
+		instance: nico.Instance{
+			Id:                     nico.PtrString(id),
+			Name:                   nico.PtrString(req.Name),
+			TenantId:               nico.PtrString(req.TenantId),
+			SiteId:                 nico.PtrString(siteID),
+			InstanceTypeId:         machine.machine.InstanceTypeId.Get(),
+			VpcId:                  nico.PtrString(req.VpcId),
+			MachineId:              *nico.NewNullableString(machine.machine.Id),
+			UserData:               req.UserData,
+			IpxeScript:             req.IpxeScript,
+			Labels:                 req.Labels,
+			SshKeyGroupIds:         req.SshKeyGroupIds,
+			Interfaces:             interfaces,
+			NetworkSecurityGroupId: req.NetworkSecurityGroupId,
+			Created:                nico.PtrTime(now),
+			Updated:                nico.PtrTime(now),
+		},
+		timeline: timeline{
+			{status: "Pending", message: "Instance creation requested", at: now},
+			{status: "Provisioning", message: "Installing operating system", at: now.Add(d / 10)},
+			{status: "Configuring", message: "Configuring network interfaces", at: now.Add(d * 7 / 10)},
+			{status: "Ready", message: "Instance is ready", at: now.Add(d)},
+		},
+	}
+	c.instances[id] = rec
+	return rec, nil, nil
+}
+
+// allocateInterfaces assigns addresses to the requested interfaces.
+func (c *Client) allocateInterfaces(instanceID string, reqs []nico.InterfaceCreateRequest) ([]nico.Interface, error) {
+	interfaces := make([]nico.Interface, 0, len(reqs))
+	for i, req := range reqs {
+		iface := nico.Interface{
+			Id:                nico.PtrString(uuid.New().String()),
+			InstanceId:        nico.PtrString(instanceID),
+			SubnetId:          *nico.NewNullableString(req.SubnetId),
+			VpcPrefixId:       *nico.NewNullableString(req.VpcPrefixId),
+			IsPhysical:        req.IsPhysical,
+			MacAddress:        *nico.NewNullableString(nico.PtrString(macAddress(instanceID, i))),
+			Status:            nico.INTERFACESTATUS_READY.Ptr(),
+			Device:            *nico.NewNullableString(req.Device),
+			DeviceInstance:    *nico.NewNullableInt32(req.DeviceInstance),
+			VirtualFunctionId: req.VirtualFunctionId,
+		}
+		switch {
+		case req.IpAddress.Get() != nil:
+			iface.IpAddresses = []string{*req.IpAddress.Get()}
+			iface.RequestedIpAddress = req.IpAddress
+		case req.SubnetId != nil:
+			subnet, ok := c.subnets[*req.SubnetId]
+			if !ok {
+				return nil, fmt.Errorf("subnet %s not found", *req.SubnetId)
+			}
+			iface.IpAddresses = []string{addIP(*subnet.subnet.Ipv4Prefix.Get(), subnet.nextHost)}
+			subnet.nextHost++
+		case req.VpcPrefixId != nil:
+			prefix, ok := c.prefixes[*req.VpcPrefixId]
+			if !ok {
+				return nil, fmt.Errorf("VPC prefix %s not found", *req.VpcPrefixId)
+			}
+			if p := prefix.Prefix.Get(); p != nil {
+				iface.IpAddresses = []string{addIP(*p, uint32(2+i))}
+			}
+		}
+		interfaces = append(interfaces, iface)
+	}
+	return interfaces, nil
+}
+
+// macAddress derives a stable locally administered MAC address.
+func macAddress(instanceID string, index int) string {
+	sum := uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("%s/%d", instanceID, index)))
+	return net.HardwareAddr{0x02, sum[0], sum[1], sum[2], sum[3], sum[4]}.String()
+}
+
+func (c *Client) instanceView(rec *instanceRecord, now time.Time) *nico.Instance {
+	instance := rec.instance
+	instance.Status = nico.InstanceStatus(rec.timeline.status(now)).Ptr()
+	instance.StatusHistory = rec.timeline.history(now)
+	if instance.GetStatus() == nico.INSTANCESTATUS_PENDING {
+		// Addresses are only reported once the instance is placed on the network
+		instance.Interfaces = nil
+	}
+	return &instance
+}
+
+// GetInstance returns an instance.
+func (c *Client) GetInstance(ctx context.Context, _ string, instanceId string) (*nico.Instance, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	rec, ok := c.instances[instanceId]
+	if !ok {
+		httpResp, err := notFound("instance", instanceId)
+		return nil, httpResp, err
+	}
+	return c.instanceView(rec, now), response(http.StatusOK), nil
+}
+
+// GetAllInstance lists the instances.
+func (c *Client) GetAllInstance(ctx context.Context, _ string) ([]nico.Instance, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	instances := make([]nico.Instance, 0, len(c.instances))
+	for _, rec := range c.instances {
+		instances = append(instances, *c.instanceView(rec, now))
+	}
+	return instances, response(http.StatusOK), nil
+}
+
+// GetInstanceStatusHistory returns the status changes of an instance.
+func (c *Client) GetInstanceStatusHistory(
+	ctx context.Context, _ string, instanceId string,
+) ([]nico.StatusDetail, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	rec, ok := c.instances[instanceId]
+	if !ok {
+		httpResp, err := notFound("instance", instanceId)
+		return nil, httpResp, err
+	}
+	return rec.timeline.history(now), response(http.StatusOK), nil
+}
+
+// UpdateInstance updates an instance, rebooting it when requested.
+func (c *Client) UpdateInstance(
+	ctx context.Context, _ string, instanceId string, req nico.InstanceUpdateRequest,
+) (*nico.Instance, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	rec, ok := c.instances[instanceId]
+	if !ok || !rec.terminated.IsZero() {
+		httpResp, err := notFound("instance", instanceId)
+		return nil, httpResp, err
+	}
+
+	if name := req.Name.Get(); name != nil {
+		rec.instance.Name = name
+	}
+	if req.Labels != nil {
+		rec.instance.Labels = req.Labels
+	}
+	if req.SshKeyGroupIds != nil {
+		rec.instance.SshKeyGroupIds = req.SshKeyGroupIds
+	}
+	if req.UserData.IsSet() {
+		rec.instance.UserData = req.UserData
+	}
+	if req.IpxeScript.IsSet() {
+		rec.instance.IpxeScript = req.IpxeScript
+	}
+	rec.instance.Updated = nico.PtrTime(now)
+
+	if reboot := req.TriggerReboot.Get(); reboot != nil && *reboot {
+		if status := rec.timeline.status(now); status != "Ready" {
+			httpResp, err := apiError(http.StatusConflict, "instance %s cannot be rebooted in state %s", instanceId, status)
+			return nil, httpResp, err
+		}
+		message := "Reboot requested"
+		if custom := req.RebootWithCustomIpxe.Get(); custom != nil && *custom {
+			message = "Reboot with custom iPXE requested"
+		}
+		rec.timeline = rec.timeline.schedule(now,
+			transition{status: "Rebooting", message: message, at: now},
+			transition{status: "Ready", message: "Instance is ready", at: now.Add(c.opts.InstanceRebootTime)})
+	}
+	return c.instanceView(rec, now), response(http.StatusOK), nil
+}
+
+// BatchCreateInstance creates several instances of the same instance type.
+func (c *Client) BatchCreateInstance(
+	ctx context.Context, _ string, req nico.BatchInstanceCreateRequest,
+) ([]nico.Instance, *http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.mu.Unlock()
+
+	var created []*instanceRecord
+	for i := range int(req.Count) {
+		rec, httpResp, err := c.createInstance(now, nico.InstanceCreateRequest{
+			Name:                   fmt.Sprintf("%s-%d", req.NamePrefix, i),
+			TenantId:               req.TenantId,
+			InstanceTypeId:         nico.PtrString(req.InstanceTypeId),
+			VpcId:                  req.VpcId,
+			UserData:               req.UserData,
+			NetworkSecurityGroupId: req.NetworkSecurityGroupId,
+			IpxeScript:             req.IpxeScript,
+			Labels:                 req.Labels,
+			Interfaces:             req.Interfaces,
+			SshKeyGroupIds:         req.SshKeyGroupIds,
+		})
+		if err != nil {
+			// The batch is all or nothing
+			for _, rec := range created {
+				c.releaseInstance(rec)
+			}
+			return nil, httpResp, err
+		}
+		created = append(created, rec)
+	}
+
+	instances := make([]nico.Instance, 0, len(created))
+	for _, rec := range created {
+		instances = append(instances, *c.instanceView(rec, now))
+	}
+	return instances, response(http.StatusCreated), nil
+}
+
+// releaseInstance removes an instance that never started and frees its machine.
+func (c *Client) releaseInstance(rec *instanceRecord) {
+	delete(c.instances, rec.instance.GetId())
+	if machineID := rec.instance.MachineId.Get(); machineID != nil {
+		if m, ok := c.machines[*machineID]; ok {
+			m.machine.InstanceId.Unset()
+			m.timeline = timeline{{status: "Ready", message: "Machine available", at: time.Time{}}}
+		}
+	}
+}
+
+// DeleteInstance terminates an instance. It disappears after the termination
+// time and its machine goes through a reset, or to maintenance when a health
+// issue is reported.
+func (c *Client) DeleteInstance(
+	ctx context.Context, _ string, instanceId string, req *nico.InstanceDeleteRequest,
+) (*http.Response, error) {
+	now, err := c.call(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.mu.Unlock()
+
+	rec, ok := c.instances[instanceId]
+	if !ok {
+		return notFound("instance", instanceId)
+	}
+	if !rec.terminated.IsZero() {
+		return response(http.StatusAccepted), nil
+	}
+
+	rec.terminated = now.Add(c.opts.InstanceTerminationTime)
+	rec.timeline = rec.timeline.schedule(now,
+		transition{status: "Terminating", message: "Instance deletion requested", at: now})
+
+	if req != nil && req.MachineHealthIssue != nil {
+		rec.healthIssue = req.MachineHealthIssue.GetSummary()
+	}
+	return response(http.StatusAccepted), nil
+}
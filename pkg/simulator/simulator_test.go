@@ -0,0 +1,285 @@
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	nico "github.com/NVIDIA/ncx-infra-controller-rest/sdk/standard"
+
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/scope"
+)
+
+// newTestClient returns a simulator without API latency and with a controllable clock.
+func newTestClient() (*Client, *time.Time) {
+	opts := DefaultOptions()
+	opts.APILatency = 0
+	opts.MachinesPerInstanceType = 2
+	c := New(opts)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+// newSubnet creates a VPC and a subnet from an allocated IP block.
+func newSubnet(t *testing.T, c scope.NcxInfraClientInterface) (vpcID, subnetID string) {
+	t.Helper()
+	ctx := context.Background()
+
+	vpc, _, err := c.CreateVpc(ctx, "org", nico.VpcCreateRequest{Name: "vpc", SiteId: "site-1"})
+	if err != nil {
+		t.Fatalf("CreateVpc: %v", err)
+	}
+	block, _, err := c.CreateIpblock(ctx, "org", nico.IpBlockCreateRequest{
+		Name: "block", SiteId: "site-1", Prefix: "10.0.0.0", PrefixLength: 16,
+	})
+	if err != nil {
+		t.Fatalf("CreateIpblock: %v", err)
+	}
+	alloc, _, err := c.CreateAllocation(ctx, "org", nico.AllocationCreateRequest{
+		Name: "alloc", TenantId: "tenant", SiteId: "site-1",
+		AllocationConstraints: []nico.AllocationConstraintCreateRequest{{
+			ResourceType: nico.PtrString("IPBlock"), ResourceTypeId: block.GetId(),
+			ConstraintType: "OnDemand", ConstraintValue: 24,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("CreateAllocation: %v", err)
+	}
+	childID := alloc.AllocationConstraints[0].DerivedResourceId.Get()
+	if childID == nil {
+		t.Fatal("allocation did not derive a child IP block")
+	}
+	subnet, _, err := c.CreateSubnet(ctx, "org", nico.SubnetCreateRequest{
+		Name: "subnet", VpcId: vpc.GetId(), Ipv4BlockId: childID, PrefixLength: 26,
+	})
+	if err != nil {
+		t.Fatalf("CreateSubnet: %v", err)
+	}
+	if got := *subnet.Ipv4Prefix.Get(); got != "10.0.0.0" {
+		t.Errorf("expected subnet prefix 10.0.0.0, got %s", got)
+	}
+	return vpc.GetId(), subnet.GetId()
+}
+
+func TestVpcProvisioning(t *testing.T) {
+	c, now := newTestClient()
+	ctx := context.Background()
+
+	vpc, httpResp, err := c.CreateVpc(ctx, "org", nico.VpcCreateRequest{Name: "vpc", SiteId: "site-1"})
+	if err != nil || httpResp.StatusCode != http.StatusCreated {
+		t.Fatalf("CreateVpc: %v (%v)", err, httpResp)
+	}
+	if vpc.GetStatus() != nico.VPCSTATUS_PENDING {
+		t.Errorf("expected Pending, got %s", vpc.GetStatus())
+	}
+
+	*now = now.Add(c.opts.NetworkProvisioningTime)
+	vpc, _, _ = c.GetVpc(ctx, "org", vpc.GetId())
+	if vpc.GetStatus() != nico.VPCSTATUS_READY {
+		t.Errorf("expected Ready, got %s", vpc.GetStatus())
+	}
+	if len(vpc.StatusHistory) != 3 {
+		t.Errorf("expected 3 status history entries, got %d", len(vpc.StatusHistory))
+	}
+
+	vpc, _, err = c.UpdateVpc(ctx, "org", vpc.GetId(), nico.VpcUpdateRequest{Labels: map[string]string{"env": "prod"}})
+	if err != nil || vpc.Labels["env"] != "prod" {
+		t.Errorf("UpdateVpc: expected the labels to be replaced, got %v (%v)", vpc.Labels, err)
+	}
+}
+
+func TestInstanceLifecycle(t *testing.T) {
+	c, now := newTestClient()
+	ctx := context.Background()
+	vpcID, subnetID := newSubnet(t, c)
+
+	instance, httpResp, err := c.CreateInstance(ctx, "org", nico.InstanceCreateRequest{
+		Name: "worker-0", TenantId: "tenant", VpcId: vpcID, InstanceTypeId: nico.PtrString("gpu-large"),
+		Interfaces: []nico.InterfaceCreateRequest{{SubnetId: &subnetID}},
+	})
+	if err != nil || httpResp.StatusCode != http.StatusCreated {
+		t.Fatalf("CreateInstance: %v (%v)", err, httpResp)
+	}
+	if instance.GetStatus() != nico.INSTANCESTATUS_PENDING {
+		t.Errorf("expected Pending, got %s", instance.GetStatus())
+	}
+	machineID := *instance.MachineId.Get()
+
+	*now = now.Add(c.opts.InstanceProvisioningTime)
+	instance, _, _ = c.GetInstance(ctx, "org", instance.GetId())
+	if instance.GetStatus() != nico.INSTANCESTATUS_READY {
+		t.Errorf("expected Ready, got %s", instance.GetStatus())
+	}
+	if len(instance.Interfaces) != 1 || instance.Interfaces[0].IpAddresses[0] != "10.0.0.2" {
+		t.Errorf("unexpected interfaces: %+v", instance.Interfaces)
+	}
+
+	if _, err := c.DeleteInstance(ctx, "org", instance.GetId(), nil); err != nil {
+		t.Fatalf("DeleteInstance: %v", err)
+	}
+	instance, _, _ = c.GetInstance(ctx, "org", instance.GetId())
+	if instance.GetStatus() != nico.INSTANCESTATUS_TERMINATING {
+		t.Errorf("expected Terminating, got %s", instance.GetStatus())
+	}
+
+	*now = now.Add(c.opts.InstanceTerminationTime)
+	if _, httpResp, err := c.GetInstance(ctx, "org", instance.GetId()); err == nil ||
+		httpResp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected the instance to be gone, got %v", httpResp)
+	}
+	machine, _, _ := c.GetMachine(ctx, "org", machineID)
+	if machine.GetStatus() != nico.MACHINESTATUS_RESET {
+		t.Errorf("expected the machine to be reset, got %s", machine.GetStatus())
+	}
+
+	*now = now.Add(c.opts.MachineResetTime)
+	machine, _, _ = c.GetMachine(ctx, "org", machineID)
+	if machine.GetStatus() != nico.MACHINESTATUS_READY {
+		t.Errorf("expected the machine to be back in the pool, got %s", machine.GetStatus())
+	}
+}
+
+func TestInstanceCapacity(t *testing.T) {
+	c, _ := newTestClient()
+	ctx := context.Background()
+	vpcID, subnetID := newSubnet(t, c)
+
+	req := nico.InstanceCreateRequest{
+		TenantId: "tenant", VpcId: vpcID, InstanceTypeId: nico.PtrString("gpu-large"),
+		Interfaces: []nico.InterfaceCreateRequest{{SubnetId: &subnetID}},
+	}
+	for i := range c.opts.MachinesPerInstanceType {
+		req.Name = "worker-" + string(rune('a'+i))
+		if _, _, err := c.CreateInstance(ctx, "org", req); err != nil {
+			t.Fatalf("CreateInstance %d: %v", i, err)
+		}
+	}
+
+	req.Name = "one-too-many"
+	_, httpResp, err := c.CreateInstance(ctx, "org", req)
+	if err == nil || httpResp.StatusCode != http.StatusConflict {
+		t.Errorf("expected a conflict once the pool is exhausted, got %v", httpResp)
+	}
+}
+
+func TestGetAllInstanceType(t *testing.T) {
+	c, _ := newTestClient()
+	ctx := context.Background()
+	vpcID, subnetID := newSubnet(t, c)
+
+	_, _, err := c.CreateInstance(ctx, "org", nico.InstanceCreateRequest{
+		Name: "worker", TenantId: "tenant", VpcId: vpcID, InstanceTypeId: nico.PtrString("gpu-large"),
+		Interfaces: []nico.InterfaceCreateRequest{{SubnetId: &subnetID}},
+	})
+	if err != nil {
+		t.Fatalf("CreateInstance: %v", err)
+	}
+
+	instanceTypes, _, err := c.GetAllInstanceType(ctx, "org", "site-1")
+	if err != nil {
+		t.Fatalf("GetAllInstanceType: %v", err)
+	}
+	if len(instanceTypes) != 1 || instanceTypes[0].GetId() != "gpu-large" {
+		t.Fatalf("expected the gpu-large instance type, got %v", instanceTypes)
+	}
+	stats := instanceTypes[0].AllocationStats
+	if stats.GetTotal() != 2 || stats.GetUsed() != 1 || stats.GetUnusedUsable() != 1 {
+		t.Errorf("expected 1 of 2 machines used, got %+v", stats)
+	}
+
+	if others, _, _ := c.GetAllInstanceType(ctx, "org", "site-2"); len(others) != 0 {
+		t.Errorf("expected no instance type on another site, got %v", others)
+	}
+}
+
+func TestRebootInstance(t *testing.T) {
+	c, now := newTestClient()
+	ctx := context.Background()
+	vpcID, subnetID := newSubnet(t, c)
+
+	instance, _, err := c.CreateInstance(ctx, "org", nico.InstanceCreateRequest{
+		Name: "worker-0", TenantId: "tenant", VpcId: vpcID, InstanceTypeId: nico.PtrString("gpu-large"),
+		Interfaces: []nico.InterfaceCreateRequest{{SubnetId: &subnetID}},
+	})
+	if err != nil {
+		t.Fatalf("CreateInstance: %v", err)
+	}
+	reboot := nico.InstanceUpdateRequest{TriggerReboot: *nico.NewNullableBool(nico.PtrBool(true))}
+
+	if _, httpResp, err := c.UpdateInstance(ctx, "org", instance.GetId(), reboot); err == nil ||
+		httpResp.StatusCode != http.StatusConflict {
+		t.Errorf("expected a conflict while provisioning, got %v", httpResp)
+	}
+
+	*now = now.Add(c.opts.InstanceProvisioningTime)
+	instance, _, err = c.UpdateInstance(ctx, "org", instance.GetId(), reboot)
+	if err != nil {
+		t.Fatalf("UpdateInstance: %v", err)
+	}
+	if instance.GetStatus() != nico.INSTANCESTATUS_REBOOTING {
+		t.Errorf("expected Rebooting, got %s", instance.GetStatus())
+	}
+
+	*now = now.Add(c.opts.InstanceRebootTime)
+	instance, _, _ = c.GetInstance(ctx, "org", instance.GetId())
+	if instance.GetStatus() != nico.INSTANCESTATUS_READY {
+		t.Errorf("expected Ready after the reboot, got %s", instance.GetStatus())
+	}
+}
+
+func TestDeleteIpblockInUse(t *testing.T) {
+	c, _ := newTestClient()
+	ctx := context.Background()
+	_, subnetID := newSubnet(t, c)
+
+	ipBlocks, _, err := c.GetAllIpblock(ctx, "org", "site-1")
+	if err != nil || len(ipBlocks) != 2 {
+		t.Fatalf("expected the parent and child IP blocks, got %d: %v", len(ipBlocks), err)
+	}
+	subnet, _, _ := c.GetSubnet(ctx, "org", subnetID)
+	childID := subnet.GetIpv4BlockId()
+
+	if httpResp, err := c.DeleteIpblock(ctx, "org", childID); err == nil || httpResp.StatusCode != http.StatusConflict {
+		t.Errorf("expected a conflict deleting an IP block with a subnet, got %v", httpResp)
+	}
+	if _, err := c.DeleteSubnet(ctx, "org", subnetID); err != nil {
+		t.Fatalf("DeleteSubnet: %v", err)
+	}
+	for _, ipBlock := range ipBlocks {
+		if ipBlock.GetId() == childID {
+			continue
+		}
+		httpResp, err := c.DeleteIpblock(ctx, "org", ipBlock.GetId())
+		if err == nil || httpResp.StatusCode != http.StatusConflict {
+			t.Errorf("expected a conflict deleting an IP block with an allocation, got %v", httpResp)
+		}
+	}
+	if _, err := c.DeleteIpblock(ctx, "org", childID); err != nil {
+		t.Errorf("DeleteIpblock: %v", err)
+	}
+
+	_, httpResp, err := c.CreateIpblock(ctx, "org", nico.IpBlockCreateRequest{
+		Name: "block", SiteId: "site-1", Prefix: "10.1.0.0", PrefixLength: 16,
+	})
+	if err == nil || httpResp.StatusCode != http.StatusConflict {
+		t.Errorf("expected a conflict creating an IP block of an existing name, got %v", httpResp)
+	}
+}
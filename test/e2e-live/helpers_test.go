@@ -115,7 +115,7 @@ func waitForClusterReady(ctx context.Context, k8sClient client.Client, cluster *
 			_, _ = fmt.Fprintf(GinkgoWriter, "Error getting cluster: %v\n", err)
 			return false
 		}
-		_, _ = fmt.Fprintf(GinkgoWriter, "Cluster ready=%v, vpcID=%s\n", cluster.Status.Ready, cluster.Status.VPCID)
+		_, _ = fmt.Fprintf(GinkgoWriter, "Cluster ready=%v, vpcID=%s\n", cluster.Status.Ready, cluster.Status.NetworkStatus.VPCID())
 		return cluster.Status.Ready
 	}, clusterCreationTimeout, pollInterval).Should(BeTrue(), "NcxInfraCluster did not become ready")
 }
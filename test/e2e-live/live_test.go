@@ -171,12 +171,12 @@ var _ = Describe("Live NVIDIA Carbide Cluster E2E", Label("live"), func() {
 			waitForClusterReady(ctx, k8sClient, nvidiaCarbideCluster)
 
 			By("Verifying VPC was created")
-			Expect(nvidiaCarbideCluster.Status.VPCID).NotTo(BeEmpty())
+			Expect(nvidiaCarbideCluster.Status.NetworkStatus.VPCID()).NotTo(BeEmpty())
 
 			By("Verifying subnets were created")
-			Expect(nvidiaCarbideCluster.Status.NetworkStatus.SubnetIDs).To(HaveLen(2))
-			Expect(nvidiaCarbideCluster.Status.NetworkStatus.SubnetIDs).To(HaveKey("control-plane"))
-			Expect(nvidiaCarbideCluster.Status.NetworkStatus.SubnetIDs).To(HaveKey("worker"))
+			Expect(nvidiaCarbideCluster.Status.NetworkStatus.Subnets).To(HaveLen(2))
+			Expect(nvidiaCarbideCluster.Status.NetworkStatus.SubnetID("control-plane")).NotTo(BeEmpty())
+			Expect(nvidiaCarbideCluster.Status.NetworkStatus.SubnetID("worker")).NotTo(BeEmpty())
 
 			By("Creating a machine")
 			machineName := fmt.Sprintf("%s-machine-0", clusterName)
@@ -0,0 +1,47 @@
+//go:build e2e
+
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/test/framework"
+)
+
+// createCredentialsSecret creates the NVIDIA Carbide credentials secret the
+// cluster templates reference in the namespace of a spec. It is labeled to be
+// moved with the cluster, to a self-hosted management cluster.
+func createCredentialsSecret(managementClusterProxy framework.ClusterProxy, namespace string) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      e2eConfig.MustGetVariable("NCX_INFRA_CREDENTIALS_SECRET_NAME"),
+			Namespace: namespace,
+			Labels:    map[string]string{clusterctlv1.ClusterctlMoveLabel: ""},
+		},
+		StringData: map[string]string{
+			"endpoint": e2eConfig.MustGetVariable("NCX_INFRA_API_ENDPOINT"),
+			"orgName":  e2eConfig.MustGetVariable("NCX_INFRA_ORG_NAME"),
+			"token":    e2eConfig.MustGetVariable("NCX_INFRA_API_TOKEN"),
+		},
+	}
+	Expect(managementClusterProxy.GetClient().Create(ctx, secret)).To(Succeed(),
+		"Failed to create the credentials secret in namespace %s", namespace)
+}
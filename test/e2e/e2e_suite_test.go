@@ -1,3 +1,5 @@
+//go:build e2e
+
 /*
 Copyright 2026 Fabien Dupont.
 
@@ -14,78 +16,180 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-//go:build e2e
-
 package e2e
 
 import (
+	"flag"
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/cluster-api/test/framework/bootstrap"
+	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
+	ctrl "sigs.k8s.io/controller-runtime"
 
-	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/test/utils"
+	infrastructurev1beta1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
 )
 
+// Test suite flags.
 var (
-	// Optional Environment Variables:
-	// - CERT_MANAGER_INSTALL_SKIP=true: Skips CertManager installation during test setup.
-	// These variables are useful if CertManager is already installed, avoiding
-	// re-installation and conflicts.
-	skipCertManagerInstall = os.Getenv("CERT_MANAGER_INSTALL_SKIP") == "true"
-	// isCertManagerAlreadyInstalled will be set true when CertManager CRDs be found on the cluster
-	isCertManagerAlreadyInstalled = false
-
-	// projectImage is the name of the image which will be build and loaded
-	// with the code source changes to be tested.
-	projectImage = "example.com/cluster-api-provider-nvidia-ncx-infra-controller:v0.0.1"
+	// configPath is the path to the e2e config file.
+	configPath string
+
+	// useExistingCluster instructs the test to use the current cluster instead
+	// of creating a new one (default discovery rules apply).
+	useExistingCluster bool
+
+	// artifactFolder is the folder to store the e2e test artifacts.
+	artifactFolder string
+
+	// skipCleanup prevents the cleanup of the test resources, e.g. for debugging.
+	skipCleanup bool
 )
 
-// TestE2E runs the end-to-end (e2e) test suite for the project. These tests execute in an isolated,
-// temporary environment to validate project changes with the purpose of being used in CI jobs.
-// The default setup requires Kind, builds/loads the Manager Docker image locally, and installs
-// CertManager.
+// Test suite global vars.
+var (
+	ctx = ctrl.SetupSignalHandler()
+
+	// e2eConfig to be used for this test, read from configPath.
+	e2eConfig *clusterctl.E2EConfig
+
+	// clusterctlConfigPath to be used for this test, created by generating a
+	// clusterctl local repository with the providers specified in the
+	// configPath.
+	clusterctlConfigPath string
+
+	// bootstrapClusterProvider manages provisioning of the bootstrap cluster
+	// to be used for the e2e tests. Please note that provisioning will be
+	// skipped if e2e.use-existing-cluster is provided.
+	bootstrapClusterProvider bootstrap.ClusterProvider
+
+	// bootstrapClusterProxy allows to interact with the bootstrap cluster to
+	// be used for the e2e tests.
+	bootstrapClusterProxy framework.ClusterProxy
+)
+
+func init() {
+	flag.StringVar(&configPath, "e2e.config", "", "path to the e2e config file")
+	flag.StringVar(&artifactFolder, "e2e.artifacts-folder", "", "folder where e2e test artifact should be stored")
+	flag.BoolVar(&skipCleanup, "e2e.skip-resource-cleanup", false,
+		"if true, the resource cleanup after tests will be skipped")
+	flag.BoolVar(&useExistingCluster, "e2e.use-existing-cluster", false,
+		"if true, the test uses the current cluster instead of creating a new one (default discovery rules apply)")
+}
+
+// TestE2E runs the Cluster API e2e specs against the provider, driven by the
+// clusterctl e2e config of the e2e.config flag.
 func TestE2E(t *testing.T) {
+	ctrl.SetLogger(klog.Background())
 	RegisterFailHandler(Fail)
-	_, _ = fmt.Fprintf(GinkgoWriter, "Starting cluster-api-provider-nvidia-ncx-infra-controller integration test suite\n")
-	RunSpecs(t, "e2e suite")
+	RunSpecs(t, "capi-ncx-infra-e2e")
 }
 
-var _ = BeforeSuite(func() {
-	By("building the manager(Operator) image")
-	cmd := exec.Command("make", "docker-build", fmt.Sprintf("IMG=%s", projectImage))
-	_, err := utils.Run(cmd)
-	ExpectWithOffset(1, err).NotTo(HaveOccurred(), "Failed to build the manager(Operator) image")
-
-	// TODO(user): If you want to change the e2e test vendor from Kind, ensure the image is
-	// built and available before running the tests. Also, remove the following block.
-	By("loading the manager(Operator) image on Kind")
-	err = utils.LoadImageToKindClusterWithName(projectImage)
-	ExpectWithOffset(1, err).NotTo(HaveOccurred(), "Failed to load the manager(Operator) image into Kind")
-
-	// The tests-e2e are intended to run on a temporary cluster that is created and destroyed for testing.
-	// To prevent errors when tests run in environments with CertManager already installed,
-	// we check for its presence before execution.
-	// Setup CertManager before the suite if not skipped and if not already installed
-	if !skipCertManagerInstall {
-		By("checking if cert manager is installed already")
-		isCertManagerAlreadyInstalled = utils.IsCertManagerCRDsInstalled()
-		if !isCertManagerAlreadyInstalled {
-			_, _ = fmt.Fprintf(GinkgoWriter, "Installing CertManager...\n")
-			Expect(utils.InstallCertManager()).To(Succeed(), "Failed to install CertManager")
-		} else {
-			_, _ = fmt.Fprintf(GinkgoWriter, "WARNING: CertManager is already installed. Skipping installation...\n")
-		}
+// Before all ParallelNodes.
+var _ = SynchronizedBeforeSuite(func() []byte {
+	Expect(configPath).To(BeAnExistingFile(), "Invalid test suite argument. e2e.config should be an existing file.")
+	Expect(os.MkdirAll(artifactFolder, 0o755)).To(Succeed(),
+		"Invalid test suite argument. Can't create e2e.artifacts-folder %q", artifactFolder)
+
+	By("Initializing a runtime.Scheme with all the GVK relevant for this test")
+	scheme := initScheme()
+
+	By(fmt.Sprintf("Loading the e2e test configuration from %q", configPath))
+	e2eConfig = clusterctl.LoadE2EConfig(ctx, clusterctl.LoadE2EConfigInput{ConfigPath: configPath})
+	for _, name := range []string{"NCX_INFRA_API_ENDPOINT", "NCX_INFRA_ORG_NAME", "NCX_INFRA_API_TOKEN"} {
+		Expect(e2eConfig.GetVariableOrEmpty(name)).NotTo(BeEmpty(),
+			"The %s variable of the e2e config, or environment variable, must be set", name)
 	}
+
+	By(fmt.Sprintf("Creating a clusterctl local repository into %q", artifactFolder))
+	clusterctlConfigPath = clusterctl.CreateRepository(ctx, clusterctl.CreateRepositoryInput{
+		E2EConfig:        e2eConfig,
+		RepositoryFolder: filepath.Join(artifactFolder, "repository"),
+	})
+
+	By("Setting up the bootstrap cluster")
+	bootstrapClusterProvider, bootstrapClusterProxy = setupBootstrapCluster(e2eConfig, scheme, useExistingCluster)
+
+	By("Initializing the bootstrap cluster")
+	clusterctl.InitManagementClusterAndWatchControllerLogs(ctx, clusterctl.InitManagementClusterAndWatchControllerLogsInput{
+		ClusterProxy:            bootstrapClusterProxy,
+		ClusterctlConfigPath:    clusterctlConfigPath,
+		InfrastructureProviders: e2eConfig.InfrastructureProviders(),
+		LogFolder:               filepath.Join(artifactFolder, "clusters", bootstrapClusterProxy.GetName()),
+	}, e2eConfig.GetIntervals(bootstrapClusterProxy.GetName(), "wait-controllers")...)
+
+	return []byte(strings.Join([]string{
+		artifactFolder,
+		configPath,
+		clusterctlConfigPath,
+		bootstrapClusterProxy.GetKubeconfigPath(),
+	}, ","))
+}, func(data []byte) {
+	// Before each ParallelNode.
+	parts := strings.Split(string(data), ",")
+	Expect(parts).To(HaveLen(4))
+
+	artifactFolder = parts[0]
+	configPath = parts[1]
+	clusterctlConfigPath = parts[2]
+	kubeconfigPath := parts[3]
+
+	e2eConfig = clusterctl.LoadE2EConfig(ctx, clusterctl.LoadE2EConfigInput{ConfigPath: configPath})
+	bootstrapClusterProxy = framework.NewClusterProxy("bootstrap", kubeconfigPath, initScheme())
 })
 
-var _ = AfterSuite(func() {
-	// Teardown CertManager after the suite if not skipped and if it was not already installed
-	if !skipCertManagerInstall && !isCertManagerAlreadyInstalled {
-		_, _ = fmt.Fprintf(GinkgoWriter, "Uninstalling CertManager...\n")
-		utils.UninstallCertManager()
+// After all ParallelNodes.
+var _ = SynchronizedAfterSuite(func() {
+	// After each ParallelNode.
+}, func() {
+	By("Tearing down the management cluster")
+	if !skipCleanup {
+		if bootstrapClusterProxy != nil {
+			bootstrapClusterProxy.Dispose(ctx)
+		}
+		if bootstrapClusterProvider != nil {
+			bootstrapClusterProvider.Dispose(ctx)
+		}
 	}
 })
+
+// initScheme returns a scheme with the types of Cluster API and of the provider.
+func initScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	framework.TryAddDefaultSchemes(scheme)
+	Expect(infrastructurev1beta1.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+// setupBootstrapCluster creates the kind bootstrap cluster, loading the images
+// of the e2e config, unless an existing cluster is used.
+func setupBootstrapCluster(
+	config *clusterctl.E2EConfig, scheme *runtime.Scheme, useExistingCluster bool,
+) (bootstrap.ClusterProvider, framework.ClusterProxy) {
+	var clusterProvider bootstrap.ClusterProvider
+	kubeconfigPath := ""
+	if !useExistingCluster {
+		clusterProvider = bootstrap.CreateKindBootstrapClusterAndLoadImages(ctx,
+			bootstrap.CreateKindBootstrapClusterAndLoadImagesInput{
+				Name:      config.ManagementClusterName,
+				Images:    config.Images,
+				LogFolder: filepath.Join(artifactFolder, "kind"),
+			})
+		Expect(clusterProvider).NotTo(BeNil(), "Failed to create a bootstrap cluster")
+
+		kubeconfigPath = clusterProvider.GetKubeconfigPath()
+		Expect(kubeconfigPath).To(BeAnExistingFile(), "Failed to get the kubeconfig file for the bootstrap cluster")
+	}
+
+	clusterProxy := framework.NewClusterProxy("bootstrap", kubeconfigPath, scheme)
+	Expect(clusterProxy).NotTo(BeNil(), "Failed to get a bootstrap cluster proxy")
+	return clusterProvider, clusterProxy
+}
@@ -0,0 +1,77 @@
+//go:build e2e
+
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	capi_e2e "sigs.k8s.io/cluster-api/test/e2e"
+	"sigs.k8s.io/cluster-api/test/framework"
+)
+
+// The KubeadmControlPlane of a single control plane machine is scaled out to
+// three machines, then back in to one, which creates and deletes instances
+// behind the API server load balancer of the cluster.
+var _ = Describe("When scaling the KubeadmControlPlane", func() {
+	const specName = "kcp-scale"
+
+	scaleControlPlane := func(proxy framework.ClusterProxy, namespace, clusterName string, replicas int32) {
+		cluster := framework.GetClusterByName(ctx, framework.GetClusterByNameInput{
+			Getter:    proxy.GetClient(),
+			Name:      clusterName,
+			Namespace: namespace,
+		})
+		Expect(cluster).NotTo(BeNil())
+		controlPlane := framework.GetKubeadmControlPlaneByCluster(ctx, framework.GetKubeadmControlPlaneByClusterInput{
+			Lister:      proxy.GetClient(),
+			ClusterName: clusterName,
+			Namespace:   namespace,
+		})
+		Expect(controlPlane).NotTo(BeNil())
+
+		By(fmt.Sprintf("Scaling the control plane to %d replicas", replicas))
+		framework.ScaleAndWaitControlPlane(ctx, framework.ScaleAndWaitControlPlaneInput{
+			ClusterProxy:        proxy,
+			Cluster:             cluster,
+			ControlPlane:        controlPlane,
+			Replicas:            replicas,
+			WaitForControlPlane: e2eConfig.GetIntervals(specName, "wait-control-plane"),
+		})
+	}
+
+	capi_e2e.QuickStartSpec(ctx, func() capi_e2e.QuickStartSpecInput {
+		return capi_e2e.QuickStartSpecInput{
+			E2EConfig:                e2eConfig,
+			ClusterctlConfigPath:     clusterctlConfigPath,
+			BootstrapClusterProxy:    bootstrapClusterProxy,
+			ArtifactFolder:           artifactFolder,
+			SkipCleanup:              skipCleanup,
+			ControlPlaneMachineCount: ptr.To[int64](1),
+			WorkerMachineCount:       ptr.To[int64](0),
+			PostNamespaceCreated:     createCredentialsSecret,
+			PostMachinesProvisioned: func(proxy framework.ClusterProxy, namespace, clusterName string) {
+				scaleControlPlane(proxy, namespace, clusterName, 3)
+				scaleControlPlane(proxy, namespace, clusterName, 1)
+			},
+		}
+	})
+})
@@ -0,0 +1,100 @@
+//go:build e2e
+
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// providerNamespace is the namespace the provider is deployed to.
+	providerNamespace = "capi-ncx-infra-system"
+
+	// providerDeployment is the Deployment of the controller-manager of the provider.
+	providerDeployment = "capi-ncx-infra-controller-manager"
+
+	// leaderElectionID is the name of the lease of the leader election of the
+	// controller-manager.
+	leaderElectionID = "7eb3518c.cluster.x-k8s.io"
+)
+
+// The provider is deployed with the high availability profile of config/ha,
+// two replicas of which only the leader reconciles. Deleting the leader hands
+// the lease over to the other replica.
+var _ = Describe("When the leader of the provider stops", func() {
+	const specName = "leader-failover"
+
+	// leader returns the pod holding the lease, whose holder identity is the
+	// pod name followed by a UUID.
+	leader := func(g Gomega) string {
+		lease := &coordinationv1.Lease{}
+		g.Expect(bootstrapClusterProxy.GetClient().Get(ctx,
+			client.ObjectKey{Namespace: providerNamespace, Name: leaderElectionID}, lease)).To(Succeed())
+		g.Expect(lease.Spec.HolderIdentity).NotTo(BeNil())
+		name, _, _ := strings.Cut(*lease.Spec.HolderIdentity, "_")
+		g.Expect(name).To(HavePrefix(providerDeployment))
+		return name
+	}
+
+	It("should fail over to the other replica", func() {
+		c := bootstrapClusterProxy.GetClient()
+		intervals := e2eConfig.GetIntervals(specName, "wait-leader")
+
+		By("Waiting for both replicas of the controller-manager")
+		Eventually(func(g Gomega) {
+			deployment := &appsv1.Deployment{}
+			g.Expect(c.Get(ctx,
+				client.ObjectKey{Namespace: providerNamespace, Name: providerDeployment}, deployment)).To(Succeed())
+			g.Expect(deployment.Status.ReadyReplicas).To(BeNumerically(">=", 2))
+		}, intervals...).Should(Succeed())
+
+		By("Finding the leader from the lease")
+		var current string
+		Eventually(func(g Gomega) { current = leader(g) }, intervals...).Should(Succeed())
+
+		By("Deleting the leader")
+		pod := &corev1.Pod{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: providerNamespace, Name: current}, pod)).To(Succeed())
+		Expect(c.Delete(ctx, pod)).To(Succeed())
+
+		By("Verifying that the other replica took over the lease")
+		Eventually(func(g Gomega) {
+			next := leader(g)
+			g.Expect(next).NotTo(Equal(current))
+			running := &corev1.Pod{}
+			g.Expect(c.Get(ctx, client.ObjectKey{Namespace: providerNamespace, Name: next}, running)).To(Succeed())
+			g.Expect(running.Status.Phase).To(Equal(corev1.PodRunning))
+		}, intervals...).Should(Succeed())
+
+		By("Waiting for the Deployment to replace the deleted replica")
+		Eventually(func(g Gomega) {
+			deployment := &appsv1.Deployment{}
+			g.Expect(c.Get(ctx,
+				client.ObjectKey{Namespace: providerNamespace, Name: providerDeployment}, deployment)).To(Succeed())
+			g.Expect(deployment.Status.ReadyReplicas).To(BeNumerically(">=", 2))
+		}, intervals...).Should(Succeed())
+	})
+})
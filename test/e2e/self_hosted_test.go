@@ -0,0 +1,40 @@
+//go:build e2e
+
+/*
+Copyright 2026 Fabien Dupont.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	capi_e2e "sigs.k8s.io/cluster-api/test/e2e"
+)
+
+// The workload cluster pulls the image of the provider from MANAGER_IMAGE
+// once it hosts its own management components.
+var _ = Describe("When testing Cluster API working on self-hosted clusters", func() {
+	capi_e2e.SelfHostedSpec(ctx, func() capi_e2e.SelfHostedSpecInput {
+		return capi_e2e.SelfHostedSpecInput{
+			E2EConfig:             e2eConfig,
+			ClusterctlConfigPath:  clusterctlConfigPath,
+			BootstrapClusterProxy: bootstrapClusterProxy,
+			ArtifactFolder:        artifactFolder,
+			SkipCleanup:           skipCleanup,
+			SkipUpgrade:           true,
+			PostNamespaceCreated:  createCredentialsSecret,
+		}
+	})
+})
@@ -18,6 +18,7 @@ package integration
 
 import (
 	"context"
+	"net/http/httptest"
 	"path/filepath"
 	"testing"
 	"time"
@@ -38,6 +39,7 @@ import (
 
 	infrastructurev1beta1 "github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/api/v1beta1"
 	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/internal/controller"
+	"github.com/fabiendupont/cluster-api-provider-nvidia-ncx-infra-controller/pkg/simulator"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 )
 
@@ -47,6 +49,11 @@ var (
 	testEnv   *envtest.Environment
 	ctx       context.Context
 	cancel    context.CancelFunc
+
+	// carbide is the simulated NVIDIA Carbide API served by carbideServer,
+	// which the credentials secrets of the specs point at
+	carbide       *simulator.Client
+	carbideServer *httptest.Server
 )
 
 func TestIntegration(t *testing.T) {
@@ -83,6 +90,14 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
 
+	By("starting the simulated NVIDIA Carbide API")
+	opts := simulator.DefaultOptions()
+	opts.APILatency = 0
+	opts.NetworkProvisioningTime = time.Second
+	opts.InstanceProvisioningTime = 2 * time.Second
+	carbide = simulator.New(opts)
+	carbideServer = simulator.NewServer(carbide)
+
 	// Start controllers
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme.Scheme,
@@ -93,6 +108,8 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).ToNot(HaveOccurred())
 
+	Expect(controller.SetupIndexes(ctx, k8sManager)).To(Succeed())
+
 	err = (&controller.NcxInfraClusterReconciler{
 		Client: k8sManager.GetClient(),
 		Scheme: k8sManager.GetScheme(),
@@ -114,6 +131,10 @@ var _ = BeforeSuite(func() {
 
 var _ = AfterSuite(func() {
 	cancel()
+	// The server is not started when the test environment failed to start
+	if carbideServer != nil {
+		carbideServer.Close()
+	}
 	By("tearing down the test environment")
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
@@ -142,11 +163,7 @@ var _ = Describe("NcxInfraCluster Integration", func() {
 				Name:      "ncx-infra-creds",
 				Namespace: namespace.Name,
 			},
-			Data: map[string][]byte{
-				"endpoint": []byte("https://api.test.com"),
-				"orgName":  []byte("test-org"),
-				"token":    []byte("test-token"),
-			},
+			Data: simulator.CredentialsData(carbideServer, "test-org"),
 		}
 		Expect(k8sClient.Create(ctx, credSecret)).To(Succeed())
 
@@ -228,6 +245,31 @@ var _ = Describe("NcxInfraCluster Integration", func() {
 		}, 10*time.Second, 500*time.Millisecond).Should(ContainElement(controller.NcxInfraClusterFinalizer))
 	})
 
+	It("should provision the network on the NVIDIA Carbide API and release it on deletion", func() {
+		updated := &infrastructurev1beta1.NcxInfraCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(nvidiaCarbideCluster), updated)).To(Succeed())
+			g.Expect(updated.Status.Ready).To(BeTrue())
+		}, 30*time.Second, 500*time.Millisecond).Should(Succeed())
+
+		Expect(updated.Status.NetworkStatus.VPC).NotTo(BeNil())
+		vpcID := updated.Status.NetworkStatus.VPC.ID
+		vpc, _, err := carbide.GetVpc(ctx, "test-org", vpcID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vpc.GetName()).To(Equal("test-vpc"))
+		Expect(updated.Status.NetworkStatus.Subnets).To(HaveLen(2))
+		for _, subnet := range updated.Status.NetworkStatus.Subnets {
+			_, _, err := carbide.GetSubnet(ctx, "test-org", subnet.ID)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(k8sClient.Delete(ctx, updated)).To(Succeed())
+		Eventually(func() error {
+			_, _, err := carbide.GetVpc(ctx, "test-org", vpcID)
+			return err
+		}, 30*time.Second, 500*time.Millisecond).Should(HaveOccurred())
+	})
+
 	It("should handle missing owner cluster gracefully", func() {
 		orphanCluster := &infrastructurev1beta1.NcxInfraCluster{
 			ObjectMeta: metav1.ObjectMeta{
@@ -294,11 +336,7 @@ var _ = Describe("NcxInfraMachine Integration", func() {
 				Name:      "ncx-infra-creds",
 				Namespace: namespace.Name,
 			},
-			Data: map[string][]byte{
-				"endpoint": []byte("https://api.test.com"),
-				"orgName":  []byte("test-org"),
-				"token":    []byte("test-token"),
-			},
+			Data: simulator.CredentialsData(carbideServer, "test-org"),
 		}
 		Expect(k8sClient.Create(ctx, credSecret)).To(Succeed())
 
@@ -364,10 +402,10 @@ var _ = Describe("NcxInfraMachine Integration", func() {
 
 		// Update status separately (status is a subresource)
 		nvidiaCarbideCluster.Status.Ready = true
-		nvidiaCarbideCluster.Status.VPCID = "9bb2d7d0-a017-4018-a212-a3d6b38e4ec9"
 		nvidiaCarbideCluster.Status.NetworkStatus = infrastructurev1beta1.NetworkStatus{
-			SubnetIDs: map[string]string{
-				"control-plane": "63e3909a-dfae-4b8e-8090-3269c5d2a2da",
+			VPC: &infrastructurev1beta1.NetworkResourceStatus{ID: "9bb2d7d0-a017-4018-a212-a3d6b38e4ec9"},
+			Subnets: []infrastructurev1beta1.NetworkResourceStatus{
+				{Name: "control-plane", ID: "63e3909a-dfae-4b8e-8090-3269c5d2a2da"},
 			},
 		}
 		Expect(k8sClient.Status().Update(ctx, nvidiaCarbideCluster)).To(Succeed())